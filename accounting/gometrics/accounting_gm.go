@@ -19,6 +19,7 @@ import (
 	"github.com/couchbase/query/accounting/metrics"
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/ffdc"
+	"github.com/couchbase/query/inferencer"
 	"github.com/couchbase/query/logging"
 	"github.com/couchbase/query/memory"
 	"github.com/couchbase/query/server"
@@ -187,6 +188,7 @@ func (g *gometricsAccountingStore) Vitals(backward bool, style util.DurationStyl
 	ffdc.Stats("ffdc.", rv, false)
 	server.RequestsFileStreamStats(rv)
 	server.AwrCB.Vitals(rv)
+	inferencer.DriftStats("infer.drift.", rv)
 	return rv, nil
 }
 
@@ -17,6 +17,7 @@ import (
 type statementBase struct {
 	stmt       Statement
 	paramCount int
+	policies   bool
 }
 
 /*
@@ -86,3 +87,15 @@ Get optimier hints
 func (this *statementBase) OptimHints() *OptimHints {
 	return nil
 }
+
+/*
+Row-level security policies are added to a statement once, however many
+times it is planned.
+*/
+func (this *statementBase) PoliciesApplied() bool {
+	return this.policies
+}
+
+func (this *statementBase) SetPoliciesApplied() {
+	this.policies = true
+}
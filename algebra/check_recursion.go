@@ -107,6 +107,10 @@ func (this *checkRecursion) VisitAnsiJoin(node *AnsiJoin) (interface{}, error) {
 	return nil, this.visitJoin(node.Left(), node.Right())
 }
 
+func (this *checkRecursion) VisitAsofJoin(node *AsofJoin) (interface{}, error) {
+	return nil, this.visitJoin(node.Left(), node.Right())
+}
+
 func (this *checkRecursion) VisitNest(node *Nest) (interface{}, error) {
 	return nil, errors.NewRecursionUnsupportedError("NEST", node.String())
 }
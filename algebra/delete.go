@@ -332,6 +332,10 @@ func (this *Delete) Where() expression.Expression {
 	return this.where
 }
 
+func (this *Delete) SetWhere(where expression.Expression) {
+	this.where = where
+}
+
 func (this *Delete) Limit() expression.Expression {
 	return this.limit
 }
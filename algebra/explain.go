@@ -9,12 +9,32 @@
 package algebra
 
 import (
+	"strings"
+
 	"github.com/couchbase/query/auth"
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/expression"
 	"github.com/couchbase/query/value"
 )
 
+/*
+Output formats of EXPLAIN. JSON is the plan itself, TEXT an indented
+operator tree and DOT a Graphviz digraph.
+*/
+const (
+	EXPLAIN_FORMAT_JSON = "json"
+	EXPLAIN_FORMAT_TEXT = "text"
+	EXPLAIN_FORMAT_DOT  = "dot"
+)
+
+func IsExplainFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case EXPLAIN_FORMAT_JSON, EXPLAIN_FORMAT_TEXT, EXPLAIN_FORMAT_DOT:
+		return true
+	}
+	return false
+}
+
 /*
 Represents the explain text for a query. Type Explain is
 a struct that represents the explain json statement.
@@ -22,24 +42,61 @@ a struct that represents the explain json statement.
 type Explain struct {
 	statementBase
 
-	stmt Statement `json:"stmt"`
-	text string    `json:"text"`
+	stmt     Statement   `json:"stmt"`
+	text     string      `json:"text"`
+	analyze  bool        `json:"analyze"`
+	rollback bool        `json:"rollback"`
+	options  value.Value `json:"options"`
+	format   string      `json:"format"`
 }
 
 /*
 The function NewExplain returns a pointer to the Explain
 struct that has its field stmt set to the input Statement.
 */
-func NewExplain(stmt Statement, text string) *Explain {
+func NewExplain(stmt Statement, text string, format string) *Explain {
 	rv := &Explain{
-		stmt: stmt,
-		text: text,
+		stmt:   stmt,
+		text:   text,
+		format: strings.ToLower(format),
 	}
 
 	rv.statementBase.stmt = rv
 	return rv
 }
 
+/*
+The function NewExplainAnalyze returns an Explain that runs the
+input Statement and reports how it actually executed.
+*/
+func NewExplainAnalyze(stmt Statement, text string, format string) *Explain {
+	rv := NewExplain(stmt, text, format)
+	rv.analyze = true
+	rv.rollback = true
+	return rv
+}
+
+/*
+Sets the options of EXPLAIN ANALYZE. The only one is rollback, true by
+default, which undoes the changes of a data modification statement.
+*/
+func (this *Explain) SetAnalyzeOptions(options value.Value) errors.Error {
+	for name := range options.Fields() {
+		switch name {
+		case "rollback":
+			v, _ := options.Field(name)
+			if v.Type() != value.BOOLEAN {
+				return errors.NewWithInvalidValueError(name, "Boolean expected")
+			}
+			this.rollback = v.Truth()
+		default:
+			return errors.NewWithInvalidOptionError(name)
+		}
+	}
+	this.options = options
+	return nil
+}
+
 /*
 It calls the VisitExplain method by passing in the receiver to
 and returns the interface. It is a visitor pattern.
@@ -98,11 +155,42 @@ func (this *Explain) Text() string {
 	return this.text
 }
 
+/*
+Returns true for EXPLAIN ANALYZE.
+*/
+func (this *Explain) Analyze() bool {
+	return this.analyze
+}
+
+/*
+Returns true if EXPLAIN ANALYZE undoes the changes of the statement.
+*/
+func (this *Explain) Rollback() bool {
+	return this.rollback
+}
+
+/*
+Returns the output format, empty for the default.
+*/
+func (this *Explain) Format() string {
+	return this.format
+}
+
 func (this *Explain) Type() string {
 	return "EXPLAIN"
 }
 
 func (this *Explain) String() string {
-	s := "EXPLAIN " + this.stmt.String()
+	s := "EXPLAIN "
+	if this.format != "" {
+		s += "FORMAT " + strings.ToUpper(this.format) + " "
+	}
+	if this.analyze {
+		s += "ANALYZE "
+		if this.options != nil {
+			s += "WITH " + this.options.String() + " "
+		}
+	}
+	s += this.stmt.String()
 	return s
 }
@@ -109,7 +109,8 @@ func GetKeyspaceTerm(term SimpleFromTerm) *KeyspaceTerm {
 	case *KeyspaceTerm:
 		return term
 	case *ExpressionTerm:
-		if term.IsKeyspace() {
+		// the expression term of a MERGE source that has none is a nil pointer
+		if term != nil && term.IsKeyspace() {
 			return term.KeyspaceTerm()
 		}
 		return nil
@@ -0,0 +1,195 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+)
+
+/*
+Represents the ASOF JOIN clause. Each object of the left source is
+joined to at most one object of the right source: among those matching
+the equality terms of the ON-clause, the one nearest to it on the
+inequality term comparing their times.
+*/
+type AsofJoin struct {
+	left     FromTerm
+	right    SimpleFromTerm
+	outer    bool
+	onclause expression.Expression
+}
+
+func NewAsofJoin(left FromTerm, outer bool, right SimpleFromTerm, onclause expression.Expression) *AsofJoin {
+	return &AsofJoin{left, right, outer, onclause}
+}
+
+func (this *AsofJoin) Accept(visitor NodeVisitor) (interface{}, error) {
+	return visitor.VisitAsofJoin(this)
+}
+
+/*
+Maps left and right source objects of the JOIN.
+*/
+func (this *AsofJoin) MapExpressions(mapper expression.Mapper) (err error) {
+	err = this.left.MapExpressions(mapper)
+	if err != nil {
+		return
+	}
+
+	err = this.right.MapExpressions(mapper)
+	if err != nil {
+		return
+	}
+
+	this.onclause, err = mapper.Map(this.onclause)
+	return
+}
+
+/*
+Returns all contained Expressions.
+*/
+func (this *AsofJoin) Expressions() expression.Expressions {
+	exprs := this.left.Expressions()
+	exprs = append(exprs, this.right.Expressions()...)
+	return append(exprs, this.onclause)
+}
+
+/*
+Returns all required privileges.
+*/
+func (this *AsofJoin) Privileges() (*auth.Privileges, errors.Error) {
+	privs, err := this.left.Privileges()
+	if err != nil {
+		return nil, err
+	}
+
+	rprivs, err := this.right.Privileges()
+	if err != nil {
+		return nil, err
+	}
+
+	privs.AddAll(rprivs)
+	privs.AddAll(this.onclause.Privileges())
+	return privs, nil
+}
+
+/*
+Representation as a N1QL string.
+*/
+func (this *AsofJoin) String() string {
+	var buf strings.Builder
+	buf.WriteString(this.left.String())
+	if this.outer {
+		buf.WriteString(" asof left outer join ")
+	} else {
+		buf.WriteString(" asof join ")
+	}
+	buf.WriteString(this.right.String())
+	buf.WriteString(" on ")
+	buf.WriteString(this.onclause.String())
+	return buf.String()
+}
+
+/*
+Qualify all identifiers for the parent expression. Checks if
+a JOIN alias exists and if it is a duplicate alias.
+*/
+func (this *AsofJoin) Formalize(parent *expression.Formalizer) (f *expression.Formalizer, err error) {
+	f, err = this.left.Formalize(parent)
+	if err != nil {
+		return
+	}
+
+	f, err = this.right.Formalize(f)
+	if err != nil {
+		return
+	}
+
+	this.onclause, err = f.Map(this.onclause)
+	return
+}
+
+/*
+Returns the primary term in the left source of
+the JOIN.
+*/
+func (this *AsofJoin) PrimaryTerm() SimpleFromTerm {
+	return this.left.PrimaryTerm()
+}
+
+/*
+Returns the alias of the right source.
+*/
+func (this *AsofJoin) Alias() string {
+	return this.right.Alias()
+}
+
+/*
+Returns the left source object of the JOIN.
+*/
+func (this *AsofJoin) Left() FromTerm {
+	return this.left
+}
+
+/*
+Returns the right source object of the JOIN.
+*/
+func (this *AsofJoin) Right() SimpleFromTerm {
+	return this.right
+}
+
+/*
+Returns boolean value based on if it is
+an outer or inner JOIN.
+*/
+func (this *AsofJoin) Outer() bool {
+	return this.outer
+}
+
+/*
+Returns ON-clause of ASOF JOIN
+*/
+func (this *AsofJoin) Onclause() expression.Expression {
+	return this.onclause
+}
+
+/*
+Set ON-clause
+*/
+func (this *AsofJoin) SetOnclause(onclause expression.Expression) {
+	this.onclause = onclause
+}
+
+/*
+Returns whether contains correlation reference
+*/
+func (this *AsofJoin) IsCorrelated() bool {
+	return joinCorrelated(this.left, this.right)
+}
+
+func (this *AsofJoin) GetCorrelation() map[string]uint32 {
+	return getJoinCorrelation(this.left, this.right)
+}
+
+/*
+Marshals input JOIN terms.
+*/
+func (this *AsofJoin) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "AsofJoin"}
+	r["left"] = this.left
+	r["right"] = this.right
+	r["outer"] = this.outer
+	r["onclause"] = this.onclause
+	return json.Marshal(r)
+}
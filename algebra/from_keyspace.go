@@ -29,6 +29,7 @@ const (
 	TERM_INFER_JOIN_HINT             // join hint inferred (from other side of join)
 	TERM_XFER_JOIN_HINT              // join hint transferred (to other side of join)
 	TERM_LATERAL_JOIN                // lateral join
+	TERM_MASKED                      // documents masked by a masking policy
 )
 
 const TERM_JOIN_PROPS = (TERM_ANSI_JOIN | TERM_ANSI_NEST | TERM_PRIMARY_JOIN)
@@ -92,6 +93,21 @@ func NewKeyspaceTermFromExpression(expr expression.Expression, as string,
 	}
 }
 
+/*
+A copy of the term, whose properties can be changed without affecting
+the original.
+*/
+func (this *KeyspaceTerm) Copy() *KeyspaceTerm {
+	rv := *this
+	if this.correlation != nil {
+		rv.correlation = make(map[string]uint32, len(this.correlation))
+		for k, v := range this.correlation {
+			rv.correlation[k] = v
+		}
+	}
+	return &rv
+}
+
 func (this *KeyspaceTerm) Accept(visitor NodeVisitor) (interface{}, error) {
 	return visitor.VisitKeyspaceTerm(this)
 }
@@ -519,6 +535,18 @@ func (this *KeyspaceTerm) IsLateralJoin() bool {
 	return (this.property & TERM_LATERAL_JOIN) != 0
 }
 
+/*
+Returns whether documents are masked by a masking policy, and thus cannot
+be covered by an index
+*/
+func (this *KeyspaceTerm) IsMasked() bool {
+	return (this.property & TERM_MASKED) != 0
+}
+
+func (this *KeyspaceTerm) SetMasked() {
+	this.property |= TERM_MASKED
+}
+
 /*
 Set join keys
 */
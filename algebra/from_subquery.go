@@ -21,6 +21,9 @@ type SubqueryTerm struct {
 	property     uint32
 	correlation  map[string]uint32
 	errorContext expression.ErrorContext
+	view         *Path
+	viewPushed   bool
+	inlinedWith  bool
 }
 
 /*
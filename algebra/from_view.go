@@ -0,0 +1,146 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+/*
+Views are expanded by the planner: a keyspace term naming a view is
+replaced by a subquery term over the view definition, keeping the alias,
+join properties and join hint of the original term.
+*/
+func NewViewTerm(term *KeyspaceTerm, body *Select) *SubqueryTerm {
+	rv := NewSubqueryTerm(body, term.Alias(), term.JoinHint())
+	rv.property = term.property
+	rv.errorContext = term.errorContext
+	rv.view = term.Path()
+	return rv
+}
+
+/*
+Returns the view this term was expanded from, if any.
+*/
+func (this *SubqueryTerm) View() *Path {
+	return this.view
+}
+
+/*
+Predicates are pushed into a view definition once, however many times
+the term is planned.
+*/
+func (this *SubqueryTerm) ViewPushed() bool {
+	return this.viewPushed
+}
+
+func (this *SubqueryTerm) SetViewPushed() {
+	this.viewPushed = true
+}
+
+/*
+Replace the keyspace terms of a FROM clause for which expand returns a
+different term.
+*/
+func ReplaceViewTerms(term FromTerm, expand func(*KeyspaceTerm) (SimpleFromTerm, error)) (FromTerm, error) {
+	return ReplaceFromTerms(term, func(term SimpleFromTerm) (SimpleFromTerm, error) {
+		switch t := term.(type) {
+		case *KeyspaceTerm:
+			return expand(t)
+		case *ExpressionTerm:
+			if t.isKeyspace && t.keyspaceTerm != nil {
+				rv, err := expand(t.keyspaceTerm)
+				if err != nil || rv != t.keyspaceTerm {
+					return rv, err
+				}
+			}
+		}
+		return term, nil
+	})
+}
+
+/*
+Replace the terms of a FROM clause for which replace returns a different
+term. Only terms that may be derived tables are considered: the primary
+term and the right hand side of ANSI joins and nests.
+*/
+func ReplaceFromTerms(term FromTerm, replace func(SimpleFromTerm) (SimpleFromTerm, error)) (FromTerm, error) {
+	switch term := term.(type) {
+	case *AnsiJoin:
+		left, err := ReplaceFromTerms(term.left, replace)
+		if err != nil {
+			return nil, err
+		}
+		right, err := replace(term.right)
+		if err != nil {
+			return nil, err
+		}
+		term.left = left
+		term.right = right
+		return term, nil
+	case *AsofJoin:
+		left, err := ReplaceFromTerms(term.left, replace)
+		if err != nil {
+			return nil, err
+		}
+		right, err := replace(term.right)
+		if err != nil {
+			return nil, err
+		}
+		term.left = left
+		term.right = right
+		return term, nil
+	case *AnsiNest:
+		left, err := ReplaceFromTerms(term.left, replace)
+		if err != nil {
+			return nil, err
+		}
+		right, err := replace(term.right)
+		if err != nil {
+			return nil, err
+		}
+		term.left = left
+		term.right = right
+		return term, nil
+	case *Join:
+		left, err := ReplaceFromTerms(term.left, replace)
+		if err != nil {
+			return nil, err
+		}
+		term.left = left
+		return term, nil
+	case *IndexJoin:
+		left, err := ReplaceFromTerms(term.left, replace)
+		if err != nil {
+			return nil, err
+		}
+		term.left = left
+		return term, nil
+	case *Nest:
+		left, err := ReplaceFromTerms(term.left, replace)
+		if err != nil {
+			return nil, err
+		}
+		term.left = left
+		return term, nil
+	case *IndexNest:
+		left, err := ReplaceFromTerms(term.left, replace)
+		if err != nil {
+			return nil, err
+		}
+		term.left = left
+		return term, nil
+	case *Unnest:
+		left, err := ReplaceFromTerms(term.left, replace)
+		if err != nil {
+			return nil, err
+		}
+		term.left = left
+		return term, nil
+	case SimpleFromTerm:
+		return replace(term)
+	}
+	return term, nil
+}
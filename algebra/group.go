@@ -29,6 +29,7 @@ type Group struct {
 	letting        expression.Bindings    `json:"letting"`
 	having         expression.Expression  `json:"having"`
 	groupAs        string                 `json:"groupAs"`
+	gapfill        *GapFill               `json:"gapfill"`
 	asErrorContext expression.ErrorContext
 }
 
@@ -71,6 +72,13 @@ func (this *Group) Formalize(f *expression.Formalizer) error {
 		}
 	}
 
+	if this.gapfill != nil {
+		err = this.gapfill.Formalize(f)
+		if err != nil {
+			return err
+		}
+	}
+
 	if this.groupAs != "" {
 		if ok := f.AllowedAlias(this.groupAs, true, false); !ok {
 			f.SetAllowedGroupAsAlias(this.groupAs)
@@ -110,6 +118,21 @@ func (this *Group) MapExpressions(mapper expression.Mapper) (err error) {
 		}
 	}
 
+	if this.gapfill != nil {
+		err = this.gapfill.MapExpressions(mapper)
+		if err != nil {
+			return
+		}
+	}
+
+	return this.MapAggregateExpressions(mapper)
+}
+
+/*
+This method maps the clauses evaluated over the groups, namely the
+letting and having.
+*/
+func (this *Group) MapAggregateExpressions(mapper expression.Mapper) (err error) {
 	if this.letting != nil {
 		err = this.letting.MapExpressions(mapper)
 		if err != nil {
@@ -134,6 +157,10 @@ func (this *Group) Expressions() expression.Expressions {
 		exprs = append(exprs, this.by...)
 	}
 
+	if this.gapfill != nil {
+		exprs = append(exprs, this.gapfill.Expressions()...)
+	}
+
 	if this.letting != nil {
 		exprs = append(exprs, this.letting.Expressions()...)
 	}
@@ -160,6 +187,10 @@ func (this *Group) String() string {
 		}
 	}
 
+	if this.gapfill != nil {
+		buf.WriteString(this.gapfill.String())
+	}
+
 	if this.groupAs != "" {
 		buf.WriteString(" GROUP AS ")
 		buf.WriteString(this.groupAs)
@@ -203,10 +234,93 @@ func (this *Group) GroupAs() string {
 	return this.groupAs
 }
 
+/*
+Returns the GAPFILL modifier, or nil.
+*/
+func (this *Group) GapFill() *GapFill {
+	return this.gapfill
+}
+
+func (this *Group) SetGapFill(gapfill *GapFill) {
+	this.gapfill = gapfill
+}
+
 func (this *Group) SetAsErrorContext(line int, column int) {
 	this.asErrorContext.Set(line, column)
 }
 
+/*
+The GAPFILL modifier of GROUP BY: one of the group keys is a
+TIME_BUCKET() call, and a group is added for every bucket missing
+between the first and last buckets of each series, or over the range
+from start (inclusive) to end (exclusive) when given.
+*/
+type GapFill struct {
+	start expression.Expression `json:"start"`
+	end   expression.Expression `json:"end"`
+}
+
+func NewGapFill(start, end expression.Expression) *GapFill {
+	return &GapFill{
+		start: start,
+		end:   end,
+	}
+}
+
+func (this *GapFill) Formalize(f *expression.Formalizer) (err error) {
+	if this.start != nil {
+		this.start, err = f.Map(this.start)
+		if err != nil {
+			return
+		}
+	}
+
+	if this.end != nil {
+		this.end, err = f.Map(this.end)
+	}
+
+	return
+}
+
+func (this *GapFill) MapExpressions(mapper expression.Mapper) (err error) {
+	if this.start != nil {
+		this.start, err = mapper.Map(this.start)
+		if err != nil {
+			return
+		}
+	}
+
+	if this.end != nil {
+		this.end, err = mapper.Map(this.end)
+	}
+
+	return
+}
+
+func (this *GapFill) Expressions() expression.Expressions {
+	if this.start == nil {
+		return nil
+	}
+
+	return expression.Expressions{this.start, this.end}
+}
+
+func (this *GapFill) String() string {
+	if this.start == nil {
+		return " gapfill"
+	}
+
+	return " gapfill (" + this.start.String() + ", " + this.end.String() + ")"
+}
+
+func (this *GapFill) Start() expression.Expression {
+	return this.start
+}
+
+func (this *GapFill) End() expression.Expression {
+	return this.end
+}
+
 type GroupTerms []*GroupTerm
 
 func (this GroupTerms) Expressions() expression.Expressions {
@@ -0,0 +1,145 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+/*
+Represents the ALTER JOB statement, which changes any of the schedule,
+the options and the statement of a job. A new statement runs with the
+credentials of the user altering the job.
+*/
+type AlterJob struct {
+	statementBase
+
+	name         string      `json:"name"`
+	schedule     string      `json:"schedule"`
+	with         value.Value `json:"with"`
+	body         Statement   `json:"body"`
+	text         string      `json:"text"`
+	queryContext string      `json:"queryContext"`
+}
+
+func NewAlterJob(name string, schedule string, with value.Value, body Statement, text string,
+	queryContext string) *AlterJob {
+	rv := &AlterJob{
+		name:         name,
+		schedule:     schedule,
+		with:         with,
+		body:         body,
+		text:         text,
+		queryContext: queryContext,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *AlterJob) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitAlterJob(this)
+}
+
+func (this *AlterJob) Signature() value.Value {
+	return nil
+}
+
+func (this *AlterJob) Formalize() error {
+	if this.body == nil {
+		return nil
+	}
+	return this.body.Formalize()
+}
+
+func (this *AlterJob) MapExpressions(mapper expression.Mapper) error {
+	return nil
+}
+
+func (this *AlterJob) Expressions() expression.Expressions {
+	return nil
+}
+
+func (this *AlterJob) Privileges() (*auth.Privileges, errors.Error) {
+	return jobPrivileges(this.body)
+}
+
+func (this *AlterJob) Name() string {
+	return this.name
+}
+
+// The new schedule, or empty if unchanged
+func (this *AlterJob) Schedule() string {
+	return this.schedule
+}
+
+func (this *AlterJob) With() value.Value {
+	return this.with
+}
+
+// The new statement, or nil if unchanged
+func (this *AlterJob) Body() Statement {
+	return this.body
+}
+
+func (this *AlterJob) Text() string {
+	return this.text
+}
+
+func (this *AlterJob) QueryContext() string {
+	return this.queryContext
+}
+
+func (this *AlterJob) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "alterJob"}
+	r["name"] = this.name
+	if this.schedule != "" {
+		r["schedule"] = this.schedule
+	}
+	if this.with != nil {
+		r["with"] = this.with
+	}
+	if this.text != "" {
+		r["text"] = this.text
+		if this.queryContext != "" {
+			r["query_context"] = this.queryContext
+		}
+	}
+	return json.Marshal(r)
+}
+
+func (this *AlterJob) Type() string {
+	return "ALTER_JOB"
+}
+
+func (this *AlterJob) String() string {
+	var s strings.Builder
+	s.WriteString("ALTER JOB `")
+	s.WriteString(this.name)
+	s.WriteString("`")
+	if this.schedule != "" {
+		s.WriteString(" SCHEDULE ")
+		s.WriteString(value.NewValue(this.schedule).String())
+	}
+	if this.with != nil {
+		s.WriteString(" WITH ")
+		s.WriteString(this.with.String())
+	}
+	if this.text != "" {
+		s.WriteString(" AS ")
+		s.WriteString(this.text)
+	}
+	return s.String()
+}
@@ -0,0 +1,167 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+/*
+Represents the CREATE [OR REPLACE] JOB statement:
+
+	CREATE JOB name SCHEDULE 'cron expression' [WITH options] AS statement
+
+The statement is kept both as parsed, for validation, and as text, which
+is what is stored and run, together with the query context it was
+created under.
+*/
+type CreateJob struct {
+	statementBase
+
+	name         string      `json:"name"`
+	schedule     string      `json:"schedule"`
+	with         value.Value `json:"with"`
+	body         Statement   `json:"body"`
+	text         string      `json:"text"`
+	queryContext string      `json:"queryContext"`
+	replace      bool        `json:"replace"`
+}
+
+func NewCreateJob(name string, schedule string, with value.Value, body Statement, text string,
+	queryContext string, replace bool) *CreateJob {
+	rv := &CreateJob{
+		name:         name,
+		schedule:     schedule,
+		with:         with,
+		body:         body,
+		text:         text,
+		queryContext: queryContext,
+		replace:      replace,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *CreateJob) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateJob(this)
+}
+
+func (this *CreateJob) Signature() value.Value {
+	return nil
+}
+
+func (this *CreateJob) Formalize() error {
+	if this.body == nil {
+		return nil
+	}
+	return this.body.Formalize()
+}
+
+func (this *CreateJob) MapExpressions(mapper expression.Mapper) error {
+	return nil
+}
+
+func (this *CreateJob) Expressions() expression.Expressions {
+	return nil
+}
+
+/*
+Creating a job needs the right to manage global functions, as well as
+those needed to run its statement, which runs with the credentials of
+the creator of the job.
+*/
+func (this *CreateJob) Privileges() (*auth.Privileges, errors.Error) {
+	return jobPrivileges(this.body)
+}
+
+func jobPrivileges(body Statement) (*auth.Privileges, errors.Error) {
+	privs := auth.NewPrivileges()
+	privs.Add("", auth.PRIV_QUERY_MANAGE_FUNCTIONS, auth.PRIV_PROPS_NONE)
+	if body != nil {
+		bodyPrivs, err := body.Privileges()
+		if err != nil {
+			return nil, err
+		}
+		privs.AddAll(bodyPrivs)
+	}
+	return privs, nil
+}
+
+func (this *CreateJob) Name() string {
+	return this.name
+}
+
+func (this *CreateJob) Schedule() string {
+	return this.schedule
+}
+
+func (this *CreateJob) With() value.Value {
+	return this.with
+}
+
+func (this *CreateJob) Body() Statement {
+	return this.body
+}
+
+func (this *CreateJob) Text() string {
+	return this.text
+}
+
+func (this *CreateJob) QueryContext() string {
+	return this.queryContext
+}
+
+func (this *CreateJob) Replace() bool {
+	return this.replace
+}
+
+func (this *CreateJob) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "createJob"}
+	r["name"] = this.name
+	r["schedule"] = this.schedule
+	if this.with != nil {
+		r["with"] = this.with
+	}
+	r["text"] = this.text
+	if this.queryContext != "" {
+		r["query_context"] = this.queryContext
+	}
+	r["replace"] = this.replace
+	return json.Marshal(r)
+}
+
+func (this *CreateJob) Type() string {
+	return "CREATE_JOB"
+}
+
+func (this *CreateJob) String() string {
+	var s strings.Builder
+	s.WriteString("CREATE ")
+	if this.replace {
+		s.WriteString("OR REPLACE ")
+	}
+	s.WriteString("JOB `")
+	s.WriteString(this.name)
+	s.WriteString("` SCHEDULE ")
+	s.WriteString(value.NewValue(this.schedule).String())
+	if this.with != nil {
+		s.WriteString(" WITH ")
+		s.WriteString(this.with.String())
+	}
+	s.WriteString(" AS ")
+	s.WriteString(this.text)
+	return s.String()
+}
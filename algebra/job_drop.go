@@ -0,0 +1,91 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+type DropJob struct {
+	statementBase
+
+	name            string `json:"name"`
+	failIfNotExists bool   `json:"failIfNotExists"`
+}
+
+func NewDropJob(name string, failIfNotExists bool) *DropJob {
+	rv := &DropJob{
+		name:            name,
+		failIfNotExists: failIfNotExists,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *DropJob) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropJob(this)
+}
+
+func (this *DropJob) Signature() value.Value {
+	return nil
+}
+
+func (this *DropJob) Formalize() error {
+	return nil
+}
+
+func (this *DropJob) MapExpressions(mapper expression.Mapper) error {
+	return nil
+}
+
+func (this *DropJob) Expressions() expression.Expressions {
+	return nil
+}
+
+func (this *DropJob) Privileges() (*auth.Privileges, errors.Error) {
+	return jobPrivileges(nil)
+}
+
+func (this *DropJob) Name() string {
+	return this.name
+}
+
+func (this *DropJob) FailIfNotExists() bool {
+	return this.failIfNotExists
+}
+
+func (this *DropJob) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "dropJob"}
+	r["name"] = this.name
+	r["failIfNotExists"] = this.failIfNotExists
+	return json.Marshal(r)
+}
+
+func (this *DropJob) Type() string {
+	return "DROP_JOB"
+}
+
+func (this *DropJob) String() string {
+	var s strings.Builder
+	s.WriteString("DROP JOB ")
+	if !this.failIfNotExists {
+		s.WriteString("IF EXISTS ")
+	}
+	s.WriteString("`")
+	s.WriteString(this.name)
+	s.WriteString("`")
+	return s.String()
+}
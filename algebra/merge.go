@@ -313,6 +313,10 @@ func (this *Merge) On() expression.Expression {
 	return this.on
 }
 
+func (this *Merge) SetOn(on expression.Expression) {
+	this.on = on
+}
+
 func (this *Merge) IsOnKey() bool {
 	return this.isOnKey
 }
@@ -972,6 +976,10 @@ func (this *MergeUpdate) Where() expression.Expression {
 	return this.where
 }
 
+func (this *MergeUpdate) SetWhere(where expression.Expression) {
+	this.where = where
+}
+
 /*
 Represents the merge delete merge actions statement.
 Type MergeDelete is a struct that contains the where
@@ -1033,6 +1041,10 @@ func (this *MergeDelete) Where() expression.Expression {
 	return this.where
 }
 
+func (this *MergeDelete) SetWhere(where expression.Expression) {
+	this.where = where
+}
+
 /*
 Represents the merge insert merge actions statement.
 Type MergeInsert is a struct that contains the value
@@ -0,0 +1,231 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+/*
+Represents the CREATE [OR REPLACE] POLICY statement:
+
+	CREATE POLICY name ON keyspace FOR {SELECT|UPDATE|DELETE}
+	TO {PUBLIC|role[, ...]} USING (predicate)
+
+The predicate refers to the documents of the keyspace as index keys do,
+by their fields alone, and may use CURRENT_USER() and CURRENT_USER_INFO().
+A nil list of roles grants the policy to PUBLIC.
+
+It also represents the CREATE [OR REPLACE] MASKING POLICY statement:
+
+	CREATE MASKING POLICY name ON keyspace (column) USING (mask)
+	[EXEMPT ROLE role[, ...]]
+
+where the event is MASK, the mask is held as the predicate, and the roles
+are those exempt from the policy.
+*/
+type CreatePolicy struct {
+	statementBase
+
+	name      string                `json:"name"`
+	keyspace  *KeyspaceRef          `json:"keyspace"`
+	event     string                `json:"event"`
+	column    expression.Expression `json:"column"`
+	roles     []string              `json:"roles"`
+	predicate expression.Expression `json:"predicate"`
+	replace   bool                  `json:"replace"`
+}
+
+func NewCreatePolicy(name string, keyspace *KeyspaceRef, event string, roles []string,
+	predicate expression.Expression, replace bool) *CreatePolicy {
+	rv := &CreatePolicy{
+		name:      name,
+		keyspace:  keyspace,
+		event:     event,
+		roles:     roles,
+		predicate: predicate,
+		replace:   replace,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func NewCreateMaskingPolicy(name string, keyspace *KeyspaceRef, column expression.Expression,
+	mask expression.Expression, exempt []string, replace bool) *CreatePolicy {
+	rv := &CreatePolicy{
+		name:      name,
+		keyspace:  keyspace,
+		event:     "mask",
+		column:    column,
+		roles:     exempt,
+		predicate: mask,
+		replace:   replace,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *CreatePolicy) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreatePolicy(this)
+}
+
+func (this *CreatePolicy) Signature() value.Value {
+	return nil
+}
+
+func (this *CreatePolicy) Formalize() error {
+	f := expression.NewKeyspaceFormalizer(this.keyspace.Keyspace(), nil)
+	return this.MapExpressions(f)
+}
+
+func (this *CreatePolicy) MapExpressions(mapper expression.Mapper) (err error) {
+	if this.column != nil {
+		this.column, err = mapper.Map(this.column)
+		if err != nil {
+			return
+		}
+	}
+	this.predicate, err = mapper.Map(this.predicate)
+	return
+}
+
+func (this *CreatePolicy) Expressions() expression.Expressions {
+	if this.column != nil {
+		return expression.Expressions{this.column, this.predicate}
+	}
+	return expression.Expressions{this.predicate}
+}
+
+/*
+Creating a policy needs scope administration rights on the keyspace, as
+creating a trigger does.
+*/
+func (this *CreatePolicy) Privileges() (*auth.Privileges, errors.Error) {
+	privs := auth.NewPrivileges()
+	path := this.keyspace.Path()
+	if scope := path.ScopePath(); scope != nil {
+		privs.Add(scope.FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	} else {
+		privs.Add(path.FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	}
+	return privs, nil
+}
+
+func (this *CreatePolicy) Name() string {
+	return this.name
+}
+
+func (this *CreatePolicy) Keyspace() *KeyspaceRef {
+	return this.keyspace
+}
+
+func (this *CreatePolicy) Event() string {
+	return this.event
+}
+
+// The masked field, for masking policies
+func (this *CreatePolicy) Column() expression.Expression {
+	return this.column
+}
+
+func (this *CreatePolicy) Masking() bool {
+	return this.column != nil
+}
+
+func (this *CreatePolicy) Roles() []string {
+	return this.roles
+}
+
+func (this *CreatePolicy) Predicate() expression.Expression {
+	return this.predicate
+}
+
+func (this *CreatePolicy) Replace() bool {
+	return this.replace
+}
+
+func (this *CreatePolicy) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "createPolicy"}
+	r["name"] = this.name
+	r["keyspaceRef"] = this.keyspace
+	r["event"] = this.event
+	if this.column != nil {
+		r["column"] = this.column.String()
+	}
+	if len(this.roles) > 0 {
+		r["roles"] = this.roles
+	}
+	r["predicate"] = this.predicate.String()
+	r["replace"] = this.replace
+	return json.Marshal(r)
+}
+
+func (this *CreatePolicy) Type() string {
+	return "CREATE_POLICY"
+}
+
+func (this *CreatePolicy) String() string {
+	var s strings.Builder
+	s.WriteString("CREATE ")
+	if this.replace {
+		s.WriteString("OR REPLACE ")
+	}
+	if this.column != nil {
+		s.WriteString("MASKING POLICY `")
+		s.WriteString(this.name)
+		s.WriteString("` ON ")
+		s.WriteString(this.keyspace.Path().ProtectedString())
+		s.WriteString(" (")
+		s.WriteString(this.column.String())
+		s.WriteString(") USING (")
+		s.WriteString(this.predicate.String())
+		s.WriteString(")")
+		for i, r := range this.roles {
+			if i == 0 {
+				s.WriteString(" EXEMPT ROLE ")
+			} else {
+				s.WriteString(", ")
+			}
+			s.WriteString("`")
+			s.WriteString(r)
+			s.WriteString("`")
+		}
+		return s.String()
+	}
+	s.WriteString("POLICY `")
+	s.WriteString(this.name)
+	s.WriteString("` ON ")
+	s.WriteString(this.keyspace.Path().ProtectedString())
+	s.WriteString(" FOR ")
+	s.WriteString(strings.ToUpper(this.event))
+	s.WriteString(" TO ")
+	if len(this.roles) == 0 {
+		s.WriteString("PUBLIC")
+	}
+	for i, r := range this.roles {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString("`")
+		s.WriteString(r)
+		s.WriteString("`")
+	}
+	s.WriteString(" USING (")
+	s.WriteString(this.predicate.String())
+	s.WriteString(")")
+	return s.String()
+}
@@ -0,0 +1,120 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+type DropPolicy struct {
+	statementBase
+
+	name            string       `json:"name"`
+	keyspace        *KeyspaceRef `json:"keyspace"`
+	masking         bool         `json:"masking"`
+	failIfNotExists bool         `json:"failIfNotExists"`
+}
+
+func NewDropPolicy(name string, keyspace *KeyspaceRef, masking bool, failIfNotExists bool) *DropPolicy {
+	rv := &DropPolicy{
+		name:            name,
+		keyspace:        keyspace,
+		masking:         masking,
+		failIfNotExists: failIfNotExists,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *DropPolicy) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropPolicy(this)
+}
+
+func (this *DropPolicy) Signature() value.Value {
+	return nil
+}
+
+func (this *DropPolicy) Formalize() error {
+	return nil
+}
+
+func (this *DropPolicy) MapExpressions(mapper expression.Mapper) error {
+	return nil
+}
+
+func (this *DropPolicy) Expressions() expression.Expressions {
+	return nil
+}
+
+func (this *DropPolicy) Privileges() (*auth.Privileges, errors.Error) {
+	privs := auth.NewPrivileges()
+	path := this.keyspace.Path()
+	if scope := path.ScopePath(); scope != nil {
+		privs.Add(scope.FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	} else {
+		privs.Add(path.FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	}
+	return privs, nil
+}
+
+func (this *DropPolicy) Name() string {
+	return this.name
+}
+
+func (this *DropPolicy) Keyspace() *KeyspaceRef {
+	return this.keyspace
+}
+
+// DROP MASKING POLICY
+func (this *DropPolicy) Masking() bool {
+	return this.masking
+}
+
+func (this *DropPolicy) FailIfNotExists() bool {
+	return this.failIfNotExists
+}
+
+func (this *DropPolicy) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "dropPolicy"}
+	r["name"] = this.name
+	r["keyspaceRef"] = this.keyspace
+	if this.masking {
+		r["masking"] = this.masking
+	}
+	r["failIfNotExists"] = this.failIfNotExists
+	return json.Marshal(r)
+}
+
+func (this *DropPolicy) Type() string {
+	return "DROP_POLICY"
+}
+
+func (this *DropPolicy) String() string {
+	var s strings.Builder
+	s.WriteString("DROP ")
+	if this.masking {
+		s.WriteString("MASKING ")
+	}
+	s.WriteString("POLICY ")
+	if !this.failIfNotExists {
+		s.WriteString("IF EXISTS ")
+	}
+	s.WriteString("`")
+	s.WriteString(this.name)
+	s.WriteString("` ON ")
+	s.WriteString(this.keyspace.Path().ProtectedString())
+	return s.String()
+}
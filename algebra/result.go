@@ -30,8 +30,21 @@ type Projection struct {
 	raw      bool                   `json:"raw"`
 	terms    ResultTerms            `json:"terms"`
 	exclude  expression.Expressions `json:"exclude"`
+	masks    ProjectionMasks        `json:"masks"`
 }
 
+/*
+A masking policy on the documents of a keyspace alias, applied as they
+are projected. Mask evaluates to the value the field at Path takes.
+*/
+type ProjectionMask struct {
+	Alias string
+	Path  []string
+	Mask  expression.Expression
+}
+
+type ProjectionMasks []*ProjectionMask
+
 /*
 The function NewProjection returns a pointer to the Projection
 struct by assigning the input attributes to the fields of the
@@ -264,6 +277,14 @@ func (this *Projection) Exclude() expression.Expressions {
 	return this.exclude
 }
 
+func (this *Projection) Masks() ProjectionMasks {
+	return this.masks
+}
+
+func (this *Projection) SetMasks(masks ProjectionMasks) {
+	this.masks = masks
+}
+
 /*
 Set the result term alias by calling setAlias for
 each term.
@@ -279,6 +300,12 @@ func (this *Projection) setAliases() {
 Check whether early projection can be done on a keyspace alias
 */
 func (this *Projection) CheckEarlyProjection(alias string) bool {
+	// masks may depend on any field
+	for _, mask := range this.masks {
+		if mask.Alias == alias {
+			return false
+		}
+	}
 	ident := expression.NewIdentifier(alias)
 	for _, term := range this.terms {
 		if term.star {
@@ -396,6 +396,10 @@ func (this *Select) With() *WithClause {
 	return this.with
 }
 
+func (this *Select) SetWith(with *WithClause) {
+	this.with = with
+}
+
 func (this *Select) OptimHints() *OptimHints {
 	return this.subresult.OptimHints()
 }
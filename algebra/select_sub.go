@@ -343,6 +343,10 @@ func (this *Subselect) From() FromTerm {
 	return this.from
 }
 
+func (this *Subselect) SetFrom(from FromTerm) {
+	this.from = from
+}
+
 /*
 Returns the let field that represents the Let
 clause in the subselect statement.
@@ -359,6 +363,10 @@ func (this *Subselect) Where() expression.Expression {
 	return this.where
 }
 
+func (this *Subselect) SetWhere(where expression.Expression) {
+	this.where = where
+}
+
 /*
 Returns the group field that represents the group by
 clause in the subselect statement.
@@ -367,6 +375,10 @@ func (this *Subselect) Group() *Group {
 	return this.group
 }
 
+func (this *Subselect) SetGroup(group *Group) {
+	this.group = group
+}
+
 /*
 Returns the projection (select clause) in the subselect
 statement.
@@ -375,6 +387,10 @@ func (this *Subselect) Projection() *Projection {
 	return this.projection
 }
 
+func (this *Subselect) SetProjection(projection *Projection) {
+	this.projection = projection
+}
+
 /*
 Returns the Window in the subselect
 statement.
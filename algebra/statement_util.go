@@ -18,7 +18,7 @@ func CanSkipAutoPrepare(stmt Statement) bool {
 		*CreateIndex, *DropIndex, *BuildIndexes, *AlterIndex, *CreatePrimaryIndex,
 		*CreateBucket, *DropBucket, *AlterBucket,
 		*CreateScope, *DropScope,
-		*CreateCollection, *DropCollection, *FlushCollection, *AlterCollection,
+		*CreateCollection, *DropCollection, *FlushCollection, *AlterCollection, *Truncate,
 		*CreateCatalog, *DropCatalog, *AlterCatalog,
 		*CreateCredentialStore, *DropCredentialStore, *AlterCredentialStore,
 		*CreateUser, *DropUser, *AlterUser,
@@ -26,7 +26,11 @@ func CanSkipAutoPrepare(stmt Statement) bool {
 		*GrantRole, *RevokeRole,
 		*CreateFunction, *DropFunction, *ExecuteFunction,
 		*StartTransaction, *CommitTransaction, *RollbackTransaction, *Savepoint, *TransactionIsolation,
-		*CreateSequence, *DropSequence, *AlterSequence:
+		*CreateSequence, *DropSequence, *AlterSequence,
+		*CreateView, *DropView, *RefreshView,
+		*CreateTrigger, *DropTrigger,
+		*CreateJob, *AlterJob, *DropJob,
+		*CreatePolicy, *DropPolicy:
 		return true
 	case *Insert:
 		if stmt.query == nil {
@@ -0,0 +1,194 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/functions"
+	"github.com/couchbase/query/value"
+)
+
+// The rows a trigger fires on, as seen by the arguments of its function
+const (
+	TRIGGER_OLD = "OLD"
+	TRIGGER_NEW = "NEW"
+)
+
+/*
+Represents the CREATE [OR REPLACE] TRIGGER statement:
+
+	CREATE TRIGGER name {BEFORE|AFTER} {INSERT|UPDATE|DELETE} ON keyspace
+	FOR EACH ROW EXECUTE FUNCTION udf(args)
+
+The arguments may refer to the document before and after the mutation as
+OLD and NEW. The definition is stored as text, with the keyspace and
+function fully qualified, and parsed again by the send operators of the
+keyspace.
+*/
+type CreateTrigger struct {
+	statementBase
+
+	name         string                 `json:"name"`
+	keyspace     *KeyspaceRef           `json:"keyspace"`
+	timing       string                 `json:"timing"`
+	event        string                 `json:"event"`
+	function     functions.FunctionName `json:"function"`
+	args         expression.Expressions `json:"args"`
+	queryContext string                 `json:"queryContext"`
+	replace      bool                   `json:"replace"`
+}
+
+func NewCreateTrigger(name string, keyspace *KeyspaceRef, timing string, event string, function functions.FunctionName,
+	args expression.Expressions, queryContext string, replace bool) *CreateTrigger {
+	rv := &CreateTrigger{
+		name:         name,
+		keyspace:     keyspace,
+		timing:       timing,
+		event:        event,
+		function:     function,
+		args:         args,
+		queryContext: queryContext,
+		replace:      replace,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *CreateTrigger) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateTrigger(this)
+}
+
+func (this *CreateTrigger) Signature() value.Value {
+	return nil
+}
+
+func (this *CreateTrigger) Formalize() error {
+	f := expression.NewFormalizer("", nil)
+	f.SetAllowedAlias(TRIGGER_OLD, true)
+	f.SetAllowedAlias(TRIGGER_NEW, true)
+	return this.MapExpressions(f)
+}
+
+func (this *CreateTrigger) MapExpressions(mapper expression.Mapper) error {
+	if len(this.args) > 0 {
+		return this.args.MapExpressions(mapper)
+	}
+	return nil
+}
+
+func (this *CreateTrigger) Expressions() expression.Expressions {
+	return this.args
+}
+
+/*
+Creating a trigger needs scope administration rights on the keyspace.
+The function runs with the credentials of the statements that fire it.
+*/
+func (this *CreateTrigger) Privileges() (*auth.Privileges, errors.Error) {
+	privs := auth.NewPrivileges()
+	path := this.keyspace.Path()
+	if scope := path.ScopePath(); scope != nil {
+		privs.Add(scope.FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	} else {
+		privs.Add(path.FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	}
+	return privs, nil
+}
+
+func (this *CreateTrigger) Name() string {
+	return this.name
+}
+
+func (this *CreateTrigger) Keyspace() *KeyspaceRef {
+	return this.keyspace
+}
+
+func (this *CreateTrigger) Timing() string {
+	return this.timing
+}
+
+func (this *CreateTrigger) Event() string {
+	return this.event
+}
+
+func (this *CreateTrigger) Function() functions.FunctionName {
+	return this.function
+}
+
+func (this *CreateTrigger) Args() expression.Expressions {
+	return this.args
+}
+
+// The statement as stored, which always creates the trigger
+func (this *CreateTrigger) Definition() string {
+	return this.format(false)
+}
+
+func (this *CreateTrigger) QueryContext() string {
+	return this.queryContext
+}
+
+func (this *CreateTrigger) Replace() bool {
+	return this.replace
+}
+
+func (this *CreateTrigger) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "createTrigger"}
+	r["name"] = this.name
+	r["keyspaceRef"] = this.keyspace
+	r["timing"] = this.timing
+	r["event"] = this.event
+	r["text"] = this.Definition()
+	if this.queryContext != "" {
+		r["query_context"] = this.queryContext
+	}
+	r["replace"] = this.replace
+	return json.Marshal(r)
+}
+
+func (this *CreateTrigger) Type() string {
+	return "CREATE_TRIGGER"
+}
+
+func (this *CreateTrigger) String() string {
+	return this.format(this.replace)
+}
+
+func (this *CreateTrigger) format(replace bool) string {
+	var s strings.Builder
+	s.WriteString("CREATE ")
+	if replace {
+		s.WriteString("OR REPLACE ")
+	}
+	s.WriteString("TRIGGER `")
+	s.WriteString(this.name)
+	s.WriteString("` ")
+	s.WriteString(strings.ToUpper(this.timing))
+	s.WriteString(" ")
+	s.WriteString(strings.ToUpper(this.event))
+	s.WriteString(" ON ")
+	s.WriteString(this.keyspace.Path().ProtectedString())
+	s.WriteString(" FOR EACH ROW EXECUTE FUNCTION ")
+	s.WriteString(this.function.ProtectedKey())
+	s.WriteString("(")
+	for i, arg := range this.args {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(arg.String())
+	}
+	s.WriteString(")")
+	return s.String()
+}
@@ -0,0 +1,106 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+type DropTrigger struct {
+	statementBase
+
+	name            string       `json:"name"`
+	keyspace        *KeyspaceRef `json:"keyspace"`
+	failIfNotExists bool         `json:"failIfNotExists"`
+}
+
+func NewDropTrigger(name string, keyspace *KeyspaceRef, failIfNotExists bool) *DropTrigger {
+	rv := &DropTrigger{
+		name:            name,
+		keyspace:        keyspace,
+		failIfNotExists: failIfNotExists,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *DropTrigger) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropTrigger(this)
+}
+
+func (this *DropTrigger) Signature() value.Value {
+	return nil
+}
+
+func (this *DropTrigger) Formalize() error {
+	return nil
+}
+
+func (this *DropTrigger) MapExpressions(mapper expression.Mapper) error {
+	return nil
+}
+
+func (this *DropTrigger) Expressions() expression.Expressions {
+	return nil
+}
+
+func (this *DropTrigger) Privileges() (*auth.Privileges, errors.Error) {
+	privs := auth.NewPrivileges()
+	path := this.keyspace.Path()
+	if scope := path.ScopePath(); scope != nil {
+		privs.Add(scope.FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	} else {
+		privs.Add(path.FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	}
+	return privs, nil
+}
+
+func (this *DropTrigger) Name() string {
+	return this.name
+}
+
+func (this *DropTrigger) Keyspace() *KeyspaceRef {
+	return this.keyspace
+}
+
+func (this *DropTrigger) FailIfNotExists() bool {
+	return this.failIfNotExists
+}
+
+func (this *DropTrigger) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "dropTrigger"}
+	r["name"] = this.name
+	r["keyspaceRef"] = this.keyspace
+	r["failIfNotExists"] = this.failIfNotExists
+	return json.Marshal(r)
+}
+
+func (this *DropTrigger) Type() string {
+	return "DROP_TRIGGER"
+}
+
+func (this *DropTrigger) String() string {
+	var s strings.Builder
+	s.WriteString("DROP TRIGGER ")
+	if !this.failIfNotExists {
+		s.WriteString("IF EXISTS ")
+	}
+	s.WriteString("`")
+	s.WriteString(this.name)
+	s.WriteString("` ON ")
+	s.WriteString(this.keyspace.Path().ProtectedString())
+	return s.String()
+}
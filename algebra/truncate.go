@@ -0,0 +1,86 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+/*
+Represents the TRUNCATE [TABLE] statement, which removes all documents
+from a keyspace. Unlike FLUSH COLLECTION, it keeps the indexes up to
+date, needs no bucket flush setting, and can run in a transaction.
+*/
+type Truncate struct {
+	statementBase
+
+	keyspace *KeyspaceRef `json:"keyspace"`
+}
+
+func NewTruncate(keyspace *KeyspaceRef) *Truncate {
+	rv := &Truncate{
+		keyspace: keyspace,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *Truncate) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitTruncate(this)
+}
+
+func (this *Truncate) Signature() value.Value {
+	return nil
+}
+
+func (this *Truncate) Formalize() error {
+	return nil
+}
+
+func (this *Truncate) MapExpressions(mapper expression.Mapper) error {
+	return nil
+}
+
+func (this *Truncate) Expressions() expression.Expressions {
+	return nil
+}
+
+func (this *Truncate) Privileges() (*auth.Privileges, errors.Error) {
+	privs := auth.NewPrivileges()
+	privs.Add(this.keyspace.Path().FullName(), auth.PRIV_QUERY_TRUNCATE, auth.PRIV_PROPS_NONE)
+	return privs, nil
+}
+
+func (this *Truncate) Keyspace() *KeyspaceRef {
+	return this.keyspace
+}
+
+func (this *Truncate) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "truncate"}
+	r["keyspaceRef"] = this.keyspace
+	return json.Marshal(r)
+}
+
+func (this *Truncate) Type() string {
+	return "TRUNCATE"
+}
+
+func (this *Truncate) String() string {
+	var s strings.Builder
+	s.WriteString("TRUNCATE ")
+	s.WriteString(this.keyspace.Path().ProtectedString())
+	return s.String()
+}
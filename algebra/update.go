@@ -466,6 +466,10 @@ func (this *Update) Where() expression.Expression {
 	return this.where
 }
 
+func (this *Update) SetWhere(where expression.Expression) {
+	this.where = where
+}
+
 func (this *Update) Limit() expression.Expression {
 	return this.limit
 }
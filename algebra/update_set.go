@@ -281,6 +281,10 @@ func (this *SetTerm) Value() expression.Expression {
 	return this.value
 }
 
+func (this *SetTerm) SetValue(value expression.Expression) {
+	this.value = value
+}
+
 /*
 Returns the update-for clause in the SET clause.
 */
@@ -0,0 +1,160 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+/*
+Represents the CREATE [OR REPLACE] VIEW and CREATE MATERIALIZED VIEW
+statements. The definition is kept both as parsed, for validation, and as
+text, which is what is stored and parsed again whenever the view is
+referenced or refreshed.
+*/
+type CreateView struct {
+	statementBase
+
+	name         *Path       `json:"name"`
+	body         *Select     `json:"body"`
+	text         string      `json:"text"`
+	queryContext string      `json:"queryContext"`
+	replace      bool        `json:"replace"`
+	materialized bool        `json:"materialized"`
+	with         value.Value `json:"with"`
+}
+
+func NewCreateView(name *Path, body *Select, text string, queryContext string, replace bool,
+	materialized bool, with value.Value) *CreateView {
+	rv := &CreateView{
+		name:         name,
+		body:         body,
+		text:         text,
+		queryContext: queryContext,
+		replace:      replace,
+		materialized: materialized,
+		with:         with,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *CreateView) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateView(this)
+}
+
+func (this *CreateView) Signature() value.Value {
+	return nil
+}
+
+func (this *CreateView) Formalize() error {
+	return this.body.Formalize()
+}
+
+func (this *CreateView) MapExpressions(mapper expression.Mapper) error {
+	return nil
+}
+
+func (this *CreateView) Expressions() expression.Expressions {
+	return nil
+}
+
+/*
+Creating a view needs scope administration rights. Access to the
+underlying keyspaces is checked when the view is queried, or, for
+materialized views, when it is refreshed.
+*/
+func (this *CreateView) Privileges() (*auth.Privileges, errors.Error) {
+	privs := auth.NewPrivileges()
+	privs.Add(this.name.ScopePath().FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	return privs, nil
+}
+
+func (this *CreateView) Name() *Path {
+	return this.name
+}
+
+func (this *CreateView) Body() *Select {
+	return this.body
+}
+
+func (this *CreateView) Text() string {
+	return this.text
+}
+
+func (this *CreateView) QueryContext() string {
+	return this.queryContext
+}
+
+func (this *CreateView) Replace() bool {
+	return this.replace
+}
+
+func (this *CreateView) Materialized() bool {
+	return this.materialized
+}
+
+func (this *CreateView) With() value.Value {
+	return this.with
+}
+
+func (this *CreateView) MarshalName(m map[string]interface{}) {
+	m["namespace"] = this.name.Namespace()
+	m["bucket"] = this.name.Bucket()
+	m["scope"] = this.name.Scope()
+	m["keyspace"] = this.name.Keyspace()
+}
+
+func (this *CreateView) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "createView"}
+	this.MarshalName(r)
+	r["text"] = this.text
+	if this.queryContext != "" {
+		r["query_context"] = this.queryContext
+	}
+	r["replace"] = this.replace
+	if this.materialized {
+		r["materialized"] = true
+	}
+	if this.with != nil {
+		r["with"] = this.with
+	}
+	return json.Marshal(r)
+}
+
+func (this *CreateView) Type() string {
+	return "CREATE_VIEW"
+}
+
+func (this *CreateView) String() string {
+	var s strings.Builder
+	s.WriteString("CREATE ")
+	if this.replace {
+		s.WriteString("OR REPLACE ")
+	}
+	if this.materialized {
+		s.WriteString("MATERIALIZED ")
+	}
+	s.WriteString("VIEW ")
+	s.WriteString(this.name.ProtectedString())
+	if this.with != nil {
+		s.WriteString(" WITH ")
+		s.WriteString(this.with.String())
+	}
+	s.WriteString(" AS ")
+	s.WriteString(this.text)
+	return s.String()
+}
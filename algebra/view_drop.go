@@ -0,0 +1,111 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+type DropView struct {
+	statementBase
+
+	name            *Path `json:"name"`
+	failIfNotExists bool  `json:"failIfNotExists"`
+	materialized    bool  `json:"materialized"`
+}
+
+func NewDropView(name *Path, failIfNotExists bool, materialized bool) *DropView {
+	rv := &DropView{
+		name:            name,
+		failIfNotExists: failIfNotExists,
+		materialized:    materialized,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *DropView) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropView(this)
+}
+
+func (this *DropView) Signature() value.Value {
+	return nil
+}
+
+func (this *DropView) Formalize() error {
+	return nil
+}
+
+func (this *DropView) MapExpressions(mapper expression.Mapper) error {
+	return nil
+}
+
+func (this *DropView) Expressions() expression.Expressions {
+	return nil
+}
+
+func (this *DropView) Privileges() (*auth.Privileges, errors.Error) {
+	privs := auth.NewPrivileges()
+	privs.Add(this.name.ScopePath().FullName(), auth.PRIV_QUERY_SCOPE_ADMIN, auth.PRIV_PROPS_NONE)
+	return privs, nil
+}
+
+func (this *DropView) Name() *Path {
+	return this.name
+}
+
+func (this *DropView) FailIfNotExists() bool {
+	return this.failIfNotExists
+}
+
+func (this *DropView) Materialized() bool {
+	return this.materialized
+}
+
+func (this *DropView) MarshalName(m map[string]interface{}) {
+	m["namespace"] = this.name.Namespace()
+	m["bucket"] = this.name.Bucket()
+	m["scope"] = this.name.Scope()
+	m["keyspace"] = this.name.Keyspace()
+}
+
+func (this *DropView) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "dropView"}
+	this.MarshalName(r)
+	r["failIfNotExists"] = this.failIfNotExists
+	if this.materialized {
+		r["materialized"] = true
+	}
+	return json.Marshal(r)
+}
+
+func (this *DropView) Type() string {
+	return "DROP_VIEW"
+}
+
+func (this *DropView) String() string {
+	var s strings.Builder
+	s.WriteString("DROP ")
+	if this.materialized {
+		s.WriteString("MATERIALIZED ")
+	}
+	s.WriteString("VIEW ")
+	if !this.failIfNotExists {
+		s.WriteString("IF EXISTS ")
+	}
+	s.WriteString(this.name.ProtectedString())
+	return s.String()
+}
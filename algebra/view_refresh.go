@@ -0,0 +1,109 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package algebra
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+/*
+Represents the REFRESH MATERIALIZED VIEW statement. The refresh runs as
+a scheduled task; incremental refreshes fall back to full ones when the
+mutations of the source are not known.
+*/
+type RefreshView struct {
+	statementBase
+
+	name        *Path `json:"name"`
+	incremental bool  `json:"incremental"`
+}
+
+func NewRefreshView(name *Path, incremental bool) *RefreshView {
+	rv := &RefreshView{
+		name:        name,
+		incremental: incremental,
+	}
+
+	rv.stmt = rv
+	return rv
+}
+
+func (this *RefreshView) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitRefreshView(this)
+}
+
+func (this *RefreshView) Signature() value.Value {
+	return nil
+}
+
+func (this *RefreshView) Formalize() error {
+	return nil
+}
+
+func (this *RefreshView) MapExpressions(mapper expression.Mapper) error {
+	return nil
+}
+
+func (this *RefreshView) Expressions() expression.Expressions {
+	return nil
+}
+
+/*
+Refreshing replaces the contents of the view's collection. Full
+refreshes also read the source with the privileges of the requester.
+*/
+func (this *RefreshView) Privileges() (*auth.Privileges, errors.Error) {
+	privs := auth.NewPrivileges()
+	fullKeyspace := this.name.FullName()
+	privs.Add(fullKeyspace, auth.PRIV_QUERY_INSERT, auth.PRIV_PROPS_NONE)
+	privs.Add(fullKeyspace, auth.PRIV_QUERY_DELETE, auth.PRIV_PROPS_NONE)
+	return privs, nil
+}
+
+func (this *RefreshView) Name() *Path {
+	return this.name
+}
+
+func (this *RefreshView) Incremental() bool {
+	return this.incremental
+}
+
+func (this *RefreshView) MarshalName(m map[string]interface{}) {
+	m["namespace"] = this.name.Namespace()
+	m["bucket"] = this.name.Bucket()
+	m["scope"] = this.name.Scope()
+	m["keyspace"] = this.name.Keyspace()
+}
+
+func (this *RefreshView) MarshalJSON() ([]byte, error) {
+	r := map[string]interface{}{"type": "refreshView"}
+	this.MarshalName(r)
+	r["incremental"] = this.incremental
+	return json.Marshal(r)
+}
+
+func (this *RefreshView) Type() string {
+	return "REFRESH_VIEW"
+}
+
+func (this *RefreshView) String() string {
+	var s strings.Builder
+	s.WriteString("REFRESH MATERIALIZED VIEW ")
+	s.WriteString(this.name.ProtectedString())
+	if this.incremental {
+		s.WriteString(" INCREMENTAL")
+	}
+	return s.String()
+}
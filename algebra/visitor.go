@@ -42,6 +42,7 @@ type Visitor interface {
 	VisitCreateCollection(stmt *CreateCollection) (interface{}, error)
 	VisitDropCollection(stmt *DropCollection) (interface{}, error)
 	VisitFlushCollection(stmt *FlushCollection) (interface{}, error)
+	VisitTruncate(stmt *Truncate) (interface{}, error)
 	VisitCreateBucket(stmt *CreateBucket) (interface{}, error)
 	VisitAlterBucket(stmt *AlterBucket) (interface{}, error)
 	VisitDropBucket(stmt *DropBucket) (interface{}, error)
@@ -126,6 +127,20 @@ type Visitor interface {
 	VisitDropSequence(stmt *DropSequence) (interface{}, error)
 	VisitAlterSequence(stmt *AlterSequence) (interface{}, error)
 
+	VisitCreateView(stmt *CreateView) (interface{}, error)
+	VisitDropView(stmt *DropView) (interface{}, error)
+	VisitRefreshView(stmt *RefreshView) (interface{}, error)
+
+	VisitCreateTrigger(stmt *CreateTrigger) (interface{}, error)
+	VisitDropTrigger(stmt *DropTrigger) (interface{}, error)
+
+	VisitCreateJob(stmt *CreateJob) (interface{}, error)
+	VisitAlterJob(stmt *AlterJob) (interface{}, error)
+	VisitDropJob(stmt *DropJob) (interface{}, error)
+
+	VisitCreatePolicy(stmt *CreatePolicy) (interface{}, error)
+	VisitDropPolicy(stmt *DropPolicy) (interface{}, error)
+
 	VisitCreateCredentialStore(stmt *CreateCredentialStore) (any, error)
 	VisitAlterCredentialStore(stmt *AlterCredentialStore) (any, error)
 	VisitDropCredentialStore(stmt *DropCredentialStore) (any, error)
@@ -140,6 +155,7 @@ type NodeVisitor interface {
 	VisitJoin(node *Join) (interface{}, error)
 	VisitIndexJoin(node *IndexJoin) (interface{}, error)
 	VisitAnsiJoin(node *AnsiJoin) (interface{}, error)
+	VisitAsofJoin(node *AsofJoin) (interface{}, error)
 	VisitNest(node *Nest) (interface{}, error)
 	VisitIndexNest(node *IndexNest) (interface{}, error)
 	VisitAnsiNest(node *AnsiNest) (interface{}, error)
@@ -41,6 +41,7 @@ type With struct {
 	isUnion      bool
 	config       value.Value
 	cycle        *CycleCheck
+	materialize  expression.Materialization
 	errorContext expression.ErrorContext
 }
 
@@ -98,6 +99,14 @@ func (this *With) CycleFields() expression.Expressions {
 	return nil
 }
 
+func (this *With) Materialization() expression.Materialization {
+	return this.materialize
+}
+
+func (this *With) SetMaterialization(m expression.Materialization) {
+	this.materialize = m
+}
+
 func (this *With) ErrorContext() string {
 	return this.errorContext.String()
 }
@@ -120,7 +129,14 @@ func (this *With) String() string {
 }
 
 func (this *With) WriteSyntaxString(s *strings.Builder) {
-	s.WriteString("`" + this.alias + "`" + " AS ( ")
+	s.WriteString("`" + this.alias + "`" + " AS ")
+	switch this.materialize {
+	case expression.MATERIALIZE_ALWAYS:
+		s.WriteString("MATERIALIZED ")
+	case expression.MATERIALIZE_NEVER:
+		s.WriteString("NOT MATERIALIZED ")
+	}
+	s.WriteString("( ")
 	s.WriteString(this.expr.String())
 	if this.rexpr != nil {
 		if rsubq, ok := this.rexpr.(*Subquery); ok {
@@ -139,6 +155,9 @@ func (this *With) MarshalJSON() ([]byte, error) {
 	r := make(map[string]interface{}, 2)
 	r["alias"] = this.alias
 	r["expr"] = this.expr.String()
+	if this.materialize != expression.MATERIALIZE_DEFAULT {
+		r["materialized"] = this.materialize == expression.MATERIALIZE_ALWAYS
+	}
 	if this.rexpr != nil {
 		r["rexpr"] = this.rexpr.String()
 		if this.isUnion {
@@ -212,6 +231,10 @@ func (this *With) SplitRecursive() error {
 		return errors.NewRecursiveWithSemanticError("Order/Limit/Offset not allowed")
 	}
 
+	if this.materialize == expression.MATERIALIZE_NEVER {
+		return errors.NewRecursiveWithSemanticError("NOT MATERIALIZED not allowed")
+	}
+
 	var firstSelect, secondSelect *Select
 	if firstSelectTerm, ok := first.(*SelectTerm); ok {
 		// order/limit/offset check are handled in visitSelect is using Select Term
@@ -274,3 +297,22 @@ func (this *WithClause) Expressions() expression.Expressions {
 func (this *WithClause) MapExpressions(mapper expression.Mapper) error {
 	return this.withs.MapExpressions(mapper)
 }
+
+/*
+A CTE that is not materialized is inlined by the planner: a FROM clause
+reference to it is replaced by a subquery term over its definition,
+keeping the alias, join properties and join hint of the reference.
+*/
+func NewWithTerm(term *ExpressionTerm, body *Select) *SubqueryTerm {
+	rv := NewSubqueryTerm(body, term.Alias(), term.joinHint)
+	rv.property = term.property
+	rv.inlinedWith = true
+	return rv
+}
+
+/*
+Whether the term is an inlined CTE reference.
+*/
+func (this *SubqueryTerm) IsInlinedWith() bool {
+	return this.inlinedWith
+}
@@ -125,8 +125,9 @@ type ApiAuditFields struct {
 }
 
 // An auditor is a component that can accept an audit record for processing.
-// We create a formal interface, so we can have two Auditors: the regular one that
-// talks to the audit daemon, and a mock that just stores audit records for testing.
+// We create a formal interface, so we can have several Auditors: the regular one that
+// talks to the audit daemon, one that writes to audit sinks instead, and a mock that
+// just stores audit records for testing.
 // The mock is over in the test file.
 type Auditor interface {
 	auditInfo() *datastore.AuditInfo
@@ -135,6 +136,13 @@ type Auditor interface {
 	submit(entry auditQueueEntry)
 }
 
+// An auditor following the audit settings of the cluster, which records changes to them.
+type settingsAuditor interface {
+	Auditor
+
+	configurationChanged(change *n1qlConfigurationChangeEvent) error
+}
+
 type standardAuditor struct {
 	auditService     *adt.AuditSvc
 	auditRecordQueue chan auditQueueEntry
@@ -178,6 +186,10 @@ func (sa *standardAuditor) submit(entry auditQueueEntry) {
 	sa.auditRecordQueue <- entry
 }
 
+func (sa *standardAuditor) configurationChanged(change *n1qlConfigurationChangeEvent) error {
+	return sa.auditService.Write(_CONFIGURATION_CHANGE_EVENT, change)
+}
+
 var _AUDITOR Auditor
 
 func loggerFunc(level string, format string, args ...interface{}) string {
@@ -197,11 +209,27 @@ func loggerFunc(level string, format string, args ...interface{}) string {
 // numServicers is the number of worker threads we expect to see
 // accessing the audit functionality. It is NOT the number of worker threads
 // the audit system itself has.
-func StartAuditService(server string, numServicers int) {
+// When sinks are given, audit records are written to them rather than to the audit daemon,
+// and disabledEvents lists the events not to audit when the datastore has no audit
+// settings of its own, as with a standalone node.
+func StartAuditService(server string, numServicers int, sinks []AuditSink, disabledEvents []uint32) {
 	// No support for auditing?
 	// Set auditor to NIL for no auditing work at all.
 	if !VERSION_SUPPORTS_AUDIT {
 		_AUDITOR = nil
+		if len(sinks) > 0 {
+			names := make([]string, 0, len(sinks))
+			for _, sink := range sinks {
+				names = append(names, sink.Name())
+				sink.Close()
+			}
+			logging.Errorf("Audit sinks %v ignored: auditing is not supported in this edition", names)
+		}
+		return
+	}
+
+	if len(sinks) > 0 {
+		startSinkAuditor(numServicers, sinks, disabledEvents)
 		return
 	}
 
@@ -247,7 +275,36 @@ func StartAuditService(server string, numServicers int) {
 	_AUDITOR = auditor
 }
 
-func auditSettingsWorker(auditor *standardAuditor, num int) {
+func startSinkAuditor(numServicers int, sinks []AuditSink, disabledEvents []uint32) {
+	ds := datastore.GetDatastore()
+	if ds == nil {
+		logging.Errorf("Audit service not started: no data store available")
+		return
+	}
+
+	// Follow the audit settings of the cluster, if there are any.
+	auditInfo, err := ds.AuditInfo()
+	clusterSettings := err == nil
+	if !clusterSettings {
+		auditInfo = defaultAuditInfo(disabledEvents)
+	}
+	ds.EnableStorageAudit(auditInfo.AuditEnabled)
+
+	auditor := newSinkAuditor(sinks, auditInfo, numServicers*25)
+	if clusterSettings {
+		go auditSettingsWorker(auditor, 1)
+	}
+
+	names := make([]string, len(sinks))
+	for i, sink := range sinks {
+		names[i] = sink.Name()
+	}
+	logging.Infof("Audit records written to %v", strings.Join(names, ", "))
+
+	_AUDITOR = auditor
+}
+
+func auditSettingsWorker(auditor settingsAuditor, num int) {
 	// If this audit worker panics, start up a replacement.
 	defer func() {
 		r := recover()
@@ -277,13 +334,7 @@ func auditSettingsWorker(auditor *standardAuditor, num int) {
 			if curUid != auditInfo.Uid {
 				logging.Infof("Audit update handler function %d: Got updated audit settings: %+v", num,
 					stringifyauditInfo(*auditInfo))
-				change := n1qlConfigurationChangeEvent{
-					Timestamp:  time.Now().Format("2006-01-02T15:04:05.000Z07:00"),
-					RealUserid: adt.RealUserId{Domain: "local", Username: "@cbq-engine"},
-					Uuid:       auditInfo.Uid,
-				}
-
-				e := auditor.auditService.Write(28703, &change)
+				e := auditor.configurationChanged(configurationChange(auditInfo.Uid))
 				if e != nil {
 					return fmt.Errorf("Audit settings worker %d: Unable to send configuration change message: %v", num, e)
 				}
@@ -488,6 +539,7 @@ const (
 	API_STMT_ALTER_CREDENTIALSTORE       = 28750
 	API_STMT_DROP_CREDENTIALSTORE        = 28751
 	API_ADMIN_CATALOGS                   = 28752
+	API_STMT_TRUNCATE                    = 28753
 )
 
 // Event types are described in /query/etc/audit_descriptor.json
@@ -541,6 +593,7 @@ var _EVENT_TYPE_MAP = map[string]uint32{
 	"CREATE_CREDENTIALSTORE":    API_STMT_CREATE_CREDENTIALSTORE,
 	"ALTER_CREDENTIALSTORE":     API_STMT_ALTER_CREDENTIALSTORE,
 	"DROP_CREDENTIALSTORE":      API_STMT_DROP_CREDENTIALSTORE,
+	"TRUNCATE":                  API_STMT_TRUNCATE,
 }
 
 func Submit(event Auditable) {
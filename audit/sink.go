@@ -0,0 +1,200 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	adt "github.com/couchbase/goutils/go-cbaudit"
+	"github.com/couchbase/query/accounting"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/logging"
+)
+
+// An audit sink is a destination for audit records other than the audit daemon,
+// so that a node without one, such as a standalone node, still leaves an audit trail.
+// Records are handed over in batches, each record already encoded as a JSON object
+// carrying its event id, in the order they were submitted.
+// A sink is only ever written to by one goroutine at a time.
+type AuditSink interface {
+	Name() string
+	Write(records [][]byte) error
+	Close() error
+}
+
+const (
+	_SINK_BATCH_SIZE = 256 // records handed to a sink in one write
+
+	_CONFIGURATION_CHANGE_EVENT = 28703
+)
+
+// The auditor used when sinks are configured. Audit records go to every sink,
+// each sink being written by its own worker from its own queue, so a slow sink
+// does not hold the others up. Requests never wait on a sink: when its queue is
+// full, the record is dropped for that sink and counted as a failed audit action.
+type sinkAuditor struct {
+	queues []*sinkQueue
+
+	auditInfoLock sync.RWMutex
+	info          *datastore.AuditInfo
+}
+
+type sinkQueue struct {
+	sink    AuditSink
+	records chan []byte
+	dropped int64 // records dropped since the worker last reported them
+}
+
+func newSinkAuditor(sinks []AuditSink, info *datastore.AuditInfo, queueSize int) *sinkAuditor {
+	auditor := &sinkAuditor{info: info}
+	for _, sink := range sinks {
+		queue := &sinkQueue{sink: sink, records: make(chan []byte, queueSize)}
+		auditor.queues = append(auditor.queues, queue)
+		go sinkWorker(queue, 1)
+	}
+	return auditor
+}
+
+func (sa *sinkAuditor) auditInfo() *datastore.AuditInfo {
+	sa.auditInfoLock.RLock()
+	ret := sa.info
+	sa.auditInfoLock.RUnlock()
+	return ret
+}
+
+func (sa *sinkAuditor) setAuditInfo(info *datastore.AuditInfo) {
+	sa.auditInfoLock.Lock()
+	sa.info = info
+	sa.auditInfoLock.Unlock()
+}
+
+func (sa *sinkAuditor) submit(entry auditQueueEntry) {
+	var record interface{}
+	if entry.isQueryType {
+		record = entry.queryAuditRecord
+	} else {
+		record = entry.apiAuditRecord
+	}
+	sa.write(entry.eventId, record)
+}
+
+func (sa *sinkAuditor) configurationChanged(change *n1qlConfigurationChangeEvent) error {
+	sa.write(_CONFIGURATION_CHANGE_EVENT, change)
+	return nil
+}
+
+func (sa *sinkAuditor) write(eventId uint32, record interface{}) {
+	data, err := encodeAuditRecord(eventId, record)
+	if err != nil {
+		accounting.UpdateCounter(accounting.AUDIT_ACTIONS)
+		accounting.UpdateCounter(accounting.AUDIT_ACTIONS_FAILED)
+		logging.Errorf("Unable to encode audit record for event %d: %v", eventId, err)
+		return
+	}
+
+	// Unlike the audit daemon, a sink may be remote, and could stall every request that
+	// audits, so a record that does not fit in the queue is dropped instead.
+	for _, queue := range sa.queues {
+		select {
+		case queue.records <- data:
+		default:
+			accounting.UpdateCounter(accounting.AUDIT_ACTIONS)
+			accounting.UpdateCounter(accounting.AUDIT_ACTIONS_FAILED)
+			atomic.AddInt64(&queue.dropped, 1)
+		}
+	}
+}
+
+// The record as a JSON object, with the event id as its first field, as the audit daemon
+// would have written it.
+func encodeAuditRecord(eventId uint32, record interface{}) ([]byte, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] != '{' {
+		return nil, fmt.Errorf("audit record is not an object")
+	}
+	buf := make([]byte, 0, len(data)+16)
+	buf = append(buf, fmt.Sprintf("{\"id\":%d", eventId)...)
+	if len(data) > 2 {
+		buf = append(buf, ',')
+	}
+	return append(buf, data[1:]...), nil
+}
+
+func sinkWorker(queue *sinkQueue, num int) {
+	// If this sink worker panics, start up a replacement.
+	defer func() {
+		r := recover()
+		if r != nil {
+			logging.Errorf("Audit %v sink worker %d: Panic: %v. Starting a replacement.", queue.sink.Name(), num, r)
+			go sinkWorker(queue, num+1)
+		}
+	}()
+	logging.Infof("Starting audit %v sink worker %d", queue.sink.Name(), num)
+
+	batch := make([][]byte, 0, _SINK_BATCH_SIZE)
+	for {
+		batch = append(batch[:0], <-queue.records)
+
+		// Take whatever else is waiting, so that a busy node writes fewer, larger batches.
+	drain:
+		for len(batch) < _SINK_BATCH_SIZE {
+			select {
+			case record := <-queue.records:
+				batch = append(batch, record)
+			default:
+				break drain
+			}
+		}
+
+		for range batch {
+			accounting.UpdateCounter(accounting.AUDIT_ACTIONS)
+		}
+		err := queue.sink.Write(batch)
+		if err != nil {
+			for range batch {
+				accounting.UpdateCounter(accounting.AUDIT_ACTIONS_FAILED)
+			}
+			logging.Errorf("Audit %v sink worker %d: unable to write %d audit records: %v", queue.sink.Name(), num,
+				len(batch), err)
+		}
+		if dropped := atomic.SwapInt64(&queue.dropped, 0); dropped > 0 {
+			logging.Errorf("Audit %v sink worker %d: dropped %d audit records as the sink fell behind",
+				queue.sink.Name(), num, dropped)
+		}
+	}
+}
+
+// Audit settings for a datastore that has none of its own: everything is audited
+// bar the events listed.
+func defaultAuditInfo(disabledEvents []uint32) *datastore.AuditInfo {
+	info := &datastore.AuditInfo{
+		AuditEnabled:    true,
+		EventDisabled:   make(map[uint32]bool, len(disabledEvents)),
+		UserAllowlisted: make(map[datastore.UserInfo]bool),
+	}
+	for _, id := range disabledEvents {
+		info.EventDisabled[id] = true
+	}
+	return info
+}
+
+func configurationChange(uid string) *n1qlConfigurationChangeEvent {
+	return &n1qlConfigurationChangeEvent{
+		Timestamp:  time.Now().Format("2006-01-02T15:04:05.000Z07:00"),
+		RealUserid: adt.RealUserId{Domain: "local", Username: "@cbq-engine"},
+		Uuid:       uid,
+	}
+}
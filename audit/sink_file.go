@@ -0,0 +1,214 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package audit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/couchbase/query/encryption"
+	"github.com/couchbase/query/errors"
+)
+
+const (
+	DEF_AUDIT_FILE_SIZE  = 100 * 1024 * 1024 // bytes written before the file is rotated
+	DEF_AUDIT_FILE_COUNT = 10                // rotated files kept
+
+	_AUDIT_FILE_BUF_SIZE = 64 * 1024
+)
+
+var auditKeyDataType = encryption.KeyDataType{TypeName: encryption.LOG_KEY_DATATYPE}
+
+// Writes audit records as JSON lines to a file, which is rotated once it reaches its maximum size:
+// the file is renamed path.1, path.1 becomes path.2 and so on, the oldest being removed.
+// When the encryption provider has an active key for logs, files are encrypted with it, and a file
+// is also rotated when the active key changes, so that each file is encrypted with one key.
+type fileSink struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+	provider encryption.EncryptionProvider
+
+	f       *os.File
+	w       *bufio.Writer
+	ew      *encryption.CBEFWriter
+	keyId   string
+	written int64
+}
+
+func NewFileSink(path string, maxSize int64, maxFiles int, provider encryption.EncryptionProvider) (AuditSink, error) {
+	if maxSize <= 0 {
+		maxSize = DEF_AUDIT_FILE_SIZE
+	}
+	if maxFiles <= 0 {
+		maxFiles = DEF_AUDIT_FILE_COUNT
+	}
+
+	// The file is only created on the first write, by which time the encryption keys are known.
+	info, err := os.Stat(filepath.Dir(path))
+	if err == nil && !info.IsDir() {
+		err = fmt.Errorf("not a directory")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid audit file %v: %v", path, err)
+	}
+	return &fileSink{path: path, maxSize: maxSize, maxFiles: maxFiles, provider: provider}, nil
+}
+
+func (this *fileSink) Name() string {
+	return "file"
+}
+
+func (this *fileSink) Write(records [][]byte) error {
+	if this.f == nil {
+		if err := this.open(); err != nil {
+			return err
+		}
+	} else if this.provider != nil {
+		key, err := this.provider.GetActiveKey(auditKeyDataType)
+		if err == nil && activeKeyId(key) != this.keyId {
+			if err := this.reopen(); err != nil {
+				return err
+			}
+		}
+	}
+
+	var w io.Writer = this.w
+	if this.ew != nil {
+		w = this.ew
+	}
+	for _, record := range records {
+		n, err := w.Write(record)
+		if err == nil {
+			_, err = w.Write([]byte{'\n'})
+			n++
+		}
+		if err != nil {
+			this.close()
+			return err
+		}
+		this.written += int64(n)
+	}
+
+	// Records are flushed with every batch, so that as little as possible is lost if the node fails.
+	var err error
+	if this.ew != nil {
+		err = this.ew.Flush()
+	} else {
+		err = this.w.Flush()
+	}
+	if err != nil {
+		this.close()
+		return err
+	}
+
+	if this.written >= this.maxSize {
+		return this.reopen()
+	}
+	return nil
+}
+
+func (this *fileSink) Close() error {
+	return this.close()
+}
+
+// An encrypted file cannot be appended to, so a file that is already there is rotated
+// rather than written to.
+func (this *fileSink) open() error {
+	info, err := os.Stat(this.path)
+	if err == nil && info.Size() > 0 {
+		err = this.rotate()
+	} else if os.IsNotExist(err) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var key *encryption.EaRKey
+	if this.provider != nil {
+		var err1 errors.Error
+		key, err1 = this.provider.GetActiveKey(auditKeyDataType)
+		if err1 != nil {
+			return err1
+		}
+	}
+
+	f, err := os.OpenFile(this.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		ew, err1 := encryption.NewCBEFWriterSize(f, key, encryption.CBEF_NONE, _AUDIT_FILE_BUF_SIZE)
+		if err1 != nil {
+			f.Close()
+			os.Remove(this.path)
+			return err1
+		}
+		this.ew = ew
+	} else {
+		this.w = bufio.NewWriterSize(f, _AUDIT_FILE_BUF_SIZE)
+	}
+	this.f = f
+	this.keyId = activeKeyId(key)
+	this.written = 0
+	return nil
+}
+
+func (this *fileSink) close() error {
+	if this.f == nil {
+		return nil
+	}
+	var err error
+	if this.ew != nil {
+		err = this.ew.Close()
+		this.ew = nil
+	} else {
+		err = this.w.Flush()
+		this.w = nil
+	}
+	if err1 := this.f.Close(); err == nil {
+		err = err1
+	}
+	this.f = nil
+	return err
+}
+
+func (this *fileSink) reopen() error {
+	err := this.close()
+	if err == nil {
+		err = this.open()
+	}
+	return err
+}
+
+func (this *fileSink) rotate() error {
+	os.Remove(rotatedFileName(this.path, this.maxFiles))
+	for i := this.maxFiles - 1; i > 0; i-- {
+		err := os.Rename(rotatedFileName(this.path, i), rotatedFileName(this.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(this.path, rotatedFileName(this.path, 1))
+}
+
+func rotatedFileName(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+func activeKeyId(key *encryption.EaRKey) string {
+	if key == nil {
+		return encryption.UNENCRYPTED_KEY_ID
+	}
+	return key.Id
+}
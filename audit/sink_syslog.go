@@ -0,0 +1,161 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package audit
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	_SYSLOG_FACILITY = 13 // log audit
+	_SYSLOG_SEVERITY = 6  // informational
+	_SYSLOG_APP_NAME = "cbq-engine"
+	_SYSLOG_TIMEOUT  = 10 * time.Second
+)
+
+// Sends audit records as RFC 5424 messages, with the event id as the message id and the record
+// as the message. The address is udp://host:port, tcp://host:port, tls://host:port for RFC 5425,
+// or unix:///path for a local syslog daemon, such as unix:///dev/log.
+// Over a stream, messages are framed by octet counting; over a datagram socket, each is sent on its own.
+type syslogSink struct {
+	network string
+	address string
+	stream  bool
+	tls     bool
+
+	hostname string
+	procId   string
+
+	conn net.Conn
+}
+
+func NewSyslogSink(address string) (AuditSink, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid syslog address %v: %v", address, err)
+	}
+
+	this := &syslogSink{procId: strconv.Itoa(os.Getpid())}
+	switch u.Scheme {
+	case "udp":
+		this.network, this.address = "udp", u.Host
+	case "tcp":
+		this.network, this.address, this.stream = "tcp", u.Host, true
+	case "tls":
+		this.network, this.address, this.stream, this.tls = "tcp", u.Host, true, true
+	case "unix":
+		this.network, this.address = "unixgram", u.Path
+	default:
+		return nil, fmt.Errorf("Invalid syslog address %v: the scheme must be udp, tcp, tls or unix", address)
+	}
+	if this.address == "" {
+		return nil, fmt.Errorf("Invalid syslog address %v: no host or path", address)
+	}
+
+	this.hostname, err = os.Hostname()
+	if err != nil || this.hostname == "" {
+		this.hostname = "-"
+	}
+	return this, nil
+}
+
+func (this *syslogSink) Name() string {
+	return "syslog"
+}
+
+func (this *syslogSink) Write(records [][]byte) error {
+	for _, record := range records {
+		msg := this.message(record)
+
+		// A connection the daemon has dropped is only found out on writing, so try once more on a new one.
+		var err error
+		for attempt := 0; attempt < 2; attempt++ {
+			if this.conn == nil {
+				if err = this.connect(); err != nil {
+					return err
+				}
+			}
+			this.conn.SetWriteDeadline(time.Now().Add(_SYSLOG_TIMEOUT))
+			_, err = this.conn.Write(msg)
+			if err == nil {
+				break
+			}
+			this.conn.Close()
+			this.conn = nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *syslogSink) Close() error {
+	if this.conn == nil {
+		return nil
+	}
+	err := this.conn.Close()
+	this.conn = nil
+	return err
+}
+
+func (this *syslogSink) connect() error {
+	var err error
+	if this.tls {
+		dialer := &net.Dialer{Timeout: _SYSLOG_TIMEOUT}
+		this.conn, err = tls.DialWithDialer(dialer, this.network, this.address, &tls.Config{})
+	} else {
+		this.conn, err = net.DialTimeout(this.network, this.address, _SYSLOG_TIMEOUT)
+	}
+	if err != nil {
+		this.conn = nil
+		return fmt.Errorf("Unable to connect to syslog %v: %v", this.address, err)
+	}
+	return nil
+}
+
+// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (this *syslogSink) message(record []byte) []byte {
+	msgId := "-"
+	if id, ok := recordEventId(record); ok {
+		msgId = id
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s %s %s - ", _SYSLOG_FACILITY*8+_SYSLOG_SEVERITY,
+		time.Now().UTC().Format("2006-01-02T15:04:05.000000Z07:00"), this.hostname, _SYSLOG_APP_NAME, this.procId, msgId)
+
+	msg := make([]byte, 0, len(header)+len(record)+8)
+	if this.stream {
+		msg = strconv.AppendInt(msg, int64(len(header)+len(record)), 10)
+		msg = append(msg, ' ')
+	}
+	msg = append(msg, header...)
+	return append(msg, record...)
+}
+
+// The event id encodeAuditRecord put at the start of the record.
+func recordEventId(record []byte) (string, bool) {
+	const prefix = "{\"id\":"
+	if len(record) <= len(prefix) || string(record[:len(prefix)]) != prefix {
+		return "", false
+	}
+	i := len(prefix)
+	for i < len(record) && record[i] >= '0' && record[i] <= '9' {
+		i++
+	}
+	if i == len(prefix) {
+		return "", false
+	}
+	return string(record[len(prefix):i]), true
+}
@@ -0,0 +1,240 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// A sink that hands each record it is given to a channel.
+type channelSink struct {
+	records chan []byte
+}
+
+func (this *channelSink) Name() string {
+	return "channel"
+}
+
+func (this *channelSink) Write(records [][]byte) error {
+	for _, record := range records {
+		this.records <- record
+	}
+	return nil
+}
+
+func (this *channelSink) Close() error {
+	return nil
+}
+
+func TestSinkAuditor(t *testing.T) {
+	sink := &channelSink{records: make(chan []byte, 16)}
+	_AUDITOR = newSinkAuditor([]AuditSink{sink}, defaultAuditInfo([]uint32{28678}), 16)
+	defer func() { _AUDITOR = nil }()
+
+	auditable := &simpleAuditable{eventType: "SELECT", eventUsers: []string{"bill"}, statement: "SELECT 1"}
+	Submit(auditable)
+	auditable.eventType = "DELETE"
+	Submit(auditable)
+	auditable.eventType = "INSERT"
+	Submit(auditable)
+
+	for _, expected := range []float64{28672, 28676} {
+		var record map[string]interface{}
+		select {
+		case data := <-sink.records:
+			if !bytes.HasPrefix(data, []byte("{\"id\":")) {
+				t.Fatalf("Record does not start with its event id: %s", data)
+			}
+			if err := json.Unmarshal(data, &record); err != nil {
+				t.Fatalf("Record is not JSON: %v: %s", err, data)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected a record for event %v", expected)
+		}
+		if record["id"] != expected || record["statement"] != "SELECT 1" {
+			t.Fatalf("Expected event %v, found %v", expected, record)
+		}
+		if user, ok := record["real_userid"].(map[string]interface{}); !ok || user["user"] != "bill" {
+			t.Fatalf("Expected user bill, found %v", record["real_userid"])
+		}
+	}
+	select {
+	case data := <-sink.records:
+		t.Fatalf("Record written for a disabled event: %s", data)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSinkAuditorDrops(t *testing.T) {
+	// a sink that never takes a record, and a queue of one
+	sink := &channelSink{records: make(chan []byte)}
+	auditor := newSinkAuditor([]AuditSink{sink}, defaultAuditInfo(nil), 1)
+	_AUDITOR = auditor
+	defer func() { _AUDITOR = nil }()
+
+	done := make(chan bool)
+	go func() {
+		auditable := &simpleAuditable{eventType: "SELECT", eventUsers: []string{"bill"}, statement: "SELECT 1"}
+		for i := 0; i < 10; i++ {
+			Submit(auditable)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Submitting records blocked on a full queue")
+	}
+	if dropped := atomic.LoadInt64(&auditor.queues[0].dropped); dropped < 8 {
+		t.Fatalf("Expected at least 8 records to be dropped, found %v", dropped)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(path, []byte("{\"id\":0}\n"), 0600); err != nil {
+		t.Fatalf("Unable to write audit file: %v", err)
+	}
+	sink, err := NewFileSink(path, 64, 2, nil)
+	if err != nil {
+		t.Fatalf("Unable to create file sink: %v", err)
+	}
+
+	record := func(id int) []byte {
+		data, _ := encodeAuditRecord(uint32(id), map[string]interface{}{"statement": strings.Repeat("x", 20)})
+		return data
+	}
+	for i := 1; i <= 5; i++ {
+		if err := sink.Write([][]byte{record(i)}); err != nil {
+			t.Fatalf("Unable to write record %d: %v", i, err)
+		}
+	}
+	sink.Close()
+
+	// each file takes two records before it is rotated; the file that was there before
+	// has been rotated out of the two kept
+	expected := map[string][]string{
+		path:        {"5"},
+		path + ".1": {"3", "4"},
+		path + ".2": {"1", "2"},
+	}
+	for file, ids := range expected {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Unable to read %v: %v", file, err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if len(lines) != len(ids) {
+			t.Fatalf("Expected %d records in %v, found %d", len(ids), file, len(lines))
+		}
+		for i, line := range lines {
+			if id, _ := recordEventId([]byte(line)); id != ids[i] {
+				t.Fatalf("Expected event %v in %v, found %v", ids[i], file, line)
+			}
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("Expected only 2 rotated files")
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := NewSyslogSink("udp://" + conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Unable to create syslog sink: %v", err)
+	}
+	defer sink.Close()
+	record, _ := encodeAuditRecord(28672, map[string]interface{}{"statement": "SELECT 1"})
+	if err := sink.Write([][]byte{record}); err != nil {
+		t.Fatalf("Unable to write record: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("No syslog message received: %v", err)
+	}
+	fields := strings.SplitN(string(buf[:n]), " ", 8)
+	if len(fields) != 8 || fields[0] != "<110>1" || fields[3] != "cbq-engine" || fields[5] != "28672" ||
+		fields[6] != "-" || fields[7] != string(record) {
+		t.Fatalf("Unexpected syslog message: %s", buf[:n])
+	}
+	if _, err := time.Parse(time.RFC3339Nano, fields[1]); err != nil {
+		t.Fatalf("Invalid syslog timestamp %v: %v", fields[1], err)
+	}
+
+	if _, err := NewSyslogSink("http://localhost:514"); err == nil {
+		t.Fatalf("Syslog sink created with an invalid scheme")
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var lock sync.Mutex
+	var bodies [][]byte
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, body)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL)
+	if err != nil {
+		t.Fatalf("Unable to create webhook sink: %v", err)
+	}
+	sink.(*webhookSink).backoff = time.Millisecond
+	defer sink.Close()
+
+	first, _ := encodeAuditRecord(28672, map[string]interface{}{"statement": "SELECT 1"})
+	second, _ := encodeAuditRecord(28676, map[string]interface{}{"statement": "INSERT"})
+	if err := sink.Write([][]byte{first, second}); err != nil {
+		t.Fatalf("Records not posted after a failure: %v", err)
+	}
+
+	lock.Lock()
+	if len(bodies) != 1 {
+		t.Fatalf("Expected 1 post, found %d", len(bodies))
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(bodies[0], &records); err != nil || len(records) != 2 || records[1]["id"] != float64(28676) {
+		t.Fatalf("Unexpected body posted: %s", bodies[0])
+	}
+	failures = _WEBHOOK_ATTEMPTS
+	lock.Unlock()
+
+	if err := sink.Write([][]byte{first}); err == nil {
+		t.Fatalf("Records posted although the webhook failed")
+	}
+}
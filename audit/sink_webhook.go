@@ -0,0 +1,99 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	_WEBHOOK_TIMEOUT  = 10 * time.Second
+	_WEBHOOK_ATTEMPTS = 3
+	_WEBHOOK_BACKOFF  = time.Second
+)
+
+// Posts audit records to an http or https URL, each batch as a JSON array of records.
+// A batch that the endpoint fails, or answers with anything but a 2xx status, is sent again
+// a couple of times, backing off in between, before it is given up.
+type webhookSink struct {
+	url     string
+	display string // the URL without any password, for messages
+	client  *http.Client
+	backoff time.Duration
+}
+
+func NewWebhookSink(address string) (AuditSink, error) {
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("Invalid webhook URL %v: an http or https URL is required", address)
+	}
+	return &webhookSink{
+		url:     address,
+		display: u.Redacted(),
+		client:  &http.Client{Timeout: _WEBHOOK_TIMEOUT},
+		backoff: _WEBHOOK_BACKOFF,
+	}, nil
+}
+
+func (this *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (this *webhookSink) Write(records [][]byte) error {
+	body := bytes.NewBuffer(make([]byte, 0, 2+len(records)*512))
+	body.WriteByte('[')
+	for i, record := range records {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(record)
+	}
+	body.WriteByte(']')
+
+	var err error
+	for attempt := 0; attempt < _WEBHOOK_ATTEMPTS; attempt++ {
+		if attempt > 0 {
+			time.Sleep(this.backoff << (attempt - 1))
+		}
+		if err = this.post(body.Bytes()); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (this *webhookSink) Close() error {
+	this.client.CloseIdleConnections()
+	return nil
+}
+
+func (this *webhookSink) post(body []byte) error {
+	req, err := http.NewRequest("POST", this.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := this.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	// Drain the body, so the connection can be used again.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %v returned %v", this.display, resp.Status)
+	}
+	return nil
+}
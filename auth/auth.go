@@ -75,6 +75,7 @@ const (
 	PRIV_CATALOG_INSERT                         Privilege = 53 // INSERT access to catalog
 	PRIV_CATALOG_DELETE                         Privilege = 54 // DELETE access to catalog
 	PRIV_CREDENTIAL_WRITE                       Privilege = 55 // Write (CRUD) access to cluster credentials store
+	PRIV_QUERY_TRUNCATE                         Privilege = 56 // Ability to run TRUNCATE statements.
 )
 
 type PrivilegePair struct {
@@ -231,6 +232,7 @@ type Credentials struct {
 	HttpRequest           *http.Request
 	AuthenticatedUsers    AuthenticatedUsers
 	CbauthCredentialsList []cbauth.Creds
+	Bearer                *TokenIdentity // the identity of a bearer token, if the request has one
 }
 
 func NewCredentials(args ...string) *Credentials {
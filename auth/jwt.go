@@ -0,0 +1,497 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Bearer tokens are JSON Web Tokens, signed by an identity provider with a
+key of a JSON Web Key Set (JWKS) read from a file, or fetched from a URL
+and refreshed hourly, or as soon as a token is signed with a key it does
+not have. A token identifies the user a request runs as, in the domain
+the tokens are configured for. The roles it carries do not grant
+privileges, which on a cluster are those the user has been granted
+there; they are reported to the request, and matched against the
+grantees of policies.
+*/
+
+const DEF_JWT_CLOCK_SKEW = time.Minute
+const DEF_JWT_USER_CLAIM = "sub"
+const DEF_JWT_ROLES_CLAIM = "roles"
+const DEF_JWT_DOMAIN = "external"
+
+const _JWKS_REFRESH = time.Hour
+const _JWKS_RETRY = time.Minute // between fetches for keys a set does not have
+const _JWKS_SIZE_LIMIT = 1024 * 1024
+const _JWKS_TIMEOUT = 10 * time.Second
+
+type JWTConfig struct {
+	Issuers    []string      // accepted issuers, any if empty
+	JWKS       string        // the key set: a file, or an http or https URL
+	Audience   []string      // tokens must be intended for one of these, if any
+	ClockSkew  time.Duration // allowed when checking expiry and validity times
+	UserClaim  string        // the claim naming the user
+	RolesClaim string        // the claim listing the roles the token carries
+	Domain     string        // the domain of the users tokens name
+}
+
+// The identity a verified bearer token carries
+type TokenIdentity struct {
+	User    string
+	Domain  string
+	Roles   []string
+	Issuer  string
+	Expires time.Time
+	Claims  map[string]interface{}
+}
+
+// the user in the domain:user form of AuthenticatedUsers
+func (this *TokenIdentity) Name() string {
+	return this.Domain + ":" + this.User
+}
+
+type jwtKey struct {
+	alg string
+	key interface{}
+}
+
+type jwtVerifier struct {
+	sync.Mutex
+	config  JWTConfig
+	keys    map[string]*jwtKey
+	fetched time.Time
+	tried   time.Time
+	client  *http.Client
+}
+
+var bearer struct {
+	sync.RWMutex
+	verifier *jwtVerifier
+}
+
+/*
+Configures the verification of bearer tokens, and loads the keys they are
+signed with. A nil configuration disables bearer tokens.
+*/
+func SetJWTConfig(config *JWTConfig) error {
+	var verifier *jwtVerifier
+	if config != nil {
+		var err error
+		verifier, err = newJWTVerifier(config)
+		if err != nil {
+			return err
+		}
+	}
+	bearer.Lock()
+	bearer.verifier = verifier
+	bearer.Unlock()
+	return nil
+}
+
+func BearerEnabled() bool {
+	bearer.RLock()
+	defer bearer.RUnlock()
+	return bearer.verifier != nil
+}
+
+// Returns the bearer token of the request, if it has one
+func GetBearerToken(req *http.Request) (string, bool) {
+	headers := req.Header["Authorization"]
+	if len(headers) != 1 || len(headers[0]) < 7 || !strings.EqualFold(headers[0][:7], "Bearer ") {
+		return "", false
+	}
+	return strings.TrimSpace(headers[0][7:]), true
+}
+
+// Verifies a bearer token, and returns the identity it carries
+func VerifyBearerToken(token string) (*TokenIdentity, error) {
+	bearer.RLock()
+	verifier := bearer.verifier
+	bearer.RUnlock()
+	if verifier == nil {
+		return nil, fmt.Errorf("bearer tokens are not enabled")
+	}
+	return verifier.verify(token, time.Now())
+}
+
+func newJWTVerifier(config *JWTConfig) (*jwtVerifier, error) {
+	rv := &jwtVerifier{config: *config}
+	if rv.config.JWKS == "" {
+		return nil, fmt.Errorf("no JWKS configured")
+	}
+	if rv.config.ClockSkew < 0 {
+		return nil, fmt.Errorf("invalid clock skew %v", rv.config.ClockSkew)
+	}
+	if rv.config.UserClaim == "" {
+		rv.config.UserClaim = DEF_JWT_USER_CLAIM
+	}
+	if rv.config.RolesClaim == "" {
+		rv.config.RolesClaim = DEF_JWT_ROLES_CLAIM
+	}
+	if rv.config.Domain == "" {
+		rv.config.Domain = DEF_JWT_DOMAIN
+	}
+	if rv.remote() {
+		rv.client = &http.Client{Timeout: _JWKS_TIMEOUT}
+	}
+	if err := rv.load(time.Now()); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (this *jwtVerifier) remote() bool {
+	return strings.HasPrefix(this.config.JWKS, "http://") || strings.HasPrefix(this.config.JWKS, "https://")
+}
+
+func (this *jwtVerifier) load(now time.Time) error {
+	this.tried = now
+	keys, err := this.fetch()
+	if err != nil {
+		return err
+	}
+	this.keys = keys
+	this.fetched = now
+	return nil
+}
+
+// reads the key set; the verifier need not, and should not, be locked
+func (this *jwtVerifier) fetch() (map[string]*jwtKey, error) {
+	var data []byte
+	var err error
+	if this.remote() {
+		var resp *http.Response
+		resp, err = this.client.Get(this.config.JWKS)
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch JWKS: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("cannot fetch JWKS: %v", resp.Status)
+		}
+		data, err = io.ReadAll(io.LimitReader(resp.Body, _JWKS_SIZE_LIMIT))
+	} else {
+		data, err = os.ReadFile(this.config.JWKS)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read JWKS: %v", err)
+	}
+	return parseJWKS(data)
+}
+
+/*
+Returns the key a token is signed with, fetching the key set again when it is
+due a refresh, or does not have the key. The fetch is made without the verifier
+locked, so that other tokens are not held up by it, and only one request fetches
+at a time, others using the keys already there.
+*/
+func (this *jwtVerifier) key(kid string, now time.Time) *jwtKey {
+	this.Lock()
+	key := this.lookup(kid)
+	refresh := this.remote() && now.Sub(this.tried) > _JWKS_RETRY &&
+		(key == nil || now.Sub(this.fetched) > _JWKS_REFRESH)
+	if refresh {
+		this.tried = now
+	}
+	this.Unlock()
+	if !refresh {
+		return key
+	}
+
+	keys, err := this.fetch()
+	if err != nil {
+		return key
+	}
+	this.Lock()
+	this.keys = keys
+	this.fetched = now
+	key = this.lookup(kid)
+	this.Unlock()
+	return key
+}
+
+// a token without a key id can only be verified with the only key of a set
+func (this *jwtVerifier) lookup(kid string) *jwtKey {
+	if kid == "" && len(this.keys) == 1 {
+		for _, key := range this.keys {
+			return key
+		}
+	}
+	return this.keys[kid]
+}
+
+func (this *jwtVerifier) verify(token string, now time.Time) (*TokenIdentity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %v", err)
+	}
+	key := this.key(header.Kid, now)
+	if key == nil {
+		return nil, fmt.Errorf("unknown signing key '%v'", header.Kid)
+	}
+	if key.alg != "" && key.alg != header.Alg {
+		return nil, fmt.Errorf("algorithm %v does not match the signing key", header.Alg)
+	}
+	if err = verifyJWTSignature(header.Alg, key.key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	claims := make(map[string]interface{})
+	if err = decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %v", err)
+	}
+	return this.identity(claims, now)
+}
+
+func (this *jwtVerifier) identity(claims map[string]interface{}, now time.Time) (*TokenIdentity, error) {
+	skew := this.config.ClockSkew
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("token has no expiry")
+	}
+	expires := time.Unix(int64(exp), 0)
+	if now.After(expires.Add(skew)) {
+		return nil, fmt.Errorf("token has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(skew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, fmt.Errorf("token is not valid yet")
+	}
+	if iat, ok := claims["iat"].(float64); ok && now.Add(skew).Before(time.Unix(int64(iat), 0)) {
+		return nil, fmt.Errorf("token is issued in the future")
+	}
+
+	issuer, _ := claims["iss"].(string)
+	if len(this.config.Issuers) > 0 && !containsString(this.config.Issuers, issuer) {
+		return nil, fmt.Errorf("issuer '%v' is not accepted", issuer)
+	}
+	if len(this.config.Audience) > 0 {
+		found := false
+		for _, a := range claimStrings(claims["aud"]) {
+			if containsString(this.config.Audience, a) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("token is not intended for this service")
+		}
+	}
+
+	user, _ := claims[this.config.UserClaim].(string)
+	if user == "" || strings.ContainsRune(user, ':') {
+		return nil, fmt.Errorf("invalid user claim '%v'", this.config.UserClaim)
+	}
+	return &TokenIdentity{
+		User:    user,
+		Domain:  this.config.Domain,
+		Roles:   claimStrings(claims[this.config.RolesClaim]),
+		Issuer:  issuer,
+		Expires: expires,
+		Claims:  claims,
+	}, nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// a claim holding an array of strings, or a string of space separated ones
+func claimStrings(claim interface{}) []string {
+	switch claim := claim.(type) {
+	case string:
+		return strings.Fields(claim)
+	case []interface{}:
+		rv := make([]string, 0, len(claim))
+		for _, c := range claim {
+			if s, ok := c.(string); ok {
+				rv = append(rv, s)
+			}
+		}
+		return rv
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyJWTSignature(alg string, key interface{}, input string, signature []byte) error {
+	var hash crypto.Hash
+	if len(alg) == 5 {
+		switch alg[2:] {
+		case "256":
+			hash = crypto.SHA256
+		case "384":
+			hash = crypto.SHA384
+		case "512":
+			hash = crypto.SHA512
+		}
+	}
+	if hash == 0 {
+		return fmt.Errorf("unsupported algorithm '%v'", alg)
+	}
+	h := hash.New()
+	h.Write([]byte(input))
+	digest := h.Sum(nil)
+
+	var ok bool
+	switch alg[:2] {
+	case "RS":
+		if pub, isRSA := key.(*rsa.PublicKey); isRSA {
+			ok = rsa.VerifyPKCS1v15(pub, hash, digest, signature) == nil
+		}
+	case "PS":
+		if pub, isRSA := key.(*rsa.PublicKey); isRSA {
+			opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+			ok = rsa.VerifyPSS(pub, hash, digest, signature, opts) == nil
+		}
+	case "ES":
+		var curve elliptic.Curve
+		switch hash {
+		case crypto.SHA256:
+			curve = elliptic.P256()
+		case crypto.SHA384:
+			curve = elliptic.P384()
+		case crypto.SHA512:
+			curve = elliptic.P521()
+		}
+		if pub, isEC := key.(*ecdsa.PublicKey); isEC && pub.Curve == curve {
+			size := (curve.Params().BitSize + 7) / 8
+			if len(signature) == 2*size {
+				r := new(big.Int).SetBytes(signature[:size])
+				s := new(big.Int).SetBytes(signature[size:])
+				ok = ecdsa.Verify(pub, digest, r, s)
+			}
+		}
+	case "HS":
+		if secret, isHMAC := key.([]byte); isHMAC {
+			mac := hmac.New(hash.New, secret)
+			mac.Write([]byte(input))
+			ok = hmac.Equal(mac.Sum(nil), signature)
+		}
+	default:
+		return fmt.Errorf("unsupported algorithm '%v'", alg)
+	}
+	if !ok {
+		return fmt.Errorf("invalid token signature")
+	}
+	return nil
+}
+
+// the signing keys of a JWKS by key id; keys of other uses or types are skipped
+func parseJWKS(data []byte) (map[string]*jwtKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %v", err)
+	}
+
+	keys := make(map[string]*jwtKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var key interface{}
+		switch k.Kty {
+		case "RSA":
+			n, err1 := base64.RawURLEncoding.DecodeString(k.N)
+			e, err2 := base64.RawURLEncoding.DecodeString(k.E)
+			if err1 != nil || err2 != nil || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("invalid JWKS: RSA key '%v'", k.Kid)
+			}
+			key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch k.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				continue
+			}
+			x, err1 := base64.RawURLEncoding.DecodeString(k.X)
+			y, err2 := base64.RawURLEncoding.DecodeString(k.Y)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid JWKS: EC key '%v'", k.Kid)
+			}
+			pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+			if !curve.IsOnCurve(pub.X, pub.Y) {
+				return nil, fmt.Errorf("invalid JWKS: EC key '%v'", k.Kid)
+			}
+			key = pub
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("invalid JWKS: symmetric key '%v'", k.Kid)
+			}
+			key = secret
+		default:
+			continue
+		}
+		keys[k.Kid] = &jwtKey{alg: k.Alg, key: key}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("invalid JWKS: no signing keys")
+	}
+	return keys, nil
+}
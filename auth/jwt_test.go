@@ -0,0 +1,242 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testSigner struct {
+	kid  string
+	alg  string
+	sign func(input []byte) []byte
+	jwk  map[string]interface{}
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func sha256Sum(b []byte) []byte {
+	sum := sha256.Sum256(b)
+	return sum[:]
+}
+
+func newRSASigner(t *testing.T, kid string) *testSigner {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unable to generate RSA key: %v", err)
+	}
+	return &testSigner{
+		kid: kid,
+		alg: "RS256",
+		sign: func(input []byte) []byte {
+			sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sha256Sum(input))
+			return sig
+		},
+		jwk: map[string]interface{}{"kty": "RSA", "kid": kid, "use": "sig",
+			"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes())},
+	}
+}
+
+func newECSigner(t *testing.T, kid string) *testSigner {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unable to generate EC key: %v", err)
+	}
+	return &testSigner{
+		kid: kid,
+		alg: "ES256",
+		sign: func(input []byte) []byte {
+			r, s, _ := ecdsa.Sign(rand.Reader, key, sha256Sum(input))
+			sig := make([]byte, 64)
+			r.FillBytes(sig[:32])
+			s.FillBytes(sig[32:])
+			return sig
+		},
+		jwk: map[string]interface{}{"kty": "EC", "kid": kid, "crv": "P-256",
+			"x": b64(key.X.FillBytes(make([]byte, 32))), "y": b64(key.Y.FillBytes(make([]byte, 32)))},
+	}
+}
+
+func (this *testSigner) token(t *testing.T, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]interface{}{"alg": this.alg, "kid": this.kid, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("Unable to marshal claims: %v", err)
+	}
+	input := b64(header) + "." + b64(payload)
+	return input + "." + b64(this.sign([]byte(input)))
+}
+
+func writeJWKS(t *testing.T, signers ...*testSigner) string {
+	keys := make([]interface{}, len(signers))
+	for i, s := range signers {
+		keys[i] = s.jwk
+	}
+	data, _ := json.Marshal(map[string]interface{}{"keys": keys})
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatalf("Unable to write JWKS: %v", err)
+	}
+	return file
+}
+
+func TestBearerToken(t *testing.T) {
+	rsaSigner := newRSASigner(t, "rsa1")
+	ecSigner := newECSigner(t, "ec1")
+	err := SetJWTConfig(&JWTConfig{
+		Issuers:   []string{"https://idp.example.com"},
+		JWKS:      writeJWKS(t, rsaSigner, ecSigner),
+		Audience:  []string{"query"},
+		ClockSkew: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Unable to configure bearer tokens: %v", err)
+	}
+	defer SetJWTConfig(nil)
+
+	now := time.Now()
+	claims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   "https://idp.example.com",
+			"aud":   []string{"gateway", "query"},
+			"sub":   "alice",
+			"roles": []string{"analyst", "support"},
+			"exp":   now.Add(time.Hour).Unix(),
+			"iat":   now.Unix(),
+		}
+	}
+
+	for _, signer := range []*testSigner{rsaSigner, ecSigner} {
+		req, _ := http.NewRequest("POST", "/query/service", nil)
+		req.Header.Set("Authorization", "Bearer "+signer.token(t, claims()))
+		token, ok := GetBearerToken(req)
+		if !ok {
+			t.Fatalf("Bearer token not found")
+		}
+		identity, err := VerifyBearerToken(token)
+		if err != nil {
+			t.Fatalf("%v token rejected: %v", signer.alg, err)
+		}
+		if identity.Name() != "external:alice" || len(identity.Roles) != 2 || identity.Roles[1] != "support" {
+			t.Fatalf("Unexpected identity %v with roles %v", identity.Name(), identity.Roles)
+		}
+	}
+
+	rejected := map[string]func(map[string]interface{}){
+		"expired":       func(c map[string]interface{}) { c["exp"] = now.Add(-2 * time.Minute).Unix() },
+		"no expiry":     func(c map[string]interface{}) { delete(c, "exp") },
+		"not yet valid": func(c map[string]interface{}) { c["nbf"] = now.Add(2 * time.Minute).Unix() },
+		"issuer":        func(c map[string]interface{}) { c["iss"] = "https://other.example.com" },
+		"audience":      func(c map[string]interface{}) { c["aud"] = "gateway" },
+		"user":          func(c map[string]interface{}) { delete(c, "sub") },
+	}
+	for name, change := range rejected {
+		c := claims()
+		change(c)
+		if _, err := VerifyBearerToken(rsaSigner.token(t, c)); err == nil {
+			t.Fatalf("Token accepted with invalid %v", name)
+		}
+	}
+
+	// within the clock skew
+	c := claims()
+	c["exp"] = now.Add(-30 * time.Second).Unix()
+	if _, err := VerifyBearerToken(rsaSigner.token(t, c)); err != nil {
+		t.Fatalf("Token rejected within the clock skew: %v", err)
+	}
+
+	// a key that is not in the set, and a tampered token
+	if _, err := VerifyBearerToken(newRSASigner(t, "rsa2").token(t, claims())); err == nil {
+		t.Fatalf("Token accepted with an unknown key")
+	}
+	forged := newRSASigner(t, "rsa1")
+	if _, err := VerifyBearerToken(forged.token(t, claims())); err == nil {
+		t.Fatalf("Token accepted with a forged signature")
+	}
+
+	// the public key of an asymmetric algorithm may not be used as a shared secret
+	secret, _ := json.Marshal(rsaSigner.jwk)
+	confused := &testSigner{
+		kid: "rsa1",
+		alg: "HS256",
+		sign: func(input []byte) []byte {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(input)
+			return mac.Sum(nil)
+		},
+	}
+	if _, err := VerifyBearerToken(confused.token(t, claims())); err == nil {
+		t.Fatalf("Token accepted with a mismatched algorithm")
+	}
+}
+
+func TestBearerTokenJWKSURL(t *testing.T) {
+	first := newRSASigner(t, "k1")
+	second := newECSigner(t, "k2")
+	current := []*testSigner{first}
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		keys := make([]interface{}, len(current))
+		for i, s := range current {
+			keys[i] = s.jwk
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer server.Close()
+
+	verifier, err := newJWTVerifier(&JWTConfig{JWKS: server.URL, UserClaim: "email", Domain: "local"})
+	if err != nil {
+		t.Fatalf("Unable to fetch JWKS: %v", err)
+	}
+	now := time.Now()
+	claims := map[string]interface{}{"email": "bob@example.com", "roles": "reader writer",
+		"exp": now.Add(time.Hour).Unix()}
+	identity, err := verifier.verify(first.token(t, claims), now)
+	if err != nil {
+		t.Fatalf("Token rejected: %v", err)
+	}
+	if identity.Name() != "local:bob@example.com" || len(identity.Roles) != 2 || identity.Roles[0] != "reader" {
+		t.Fatalf("Unexpected identity %v with roles %v", identity.Name(), identity.Roles)
+	}
+
+	// keys rotated by the provider are fetched, though not more than once a minute
+	current = []*testSigner{second}
+	if _, err = verifier.verify(second.token(t, claims), now); err == nil {
+		t.Fatalf("Token accepted before the JWKS could be fetched again")
+	}
+	later := now.Add(2 * _JWKS_RETRY)
+	if _, err = verifier.verify(second.token(t, claims), later); err != nil {
+		t.Fatalf("Token rejected after the keys were rotated: %v", err)
+	}
+	if _, err = verifier.verify(first.token(t, claims), later); err == nil {
+		t.Fatalf("Token accepted with a retired key")
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Fatalf("Expected 2 fetches of the JWKS, got %v", n)
+	}
+}
@@ -0,0 +1,100 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package datastore
+
+import (
+	"sync"
+
+	"github.com/couchbase/query/value"
+)
+
+// A document change, numbered in the order the keyspace applied it
+type Change struct {
+	Seqno   uint64
+	Key     string
+	Value   value.Value // nil for a deletion
+	Deleted bool
+}
+
+/*
+ChangeSource is implemented by keyspaces that can report the changes
+made to their documents, for continuous queries.
+
+Changes returns up to max changes following the one numbered since, in
+order. When there are none yet it returns a channel closed as soon as
+there are. It returns false when the changes following since are no
+longer, or not yet, held by the source; the reader then has to start
+over from LastSeqno.
+*/
+type ChangeSource interface {
+	Changes(since uint64, max int) ([]*Change, <-chan struct{}, bool)
+	LastSeqno() uint64
+}
+
+/*
+ChangeLog is a ChangeSource holding the last changes of a keyspace in
+memory, for datastores with no change feed of their own. Writers never
+wait for readers: a reader that falls more than the size of the log
+behind loses its place.
+*/
+type ChangeLog struct {
+	sync.Mutex
+	changes []*Change
+	next    int    // position of the next change in changes
+	last    uint64 // number of the last change
+	wait    chan struct{}
+}
+
+func NewChangeLog(size int) *ChangeLog {
+	return &ChangeLog{
+		changes: make([]*Change, size),
+		wait:    make(chan struct{}),
+	}
+}
+
+func (this *ChangeLog) Append(key string, val value.Value, deleted bool) {
+	this.Lock()
+	this.last++
+	this.changes[this.next] = &Change{Seqno: this.last, Key: key, Value: val, Deleted: deleted}
+	this.next = (this.next + 1) % len(this.changes)
+	wait := this.wait
+	this.wait = make(chan struct{})
+	this.Unlock()
+	close(wait)
+}
+
+func (this *ChangeLog) Changes(since uint64, max int) ([]*Change, <-chan struct{}, bool) {
+	this.Lock()
+	defer this.Unlock()
+
+	size := uint64(len(this.changes))
+	if since > this.last || (this.last > size && since < this.last-size) {
+		return nil, nil, false
+	}
+	if since == this.last {
+		return nil, this.wait, true
+	}
+
+	n := this.last - since
+	if max > 0 && n > uint64(max) {
+		n = uint64(max)
+	}
+	rv := make([]*Change, 0, n)
+	start := (uint64(this.next) + size - (this.last - since)) % size
+	for i := uint64(0); i < n; i++ {
+		rv = append(rv, this.changes[(start+i)%size])
+	}
+	return rv, nil, true
+}
+
+func (this *ChangeLog) LastSeqno() uint64 {
+	this.Lock()
+	defer this.Unlock()
+	return this.last
+}
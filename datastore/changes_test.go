@@ -0,0 +1,47 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package datastore
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestChangeLog(t *testing.T) {
+	log := NewChangeLog(4)
+
+	changes, wait, ok := log.Changes(0, 10)
+	if !ok || len(changes) != 0 || wait == nil {
+		t.Fatalf("empty log: received %v changes, ok %v", len(changes), ok)
+	}
+	for i := 1; i <= 6; i++ {
+		log.Append(fmt.Sprintf("k%d", i), nil, false)
+	}
+	select {
+	case <-wait:
+	default:
+		t.Errorf("waiting reader not woken up")
+	}
+
+	if _, _, ok = log.Changes(1, 10); ok {
+		t.Errorf("cursor 1 expected to be expired")
+	}
+	if _, _, ok = log.Changes(7, 10); ok {
+		t.Errorf("cursor 7 expected to be unknown")
+	}
+
+	changes, _, ok = log.Changes(2, 3)
+	if !ok || len(changes) != 3 || changes[0].Key != "k3" || changes[2].Seqno != 5 {
+		t.Errorf("cursor 2: received %v changes, ok %v", len(changes), ok)
+	}
+	changes, wait, ok = log.Changes(6, 10)
+	if !ok || len(changes) != 0 || wait == nil || log.LastSeqno() != 6 {
+		t.Errorf("cursor 6: received %v changes, ok %v", len(changes), ok)
+	}
+}
@@ -55,6 +55,10 @@ func privilegeString(namespace, target, obj string, requested auth.Privilege) (s
 		permission = join5Strings("cluster.", obj, "[", target, "].n1ql.insert!execute")
 	case auth.PRIV_QUERY_DELETE:
 		permission = join5Strings("cluster.", obj, "[", target, "].n1ql.delete!execute")
+	case auth.PRIV_QUERY_TRUNCATE:
+		// ns_server defines no n1ql.truncate permission, nor a role granting it, so until it
+		// does TRUNCATE is checked against delete: truncating is deleting every document
+		permission = join5Strings("cluster.", obj, "[", target, "].n1ql.delete!execute")
 	case auth.PRIV_QUERY_BUILD_INDEX:
 		permission = join5Strings("cluster.", obj, "[", target, "].n1ql.index!build")
 	case auth.PRIV_QUERY_CREATE_INDEX:
@@ -30,12 +30,16 @@ import (
 
 	"github.com/couchbase/query/datastore"
 	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression/jsonschema"
 	"github.com/couchbase/query/functions"
 	functionsStorage "github.com/couchbase/query/functions/storage"
 	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/policies"
 	"github.com/couchbase/query/sequences"
+	"github.com/couchbase/query/triggers"
 	"github.com/couchbase/query/util"
 	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
 )
 
 const _DEFAULT_SCOPE_COLLECTION_NAME = "._default._default"
@@ -165,29 +169,57 @@ func (sc *scope) CreateCollection(context datastore.QueryContext, name, catalog,
 }
 
 func (sc *scope) AlterCollection(context datastore.QueryContext, name string, with value.Value) errors.Error {
-	var params map[string]any
-	if with != nil {
-		bytes, err := with.MarshalJSON()
-		if err == nil {
-			json.Unmarshal(bytes, &params)
+	// the validation rule is checked first and set last, so that a failed ALTER leaves it unchanged
+	with, rule, validate := splitValidationOption(with)
+	var coll *collection
+	var schema *jsonschema.Schema
+	if validate {
+		ks, err := sc.KeyspaceByName(name)
+		if err != nil {
+			return err
+		}
+		coll = ks.(*collection)
+		schema, err = coll.compileValidationRule(rule)
+		if err != nil {
+			return err
 		}
 	}
-	ctx, cancel := reqContext(context)
-	defer cancel()
-	cbErr := sc.bucket.cbbucket.AlterCollection(context.Credential(), sc.id, name, params, ctx)
-	if cbErr != nil {
-		return errors.NewCbBucketAlterCollectionError(sc.objectFullName(name), cbErr)
+
+	if with != nil || !validate {
+		var params map[string]any
+		if with != nil {
+			bytes, err := with.MarshalJSON()
+			if err == nil {
+				json.Unmarshal(bytes, &params)
+			}
+		}
+		ctx, cancel := reqContext(context)
+		defer cancel()
+		cbErr := sc.bucket.cbbucket.AlterCollection(context.Credential(), sc.id, name, params, ctx)
+		if cbErr != nil {
+			return errors.NewCbBucketAlterCollectionError(sc.objectFullName(name), cbErr)
+		}
+	}
+
+	if validate {
+		return coll.setValidationRule(rule, schema)
 	}
 	return nil
 }
 
 func (sc *scope) DropCollection(context datastore.QueryContext, name string) errors.Error {
+	ks, _ := sc.KeyspaceByName(name)
 	ctx, cancel := reqContext(context)
 	defer cancel()
 	err := sc.bucket.cbbucket.DropCollection(context.Credential(), sc.id, name, ctx)
 	if err != nil {
 		return errors.NewCbBucketDropCollectionError(sc.objectFullName(name), err)
 	}
+	if coll, ok := ks.(*collection); ok {
+		coll.dropValidationRule()
+	}
+	triggers.DropKeyspaceTriggers(sc.bucket.namespace.name, sc.bucket.name, sc.id, sc.uid, name)
+	policies.DropKeyspacePolicies(sc.bucket.namespace.name, sc.bucket.name, sc.id, sc.uid, name)
 	sc.bucket.setNeedsManifest()
 	return nil
 }
@@ -220,6 +252,8 @@ type collection struct {
 	isSystem         bool
 	isExternal       bool
 	externalEntry    *extparams.ExternalCollectionEntry
+	validation       *jsonschema.Schema // cached validation rule
+	validationRev    int32              // validation rules revision the cached rule was loaded at
 }
 
 func getUser(context datastore.QueryContext) string {
@@ -980,6 +1014,9 @@ func clearOldScope(bucket *keyspace, s *scope, isDropBucket bool, cleanUp bool)
 		if err := s.DropAllSequences(); err == nil || err.Code() != errors.E_CB_KEYSPACE_NOT_FOUND {
 			functionsStorage.DropScope(bucket.namespace.name, bucket.name, s.Name(), s.Uid())
 			aus.DropScope(bucket.namespace.name, bucket.name, s.Name(), s.Uid())
+			views.DropAllViews(bucket.namespace.name, bucket.name, s.Name(), s.Uid())
+			triggers.DropAllTriggers(bucket.namespace.name, bucket.name, s.Name(), s.Uid())
+			policies.DropAllPolicies(bucket.namespace.name, bucket.name, s.Name(), s.Uid())
 			return true
 		}
 	}
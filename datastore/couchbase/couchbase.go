@@ -3941,7 +3941,8 @@ func CleanupSystemCollection(namespace string, bucket string) {
 			toDelete := false
 			isCBOKeyspaceDoc := false
 
-			if len(parts) == 3 && (parts[0] == "seq" || parts[0] == "cbo" || parts[0] == "udf") {
+			if len(parts) == 3 && (parts[0] == "seq" || parts[0] == "cbo" || parts[0] == "udf" || parts[0] == "view" ||
+				parts[0] == "trigger" || parts[0] == "policy") {
 				path := parts[len(parts)-1]
 				if parts[0] == "cbo" {
 					keyspace, keyspaceMayContainUUID, isKeyspaceDoc, err := GetCBOKeyspaceFromKey(path)
@@ -3972,7 +3973,7 @@ func CleanupSystemCollection(namespace string, bucket string) {
 					if err == nil && s.Uid() != parts[1] {
 						err = errors.NewCbScopeNotFoundError(nil, s.Name()) // placeholder to trigger deletion
 					}
-					if err == nil && parts[0] == "cbo" {
+					if err == nil && (parts[0] == "cbo" || parts[0] == "trigger" || parts[0] == "policy") {
 						_, err = s.KeyspaceByName(elements[1])
 					}
 					if err != nil {
@@ -0,0 +1,104 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package couchbase
+
+import (
+	"fmt"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/tenant"
+	"github.com/couchbase/query/transactions"
+	"github.com/couchbase/query/value"
+)
+
+const _TRUNCATE_BATCH = 512
+
+type scannableKeyspace interface {
+	datastore.Keyspace
+	datastore.SeqScanner
+}
+
+// collection implements datastore.TruncatingKeyspace
+func (coll *collection) Truncate(context datastore.QueryContext) (int, errors.Error) {
+	if coll.isSystem || coll.isExternal {
+		return 0, errors.NewNoTruncateError(coll.QualifiedName())
+	}
+	return truncate(coll, context)
+}
+
+// keyspace (as a bucket) implements datastore.TruncatingKeyspace, on the default collection
+func (ks *keyspace) Truncate(context datastore.QueryContext) (int, errors.Error) {
+	if isSysBucket(ks.name) || ks.IsSystemCollection() || ks.IsExternalCollection() {
+		return 0, errors.NewNoTruncateError(ks.QualifiedName())
+	}
+	return truncate(ks, context)
+}
+
+/*
+Unlike FLUSH COLLECTION, which needs flush to be enabled on the bucket,
+truncation deletes the documents one by one, as found by a range scan of
+the keys, so that indexes are maintained rather than rebuilt. Within a
+transaction the documents are fetched first, as transactional deletes
+need their metadata; documents inserted earlier in the same transaction
+are not seen by the scan and are left in place. The documents deleted
+are counted even when the truncation fails part way through.
+*/
+func truncate(ks scannableKeyspace, context datastore.QueryContext) (int, errors.Error) {
+	ranges := []*datastore.SeqScanRange{{Start: _SCAN_RANGE_MIN, ExcludeStart: true,
+		End: _SCAN_RANGE_MAX, ExcludeEnd: true}}
+	scan, err := ks.StartKeyScan(context, ranges, 0, 0, false, _DEFAULT_REQUEST_TIMEOUT, _TRUNCATE_BATCH,
+		tenant.IsServerless(), nil, datastore.UNBOUNDED, nil)
+	if err != nil {
+		return 0, errors.NewTruncateError(err, ks.QualifiedName())
+	}
+	defer ks.StopScan(scan)
+
+	txContext, _ := context.GetTxContext().(*transactions.TranContext)
+	deleted := 0
+	for {
+		// a request stopped or timed out leaves the keyspace partly truncated
+		if !context.IsActive() {
+			return deleted, errors.NewTruncateError(fmt.Errorf("stopped after deleting %v documents", deleted),
+				ks.QualifiedName())
+		}
+		keys, err, timeout := ks.FetchKeys(scan, _SCAN_POLL_TIMOUT)
+		if err != nil {
+			return deleted, errors.NewTruncateError(err, ks.QualifiedName())
+		} else if timeout {
+			continue
+		} else if len(keys) == 0 {
+			break
+		}
+
+		pairs := make(value.Pairs, 0, len(keys))
+		if txContext != nil {
+			docs := make(map[string]value.AnnotatedValue, len(keys))
+			errs := ks.Fetch(keys, docs, context, nil, nil, false)
+			if len(errs) > 0 {
+				return deleted, errs[0]
+			}
+			for _, key := range keys {
+				if doc, ok := docs[key]; ok && doc != nil {
+					pairs = append(pairs, value.Pair{Name: key, Value: doc})
+				}
+			}
+		} else {
+			for _, key := range keys {
+				pairs = append(pairs, value.Pair{Name: key})
+			}
+		}
+		n, _, errs := ks.Delete(pairs, context, false)
+		deleted += n
+		if len(errs) > 0 {
+			return deleted, errs[0]
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,99 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package couchbase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/timestamp"
+	"github.com/couchbase/query/value"
+)
+
+// A keyspace whose key scan returns fixed batches of keys
+type truncateKeyspace struct {
+	datastore.Keyspace
+	batches [][]string
+	deleted []string
+}
+
+func (this *truncateKeyspace) QualifiedName() string {
+	return "default:b.s.c"
+}
+
+func (this *truncateKeyspace) StartKeyScan(context datastore.QueryContext, ranges []*datastore.SeqScanRange, offset int64,
+	limit int64, ordered bool, timeout time.Duration, pipelineSize int, serverless bool, skipKey func(string) bool,
+	cons datastore.ScanConsistency, vector timestamp.Vector) (interface{}, errors.Error) {
+	return this, nil
+}
+
+func (this *truncateKeyspace) StopScan(interface{}) (uint64, errors.Error) {
+	return 0, nil
+}
+
+func (this *truncateKeyspace) FetchKeys(interface{}, time.Duration) ([]string, errors.Error, bool) {
+	if len(this.batches) == 0 {
+		return nil, nil, false
+	}
+	keys := this.batches[0]
+	this.batches = this.batches[1:]
+	return keys, nil, false
+}
+
+func (this *truncateKeyspace) Delete(deletes value.Pairs, context datastore.QueryContext, preserveMutations bool) (
+	int, value.Pairs, errors.Errors) {
+	for _, p := range deletes {
+		this.deleted = append(this.deleted, p.Name)
+	}
+	return len(deletes), nil, nil
+}
+
+// A request that is stopped after a number of checks
+type truncateContext struct {
+	datastore.QueryContext
+	active int
+}
+
+func (this *truncateContext) IsActive() bool {
+	this.active--
+	return this.active >= 0
+}
+
+func (this *truncateContext) GetTxContext() interface{} {
+	return nil
+}
+
+func TestTruncateScan(t *testing.T) {
+	ks := &truncateKeyspace{batches: [][]string{{"k1", "k2"}, {"k3"}}}
+	n, err := truncate(ks, &truncateContext{active: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else if n != 3 {
+		t.Fatalf("Expected 3 documents deleted, found %v", n)
+	}
+	if strings.Join(ks.deleted, ",") != "k1,k2,k3" {
+		t.Fatalf("Expected every key to be deleted, found %v", ks.deleted)
+	}
+
+	// a request stopped part way through fails rather than reporting the keyspace empty
+	ks = &truncateKeyspace{batches: [][]string{{"k1", "k2"}, {"k3"}}}
+	n, err = truncate(ks, &truncateContext{active: 1})
+	if n != 2 {
+		t.Fatalf("Expected 2 documents deleted, found %v", n)
+	}
+	if err == nil || err.Code() != errors.E_TRUNCATE || !strings.Contains(err.Error(), "default:b.s.c") {
+		t.Fatalf("Expected the truncate to fail, found %v", err)
+	}
+	if cause := err.GetICause(); cause == nil || cause.Error() != "stopped after deleting 2 documents" {
+		t.Fatalf("Expected the documents deleted to be reported, found %v", cause)
+	}
+}
@@ -0,0 +1,258 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package couchbase
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/couchbase/cbauth/metakv"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/distributed"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression/jsonschema"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/value"
+)
+
+/*
+Collection validation rules are JSON schemas that documents written by
+DML statements must conform to. They are set with
+
+	ALTER COLLECTION ks WITH {"validation": schema}
+
+and dropped by setting the option to null. The rule is stored in the
+query system collection of the bucket, keyed by collection uid, and
+cached in the collection object. Changes are broadcast via a metakv
+revision counter, which invalidates the cached rules on all nodes.
+*/
+
+const _ALTER_COLLECTION_VALIDATION = "validation"
+const _VALIDATION_RULE = "validation::"
+const _VALIDATION_REVISION_PATH = "/query/validation_cache/"
+const _VALIDATION_REVISION = _VALIDATION_REVISION_PATH + "revision"
+
+// 0 is never a valid revision, so that collections start with no cached rule
+var validationRevision int32 = 1
+
+// Starts monitoring the validation rules of the cluster; to be called once at server startup
+func InitValidation() {
+	err := metakv.Add(_VALIDATION_REVISION, fmtValidationRevision())
+	if err != nil && err != metakv.ErrRevMismatch {
+		logging.Warnf("Unable to start validation rules cache monitor: %v", err)
+	}
+	go metakv.RunObserveChildren(_VALIDATION_REVISION_PATH, validationChangeMonitor, make(chan struct{}))
+}
+
+func validationChangeMonitor(kve metakv.KVEntry) error {
+	if kve.Path != _VALIDATION_REVISION {
+		return nil
+	}
+	node, _ := distributed.RemoteAccess().SplitKey(string(kve.Value))
+	if node == "" || node != distributed.RemoteAccess().WhoAmI() {
+		atomic.AddInt32(&validationRevision, 1)
+	}
+	return nil
+}
+
+func nextValidationRevision() int32 {
+	rev := atomic.AddInt32(&validationRevision, 1)
+	err := metakv.Set(_VALIDATION_REVISION, fmtValidationRevision(), nil)
+	if err != nil && err.Error() == "Not found" {
+		err = metakv.Add(_VALIDATION_REVISION, fmtValidationRevision())
+	}
+	if err != nil {
+		logging.Infof("Unable to update validation rules cache monitor %v", errors.NewMetaKVChangeCounterError(err))
+	}
+	return rev
+}
+
+func fmtValidationRevision() []byte {
+	return []byte(distributed.RemoteAccess().MakeKey(distributed.RemoteAccess().WhoAmI(),
+		strconv.Itoa(int(atomic.LoadInt32(&validationRevision)))))
+}
+
+func (coll *collection) validationKey() string {
+	return _VALIDATION_RULE + coll.uidString + "::" + coll.scope.id + "." + coll.id
+}
+
+/*
+Remove the validation option from the ALTER COLLECTION options, returning
+the remaining options, which are passed on to the cluster manager.
+*/
+func splitValidationOption(with value.Value) (value.Value, value.Value, bool) {
+	if with == nil || with.Type() != value.OBJECT {
+		return with, nil, false
+	}
+	rule, ok := with.Field(_ALTER_COLLECTION_VALIDATION)
+	if !ok {
+		return with, nil, false
+	}
+
+	rest := make(map[string]interface{}, len(with.Fields()))
+	for k, v := range with.Fields() {
+		if k != _ALTER_COLLECTION_VALIDATION {
+			rest[k] = v
+		}
+	}
+	if len(rest) == 0 {
+		return nil, rule, true
+	}
+	return value.NewValue(rest), rule, true
+}
+
+// The compiled rule, nil if the rule is being dropped
+func (coll *collection) compileValidationRule(rule value.Value) (*jsonschema.Schema, errors.Error) {
+	name := coll.QualifiedName()
+	if coll.isSystem || coll.isExternal {
+		return nil, errors.NewCbBucketAlterCollectionError(name,
+			fmt.Errorf("validation rules are not supported for this collection"))
+	}
+	if rule.Type() == value.NULL {
+		return nil, nil
+	}
+	schema, err := jsonschema.Compile(rule)
+	if err != nil {
+		return nil, errors.NewCbBucketAlterCollectionError(name, errors.NewJSONSchemaInvalidError(err))
+	}
+	return schema, nil
+}
+
+func (coll *collection) setValidationRule(rule value.Value, schema *jsonschema.Schema) errors.Error {
+	name := coll.QualifiedName()
+	sys, err := datastore.GetDatastore().GetSystemCollection(coll.bucket.name)
+	if err == nil && sys == nil {
+		err = errors.NewCbKeyspaceNotFoundError(nil, fullName(coll.bucket.namespace.name, coll.bucket.name,
+			_BUCKET_SYSTEM_SCOPE, _BUCKET_SYSTEM_COLLECTION))
+	}
+	if err != nil {
+		return errors.NewCbBucketAlterCollectionError(name, err)
+	}
+
+	pairs := make([]value.Pair, 1)
+	pairs[0].Name = coll.validationKey()
+	var errs errors.Errors
+	if schema == nil {
+		_, _, errs = sys.Delete(pairs, datastore.GetDurableQueryContextFor(sys), false)
+		if len(errs) > 0 && errors.IsNotFoundError("", errs[0]) {
+			errs = nil
+		}
+	} else {
+		m := map[string]interface{}{
+			"keyspace": name,
+			"rule":     rule,
+		}
+		pairs[0].Value = value.NewAnnotatedValue(value.NewValue(m))
+		_, _, errs = sys.Upsert(pairs, datastore.GetDurableQueryContextFor(sys), false)
+	}
+	if len(errs) > 0 {
+		return errors.NewCbBucketAlterCollectionError(name, errs[0])
+	}
+
+	rev := nextValidationRevision()
+	coll.Lock()
+	coll.validation = schema
+	coll.validationRev = rev
+	coll.Unlock()
+	return nil
+}
+
+/*
+Drop the rule of a collection that is being dropped. Failures are only
+logged, since the key of the rule is never reused.
+*/
+func (coll *collection) dropValidationRule() {
+	sys, err := datastore.GetDatastore().GetSystemCollection(coll.bucket.name)
+	if err != nil || sys == nil {
+		return
+	}
+	pairs := make([]value.Pair, 1)
+	pairs[0].Name = coll.validationKey()
+	_, _, errs := sys.Delete(pairs, datastore.GetDurableQueryContextFor(sys), false)
+	if len(errs) > 0 && !errors.IsNotFoundError("", errs[0]) {
+		logging.Infof("Unable to drop validation rule of %v: %v", coll.QualifiedName(), errs[0])
+	}
+}
+
+/*
+collection implements datastore.ValidatingKeyspace
+*/
+func (coll *collection) ValidationRule() (*jsonschema.Schema, errors.Error) {
+	rev := atomic.LoadInt32(&validationRevision)
+	coll.Lock()
+	if coll.validationRev == rev {
+		schema := coll.validation
+		coll.Unlock()
+		return schema, nil
+	}
+	coll.Unlock()
+
+	schema, err := coll.loadValidationRule()
+	if err != nil {
+		return nil, err
+	}
+
+	coll.Lock()
+	coll.validation = schema
+	coll.validationRev = rev
+	coll.Unlock()
+	return schema, nil
+}
+
+func (coll *collection) loadValidationRule() (*jsonschema.Schema, errors.Error) {
+	if coll.isSystem || coll.isExternal {
+		return nil, nil
+	}
+
+	sys, err := datastore.GetDatastore().GetSystemCollection(coll.bucket.name)
+	if err != nil {
+		if err.Code() == errors.E_CB_SCOPE_NOT_FOUND || errors.IsNotFoundError("", err) {
+			// no system collection, no rules
+			return nil, nil
+		}
+		return nil, err
+	} else if sys == nil {
+		return nil, nil
+	}
+
+	res := make(map[string]value.AnnotatedValue, 1)
+	keys := []string{coll.validationKey()}
+	errs := sys.Fetch(keys, res, datastore.NULL_QUERY_CONTEXT, nil, nil, false)
+	if len(errs) > 0 {
+		if !errors.IsNotFoundError("", errs[0]) && !errs[0].HasCause(errors.E_CB_BULK_GET) {
+			return nil, errs[0]
+		}
+		return nil, nil
+	}
+
+	av, ok := res[keys[0]]
+	if !ok {
+		return nil, nil
+	}
+	rule, ok := av.Field("rule")
+	if !ok {
+		return nil, nil
+	}
+	schema, e := jsonschema.Compile(rule)
+	if e != nil {
+		return nil, errors.NewJSONSchemaInvalidError(e)
+	}
+	return schema, nil
+}
+
+/*
+A bucket used as a keyspace has the rule of its default collection.
+*/
+func (b *keyspace) ValidationRule() (*jsonschema.Schema, errors.Error) {
+	if coll, ok := b.defaultCollection.(*collection); ok && coll != nil {
+		return coll.ValidationRule()
+	}
+	return nil, nil
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/couchbase/query/encryption"
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/expression/jsonschema"
 	"github.com/couchbase/query/logging"
 	"github.com/couchbase/query/tenant"
 	"github.com/couchbase/query/timestamp"
@@ -469,6 +470,16 @@ type KeyspaceMetadata interface {
 	MetadataId() string                // A unique identifier across all of the stores. We choose the path of the object
 }
 
+// Keyspaces that can have a validation rule, a JSON schema that documents written by DML statements must conform to
+type ValidatingKeyspace interface {
+	ValidationRule() (*jsonschema.Schema, errors.Error) // nil if the keyspace has no rule
+}
+
+// Keyspaces that can remove all of their documents, keeping indexes, sequences and statistics
+type TruncatingKeyspace interface {
+	Truncate(context QueryContext) (int, errors.Error) // the documents deleted, within the transaction of the context, if any
+}
+
 // migration infrastructure
 
 type Migration int
@@ -27,6 +27,7 @@ import (
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/expression"
 	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/statistics"
 	"github.com/couchbase/query/timestamp"
 	"github.com/couchbase/query/util"
 	"github.com/couchbase/query/value"
@@ -135,7 +136,7 @@ func (s *store) Inferencers() ([]datastore.Inferencer, errors.Error) {
 }
 
 func (s *store) StatUpdater() (datastore.StatUpdater, errors.Error) {
-	return nil, errors.NewOtherNotImplementedError(nil, "UPDATE STATISTICS")
+	return statistics.NewStatUpdater(), nil
 }
 
 func (s *store) SetConnectionSecurityConfig(conSecConfig *datastore.ConnectionSecurityConfig) {
@@ -395,6 +396,7 @@ type keyspace struct {
 	name      string
 	fi        datastore.Indexer
 	fileLock  sync.Mutex
+	changes   *datastore.ChangeLog
 }
 
 func (b *keyspace) NamespaceId() string {
@@ -611,6 +613,7 @@ func (b *keyspace) performOp(op int, kvPairs value.Pairs, preserveMutations bool
 			errs = append(errs, errors.NewFileDMLError(nil, opToString(op)+" Failed "+err.Error()))
 		} else {
 			rCount++
+			b.changed(key, value)
 			if preserveMutations {
 				rParis = append(rParis, kv)
 			}
@@ -660,6 +663,7 @@ func (b *keyspace) Delete(deletes value.Pairs, context datastore.QueryContext, p
 			}
 		} else {
 			dCount++
+			b.changes.Append(key, nil, true)
 
 			if preserveMutations {
 				deleted = append(deleted, pair)
@@ -694,6 +698,45 @@ func (b *keyspace) Flush(context datastore.QueryContext) errors.Error {
 	return errors.NewNoFlushError(b.name)
 }
 
+// Remove the document files of the keyspace; the indexes of the file datastore are computed on the fly
+func (b *keyspace) Truncate(context datastore.QueryContext) (int, errors.Error) {
+	b.fileLock.Lock()
+	defer b.fileLock.Unlock()
+
+	dirEntries, er := os.ReadDir(b.path())
+	if er != nil {
+		return 0, errors.NewFileDatastoreError(er, "")
+	}
+	deleted := 0
+	for _, ent := range dirEntries {
+		if ent.IsDir() || !strings.HasSuffix(ent.Name(), ".json") {
+			continue
+		}
+		if er = os.Remove(filepath.Join(b.path(), ent.Name())); er != nil {
+			if !os.IsNotExist(er) {
+				return deleted, errors.NewFileDatastoreError(er, "")
+			}
+			continue
+		}
+		deleted++
+		b.changes.Append(strings.TrimSuffix(ent.Name(), ".json"), nil, true)
+	}
+	return deleted, nil
+}
+
+func (b *keyspace) changed(key string, data []byte) {
+	b.changes.Append(key, value.NewValue(data), false)
+}
+
+// The last document changes are kept for continuous queries
+func (b *keyspace) Changes(since uint64, max int) ([]*datastore.Change, <-chan struct{}, bool) {
+	return b.changes.Changes(since, max)
+}
+
+func (b *keyspace) LastSeqno() uint64 {
+	return b.changes.LastSeqno()
+}
+
 func (b *keyspace) IsBucket() bool {
 	return true
 }
@@ -730,11 +773,15 @@ func (b *keyspace) keyPath(key string) (string, errors.Error) {
 	return path, nil
 }
 
+// The number of document changes each keyspace keeps for continuous queries
+const _CHANGE_LOG_SIZE = 4096
+
 // newKeyspace creates a new keyspace.
 func newKeyspace(p *namespace, dir string) (b *keyspace, e errors.Error) {
 	b = new(keyspace)
 	b.namespace = p
 	b.name = dir
+	b.changes = datastore.NewChangeLog(_CHANGE_LOG_SIZE)
 
 	fi, er := os.Stat(b.path())
 	if er != nil {
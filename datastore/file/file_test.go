@@ -11,6 +11,8 @@ package file
 import (
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -191,6 +193,52 @@ func TestFile(t *testing.T) {
 
 }
 
+func TestTruncate(t *testing.T) {
+	dir := t.TempDir()
+	ksPath := filepath.Join(dir, "default", "orders")
+	if err := os.MkdirAll(ksPath, 0755); err != nil {
+		t.Fatalf("failed to create keyspace: %v", err)
+	}
+	for _, name := range []string{"o1.json", "o2.json", "README"} {
+		if err := os.WriteFile(filepath.Join(ksPath, name), []byte(`{"id": 1}`), 0644); err != nil {
+			t.Fatalf("failed to create %v: %v", name, err)
+		}
+	}
+
+	store, err := NewDatastore(dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	namespace, err := store.NamespaceByName("default")
+	if err != nil {
+		t.Fatalf("failed to get namespace: %v", err)
+	}
+	keyspace, err := namespace.KeyspaceByName("orders")
+	if err != nil {
+		t.Fatalf("failed to get keyspace by name: orders")
+	}
+	truncating, ok := keyspace.(datastore.TruncatingKeyspace)
+	if !ok {
+		t.Fatalf("Expected keyspace orders to support truncate")
+	}
+	n, err := truncating.Truncate(datastore.NULL_QUERY_CONTEXT)
+	if err != nil {
+		t.Fatalf("failed to truncate orders: %v", err)
+	} else if n != 2 {
+		t.Errorf("Expected 2 documents deleted, found %v", n)
+	}
+
+	docs := make(map[string]value.AnnotatedValue, 2)
+	keyspace.Fetch([]string{"o1", "o2"}, docs, datastore.NULL_QUERY_CONTEXT, nil, nil, false)
+	if len(docs) != 0 {
+		t.Errorf("Expected no documents after truncate, found %v", len(docs))
+	}
+	// files that are not documents are left alone
+	if _, er := os.Stat(filepath.Join(ksPath, "README")); er != nil {
+		t.Errorf("Expected README to be kept: %v", er)
+	}
+}
+
 type testingContext struct {
 	t *testing.T
 }
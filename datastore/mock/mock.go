@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/couchbase/query/auth"
 	"github.com/couchbase/query/datastore"
@@ -25,6 +26,7 @@ import (
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/expression"
 	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/statistics"
 	"github.com/couchbase/query/timestamp"
 	"github.com/couchbase/query/value"
 )
@@ -122,7 +124,7 @@ func (s *store) Inferencers() ([]datastore.Inferencer, errors.Error) {
 }
 
 func (s *store) StatUpdater() (datastore.StatUpdater, errors.Error) {
-	return nil, errors.NewOtherNotImplementedError(nil, "UPDATE STATISTICS")
+	return statistics.NewStatUpdater(), nil
 }
 
 func (s *store) SetConnectionSecurityConfig(conSecConfig *datastore.ConnectionSecurityConfig) {
@@ -279,9 +281,10 @@ func (p *namespace) BucketByName(name string) (datastore.Bucket, errors.Error) {
 
 // keyspace is a mock-based keyspace.
 type keyspace struct {
+	sync.RWMutex
 	namespace *namespace
 	name      string
-	nitems    int
+	nitems    int // guarded by the lock, as TRUNCATE sets it while scans read it
 	mi        datastore.Indexer
 }
 
@@ -352,12 +355,18 @@ func (this *keyspace) Stats(context datastore.QueryContext, which []datastore.Ke
 	return res, err
 }
 
+func (b *keyspace) items() int {
+	b.RLock()
+	defer b.RUnlock()
+	return b.nitems
+}
+
 func (b *keyspace) Count(context datastore.QueryContext) (int64, errors.Error) {
-	return int64(b.nitems), nil
+	return int64(b.items()), nil
 }
 
 func (b *keyspace) Size(context datastore.QueryContext) (int64, errors.Error) {
-	return int64(b.nitems) * 25, nil // assumes each document is 25 bytes, see genItem()
+	return int64(b.items()) * 25, nil // assumes each document is 25 bytes, see genItem()
 }
 
 func (b *keyspace) Indexer(name datastore.IndexType) (datastore.Indexer, errors.Error) {
@@ -392,7 +401,7 @@ func (b *keyspace) fetchOne(key string) (value.AnnotatedValue, errors.Error) {
 	if e != nil {
 		return nil, errors.NewOtherKeyNotFoundError(e, fmt.Sprintf("no mock item: %v", key))
 	} else {
-		return genItem(i, b.nitems)
+		return genItem(i, b.items())
 	}
 }
 
@@ -455,6 +464,15 @@ func (b *keyspace) Flush(context datastore.QueryContext) errors.Error {
 	return errors.NewNoFlushError(b.name)
 }
 
+// Mock documents are generated from their keys: there are none past nitems
+func (b *keyspace) Truncate(context datastore.QueryContext) (int, errors.Error) {
+	b.Lock()
+	deleted := b.nitems
+	b.nitems = 0
+	b.Unlock()
+	return deleted, nil
+}
+
 func (b *keyspace) IsBucket() bool {
 	return true
 }
@@ -726,11 +744,12 @@ func (pi *primaryIndex) Scan(requestId string, span *datastore.Span, distinct bo
 		}
 	}
 
+	nitems := pi.keyspace.items()
 	if limit == 0 {
-		limit = int64(pi.keyspace.nitems)
+		limit = int64(nitems)
 	}
 
-	for i := 0; i < pi.keyspace.nitems && int64(i) < limit; i++ {
+	for i := 0; i < nitems && int64(i) < limit; i++ {
 		id := strconv.Itoa(i)
 
 		if low != "" &&
@@ -756,11 +775,12 @@ func (pi *primaryIndex) ScanEntries(requestId string, limit int64, cons datastor
 	vector timestamp.Vector, conn *datastore.IndexConnection) {
 	defer conn.Sender().Close()
 
+	nitems := pi.keyspace.items()
 	if limit == 0 {
-		limit = int64(pi.keyspace.nitems)
+		limit = int64(nitems)
 	}
 
-	for i := 0; i < pi.keyspace.nitems && int64(i) < limit; i++ {
+	for i := 0; i < nitems && int64(i) < limit; i++ {
 		entry := datastore.IndexEntry{PrimaryKey: strconv.Itoa(i)}
 		conn.Sender().SendEntry(&entry)
 	}
@@ -309,3 +309,48 @@ func doIndexScan(t *testing.T, b datastore.Keyspace, span *datastore.Span) (
 
 	return
 }
+
+func TestMockTruncate(t *testing.T) {
+	s, err := NewDatastore("mock:")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	p, err := s.NamespaceByName("p0")
+	if err != nil {
+		t.Fatalf("expected namespace p0")
+	}
+	b, err := p.KeyspaceByName("b0")
+	if err != nil {
+		t.Fatalf("expected keyspace b0")
+	}
+
+	indexers, err := b.Indexers()
+	if err != nil {
+		t.Fatalf("failed to retrieve indexers")
+	}
+	idx, err := indexers[0].IndexByName("#primary")
+	if err != nil {
+		t.Fatalf("failed to retrieve primary index")
+	}
+
+	// scans may run as the keyspace is truncated
+	conn := datastore.NewIndexConnection(&testingContext{t})
+	go idx.Scan("", &datastore.Span{Range: datastore.Range{Inclusion: datastore.BOTH}}, false, 0,
+		datastore.UNBOUNDED, nil, conn)
+	if _, err = b.(datastore.TruncatingKeyspace).Truncate(datastore.NULL_QUERY_CONTEXT); err != nil {
+		t.Fatalf("failed to truncate: %v", err)
+	}
+	for {
+		entry, ok := conn.Sender().GetEntry()
+		if entry == nil || !ok {
+			break
+		}
+	}
+
+	if n, _ := b.Count(datastore.NULL_QUERY_CONTEXT); n != 0 {
+		t.Fatalf("expected no documents after truncate, found %v", n)
+	}
+	if e, _ := doIndexScan(t, b, &datastore.Span{Range: datastore.Range{Inclusion: datastore.BOTH}}); len(e) != 0 {
+		t.Fatalf("expected no index entries after truncate, found %v", len(e))
+	}
+}
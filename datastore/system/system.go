@@ -56,6 +56,10 @@ const KEYSPACE_NAME_TRANSACTIONS = "transactions"
 const KEYSPACE_NAME_VITALS = "vitals"
 const KEYSPACE_NAME_SEQUENCES = "sequences"
 const KEYSPACE_NAME_ALL_SEQUENCES = "all_sequences"
+const KEYSPACE_NAME_VIEWS = "views"
+const KEYSPACE_NAME_TRIGGERS = "triggers"
+const KEYSPACE_NAME_POLICIES = "policies"
+const KEYSPACE_NAME_JOBS = "jobs"
 const KEYSPACE_NAME_AUS = "aus"
 const KEYSPACE_NAME_AUS_SETTINGS = "aus_settings"
 const KEYSPACE_NAME_AWR = "awr"
@@ -129,6 +133,9 @@ func (s *store) PrivilegesFromPath(fullname string, keyspace string, privilege a
 		case KEYSPACE_NAME_FUNCTIONS:
 		case KEYSPACE_NAME_SEQUENCES:
 		case KEYSPACE_NAME_ALL_SEQUENCES:
+		case KEYSPACE_NAME_VIEWS:
+		case KEYSPACE_NAME_TRIGGERS:
+		case KEYSPACE_NAME_POLICIES:
 		case KEYSPACE_NAME_NATURAL_CHAT:
 
 		// currently these keyspaces require system read for select if on prem and open (but limited to the user) for elixir
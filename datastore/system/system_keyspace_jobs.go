@@ -0,0 +1,162 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package system
+
+import (
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/jobs"
+	"github.com/couchbase/query/timestamp"
+	"github.com/couchbase/query/value"
+)
+
+type jobsKeyspace struct {
+	keyspaceBase
+	indexer datastore.Indexer
+}
+
+// to create an instance of the system:jobs keyspace, keyed by job name
+func newJobsKeyspace(p *namespace) (*jobsKeyspace, errors.Error) {
+	b := new(jobsKeyspace)
+	setKeyspaceBase(&b.keyspaceBase, p, KEYSPACE_NAME_JOBS)
+
+	primary := &jobsIndex{name: PRIMARY_INDEX_NAME, keyspace: b}
+	b.indexer = newSystemIndexer(b, primary)
+	setIndexBase(&primary.indexBase, b.indexer)
+
+	return b, nil
+}
+
+func (b *jobsKeyspace) Id() string {
+	return b.name
+}
+
+func (b *jobsKeyspace) Name() string {
+	return b.name
+}
+
+func (b *jobsKeyspace) NamespaceId() string {
+	return b.namespace.Id()
+}
+
+func (b *jobsKeyspace) Count(context datastore.QueryContext) (int64, errors.Error) {
+	return jobs.CountJobs()
+}
+
+func (b *jobsKeyspace) Size(context datastore.QueryContext) (int64, errors.Error) {
+	return -1, nil
+}
+
+func (b *jobsKeyspace) Indexer(name datastore.IndexType) (datastore.Indexer, errors.Error) {
+	return b.indexer, nil
+}
+
+func (b *jobsKeyspace) Indexers() ([]datastore.Indexer, errors.Error) {
+	return []datastore.Indexer{b.indexer}, nil
+}
+
+func (b *jobsKeyspace) Fetch(keys []string, keysMap map[string]value.AnnotatedValue, context datastore.QueryContext,
+	subPath []string, projection []string, useSubDoc bool) (errs errors.Errors) {
+
+	for _, key := range keys {
+		av, err := jobs.FetchJob(key)
+		if err != nil {
+			errs = append(errs, err)
+		} else if av != nil {
+			av.SetId(key)
+			av.SetMetaField(value.META_KEYSPACE, b.fullName)
+			keysMap[key] = av
+		}
+	}
+	return
+}
+
+func (b *jobsKeyspace) Release(close bool) {
+}
+
+type jobsIndex struct {
+	indexBase
+	name     string
+	keyspace *jobsKeyspace
+}
+
+func (ji *jobsIndex) KeyspaceId() string {
+	return ji.keyspace.Id()
+}
+
+func (ji *jobsIndex) Id() string {
+	return ji.name
+}
+
+func (ji *jobsIndex) Name() string {
+	return ji.name
+}
+
+func (ji *jobsIndex) Type() datastore.IndexType {
+	return datastore.SYSTEM
+}
+
+func (ji *jobsIndex) Indexer() datastore.Indexer {
+	return ji.indexer
+}
+
+func (ji *jobsIndex) SeekKey() expression.Expressions {
+	return nil
+}
+
+func (ji *jobsIndex) RangeKey() expression.Expressions {
+	return nil
+}
+
+func (ji *jobsIndex) Condition() expression.Expression {
+	return nil
+}
+
+func (ji *jobsIndex) IsPrimary() bool {
+	return true
+}
+
+func (ji *jobsIndex) State() (state datastore.IndexState, msg string, err errors.Error) {
+	return datastore.ONLINE, "", nil
+}
+
+func (ji *jobsIndex) Statistics(requestId string, span *datastore.Span) (datastore.Statistics, errors.Error) {
+	return nil, nil
+}
+
+func (ji *jobsIndex) Drop(requestId string) errors.Error {
+	return errors.NewSystemIdxNoDropError(nil, "")
+}
+
+func (ji *jobsIndex) Scan(requestId string, span *datastore.Span, distinct bool, limit int64, cons datastore.ScanConsistency,
+	vector timestamp.Vector, conn *datastore.IndexConnection) {
+	ji.ScanEntries(requestId, limit, cons, vector, conn)
+}
+
+func (ji *jobsIndex) ScanEntries(requestId string, limit int64, cons datastore.ScanConsistency,
+	vector timestamp.Vector, conn *datastore.IndexConnection) {
+	defer conn.Sender().Close()
+
+	names, err := jobs.ListJobNames()
+	if err != nil {
+		conn.Error(err)
+		return
+	}
+	for _, name := range names {
+		if limit == 0 {
+			break
+		}
+		entry := datastore.IndexEntry{PrimaryKey: name}
+		if !sendSystemKey(conn, &entry) {
+			return
+		}
+		limit--
+	}
+}
@@ -0,0 +1,256 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package system
+
+import (
+	"math"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/expression/parser"
+	"github.com/couchbase/query/policies"
+	"github.com/couchbase/query/timestamp"
+	"github.com/couchbase/query/value"
+)
+
+type policyKeyspace struct {
+	keyspaceBase
+	store   datastore.Datastore
+	indexer datastore.Indexer
+}
+
+func (b *policyKeyspace) Release(close bool) {
+}
+
+func (b *policyKeyspace) NamespaceId() string {
+	return b.namespace.Id()
+}
+
+func (b *policyKeyspace) Id() string {
+	return b.Name()
+}
+
+func (b *policyKeyspace) Name() string {
+	return b.name
+}
+
+func (b *policyKeyspace) Count(context datastore.QueryContext) (int64, errors.Error) {
+	count := int64(0)
+	err := b.forEachScope(context, nil, func(namespace, bucket, scope string) bool {
+		keys, _ := policies.ListPolicyKeys(namespace, bucket, scope, math.MaxInt64)
+		count += int64(len(keys))
+		return true
+	}, func() {
+		context.Warning(errors.NewSystemFilteredRowsWarning("system:policies"))
+	})
+	if err != nil {
+		return 0, errors.NewSystemDatastoreError(err, "")
+	}
+	return count, nil
+}
+
+// Calls f for every scope the user may read, and filtered for the others
+func (b *policyKeyspace) forEachScope(context datastore.QueryContext, bucketFilter func(string) bool,
+	f func(namespace, bucket, scope string) bool, filtered func()) errors.Error {
+
+	namespaceIds, err := b.store.NamespaceIds()
+	if err != nil {
+		return err
+	}
+
+	// this access check is done to check if the user has system catalog permissions
+	// i.e if checking permissions on individual entities in the system keyspace can be avoided.
+	// thus consider this check an internal action.
+	canAccessAll := canAccessSystemTables(context, true)
+	for _, namespaceId := range namespaceIds {
+		namespace, err := b.store.NamespaceById(namespaceId)
+		if err != nil {
+			continue
+		}
+		ds := namespace.Datastore()
+
+		objects, err := namespace.Objects(context.Credentials(), bucketFilter, true)
+		if err != nil {
+			continue
+		}
+		for _, object := range objects {
+			if !object.IsBucket || (bucketFilter != nil && !bucketFilter(object.Id)) {
+				continue
+			}
+			bucket, err := namespace.BucketById(object.Id)
+			if err != nil {
+				continue
+			}
+			scopeIds, _ := bucket.ScopeIds()
+			for _, scopeId := range scopeIds {
+				if canAccessAll || canRead(context, ds, namespaceId, object.Id, scopeId) {
+					if !f(namespaceId, object.Id, scopeId) {
+						return nil
+					}
+				} else {
+					filtered()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (b *policyKeyspace) Size(context datastore.QueryContext) (int64, errors.Error) {
+	return -1, nil
+}
+
+func (b *policyKeyspace) Indexer(name datastore.IndexType) (datastore.Indexer, errors.Error) {
+	return b.indexer, nil
+}
+
+func (b *policyKeyspace) Indexers() ([]datastore.Indexer, errors.Error) {
+	return []datastore.Indexer{b.indexer}, nil
+}
+
+func (b *policyKeyspace) Fetch(keys []string, keysMap map[string]value.AnnotatedValue,
+	context datastore.QueryContext, subPaths []string, projection []string, useSubDoc bool) (errs errors.Errors) {
+
+	for _, key := range keys {
+		av, err := policies.FetchPolicy(key)
+		if err != nil {
+			errs = append(errs, err)
+		} else if av != nil {
+			av.SetId(key)
+			av.SetMetaField(value.META_KEYSPACE, b.fullName)
+			keysMap[key] = av
+		}
+	}
+	return
+}
+
+func newPoliciesKeyspace(p *namespace, store datastore.Datastore, name string) (*policyKeyspace, errors.Error) {
+	b := new(policyKeyspace)
+	b.store = store
+	setKeyspaceBase(&b.keyspaceBase, p, name)
+
+	primary := &policyIndex{name: PRIMARY_INDEX_NAME, keyspace: b, primary: true}
+	b.indexer = newSystemIndexer(b, primary)
+	setIndexBase(&primary.indexBase, b.indexer)
+
+	// add a secondary index on `bucket`
+	expr, err := parser.Parse("`bucket`")
+
+	if err == nil {
+		key := expression.Expressions{expr}
+		buckets := &policyIndex{
+			name:     "#buckets",
+			keyspace: b,
+			primary:  false,
+			idxKey:   key,
+		}
+		setIndexBase(&buckets.indexBase, b.indexer)
+		b.indexer.(*systemIndexer).AddIndex(buckets.name, buckets)
+	} else {
+		return nil, errors.NewSystemDatastoreError(err, "")
+	}
+
+	return b, nil
+}
+
+type policyIndex struct {
+	indexBase
+	name     string
+	keyspace *policyKeyspace
+	primary  bool
+	idxKey   expression.Expressions
+}
+
+func (pi *policyIndex) KeyspaceId() string {
+	return pi.keyspace.Id()
+}
+
+func (pi *policyIndex) Id() string {
+	return pi.Name()
+}
+
+func (pi *policyIndex) Name() string {
+	return pi.name
+}
+
+func (pi *policyIndex) Type() datastore.IndexType {
+	return datastore.SYSTEM
+}
+
+func (pi *policyIndex) SeekKey() expression.Expressions {
+	return nil
+}
+
+func (pi *policyIndex) RangeKey() expression.Expressions {
+	return pi.idxKey
+}
+
+func (pi *policyIndex) Condition() expression.Expression {
+	return nil
+}
+
+func (pi *policyIndex) IsPrimary() bool {
+	return pi.primary
+}
+
+func (pi *policyIndex) State() (state datastore.IndexState, msg string, err errors.Error) {
+	return datastore.ONLINE, "", nil
+}
+
+func (pi *policyIndex) Statistics(requestId string, span *datastore.Span) (
+	datastore.Statistics, errors.Error) {
+	return nil, nil
+}
+
+func (pi *policyIndex) Drop(requestId string) errors.Error {
+	return errors.NewSystemIdxNoDropError(nil, "")
+}
+
+func (pi *policyIndex) Scan(requestId string, span *datastore.Span, distinct bool, limit int64,
+	cons datastore.ScanConsistency, vector timestamp.Vector, conn *datastore.IndexConnection) {
+	var filter func(string) bool
+	spanEvaluator, err := compileSpan(span)
+	if err != nil {
+		conn.Error(err)
+		return
+	}
+	if !pi.primary {
+		filter = func(name string) bool {
+			return spanEvaluator.evaluate(name)
+		}
+	}
+	pi.doScanEntries(filter, limit, conn)
+}
+
+func (pi *policyIndex) ScanEntries(requestId string, limit int64, cons datastore.ScanConsistency,
+	vector timestamp.Vector, conn *datastore.IndexConnection) {
+	pi.doScanEntries(nil, limit, conn)
+}
+
+func (pi *policyIndex) doScanEntries(filter func(string) bool, limit int64, conn *datastore.IndexConnection) {
+	defer conn.Sender().Close()
+
+	pi.keyspace.forEachScope(conn.QueryContext(), filter, func(namespace, bucket, scope string) bool {
+		keys, err := policies.ListPolicyKeys(namespace, bucket, scope, limit)
+		if err != nil {
+			return true
+		}
+		for _, key := range keys {
+			entry := datastore.IndexEntry{PrimaryKey: key}
+			if !sendSystemKey(conn, &entry) {
+				return false
+			}
+			limit--
+		}
+		return limit > 0
+	}, func() {
+		conn.Warning(errors.NewSystemFilteredRowsWarning("system:policies"))
+	})
+}
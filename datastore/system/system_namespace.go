@@ -291,6 +291,30 @@ func (p *namespace) loadKeyspaces() (e errors.Error) {
 	}
 	registerKeyspace(p, aqk)
 
+	vk, e := newViewsKeyspace(p, p.store.actualStore, KEYSPACE_NAME_VIEWS)
+	if e != nil {
+		return e
+	}
+	registerKeyspace(p, vk)
+
+	tk, e := newTriggersKeyspace(p, p.store.actualStore, KEYSPACE_NAME_TRIGGERS)
+	if e != nil {
+		return e
+	}
+	registerKeyspace(p, tk)
+
+	pk, e := newPoliciesKeyspace(p, p.store.actualStore, KEYSPACE_NAME_POLICIES)
+	if e != nil {
+		return e
+	}
+	registerKeyspace(p, pk)
+
+	jk, e := newJobsKeyspace(p)
+	if e != nil {
+		return e
+	}
+	registerKeyspace(p, jk)
+
 	ausK, e := newAusKeyspace(p)
 	if e != nil {
 		return e
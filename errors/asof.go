@@ -0,0 +1,18 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package errors
+
+import (
+	"fmt"
+)
+
+func NewAsofJoinOnclauseError(alias, reason string) Error {
+	return &err{level: EXCEPTION, ICode: E_ASOF_JOIN_ONCLAUSE, IKey: "plan.asof_join.onclause",
+		InternalMsg: fmt.Sprintf("ASOF JOIN on %s: %s", alias, reason), InternalCaller: CallerN(1)}
+}
@@ -90,6 +90,7 @@ const (
 	E_SERVICE_NO_CLIENT                          ErrorCode = 1202
 	E_SERVICE_SLOW_CLIENT                        ErrorCode = 1203
 	E_SERVICE_LOW_MEMORY                         ErrorCode = 1204
+	E_SERVICE_KILL_RULE                          ErrorCode = 1205
 	E_ADMIN_CONNECTION                           ErrorCode = 2000
 	E_ADMIN_START                                ErrorCode = 2001
 	E_ADMIN_INVALIDURL                           ErrorCode = 2010
@@ -295,6 +296,7 @@ const (
 	E_UPDATE_ALIAS_METADATA                      ErrorCode = 5110
 	E_UPDATE_MISSING_CLONE                       ErrorCode = 5120
 	E_UPDATE_INVALID_FIELD                       ErrorCode = 5130
+	E_DOCUMENT_VALIDATION                        ErrorCode = 5135
 	E_UNNEST_INVALID_POSITION                    ErrorCode = 5180
 	E_SCAN_VECTOR_TOO_MANY_SCANNED_BUCKETS       ErrorCode = 5190
 	_RETIRED_5200                                          = 5200
@@ -391,6 +393,10 @@ const (
 	E_INFER_NEXT_DOCUMENT                        ErrorCode = 7024
 	W_INFER_INVALID_FLAGS                        ErrorCode = 7025
 	W_INFER_INVALID_FLAG                         ErrorCode = 7026
+	E_INFER_INVALID_FORMAT                       ErrorCode = 7027
+	W_INFER_SCHEMA_DRIFT                         ErrorCode = 7028
+	E_INFER_BASELINE                             ErrorCode = 7029
+	E_INFER_BASELINE_NOT_FOUND                   ErrorCode = 7030
 	E_MIGRATION                                  ErrorCode = 7200
 	E_MIGRATION_INTERNAL                         ErrorCode = 7201
 	E_BACKUP_NOT_POSSIBLE                        ErrorCode = 7300
@@ -441,6 +447,10 @@ const (
 	E_VECTOR_FUNC_INVALID_FIELD                  ErrorCode = 10511
 	E_IS_VECTOR_INVALID_DIMENSION                ErrorCode = 10512
 	E_IS_VECTOR_INVALID_ARG                      ErrorCode = 10513
+	E_JSON_PATH_INVALID                          ErrorCode = 10514
+	E_JSON_PATCH_INVALID                         ErrorCode = 10515
+	E_JSON_PATCH_FAILED                          ErrorCode = 10516
+	E_JSON_SCHEMA_INVALID                        ErrorCode = 10517
 	E_SYSTEM_DATASTORE                           ErrorCode = 11000
 	E_SYSTEM_KEYSPACE_NOT_FOUND                  ErrorCode = 11002
 	E_SYSTEM_NOT_IMPLEMENTED                     ErrorCode = 11003
@@ -556,6 +566,8 @@ const (
 	E_SCOPES_NOT_SUPPORTED                       ErrorCode = 16022
 	E_STAT_UPDATER_NOT_FOUND                     ErrorCode = 16030
 	E_NO_FLUSH                                   ErrorCode = 16040
+	E_NO_TRUNCATE                                ErrorCode = 16041
+	E_TRUNCATE                                   ErrorCode = 16042
 	E_SS_IDX_NOT_FOUND                           ErrorCode = 16050
 	E_SS_NOT_SUPPORTED                           ErrorCode = 16051
 	E_SS_INACTIVE                                ErrorCode = 16052
@@ -640,6 +652,48 @@ const (
 	E_SEQUENCE_NAME_PARTS                        ErrorCode = 19116
 	E_SEQUENCE_DROP_ALL                          ErrorCode = 19117
 	W_SEQUENCE_NO_PREV_VALUE                     ErrorCode = 19118
+	E_VIEW_NOT_ENABLED                           ErrorCode = 19150
+	E_VIEW_CREATE                                ErrorCode = 19151
+	E_VIEW_DROP                                  ErrorCode = 19152
+	E_VIEW_DROP_ALL                              ErrorCode = 19153
+	E_VIEW_NOT_FOUND                             ErrorCode = 19154
+	E_VIEW_ALREADY_EXISTS                        ErrorCode = 19155
+	E_VIEW_INVALID_NAME                          ErrorCode = 19156
+	E_VIEW_INVALID_DEFINITION                    ErrorCode = 19157
+	E_VIEW_RECURSIVE                             ErrorCode = 19158
+	E_VIEW_USE_KEYS_INDEX                        ErrorCode = 19159
+	E_VIEW_NOT_MATERIALIZED                      ErrorCode = 19160
+	E_VIEW_REFRESH                               ErrorCode = 19161
+	E_VIEW_NOT_INCREMENTAL                       ErrorCode = 19162
+	E_VIEW_OPTION                                ErrorCode = 19163
+	E_VIEW_REFRESH_RUNNING                       ErrorCode = 19164
+	E_TRIGGER_NOT_ENABLED                        ErrorCode = 19170
+	E_TRIGGER_CREATE                             ErrorCode = 19171
+	E_TRIGGER_DROP                               ErrorCode = 19172
+	E_TRIGGER_DROP_ALL                           ErrorCode = 19173
+	E_TRIGGER_NOT_FOUND                          ErrorCode = 19174
+	E_TRIGGER_ALREADY_EXISTS                     ErrorCode = 19175
+	E_TRIGGER_INVALID_KEYSPACE                   ErrorCode = 19176
+	E_TRIGGER_INVALID_DEFINITION                 ErrorCode = 19177
+	E_TRIGGER_EXECUTION                          ErrorCode = 19178
+	E_TRIGGER_REJECTED                           ErrorCode = 19179
+	E_SUBSCRIBE_STATEMENT                        ErrorCode = 19180
+	E_SUBSCRIBE_NOT_SUPPORTED                    ErrorCode = 19181
+	E_SUBSCRIBE_CURSOR                           ErrorCode = 19182
+	E_GAPFILL_GROUP                              ErrorCode = 19183
+	E_GAPFILL_RANGE                              ErrorCode = 19184
+	E_ASOF_JOIN_ONCLAUSE                         ErrorCode = 19185
+	E_JOB_CREATE                                 ErrorCode = 19186
+	E_JOB_ALTER                                  ErrorCode = 19187
+	E_JOB_DROP                                   ErrorCode = 19188
+	E_JOB_NOT_FOUND                              ErrorCode = 19189
+	E_JOB_ALREADY_EXISTS                         ErrorCode = 19190
+	E_JOB_INVALID_SCHEDULE                       ErrorCode = 19191
+	E_JOB_INVALID_OPTION                         ErrorCode = 19192
+	E_JOB_INVALID_DEFINITION                     ErrorCode = 19193
+	E_JOB_EXECUTION                              ErrorCode = 19194
+	E_JOB_TIMEOUT                                ErrorCode = 19195
+	E_TRIGGER_UNSUPPORTED                        ErrorCode = 19196
 	E_NL_CREATE_SESSIONS_REQ                     ErrorCode = 19200
 	E_NL_SEND_SESSIONS_REQ                       ErrorCode = 19201
 	E_NL_SESSIONS_AUTH                           ErrorCode = 19202
@@ -703,6 +757,16 @@ const (
 	E_ENCRYPTION_KEY_INFO_NOT_FOUND              ErrorCode = 19304
 	E_ENCRYPTION_PRIME                           ErrorCode = 19305
 	E_ENCRYPTION                                 ErrorCode = 19306
+	E_POLICY_NOT_ENABLED                         ErrorCode = 19310
+	E_POLICY_CREATE                              ErrorCode = 19311
+	E_POLICY_DROP                                ErrorCode = 19312
+	E_POLICY_DROP_ALL                            ErrorCode = 19313
+	E_POLICY_NOT_FOUND                           ErrorCode = 19314
+	E_POLICY_ALREADY_EXISTS                      ErrorCode = 19315
+	E_POLICY_INVALID_KEYSPACE                    ErrorCode = 19316
+	E_POLICY_INVALID_DEFINITION                  ErrorCode = 19317
+	E_POLICY_UNSUPPORTED                         ErrorCode = 19318
+	E_POLICY_MASKED_REFERENCE                    ErrorCode = 19319
 	E_AUS_NOT_SUPPORTED                          ErrorCode = 20000
 	E_AUS_NOT_INITIALIZED                        ErrorCode = 20001
 	E_AUS_STORAGE                                ErrorCode = 20002
@@ -81,3 +81,13 @@ func NewNoFlushError(k string) Error {
 	return &err{level: EXCEPTION, ICode: E_NO_FLUSH, IKey: "datastore.other.flush_disabled",
 		InternalMsg: "Keyspace does not support flush: " + k, InternalCaller: CallerN(1)}
 }
+
+func NewNoTruncateError(k string) Error {
+	return &err{level: EXCEPTION, ICode: E_NO_TRUNCATE, IKey: "datastore.other.truncate_not_supported",
+		InternalMsg: "Keyspace does not support truncate: " + k, InternalCaller: CallerN(1)}
+}
+
+func NewTruncateError(e error, k string) Error {
+	return &err{level: EXCEPTION, ICode: E_TRUNCATE, IKey: "datastore.other.truncate", ICause: e,
+		InternalMsg: "Truncate of " + k + " failed", InternalCaller: CallerN(1)}
+}
@@ -0,0 +1,50 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package errors
+
+import (
+	"fmt"
+)
+
+var _policy = map[ErrorCode][2]string{
+	E_POLICY_NOT_ENABLED:        {"not_enabled", "Row-level security policies are not enabled for '%v'"},
+	E_POLICY_CREATE:             {"create", "Create failed for policy '%v'"},
+	E_POLICY_DROP:               {"drop", "Drop failed for policy '%v'"},
+	E_POLICY_DROP_ALL:           {"drop_all", "Drop failed for policies '%v'"},
+	E_POLICY_NOT_FOUND:          {"not_found", "Policy '%v' not found"},
+	E_POLICY_ALREADY_EXISTS:     {"duplicate", "Policy '%v' already exists"},
+	E_POLICY_INVALID_KEYSPACE:   {"invalid_keyspace", "Policies are not supported on '%v'"},
+	E_POLICY_INVALID_DEFINITION: {"invalid_definition", "Invalid definition for policy '%v'"},
+	E_POLICY_UNSUPPORTED:        {"unsupported", "Policies on '%v' do not support %v"},
+	E_POLICY_MASKED_REFERENCE:   {"masked_reference", "'%v' holds data masked by a policy, and can only be used as a whole in the projection"},
+}
+
+func NewPolicyError(code ErrorCode, args ...interface{}) Error {
+	e := &err{level: EXCEPTION, ICode: code, InternalCaller: CallerN(1),
+		IKey: "datastore.policy." + _policy[code][0], InternalMsg: _policy[code][1]}
+	var fmtArgs []interface{}
+	for _, a := range args {
+		switch a := a.(type) {
+		case string:
+			fmtArgs = append(fmtArgs, a)
+		case Error:
+			e.cause = a
+		case error:
+			e.cause = a
+		case nil:
+			// ignore
+		default:
+			panic(fmt.Sprintf("invalid argument (%T) to NewPolicyError", a))
+		}
+	}
+	if len(fmtArgs) > 0 {
+		e.InternalMsg = fmt.Sprintf(e.InternalMsg, fmtArgs...)
+	}
+	return e
+}
@@ -0,0 +1,51 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package errors
+
+import (
+	"fmt"
+)
+
+var _trigger = map[ErrorCode][2]string{
+	E_TRIGGER_NOT_ENABLED:        {"not_enabled", "Trigger support is not enabled for '%v'"},
+	E_TRIGGER_CREATE:             {"create", "Create failed for trigger '%v'"},
+	E_TRIGGER_DROP:               {"drop", "Drop failed for trigger '%v'"},
+	E_TRIGGER_DROP_ALL:           {"drop_all", "Drop failed for triggers '%v'"},
+	E_TRIGGER_NOT_FOUND:          {"not_found", "Trigger '%v' not found"},
+	E_TRIGGER_ALREADY_EXISTS:     {"duplicate", "Trigger '%v' already exists"},
+	E_TRIGGER_INVALID_KEYSPACE:   {"invalid_keyspace", "Triggers are not supported on '%v'"},
+	E_TRIGGER_INVALID_DEFINITION: {"invalid_definition", "Invalid definition for trigger '%v'"},
+	E_TRIGGER_EXECUTION:          {"execution", "Trigger '%v' failed for document '%v'"},
+	E_TRIGGER_REJECTED:           {"rejected", "Trigger '%v' rejected document '%v'"},
+	E_TRIGGER_UNSUPPORTED:        {"unsupported", "Triggers on '%v' do not support %v"},
+}
+
+func NewTriggerError(code ErrorCode, args ...interface{}) Error {
+	e := &err{level: EXCEPTION, ICode: code, InternalCaller: CallerN(1),
+		IKey: "datastore.trigger." + _trigger[code][0], InternalMsg: _trigger[code][1]}
+	var fmtArgs []interface{}
+	for _, a := range args {
+		switch a := a.(type) {
+		case string:
+			fmtArgs = append(fmtArgs, a)
+		case Error:
+			e.cause = a
+		case error:
+			e.cause = a
+		case nil:
+			// ignore
+		default:
+			panic(fmt.Sprintf("invalid argument (%T) to NewTriggerError", a))
+		}
+	}
+	if len(fmtArgs) > 0 {
+		e.InternalMsg = fmt.Sprintf(e.InternalMsg, fmtArgs...)
+	}
+	return e
+}
@@ -0,0 +1,55 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package errors
+
+import (
+	"fmt"
+)
+
+var _view = map[ErrorCode][2]string{
+	E_VIEW_NOT_ENABLED:        {"not_enabled", "View support is not enabled for '%v'"},
+	E_VIEW_CREATE:             {"create", "Create failed for view '%v'"},
+	E_VIEW_DROP:               {"drop", "Drop failed for view '%v'"},
+	E_VIEW_DROP_ALL:           {"drop_all", "Drop failed for views '%v'"},
+	E_VIEW_NOT_FOUND:          {"not_found", "View '%v' not found"},
+	E_VIEW_ALREADY_EXISTS:     {"duplicate", "View '%v' already exists"},
+	E_VIEW_INVALID_NAME:       {"invalid_name", "Invalid view name '%v'"},
+	E_VIEW_INVALID_DEFINITION: {"invalid_definition", "Invalid definition for view '%v'"},
+	E_VIEW_RECURSIVE:          {"recursive", "View '%v' references itself"},
+	E_VIEW_USE_KEYS_INDEX:     {"use_keys_index", "USE KEYS and USE INDEX are not supported for view '%v'"},
+	E_VIEW_NOT_MATERIALIZED:   {"not_materialized", "View '%v' is not a materialized view"},
+	E_VIEW_REFRESH:            {"refresh", "Refresh failed for materialized view '%v'"},
+	E_VIEW_NOT_INCREMENTAL:    {"not_incremental", "Materialized view '%v' cannot be refreshed incrementally: %v"},
+	E_VIEW_OPTION:             {"option", "Invalid option '%v' for materialized view '%v'"},
+	E_VIEW_REFRESH_RUNNING:    {"refresh_running", "Materialized view '%v' is already being refreshed"},
+}
+
+func NewViewError(code ErrorCode, args ...interface{}) Error {
+	e := &err{level: EXCEPTION, ICode: code, InternalCaller: CallerN(1),
+		IKey: "datastore.view." + _view[code][0], InternalMsg: _view[code][1]}
+	var fmtArgs []interface{}
+	for _, a := range args {
+		switch a := a.(type) {
+		case string:
+			fmtArgs = append(fmtArgs, a)
+		case Error:
+			e.cause = a
+		case error:
+			e.cause = a
+		case nil:
+			// ignore
+		default:
+			panic(fmt.Sprintf("invalid argument (%T) to NewViewError", a))
+		}
+	}
+	if len(fmtArgs) > 0 {
+		e.InternalMsg = fmt.Sprintf(e.InternalMsg, fmtArgs...)
+	}
+	return e
+}
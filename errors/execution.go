@@ -290,6 +290,16 @@ func NewUpdateInvalidField(key string, field string, unset bool) Error {
 		InternalMsg: "Invalid field update.", cause: c, InternalCaller: CallerN(1)}
 }
 
+func NewDocumentValidationError(keyspace string, key string, failures []interface{}) Error {
+	c := make(map[string]interface{})
+	c["keyspace"] = keyspace
+	c["key"] = key
+	c["failures"] = failures
+	return &err{level: EXCEPTION, ICode: E_DOCUMENT_VALIDATION, IKey: "execution.document_validation",
+		InternalMsg: fmt.Sprintf("Document (document key '%s') does not conform to the validation rule of %s", key, keyspace),
+		cause:       c, InternalCaller: CallerN(1)}
+}
+
 func NewUnnestInvalidPosition(pos interface{}) Error {
 	return &err{level: EXCEPTION, ICode: E_UNNEST_INVALID_POSITION, IKey: "execution.unnest_invalid_position",
 		InternalMsg: fmt.Sprintf("Invalid UNNEST position of type %T.", pos), InternalCaller: CallerN(1)}
@@ -669,6 +679,12 @@ func NewLowMemory(threshold int) Error {
 		InternalMsg: fmt.Sprintf("request halted: free memory below %v", threshold) + "% " + "of available memory", InternalCaller: CallerN(1)}
 }
 
+func NewKillRuleError(rule, condition string) Error {
+	return &err{level: EXCEPTION, ICode: E_SERVICE_KILL_RULE, IKey: "service.request.killed",
+		InternalMsg:    fmt.Sprintf("request terminated by kill rule '%s': %s", rule, condition),
+		InternalCaller: CallerN(1)}
+}
+
 func NewNilEvaluateParamError(param string) Error {
 	return &err{level: EXCEPTION, ICode: E_NIL_EVALUATE_PARAM, IKey: "execution.evaluate.nil.param",
 		InternalMsg:    fmt.Sprintf("nil '%s' parameter for evaluation", param),
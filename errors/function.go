@@ -218,3 +218,26 @@ func NewIsVectorInvalidArg(msg string) Error {
 		InternalMsg:    fmt.Sprintf("IsVector() function has invalid argument: %v", msg),
 		InternalCaller: CallerN(1)}
 }
+
+func NewJSONPathInvalidError(path string, reason error) Error {
+	return &err{level: EXCEPTION, ICode: E_JSON_PATH_INVALID, IKey: "function.json_path.invalid", ICause: reason,
+		InternalMsg:    fmt.Sprintf("Invalid JSONPath '%s'", path),
+		InternalCaller: CallerN(1)}
+}
+
+func NewJSONPatchInvalidError(index int, reason string) Error {
+	return &err{level: EXCEPTION, ICode: E_JSON_PATCH_INVALID, IKey: "function.json_patch.invalid",
+		InternalMsg:    fmt.Sprintf("Invalid JSON Patch operation %d: %s", index, reason),
+		InternalCaller: CallerN(1)}
+}
+
+func NewJSONPatchFailedError(index int, op string, path string, reason string) Error {
+	return &err{level: EXCEPTION, ICode: E_JSON_PATCH_FAILED, IKey: "function.json_patch.failed",
+		InternalMsg:    fmt.Sprintf("JSON Patch operation %d (%s '%s') failed: %s", index, op, path, reason),
+		InternalCaller: CallerN(1)}
+}
+
+func NewJSONSchemaInvalidError(reason error) Error {
+	return &err{level: EXCEPTION, ICode: E_JSON_SCHEMA_INVALID, IKey: "function.json_schema.invalid", ICause: reason,
+		InternalMsg: "Invalid JSON schema", InternalCaller: CallerN(1)}
+}
@@ -0,0 +1,23 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package errors
+
+import (
+	"fmt"
+)
+
+func NewGapFillGroupError(reason string) Error {
+	return &err{level: EXCEPTION, ICode: E_GAPFILL_GROUP, IKey: "plan.gapfill.group",
+		InternalMsg: fmt.Sprintf("GROUP BY GAPFILL: %s", reason), InternalCaller: CallerN(1)}
+}
+
+func NewGapFillRangeError(reason string) Error {
+	return &err{level: EXCEPTION, ICode: E_GAPFILL_RANGE, IKey: "execution.gapfill.range",
+		InternalMsg: fmt.Sprintf("GAPFILL: %s", reason), InternalCaller: CallerN(1)}
+}
@@ -198,3 +198,39 @@ func NewInvalidFlagWarning(e string) Error {
 	return &err{level: WARNING, ICode: W_INFER_INVALID_FLAG, IKey: "infer.invalid_flag", cause: c,
 		InternalMsg: "'flags' array element '" + e + "' is invalid", InternalCaller: CallerN(1)}
 }
+
+func NewInferInvalidFormat(f string) Error {
+	c := make(map[string]interface{})
+	c["format"] = f
+	return &err{level: EXCEPTION, ICode: E_INFER_INVALID_FORMAT, IKey: "infer.invalid_format", cause: c,
+		InternalMsg:    fmt.Sprintf("Invalid format '%s'; valid formats are 'jsonschema', 'avro', 'typescript' and 'go'", f),
+		InternalCaller: CallerN(1)}
+}
+
+func NewInferSchemaDrift(keyspace string, baseline string, changes int) Error {
+	c := make(map[string]interface{})
+	c["keyspace"] = keyspace
+	c["baseline"] = baseline
+	c["changes"] = changes
+	return &err{level: WARNING, ICode: W_INFER_SCHEMA_DRIFT, IKey: "infer.schema_drift", cause: c,
+		InternalMsg:    fmt.Sprintf("Schema of %s has drifted from baseline '%s' (%d changes)", keyspace, baseline, changes),
+		InternalCaller: CallerN(1)}
+}
+
+func NewInferBaselineError(op string, baseline string, e error) Error {
+	c := make(map[string]interface{})
+	c["operation"] = op
+	c["baseline"] = baseline
+	if e != nil {
+		c["error"] = e
+	}
+	return &err{level: EXCEPTION, ICode: E_INFER_BASELINE, IKey: "infer.baseline." + op, cause: c,
+		InternalMsg: fmt.Sprintf("Unable to %s baseline '%s'", op, baseline), InternalCaller: CallerN(1)}
+}
+
+func NewInferBaselineNotFound(baseline string) Error {
+	c := make(map[string]interface{})
+	c["baseline"] = baseline
+	return &err{level: EXCEPTION, ICode: E_INFER_BASELINE_NOT_FOUND, IKey: "infer.baseline.not_found", cause: c,
+		InternalMsg: fmt.Sprintf("Baseline '%s' not found", baseline), InternalCaller: CallerN(1)}
+}
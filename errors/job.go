@@ -0,0 +1,50 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package errors
+
+import (
+	"fmt"
+)
+
+var _job = map[ErrorCode][2]string{
+	E_JOB_CREATE:             {"create", "Create failed for job '%v'"},
+	E_JOB_ALTER:              {"alter", "Alter failed for job '%v'"},
+	E_JOB_DROP:               {"drop", "Drop failed for job '%v'"},
+	E_JOB_NOT_FOUND:          {"not_found", "Job '%v' not found"},
+	E_JOB_ALREADY_EXISTS:     {"duplicate", "Job '%v' already exists"},
+	E_JOB_INVALID_SCHEDULE:   {"invalid_schedule", "Invalid schedule '%v' for job '%v'"},
+	E_JOB_INVALID_OPTION:     {"invalid_option", "Invalid option '%v' for job '%v'"},
+	E_JOB_INVALID_DEFINITION: {"invalid_definition", "Invalid definition for job '%v'"},
+	E_JOB_EXECUTION:          {"execution", "Job '%v' failed"},
+	E_JOB_TIMEOUT:            {"timeout", "Job '%v' exceeded its timeout of %v"},
+}
+
+func NewJobError(code ErrorCode, args ...interface{}) Error {
+	e := &err{level: EXCEPTION, ICode: code, InternalCaller: CallerN(1),
+		IKey: "job." + _job[code][0], InternalMsg: _job[code][1]}
+	var fmtArgs []interface{}
+	for _, a := range args {
+		switch a := a.(type) {
+		case string:
+			fmtArgs = append(fmtArgs, a)
+		case Error:
+			e.cause = a
+		case error:
+			e.cause = a
+		case nil:
+			// ignore
+		default:
+			panic(fmt.Sprintf("invalid argument (%T) to NewJobError", a))
+		}
+	}
+	if len(fmtArgs) > 0 {
+		e.InternalMsg = fmt.Sprintf(e.InternalMsg, fmtArgs...)
+	}
+	return e
+}
@@ -1201,6 +1201,21 @@ var errData = []ErrData{
 			"Server",
 		},
 	},
+	{
+		Code:        E_SERVICE_KILL_RULE, // 1205
+		symbol:      "E_SERVICE_KILL_RULE",
+		Description: "request terminated by kill rule '«rule»': «condition»",
+		Reason: []string{
+			"The request matched the condition of a kill rule in system:settings while it was running.",
+		},
+		Action: []string{
+			"Change the request so that it stays within the limits the kill rule sets, or ask an administrator to change the rule.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
 	{
 		Code:        E_ADMIN_CONNECTION, // 2000
 		symbol:      "E_ADMIN_CONNECTION",
@@ -3958,6 +3973,23 @@ var errData = []ErrData{
 			"Server",
 		},
 	},
+	{
+		Code:        E_DOCUMENT_VALIDATION, // 5135
+		symbol:      "E_DOCUMENT_VALIDATION",
+		Description: "Document (document key «key») does not conform to the validation rule of «keyspace»",
+		Reason: []string{
+			"The collection has a validation rule, set with ALTER COLLECTION ... WITH {\"validation\": schema}, and the" +
+				" document written by an INSERT, UPSERT, UPDATE or MERGE statement does not conform to the JSON schema.",
+			"The cause of the error lists the JSON Pointers of the non-conforming values and the failing schema keywords.",
+		},
+		Action: []string{
+			"Correct the document, or revise the validation rule of the collection.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
 	{
 		Code:        E_UNNEST_INVALID_POSITION, // 5180
 		symbol:      "E_UNNEST_INVALID_POSITION",
@@ -5299,6 +5331,72 @@ var errData = []ErrData{
 			"Server",
 		},
 	},
+	{
+		Code:        E_INFER_INVALID_FORMAT, // 7027
+		symbol:      "E_INFER_INVALID_FORMAT",
+		Description: "Invalid format «format»; valid formats are 'jsonschema', 'avro', 'typescript' and 'go'",
+		Reason: []string{
+			"The INFER statement's ˝format˝ option was passed a value that is not a string or is not a supported output format.",
+		},
+		Action: []string{
+			"Revise the statement and provide one of the supported formats, or omit the option for the default schema document.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        W_INFER_SCHEMA_DRIFT, // 7028
+		symbol:      "W_INFER_SCHEMA_DRIFT",
+		Description: "Schema of «keyspace» has drifted from baseline «baseline» («changes» changes)",
+		Reason: []string{
+			"An INFER statement with the ˝compare_to˝ option found fields added or removed, types changed or " +
+				"significant shifts in the presence of fields when compared to the baseline schema.",
+		},
+		Action: []string{
+			"Review the drift report returned by the statement. If the changes are expected, save a new baseline " +
+				"with the ˝save_baseline˝ option.",
+		},
+		IsUser:    YES,
+		IsWarning: true,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_INFER_BASELINE, // 7029
+		symbol:      "E_INFER_BASELINE",
+		Description: "Unable to «operation» baseline «baseline»",
+		Reason: []string{
+			"The baseline schema could not be saved to or loaded from the bucket's system collection, or the " +
+				"operation requires a keyspace.",
+			"The baseline passed as the ˝compare_to˝ option is not a schema returned by INFER.",
+		},
+		Action: []string{
+			"Refer to the cause for further information. Named baselines are only available when inferring " +
+				"the schema of a keyspace in a bucket with a system collection.",
+		},
+		IsUser: MAYBE,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_INFER_BASELINE_NOT_FOUND, // 7030
+		symbol:      "E_INFER_BASELINE_NOT_FOUND",
+		Description: "Baseline «baseline» not found",
+		Reason: []string{
+			"The baseline named by the INFER statement's ˝compare_to˝ option has not been saved in the bucket.",
+		},
+		Action: []string{
+			"Save a baseline with the ˝save_baseline˝ option first, or correct the name.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
 	{
 		Code:        E_MIGRATION, // 7200
 		symbol:      "E_MIGRATION",
@@ -5864,6 +5962,68 @@ var errData = []ErrData{
 			"Server",
 		},
 	},
+	{
+		Code:        E_JSON_PATH_INVALID, // 10514
+		symbol:      "E_JSON_PATH_INVALID",
+		Description: "Invalid JSONPath '<<path>>'",
+		Reason: []string{
+			"The JSONPath query passed to JSON_PATH_QUERY() does not conform to RFC 9535.",
+		},
+		Action: []string{
+			"Correct the JSONPath query. The cause of the error gives the position of the problem.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JSON_PATCH_INVALID, // 10515
+		symbol:      "E_JSON_PATCH_INVALID",
+		Description: "Invalid JSON Patch operation <<index>>: <<reason>>",
+		Reason: []string{
+			"The patch passed to JSON_PATCH() is not a well formed RFC 6902 document.",
+		},
+		Action: []string{
+			"Ensure the patch is an array of objects, each with a valid \"op\" and \"path\" and the members that the operation requires.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JSON_PATCH_FAILED, // 10516
+		symbol:      "E_JSON_PATCH_FAILED",
+		Description: "JSON Patch operation <<index>> (<<op>> '<<path>>') failed: <<reason>>",
+		Reason: []string{
+			"A JSON_PATCH() operation could not be applied to the document, for example because a \"test\" operation did not match or a path does not exist.",
+			"A patch is applied atomically: when any operation fails, the document is not modified and the statement fails.",
+		},
+		Action: []string{
+			"Recompute the patch against the current version of the document.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JSON_SCHEMA_INVALID, // 10517
+		symbol:      "E_JSON_SCHEMA_INVALID",
+		Description: "Invalid JSON schema",
+		Reason: []string{
+			"The schema passed to JSON_SCHEMA_VALID() or JSON_SCHEMA_ERRORS(), or set as the validation rule of a collection, is" +
+				" not a valid JSON schema, or uses an unsupported feature such as a remote $ref.",
+		},
+		Action: []string{
+			"Correct the schema. The cause of the error gives the location of the problem within the schema.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
 	{
 		Code:        E_SYSTEM_DATASTORE, // 11000
 		symbol:      "E_SYSTEM_DATASTORE",
@@ -7182,6 +7342,31 @@ var errData = []ErrData{
 			"Server",
 		},
 	},
+	{
+		Code:        E_NO_TRUNCATE, // 16041
+		symbol:      "E_NO_TRUNCATE",
+		Description: "Keyspace does not support truncate: «keyspace»",
+		Reason: []string{
+			"The keyspace is a system keyspace, an external collection, or belongs to a datastore that cannot remove all of its documents.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRUNCATE, // 16042
+		symbol:      "E_TRUNCATE",
+		Description: "Truncate of «keyspace» failed",
+		Reason: []string{
+			"Documents of the keyspace could not be listed or removed. The keyspace may have been partially emptied.",
+		},
+		Action: []string{
+			"Check the cause in the error, and run TRUNCATE again.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
 	{
 		Code:        E_SS_IDX_NOT_FOUND, // 16050
 		symbol:      "E_SS_IDX_NOT_FOUND",
@@ -8220,199 +8405,830 @@ var errData = []ErrData{
 		},
 	},
 	{
-		Code:        E_NL_CREATE_SESSIONS_REQ, // 19200,
-		symbol:      "E_NL_CREATE_SESSIONS_REQ",
-		Description: "Failed to create a new request to «sessions url»",
+		Code:        E_VIEW_NOT_ENABLED, // 19150
+		symbol:      "E_VIEW_NOT_ENABLED",
+		Description: "View support is not enabled for «bucket»",
+		Reason: []string{
+			"Views are stored in the bucket's system collection, which does not exist for the noted bucket.",
+		},
+		Action: []string{
+			"Ensure the bucket supports a system collection before creating views in it.",
+		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_SEND_SESSIONS_REQ, // 19201
-		symbol:      "E_NL_SEND_SESSIONS_REQ",
-		Description: "Failed to send the request to «sessions api» to get JWT",
+		Code:        E_VIEW_CREATE, // 19151
+		symbol:      "E_VIEW_CREATE",
+		Description: "Create failed for view «name»",
+		Reason: []string{
+			"The view definition could not be stored.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details.",
+		},
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_SESSIONS_AUTH, // 19202
-		symbol:      "E_NL_SESSIONS_AUTH",
-		Description: "Authorization failed when establishing natural language session",
-		IsUser:      YES,
+		Code:        E_VIEW_DROP, // 19152
+		symbol:      "E_VIEW_DROP",
+		Description: "Drop failed for view «name»",
+		Reason: []string{
+			"The view definition could not be removed.",
+		},
 		Action: []string{
-			"Verify the natural language processing credentials supplied in the request.",
-			"Create a Couchbase cloud account if necessary.",
+			"Refer to the underlying cause for details.",
 		},
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_SESSIONS_RESP_READ, // 19203
-		symbol:      "E_NL_SESSIONS_RESP_READ",
-		Description: "Error reading the response from «sessions api»",
+		Code:        E_VIEW_DROP_ALL, // 19153
+		symbol:      "E_VIEW_DROP_ALL",
+		Description: "Drop failed for views «views»",
+		Reason: []string{
+			"The clean-up operation for views belonging to a scope that has been dropped encountered the noted error.",
+		},
+		Action: []string{
+			"Contact support.",
+		},
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_SESSIONS_RESP_UNMARSHAL, // 19204
-		symbol:      "E_NL_SESSIONS_RESP_UNMARSHAL",
-		Description: "Unmarshalling response from «sessions api» failed: ",
+		Code:        E_VIEW_NOT_FOUND, // 19154
+		symbol:      "E_VIEW_NOT_FOUND",
+		Description: "View «name» not found",
+		Reason: []string{
+			"The statement referenced a view that does not exist.",
+		},
+		Action: []string{
+			"Check the view name and the query_context.",
+		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_SESSIONS_PARSE_EXPIRE_TIME, // 19205
-		symbol:      "E_NL_SESSIONS_PARSE_EXPIRE_TIME",
-		Description: "Error parsing \"expiresAt\": «expiresAt»",
+		Code:        E_VIEW_ALREADY_EXISTS, // 19155
+		symbol:      "E_VIEW_ALREADY_EXISTS",
+		Description: "View «name» already exists",
+		Reason: []string{
+			"A view or collection with the same name already exists in the scope.",
+		},
+		Action: []string{
+			"Use CREATE OR REPLACE VIEW to replace an existing view, or choose a different name.",
+		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_PROMPT_SCHEMA_MARSHAL, // 19206
-		symbol:      "E_NL_PROMPT_SCHEMA_MARSHAL",
-		Description: "Error marshalling schema information for prompt:",
+		Code:        E_VIEW_INVALID_NAME, // 19156
+		symbol:      "E_VIEW_INVALID_NAME",
+		Description: "Invalid view name «name»",
+		Reason: []string{
+			"Views must be created in a named scope of a bucket.",
+		},
+		Action: []string{
+			"Qualify the view name with a bucket and scope, or set the query_context.",
+		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_CHATCOMPLETIONS_PROMPT_MARSHAL, // 19207
-		symbol:      "E_NL_CHATCOMPLETIONS_PROMPT_MARSHAL",
-		Description: "Error marshalling prompt for chat completions API request",
+		Code:        E_VIEW_INVALID_DEFINITION, // 19157
+		symbol:      "E_VIEW_INVALID_DEFINITION",
+		Description: "Invalid definition for view «name»",
+		Reason: []string{
+			"The stored definition of the view could not be parsed as a SELECT statement, or the definition uses parameters.",
+		},
+		Action: []string{
+			"Recreate the view with CREATE OR REPLACE VIEW.",
+		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_CHATCOMPLETIONS_REQ_FAILED, // 19209
-		symbol:      "E_NL_CHATCOMPLETIONS_REQ_FAILED",
-		Description: "Chat completions request failed with status «http-status-code»",
-		IsUser:      YES,
+		Code:        E_VIEW_RECURSIVE, // 19158
+		symbol:      "E_VIEW_RECURSIVE",
+		Description: "View «name» references itself",
 		Reason: []string{
-			"Status 429: Rate limited. The natural language processing facilities are limiting the number of requests.",
-			"Status 404: Unauthorized. Authorization for natural language processing failed.",
+			"The definition of the view refers to the view itself, directly or through other views.",
 		},
 		Action: []string{
-			"Status 429: Retry later.",
-			"Status 404: Verify the credentials provided for natural language processing.",
+			"Redefine the views so that they do not form a cycle.",
 		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_CHATCOMPLETIONS_RESP_UNMARSHAL, // 19211
-		symbol:      "E_NL_CHATCOMPLETIONS_RESP_UNMARSHAL",
-		Description: "Error unmarshalling chat completions response",
+		Code:        E_VIEW_USE_KEYS_INDEX, // 19159
+		symbol:      "E_VIEW_USE_KEYS_INDEX",
+		Description: "USE KEYS and USE INDEX are not supported for view «name»",
+		Reason: []string{
+			"A view was referenced with a USE KEYS or USE INDEX clause. Views are expanded as derived tables, which have neither.",
+		},
+		Action: []string{
+			"Remove the clause, or apply it to the keyspace in the view definition.",
+		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_ERR_CHATCOMPLETIONS_RESP, // 19212
-		symbol:      "E_NL_ERR_CHATCOMPLETIONS_RESP",
-		Description: "LLM processing failed",
-		IsUser:      MAYBE,
+		Code:        E_VIEW_NOT_MATERIALIZED, // 19160
+		symbol:      "E_VIEW_NOT_MATERIALIZED",
+		Description: "View «name» is not a materialized view",
 		Reason: []string{
-			"\"natural\" parameter is not a valid prompt or doesn't prompt for a SELECT query.",
-			"The natural language statement is not a valid prompt or doesn't prompt for a SELECT query.",
+			"REFRESH MATERIALIZED VIEW or DROP MATERIALIZED VIEW was used on a view that is not materialized.",
 		},
 		Action: []string{
-			"Review the embedded \"reason\" field for more information on the failure.",
-			"Try rewording your request or revising the keyspace information provided.",
+			"Use DROP VIEW for views that are not materialized.",
 		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_MISSING_NL_PARAM, // 19213
-		symbol:      "E_NL_MISSING_NL_PARAM",
-		Description: "Natural Language request expects «param» request parameter to be set",
-		IsUser:      YES,
+		Code:        E_VIEW_REFRESH, // 19161
+		symbol:      "E_VIEW_REFRESH",
+		Description: "Refresh failed for materialized view «name»",
 		Reason: []string{
-			"\"natural_cred\", \"natural_context\" and \"natural_orgid\" parameters are required when sending a request " +
-				"using the \"natural\" parameter",
-			"The options \"cred\", \"keyspaces\" and \"orgid\" are required in the statement when the \"natural_\" parameters " +
-				"are not supplied.",
+			"The statements populating the backing collection of the view failed. The cause is included in the error.",
+		},
+		Action: []string{
+			"Correct the cause and run REFRESH MATERIALIZED VIEW again.",
 		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_FAIL_GENERATED_STMT, // 19214
-		symbol:      "E_NL_FAIL_GENERATED_STMT",
-		Description: "Statement generation failed: «failure»",
-		IsUser:      MAYBE,
+		Code:        E_VIEW_NOT_INCREMENTAL, // 19162
+		symbol:      "E_VIEW_NOT_INCREMENTAL",
+		Description: "Materialized view «name» cannot be refreshed incrementally: «reason»",
 		Reason: []string{
-			"Syntax error in generated statement.",
-			"LLM returned an empty response",
+			"Incremental refresh needs a single collection, a GROUP BY whose keys are all projected, COUNT(*), and only COUNT and SUM aggregates, with a COUNT for the argument of every SUM.",
 		},
 		Action: []string{
-			"Examine the «failure», adjust and re-submit as a direct statement execution request if possible.",
+			"Refresh the view without INCREMENTAL, or redefine it so that it can be maintained incrementally.",
 		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_CONTEXT, // 19215
-		symbol:      "E_NL_CONTEXT",
-		Description: "Error in keyspace list provided for natural language processing",
-		IsUser:      YES,
+		Code:        E_VIEW_OPTION, // 19163
+		symbol:      "E_VIEW_OPTION",
+		Description: "Invalid option «option» for materialized view «name»",
 		Reason: []string{
-			"Validation of the \"natural_context\" parameter failed for the reason specified.",
-			"Validation of the \"keyspaces\" option failed for the reason specified.",
-			"Insufficient permissions to access the keyspaces provided for natural language processing.",
-			"System keyspaces cannot be used for natural language processing.",
+			"The WITH clause of CREATE MATERIALIZED VIEW contains an unknown option or an option of the wrong type.",
 		},
 		Action: []string{
-			"Revise the \"natural_context\" parameter.",
-			"Revise the \"keyspaces\" option.",
-			"Ensure the natural language processing credentials have permissions to access the keyspaces provided.",
+			"The only option is \"rewrite\", which takes a boolean.",
 		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_PROMPT_INFER, // 19216
-		symbol:      "E_NL_PROMPT_INFER",
-		Description: "Schema inferring failed for keyspace «keyspace»",
-		IsUser:      YES,
+		Code:        E_VIEW_REFRESH_RUNNING, // 19164
+		symbol:      "E_VIEW_REFRESH_RUNNING",
+		Description: "Materialized view «name» is already being refreshed",
 		Reason: []string{
-			"A keyspace the list of keyspaces passed for natural language processing doesn't exist in the cluster.",
+			"A refresh of the view was requested on this node while another was still running.",
 		},
 		Action: []string{
-			"Ensure all keyspaces provided for natural language processing exist.",
+			"Wait for the running refresh to complete, as shown in system:tasks_cache.",
 		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:        E_NL_ORG_NOT_FOUND, // 19217
-		symbol:      "E_NL_ORG_NOT_FOUND",
-		Description: "Organization «organization» not found",
-		IsUser:      YES,
+		Code:        E_TRIGGER_NOT_ENABLED, // 19170
+		symbol:      "E_TRIGGER_NOT_ENABLED",
+		Description: "Trigger support is not enabled for «bucket»",
 		Reason: []string{
-			"The organisation specified in the \"natural_orgid\" parameter was not found by the chat completions API.",
-			"The organisation specified in the \"orgid\" option was not found by the chat completions API.",
+			"Triggers are stored in the bucket's system collection, which does not exist for the noted bucket.",
+		},
+		Action: []string{
+			"Ensure the bucket supports a system collection before creating triggers on its collections.",
 		},
+		IsUser: YES,
 		AppliesTo: []string{
 			"Server",
 		},
 	},
 	{
-		Code:   E_NL_ORG_UNAUTH, // 19218
-		symbol: "E_NL_ORG_UNAUTH",
-		Description: "Access to organisation «organization» is not authorized " +
+		Code:        E_TRIGGER_CREATE, // 19171
+		symbol:      "E_TRIGGER_CREATE",
+		Description: "Create failed for trigger «name»",
+		Reason: []string{
+			"The trigger definition could not be stored.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRIGGER_DROP, // 19172
+		symbol:      "E_TRIGGER_DROP",
+		Description: "Drop failed for trigger «name»",
+		Reason: []string{
+			"The trigger definition could not be removed.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRIGGER_DROP_ALL, // 19173
+		symbol:      "E_TRIGGER_DROP_ALL",
+		Description: "Drop failed for triggers «name»",
+		Reason: []string{
+			"The triggers of a dropped scope or collection could not all be removed.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details. Stale definitions are removed by the system collection clean-up.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRIGGER_NOT_FOUND, // 19174
+		symbol:      "E_TRIGGER_NOT_FOUND",
+		Description: "Trigger «name» not found",
+		Reason: []string{
+			"DROP TRIGGER named a trigger that is not defined on the keyspace.",
+		},
+		Action: []string{
+			"Check the name and keyspace of the trigger against system:triggers, or use DROP TRIGGER IF EXISTS.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRIGGER_ALREADY_EXISTS, // 19175
+		symbol:      "E_TRIGGER_ALREADY_EXISTS",
+		Description: "Trigger «name» already exists",
+		Reason: []string{
+			"CREATE TRIGGER named a trigger that is already defined on the keyspace.",
+		},
+		Action: []string{
+			"Use a different name, drop the existing trigger first, or use CREATE OR REPLACE TRIGGER.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRIGGER_INVALID_KEYSPACE, // 19176
+		symbol:      "E_TRIGGER_INVALID_KEYSPACE",
+		Description: "Triggers are not supported on «keyspace»",
+		Reason: []string{
+			"Triggers can only be defined on collections; system and external keyspaces are not supported.",
+		},
+		Action: []string{
+			"Define the trigger on a collection.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRIGGER_INVALID_DEFINITION, // 19177
+		symbol:      "E_TRIGGER_INVALID_DEFINITION",
+		Description: "Invalid definition for trigger «name»",
+		Reason: []string{
+			"The stored definition of the trigger could not be read or parsed.",
+		},
+		Action: []string{
+			"Drop and re-create the trigger.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRIGGER_EXECUTION, // 19178
+		symbol:      "E_TRIGGER_EXECUTION",
+		Description: "Trigger «name» failed for document «key»",
+		Reason: []string{
+			"The function executed by the trigger returned an error, which fails the statement that fired it.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRIGGER_REJECTED, // 19179
+		symbol:      "E_TRIGGER_REJECTED",
+		Description: "Trigger «name» rejected document «key»",
+		Reason: []string{
+			"The function executed by a BEFORE trigger returned FALSE for the document, which is then not written.",
+		},
+		Action: []string{
+			"None - the document was rejected by design of the trigger function.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_SUBSCRIBE_STATEMENT, // 19180
+		symbol:      "E_SUBSCRIBE_STATEMENT",
+		Description: "Statement cannot be subscribed to: «reason»",
+		Reason: []string{
+			"A continuous query must be a single SELECT over one keyspace, with at most a WHERE clause.",
+			"It cannot have joins, subqueries, grouping, ordering or a LIMIT, as each document is matched on its own.",
+		},
+		Action: []string{
+			"Rewrite the statement as a projection and a filter over the keyspace.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_SUBSCRIBE_NOT_SUPPORTED, // 19181
+		symbol:      "E_SUBSCRIBE_NOT_SUPPORTED",
+		Description: "Keyspace «name» does not support continuous queries",
+		Reason: []string{
+			"The datastore of the keyspace does not report changes to its documents.",
+		},
+		Action: []string{
+			"Poll the keyspace with a regular query instead.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_SUBSCRIBE_CURSOR, // 19182
+		symbol:      "E_SUBSCRIBE_CURSOR",
+		Description: "Cursor «cursor» is no longer available for «keyspace»",
+		Reason: []string{
+			"The changes following the cursor are no longer held by the keyspace, either because the subscriber fell too far behind or because the service restarted.",
+		},
+		Action: []string{
+			"Query the keyspace for its current state, then subscribe again without a cursor.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_GAPFILL_GROUP, // 19183
+		symbol:      "E_GAPFILL_GROUP",
+		Description: "GROUP BY GAPFILL: «reason»",
+		Reason: []string{
+			"GAPFILL requires exactly one of the GROUP BY terms to be a TIME_BUCKET() call, with an interval and origin that do not depend on the documents.",
+			"The operands of LOCF() and INTERPOLATE() are evaluated before the LETTING clause and so cannot refer to its variables.",
+		},
+		Action: []string{
+			"Revise the GROUP BY clause or the LOCF() and INTERPOLATE() calls.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_GAPFILL_RANGE, // 19184
+		symbol:      "E_GAPFILL_RANGE",
+		Description: "GAPFILL: «reason»",
+		Reason: []string{
+			"The interval of the TIME_BUCKET() group term or the GAPFILL range is not valid.",
+			"Filling the range would add more buckets to a series than are allowed.",
+		},
+		Action: []string{
+			"Use a positive interval and a range of timestamps, or a coarser interval or smaller range.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_ASOF_JOIN_ONCLAUSE, // 19185
+		symbol:      "E_ASOF_JOIN_ONCLAUSE",
+		Description: "ASOF JOIN on «alias»: «reason»",
+		Reason: []string{
+			"The ON-clause of an ASOF JOIN must be made of equality terms between the two sides and of a single " +
+				"inequality term comparing an expression of the right-hand side to one of the left-hand side.",
+		},
+		Action: []string{
+			"Write the ON-clause as equality terms AND a single >=, >, <= or < term between the times of the two sides.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_CREATE, // 19186
+		symbol:      "E_JOB_CREATE",
+		Description: "Create failed for job «name»",
+		Reason: []string{
+			"The job definition could not be stored in the query metadata collection.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_ALTER, // 19187
+		symbol:      "E_JOB_ALTER",
+		Description: "Alter failed for job «name»",
+		Reason: []string{
+			"The job definition could not be updated in the query metadata collection.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_DROP, // 19188
+		symbol:      "E_JOB_DROP",
+		Description: "Drop failed for job «name»",
+		Reason: []string{
+			"The job definition could not be removed from the query metadata collection.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_NOT_FOUND, // 19189
+		symbol:      "E_JOB_NOT_FOUND",
+		Description: "Job «name» not found",
+		Reason: []string{
+			"The statement refers to a job that does not exist.",
+		},
+		Action: []string{
+			"Check the name of the job against system:jobs, or use IF EXISTS with DROP JOB.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_ALREADY_EXISTS, // 19190
+		symbol:      "E_JOB_ALREADY_EXISTS",
+		Description: "Job «name» already exists",
+		Reason: []string{
+			"A job with the same name has already been created.",
+		},
+		Action: []string{
+			"Choose a different name, or use CREATE OR REPLACE JOB.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_INVALID_SCHEDULE, // 19191
+		symbol:      "E_JOB_INVALID_SCHEDULE",
+		Description: "Invalid schedule «schedule» for job «name»",
+		Reason: []string{
+			"The schedule is not a valid cron expression, never occurs, or names an unknown time zone.",
+		},
+		Action: []string{
+			"Give the schedule as five fields - minute, hour, day of month, month and day of week - or as one of @hourly, @daily, @weekly, @monthly or @yearly.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_INVALID_OPTION, // 19192
+		symbol:      "E_JOB_INVALID_OPTION",
+		Description: "Invalid option «option» for job «name»",
+		Reason: []string{
+			"An option of the WITH clause is not recognized or its value is not valid.",
+		},
+		Action: []string{
+			"Only timeout (a duration string), timezone (a time zone name) and enabled (a boolean) may be given.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_INVALID_DEFINITION, // 19193
+		symbol:      "E_JOB_INVALID_DEFINITION",
+		Description: "Invalid definition for job «name»",
+		Reason: []string{
+			"The statement of the job cannot be run on a schedule: transaction and job statements are not allowed.",
+		},
+		Action: []string{
+			"Run the statement directly, or wrap it in a function that the job executes.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_EXECUTION, // 19194
+		symbol:      "E_JOB_EXECUTION",
+		Description: "Job «name» failed",
+		Reason: []string{
+			"The statement of the job returned an error when it ran.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details, and to system:tasks_cache for the history of the job.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_JOB_TIMEOUT, // 19195
+		symbol:      "E_JOB_TIMEOUT",
+		Description: "Job «name» exceeded its timeout of «timeout»",
+		Reason: []string{
+			"The statement of the job did not complete within the timeout of the job and was stopped.",
+		},
+		Action: []string{
+			"Increase the timeout of the job with ALTER JOB ... WITH {\"timeout\": ...}, or make the statement cheaper.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_TRIGGER_UNSUPPORTED, // 19196
+		symbol:      "E_TRIGGER_UNSUPPORTED",
+		Description: "Triggers on «keyspace» do not support «operation»",
+		Reason: []string{
+			"The statement is a TRUNCATE of a collection with DELETE triggers, which TRUNCATE would not fire.",
+		},
+		Action: []string{
+			"Use DELETE instead of TRUNCATE, or drop the DELETE triggers first.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_CREATE_SESSIONS_REQ, // 19200,
+		symbol:      "E_NL_CREATE_SESSIONS_REQ",
+		Description: "Failed to create a new request to «sessions url»",
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_SEND_SESSIONS_REQ, // 19201
+		symbol:      "E_NL_SEND_SESSIONS_REQ",
+		Description: "Failed to send the request to «sessions api» to get JWT",
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_SESSIONS_AUTH, // 19202
+		symbol:      "E_NL_SESSIONS_AUTH",
+		Description: "Authorization failed when establishing natural language session",
+		IsUser:      YES,
+		Action: []string{
+			"Verify the natural language processing credentials supplied in the request.",
+			"Create a Couchbase cloud account if necessary.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_SESSIONS_RESP_READ, // 19203
+		symbol:      "E_NL_SESSIONS_RESP_READ",
+		Description: "Error reading the response from «sessions api»",
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_SESSIONS_RESP_UNMARSHAL, // 19204
+		symbol:      "E_NL_SESSIONS_RESP_UNMARSHAL",
+		Description: "Unmarshalling response from «sessions api» failed: ",
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_SESSIONS_PARSE_EXPIRE_TIME, // 19205
+		symbol:      "E_NL_SESSIONS_PARSE_EXPIRE_TIME",
+		Description: "Error parsing \"expiresAt\": «expiresAt»",
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_PROMPT_SCHEMA_MARSHAL, // 19206
+		symbol:      "E_NL_PROMPT_SCHEMA_MARSHAL",
+		Description: "Error marshalling schema information for prompt:",
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_CHATCOMPLETIONS_PROMPT_MARSHAL, // 19207
+		symbol:      "E_NL_CHATCOMPLETIONS_PROMPT_MARSHAL",
+		Description: "Error marshalling prompt for chat completions API request",
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_CHATCOMPLETIONS_REQ_FAILED, // 19209
+		symbol:      "E_NL_CHATCOMPLETIONS_REQ_FAILED",
+		Description: "Chat completions request failed with status «http-status-code»",
+		IsUser:      YES,
+		Reason: []string{
+			"Status 429: Rate limited. The natural language processing facilities are limiting the number of requests.",
+			"Status 404: Unauthorized. Authorization for natural language processing failed.",
+		},
+		Action: []string{
+			"Status 429: Retry later.",
+			"Status 404: Verify the credentials provided for natural language processing.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_CHATCOMPLETIONS_RESP_UNMARSHAL, // 19211
+		symbol:      "E_NL_CHATCOMPLETIONS_RESP_UNMARSHAL",
+		Description: "Error unmarshalling chat completions response",
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_ERR_CHATCOMPLETIONS_RESP, // 19212
+		symbol:      "E_NL_ERR_CHATCOMPLETIONS_RESP",
+		Description: "LLM processing failed",
+		IsUser:      MAYBE,
+		Reason: []string{
+			"\"natural\" parameter is not a valid prompt or doesn't prompt for a SELECT query.",
+			"The natural language statement is not a valid prompt or doesn't prompt for a SELECT query.",
+		},
+		Action: []string{
+			"Review the embedded \"reason\" field for more information on the failure.",
+			"Try rewording your request or revising the keyspace information provided.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_MISSING_NL_PARAM, // 19213
+		symbol:      "E_NL_MISSING_NL_PARAM",
+		Description: "Natural Language request expects «param» request parameter to be set",
+		IsUser:      YES,
+		Reason: []string{
+			"\"natural_cred\", \"natural_context\" and \"natural_orgid\" parameters are required when sending a request " +
+				"using the \"natural\" parameter",
+			"The options \"cred\", \"keyspaces\" and \"orgid\" are required in the statement when the \"natural_\" parameters " +
+				"are not supplied.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_FAIL_GENERATED_STMT, // 19214
+		symbol:      "E_NL_FAIL_GENERATED_STMT",
+		Description: "Statement generation failed: «failure»",
+		IsUser:      MAYBE,
+		Reason: []string{
+			"Syntax error in generated statement.",
+			"LLM returned an empty response",
+		},
+		Action: []string{
+			"Examine the «failure», adjust and re-submit as a direct statement execution request if possible.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_CONTEXT, // 19215
+		symbol:      "E_NL_CONTEXT",
+		Description: "Error in keyspace list provided for natural language processing",
+		IsUser:      YES,
+		Reason: []string{
+			"Validation of the \"natural_context\" parameter failed for the reason specified.",
+			"Validation of the \"keyspaces\" option failed for the reason specified.",
+			"Insufficient permissions to access the keyspaces provided for natural language processing.",
+			"System keyspaces cannot be used for natural language processing.",
+		},
+		Action: []string{
+			"Revise the \"natural_context\" parameter.",
+			"Revise the \"keyspaces\" option.",
+			"Ensure the natural language processing credentials have permissions to access the keyspaces provided.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_PROMPT_INFER, // 19216
+		symbol:      "E_NL_PROMPT_INFER",
+		Description: "Schema inferring failed for keyspace «keyspace»",
+		IsUser:      YES,
+		Reason: []string{
+			"A keyspace the list of keyspaces passed for natural language processing doesn't exist in the cluster.",
+		},
+		Action: []string{
+			"Ensure all keyspaces provided for natural language processing exist.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_NL_ORG_NOT_FOUND, // 19217
+		symbol:      "E_NL_ORG_NOT_FOUND",
+		Description: "Organization «organization» not found",
+		IsUser:      YES,
+		Reason: []string{
+			"The organisation specified in the \"natural_orgid\" parameter was not found by the chat completions API.",
+			"The organisation specified in the \"orgid\" option was not found by the chat completions API.",
+		},
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:   E_NL_ORG_UNAUTH, // 19218
+		symbol: "E_NL_ORG_UNAUTH",
+		Description: "Access to organisation «organization» is not authorized " +
 			"or collison in JWT refresh with an external client",
 		IsUser: MAYBE,
 		Reason: []string{
@@ -8994,6 +9810,159 @@ var errData = []ErrData{
 			"Server",
 		},
 	},
+	{
+		Code:        E_POLICY_NOT_ENABLED, // 19310
+		symbol:      "E_POLICY_NOT_ENABLED",
+		Description: "Row-level security policies are not enabled for «bucket»",
+		Reason: []string{
+			"Policies are stored in the bucket's system collection, which does not exist for the noted bucket.",
+		},
+		Action: []string{
+			"Create the system collection of the bucket, for instance by creating a user defined function in one of its scopes.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_POLICY_CREATE, // 19311
+		symbol:      "E_POLICY_CREATE",
+		Description: "Create failed for policy «name»",
+		Reason: []string{
+			"The policy definition could not be stored.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_POLICY_DROP, // 19312
+		symbol:      "E_POLICY_DROP",
+		Description: "Drop failed for policy «name»",
+		Reason: []string{
+			"The policy definition could not be removed.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_POLICY_DROP_ALL, // 19313
+		symbol:      "E_POLICY_DROP_ALL",
+		Description: "Drop failed for policies «name»",
+		Reason: []string{
+			"The policies of a dropped scope or collection could not all be removed.",
+		},
+		Action: []string{
+			"Refer to the underlying cause for details. Stale definitions are removed by the system collection clean-up.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_POLICY_NOT_FOUND, // 19314
+		symbol:      "E_POLICY_NOT_FOUND",
+		Description: "Policy «name» not found",
+		Reason: []string{
+			"DROP POLICY named a policy that is not defined on the keyspace.",
+		},
+		Action: []string{
+			"Check the name and keyspace of the policy against system:policies, or use DROP POLICY IF EXISTS.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_POLICY_ALREADY_EXISTS, // 19315
+		symbol:      "E_POLICY_ALREADY_EXISTS",
+		Description: "Policy «name» already exists",
+		Reason: []string{
+			"CREATE POLICY named a policy that is already defined on the keyspace.",
+		},
+		Action: []string{
+			"Use a different name, drop the existing policy first, or use CREATE OR REPLACE POLICY.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_POLICY_INVALID_KEYSPACE, // 19316
+		symbol:      "E_POLICY_INVALID_KEYSPACE",
+		Description: "Policies are not supported on «keyspace»",
+		Reason: []string{
+			"Policies can only be defined on collections; system and external keyspaces are not supported.",
+		},
+		Action: []string{
+			"Define the policy on a collection.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_POLICY_INVALID_DEFINITION, // 19317
+		symbol:      "E_POLICY_INVALID_DEFINITION",
+		Description: "Invalid definition for policy «name»",
+		Reason: []string{
+			"The predicate of the policy uses a subquery, an aggregate or a parameter, or its stored definition could not be parsed.",
+		},
+		Action: []string{
+			"Correct the predicate, or drop and re-create the policy.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_POLICY_UNSUPPORTED, // 19318
+		symbol:      "E_POLICY_UNSUPPORTED",
+		Description: "Policies on «keyspace» do not support «operation»",
+		Reason: []string{
+			"The statement reads a keyspace with row-level security policies in a way that the policy predicate cannot be applied to, such as an outer lookup join or a lookup or index nest.",
+			"The statement nests or groups the documents of a keyspace with masking policies, so that they cannot be masked.",
+			"The statement is an UPSERT into a keyspace with UPDATE policies, or a TRUNCATE of a keyspace with DELETE policies.",
+		},
+		Action: []string{
+			"Rewrite the statement using an ANSI JOIN, or without NEST or GROUP AS.",
+			"Use INSERT and UPDATE instead of UPSERT, and DELETE instead of TRUNCATE.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
+	{
+		Code:        E_POLICY_MASKED_REFERENCE, // 19319
+		symbol:      "E_POLICY_MASKED_REFERENCE",
+		Description: "«expression» holds data masked by a policy, and can only be used as a whole in the projection",
+		Reason: []string{
+			"A document, or an object holding a masked field, is used as a whole outside of the projection, or as an argument to a function or aggregate, where the masked fields could not be masked.",
+		},
+		Action: []string{
+			"Refer to the fields that are needed by name instead.",
+		},
+		IsUser: YES,
+		AppliesTo: []string{
+			"Server",
+		},
+	},
 	{
 		Code:        E_AUS_NOT_SUPPORTED, // 20000
 		symbol:      "E_AUS_NOT_SUPPORTED",
@@ -0,0 +1,28 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package errors
+
+import (
+	"fmt"
+)
+
+func NewSubscribeStatementError(reason string) Error {
+	return &err{level: EXCEPTION, ICode: E_SUBSCRIBE_STATEMENT, IKey: "service.subscribe.statement",
+		InternalMsg: fmt.Sprintf("Statement cannot be subscribed to: %s", reason), InternalCaller: CallerN(1)}
+}
+
+func NewSubscribeNotSupportedError(keyspace string) Error {
+	return &err{level: EXCEPTION, ICode: E_SUBSCRIBE_NOT_SUPPORTED, IKey: "service.subscribe.not_supported",
+		InternalMsg: fmt.Sprintf("Keyspace '%s' does not support continuous queries", keyspace), InternalCaller: CallerN(1)}
+}
+
+func NewSubscribeCursorError(cursor uint64, keyspace string) Error {
+	return &err{level: EXCEPTION, ICode: E_SUBSCRIBE_CURSOR, IKey: "service.subscribe.cursor",
+		InternalMsg: fmt.Sprintf("Cursor %d is no longer available for '%s'", cursor, keyspace), InternalCaller: CallerN(1)}
+}
@@ -9,8 +9,12 @@
 package execution
 
 import (
+	"fmt"
 	"sort"
 	"time"
+
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/util"
 )
 
 const (
@@ -20,33 +24,43 @@ const (
 	_AUTH_THRESHOLD         = time.Second
 	_IS_THRESHOLD           = 0.9
 	_MIN_TIME_FOR_REPORTING = time.Second
+	_MISESTIMATE_RATIO      = 10
+	_MISESTIMATE_COUNT      = 1000
 )
 
 func AnalyseExecution(start Operator) ([]interface{}, error) {
-	a := &execAnalyser{}
+	return (&execAnalyser{}).analyse(start)
+}
 
-	_, err := start.Accept(a)
+func (this *execAnalyser) analyse(start Operator) ([]interface{}, error) {
+	if this.planStats == nil {
+		this.planStats = make(map[plan.Operator]*planStats)
+	}
+	_, err := start.Accept(this)
 	if err != nil {
 		return nil, err
 	}
+	for op, s := range this.planStats {
+		this.checkEstimate(op, s)
+	}
 
-	if a.ioTime > a.cpuTime*_IO_THRESHOLD && a.ioTime > _MIN_TIME_FOR_REPORTING {
-		a.add("High IO time")
+	if this.ioTime > this.cpuTime*_IO_THRESHOLD && this.ioTime > _MIN_TIME_FOR_REPORTING {
+		this.add("High IO time")
 	}
-	if a.waitTime > (a.cpuTime+a.ioTime)*_WT_THRESHOLD && a.waitTime > _MIN_TIME_FOR_REPORTING {
-		a.add("High wait time")
+	if this.waitTime > (this.cpuTime+this.ioTime)*_WT_THRESHOLD && this.waitTime > _MIN_TIME_FOR_REPORTING {
+		this.add("High wait time")
 	}
-	if a.indexScanTime > time.Duration(float64(a.ioTime)*_IS_THRESHOLD) && a.indexScanTime > _MIN_TIME_FOR_REPORTING {
-		a.add("High index scan time")
+	if this.indexScanTime > time.Duration(float64(this.ioTime)*_IS_THRESHOLD) && this.indexScanTime > _MIN_TIME_FOR_REPORTING {
+		this.add("High index scan time")
 	}
 
-	if len(a.results) > 1 {
-		sort.Slice(a.results, func(i int, j int) bool {
-			return a.results[i].(string) < a.results[j].(string)
+	if len(this.results) > 1 {
+		sort.Slice(this.results, func(i int, j int) bool {
+			return this.results[i].(string) < this.results[j].(string)
 		})
 	}
 
-	return a.results, nil
+	return this.results, nil
 }
 
 type execAnalyser struct {
@@ -56,6 +70,40 @@ type execAnalyser struct {
 	ioTime        time.Duration
 	waitTime      time.Duration
 	indexScanTime time.Duration
+
+	// totals per plan operator, summed over parallel instances
+	planStats map[plan.Operator]*planStats
+}
+
+type planStats struct {
+	inDocs      int64
+	outDocs     int64
+	execTime    time.Duration
+	memory      uint64
+	spilled     bool
+	interrupted bool // an instance was stopped before it had seen all its input
+}
+
+/*
+The execution statistics of a plan operator, summed over its parallel
+instances.
+*/
+func (this *execAnalyser) annotate(op plan.Operator) []string {
+	s, ok := this.planStats[op]
+	if !ok {
+		return nil
+	}
+	rv := []string{fmt.Sprintf("actual rows: %d", s.outDocs)}
+	if s.execTime > 0 {
+		rv = append(rv, "time: "+util.OutputDuration(s.execTime))
+	}
+	if s.memory > 0 {
+		rv = append(rv, fmt.Sprintf("memory: %d", s.memory))
+	}
+	if s.spilled {
+		rv = append(rv, "spilled")
+	}
+	return rv
 }
 
 func (this *execAnalyser) add(s string) {
@@ -71,16 +119,75 @@ func (this *execAnalyser) add(s string) {
 	this.results = append(this.results, s)
 }
 
-func (this *execAnalyser) record(op *base) {
+func (this *execAnalyser) record(op Operator) {
+	b := op.getBase()
+
 	// force accumulation of time
-	op.switchPhase(_NOTIME)
-	this.cpuTime += op.execTime
-	this.ioTime += op.servTime
-	this.waitTime += op.kernTime
+	b.switchPhase(_NOTIME)
+	this.cpuTime += b.execTime
+	this.ioTime += b.servTime
+	this.waitTime += b.kernTime
+
+	if p := op.PlanOp(); p != nil {
+		s, ok := this.planStats[p]
+		if !ok {
+			s = &planStats{}
+			this.planStats[p] = s
+		}
+		s.inDocs += b.inDocs
+		s.outDocs += b.outDocs
+		s.execTime += b.execTime + b.servTime
+		if b.valueExchange.maxSize > s.memory {
+			s.memory = b.valueExchange.maxSize
+		}
+		s.interrupted = s.interrupted || b.interrupted
+		switch op := op.(type) {
+		case *Order:
+			s.spilled = s.spilled || op.spilled
+		case *Merge:
+			s.spilled = s.spilled || op.spilled
+		}
+	}
+}
+
+/*
+Only operators that have seen documents and ran to completion are checked,
+since a LIMIT, an error or a kill leaves the actual count short, and the
+counts have to be large enough to matter.
+*/
+func (this *execAnalyser) checkEstimate(op plan.Operator, s *planStats) {
+	switch op.(type) {
+	case *plan.Sequence, *plan.Parallel, *plan.Authorize:
+		// the estimate is that of the child
+		return
+	}
+	estimated := op.Cardinality()
+	actual := float64(s.outDocs)
+	if estimated <= 0.0 || s.interrupted || (s.inDocs == 0 && s.outDocs == 0) {
+		return
+	}
+	if (actual > estimated*_MISESTIMATE_RATIO && actual > _MISESTIMATE_COUNT) ||
+		(estimated > actual*_MISESTIMATE_RATIO && estimated > _MISESTIMATE_COUNT) {
+		this.add(fmt.Sprintf("Large cardinality misestimate in %s: %.0f rows estimated, %d actual",
+			operatorName(op), estimated, s.outDocs))
+	}
+}
+
+func operatorName(op plan.Operator) string {
+	r := op.MarshalBase(nil)
+	name, _ := r["#operator"].(string)
+	alias, ok := r["as"].(string)
+	if !ok {
+		alias, _ = r["alias"].(string)
+	}
+	if alias != "" {
+		name += " (" + alias + ")"
+	}
+	return name
 }
 
 func (this *execAnalyser) VisitPrimaryScan(op *PrimaryScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.outDocs > _LARGE_COUNT {
 		this.add("High primary scan count")
 	}
@@ -88,7 +195,7 @@ func (this *execAnalyser) VisitPrimaryScan(op *PrimaryScan) (interface{}, error)
 }
 
 func (this *execAnalyser) VisitPrimaryScan3(op *PrimaryScan3) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.outDocs > _LARGE_COUNT {
 		this.add("High primary scan count")
 	}
@@ -96,7 +203,7 @@ func (this *execAnalyser) VisitPrimaryScan3(op *PrimaryScan3) (interface{}, erro
 }
 
 func (this *execAnalyser) VisitIndexScan(op *IndexScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	this.indexScanTime += op.servTime
 	for _, o := range op.children {
 		_, e := o.Accept(this)
@@ -108,57 +215,57 @@ func (this *execAnalyser) VisitIndexScan(op *IndexScan) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitIndexScan2(op *IndexScan2) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitIndexScan3(op *IndexScan3) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitKeyScan(op *KeyScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitValueScan(op *ValueScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDummyScan(op *DummyScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitExternalScan(op *ExternalScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCountScan(op *CountScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitIndexCountScan(op *IndexCountScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitIndexCountScan2(op *IndexCountScan2) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitIndexCountDistinctScan2(op *IndexCountDistinctScan2) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDistinctScan(op *DistinctScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	_, e := op.scan.Accept(this)
 	if e != nil {
 		return nil, e
@@ -167,7 +274,7 @@ func (this *execAnalyser) VisitDistinctScan(op *DistinctScan) (interface{}, erro
 }
 
 func (this *execAnalyser) VisitUnionScan(op *UnionScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range op.scans {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -178,7 +285,7 @@ func (this *execAnalyser) VisitUnionScan(op *UnionScan) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitIntersectScan(op *IntersectScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range op.scans {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -189,7 +296,7 @@ func (this *execAnalyser) VisitIntersectScan(op *IntersectScan) (interface{}, er
 }
 
 func (this *execAnalyser) VisitOrderedIntersectScan(op *OrderedIntersectScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range op.scans {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -200,17 +307,17 @@ func (this *execAnalyser) VisitOrderedIntersectScan(op *OrderedIntersectScan) (i
 }
 
 func (this *execAnalyser) VisitExpressionScan(op *ExpressionScan) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitIndexFtsSearch(op *IndexFtsSearch) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitFetch(op *Fetch) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.outDocs > _LARGE_COUNT {
 		this.add("High fetch count")
 	}
@@ -218,12 +325,12 @@ func (this *execAnalyser) VisitFetch(op *Fetch) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitDummyFetch(op *DummyFetch) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitJoin(op *Join) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.inDocs > _LARGE_COUNT {
 		perc := float64(op.outDocs) / float64(op.inDocs)
 		if perc < 0.1 {
@@ -234,27 +341,27 @@ func (this *execAnalyser) VisitJoin(op *Join) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitIndexJoin(op *IndexJoin) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitNest(op *Nest) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitIndexNest(op *IndexNest) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitUnnest(op *Unnest) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitNLJoin(op *NLJoin) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.inDocs > _LARGE_COUNT {
 		perc := float64(op.outDocs) / float64(op.inDocs)
 		if perc < 0.1 {
@@ -269,7 +376,7 @@ func (this *execAnalyser) VisitNLJoin(op *NLJoin) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitNLNest(op *NLNest) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	_, e := op.child.Accept(this)
 	if e != nil {
 		return nil, e
@@ -278,7 +385,7 @@ func (this *execAnalyser) VisitNLNest(op *NLNest) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitHashJoin(op *HashJoin) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.inDocs > _LARGE_COUNT {
 		perc := float64(op.outDocs) / float64(op.inDocs)
 		if perc < 0.1 {
@@ -293,7 +400,7 @@ func (this *execAnalyser) VisitHashJoin(op *HashJoin) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitHashNest(op *HashNest) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	_, e := op.child.Accept(this)
 	if e != nil {
 		return nil, e
@@ -302,12 +409,12 @@ func (this *execAnalyser) VisitHashNest(op *HashNest) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitLet(op *Let) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitWith(op *With) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	_, e := op.child.Accept(this)
 	if e != nil {
 		return nil, e
@@ -316,7 +423,7 @@ func (this *execAnalyser) VisitWith(op *With) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitFilter(op *Filter) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.inDocs > _LARGE_COUNT {
 		perc := float64(op.outDocs) / float64(op.inDocs)
 		if perc < 0.1 {
@@ -327,47 +434,57 @@ func (this *execAnalyser) VisitFilter(op *Filter) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitInitialGroup(op *InitialGroup) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitIntermediateGroup(op *IntermediateGroup) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitFinalGroup(op *FinalGroup) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitAsofJoin(op *AsofJoin) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitGapFill(op *GapFill) (interface{}, error) {
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitWindowAggregate(op *WindowAggregate) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitInitialProject(op *InitialProject) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitIndexCountProject(op *IndexCountProject) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDistinct(op *Distinct) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAll(op *All) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitUnionAll(op *UnionAll) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range op.children {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -378,7 +495,7 @@ func (this *execAnalyser) VisitUnionAll(op *UnionAll) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitIntersect(op *Intersect) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range []Operator{op.first, op.second} {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -389,7 +506,7 @@ func (this *execAnalyser) VisitIntersect(op *Intersect) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitIntersectAll(op *IntersectAll) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range []Operator{op.first, op.second} {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -400,7 +517,7 @@ func (this *execAnalyser) VisitIntersectAll(op *IntersectAll) (interface{}, erro
 }
 
 func (this *execAnalyser) VisitExcept(op *Except) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range []Operator{op.first, op.second} {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -411,7 +528,7 @@ func (this *execAnalyser) VisitExcept(op *Except) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitExceptAll(op *ExceptAll) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range []Operator{op.first, op.second} {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -422,7 +539,7 @@ func (this *execAnalyser) VisitExceptAll(op *ExceptAll) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitOrder(op *Order) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.inDocs > _LARGE_COUNT {
 		this.add("Large sort")
 	}
@@ -430,52 +547,52 @@ func (this *execAnalyser) VisitOrder(op *Order) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitOffset(op *Offset) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitLimit(op *Limit) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitSendInsert(op *SendInsert) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitSendUpsert(op *SendUpsert) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitSendDelete(op *SendDelete) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitClone(op *Clone) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitSet(op *Set) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitUnset(op *Unset) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitSendUpdate(op *SendUpdate) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitMerge(op *Merge) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range op.children {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -486,12 +603,12 @@ func (this *execAnalyser) VisitMerge(op *Merge) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitAlias(op *Alias) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAuthorize(op *Authorize) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.servTime > _AUTH_THRESHOLD {
 		this.add("Slow auth")
 	}
@@ -499,7 +616,7 @@ func (this *execAnalyser) VisitAuthorize(op *Authorize) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitParallel(op *Parallel) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if len(op.children) == 1 {
 		_, e := op.child.Accept(this)
 		if e != nil {
@@ -517,7 +634,7 @@ func (this *execAnalyser) VisitParallel(op *Parallel) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitSequence(op *Sequence) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	for _, o := range op.children {
 		_, e := o.Accept(this)
 		if e != nil {
@@ -528,7 +645,7 @@ func (this *execAnalyser) VisitSequence(op *Sequence) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitDiscard(op *Discard) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.inDocs > _LARGE_COUNT {
 		this.add("Large number of discarded results")
 	}
@@ -536,12 +653,12 @@ func (this *execAnalyser) VisitDiscard(op *Discard) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitStream(op *Stream) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCollect(op *Collect) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	if op.inDocs > _LARGE_COUNT {
 		this.add("Large sub-query result")
 	}
@@ -549,246 +666,301 @@ func (this *execAnalyser) VisitCollect(op *Collect) (interface{}, error) {
 }
 
 func (this *execAnalyser) VisitReceive(op *Receive) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitChannel(op *Channel) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreatePrimaryIndex(op *CreatePrimaryIndex) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateIndex(op *CreateIndex) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropIndex(op *DropIndex) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAlterIndex(op *AlterIndex) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitBuildIndexes(op *BuildIndexes) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateScope(op *CreateScope) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropScope(op *DropScope) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateCollection(op *CreateCollection) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAlterCollection(op *AlterCollection) (any, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropCollection(op *DropCollection) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitFlushCollection(op *FlushCollection) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitTruncate(op *Truncate) (interface{}, error) {
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitGrantRole(op *GrantRole) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitRevokeRole(op *RevokeRole) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitExplain(op *Explain) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitExplainFunction(op *ExplainFunction) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitPrepare(op *Prepare) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitInferKeyspace(op *InferKeyspace) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitInferExpression(op *InferExpression) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateFunction(op *CreateFunction) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropFunction(op *DropFunction) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitExecuteFunction(op *ExecuteFunction) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitIndexAdvice(op *IndexAdvice) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAdvise(op *Advise) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitUpdateStatistics(op *UpdateStatistics) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitStartTransaction(op *StartTransaction) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCommitTransaction(op *CommitTransaction) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitRollbackTransaction(op *RollbackTransaction) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitTransactionIsolation(op *TransactionIsolation) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitSavepoint(op *Savepoint) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateSequence(op *CreateSequence) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropSequence(op *DropSequence) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAlterSequence(op *AlterSequence) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitCreateView(op *CreateView) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitDropView(op *DropView) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitRefreshView(op *RefreshView) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitCreateTrigger(op *CreateTrigger) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitDropTrigger(op *DropTrigger) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitCreateJob(op *CreateJob) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitAlterJob(op *AlterJob) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitDropJob(op *DropJob) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitCreatePolicy(op *CreatePolicy) (interface{}, error) {
+	this.record(op)
+	return nil, nil
+}
+
+func (this *execAnalyser) VisitDropPolicy(op *DropPolicy) (interface{}, error) {
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateCredentialStore(op *CreateCredentialStore) (any, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAlterCredentialStore(op *AlterCredentialStore) (any, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropCredentialStore(op *DropCredentialStore) (any, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAlterBucket(op *AlterBucket) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateBucket(op *CreateBucket) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropBucket(op *DropBucket) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateCatalog(op *CreateCatalog) (any, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAlterCatalog(op *AlterCatalog) (any, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropCatalog(op *DropCatalog) (any, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAlterGroup(op *AlterGroup) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateGroup(op *CreateGroup) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropGroup(op *DropGroup) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitAlterUser(op *AlterUser) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitCreateUser(op *CreateUser) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
 
 func (this *execAnalyser) VisitDropUser(op *DropUser) (interface{}, error) {
-	this.record(op.getBase())
+	this.record(op)
 	return nil, nil
 }
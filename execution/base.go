@@ -169,6 +169,7 @@ type base struct {
 	outDocs         int64
 	phaseSwitches   int64
 	stopped         bool
+	interrupted     bool // a stop or a pause was received while running
 	isRoot          bool
 	bit             uint8
 	operatorCtx     opContext
@@ -680,6 +681,7 @@ func (this *base) baseSendAction(action opAction) bool {
 		} else {
 			this.opState = _STOPPING
 		}
+		this.interrupted = true
 		this.activeCond.L.Unlock()
 		rv = true
 		this.valueExchange.sendStop()
@@ -1637,6 +1639,19 @@ func (this *base) Phase() Phases {
 	return this.phase
 }
 
+// spilling to disk is reported with the operator statistics
+func marshalSpilled(r map[string]interface{}, spilled bool) {
+	if !spilled {
+		return
+	}
+	stats, ok := r["#stats"].(map[string]interface{})
+	if !ok {
+		stats = make(map[string]interface{}, 1)
+		r["#stats"] = stats
+	}
+	stats["#spilled"] = true
+}
+
 // profile marshaller
 func (this *base) marshalTimes(r map[string]interface{}) {
 	var d time.Duration
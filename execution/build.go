@@ -262,6 +262,16 @@ func (this *builder) VisitHashJoin(plan *plan.HashJoin) (interface{}, error) {
 	return checkOp(NewHashJoin(plan, this.context, c.(Operator), this.aliasMap), this.context)
 }
 
+func (this *builder) VisitAsofJoin(plan *plan.AsofJoin) (interface{}, error) {
+	child := plan.Child()
+	c, e := child.Accept(this)
+	if e != nil {
+		return nil, e
+	}
+
+	return checkOp(NewAsofJoin(plan, this.context, c.(Operator), this.aliasMap), this.context)
+}
+
 func (this *builder) VisitNest(plan *plan.Nest) (interface{}, error) {
 	this.setAliasMap(plan.Term())
 	return checkOp(NewNest(plan, this.context), this.context)
@@ -329,6 +339,11 @@ func (this *builder) VisitFinalGroup(plan *plan.FinalGroup) (interface{}, error)
 	return checkOp(NewFinalGroup(plan, this.context), this.context)
 }
 
+// GapFill
+func (this *builder) VisitGapFill(plan *plan.GapFill) (interface{}, error) {
+	return checkOp(NewGapFill(plan, this.context), this.context)
+}
+
 // Window functions
 func (this *builder) VisitWindowAggregate(plan *plan.WindowAggregate) (interface{}, error) {
 	return checkOp(NewWindowAggregate(plan, this.context), this.context)
@@ -734,6 +749,11 @@ func (this *builder) VisitFlushCollection(plan *plan.FlushCollection) (interface
 	return checkOp(NewFlushCollection(plan, this.context), this.context)
 }
 
+// Truncate
+func (this *builder) VisitTruncate(plan *plan.Truncate) (interface{}, error) {
+	return checkOp(NewTruncate(plan, this.context), this.context)
+}
+
 // CreateBucket
 func (this *builder) VisitCreateBucket(plan *plan.CreateBucket) (interface{}, error) {
 	return checkOp(NewCreateBucket(plan, this.context), this.context)
@@ -866,6 +886,50 @@ func (this *builder) VisitAlterSequence(plan *plan.AlterSequence) (interface{},
 	return checkOp(NewAlterSequence(plan, this.context), this.context)
 }
 
+// Views
+func (this *builder) VisitCreateView(plan *plan.CreateView) (interface{}, error) {
+	return checkOp(NewCreateView(plan, this.context), this.context)
+}
+
+func (this *builder) VisitDropView(plan *plan.DropView) (interface{}, error) {
+	return checkOp(NewDropView(plan, this.context), this.context)
+}
+
+func (this *builder) VisitRefreshView(plan *plan.RefreshView) (interface{}, error) {
+	return checkOp(NewRefreshView(plan, this.context), this.context)
+}
+
+// Triggers
+func (this *builder) VisitCreateTrigger(plan *plan.CreateTrigger) (interface{}, error) {
+	return checkOp(NewCreateTrigger(plan, this.context), this.context)
+}
+
+func (this *builder) VisitDropTrigger(plan *plan.DropTrigger) (interface{}, error) {
+	return checkOp(NewDropTrigger(plan, this.context), this.context)
+}
+
+// Jobs
+func (this *builder) VisitCreateJob(plan *plan.CreateJob) (interface{}, error) {
+	return checkOp(NewCreateJob(plan, this.context), this.context)
+}
+
+func (this *builder) VisitAlterJob(plan *plan.AlterJob) (interface{}, error) {
+	return checkOp(NewAlterJob(plan, this.context), this.context)
+}
+
+func (this *builder) VisitDropJob(plan *plan.DropJob) (interface{}, error) {
+	return checkOp(NewDropJob(plan, this.context), this.context)
+}
+
+// Policies
+func (this *builder) VisitCreatePolicy(plan *plan.CreatePolicy) (interface{}, error) {
+	return checkOp(NewCreatePolicy(plan, this.context), this.context)
+}
+
+func (this *builder) VisitDropPolicy(plan *plan.DropPolicy) (interface{}, error) {
+	return checkOp(NewDropPolicy(plan, this.context), this.context)
+}
+
 func (this *builder) VisitCreateCredentialStore(plan *plan.CreateCredentialStore) (any, error) {
 	return checkOp(NewCreateCredentialStore(plan, this.context), this.context)
 }
@@ -18,8 +18,9 @@ import (
 // Collect subquery results
 type Collect struct {
 	base
-	plan   *plan.Collect
-	values []interface{}
+	plan    *plan.Collect
+	values  []interface{}
+	buffers []*value.AnnotatedArray
 }
 
 const _COLLECT_CAP = 64
@@ -57,6 +58,17 @@ func (this *Collect) RunOnce(context *Context, parent value.Value) {
 }
 
 func (this *Collect) processItem(item value.AnnotatedValue, context *Context) bool {
+	if this.buffers != nil {
+		for _, b := range this.buffers {
+			err := b.Append(value.NewAnnotatedValue(item.Actual()))
+			if err != nil {
+				context.Error(err)
+				return false
+			}
+		}
+		return true
+	}
+
 	if len(this.values) == cap(this.values) {
 		values := make([]interface{}, len(this.values), len(this.values)<<1)
 		copy(values, this.values)
@@ -67,6 +79,11 @@ func (this *Collect) processItem(item value.AnnotatedValue, context *Context) bo
 	return true
 }
 
+// Results go to the buffers rather than to the values
+func (this *Collect) setBuffers(buffers []*value.AnnotatedArray) {
+	this.buffers = buffers
+}
+
 func (this *Collect) ValuesOnce() value.Value {
 	defer this.releaseValues()
 	return value.NewValue(this.values)
@@ -85,6 +85,7 @@ const (
 	INDEX_SCAN_HVI
 	FTS_SEARCH_SVI
 	EXTERNAL_SCAN
+	ASOF_JOIN
 
 	// Expression layer
 	ADVISOR
@@ -154,6 +155,7 @@ var _PHASE_NAMES = []string{
 	INDEX_SCAN_HVI:   "indexScan.HVI",
 	FTS_SEARCH_SVI:   "ftsSearch.SVI",
 	EXTERNAL_SCAN:    "externalScan",
+	ASOF_JOIN:        "asofJoin",
 
 	ADVISOR: "advisor",
 
@@ -392,6 +394,8 @@ type Context struct {
 	userAgent  string
 	users      string
 	remoteAddr string
+	clientId   string
+	statement  string
 
 	// queryMutex is a pointer and is meant to be shared when we share certain fields
 	// when calling NewQueryContext(). Since the fields are shared the mutex that protect
@@ -544,6 +548,8 @@ func (this *Context) Copy() *Context {
 		userAgent:      this.userAgent,
 		users:          this.users,
 		remoteAddr:     this.remoteAddr,
+		clientId:       this.clientId,
+		statement:      this.statement,
 		subqueryPlans:  this.subqueryPlans,
 		queryMutex:     this.queryMutex,
 		scanReportWait: this.scanReportWait,
@@ -1574,10 +1580,6 @@ func (this *Context) SetInlineUdf(udf string, expr expression.Expression, varNam
 
 func (this *opContext) EvaluateSubquery(query *algebra.Select, parent value.Value) (value.Value, error) {
 
-	var subplan, subplanIsks interface{}
-	var err error
-	planFound := false
-
 	useCache := useSubqCachedResult(query)
 	if useCache {
 		subresults := this.getSubresults()
@@ -1587,6 +1589,41 @@ func (this *opContext) EvaluateSubquery(query *algebra.Select, parent value.Valu
 		}
 	}
 
+	sequence, collect, err := this.subquerySequence(query)
+	if err != nil {
+		return nil, err
+	}
+	results := this.runSubquery(query, sequence, collect, parent)
+
+	// Cache results
+	if useCache {
+		subresults := this.getSubresults()
+		subresults.set(query, results)
+	}
+
+	return results, nil
+}
+
+/*
+Evaluates a non correlated subquery into buffers that can spill to disk,
+each getting all of the results.
+*/
+func (this *opContext) BufferSubquery(query *algebra.Select, parent value.Value, buffers []*value.AnnotatedArray) error {
+	sequence, collect, err := this.subquerySequence(query)
+	if err != nil {
+		return err
+	}
+	collect.setBuffers(buffers)
+	this.runSubquery(query, sequence, collect, parent)
+	return nil
+}
+
+// The execution tree of a subquery, reused if possible
+func (this *opContext) subquerySequence(query *algebra.Select) (*Sequence, *Collect, error) {
+	var subplan, subplanIsks interface{}
+	var err error
+	planFound := false
+
 	subplans := this.GetSubqueryPlans(true)
 	_, subplan, subplanIsks, planFound = subplans.Get(query, true)
 
@@ -1603,7 +1640,7 @@ func (this *opContext) EvaluateSubquery(query *algebra.Select, parent value.Valu
 				subplan, subplanIsks, err = this.SubqueryPlan(query, mutex, nil, nil, nil, false)
 				if err != nil {
 					mutex.Unlock()
-					return nil, err
+					return nil, nil, err
 				}
 				planFound = true
 				psubplans.Set(query, nil, subplan, subplanIsks, false)
@@ -1640,7 +1677,7 @@ func (this *opContext) EvaluateSubquery(query *algebra.Select, parent value.Valu
 					this.namedArgs, this.positionalArgs, false)
 				if err != nil {
 					mutex.Unlock()
-					return nil, err
+					return nil, nil, err
 				}
 				subplans.Set(query, nil, subplan, subplanIsks, false)
 			}
@@ -1672,20 +1709,26 @@ func (this *opContext) EvaluateSubquery(query *algebra.Select, parent value.Valu
 		// if any additional permissions need to be checked, check them now
 		authErr := qp.Authorize(this.Context.Credentials())
 		if authErr != nil {
-			return nil, authErr
+			return nil, nil, authErr
 		}
 		pipeline, err := Build(qp.PlanOp(), this.Context)
 		if err != nil {
 			// Generate our own error for this subquery, in addition to whatever the query above is doing.
 			err1 := errors.NewSubqueryBuildError(err)
 			this.Error(err1)
-			return nil, err1
+			return nil, nil, err1
 		}
 
 		// Collect subquery results
 		collect = NewCollect(plan.NewCollect(), this.Context)
 		sequence = NewSequence(plan.NewSequence(), this.Context, pipeline, collect)
 	}
+	return sequence, collect, nil
+}
+
+func (this *opContext) runSubquery(query *algebra.Select, sequence *Sequence, collect *Collect,
+	parent value.Value) value.Value {
+
 	var track int32
 	av, stashTracking := parent.(value.AnnotatedValue)
 	if stashTracking {
@@ -1707,11 +1750,13 @@ func (this *opContext) EvaluateSubquery(query *algebra.Select, parent value.Valu
 
 	results := collect.ValuesOnce()
 
+	collect.setBuffers(nil)
+
 	// mark execution tree for reuse if possible
 	if collect.opState == _DONE {
 		collect.opState = _COMPLETED
 	}
-	subExecTrees.set(query, sequence, collect)
+	this.getSubExecTrees().set(query, sequence, collect)
 
 	if stashTracking {
 		check := int32(0)
@@ -1723,13 +1768,7 @@ func (this *opContext) EvaluateSubquery(query *algebra.Select, parent value.Valu
 		}
 	}
 
-	// Cache results
-	if useCache {
-		subresults := this.getSubresults()
-		subresults.set(query, results)
-	}
-
-	return results, nil
+	return results
 }
 
 func useSubqCachedResult(query *algebra.Select) bool {
@@ -2576,6 +2615,23 @@ func (this *Context) Users() string {
 	return this.users
 }
 
+func (this *Context) SetClientContextId(id string) {
+	this.clientId = id
+}
+
+func (this *Context) ClientContextId() string {
+	return this.clientId
+}
+
+func (this *Context) SetStatement(statement string) {
+	this.statement = statement
+}
+
+// Context implements logging.RequestInfo
+func (this *Context) StatementHash() string {
+	return logging.StatementHash(this.statement)
+}
+
 func (this *Context) FormatDuration(d time.Duration) string {
 	return util.FormatDuration(d, this.durationStyle)
 }
@@ -14,8 +14,10 @@ import (
 	"github.com/couchbase/query/datastore"
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/triggers"
 	"github.com/couchbase/query/util"
 	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
 )
 
 var _SENDDELETE_OP_POOL util.FastPool
@@ -33,6 +35,7 @@ type SendDelete struct {
 	limit     int64
 	mk        missingKeys
 	batchSize int
+	triggers  *triggerSet
 }
 
 func NewSendDelete(plan *plan.SendDelete, context *Context) *SendDelete {
@@ -87,6 +90,12 @@ func (this *SendDelete) beforeItems(context *Context, parent value.Value) bool {
 		return false
 	}
 
+	var ok bool
+	this.triggers, ok = loadTriggers(this.keyspace, triggers.DELETE, context)
+	if !ok {
+		return false
+	}
+
 	if this.plan.Limit() == nil {
 		return true
 	}
@@ -157,18 +166,42 @@ func (this *SendDelete) flushBatch(context *Context) bool {
 			return false
 		}
 
+		if this.triggers.hasBefore() {
+			if _, err := this.triggers.fireBefore(key, av, nil, &this.operatorCtx); err != nil {
+				context.Error(err)
+				return false
+			}
+		}
+
 		pairs = pairs[0 : i+1]
 		pair := &pairs[i]
 		pair.Name = key
 		pair.Value = av
 	}
 
-	// If there is a RETURNING clause or USE KEYS VALIDATE clause
-	preserveMutations := (!fastDiscard || this.mk.validate)
+	// If there is a RETURNING clause or USE KEYS VALIDATE clause, or AFTER triggers need the documents deleted
+	preserveMutations := (!fastDiscard || this.mk.validate || this.triggers.hasAfter())
 
 	this.switchPhase(_SERVTIME)
 
+	var deltas []views.Delta
+	tracking := views.Tracking(this.keyspace.QualifiedName())
+	if tracking && this.plan.Unfetched() {
+		// planned before the keyspace was tracked, the documents deleted are not known
+		views.InvalidateMutations(this.keyspace.QualifiedName())
+		tracking = false
+	}
+	if tracking {
+		deltas = make([]views.Delta, len(pairs))
+		for i := range pairs {
+			deltas[i].Old = pairs[i].Value
+		}
+	}
+
 	dCount, dpairs, errs := this.keyspace.Delete(pairs, &this.operatorCtx, preserveMutations)
+	if tracking {
+		logViewMutations(this.keyspace.QualifiedName(), context, deltas, errs)
+	}
 
 	// Update mutation count with number of deleted docs
 	context.AddMutationCount(uint64(dCount))
@@ -198,6 +231,15 @@ func (this *SendDelete) flushBatch(context *Context) bool {
 		}
 	}
 
+	if this.triggers.hasAfter() {
+		for _, dp := range dpairs {
+			if err := this.triggers.fireAfter(dp.Name, dp.Value, nil, &this.operatorCtx); err != nil {
+				context.Error(err)
+				return false
+			}
+		}
+	}
+
 	if !fastDiscard {
 		for _, item := range this.batch {
 			if !this.sendItem(item) {
@@ -18,6 +18,7 @@ import (
 	"github.com/couchbase/query/auth"
 	"github.com/couchbase/query/datastore"
 	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression/jsonschema"
 	"github.com/couchbase/query/plan"
 	"github.com/couchbase/query/value"
 )
@@ -94,3 +95,34 @@ func authForSysXattrs(ks datastore.Keyspace, context *Context) errors.Error {
 	}
 	return nil
 }
+
+// Maximum number of failures reported for a document that does not conform to a validation rule
+const _VALIDATION_FAILURES = 16
+
+func validationRule(ks datastore.Keyspace, context *Context) (*jsonschema.Schema, bool) {
+	if vks, ok := ks.(datastore.ValidatingKeyspace); ok {
+		schema, err := vks.ValidationRule()
+		if err != nil {
+			context.Error(err)
+			return nil, false
+		}
+		return schema, true
+	}
+	return nil, true
+}
+
+func validateDocument(schema *jsonschema.Schema, ks datastore.Keyspace, key string, doc value.Value) errors.Error {
+	failures := schema.Validate(doc, _VALIDATION_FAILURES)
+	if len(failures) == 0 {
+		return nil
+	}
+	f := make([]interface{}, len(failures))
+	for i, failure := range failures {
+		f[i] = map[string]interface{}{
+			"path":    failure.Path,
+			"keyword": failure.Keyword,
+			"message": failure.Message,
+		}
+	}
+	return errors.NewDocumentValidationError(ks.QualifiedName(), key, f)
+}
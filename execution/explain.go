@@ -11,8 +11,10 @@ package execution
 import (
 	"encoding/json"
 
+	"github.com/couchbase/query/algebra"
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/transactions"
 	"github.com/couchbase/query/value"
 )
 
@@ -57,13 +59,31 @@ func (this *Explain) RunOnce(context *Context, parent value.Value) {
 			return
 		}
 
-		bytes, err := this.plan.MarshalJSON()
-		if err != nil {
-			context.Fatal(errors.NewExplainError(err, "EXPLAIN: Error marshaling JSON."))
-			return
+		var res interface{}
+		var err error
+		explain, _ := this.plan.(*plan.Explain)
+		switch {
+		case explain != nil && explain.Analyze():
+			res, err = this.analyze(explain, context, parent)
+			if err != nil {
+				context.Fatal(errors.NewExplainError(err, "EXPLAIN ANALYZE: Error executing statement."))
+				return
+			}
+		case explain != nil && explain.Format() != "" && explain.Format() != algebra.EXPLAIN_FORMAT_JSON:
+			res, err = explain.Render(nil)
+			if err != nil {
+				context.Fatal(errors.NewExplainError(err, "EXPLAIN: Error formatting plan."))
+				return
+			}
+		default:
+			res, err = this.plan.MarshalJSON()
+			if err != nil {
+				context.Fatal(errors.NewExplainError(err, "EXPLAIN: Error marshaling JSON."))
+				return
+			}
 		}
 
-		av := value.NewAnnotatedValue(bytes)
+		av := value.NewAnnotatedValue(res)
 		if context.UseRequestQuota() {
 			err := context.TrackValueSize(av.Size())
 			if err != nil {
@@ -77,6 +97,163 @@ func (this *Explain) RunOnce(context *Context, parent value.Value) {
 	})
 }
 
+const (
+	_ANALYZE_KEEP        = iota // the statement is read only, or its changes are kept
+	_ANALYZE_SAVEPOINT          // the transaction is rolled back to a savepoint
+	_ANALYZE_TRANSACTION        // the statement runs in a transaction that is rolled back
+)
+
+/*
+How the changes of the statement are undone. Unless the rollback option
+is turned off, a data modification statement is rolled back to a
+savepoint within a transaction, and runs in a transaction of its own
+otherwise.
+*/
+func analyzeRollback(explain *plan.Explain, context *Context) int {
+	switch {
+	case explain.Plan().Readonly() || !explain.Rollback():
+		return _ANALYZE_KEEP
+	case context.TxContext() != nil:
+		return _ANALYZE_SAVEPOINT
+	default:
+		return _ANALYZE_TRANSACTION
+	}
+}
+
+/*
+EXPLAIN ANALYZE runs the statement, discarding its results, and returns
+the plan as executed, with the actual rows of each operator next to the
+optimizer estimate. The changes of data modification statements are
+undone unless the rollback option is false: they are run in an implicit
+transaction, which is rolled back, or, within a transaction, rolled back
+to a savepoint set before they run.
+*/
+func (this *Explain) analyze(explain *plan.Explain, context *Context, parent value.Value) (interface{}, error) {
+	op := explain.Plan()
+	switch analyzeRollback(explain, context) {
+	case _ANALYZE_SAVEPOINT:
+		savepoint := "_explain_analyze_" + context.RequestId()
+		err := context.datastore.SetSavepoint(false, context, savepoint)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			err := context.datastore.RollbackTransaction(false, context, savepoint)
+			if err != nil {
+				context.Error(err)
+			}
+			context.AddMutationCount(-context.MutationCount())
+		}()
+	case _ANALYZE_TRANSACTION:
+		atrCollection, numAtrs := context.AtrCollection()
+		err := context.SetTransactionContext("EXPLAIN", true, context.txTimeout, 0, atrCollection, numAtrs, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			context.ExecuteTranStatement("ROLLBACK", false)
+			if txContext := context.TxContext(); txContext != nil {
+				transactions.DeleteTransContext(txContext.TxId(), false)
+			}
+			context.ResetTxContext()
+			context.AddMutationCount(-context.MutationCount())
+		}()
+	}
+
+	discard := NewDiscard(plan.NewDiscard(op.Cost(), op.Cardinality(), op.Size(), op.FrCost()), context)
+	pipeline, used, err := Build2(op, context, discard)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		pipeline = NewSequence(plan.NewSequence(), context, pipeline, discard)
+	}
+	defer pipeline.Done()
+
+	this.operatorCtx.Park(func(stop bool) {
+		if stop {
+			pipeline.SendAction(_ACTION_STOP)
+		} else {
+			pipeline.SendAction(_ACTION_PAUSE)
+		}
+	}, true)
+	pipeline.RunOnce(context, parent)
+
+	// Await completion
+	// If the root op implements a fork check - make a check. To avoid infinitely waiting
+	if pOp, ok := pipeline.(interface{ HasForkedChild() bool }); !used || !ok || pOp.HasForkedChild() {
+		discard.waitComplete()
+	}
+	this.operatorCtx.Resume(true)
+
+	analyser := &execAnalyser{planStats: make(map[plan.Operator]*planStats)}
+	analysis, err := analyser.analyse(pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	if format := explain.Format(); format != "" && format != algebra.EXPLAIN_FORMAT_JSON {
+		rendered, err := explain.Render(analyser.annotate)
+		if err != nil {
+			return nil, err
+		}
+		if len(analysis) > 0 && format == algebra.EXPLAIN_FORMAT_TEXT {
+			rendered += "\nAnalysis:\n"
+			for _, a := range analysis {
+				rendered += "   " + a.(string) + "\n"
+			}
+		}
+		return rendered, nil
+	}
+
+	bytes, err := json.Marshal(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var executed interface{}
+	err = json.Unmarshal(bytes, &executed)
+	if err != nil {
+		return nil, err
+	}
+	annotateRows(executed)
+
+	r := explain.MarshalBase(nil)
+	r["plan"] = executed
+	if len(analysis) > 0 {
+		r["analysis"] = analysis
+	}
+	return json.Marshal(r)
+}
+
+/*
+Add the estimated and actual rows to every operator that has an
+optimizer estimate.
+*/
+func annotateRows(op interface{}) {
+	switch op := op.(type) {
+	case map[string]interface{}:
+		for _, v := range op {
+			annotateRows(v)
+		}
+		if estimates, ok := op["optimizer_estimates"].(map[string]interface{}); ok {
+			if estimated, ok := estimates["cardinality"]; ok {
+				actual := float64(0)
+				if stats, ok := op["#stats"].(map[string]interface{}); ok {
+					actual, _ = stats["#itemsOut"].(float64)
+				}
+				op["#rows"] = map[string]interface{}{
+					"estimated": estimated,
+					"actual":    actual,
+				}
+			}
+		}
+	case []interface{}:
+		for _, v := range op {
+			annotateRows(v)
+		}
+	}
+}
+
 func (this *Explain) MarshalJSON() ([]byte, error) {
 	r := this.plan.MarshalBase(func(r map[string]interface{}) {
 		this.marshalTimes(r)
@@ -88,3 +265,18 @@ func (this *Explain) Done() {
 	this.baseDone()
 	this.plan = nil
 }
+
+// FormatPlan renders a stored plan: either a plan operator, as in system:completed_requests,
+// or an object holding one in "plan", as the metadata of system:prepareds does.
+func (this *Context) FormatPlan(p value.Value, format string) (string, error) {
+	if _, ok := p.Field("#operator"); !ok {
+		if inner, ok := p.Field("plan"); ok && inner.Type() == value.OBJECT {
+			p = inner
+		}
+	}
+	bytes, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return plan.FormatJSON(bytes, format)
+}
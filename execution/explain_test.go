@@ -0,0 +1,121 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/transactions"
+)
+
+func TestAnnotateRows(t *testing.T) {
+	var executed interface{}
+	err := json.Unmarshal([]byte(`{"#operator": "Sequence", "~children": [
+		{"#operator": "IndexScan3", "optimizer_estimates": {"cardinality": 10}, "#stats": {"#itemsOut": 250}},
+		{"#operator": "Filter", "optimizer_estimates": {"cardinality": 5}},
+		{"#operator": "InitialProject", "#stats": {"#itemsOut": 3}}]}`), &executed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotateRows(executed)
+	children := executed.(map[string]interface{})["~children"].([]interface{})
+	expected := []interface{}{
+		map[string]interface{}{"estimated": 10.0, "actual": 250.0},
+		map[string]interface{}{"estimated": 5.0, "actual": 0.0},
+		nil,
+	}
+	for i, c := range children {
+		rows, _ := c.(map[string]interface{})["#rows"]
+		if !reflect.DeepEqual(rows, expected[i]) {
+			t.Errorf("child %v: expected rows %v, got %v", i, expected[i], rows)
+		}
+	}
+}
+
+func TestCheckEstimate(t *testing.T) {
+	tests := []struct {
+		estimated   float64
+		actual      int64
+		interrupted bool
+		flagged     bool
+	}{
+		{-1, 50000, false, false},
+		{10, 50000, false, true},
+		{50000, 10, false, true},
+		{10, 500, false, false},
+		{2000, 5000, false, false},
+		{50000, 10, true, false},
+	}
+
+	for _, test := range tests {
+		a := &execAnalyser{}
+		op := plan.NewFilter(expression.TRUE_EXPR, "o", 1, test.estimated, 0, 0)
+		a.checkEstimate(op, &planStats{inDocs: test.actual, outDocs: test.actual, interrupted: test.interrupted})
+		if flagged := len(a.results) > 0; flagged != test.flagged {
+			t.Errorf("checkEstimate(%v, %v, %v): expected %v, got %v", test.estimated, test.actual,
+				test.interrupted, test.flagged, flagged)
+		}
+	}
+
+	// the hint names the operator and its rows, once
+	a := &execAnalyser{}
+	op := plan.NewFilter(expression.TRUE_EXPR, "o", 1, 10, 0, 0)
+	for i := 0; i < 2; i++ {
+		a.checkEstimate(op, &planStats{inDocs: 50000, outDocs: 50000})
+	}
+	expected := []interface{}{"Large cardinality misestimate in Filter (o): 10 rows estimated, 50000 actual"}
+	if !reflect.DeepEqual(a.results, expected) {
+		t.Errorf("expected %v, got %v", expected, a.results)
+	}
+
+	// sequences and parallel operators are skipped, their estimate is that of their child
+	a = &execAnalyser{}
+	a.checkEstimate(plan.NewParallel(op, 4), &planStats{inDocs: 50000, outDocs: 50000})
+	if len(a.results) > 0 {
+		t.Errorf("expected no hint for a Parallel operator, got %v", a.results)
+	}
+}
+
+func TestAnalyzeRollback(t *testing.T) {
+	ksref := algebra.NewKeyspaceRefWithContext("ks", "", "default", "")
+	del := plan.NewSendDelete(nil, ksref, nil, 0, 0, 0, 0, false)
+	sel := plan.NewDiscard(0, 0, 0, 0)
+
+	cases := []struct {
+		op       plan.Operator
+		rollback bool
+		tx       bool
+		expected int
+	}{
+		{sel, true, false, _ANALYZE_KEEP},
+		{sel, true, true, _ANALYZE_KEEP},
+		{del, true, false, _ANALYZE_TRANSACTION},
+		{del, true, true, _ANALYZE_SAVEPOINT},
+
+		// WITH {"rollback": false} keeps the changes, in or out of a transaction
+		{del, false, false, _ANALYZE_KEEP},
+		{del, false, true, _ANALYZE_KEEP},
+	}
+	for i, c := range cases {
+		explain := plan.NewExplain(plan.NewQueryPlan(c.op), "", nil, true, c.rollback, "")
+		context := &Context{}
+		if c.tx {
+			context.txContext = &transactions.TranContext{}
+		}
+		if rv := analyzeRollback(explain, context); rv != c.expected {
+			t.Errorf("case %v: expected %v, got %v", i, c.expected, rv)
+		}
+	}
+}
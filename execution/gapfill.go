@@ -0,0 +1,335 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/value"
+)
+
+const _GAPFILL_MAX_BUCKETS = 1000000
+
+/*
+Groups are held until all have been received; each series is then
+sorted on its buckets and sent with the missing buckets added.
+
+The group keys and the LOCF() and INTERPOLATE() calls of the following
+clauses are covers, set here on every group: added groups have no
+documents to evaluate them over.
+*/
+type GapFill struct {
+	base
+	plan      *plan.GapFill
+	keyNames  []string
+	fillNames []string
+	interval  float64
+	start     float64
+	end       float64
+	series    map[string]*gapSeries
+	order     []*gapSeries
+}
+
+type gapSeries struct {
+	rows []*gapRow
+}
+
+type gapRow struct {
+	item   value.AnnotatedValue
+	keys   value.Values
+	bucket float64
+	fills  value.Values
+}
+
+func NewGapFill(plan *plan.GapFill, context *Context) *GapFill {
+	rv := &GapFill{
+		plan: plan,
+	}
+
+	newBase(&rv.base, context)
+	rv.output = rv
+	return rv
+}
+
+func (this *GapFill) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitGapFill(this)
+}
+
+func (this *GapFill) Copy() Operator {
+	rv := &GapFill{
+		plan: this.plan,
+	}
+	this.base.copy(&rv.base)
+	return rv
+}
+
+func (this *GapFill) PlanOp() plan.Operator {
+	return this.plan
+}
+
+func (this *GapFill) RunOnce(context *Context, parent value.Value) {
+	this.runConsumer(this, context, parent, this.Release)
+}
+
+func (this *GapFill) beforeItems(context *Context, parent value.Value) bool {
+	this.keyNames = make([]string, len(this.plan.Keys()))
+	for i, key := range this.plan.Keys() {
+		this.keyNames[i] = expression.NewCover(key).Text()
+	}
+	this.fillNames = make([]string, len(this.plan.Fills()))
+	for i, fill := range this.plan.Fills() {
+		this.fillNames[i] = fill.String()
+	}
+	this.series = make(map[string]*gapSeries)
+	this.interval = 0
+
+	this.start = math.Inf(-1)
+	this.end = math.Inf(1)
+	if this.plan.Start() != nil {
+		var ok bool
+		this.start, ok = this.evaluateTime(this.plan.Start(), parent, context)
+		if ok {
+			this.end, ok = this.evaluateTime(this.plan.End(), parent, context)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *GapFill) evaluateTime(expr expression.Expression, parent value.Value, context *Context) (float64, bool) {
+	v, err := expr.Evaluate(parent, &this.operatorCtx)
+	if err != nil {
+		context.Fatal(errors.NewEvaluationError(err, "GAPFILL range"))
+		return 0, false
+	}
+	rv, ok := expression.TimeBucketMillis(v)
+	if !ok {
+		context.Fatal(errors.NewGapFillRangeError("the range must be given as two timestamps"))
+		return 0, false
+	}
+	return rv, true
+}
+
+func (this *GapFill) processItem(item value.AnnotatedValue, context *Context) bool {
+	keys := make(value.Values, len(this.plan.Keys()))
+	for i, key := range this.plan.Keys() {
+		k, err := key.Evaluate(item, &this.operatorCtx)
+		if err != nil {
+			context.Fatal(errors.NewEvaluationError(err, "GROUP key"))
+			item.Recycle()
+			return false
+		}
+		keys[i] = k
+		item.SetCover(this.keyNames[i], k)
+	}
+
+	fills := make(value.Values, len(this.plan.Fills()))
+	for i, fill := range this.plan.Fills() {
+		f, err := fill.Evaluate(item, &this.operatorCtx)
+		if err != nil {
+			context.Fatal(errors.NewEvaluationError(err, "GAPFILL"))
+			item.Recycle()
+			return false
+		}
+		fills[i] = f
+		item.SetCover(this.fillNames[i], f)
+	}
+
+	// groups outside of any bucket are sent as they are
+	bucket, ok := expression.TimeBucketMillis(keys[this.plan.Bucket()])
+	if !ok {
+		return this.sendItem(item)
+	}
+
+	if this.interval == 0 {
+		key := this.plan.Keys()[this.plan.Bucket()]
+		if cover, ok := key.(*expression.Cover); ok {
+			key = cover.Covered()
+		}
+		fn, _ := key.(*expression.TimeBucket)
+		if fn != nil {
+			iv, err := fn.Operands()[0].Evaluate(item, &this.operatorCtx)
+			if err != nil {
+				context.Fatal(errors.NewEvaluationError(err, "TIME_BUCKET interval"))
+				item.Recycle()
+				return false
+			}
+			this.interval, ok = expression.TimeBucketInterval(iv)
+		}
+		if !ok || fn == nil {
+			context.Fatal(errors.NewGapFillRangeError("the TIME_BUCKET() interval is not valid"))
+			item.Recycle()
+			return false
+		}
+	}
+
+	series := make([]interface{}, 0, len(keys))
+	for i, k := range keys {
+		if i != this.plan.Bucket() {
+			series = append(series, k)
+		}
+	}
+	sk := value.NewValue(series).String()
+	s := this.series[sk]
+	if s == nil {
+		s = &gapSeries{}
+		this.series[sk] = s
+		this.order = append(this.order, s)
+	}
+	s.rows = append(s.rows, &gapRow{item: item, keys: keys, bucket: bucket, fills: fills})
+	return true
+}
+
+func (this *GapFill) afterItems(context *Context) {
+	for _, s := range this.order {
+		if this.stopped {
+			return
+		}
+		rows, ok := this.fillSeries(s, context)
+		if !ok {
+			return
+		}
+		this.setFills(rows)
+		for _, row := range rows {
+			if !this.sendItem(row.item) {
+				return
+			}
+		}
+	}
+}
+
+// Sorts the groups of a series and adds the missing ones
+func (this *GapFill) fillSeries(s *gapSeries, context *Context) ([]*gapRow, bool) {
+	rows := s.rows
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].bucket < rows[j].bucket })
+
+	first := rows[0]
+	eps := this.interval / 2
+	lo := first.bucket
+	if !math.IsInf(this.start, 0) {
+		lo += math.Ceil((this.start-first.bucket)/this.interval-1e-9) * this.interval
+	}
+	hi := rows[len(rows)-1].bucket + eps
+	if !math.IsInf(this.end, 0) {
+		hi = this.end
+	}
+	if (hi-lo)/this.interval > _GAPFILL_MAX_BUCKETS {
+		context.Fatal(errors.NewGapFillRangeError("too many buckets to fill"))
+		return nil, false
+	}
+
+	filled := make([]*gapRow, 0, len(rows))
+	j := 0
+	for t := lo; t < hi; t += this.interval {
+		for j < len(rows) && rows[j].bucket < t-eps {
+			filled = append(filled, rows[j])
+			j++
+		}
+		if j < len(rows) && math.Abs(rows[j].bucket-t) < eps {
+			filled = append(filled, rows[j])
+			j++
+			continue
+		}
+
+		row, ok := this.newRow(first, t, context)
+		if !ok {
+			return nil, false
+		}
+		filled = append(filled, row)
+	}
+	filled = append(filled, rows[j:]...)
+	return filled, true
+}
+
+// An empty group for the bucket starting at t, in the series of like
+func (this *GapFill) newRow(like *gapRow, t float64, context *Context) (*gapRow, bool) {
+	keys := make(value.Values, len(like.keys))
+	copy(keys, like.keys)
+	bucket := this.plan.Bucket()
+	keys[bucket], _ = expression.TimeBucketStep(like.keys[bucket], this.interval,
+		int(math.Round((t-like.bucket)/this.interval)))
+
+	av := value.NewAnnotatedValue(nil)
+	aggregates := make(map[string]value.Value, len(this.plan.Aggregates()))
+	av.SetAttachment(value.ATT_AGGREGATES, aggregates)
+	for _, agg := range this.plan.Aggregates() {
+		aggregates[agg.String()], _ = agg.Default(nil, &this.operatorCtx)
+	}
+	for i, k := range keys {
+		av.SetCover(this.keyNames[i], k)
+	}
+
+	if context.UseRequestQuota() {
+		if err := context.TrackValueSize(av.Size()); err != nil {
+			context.Error(err)
+			av.Recycle()
+			return nil, false
+		}
+	}
+	return &gapRow{item: av, keys: keys, bucket: t}, true
+}
+
+/*
+LOCF() takes the last value of its operand that is not NULL or MISSING;
+INTERPOLATE() a value on the line between the numbers on either side.
+Both are NULL when there is no such value.
+*/
+func (this *GapFill) setFills(rows []*gapRow) {
+	for i, fill := range this.plan.Fills() {
+		_, interpolate := fill.(*expression.Interpolate)
+		var prev *gapRow
+		for r, row := range rows {
+			if row.fills != nil {
+				if v := row.fills[i]; v.Type() > value.NULL && (!interpolate || v.Type() == value.NUMBER) {
+					prev = row
+				}
+				continue
+			}
+
+			rv := value.NULL_VALUE
+			if prev != nil && !interpolate {
+				rv = prev.fills[i]
+			} else if prev != nil {
+				for _, next := range rows[r+1:] {
+					if next.fills != nil && next.fills[i].Type() == value.NUMBER {
+						v0 := value.AsNumberValue(prev.fills[i]).Float64()
+						v1 := value.AsNumberValue(next.fills[i]).Float64()
+						rv = value.NewValue(v0 + (v1-v0)*(row.bucket-prev.bucket)/(next.bucket-prev.bucket))
+						break
+					}
+				}
+			}
+			row.item.SetCover(this.fillNames[i], rv)
+		}
+	}
+}
+
+func (this *GapFill) MarshalJSON() ([]byte, error) {
+	r := this.plan.MarshalBase(func(r map[string]interface{}) {
+		this.marshalTimes(r)
+	})
+	return json.Marshal(r)
+}
+
+func (this *GapFill) reopen(context *Context) bool {
+	this.Release()
+	return this.baseReopen(context)
+}
+
+func (this *GapFill) Release() {
+	this.series = nil
+	this.order = nil
+}
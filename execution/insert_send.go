@@ -15,9 +15,12 @@ import (
 
 	"github.com/couchbase/query/datastore"
 	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression/jsonschema"
 	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/triggers"
 	"github.com/couchbase/query/util"
 	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
 )
 
 var _SENDINSERT_OP_POOL util.FastPool
@@ -36,6 +39,8 @@ type SendInsert struct {
 	skipNewKeys     bool
 	batchSize       int
 	sysXattrChecked time.Duration
+	validation      *jsonschema.Schema
+	triggers        *triggerSet
 }
 
 func NewSendInsert(plan *plan.SendInsert, context *Context) *SendInsert {
@@ -87,6 +92,17 @@ func (this *SendInsert) beforeItems(context *Context, parent value.Value) bool {
 		return false
 	}
 
+	var ok bool
+	this.validation, ok = validationRule(this.keyspace, context)
+	if !ok {
+		return false
+	}
+
+	this.triggers, ok = loadTriggers(this.keyspace, triggers.INSERT, context)
+	if !ok {
+		return false
+	}
+
 	if this.plan.Limit() == nil {
 		return true
 	}
@@ -232,6 +248,22 @@ func (this *SendInsert) flushBatch(context *Context) bool {
 			this.switchPhase(_EXECTIME)
 		}
 
+		if this.triggers.hasBefore() {
+			var terr errors.Error
+			val, terr = this.triggers.fireBefore(dpair.Name, nil, val, &this.operatorCtx)
+			if terr != nil {
+				context.Error(terr)
+				return false // halt mutations
+			}
+		}
+
+		if this.validation != nil {
+			if err := validateDocument(this.validation, this.keyspace, dpair.Name, val); err != nil {
+				context.Error(err)
+				return false // halt mutations
+			}
+		}
+
 		dpair.Options = adjustExpiration(options, copyOptions)
 		expiration, _ := getExpiration(dpair.Options)
 		dpair.Value = this.setDocumentKey(dpair.Name, value.NewAnnotatedValue(val), expiration, context)
@@ -246,8 +278,9 @@ func (this *SendInsert) flushBatch(context *Context) bool {
 	var errs errors.Errors
 	var iCount int
 
-	// If there is a RETURNING clause or the index used was #sequentialScan and we need to make the Halloween Problem checks
-	preserveMutations := (!fastDiscard || this.plan.SkipNewKeys())
+	// If there is a RETURNING clause or the index used was #sequentialScan and we need to make the Halloween Problem checks,
+	// or AFTER triggers need the documents inserted
+	preserveMutations := (!fastDiscard || this.plan.SkipNewKeys() || this.triggers.hasAfter())
 
 	if preserveMutations {
 		skipNewKeys := this.plan.SkipNewKeys()
@@ -258,7 +291,19 @@ func (this *SendInsert) flushBatch(context *Context) bool {
 		}
 	}
 
+	var deltas []views.Delta
+	tracking := views.Tracking(this.keyspace.QualifiedName())
+	if tracking {
+		deltas = make([]views.Delta, len(dpairs))
+		for i := range dpairs {
+			deltas[i].New = dpairs[i].Value
+		}
+	}
+
 	iCount, dpairs, errs = this.keyspace.Insert(dpairs, &this.operatorCtx, preserveMutations)
+	if tracking {
+		logViewMutations(this.keyspace.QualifiedName(), context, deltas, errs)
+	}
 
 	// Update mutation count with number of inserted docs
 	context.AddMutationCount(uint64(iCount))
@@ -273,6 +318,15 @@ func (this *SendInsert) flushBatch(context *Context) bool {
 		}
 	}
 
+	if this.triggers.hasAfter() {
+		for _, dp := range dpairs {
+			if err := this.triggers.fireAfter(dp.Name, nil, dp.Value, &this.operatorCtx); err != nil {
+				context.Error(err)
+				return false
+			}
+		}
+	}
+
 	if !fastDiscard {
 		for _, dp := range dpairs {
 			// Capture the inserted keys in case there is a RETURNING clause
@@ -0,0 +1,88 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/jobs"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/value"
+)
+
+type AlterJob struct {
+	base
+	plan *plan.AlterJob
+}
+
+func NewAlterJob(plan *plan.AlterJob, context *Context) *AlterJob {
+	rv := &AlterJob{
+		plan: plan,
+	}
+
+	newRedirectBase(&rv.base, context)
+	rv.output = rv
+	return rv
+}
+
+func (this *AlterJob) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitAlterJob(this)
+}
+
+func (this *AlterJob) Copy() Operator {
+	rv := &AlterJob{plan: this.plan}
+	this.base.copy(&rv.base)
+	return rv
+}
+
+func (this *AlterJob) PlanOp() plan.Operator {
+	return this.plan
+}
+
+func (this *AlterJob) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover(&this.base) // Recover from any panic
+		active := this.active()
+		defer this.close(context)
+		this.switchPhase(_EXECTIME)
+		defer this.switchPhase(_NOTIME)
+		defer this.notify() // Notify that I have stopped
+
+		if !active || context.Readonly() {
+			return
+		}
+
+		node := this.plan.Node()
+		creator := jobCreator(context)
+		this.switchPhase(_SERVTIME)
+		err := jobs.AlterJob(node.Name(), func(job *jobs.Job) errors.Error {
+			err := job.SetOptions(node.With())
+			if err == nil && node.Schedule() != "" {
+				err = job.SetSchedule(node.Schedule())
+			}
+			if err == nil && node.Text() != "" {
+				job.Text = node.Text()
+				job.QueryContext = node.QueryContext()
+				job.Creator = creator
+			}
+			return err
+		})
+		if err != nil {
+			context.Error(err)
+		}
+	})
+}
+
+func (this *AlterJob) MarshalJSON() ([]byte, error) {
+	r := this.plan.MarshalBase(func(r map[string]interface{}) {
+		this.marshalTimes(r)
+	})
+	return json.Marshal(r)
+}
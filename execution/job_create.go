@@ -0,0 +1,96 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/jobs"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/value"
+)
+
+type CreateJob struct {
+	base
+	plan *plan.CreateJob
+}
+
+func NewCreateJob(plan *plan.CreateJob, context *Context) *CreateJob {
+	rv := &CreateJob{
+		plan: plan,
+	}
+
+	newRedirectBase(&rv.base, context)
+	rv.output = rv
+	return rv
+}
+
+func (this *CreateJob) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateJob(this)
+}
+
+func (this *CreateJob) Copy() Operator {
+	rv := &CreateJob{plan: this.plan}
+	this.base.copy(&rv.base)
+	return rv
+}
+
+func (this *CreateJob) PlanOp() plan.Operator {
+	return this.plan
+}
+
+func (this *CreateJob) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover(&this.base) // Recover from any panic
+		active := this.active()
+		defer this.close(context)
+		this.switchPhase(_EXECTIME)
+		defer this.switchPhase(_NOTIME)
+		defer this.notify() // Notify that I have stopped
+
+		if !active || context.Readonly() {
+			return
+		}
+
+		node := this.plan.Node()
+		job, err := jobs.NewJob(node.Name(), node.Schedule(), node.With())
+		if err != nil {
+			context.Error(err)
+			return
+		}
+		job.Text = node.Text()
+		job.QueryContext = node.QueryContext()
+		job.Creator = jobCreator(context)
+		job.Created = time.Now()
+
+		this.switchPhase(_SERVTIME)
+		err = jobs.CreateJob(job, node.Replace(), context.RequestId())
+		if err != nil {
+			context.Error(err)
+		}
+	})
+}
+
+// The user a job runs as: whoever created it, or last changed its statement
+func jobCreator(context *Context) string {
+	user, domain := datastore.FirstCred(context.Credentials())
+	if user == "" {
+		return ""
+	}
+	return datastore.EncodeName(user, domain)
+}
+
+func (this *CreateJob) MarshalJSON() ([]byte, error) {
+	r := this.plan.MarshalBase(func(r map[string]interface{}) {
+		this.marshalTimes(r)
+	})
+	return json.Marshal(r)
+}
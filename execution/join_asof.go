@@ -0,0 +1,398 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
+)
+
+/*
+ASOF JOIN. The right-hand side is read first, like the build side of a
+hash join, and split on the values of the equality terms into series
+ordered on time; series read in order, as from an index on the equality
+keys and time, are not sorted again. Objects of the left-hand side are
+then streamed: each series keeps the position reached by the last one,
+so that while left-hand side times increase, as when read in index
+order, finding the nearest object only moves forward. Otherwise the
+position is looked for again.
+
+The whole right-hand side is held in memory and is not spilled to disk.
+As for a hash join, it counts against the memory quota of the request
+until the join completes, and the number of series is limited to the
+number of distinct keys a hash join build can hold.
+*/
+type AsofJoin struct {
+	base
+	plan      *plan.AsofJoin
+	child     Operator
+	aliasMap  map[string]string
+	ansiFlags uint32
+	series    map[string]*asofSeries
+	size      uint64
+	maxSeries int
+	buildVals []interface{}
+	probeVals []interface{}
+}
+
+type asofSeries struct {
+	rows []asofRow
+	last value.Value // time of the last left-hand side object
+	pos  int         // position for last
+}
+
+type asofRow struct {
+	time value.Value
+	item value.AnnotatedValue
+}
+
+func NewAsofJoin(plan *plan.AsofJoin, context *Context, child Operator, aliasMap map[string]string) *AsofJoin {
+	rv := &AsofJoin{
+		plan:     plan,
+		child:    child,
+		aliasMap: aliasMap,
+	}
+
+	newBase(&rv.base, context)
+	rv.trackChildren(1)
+	rv.execPhase = ASOF_JOIN
+	rv.output = rv
+	return rv
+}
+
+func (this *AsofJoin) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitAsofJoin(this)
+}
+
+func (this *AsofJoin) Copy() Operator {
+	rv := &AsofJoin{
+		plan:     this.plan,
+		child:    this.child.Copy(),
+		aliasMap: this.aliasMap,
+	}
+	this.base.copy(&rv.base)
+	return rv
+}
+
+func (this *AsofJoin) PlanOp() plan.Operator {
+	return this.plan
+}
+
+func (this *AsofJoin) Child() Operator {
+	return this.child
+}
+
+func (this *AsofJoin) RunOnce(context *Context, parent value.Value) {
+	this.runConsumer(this, context, parent, nil)
+}
+
+func (this *AsofJoin) beforeItems(context *Context, parent value.Value) bool {
+	if !context.assert(this.child != nil, "ASOF JOIN has no child") {
+		return false
+	}
+
+	this.ansiFlags = 0
+	onclause := this.plan.Onclause()
+	if cpred := onclause.Value(); cpred != nil {
+		if cpred.Truth() {
+			this.ansiFlags |= ANSI_ONCLAUSE_TRUE
+		} else {
+			this.ansiFlags |= ANSI_ONCLAUSE_FALSE
+		}
+	} else {
+		onclause.EnableInlistHash(&this.operatorCtx)
+		SetSearchInfo(this.aliasMap, parent, &this.operatorCtx, onclause)
+	}
+
+	filter := this.plan.Filter()
+	if filter != nil {
+		filter.EnableInlistHash(&this.operatorCtx)
+	}
+
+	this.series = make(map[string]*asofSeries)
+	this.size = 0
+	this.maxSeries = int(float64(util.MaxHashTableSize()) * util.HTLoadThreshold)
+	this.buildVals = make([]interface{}, len(this.plan.BuildExprs()))
+	this.probeVals = make([]interface{}, len(this.plan.ProbeExprs()))
+
+	this.child.SetOutput(this.child)
+	this.child.SetInput(nil)
+	this.child.SetParent(this)
+	this.child.SetStop(nil)
+
+	this.fork(this.child, context, parent)
+
+	if !this.buildSeries(context) {
+		return false
+	}
+
+	// with nothing to join to, only an outer join has anything to send
+	return len(this.series) > 0 || this.plan.Outer()
+}
+
+func (this *AsofJoin) buildSeries(context *Context) bool {
+	stopped := false
+	n := 1
+
+loop:
+	for {
+		item, child, cont := this.getItemChildrenOp(this.child)
+		if cont {
+			if item != nil {
+				key, ok := this.key(item, this.plan.BuildExprs(), this.buildVals, context)
+				if !ok {
+					return false
+				}
+				t, err := this.plan.BuildTime().Evaluate(item, &this.operatorCtx)
+				if err != nil {
+					context.Error(errors.NewEvaluationError(err, "ASOF JOIN time"))
+					return false
+				}
+
+				// objects with a MISSING or NULL key or time can never be matched
+				if key == "" || t.Type() <= value.NULL {
+					if context.UseRequestQuota() {
+						context.ReleaseValueSize(item.Size())
+					}
+					item.Recycle()
+					continue
+				}
+
+				s := this.series[key]
+				if s == nil {
+					if len(this.series) >= this.maxSeries {
+						if context.UseRequestQuota() {
+							context.ReleaseValueSize(item.Size())
+						}
+						item.Recycle()
+						context.Error(errors.NewHashTablePutError(
+							fmt.Errorf("Maximum number of ASOF JOIN series %d exceeded", this.maxSeries)))
+						return false
+					}
+					s = &asofSeries{}
+					this.series[key] = s
+				}
+				s.rows = append(s.rows, asofRow{time: t, item: item})
+				if context.UseRequestQuota() {
+					this.size += item.Size()
+				}
+			} else if child >= 0 {
+				n--
+			} else {
+				break loop
+			}
+		} else {
+			stopped = true
+			break loop
+		}
+	}
+
+	if n > 0 {
+		notifyChildren(this.child)
+		this.childrenWaitNoStop(this.child)
+	}
+
+	if stopped {
+		return false
+	}
+
+	for _, s := range this.series {
+		rows := s.rows
+		less := func(i, j int) bool { return rows[i].time.Collate(rows[j].time) < 0 }
+		if !sort.SliceIsSorted(rows, less) {
+			sort.SliceStable(rows, less)
+		}
+	}
+	return true
+}
+
+// The equality term values as a series key, empty if any is MISSING or NULL
+func (this *AsofJoin) key(item value.AnnotatedValue, exprs expression.Expressions, vals []interface{},
+	context *Context) (string, bool) {
+
+	for i, expr := range exprs {
+		v, err := expr.Evaluate(item, &this.operatorCtx)
+		if err != nil {
+			context.Error(errors.NewEvaluationError(err, "ASOF JOIN equality term"))
+			return "", false
+		}
+		if v.Type() <= value.NULL {
+			return "", true
+		}
+		vals[i] = v
+	}
+
+	b, err := value.MarshalArray(vals)
+	if err != nil {
+		context.Error(errors.NewExecutionInternalError(err.Error()))
+		return "", false
+	}
+	return string(b), true
+}
+
+func (this *AsofJoin) processItem(item value.AnnotatedValue, context *Context) bool {
+	defer this.switchPhase(_EXECTIME)
+
+	key, ok := this.key(item, this.plan.ProbeExprs(), this.probeVals, context)
+	if !ok {
+		return false
+	}
+
+	var s *asofSeries
+	var t value.Value
+	if key != "" {
+		s = this.series[key]
+	}
+	if s != nil {
+		var err error
+		t, err = this.plan.ProbeTime().Evaluate(item, &this.operatorCtx)
+		if err != nil {
+			context.Error(errors.NewEvaluationError(err, "ASOF JOIN time"))
+			return false
+		}
+	}
+
+	if t != nil && t.Type() > value.NULL {
+		// candidates are tried from the nearest until one satisfies the ON-clause and filter
+		pos := s.position(t, this.plan.Forward(), this.plan.Strict())
+		step, i := 1, pos
+		if !this.plan.Forward() {
+			step, i = -1, pos-1
+		}
+		for ; i >= 0 && i < len(s.rows); i += step {
+			match, ok, joined := processAnsiExec(item, s.rows[i].item, this.plan.Onclause(),
+				this.plan.BuildAliases(), this.ansiFlags, &this.operatorCtx, "join")
+			if !ok {
+				return false
+			}
+			if !match {
+				joined.Recycle()
+				continue
+			}
+			if filter := this.plan.Filter(); filter != nil {
+				result, err := filter.Evaluate(joined, &this.operatorCtx)
+				if err != nil {
+					context.Error(errors.NewEvaluationError(err, "ASOF JOIN filter"))
+					joined.Recycle()
+					return false
+				}
+				if !result.Truth() {
+					joined.Recycle()
+					continue
+				}
+			}
+			return this.send(joined, joined.Size(), context)
+		}
+	}
+
+	if this.plan.Outer() {
+		return this.send(item, 0, context)
+	} else if context.UseRequestQuota() {
+		context.ReleaseValueSize(item.Size())
+	}
+	return true
+}
+
+/*
+The position of the first object after t, or at t too if forward and
+not strict; the nearest object before t is the one preceding it.
+*/
+func (this *asofSeries) position(t value.Value, forward, strict bool) int {
+	after := func(i int) bool {
+		c := this.rows[i].time.Collate(t)
+		return c > 0 || (c == 0 && forward != strict)
+	}
+
+	pos := 0
+	if this.last != nil && this.last.Collate(t) <= 0 {
+		// merge: the position only moves forward
+		pos = this.pos
+		for pos < len(this.rows) && !after(pos) {
+			pos++
+		}
+	} else {
+		pos = sort.Search(len(this.rows), after)
+	}
+	this.last = t
+	this.pos = pos
+	return pos
+}
+
+func (this *AsofJoin) send(av value.AnnotatedValue, size uint64, context *Context) bool {
+	if context.UseRequestQuota() {
+		err := context.TrackValueSize(size)
+		if err != nil {
+			context.Error(err)
+			av.Recycle()
+			return false
+		}
+	}
+	return this.sendItem(av)
+}
+
+func (this *AsofJoin) afterItems(context *Context) {
+	this.dropSeries(context)
+	if (this.ansiFlags & (ANSI_ONCLAUSE_TRUE | ANSI_ONCLAUSE_FALSE)) == 0 {
+		this.plan.Onclause().ResetMemory(&this.operatorCtx)
+	}
+	filter := this.plan.Filter()
+	if filter != nil {
+		filter.ResetMemory(&this.operatorCtx)
+	}
+}
+
+func (this *AsofJoin) dropSeries(context *Context) {
+	if this.series != nil {
+		if context.UseRequestQuota() {
+			context.ReleaseValueSize(this.size)
+		}
+		this.series = nil
+		this.size = 0
+	}
+}
+
+func (this *AsofJoin) MarshalJSON() ([]byte, error) {
+	r := this.plan.MarshalBase(func(r map[string]interface{}) {
+		this.marshalTimes(r)
+		r["~child"] = this.child
+	})
+	return json.Marshal(r)
+}
+
+func (this *AsofJoin) SendAction(action opAction) {
+	this.baseSendAction(action)
+	child := this.child
+	if child != nil {
+		child.SendAction(action)
+	}
+}
+
+func (this *AsofJoin) reopen(context *Context) bool {
+	rv := this.baseReopen(context)
+	if rv && this.child != nil {
+		rv = this.child.reopen(context)
+	}
+	return rv
+}
+
+func (this *AsofJoin) Done() {
+	this.baseDone()
+	if this.child != nil {
+		child := this.child
+		this.child = nil
+		child.Done()
+	}
+}
@@ -0,0 +1,48 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/value"
+)
+
+func TestAsofPosition(t *testing.T) {
+	newSeries := func() *asofSeries {
+		s := &asofSeries{}
+		for _, n := range []int{10, 20, 20, 30} {
+			s.rows = append(s.rows, asofRow{time: value.NewValue(n)})
+		}
+		return s
+	}
+
+	tests := []struct {
+		forward, strict bool
+		times           []int
+		pos             []int
+	}{
+		// times going back are searched for again
+		{false, false, []int{5, 20, 35, 15}, []int{0, 3, 4, 1}},
+		{false, true, []int{5, 20, 35, 15}, []int{0, 1, 4, 1}},
+		{true, false, []int{5, 20, 35, 15}, []int{0, 1, 4, 1}},
+		{true, true, []int{5, 20, 35, 15}, []int{0, 3, 4, 1}},
+	}
+
+	for _, test := range tests {
+		s := newSeries()
+		for i, tm := range test.times {
+			pos := s.position(value.NewValue(tm), test.forward, test.strict)
+			if pos != test.pos[i] {
+				t.Errorf("time %d, forward %v, strict %v: expected position %d, received %d",
+					tm, test.forward, test.strict, test.pos[i], pos)
+			}
+		}
+	}
+}
@@ -642,6 +642,7 @@ func (this *Merge) wrapChild(op Operator, context *Context) (Operator, *Channel)
 func (this *Merge) MarshalJSON() ([]byte, error) {
 	r := this.plan.MarshalBase(func(r map[string]interface{}) {
 		this.marshalTimes(r)
+		marshalSpilled(r, this.spilled)
 		if this.update != nil {
 			r["update"] = this.update
 		}
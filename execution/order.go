@@ -293,6 +293,7 @@ func (this *Order) lessThan(v1 value.AnnotatedValue, v2 value.AnnotatedValue) bo
 func (this *Order) MarshalJSON() ([]byte, error) {
 	r := this.plan.MarshalBase(func(r map[string]interface{}) {
 		this.marshalTimes(r)
+		marshalSpilled(r, this.spilled)
 	})
 	return json.Marshal(r)
 }
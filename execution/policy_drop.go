@@ -0,0 +1,75 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/policies"
+	"github.com/couchbase/query/value"
+)
+
+type DropPolicy struct {
+	base
+	plan *plan.DropPolicy
+}
+
+func NewDropPolicy(plan *plan.DropPolicy, context *Context) *DropPolicy {
+	rv := &DropPolicy{
+		plan: plan,
+	}
+
+	newRedirectBase(&rv.base, context)
+	rv.output = rv
+	return rv
+}
+
+func (this *DropPolicy) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropPolicy(this)
+}
+
+func (this *DropPolicy) Copy() Operator {
+	rv := &DropPolicy{plan: this.plan}
+	this.base.copy(&rv.base)
+	return rv
+}
+
+func (this *DropPolicy) PlanOp() plan.Operator {
+	return this.plan
+}
+
+func (this *DropPolicy) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover(&this.base) // Recover from any panic
+		active := this.active()
+		defer this.close(context)
+		this.switchPhase(_EXECTIME)
+		defer this.switchPhase(_NOTIME)
+		defer this.notify() // Notify that I have stopped
+
+		if !active || context.Readonly() {
+			return
+		}
+
+		this.switchPhase(_SERVTIME)
+		err := policies.DropPolicy(this.plan.Keyspace(), this.plan.Node().Name(), this.plan.Node().Masking(),
+			this.plan.Node().FailIfNotExists())
+		if err != nil {
+			context.Error(err)
+		}
+	})
+}
+
+func (this *DropPolicy) MarshalJSON() ([]byte, error) {
+	r := this.plan.MarshalBase(func(r map[string]interface{}) {
+		this.marshalTimes(r)
+	})
+	return json.Marshal(r)
+}
@@ -72,6 +72,7 @@ func (this *InitialProject) beforeItems(context *Context, parent value.Value) bo
 func (this *InitialProject) processItem(item value.AnnotatedValue, context *Context) bool {
 	terms := this.plan.Terms()
 	n := len(terms)
+	masked := len(this.plan.Projection().Masks()) > 0
 
 	if n > 1 {
 		return this.processTerms(item, context)
@@ -86,7 +87,10 @@ func (this *InitialProject) processItem(item value.AnnotatedValue, context *Cont
 	result := terms[0].Result()
 	expr := result.Expression()
 
-	if result.Star() && result.Self() {
+	if result.Star() && result.Self() && masked {
+		// Unprefixed star of masked documents: the item itself cannot be sent
+		return this.processTerms(item, context)
+	} else if result.Star() && result.Self() {
 		// Unprefixed star
 		if item.Type() == value.OBJECT {
 			item.SetSelf(true)
@@ -127,7 +131,11 @@ func (this *InitialProject) processItem(item value.AnnotatedValue, context *Cont
 		}
 	} else if this.plan.Projection().Raw() {
 		// Raw projection of an expression
-		v, err := expr.Evaluate(item, &this.operatorCtx)
+		eval, ok := this.maskItem(item, context)
+		if !ok {
+			return false
+		}
+		v, err := expr.Evaluate(eval, &this.operatorCtx)
 		if err != nil {
 			context.Error(errors.NewEvaluationError(err, "projection"))
 			e, ok := err.(errors.Error)
@@ -199,11 +207,15 @@ func (this *InitialProject) processTerms(item value.AnnotatedValue, context *Con
 	}
 
 	bindingNames := this.plan.BindingNames()
+	eval, ok := this.maskItem(item, context)
+	if !ok {
+		return false
+	}
 
 	for _, term := range this.plan.Terms() {
 		alias := term.Result().Alias()
 		if alias != "" {
-			v, err := term.Result().Expression().Evaluate(item, &this.operatorCtx)
+			v, err := term.Result().Expression().Evaluate(eval, &this.operatorCtx)
 			if err != nil {
 				context.Error(errors.NewEvaluationError(err, "projection"))
 				e, ok := err.(errors.Error)
@@ -254,10 +266,10 @@ func (this *InitialProject) processTerms(item value.AnnotatedValue, context *Con
 			}
 		} else {
 			// Star
-			starval := item.GetValue()
+			starval := eval.GetValue()
 			if term.Result().Expression() != nil {
 				var err error
-				starval, err = term.Result().Expression().Evaluate(item, &this.operatorCtx)
+				starval, err = term.Result().Expression().Evaluate(eval, &this.operatorCtx)
 				if err != nil {
 					context.Error(errors.NewEvaluationError(err, "projection"))
 					e, ok := err.(errors.Error)
@@ -368,6 +380,59 @@ func (this *InitialProject) processTerms(item value.AnnotatedValue, context *Con
 	return true
 }
 
+/*
+Masking policies are applied to a copy of the item, so that the clauses
+evaluated after the projection, such as ORDER BY, still see the documents
+as they were read. Fields that are missing are left so.
+*/
+func (this *InitialProject) maskItem(item value.AnnotatedValue, context *Context) (value.AnnotatedValue, bool) {
+	masks := this.plan.Projection().Masks()
+	if len(masks) == 0 {
+		return item, true
+	}
+
+	docs := make(map[string]value.Value, 2)
+	for _, mask := range masks {
+		doc, ok := docs[mask.Alias]
+		if !ok {
+			doc, ok = item.Field(mask.Alias)
+			if !ok || doc.Type() != value.OBJECT {
+				continue
+			}
+			doc = doc.CopyForUpdate()
+			docs[mask.Alias] = doc
+		}
+
+		last := len(mask.Path) - 1
+		parent := doc
+		for i := 0; ok && i < last; i++ {
+			parent, ok = parent.Field(mask.Path[i])
+			ok = ok && parent.Type() == value.OBJECT
+		}
+		if !ok {
+			continue
+		}
+		if _, ok = parent.Field(mask.Path[last]); !ok {
+			continue
+		}
+		v, err := mask.Mask.Evaluate(item, &this.operatorCtx)
+		if err != nil {
+			context.Error(errors.NewEvaluationError(err, "masking policy"))
+			return nil, false
+		}
+		parent.SetField(mask.Path[last], v)
+	}
+
+	if len(docs) == 0 {
+		return item, true
+	}
+	rv := item.Copy().(value.AnnotatedValue)
+	for alias, doc := range docs {
+		rv.SetField(alias, doc)
+	}
+	return rv, true
+}
+
 func (this *InitialProject) MarshalJSON() ([]byte, error) {
 	r := this.plan.MarshalBase(func(r map[string]interface{}) {
 		this.marshalTimes(r)
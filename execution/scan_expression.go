@@ -13,6 +13,7 @@ import (
 
 	"github.com/couchbase/query/algebra"
 	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
 	"github.com/couchbase/query/plan"
 	"github.com/couchbase/query/value"
 )
@@ -86,6 +87,12 @@ func (this *ExpressionScan) RunOnce(context *Context, parent value.Value) {
 			defer this.setBuildBitFilters(alias, context)
 		}
 
+		// rows of a materialized CTE
+		if this.plan.Buffer() > 0 {
+			this.scanBuffer(alias, buildBitFltr, context, parent)
+			return
+		}
+
 		// use cached results if available
 		if useCache && this.results != nil {
 			for _, act := range this.results {
@@ -231,6 +238,71 @@ func (this *ExpressionScan) RunOnce(context *Context, parent value.Value) {
 	})
 }
 
+func (this *ExpressionScan) scanBuffer(alias string, buildBitFltr bool, context *Context, parent value.Value) {
+	var buffer *withBuffer
+	if ident, ok := this.plan.FromExpr().(*expression.Identifier); ok {
+		buffer = findWithBuffer(this, ident.Identifier(), this.plan.Buffer())
+	}
+	if buffer == nil {
+		context.Error(errors.NewExecutionInternalError("ExpressionScan: missing WITH buffer"))
+		return
+	}
+
+	filter := this.plan.Filter()
+	if filter != nil {
+		filter.EnableInlistHash(&this.operatorCtx)
+		defer filter.ResetMemory(&this.operatorCtx)
+	}
+
+	buffer.Lock()
+	defer buffer.Unlock()
+
+	err := buffer.rows.Foreach(func(row value.AnnotatedValue) bool {
+		actv := value.NewScopeValue(make(map[string]interface{}), parent)
+		actv.SetField(alias, row.Actual())
+		av := value.NewAnnotatedValue(actv)
+		av.SetId("")
+
+		if filter != nil {
+			result, err := filter.Evaluate(av, &this.operatorCtx)
+			if err != nil {
+				context.Error(errors.NewEvaluationError(err, "expression scan filter"))
+				return false
+			}
+			if !result.Truth() {
+				av.Recycle()
+				return true
+			}
+		}
+
+		if buildBitFltr && !this.buildBitFilters(av, &this.operatorCtx) {
+			return false
+		}
+
+		if this.plan.IsUnderNL() {
+			// Reset Covers (inherited from parent) if under nested-loop join
+			av.ResetCovers(nil)
+		}
+
+		if context.UseRequestQuota() {
+			err := context.TrackValueSize(av.Size())
+			if err != nil {
+				context.Error(err)
+				av.Recycle()
+				return false
+			}
+		}
+		if !this.sendItem(av) {
+			av.Recycle()
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		context.Error(err)
+	}
+}
+
 func (this *ExpressionScan) Done() {
 	this.baseDone()
 	for i := range this.results {
@@ -0,0 +1,157 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"fmt"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/functions"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/triggers"
+	"github.com/couchbase/query/value"
+)
+
+/*
+The triggers a send operator fires, as found when the operator starts.
+
+BEFORE triggers run, in order of name, on each document about to be
+written: a function returning an object replaces the new document, and
+one returning FALSE rejects it. AFTER triggers run on each document once
+written, and their results are ignored. A rejection or an error from any
+function fails the statement.
+*/
+type triggerSet struct {
+	before []*rowTrigger
+	after  []*rowTrigger
+}
+
+type rowTrigger struct {
+	name     string
+	function functions.FunctionName
+	args     expression.Expressions
+}
+
+func loadTriggers(ks datastore.Keyspace, event string, context *Context) (*triggerSet, bool) {
+	list, err := triggers.KeyspaceTriggers(ks, event)
+	if err != nil {
+		context.Error(err)
+		return nil, false
+	} else if len(list) == 0 {
+		return nil, true
+	}
+
+	rv := &triggerSet{}
+	for _, t := range list {
+		def, err := t.Definition(parseTrigger)
+		if err != nil {
+			context.Error(errors.NewTriggerError(errors.E_TRIGGER_INVALID_DEFINITION, t.FullName(), err))
+			return nil, false
+		}
+		if t.Timing == triggers.BEFORE {
+			rv.before = append(rv.before, def.(*rowTrigger))
+		} else {
+			rv.after = append(rv.after, def.(*rowTrigger))
+		}
+	}
+	return rv, true
+}
+
+func parseTrigger(t *triggers.Trigger) (interface{}, error) {
+	elements := algebra.ParsePath(t.Keyspace)
+	stmt, err := n1ql.ParseStatement2(t.Text, elements[0], t.QueryContext)
+	if err != nil {
+		return nil, err
+	}
+	create, ok := stmt.(*algebra.CreateTrigger)
+	if !ok {
+		return nil, fmt.Errorf("not a CREATE TRIGGER statement")
+	}
+	return &rowTrigger{name: t.FullName(), function: create.Function(), args: create.Args()}, nil
+}
+
+func (this *triggerSet) hasBefore() bool {
+	return this != nil && len(this.before) > 0
+}
+
+func (this *triggerSet) hasAfter() bool {
+	return this != nil && len(this.after) > 0
+}
+
+// Returns the document to write, which is nil when deleting
+func (this *triggerSet) fireBefore(key string, old, new value.Value, context *opContext) (value.Value, errors.Error) {
+	for _, t := range this.before {
+		res, err := t.fire(key, old, new, context)
+		if err != nil {
+			return nil, err
+		}
+		switch res.Type() {
+		case value.BOOLEAN:
+			if !res.Truth() {
+				return nil, errors.NewTriggerError(errors.E_TRIGGER_REJECTED, t.name, key)
+			}
+		case value.OBJECT:
+			if new != nil {
+				new = res
+			}
+		}
+	}
+	return new, nil
+}
+
+func (this *triggerSet) fireAfter(key string, old, new value.Value, context *opContext) errors.Error {
+	for _, t := range this.after {
+		_, err := t.fire(key, old, new, context)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *rowTrigger) fire(key string, old, new value.Value, context *opContext) (value.Value, errors.Error) {
+	row := make(map[string]interface{}, 2)
+	if old != nil {
+		row[algebra.TRIGGER_OLD] = triggerRow(key, old)
+	}
+	if new != nil {
+		row[algebra.TRIGGER_NEW] = triggerRow(key, new)
+	}
+	item := value.NewValue(row)
+
+	args := make([]value.Value, len(this.args))
+	for i, arg := range this.args {
+		v, err := arg.Evaluate(item, context)
+		if err != nil {
+			return nil, errors.NewTriggerError(errors.E_TRIGGER_EXECUTION, this.name, key, err)
+		}
+		args[i] = v
+	}
+	res, err := executeFunction(this.function, functions.NONE, args, context)
+	if err != nil {
+		return nil, errors.NewTriggerError(errors.E_TRIGGER_EXECUTION, this.name, key, err)
+	}
+	return res, nil
+}
+
+// a variable so that tests can supply their own functions
+var executeFunction = functions.ExecuteFunction
+
+// OLD and NEW carry the document key, so that META(OLD).id and META(NEW).id can be passed on
+func triggerRow(key string, doc value.Value) value.Value {
+	if av, ok := doc.(value.AnnotatedValue); ok {
+		return av
+	}
+	av := value.NewAnnotatedValue(doc)
+	av.SetId(key)
+	return av
+}
@@ -0,0 +1,112 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/functions"
+	"github.com/couchbase/query/value"
+)
+
+type testFunctionName struct {
+	functions.FunctionName
+	name string
+}
+
+func (this *testFunctionName) Name() string {
+	return this.name
+}
+
+// Triggers passing NEW, or OLD when there is no NEW, to functions that return fixed results
+func testTriggers(t *testing.T, results map[string]interface{}, calls *[]string) []*rowTrigger {
+	saved := executeFunction
+	executeFunction = func(name functions.FunctionName, modifiers functions.Modifier, args []value.Value,
+		context functions.Context) (value.Value, errors.Error) {
+
+		*calls = append(*calls, name.Name()+" "+string(args[0].ToString()))
+		return value.NewValue(results[name.Name()]), nil
+	}
+	t.Cleanup(func() { executeFunction = saved })
+
+	var rv []*rowTrigger
+	for _, name := range []string{"a", "b", "c"} {
+		if _, ok := results[name]; ok {
+			arg := expression.NewIfMissing(expression.NewIdentifier(algebra.TRIGGER_NEW),
+				expression.NewIdentifier(algebra.TRIGGER_OLD))
+			rv = append(rv, &rowTrigger{name: "default:b.s.c." + name,
+				function: &testFunctionName{name: name}, args: expression.Expressions{arg}})
+		}
+	}
+	return rv
+}
+
+func TestTriggersBefore(t *testing.T) {
+	var calls []string
+
+	// triggers run in order, each seeing the document the previous one returned
+	set := &triggerSet{before: testTriggers(t, map[string]interface{}{
+		"a": map[string]interface{}{"n": 2},
+		"b": true,
+		"c": map[string]interface{}{"n": 3},
+	}, &calls)}
+	doc, err := set.fireBefore("k1", nil, value.NewValue(map[string]interface{}{"n": 1}), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !doc.Equals(value.NewValue(map[string]interface{}{"n": 3})).Truth() {
+		t.Errorf("Expected the document returned by the last trigger, got %v", doc)
+	}
+	expected := []string{`a {"n":1}`, `b {"n":2}`, `c {"n":2}`}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+
+	// an object returned for a deletion does not make one
+	calls = nil
+	doc, err = set.fireBefore("k1", value.NewValue(map[string]interface{}{"n": 1}), nil, nil)
+	if err != nil || doc != nil {
+		t.Errorf("Expected no document to write, got %v, %v", doc, err)
+	}
+
+	// FALSE rejects the document, and the triggers that follow do not run
+	calls = nil
+	set = &triggerSet{before: testTriggers(t, map[string]interface{}{"a": false, "b": true}, &calls)}
+	_, err = set.fireBefore("k1", nil, value.NewValue(map[string]interface{}{"n": 1}), nil)
+	if err == nil || err.Code() != errors.E_TRIGGER_REJECTED {
+		t.Fatalf("Expected the document to be rejected, got %v", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("Expected only the rejecting trigger to run, got %v", calls)
+	}
+}
+
+func TestTriggersAfter(t *testing.T) {
+	var calls []string
+
+	// results are ignored, and the triggers run in order
+	set := &triggerSet{after: testTriggers(t, map[string]interface{}{
+		"a": false,
+		"b": map[string]interface{}{"n": 2},
+		"c": nil,
+	}, &calls)}
+	err := set.fireAfter("k1", value.NewValue(map[string]interface{}{"n": 0}),
+		value.NewValue(map[string]interface{}{"n": 1}), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{`a {"n":1}`, `b {"n":1}`, `c {"n":1}`}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+}
@@ -0,0 +1,98 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/triggers"
+	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
+)
+
+type Truncate struct {
+	base
+	plan *plan.Truncate
+}
+
+func NewTruncate(plan *plan.Truncate, context *Context) *Truncate {
+	rv := &Truncate{
+		plan: plan,
+	}
+
+	newRedirectBase(&rv.base, context)
+	rv.output = rv
+	return rv
+}
+
+func (this *Truncate) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitTruncate(this)
+}
+
+func (this *Truncate) Copy() Operator {
+	rv := &Truncate{plan: this.plan}
+	this.base.copy(&rv.base)
+	return rv
+}
+
+func (this *Truncate) PlanOp() plan.Operator {
+	return this.plan
+}
+
+func (this *Truncate) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover(&this.base) // Recover from any panic
+		active := this.active()
+		defer this.close(context)
+		this.switchPhase(_EXECTIME)
+		defer this.switchPhase(_NOTIME)
+		defer this.notify() // Notify that I have stopped
+
+		if !active || context.Readonly() {
+			return
+		}
+
+		keyspace := this.plan.Keyspace()
+		truncater, ok := keyspace.(datastore.TruncatingKeyspace)
+		if !ok {
+			context.Error(errors.NewNoTruncateError(keyspace.QualifiedName()))
+			return
+		}
+
+		// checked again, as the triggers may have been created since the statement was prepared
+		list, err := triggers.KeyspaceTriggers(keyspace, triggers.DELETE)
+		if err != nil {
+			context.Error(err)
+			return
+		} else if len(list) > 0 {
+			context.Error(errors.NewTriggerError(errors.E_TRIGGER_UNSUPPORTED, keyspace.QualifiedName(), "TRUNCATE"))
+			return
+		}
+
+		this.switchPhase(_SERVTIME)
+		deleted, err := truncater.Truncate(context)
+		context.AddMutationCount(uint64(deleted))
+
+		// the documents removed are not known to incremental view refreshes
+		views.InvalidateMutations(keyspace.QualifiedName())
+		if err != nil {
+			context.Error(err)
+		}
+	})
+}
+
+func (this *Truncate) MarshalJSON() ([]byte, error) {
+	r := this.plan.MarshalBase(func(r map[string]interface{}) {
+		this.marshalTimes(r)
+	})
+	return json.Marshal(r)
+}
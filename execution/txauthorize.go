@@ -102,7 +102,8 @@ func (this *Authorize) getTxPrivileges(privs *auth.Privileges, checkAtrPrivs boo
 			privs.ForEach(func(pp auth.PrivilegePair) {
 				var ferr error
 				switch pp.Priv {
-				case /* auth.PRIV_QUERY_SELECT, */ auth.PRIV_QUERY_UPDATE, auth.PRIV_QUERY_INSERT, auth.PRIV_QUERY_DELETE:
+				case /* auth.PRIV_QUERY_SELECT, */ auth.PRIV_QUERY_UPDATE, auth.PRIV_QUERY_INSERT, auth.PRIV_QUERY_DELETE,
+					auth.PRIV_QUERY_TRUNCATE:
 					if kPath, ferr = algebra.NewVariablePathWithContext(pp.Target, context.namespace, ""); ferr == nil {
 						kPath = algebra.NewPathLong(kPath.Namespace(), kPath.Bucket(), _DEFAULT, _DEFAULT)
 						if pp.Priv != auth.PRIV_QUERY_SELECT {
@@ -15,9 +15,12 @@ import (
 
 	"github.com/couchbase/query/datastore"
 	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression/jsonschema"
 	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/triggers"
 	"github.com/couchbase/query/util"
 	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
 )
 
 var _SENDUPDATE_OP_POOL util.FastPool
@@ -31,10 +34,12 @@ func init() {
 // Send to keyspace
 type SendUpdate struct {
 	base
-	plan      *plan.SendUpdate
-	keyspace  datastore.Keyspace
-	limit     int64
-	batchSize int
+	plan       *plan.SendUpdate
+	keyspace   datastore.Keyspace
+	limit      int64
+	batchSize  int
+	validation *jsonschema.Schema
+	triggers   *triggerSet
 }
 
 func NewSendUpdate(plan *plan.SendUpdate, context *Context) *SendUpdate {
@@ -85,6 +90,17 @@ func (this *SendUpdate) beforeItems(context *Context, parent value.Value) bool {
 		return false
 	}
 
+	var ok bool
+	this.validation, ok = validationRule(this.keyspace, context)
+	if !ok {
+		return false
+	}
+
+	this.triggers, ok = loadTriggers(this.keyspace, triggers.UPDATE, context)
+	if !ok {
+		return false
+	}
+
 	if this.plan.Limit() == nil {
 		return true
 	}
@@ -136,6 +152,17 @@ func (this *SendUpdate) flushBatch(context *Context) bool {
 		pairs = make([]value.Pair, 0, len(this.batch))
 	}
 
+	var deltas []views.Delta
+	tracking := views.Tracking(this.keyspace.QualifiedName())
+	if tracking {
+		deltas = make([]views.Delta, 0, len(this.batch))
+	}
+
+	var olds map[string]value.Value
+	if this.triggers.hasAfter() {
+		olds = make(map[string]value.Value, len(this.batch))
+	}
+
 	for i, item := range this.batch {
 		if this.stopped {
 			return false
@@ -175,9 +202,31 @@ func (this *SendUpdate) flushBatch(context *Context) bool {
 				return false
 			}
 
+			if this.triggers.hasBefore() {
+				var err errors.Error
+				cv, err = this.triggers.fireBefore(key, av, cv, &this.operatorCtx)
+				if err != nil {
+					context.Error(err)
+					return false
+				}
+			}
+			if olds != nil {
+				olds[key] = av
+			}
+
+			if this.validation != nil {
+				if err := validateDocument(this.validation, this.keyspace, key, cv); err != nil {
+					context.Error(err)
+					return false
+				}
+			}
+
 			cav := value.NewAnnotatedValue(cv)
 			cav.CopyAnnotations(av)
 			pairs[i].Value = cav
+			if tracking {
+				deltas = append(deltas, views.Delta{Old: av.GetValue(), New: cv})
+			}
 
 			if mv := clone.GetAttachment(value.ATT_OPTIONS); mv != nil {
 				options, _ = mv.(value.Value)
@@ -209,12 +258,15 @@ func (this *SendUpdate) flushBatch(context *Context) bool {
 		}
 	}
 
-	// If there is a RETURNING clause
-	preserveMutations := !fastDiscard
+	// If there is a RETURNING clause, or AFTER triggers need the documents updated
+	preserveMutations := !fastDiscard || this.triggers.hasAfter()
 
 	this.switchPhase(_SERVTIME)
 
 	uCount, pairs, errs := this.keyspace.Update(pairs, &this.operatorCtx, preserveMutations)
+	if tracking {
+		logViewMutations(this.keyspace.QualifiedName(), context, deltas, errs)
+	}
 
 	// Update mutation count with number of updated docs
 	context.AddMutationCount(uint64(uCount))
@@ -229,6 +281,15 @@ func (this *SendUpdate) flushBatch(context *Context) bool {
 		}
 	}
 
+	if olds != nil {
+		for _, p := range pairs {
+			if err := this.triggers.fireAfter(p.Name, olds[p.Name], p.Value, &this.operatorCtx); err != nil {
+				context.Error(err)
+				return false
+			}
+		}
+	}
+
 	if !fastDiscard {
 		for _, item := range this.batch {
 			if !this.sendItem(item) {
@@ -15,9 +15,12 @@ import (
 
 	"github.com/couchbase/query/datastore"
 	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression/jsonschema"
 	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/triggers"
 	"github.com/couchbase/query/util"
 	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
 )
 
 type SendUpsert struct {
@@ -26,6 +29,9 @@ type SendUpsert struct {
 	keyspace        datastore.Keyspace
 	batchSize       int
 	sysXattrChecked time.Duration
+	validation      *jsonschema.Schema
+	inserts         *triggerSet // the triggers of the documents created
+	updates         *triggerSet // the triggers of the documents replaced
 }
 
 func NewSendUpsert(plan *plan.SendUpsert, context *Context) *SendUpsert {
@@ -61,7 +67,22 @@ func (this *SendUpsert) RunOnce(context *Context, parent value.Value) {
 func (this *SendUpsert) beforeItems(context *Context, parent value.Value) bool {
 	this.sysXattrChecked = 0
 	this.keyspace = getKeyspace(this.plan.Keyspace(), this.plan.Term().ExpressionTerm(), &this.operatorCtx)
-	return this.keyspace != nil
+	if this.keyspace == nil {
+		return false
+	}
+
+	var ok bool
+	this.validation, ok = validationRule(this.keyspace, context)
+	if !ok {
+		return false
+	}
+
+	this.inserts, ok = loadTriggers(this.keyspace, triggers.INSERT, context)
+	if !ok {
+		return false
+	}
+	this.updates, ok = loadTriggers(this.keyspace, triggers.UPDATE, context)
+	return ok
 }
 
 func (this *SendUpsert) processItem(item value.AnnotatedValue, context *Context) bool {
@@ -111,6 +132,12 @@ func (this *SendUpsert) flushBatch(context *Context) bool {
 	var ok, copyOptions bool
 	i := 0
 
+	// the documents replaced, by key, nil for those created
+	var olds map[string]value.Value
+	if this.inserts.hasAfter() || this.updates.hasAfter() {
+		olds = make(map[string]value.Value, len(this.batch))
+	}
+
 	for _, av := range this.batch {
 		if this.stopped {
 			return false
@@ -208,6 +235,35 @@ func (this *SendUpsert) flushBatch(context *Context) bool {
 			this.switchPhase(_EXECTIME)
 		}
 
+		if this.inserts != nil || this.updates != nil {
+			old, ok := this.fetchOld(dpair.Name, context)
+			if !ok {
+				return false
+			}
+			set := this.inserts
+			if old != nil {
+				set = this.updates
+			}
+			if olds != nil {
+				olds[dpair.Name] = old
+			}
+			if set.hasBefore() {
+				var terr errors.Error
+				val, terr = set.fireBefore(dpair.Name, old, val, &this.operatorCtx)
+				if terr != nil {
+					context.Error(terr)
+					return false // halt mutations
+				}
+			}
+		}
+
+		if this.validation != nil {
+			if err := validateDocument(this.validation, this.keyspace, dpair.Name, val); err != nil {
+				context.Error(err)
+				return false // halt mutations
+			}
+		}
+
 		dpair.Options = adjustExpiration(options, copyOptions)
 		expiration, _ := getExpiration(dpair.Options)
 		// UPSERT can preserve expiration, but we can't get old value without read for RETURNING clause.
@@ -223,11 +279,16 @@ func (this *SendUpsert) flushBatch(context *Context) bool {
 	var errs errors.Errors
 	var uCount int
 
-	// If there is a RETURNING clause
-	preserveMutations := !fastDiscard
+	// If there is a RETURNING clause, or AFTER triggers need the documents written
+	preserveMutations := !fastDiscard || olds != nil
 
 	uCount, dpairs, errs = this.keyspace.Upsert(dpairs, &this.operatorCtx, preserveMutations)
 
+	// the documents replaced are not known
+	if views.Tracking(this.keyspace.QualifiedName()) {
+		views.InvalidateMutations(this.keyspace.QualifiedName())
+	}
+
 	// Update mutation count with number of upserted docs
 	context.AddMutationCount(uint64(uCount))
 
@@ -241,6 +302,23 @@ func (this *SendUpsert) flushBatch(context *Context) bool {
 		}
 	}
 
+	if olds != nil {
+		for _, dp := range dpairs {
+			old := olds[dp.Name]
+			set := this.inserts
+			if old != nil {
+				set = this.updates
+			}
+			if !set.hasAfter() {
+				continue
+			}
+			if err := set.fireAfter(dp.Name, old, dp.Value, &this.operatorCtx); err != nil {
+				context.Error(err)
+				return false
+			}
+		}
+	}
+
 	if !fastDiscard {
 		for _, dp := range dpairs {
 
@@ -271,6 +349,26 @@ func (this *SendUpsert) flushBatch(context *Context) bool {
 	return mutationOk
 }
 
+/*
+The document an UPSERT is about to replace, nil if it creates one, so
+that the INSERT or the UPDATE triggers can be fired.
+*/
+func (this *SendUpsert) fetchOld(key string, context *Context) (value.Value, bool) {
+	this.switchPhase(_SERVTIME)
+	fetched := make(map[string]value.AnnotatedValue, 1)
+	errs := this.keyspace.Fetch([]string{key}, fetched, &this.operatorCtx, nil, nil, false)
+	this.switchPhase(_EXECTIME)
+
+	if len(errs) > 0 {
+		context.Errors(errs)
+		return nil, false
+	}
+	if old, ok := fetched[key]; ok {
+		return old, true
+	}
+	return nil, true
+}
+
 func (this *SendUpsert) readonly() bool {
 	return false
 }
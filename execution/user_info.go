@@ -0,0 +1,147 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/value"
+)
+
+/*
+The roles and groups of users are looked up once a minute at most, as
+CURRENT_USER_INFO() is evaluated for every document read under a
+row-level security policy. Changes to them may take that long to apply.
+*/
+const _USER_INFO_TTL = time.Minute
+const _USER_INFO_LIMIT = 1024
+
+type userInfoEntry struct {
+	info    value.Value
+	expires time.Time
+}
+
+var userInfoCache struct {
+	sync.Mutex
+	entries map[string]*userInfoEntry
+}
+
+func init() {
+	userInfoCache.entries = make(map[string]*userInfoEntry, _USER_INFO_LIMIT)
+}
+
+/*
+The value of CURRENT_USER_INFO(): the id, domain and name of the user the
+request runs as, with its roles and groups. Grants lists both, and is
+what the grantees of policies are matched against. The built-in
+administrator has the admin role alone, and the bearer of a token the
+roles and issuer of the token.
+*/
+func (this *Context) CurrentUserInfo() (value.Value, errors.Error) {
+	var domain, id string
+	var bearer *auth.TokenIdentity
+	if creds := this.Credentials(); creds != nil && creds.Bearer != nil {
+		bearer = creds.Bearer
+		domain, id = bearer.Domain, bearer.User
+	} else if cred := this.Credential(); cred != nil {
+		domain, id = cred.Domain(), cred.Name()
+	} else if creds := this.Credentials(); creds != nil && len(creds.AuthenticatedUsers) > 0 {
+		id = creds.AuthenticatedUsers[0]
+		if i := strings.LastIndexByte(id, ':'); i >= 0 {
+			domain, id = id[:i], id[i+1:]
+		}
+	}
+	if id == "" {
+		return value.NULL_VALUE, nil
+	}
+
+	key := domain + ":" + id
+	if bearer != nil {
+		// tokens for the same user may carry different roles
+		key = bearer.Issuer + "\x00" + key + "\x00" + strings.Join(bearer.Roles, "\x00")
+	}
+	now := time.Now()
+	userInfoCache.Lock()
+	entry, ok := userInfoCache.entries[key]
+	userInfoCache.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.info, nil
+	}
+
+	info := map[string]interface{}{
+		"id":     id,
+		"domain": domain,
+	}
+	var roles, groups []interface{}
+	switch {
+	case bearer != nil:
+		if name, ok := bearer.Claims["name"].(string); ok {
+			info["name"] = name
+		}
+		info["issuer"] = bearer.Issuer
+		for _, r := range bearer.Roles {
+			roles = append(roles, r)
+		}
+	case domain == "local" || domain == "external":
+		cbDatastore, ok := datastore.GetDatastore().(datastore.CouchbaseDatastore)
+		if !ok {
+			break
+		}
+		u := &datastore.User{Id: id, Domain: domain}
+		err := cbDatastore.GetUserInfo(this, u)
+		if err != nil {
+			return nil, err
+		}
+		info["name"] = u.Name
+		seen := make(map[string]bool, len(u.Roles))
+		for _, r := range u.Roles {
+			if !seen[r.Name] {
+				seen[r.Name] = true
+				roles = append(roles, r.Name)
+			}
+		}
+		for _, g := range u.Groups {
+			groups = append(groups, g)
+		}
+	case domain == "admin":
+		roles = append(roles, "admin")
+	}
+	if roles == nil {
+		roles = []interface{}{}
+	}
+	if groups == nil {
+		groups = []interface{}{}
+	}
+	info["roles"] = roles
+	info["groups"] = groups
+	grants := make([]interface{}, 0, len(roles)+len(groups))
+	grants = append(grants, roles...)
+	info["grants"] = append(grants, groups...)
+
+	rv := value.NewValue(info)
+	userInfoCache.Lock()
+	if len(userInfoCache.entries) >= _USER_INFO_LIMIT {
+		for k, e := range userInfoCache.entries {
+			if !now.Before(e.expires) {
+				delete(userInfoCache.entries, k)
+			}
+		}
+		if len(userInfoCache.entries) >= _USER_INFO_LIMIT {
+			userInfoCache.entries = make(map[string]*userInfoEntry, _USER_INFO_LIMIT)
+		}
+	}
+	userInfoCache.entries[key] = &userInfoEntry{info: rv, expires: now.Add(_USER_INFO_TTL)}
+	userInfoCache.Unlock()
+	return rv, nil
+}
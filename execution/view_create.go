@@ -0,0 +1,81 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
+)
+
+type CreateView struct {
+	base
+	plan *plan.CreateView
+}
+
+func NewCreateView(plan *plan.CreateView, context *Context) *CreateView {
+	rv := &CreateView{
+		plan: plan,
+	}
+
+	newRedirectBase(&rv.base, context)
+	rv.output = rv
+	return rv
+}
+
+func (this *CreateView) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateView(this)
+}
+
+func (this *CreateView) Copy() Operator {
+	rv := &CreateView{plan: this.plan}
+	this.base.copy(&rv.base)
+	return rv
+}
+
+func (this *CreateView) PlanOp() plan.Operator {
+	return this.plan
+}
+
+func (this *CreateView) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover(&this.base) // Recover from any panic
+		active := this.active()
+		defer this.close(context)
+		this.switchPhase(_EXECTIME)
+		defer this.switchPhase(_NOTIME)
+		defer this.notify() // Notify that I have stopped
+
+		if !active || context.Readonly() {
+			return
+		}
+
+		this.switchPhase(_SERVTIME)
+		node := this.plan.Node()
+		var err errors.Error
+		if node.Materialized() {
+			err = views.CreateMaterializedView(context, node.Name(), node.Text(), node.QueryContext(), node.With())
+		} else {
+			err = views.CreateView(node.Name(), node.Text(), node.QueryContext(), node.Replace())
+		}
+		if err != nil {
+			context.Error(err)
+		}
+	})
+}
+
+func (this *CreateView) MarshalJSON() ([]byte, error) {
+	r := this.plan.MarshalBase(func(r map[string]interface{}) {
+		this.marshalTimes(r)
+	})
+	return json.Marshal(r)
+}
@@ -0,0 +1,450 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/scheduler"
+	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
+)
+
+type RefreshView struct {
+	base
+	plan *plan.RefreshView
+}
+
+func NewRefreshView(plan *plan.RefreshView, context *Context) *RefreshView {
+	rv := &RefreshView{
+		plan: plan,
+	}
+
+	newRedirectBase(&rv.base, context)
+	rv.output = rv
+	return rv
+}
+
+func (this *RefreshView) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitRefreshView(this)
+}
+
+func (this *RefreshView) Copy() Operator {
+	rv := &RefreshView{plan: this.plan}
+	this.base.copy(&rv.base)
+	return rv
+}
+
+func (this *RefreshView) PlanOp() plan.Operator {
+	return this.plan
+}
+
+func (this *RefreshView) RunOnce(context *Context, parent value.Value) {
+	this.once.Do(func() {
+		defer context.Recover(&this.base) // Recover from any panic
+		active := this.active()
+		defer this.close(context)
+		this.switchPhase(_EXECTIME)
+		defer this.switchPhase(_NOTIME)
+		defer this.notify() // Notify that I have stopped
+
+		if !active || context.Readonly() {
+			return
+		}
+
+		this.switchPhase(_SERVTIME)
+		node := this.plan.Node()
+		err := refreshView(node.Name(), node.Incremental(), context)
+		if err != nil {
+			context.Error(err)
+		}
+	})
+}
+
+func (this *RefreshView) MarshalJSON() ([]byte, error) {
+	r := this.plan.MarshalBase(func(r map[string]interface{}) {
+		this.marshalTimes(r)
+	})
+	return json.Marshal(r)
+}
+
+const _REFRESH_VIEW_CLASS = "refresh_view"
+
+type refreshViewParams struct {
+	path        *algebra.Path
+	incremental bool
+}
+
+/*
+The definition is checked before the refresh is scheduled, so that the
+errors a user can correct are returned by the statement, not the task.
+*/
+func refreshView(path *algebra.Path, incremental bool, context *Context) errors.Error {
+	name := path.SimpleString()
+	view, body, err := getMaterializedView(path)
+	if err != nil {
+		return err
+	}
+	if incremental {
+		agg, reason := views.NewAggregateView(body)
+		if agg != nil {
+			reason = agg.NotIncremental()
+		}
+		if reason != "" {
+			return errors.NewViewError(errors.E_VIEW_NOT_INCREMENTAL, name, reason)
+		}
+	}
+
+	sessionName, e := util.UUIDV4()
+	if e != nil {
+		return errors.NewViewError(errors.E_VIEW_REFRESH, name, e)
+	}
+	subClass := "full"
+	if incremental {
+		subClass = "incremental"
+	}
+	params := &refreshViewParams{path: path, incremental: incremental}
+	err = scheduler.ScheduleTask(sessionName, _REFRESH_VIEW_CLASS, subClass, 0, execRefreshView, nil, params,
+		path.ProtectedString(), context.NewQueryContext(view.QueryContext, false).(*Context))
+	if err != nil {
+		return errors.NewViewError(errors.E_VIEW_REFRESH, name, err)
+	}
+	return nil
+}
+
+func getMaterializedView(path *algebra.Path) (*views.View, *algebra.Select, errors.Error) {
+	name := path.SimpleString()
+	view, err := views.GetView(path)
+	if err != nil {
+		return nil, nil, err
+	} else if view == nil {
+		return nil, nil, errors.NewViewError(errors.E_VIEW_NOT_FOUND, name)
+	} else if !view.Materialized {
+		return nil, nil, errors.NewViewError(errors.E_VIEW_NOT_MATERIALIZED, name)
+	}
+	stmt, e := n1ql.ParseStatement2(view.Text, path.Namespace(), view.QueryContext)
+	if e != nil {
+		return nil, nil, errors.NewViewError(errors.E_VIEW_INVALID_DEFINITION, name, e)
+	}
+	body, ok := stmt.(*algebra.Select)
+	if !ok {
+		return nil, nil, errors.NewViewError(errors.E_VIEW_INVALID_DEFINITION, name)
+	}
+	return view, body, nil
+}
+
+func execRefreshView(context scheduler.Context, parms interface{}) (interface{}, []errors.Error) {
+	params, ok := parms.(*refreshViewParams)
+	if !ok || params == nil {
+		return nil, nil
+	}
+	ctx, ok := context.(*Context)
+	if !ok {
+		return nil, nil
+	}
+	path := params.path
+	name := path.SimpleString()
+
+	err := views.BeginRefresh(name)
+	if err != nil {
+		return nil, []errors.Error{err}
+	}
+	defer views.EndRefresh(name)
+
+	view, body, err := getMaterializedView(path)
+	if err != nil {
+		return nil, []errors.Error{err}
+	}
+	agg, _ := views.NewAggregateView(body)
+	if agg != nil && agg.NotIncremental() != "" {
+		agg = nil
+	}
+
+	if params.incremental && agg != nil {
+		deltas, ok := views.TakeMutations(name)
+		if ok {
+			rows, err := applyViewMutations(ctx, path, agg, deltas)
+			if err != nil {
+				views.StopTracking(name)
+				return nil, []errors.Error{errors.NewViewError(errors.E_VIEW_REFRESH, name, err)}
+			}
+			return map[string]interface{}{"mode": "incremental", "mutations": len(deltas), "rows": rows}, nil
+		}
+	}
+
+	// the log starts before the source is read: mutations made while the
+	// refresh runs may be applied twice by the next incremental refresh
+	if agg != nil {
+		source, err := datastore.GetKeyspace(agg.Term.Path().Parts()...)
+		if err != nil {
+			return nil, []errors.Error{errors.NewViewError(errors.E_VIEW_REFRESH, name, err)}
+		}
+		views.StartTracking(name, source.QualifiedName())
+	}
+
+	rows, err := fullRefresh(ctx, path, view.Text, agg)
+	if err != nil {
+		views.StopTracking(name)
+		return nil, []errors.Error{errors.NewViewError(errors.E_VIEW_REFRESH, name, err)}
+	}
+	return map[string]interface{}{"mode": "full", "rows": rows}, nil
+}
+
+/*
+A full refresh empties the collection of the view and fills it again
+from the definition. Rows of aggregate views are keyed on their groups,
+so that incremental refreshes can find them; other rows get random keys.
+*/
+func fullRefresh(context *Context, path *algebra.Path, text string, agg *views.AggregateView) (
+	uint64, errors.Error) {
+
+	target := path.ProtectedString()
+	_, _, err := context.EvaluateStatement("DELETE FROM "+target, nil, nil, false, false, false, "")
+	if err != nil {
+		return 0, errors.NewError(err, "")
+	}
+
+	key := "UUID()"
+	if agg != nil {
+		row := expression.NewIdentifier("d")
+		fields := make(expression.Expressions, len(agg.Keys))
+		for i, k := range agg.Keys {
+			alias := agg.Terms[agg.KeyTerm(k)].Alias()
+			fields[i] = expression.NewField(row, expression.NewFieldName(alias, false))
+		}
+		key = agg.KeyExpression(fields).String()
+	}
+	insert := "INSERT INTO " + target + " (KEY _k, VALUE _v) SELECT " + key + " AS _k, d AS _v FROM (" +
+		text + ") AS d"
+	_, rows, err := context.EvaluateStatement(insert, nil, nil, false, false, false, "")
+	if err != nil {
+		return 0, errors.NewError(err, "")
+	}
+	return rows, nil
+}
+
+type viewGroup struct {
+	fields []value.Value
+	deltas []value.NumberValue
+}
+
+/*
+Fold the logged mutations into the rows of an aggregate view. Each
+before image is subtracted from its group and each after image added;
+groups whose COUNT(*) drops to zero are removed.
+*/
+func applyViewMutations(context *Context, path *algebra.Path, agg *views.AggregateView, deltas []views.Delta) (
+	int, errors.Error) {
+
+	if len(deltas) == 0 {
+		return 0, nil
+	}
+	ks, err := datastore.GetKeyspace(path.Parts()...)
+	if err != nil {
+		return 0, err
+	}
+
+	groups, keys, err1 := foldViewMutations(agg, deltas, NewOpContext(context))
+	if err1 != nil {
+		return 0, errors.NewEvaluationError(err1, "materialized view")
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	current := make(map[string]value.AnnotatedValue, len(keys))
+	errs := ks.Fetch(keys, current, context, nil, nil, false)
+	for _, err := range errs {
+		if !errors.IsNotFoundError(ks.Name(), err) {
+			return 0, err
+		}
+	}
+
+	upserts := make([]value.Pair, 0, len(keys))
+	deletes := make([]value.Pair, 0)
+	for _, key := range keys {
+		var old value.Value
+		if av, ok := current[key]; ok {
+			old = av.GetValue()
+		}
+		row, remove := viewRow(agg, groups[key], old)
+		if remove {
+			if old != nil {
+				deletes = append(deletes, value.Pair{Name: key})
+			}
+		} else {
+			upserts = append(upserts, value.Pair{Name: key, Value: value.NewAnnotatedValue(value.NewValue(row))})
+		}
+	}
+
+	if len(upserts) > 0 {
+		_, _, errs = ks.Upsert(upserts, context, false)
+		if len(errs) > 0 {
+			return 0, errs[0]
+		}
+	}
+	if len(deletes) > 0 {
+		_, _, errs = ks.Delete(deletes, context, false)
+		if len(errs) > 0 {
+			return 0, errs[0]
+		}
+	}
+	return len(upserts) + len(deletes), nil
+}
+
+// The changes the mutations make to each group, by row key, and the row keys in the order first seen
+func foldViewMutations(agg *views.AggregateView, deltas []views.Delta, context expression.Context) (
+	map[string]*viewGroup, []string, error) {
+
+	alias := agg.Term.Alias()
+	keyExpr := agg.KeyExpression(agg.Keys)
+	zero := value.AsNumberValue(value.ZERO_VALUE)
+	one := value.AsNumberValue(value.ONE_VALUE)
+	groups := make(map[string]*viewGroup)
+	keys := make([]string, 0, len(deltas))
+
+	fold := func(doc value.Value, sign value.NumberValue) error {
+		item := value.NewScopeValue(map[string]interface{}{alias: doc}, nil)
+		if agg.Where != nil {
+			cond, err := agg.Where.Evaluate(item, context)
+			if err != nil {
+				return err
+			} else if !cond.Truth() {
+				return nil
+			}
+		}
+		k, err := keyExpr.Evaluate(item, context)
+		if err != nil {
+			return err
+		}
+		key := k.ToString()
+		group, ok := groups[key]
+		if !ok {
+			group = &viewGroup{fields: make([]value.Value, len(agg.Terms)), deltas: make([]value.NumberValue, len(agg.Terms))}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		for i, t := range agg.Terms {
+			switch e := t.Expression().(type) {
+			case *algebra.Count:
+				n := sign
+				if e.Operands()[0] != nil {
+					v, err := e.Operands()[0].Evaluate(item, context)
+					if err != nil {
+						return err
+					} else if v.Type() <= value.NULL {
+						n = zero
+					}
+				}
+				group.deltas[i] = add(group.deltas[i], n)
+			case *algebra.Sum:
+				v, err := e.Operands()[0].Evaluate(item, context)
+				if err != nil {
+					return err
+				} else if v.Type() == value.NUMBER {
+					group.deltas[i] = add(group.deltas[i], value.AsNumberValue(v).Mult(sign))
+				}
+			default:
+				if group.fields[i] == nil {
+					v, err := e.Evaluate(item, context)
+					if err != nil {
+						return err
+					}
+					group.fields[i] = v
+				}
+			}
+		}
+		return nil
+	}
+	for _, d := range deltas {
+		if d.Old != nil {
+			if err := fold(d.Old, one.Neg()); err != nil {
+				return nil, nil, err
+			}
+		}
+		if d.New != nil {
+			if err := fold(d.New, one); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return groups, keys, nil
+}
+
+// The row of a group once its changes apply to the row it had, if any, and whether it is to be removed
+func viewRow(agg *views.AggregateView, group *viewGroup, old value.Value) (map[string]interface{}, bool) {
+	zero := value.AsNumberValue(value.ZERO_VALUE)
+	row := make(map[string]interface{}, len(agg.Terms))
+	remove := false
+	for i, t := range agg.Terms {
+		name := t.Alias()
+		var prev value.Value
+		if old != nil {
+			prev, _ = old.Field(name)
+		}
+		switch e := t.Expression().(type) {
+		case *algebra.Count, *algebra.Sum:
+			n := group.deltas[i]
+			if prev != nil && prev.Type() == value.NUMBER {
+				n = add(n, value.AsNumberValue(prev))
+			}
+			if n == nil {
+				n = zero
+			}
+			row[name] = n
+			if c, ok := e.(*algebra.Count); ok && c.Operands()[0] == nil && n.Float64() <= 0 {
+				remove = true
+			}
+		default:
+			if group.fields[i] != nil {
+				if group.fields[i].Type() != value.MISSING {
+					row[name] = group.fields[i]
+				}
+			} else if prev != nil && prev.Type() != value.MISSING {
+				row[name] = prev
+			}
+		}
+	}
+	// SUM over no values is NULL
+	for _, t := range agg.Terms {
+		if s, ok := t.Expression().(*algebra.Sum); ok {
+			count := row[agg.Terms[agg.CountOf(s.Operands()[0])].Alias()].(value.NumberValue)
+			if count.Float64() <= 0 {
+				row[t.Alias()] = value.NULL_VALUE
+			}
+		}
+	}
+	return row, remove
+}
+
+func add(a, b value.NumberValue) value.NumberValue {
+	if a == nil {
+		return b
+	}
+	return a.Add(b)
+}
+
+/*
+Mutations of keyspaces with materialized views are logged for incremental
+refreshes. Failed and transactional mutations make the log unusable.
+*/
+func logViewMutations(keyspace string, context *Context, deltas []views.Delta, errs errors.Errors) {
+	if len(errs) > 0 || context.txContext != nil {
+		views.InvalidateMutations(keyspace)
+	} else {
+		views.RecordMutations(keyspace, deltas)
+	}
+}
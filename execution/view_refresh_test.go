@@ -0,0 +1,75 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package execution
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
+)
+
+func orderDoc(region, status string, amount interface{}) value.Value {
+	doc := map[string]interface{}{"region": region, "status": status}
+	if amount != nil {
+		doc["amount"] = amount
+	}
+	return value.NewValue(doc)
+}
+
+func TestViewMutations(t *testing.T) {
+	n1ql.SetNamespaces(map[string]interface{}{"default": true})
+	stmt, err := n1ql.ParseStatement2("SELECT o.region, COUNT(*) AS n, COUNT(o.amount) AS c, SUM(o.amount) AS total "+
+		"FROM default:b.s.orders AS o WHERE o.status = \"open\" GROUP BY o.region", "default", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	agg, reason := views.NewAggregateView(stmt.(*algebra.Select))
+	if agg == nil || agg.NotIncremental() != "" {
+		t.Fatalf("Expected an incremental view, found %v %v", reason, agg.NotIncremental())
+	}
+
+	groups, keys, err := foldViewMutations(agg, []views.Delta{
+		{New: orderDoc("north", "open", 5)},
+		{New: orderDoc("north", "open", 3)},
+		{Old: orderDoc("south", "open", 2), New: orderDoc("south", "closed", 2)},
+		{New: orderDoc("east", "closed", 7)},
+		{Old: orderDoc("west", "open", 4), New: orderDoc("west", "open", nil)},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// orders that are not open are not in the view
+	if len(keys) != 3 || keys[0] != "[\"north\"]" || keys[1] != "[\"south\"]" || keys[2] != "[\"west\"]" {
+		t.Fatalf("Expected the groups north, south and west, found %v", keys)
+	}
+
+	check := func(key string, old map[string]interface{}, expected map[string]interface{}, remove bool) {
+		var prev value.Value
+		if old != nil {
+			prev = value.NewValue(old)
+		}
+		row, r := viewRow(agg, groups[key], prev)
+		if r != remove {
+			t.Errorf("Expected group %v to be removed: %v, found %v", key, remove, r)
+		} else if !remove && !value.NewValue(row).EquivalentTo(value.NewValue(expected)) {
+			t.Errorf("Expected group %v to be %v, found %v", key, expected, value.NewValue(row))
+		}
+	}
+
+	// a new group
+	check("[\"north\"]", nil, map[string]interface{}{"region": "north", "n": 2, "c": 2, "total": 8}, false)
+	// a group whose documents all leave it
+	check("[\"south\"]", map[string]interface{}{"region": "south", "n": 1, "c": 1, "total": 2}, nil, true)
+	// a group with no amounts left has no total
+	check("[\"west\"]", map[string]interface{}{"region": "west", "n": 2, "c": 1, "total": 4},
+		map[string]interface{}{"region": "west", "n": 2, "c": 0, "total": nil}, false)
+}
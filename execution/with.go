@@ -11,10 +11,15 @@ package execution
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/encryption"
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/expression"
 	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/system"
+	"github.com/couchbase/query/util"
 	"github.com/couchbase/query/value"
 )
 
@@ -23,9 +28,16 @@ const _MAX_IMPLICIT_DOCS = int64(10000)
 
 type With struct {
 	base
-	plan  *plan.With
-	child Operator
-	wv    value.AnnotatedValue
+	plan    *plan.With
+	child   Operator
+	wv      value.AnnotatedValue
+	buffers map[string][]*withBuffer
+}
+
+// The rows of a CTE, for one expression scan
+type withBuffer struct {
+	sync.Mutex
+	rows *value.AnnotatedArray
 }
 
 func NewWith(plan *plan.With, context *Context, child Operator) *With {
@@ -98,6 +110,18 @@ func (this *With) RunOnce(context *Context, parent value.Value) {
 				this.notify()
 				break
 			}
+			if n, ok := this.plan.Buffered()[with.Alias()]; ok {
+				err := this.bufferWith(with, n, wv, context)
+				if err != nil {
+					context.Error(err)
+					this.notify()
+
+					// MB-31605 have to start the child for the output and stop
+					// operators to be set properly by sequences
+					break
+				}
+				continue
+			}
 			v, e := with.Expression().Evaluate(wv, &this.operatorCtx)
 			if e != nil {
 				context.Error(errors.NewEvaluationError(e, "WITH"))
@@ -254,6 +278,102 @@ func (this *With) RunOnce(context *Context, parent value.Value) {
 	})
 }
 
+/*
+Evaluate a CTE once into a buffer for each expression scan reading it,
+rather than binding it as an array.
+*/
+func (this *With) bufferWith(with expression.With, n int, parent value.Value, context *Context) errors.Error {
+	subq, ok := with.Expression().(*algebra.Subquery)
+	if !ok {
+		return errors.NewExecutionInternalError(fmt.Sprintf("WITH %s is not a subquery", with.Alias()))
+	}
+
+	buffers := make([]*withBuffer, n)
+	rows := make([]*value.AnnotatedArray, n)
+	for i := range buffers {
+		b, err := newWithBuffer(context)
+		if err != nil {
+			return err
+		}
+		buffers[i] = b
+		rows[i] = b.rows
+	}
+	if this.buffers == nil {
+		this.buffers = make(map[string][]*withBuffer, len(this.plan.Buffered()))
+	}
+	this.buffers[with.Alias()] = buffers
+
+	err := this.operatorCtx.BufferSubquery(subq.Select(), parent, rows)
+	if err != nil {
+		return errors.NewEvaluationError(err, "WITH")
+	}
+	return nil
+}
+
+func newWithBuffer(context *Context) (*withBuffer, errors.Error) {
+	var shouldSpill func(uint64, uint64) bool
+	var encryptionKey *encryption.EaRKey
+
+	if context.IsFeatureEnabled(util.N1QL_SPILL_TO_DISK) {
+		var err error
+		encryptionKey, err = context.GetActiveEncryptionKey(encryption.KeyDataType{TypeName: encryption.OTHER_KEY_DATATYPE})
+		if err != nil {
+			return nil, errors.NewEncryptionError(errors.E_ENCRYPTION, err)
+		}
+
+		if context.UseRequestQuota() && context.MemoryQuota() > 0 {
+			shouldSpill = func(c uint64, n uint64) bool {
+				if (c + n) <= context.ProducerThrottleQuota() {
+					return false
+				}
+				f := util.RoundPlaces(system.GetMemActualFreePercent(), 1)
+				if f < 0.1 {
+					f = 0.1
+				} else if f > 0.7 {
+					f = 0.7
+				}
+				return context.CurrentQuotaUsage() > f
+			}
+		} else {
+			maxSize := context.AvailableMemory()
+			if maxSize > 0 {
+				maxSize = uint64(float64(maxSize) / float64(util.NumCPU()) * 0.2) // 20% of per CPU free memory
+			}
+			if maxSize < _MIN_SIZE {
+				maxSize = _MIN_SIZE
+			}
+			shouldSpill = func(c uint64, n uint64) bool {
+				return (c + n) > maxSize
+			}
+		}
+	}
+
+	rows := value.NewAnnotatedArray(
+		func(size int) value.AnnotatedValues { return make(value.AnnotatedValues, 0, size) },
+		nil,
+		shouldSpill,
+		nil,
+		nil,
+		false,
+		encryptionKey,
+	)
+	return &withBuffer{rows: rows}, nil
+}
+
+/*
+The n-th buffer of a CTE, from the With operator above op.
+*/
+func findWithBuffer(op Operator, alias string, n int) *withBuffer {
+	for p := op.Parent(); p != nil; p = p.Parent() {
+		if with, ok := p.(*With); ok {
+			if buffers := with.buffers[alias]; len(buffers) >= n {
+				return buffers[n-1]
+			}
+		}
+	}
+	return nil
+}
+
 func (this *With) MarshalJSON() ([]byte, error) {
 	r := this.plan.MarshalBase(func(r map[string]interface{}) {
 		this.marshalTimes(r)
@@ -284,6 +404,7 @@ func (this *With) reopen(context *Context) bool {
 		rv = this.child.reopen(context)
 	}
 	this.recycleBindings()
+	this.releaseBuffers()
 	return rv
 }
 
@@ -295,6 +416,16 @@ func (this *With) Done() {
 		child.Done()
 	}
 	this.recycleBindings()
+	this.releaseBuffers()
+}
+
+func (this *With) releaseBuffers() {
+	for _, buffers := range this.buffers {
+		for _, b := range buffers {
+			b.rows.Release()
+		}
+	}
+	this.buffers = nil
 }
 
 func (this *With) recycleBindings() {
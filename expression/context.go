@@ -117,6 +117,12 @@ type ParkableExecutePreparedContext interface {
 		value.Value, uint64, error)
 }
 
+// Created to avoid import cycles between plan and expression package
+type PlanFormatContext interface {
+	Context
+	FormatPlan(plan value.Value, format string) (string, error)
+}
+
 type SequenceContext interface {
 	Context
 	NextSequenceValue(name string) (int64, errors.Error)
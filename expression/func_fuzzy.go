@@ -0,0 +1,613 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package expression
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/couchbase/query/value"
+)
+
+// LEVENSHTEIN(expr1, expr2), EDIT_DISTANCE(expr1, expr2). Returns the minimum number of
+// single character insertions, deletions and substitutions needed to turn one string into the other.
+
+type Levenshtein struct {
+	CommutativeBinaryFunctionBase
+}
+
+func NewLevenshtein(first, second Expression) Function {
+	rv := &Levenshtein{}
+	rv.Init("levenshtein", first, second)
+
+	rv.expr = rv
+	return rv
+}
+
+func (this *Levenshtein) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Levenshtein) Type() value.Type { return value.NUMBER }
+
+func (this *Levenshtein) Evaluate(item value.Value, context Context) (value.Value, error) {
+	first, second, rv, err := evaluateStringPair(this.operands, item, context)
+	if rv != nil || err != nil {
+		return rv, err
+	}
+
+	return value.NewValue(levenshtein([]rune(first), []rune(second))), nil
+}
+
+func (this *Levenshtein) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewLevenshtein(operands[0], operands[1])
+	}
+}
+
+// JARO_WINKLER(expr1, expr2). Returns the Jaro-Winkler similarity of the two strings,
+// between 0 (no similarity) and 1 (identical).
+
+type JaroWinkler struct {
+	CommutativeBinaryFunctionBase
+}
+
+func NewJaroWinkler(first, second Expression) Function {
+	rv := &JaroWinkler{}
+	rv.Init("jaro_winkler", first, second)
+
+	rv.expr = rv
+	return rv
+}
+
+func (this *JaroWinkler) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *JaroWinkler) Type() value.Type { return value.NUMBER }
+
+func (this *JaroWinkler) Evaluate(item value.Value, context Context) (value.Value, error) {
+	first, second, rv, err := evaluateStringPair(this.operands, item, context)
+	if rv != nil || err != nil {
+		return rv, err
+	}
+
+	return value.NewValue(jaroWinkler([]rune(first), []rune(second))), nil
+}
+
+func (this *JaroWinkler) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewJaroWinkler(operands[0], operands[1])
+	}
+}
+
+// SIMILARITY(expr1, expr2). Returns the trigram similarity of the two strings, that is the
+// number of trigrams they share divided by the number of distinct trigrams in either.
+
+type Similarity struct {
+	CommutativeBinaryFunctionBase
+}
+
+func NewSimilarity(first, second Expression) Function {
+	rv := &Similarity{}
+	rv.Init("similarity", first, second)
+
+	rv.expr = rv
+	return rv
+}
+
+func (this *Similarity) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Similarity) Type() value.Type { return value.NUMBER }
+
+func (this *Similarity) Evaluate(item value.Value, context Context) (value.Value, error) {
+	first, second, rv, err := evaluateStringPair(this.operands, item, context)
+	if rv != nil || err != nil {
+		return rv, err
+	}
+
+	t1 := trigrams(first)
+	t2 := trigrams(second)
+	if len(t1) == 0 && len(t2) == 0 {
+		return value.ZERO_VALUE, nil
+	}
+
+	common := 0
+	for t, _ := range t1 {
+		if _, ok := t2[t]; ok {
+			common++
+		}
+	}
+
+	return value.NewValue(float64(common) / float64(len(t1)+len(t2)-common)), nil
+}
+
+func (this *Similarity) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewSimilarity(operands[0], operands[1])
+	}
+}
+
+// TRIGRAMS(expr). Returns the sorted array of distinct trigrams of the string value.
+// Together with array indexing, this allows SIMILARITY() predicates to use an index.
+
+type Trigrams struct {
+	UnaryFunctionBase
+}
+
+func NewTrigrams(operand Expression) Function {
+	rv := &Trigrams{}
+	rv.Init("trigrams", operand)
+
+	rv.expr = rv
+	return rv
+}
+
+func (this *Trigrams) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Trigrams) Type() value.Type { return value.ARRAY }
+
+func (this *Trigrams) Evaluate(item value.Value, context Context) (value.Value, error) {
+	arg, err := this.operands[0].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	} else if arg.Type() == value.MISSING {
+		return value.MISSING_VALUE, nil
+	} else if arg.Type() != value.STRING {
+		return value.NULL_VALUE, nil
+	}
+
+	set := trigrams(arg.ToString())
+	keys := make([]string, 0, len(set))
+	for t, _ := range set {
+		keys = append(keys, t)
+	}
+	sort.Strings(keys)
+
+	rv := make([]interface{}, len(keys))
+	for i, t := range keys {
+		rv[i] = t
+	}
+
+	return value.NewValue(rv), nil
+}
+
+func (this *Trigrams) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewTrigrams(operands[0])
+	}
+}
+
+// SOUNDEX(expr). Returns the four character American Soundex code of the string value.
+
+type Soundex struct {
+	UnaryFunctionBase
+}
+
+func NewSoundex(operand Expression) Function {
+	rv := &Soundex{}
+	rv.Init("soundex", operand)
+
+	rv.expr = rv
+	return rv
+}
+
+func (this *Soundex) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Soundex) Type() value.Type { return value.STRING }
+
+func (this *Soundex) Evaluate(item value.Value, context Context) (value.Value, error) {
+	arg, err := this.operands[0].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	} else if arg.Type() == value.MISSING {
+		return value.MISSING_VALUE, nil
+	} else if arg.Type() != value.STRING {
+		return value.NULL_VALUE, nil
+	}
+
+	return value.NewValue(soundex(arg.ToString())), nil
+}
+
+func (this *Soundex) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewSoundex(operands[0])
+	}
+}
+
+// METAPHONE(expr). Returns the Metaphone phonetic key of the string value.
+
+type Metaphone struct {
+	UnaryFunctionBase
+}
+
+func NewMetaphone(operand Expression) Function {
+	rv := &Metaphone{}
+	rv.Init("metaphone", operand)
+
+	rv.expr = rv
+	return rv
+}
+
+func (this *Metaphone) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Metaphone) Type() value.Type { return value.STRING }
+
+func (this *Metaphone) Evaluate(item value.Value, context Context) (value.Value, error) {
+	arg, err := this.operands[0].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	} else if arg.Type() == value.MISSING {
+		return value.MISSING_VALUE, nil
+	} else if arg.Type() != value.STRING {
+		return value.NULL_VALUE, nil
+	}
+
+	return value.NewValue(metaphone(arg.ToString())), nil
+}
+
+func (this *Metaphone) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewMetaphone(operands[0])
+	}
+}
+
+/*
+Evaluate both operands of a string comparison function. If the result is
+already determined (MISSING or NULL) it is returned as rv.
+*/
+func evaluateStringPair(operands Expressions, item value.Value, context Context) (
+	first, second string, rv value.Value, err error) {
+
+	fv, err := operands[0].Evaluate(item, context)
+	if err != nil {
+		return
+	}
+	sv, err := operands[1].Evaluate(item, context)
+	if err != nil {
+		return
+	}
+
+	if fv.Type() == value.MISSING || sv.Type() == value.MISSING {
+		rv = value.MISSING_VALUE
+	} else if fv.Type() != value.STRING || sv.Type() != value.STRING {
+		rv = value.NULL_VALUE
+	} else {
+		first = fv.ToString()
+		second = sv.ToString()
+	}
+	return
+}
+
+func levenshtein(s, t []rune) int {
+	if len(s) < len(t) {
+		s, t = t, s
+	}
+	if len(t) == 0 {
+		return len(s)
+	}
+
+	// single row of the distance matrix, indexed by position in the shorter string
+	row := make([]int, len(t)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(t); j++ {
+			cur := row[j]
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			row[j] = min(row[j]+1, row[j-1]+1, prev+cost)
+			prev = cur
+		}
+	}
+
+	return row[len(t)]
+}
+
+const (
+	_JW_PREFIX_SCALE = 0.1
+	_JW_MAX_PREFIX   = 4
+)
+
+func jaroWinkler(s, t []rune) float64 {
+	if len(s) == 0 && len(t) == 0 {
+		return 1.0
+	} else if len(s) == 0 || len(t) == 0 {
+		return 0.0
+	}
+
+	window := max(len(s), len(t))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	sMatched := make([]bool, len(s))
+	tMatched := make([]bool, len(t))
+	matches := 0
+	for i := range s {
+		lo := max(0, i-window)
+		hi := min(len(t), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i] = true
+				tMatched[j] = true
+				matches++
+				break
+			}
+		}
+	}
+
+	if matches == 0 {
+		return 0.0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions/2))/m) / 3.0
+
+	prefix := 0
+	for prefix < _JW_MAX_PREFIX && prefix < len(s) && prefix < len(t) && s[prefix] == t[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*_JW_PREFIX_SCALE*(1.0-jaro)
+}
+
+/*
+Trigrams are extracted per word, after lowercasing. Each word is padded
+with two spaces in front and one at the end, so that short words and
+word boundaries contribute to the similarity.
+*/
+func trigrams(s string) map[string]bool {
+	rv := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, w := range words {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			rv[string(padded[i:i+3])] = true
+		}
+	}
+
+	return rv
+}
+
+var _SOUNDEX_CODES = [26]byte{
+	'0', '1', '2', '3', '0', '1', '2', // A-G
+	'0', '0', '2', '2', '4', '5', '5', // H-N
+	'0', '1', '2', '6', '2', '3', '0', // O-U
+	'1', '0', '2', '0', '2', // V-Z
+}
+
+func soundex(s string) string {
+	var rv [4]byte
+	n := 0
+	var last byte
+
+	for _, r := range strings.ToUpper(s) {
+		if r < 'A' || r > 'Z' {
+			continue
+		}
+
+		c := byte(r)
+		code := _SOUNDEX_CODES[c-'A']
+		if n == 0 {
+			rv[0] = c
+			n++
+			last = code
+			continue
+		}
+
+		switch {
+		case c == 'H' || c == 'W':
+			// H and W do not separate letters with the same code
+		case code == '0':
+			last = code
+		case code != last:
+			rv[n] = code
+			n++
+			last = code
+		}
+
+		if n == len(rv) {
+			break
+		}
+	}
+
+	if n == 0 {
+		return ""
+	}
+	for ; n < len(rv); n++ {
+		rv[n] = '0'
+	}
+	return string(rv[:])
+}
+
+func isVowel(c byte) bool {
+	return c == 'A' || c == 'E' || c == 'I' || c == 'O' || c == 'U'
+}
+
+/*
+Original Metaphone algorithm by Lawrence Philips. Non-letters are ignored.
+*/
+func metaphone(s string) string {
+	w := make([]byte, 0, len(s))
+	for _, r := range strings.ToUpper(s) {
+		if r >= 'A' && r <= 'Z' {
+			w = append(w, byte(r))
+		}
+	}
+	if len(w) == 0 {
+		return ""
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+		return w[i]
+	}
+	frontV := func(c byte) bool {
+		return c == 'E' || c == 'I' || c == 'Y'
+	}
+
+	var b strings.Builder
+	i := 0
+
+	// initial letter exceptions
+	switch {
+	case (w[0] == 'A' && at(1) == 'E') ||
+		(w[0] == 'G' && at(1) == 'N') ||
+		(w[0] == 'K' && at(1) == 'N') ||
+		(w[0] == 'P' && at(1) == 'N') ||
+		(w[0] == 'W' && at(1) == 'R'):
+		i = 1
+	case w[0] == 'X':
+		b.WriteByte('S')
+		i = 1
+	case w[0] == 'W' && at(1) == 'H':
+		b.WriteByte('W')
+		i = 2
+	}
+
+	for ; i < len(w); i++ {
+		c := w[i]
+
+		// drop duplicate adjacent letters, except C
+		if c != 'C' && i > 0 && w[i-1] == c {
+			continue
+		}
+
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				b.WriteByte(c)
+			}
+		case 'B':
+			// silent in a trailing MB
+			if !(i == len(w)-1 && at(i-1) == 'M') {
+				b.WriteByte('B')
+			}
+		case 'C':
+			if at(i-1) == 'S' && frontV(at(i+1)) {
+				// SCI, SCE, SCY: silent
+			} else if at(i+1) == 'I' && at(i+2) == 'A' {
+				b.WriteByte('X')
+			} else if at(i+1) == 'H' {
+				if at(i-1) == 'S' {
+					b.WriteByte('K')
+				} else {
+					b.WriteByte('X')
+				}
+				i++
+			} else if frontV(at(i + 1)) {
+				b.WriteByte('S')
+			} else {
+				b.WriteByte('K')
+			}
+		case 'D':
+			if at(i+1) == 'G' && frontV(at(i+2)) {
+				b.WriteByte('J')
+				i += 2
+			} else {
+				b.WriteByte('T')
+			}
+		case 'G':
+			if at(i+1) == 'H' && i+2 < len(w) && !isVowel(at(i+2)) {
+				// GH not followed by a vowel is silent
+			} else if at(i+1) == 'N' && (i+2 == len(w) ||
+				(at(i+2) == 'E' && at(i+3) == 'D' && i+4 == len(w))) {
+				// silent in trailing GN and GNED
+			} else if frontV(at(i+1)) && at(i-1) != 'G' {
+				b.WriteByte('J')
+			} else {
+				b.WriteByte('K')
+			}
+		case 'H':
+			if isVowel(at(i+1)) && !strings.ContainsRune("CSPTG", rune(at(i-1))) {
+				b.WriteByte('H')
+			}
+		case 'K':
+			if at(i-1) != 'C' {
+				b.WriteByte('K')
+			}
+		case 'P':
+			if at(i+1) == 'H' {
+				b.WriteByte('F')
+			} else {
+				b.WriteByte('P')
+			}
+		case 'Q':
+			b.WriteByte('K')
+		case 'S':
+			if at(i+1) == 'H' {
+				b.WriteByte('X')
+				i++
+			} else if at(i+1) == 'I' && (at(i+2) == 'O' || at(i+2) == 'A') {
+				b.WriteByte('X')
+			} else {
+				b.WriteByte('S')
+			}
+		case 'T':
+			if at(i+1) == 'I' && (at(i+2) == 'O' || at(i+2) == 'A') {
+				b.WriteByte('X')
+			} else if at(i+1) == 'H' {
+				b.WriteByte('0')
+				i++
+			} else if !(at(i+1) == 'C' && at(i+2) == 'H') {
+				b.WriteByte('T')
+			}
+		case 'V':
+			b.WriteByte('F')
+		case 'W', 'Y':
+			if isVowel(at(i + 1)) {
+				b.WriteByte(c)
+			}
+		case 'X':
+			b.WriteString("KS")
+		case 'Z':
+			b.WriteByte('S')
+		case 'F', 'J', 'L', 'M', 'N', 'R':
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
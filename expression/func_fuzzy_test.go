@@ -0,0 +1,87 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package expression
+
+import (
+	"math"
+	"testing"
+
+	"github.com/couchbase/query/value"
+)
+
+func testFuzzy(f Function, er value.Value, t *testing.T) {
+	rv, err := f.Evaluate(nil, nil)
+	if err != nil {
+		t.Errorf("%s: received error %v", f.String(), err)
+		return
+	}
+	if er.Type() == value.NUMBER && rv.Type() == value.NUMBER {
+		e := value.AsNumberValue(er).Float64()
+		r := value.AsNumberValue(rv).Float64()
+		if math.Abs(e-r) > 1e-9 {
+			t.Errorf("%s: mismatch received %v expected %v", f.String(), r, e)
+		}
+	} else if er.Collate(rv) != 0 {
+		t.Errorf("%s: mismatch received %v expected %v", f.String(), rv.Actual(), er.Actual())
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	testFuzzy(NewLevenshtein(NewConstant("kitten"), NewConstant("sitting")), value.NewValue(3), t)
+	testFuzzy(NewLevenshtein(NewConstant(""), NewConstant("abc")), value.NewValue(3), t)
+	testFuzzy(NewLevenshtein(NewConstant("flaw"), NewConstant("lawn")), value.NewValue(2), t)
+	testFuzzy(NewLevenshtein(NewConstant("café"), NewConstant("cafe")), value.NewValue(1), t)
+	testFuzzy(NewLevenshtein(NewConstant(1), NewConstant("abc")), value.NULL_VALUE, t)
+	testFuzzy(NewLevenshtein(NewConstant(value.MISSING_VALUE), NewConstant(1)), value.MISSING_VALUE, t)
+}
+
+func TestJaroWinkler(t *testing.T) {
+	testFuzzy(NewJaroWinkler(NewConstant("MARTHA"), NewConstant("MARHTA")), value.NewValue(0.9611111111111111), t)
+	testFuzzy(NewJaroWinkler(NewConstant("DWAYNE"), NewConstant("DUANE")), value.NewValue(0.84), t)
+	testFuzzy(NewJaroWinkler(NewConstant("abc"), NewConstant("abc")), value.NewValue(1.0), t)
+	testFuzzy(NewJaroWinkler(NewConstant("abc"), NewConstant("xyz")), value.NewValue(0.0), t)
+}
+
+func TestSoundex(t *testing.T) {
+	cases := map[string]string{
+		"Robert":   "R163",
+		"Rupert":   "R163",
+		"Rubin":    "R150",
+		"Ashcraft": "A261",
+		"Tymczak":  "T522",
+		"Pfister":  "P236",
+		"Honeyman": "H555",
+		"123":      "",
+	}
+	for in, out := range cases {
+		testFuzzy(NewSoundex(NewConstant(in)), value.NewValue(out), t)
+	}
+}
+
+func TestMetaphone(t *testing.T) {
+	cases := map[string]string{
+		"knight":     "NT",
+		"Thumb":      "0M",
+		"Wright":     "RT",
+		"science":    "SNS",
+		"philosophy": "FLSF",
+		"Xavier":     "SFR",
+	}
+	for in, out := range cases {
+		testFuzzy(NewMetaphone(NewConstant(in)), value.NewValue(out), t)
+	}
+}
+
+func TestTrigrams(t *testing.T) {
+	testFuzzy(NewTrigrams(NewConstant("Word")),
+		value.NewValue([]interface{}{"  w", " wo", "ord", "rd ", "wor"}), t)
+	testFuzzy(NewSimilarity(NewConstant("word"), NewConstant("word")), value.NewValue(1.0), t)
+	testFuzzy(NewSimilarity(NewConstant("word"), NewConstant("two words")), value.NewValue(4.0/11.0), t)
+	testFuzzy(NewSimilarity(NewConstant("abc"), NewConstant("xyz")), value.NewValue(0.0), t)
+}
@@ -1146,3 +1146,66 @@ func (this *SanitizeStatement) MaxArgs() int {
 func (this *SanitizeStatement) MinArgs() int {
 	return 1
 }
+
+// FormatPlan
+
+// Renders a plan, such as that of an entry in system:prepareds or system:completed_requests,
+// as TEXT, DOT or JSON. The default is TEXT.
+type FormatPlan struct {
+	FunctionBase
+}
+
+func NewFormatPlan(operands ...Expression) Function {
+	rv := &FormatPlan{}
+	rv.Init("format_plan", operands...)
+	rv.expr = rv
+	return rv
+}
+
+func (this *FormatPlan) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *FormatPlan) Type() value.Type { return value.STRING }
+
+func (this *FormatPlan) Evaluate(item value.Value, context Context) (value.Value, error) {
+	p, err := this.operands[0].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	} else if p.Type() == value.MISSING {
+		return value.MISSING_VALUE, nil
+	} else if p.Type() != value.OBJECT {
+		return value.NULL_VALUE, nil
+	}
+
+	format := "text"
+	if len(this.operands) > 1 {
+		f, err := this.operands[1].Evaluate(item, context)
+		if err != nil {
+			return nil, err
+		} else if f.Type() == value.MISSING {
+			return value.MISSING_VALUE, nil
+		} else if f.Type() != value.STRING {
+			return value.NULL_VALUE, nil
+		}
+		format = f.ToString()
+	}
+
+	ctx, ok := context.(PlanFormatContext)
+	if !ok {
+		return value.NULL_VALUE, nil
+	}
+	rv, err := ctx.FormatPlan(p, format)
+	if err != nil {
+		return nil, errors.NewEvaluationError(err, "format_plan")
+	}
+	return value.NewValue(rv), nil
+}
+
+func (this *FormatPlan) Constructor() FunctionConstructor {
+	return NewFormatPlan
+}
+
+func (this *FormatPlan) MinArgs() int { return 1 }
+
+func (this *FormatPlan) MaxArgs() int { return 2 }
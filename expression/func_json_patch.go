@@ -0,0 +1,418 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package expression
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/value"
+)
+
+///////////////////////////////////////////////////
+//
+// JSONPatch
+//
+///////////////////////////////////////////////////
+
+/*
+This represents the json function JSON_PATCH(expr, patch). It returns
+a copy of expr with the RFC 6902 JSON Patch applied. The patch is an
+array of add, remove, replace, move, copy and test operations, which are
+applied atomically: if any operation fails, an error is raised rather
+than returning a partially patched value, so that statements such as
+UPDATE ... SET d = JSON_PATCH(d, $p) never store a half applied patch.
+*/
+type JSONPatch struct {
+	BinaryFunctionBase
+}
+
+func NewJSONPatch(first, second Expression) Function {
+	rv := &JSONPatch{}
+	rv.Init("json_patch", first, second)
+
+	rv.expr = rv
+	return rv
+}
+
+/*
+Visitor pattern.
+*/
+func (this *JSONPatch) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *JSONPatch) Type() value.Type { return value.JSON }
+
+func (this *JSONPatch) Evaluate(item value.Value, context Context) (value.Value, error) {
+	first, err := this.operands[0].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	}
+	second, err := this.operands[1].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	}
+
+	if first.Type() == value.MISSING || second.Type() == value.MISSING {
+		return value.MISSING_VALUE, nil
+	} else if second.Type() != value.ARRAY {
+		return value.NULL_VALUE, nil
+	}
+
+	ops, _ := second.Actual().([]interface{})
+	doc := first.CopyForUpdate()
+	for i, o := range ops {
+		doc, err = applyPatchOp(doc, i, value.NewValue(o))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+/*
+Factory method pattern.
+*/
+func (this *JSONPatch) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewJSONPatch(operands[0], operands[1])
+	}
+}
+
+func applyPatchOp(doc value.Value, index int, op value.Value) (value.Value, error) {
+	if op.Type() != value.OBJECT {
+		return nil, errors.NewJSONPatchInvalidError(index, "operation is not an object")
+	}
+
+	name, err := patchMember(op, index, "op")
+	if err != nil {
+		return nil, err
+	}
+	path, err := patchMember(op, index, "path")
+	if err != nil {
+		return nil, err
+	}
+	ptr, ok := parseJSONPointer(path)
+	if !ok {
+		return nil, errors.NewJSONPatchInvalidError(index, "invalid \"path\" '"+path+"'")
+	}
+
+	fail := func(reason string) error {
+		return errors.NewJSONPatchFailedError(index, name, path, reason)
+	}
+
+	var reason string
+
+	switch name {
+	case "add", "replace", "test":
+		val, ok := op.Field("value")
+		if !ok {
+			return nil, errors.NewJSONPatchInvalidError(index, "missing \"value\"")
+		}
+		if name == "test" {
+			cur, ok := jsonPointerGet(doc, ptr)
+			if !ok {
+				return nil, fail("path does not exist")
+			} else if cur.Type() != val.Type() || cur.Collate(val) != 0 {
+				return nil, fail("value does not match")
+			}
+			return doc, nil
+		}
+		doc, reason = jsonPointerSet(doc, ptr, val.CopyForUpdate(), name == "add")
+		if reason != "" {
+			return nil, fail(reason)
+		}
+		return doc, nil
+
+	case "remove":
+		doc, _, reason = jsonPointerRemove(doc, ptr)
+		if reason != "" {
+			return nil, fail(reason)
+		}
+		return doc, nil
+
+	case "move", "copy":
+		from, err := patchMember(op, index, "from")
+		if err != nil {
+			return nil, err
+		}
+		fptr, ok := parseJSONPointer(from)
+		if !ok {
+			return nil, errors.NewJSONPatchInvalidError(index, "invalid \"from\" '"+from+"'")
+		}
+
+		var val value.Value
+		if name == "move" {
+			if isJSONPointerPrefix(fptr, ptr) && len(fptr) < len(ptr) {
+				return nil, fail("cannot move a value into one of its children")
+			}
+			doc, val, reason = jsonPointerRemove(doc, fptr)
+		} else {
+			val, ok = jsonPointerGet(doc, fptr)
+			if !ok {
+				reason = "\"from\" path does not exist"
+			} else {
+				val = val.CopyForUpdate()
+			}
+		}
+		if reason != "" {
+			return nil, fail(reason)
+		}
+
+		doc, reason = jsonPointerSet(doc, ptr, val, true)
+		if reason != "" {
+			return nil, fail(reason)
+		}
+		return doc, nil
+	}
+
+	return nil, errors.NewJSONPatchInvalidError(index, "unknown operation \""+name+"\"")
+}
+
+func patchMember(op value.Value, index int, member string) (string, error) {
+	v, ok := op.Field(member)
+	if !ok || v.Type() != value.STRING {
+		return "", errors.NewJSONPatchInvalidError(index, "missing or non-string \""+member+"\"")
+	}
+	return v.ToString(), nil
+}
+
+///////////////////////////////////////////////////
+//
+// JSONMergePatch
+//
+///////////////////////////////////////////////////
+
+/*
+This represents the json function JSON_MERGE_PATCH(expr, patch). It
+returns a copy of expr with the RFC 7386 JSON Merge Patch applied:
+object members of the patch are merged recursively, null members are
+removed, and any other patch value replaces the target.
+*/
+type JSONMergePatch struct {
+	BinaryFunctionBase
+}
+
+func NewJSONMergePatch(first, second Expression) Function {
+	rv := &JSONMergePatch{}
+	rv.Init("json_merge_patch", first, second)
+
+	rv.expr = rv
+	return rv
+}
+
+/*
+Visitor pattern.
+*/
+func (this *JSONMergePatch) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *JSONMergePatch) Type() value.Type { return value.JSON }
+
+func (this *JSONMergePatch) Evaluate(item value.Value, context Context) (value.Value, error) {
+	first, err := this.operands[0].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	}
+	second, err := this.operands[1].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	}
+
+	if first.Type() == value.MISSING || second.Type() == value.MISSING {
+		return value.MISSING_VALUE, nil
+	}
+
+	return mergePatch(first.CopyForUpdate(), second), nil
+}
+
+/*
+Factory method pattern.
+*/
+func (this *JSONMergePatch) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewJSONMergePatch(operands[0], operands[1])
+	}
+}
+
+/*
+The target has already been copied and is modified in place.
+*/
+func mergePatch(target, patch value.Value) value.Value {
+	if patch.Type() != value.OBJECT {
+		return patch.CopyForUpdate()
+	}
+
+	if target.Type() != value.OBJECT {
+		target = value.NewValue(map[string]interface{}{})
+	}
+
+	for name, p := range patch.Fields() {
+		pv := value.NewValue(p)
+		if pv.Type() == value.NULL {
+			target.UnsetField(name)
+			continue
+		}
+
+		cur, ok := target.Field(name)
+		if !ok {
+			cur = value.NULL_VALUE
+		}
+		target.SetField(name, mergePatch(cur, pv))
+	}
+
+	return target
+}
+
+/*
+RFC 6901 JSON Pointers. The document passed to the helpers below has
+already been copied, so that containers may be modified in place.
+*/
+func parseJSONPointer(ptr string) ([]string, bool) {
+	if ptr == "" {
+		return nil, true
+	} else if ptr[0] != '/' {
+		return nil, false
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, true
+}
+
+func isJSONPointerPrefix(prefix, ptr []string) bool {
+	if len(prefix) > len(ptr) {
+		return false
+	}
+	for i, t := range prefix {
+		if ptr[i] != t {
+			return false
+		}
+	}
+	return true
+}
+
+func jsonPointerIndex(token string, length int, allowEnd bool) (int, bool) {
+	if token == "-" && allowEnd {
+		return length, true
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > length || (i == length && !allowEnd) {
+		return 0, false
+	}
+	return i, true
+}
+
+func jsonPointerGet(doc value.Value, ptr []string) (value.Value, bool) {
+	cur := doc
+	for _, t := range ptr {
+		switch cur.Type() {
+		case value.OBJECT:
+			v, ok := cur.Field(t)
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case value.ARRAY:
+			arr, _ := cur.Actual().([]interface{})
+			i, ok := jsonPointerIndex(t, len(arr), false)
+			if !ok {
+				return nil, false
+			}
+			cur = value.NewValue(arr[i])
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+/*
+Set the value at ptr, returning the (possibly new) document. For arrays,
+add inserts before the index (or appends for "-") while replace
+overwrites; for objects both set the member, but replace requires that
+it already exists. A non-empty string is returned if the operation fails.
+*/
+func jsonPointerSet(doc value.Value, ptr []string, val value.Value, add bool) (value.Value, string) {
+	if len(ptr) == 0 {
+		return val, ""
+	}
+
+	parent, ok := jsonPointerGet(doc, ptr[:len(ptr)-1])
+	if !ok {
+		return nil, "path does not exist"
+	}
+
+	last := ptr[len(ptr)-1]
+	switch parent.Type() {
+	case value.OBJECT:
+		if !add {
+			if _, ok := parent.Field(last); !ok {
+				return nil, "path does not exist"
+			}
+		}
+		parent.SetField(last, val)
+		return doc, ""
+	case value.ARRAY:
+		arr, _ := parent.Actual().([]interface{})
+		i, ok := jsonPointerIndex(last, len(arr), add)
+		if !ok {
+			return nil, "array index out of range"
+		}
+		if !add {
+			arr[i] = val
+			return doc, ""
+		}
+		narr := make([]interface{}, 0, len(arr)+1)
+		narr = append(narr, arr[:i]...)
+		narr = append(narr, val)
+		narr = append(narr, arr[i:]...)
+		return jsonPointerSet(doc, ptr[:len(ptr)-1], value.NewValue(narr), false)
+	}
+
+	return nil, "parent is not an object or array"
+}
+
+/*
+Remove the value at ptr, returning the new document and the removed value.
+*/
+func jsonPointerRemove(doc value.Value, ptr []string) (value.Value, value.Value, string) {
+	if len(ptr) == 0 {
+		return nil, nil, "cannot remove the whole document"
+	}
+
+	val, ok := jsonPointerGet(doc, ptr)
+	if !ok {
+		return nil, nil, "path does not exist"
+	}
+	parent, _ := jsonPointerGet(doc, ptr[:len(ptr)-1])
+
+	last := ptr[len(ptr)-1]
+	if parent.Type() == value.OBJECT {
+		parent.UnsetField(last)
+		return doc, val, ""
+	}
+
+	arr, _ := parent.Actual().([]interface{})
+	i, _ := jsonPointerIndex(last, len(arr), false)
+	narr := make([]interface{}, 0, len(arr)-1)
+	narr = append(narr, arr[:i]...)
+	narr = append(narr, arr[i+1:]...)
+	doc, reason := jsonPointerSet(doc, ptr[:len(ptr)-1], value.NewValue(narr), false)
+	return doc, val, reason
+}
@@ -0,0 +1,108 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/value"
+)
+
+func testJSONFunc(f Function, er string, fail bool, t *testing.T) {
+	rv, err := f.Evaluate(nil, nil)
+	if fail {
+		if err == nil {
+			t.Errorf("%s: expected error, received %v", f.String(), rv)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("%s: received error %v", f.String(), err)
+		return
+	}
+	ev := value.NewValue([]byte(er))
+	if ev.Collate(rv) != 0 {
+		t.Errorf("%s: mismatch received %v expected %v", f.String(), rv, ev)
+	}
+}
+
+func jsonConstant(s string) Expression {
+	return NewConstant(value.NewValue([]byte(s)))
+}
+
+func TestJSONPatch(t *testing.T) {
+	cases := []struct {
+		doc, patch, er string
+		fail           bool
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`, false},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`, false},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc"]}]`, `{"foo":["bar",["abc"]]}`, false},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`, false},
+		{`{"baz":"qux"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo"}`, false},
+		{`{"foo":{"waldo":"fred"},"qux":{}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{},"qux":{"thud":"fred"}}`, false},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			`{"foo":["all","cows","eat","grass"]}`, false},
+		{`{"a":[1,2]}`, `[{"op":"copy","from":"/a","path":"/b"},{"op":"add","path":"/b/0","value":0}]`,
+			`{"a":[1,2],"b":[0,1,2]}`, false},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`, false},
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ``, true},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, ``, true},
+		{`{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/b"}]`, ``, true},
+		{`{"a":[1]}`, `[{"op":"replace","path":"/a/1","value":2}]`, ``, true},
+		{`{"a":1}`, `[{"op":"frob","path":""}]`, ``, true},
+		{`{"a":1}`, `{"op":"add"}`, `null`, false},
+	}
+
+	for _, c := range cases {
+		testJSONFunc(NewJSONPatch(jsonConstant(c.doc), jsonConstant(c.patch)), c.er, c.fail, t)
+	}
+}
+
+func TestJSONMergePatch(t *testing.T) {
+	cases := [][3]string{
+		{`{"a":"b","c":{"d":"e","f":"g"}}`, `{"a":"z","c":{"f":null}}`, `{"a":"z","c":{"d":"e"}}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`["a","b"]`, `{"a":"b"}`, `{"a":"b"}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"a":"foo"}`, `null`, `null`},
+	}
+
+	for _, c := range cases {
+		testJSONFunc(NewJSONMergePatch(jsonConstant(c[0]), jsonConstant(c[1])), c[2], false, t)
+	}
+}
+
+func TestJSONPathQuery(t *testing.T) {
+	doc := `{"store": {"book": [
+		{"category": "reference", "author": "Nigel Rees", "price": 8.95},
+		{"category": "fiction", "author": "Evelyn Waugh", "price": 12.99},
+		{"category": "fiction", "author": "Herman Melville", "isbn": "0-553-21311-3", "price": 8.99}],
+		"bicycle": {"color": "red", "price": 399}}}`
+
+	cases := [][2]string{
+		{`$.store.book[*].author`, `["Nigel Rees","Evelyn Waugh","Herman Melville"]`},
+		{`$..book[-1].price`, `[8.99]`},
+		{`$.store..price`, `[399,8.95,12.99,8.99]`},
+		{`$..book[?@.isbn].author`, `["Herman Melville"]`},
+		{`$..book[?@.price < 10 && @.category == 'fiction'].author`, `["Herman Melville"]`},
+		{`$..book[?search(@.author, "^[EH]")].author`, `["Evelyn Waugh","Herman Melville"]`},
+		{`$.store.book[::-2].price`, `[8.99,8.95]`},
+		{`$.store.book[*].price ? (@ > 10)`, `[12.99]`},
+		{`$.missing`, `[]`},
+	}
+
+	for _, c := range cases {
+		testJSONFunc(NewJSONPathQuery(jsonConstant(doc), NewConstant(c[0])), c[1], false, t)
+	}
+
+	testJSONFunc(NewJSONPathQuery(jsonConstant(doc), NewConstant(`$.a[?count(1) == 1]`)), ``, true, t)
+}
@@ -0,0 +1,94 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package expression
+
+import (
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression/jsonpath"
+	"github.com/couchbase/query/value"
+)
+
+///////////////////////////////////////////////////
+//
+// JSONPathQuery
+//
+///////////////////////////////////////////////////
+
+/*
+This represents the json function JSON_PATH_QUERY(expr, path). It
+returns the array of values selected from expr by the RFC 9535
+JSONPath query path, in document order. An empty array is returned
+if nothing matches.
+*/
+type JSONPathQuery struct {
+	BinaryFunctionBase
+	path *jsonpath.Path
+}
+
+func NewJSONPathQuery(first, second Expression) Function {
+	rv := &JSONPathQuery{}
+	rv.Init("json_path_query", first, second)
+
+	if pv := second.Value(); pv != nil && pv.Type() == value.STRING {
+		rv.path, _ = jsonpath.Parse(pv.ToString())
+	}
+	rv.expr = rv
+	return rv
+}
+
+/*
+Visitor pattern.
+*/
+func (this *JSONPathQuery) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *JSONPathQuery) Type() value.Type { return value.ARRAY }
+
+func (this *JSONPathQuery) Evaluate(item value.Value, context Context) (value.Value, error) {
+	first, err := this.operands[0].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	}
+	second, err := this.operands[1].Evaluate(item, context)
+	if err != nil {
+		return nil, err
+	}
+
+	if first.Type() == value.MISSING || second.Type() == value.MISSING {
+		return value.MISSING_VALUE, nil
+	} else if second.Type() != value.STRING {
+		return value.NULL_VALUE, nil
+	}
+
+	path := this.path
+	if path == nil {
+		path, err = jsonpath.Parse(second.ToString())
+		if err != nil {
+			return nil, errors.NewJSONPathInvalidError(second.ToString(), err)
+		}
+	}
+
+	nodes := path.Query(first)
+	rv := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		rv[i] = n
+	}
+
+	return value.NewValue(rv), nil
+}
+
+/*
+Factory method pattern.
+*/
+func (this *JSONPathQuery) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewJSONPathQuery(operands[0], operands[1])
+	}
+}
@@ -0,0 +1,167 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package expression
+
+import (
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression/jsonschema"
+	"github.com/couchbase/query/value"
+)
+
+///////////////////////////////////////////////////
+//
+// JSONSchemaValid
+//
+///////////////////////////////////////////////////
+
+/*
+This represents the json function JSON_SCHEMA_VALID(schema, expr). It
+returns true if expr conforms to the JSON schema, and false otherwise.
+*/
+type JSONSchemaValid struct {
+	BinaryFunctionBase
+	schema *jsonschema.Schema
+}
+
+func NewJSONSchemaValid(first, second Expression) Function {
+	rv := &JSONSchemaValid{}
+	rv.Init("json_schema_valid", first, second)
+
+	rv.schema = constantSchema(first)
+	rv.expr = rv
+	return rv
+}
+
+/*
+Visitor pattern.
+*/
+func (this *JSONSchemaValid) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *JSONSchemaValid) Type() value.Type { return value.BOOLEAN }
+
+func (this *JSONSchemaValid) Evaluate(item value.Value, context Context) (value.Value, error) {
+	schema, doc, rv, err := evaluateSchema(this.schema, this.operands, item, context)
+	if rv != nil || err != nil {
+		return rv, err
+	}
+
+	return value.NewValue(schema.Valid(doc)), nil
+}
+
+/*
+Factory method pattern.
+*/
+func (this *JSONSchemaValid) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewJSONSchemaValid(operands[0], operands[1])
+	}
+}
+
+///////////////////////////////////////////////////
+//
+// JSONSchemaErrors
+//
+///////////////////////////////////////////////////
+
+/*
+This represents the json function JSON_SCHEMA_ERRORS(schema, expr). It
+returns an array of objects, one for each value in expr that does not
+conform to the JSON schema, giving the JSON Pointer of the value, the
+failing schema keyword and a message. The array is empty if expr
+conforms to the schema.
+*/
+type JSONSchemaErrors struct {
+	BinaryFunctionBase
+	schema *jsonschema.Schema
+}
+
+func NewJSONSchemaErrors(first, second Expression) Function {
+	rv := &JSONSchemaErrors{}
+	rv.Init("json_schema_errors", first, second)
+
+	rv.schema = constantSchema(first)
+	rv.expr = rv
+	return rv
+}
+
+/*
+Visitor pattern.
+*/
+func (this *JSONSchemaErrors) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *JSONSchemaErrors) Type() value.Type { return value.ARRAY }
+
+func (this *JSONSchemaErrors) Evaluate(item value.Value, context Context) (value.Value, error) {
+	schema, doc, rv, err := evaluateSchema(this.schema, this.operands, item, context)
+	if rv != nil || err != nil {
+		return rv, err
+	}
+
+	failures := schema.Validate(doc, 0)
+	res := make([]interface{}, len(failures))
+	for i, f := range failures {
+		res[i] = f.Value()
+	}
+	return value.NewValue(res), nil
+}
+
+/*
+Factory method pattern.
+*/
+func (this *JSONSchemaErrors) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewJSONSchemaErrors(operands[0], operands[1])
+	}
+}
+
+/*
+Constant schemas are compiled once, when the function is created.
+*/
+func constantSchema(expr Expression) *jsonschema.Schema {
+	if sv := expr.Value(); sv != nil {
+		schema, _ := jsonschema.Compile(sv)
+		return schema
+	}
+	return nil
+}
+
+/*
+Evaluate the operands of the schema functions. A non-nil rv is the
+function result when either operand is MISSING or NULL.
+*/
+func evaluateSchema(schema *jsonschema.Schema, operands Expressions, item value.Value, context Context) (
+	*jsonschema.Schema, value.Value, value.Value, error) {
+
+	first, err := operands[0].Evaluate(item, context)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	second, err := operands[1].Evaluate(item, context)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if first.Type() == value.MISSING || second.Type() == value.MISSING {
+		return nil, nil, value.MISSING_VALUE, nil
+	} else if first.Type() == value.NULL {
+		return nil, nil, value.NULL_VALUE, nil
+	}
+
+	if schema == nil {
+		schema, err = jsonschema.Compile(first)
+		if err != nil {
+			return nil, nil, nil, errors.NewJSONSchemaInvalidError(err)
+		}
+	}
+	return schema, second, nil, nil
+}
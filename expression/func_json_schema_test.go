@@ -0,0 +1,65 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package expression
+
+import (
+	"testing"
+)
+
+const _TEST_SCHEMA = `{
+	"$defs": {"positive": {"type": "integer", "minimum": 1}},
+	"type": "object",
+	"required": ["id", "name"],
+	"properties": {
+		"id": {"$ref": "#/$defs/positive"},
+		"name": {"type": "string", "minLength": 2, "pattern": "^[A-Z]"},
+		"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+		"kind": {"enum": ["a", "b"]},
+		"alt": {"oneOf": [{"type": "string"}, {"type": "number"}]}
+	},
+	"additionalProperties": false
+}`
+
+func TestJSONSchemaValid(t *testing.T) {
+	cases := [][2]string{
+		{`{"id": 1, "name": "Bob"}`, `true`},
+		{`{"id": 1, "name": "Bob", "tags": ["x", "y"], "kind": "a", "alt": 2}`, `true`},
+		{`{"id": 1.5, "name": "Bob"}`, `false`},
+		{`{"id": 1, "name": "bob"}`, `false`},
+		{`{"id": 1, "name": "Bob", "tags": ["x", "x"]}`, `false`},
+		{`{"id": 1, "name": "Bob", "extra": true}`, `false`},
+		{`{"name": "Bob"}`, `false`},
+		{`[]`, `false`},
+	}
+
+	for _, c := range cases {
+		testJSONFunc(NewJSONSchemaValid(jsonConstant(_TEST_SCHEMA), jsonConstant(c[0])), c[1], false, t)
+	}
+
+	testJSONFunc(NewJSONSchemaValid(jsonConstant(`{"type": "int"}`), jsonConstant(`1`)), ``, true, t)
+	testJSONFunc(NewJSONSchemaValid(jsonConstant(`{"$ref": "http://example.com/s"}`), jsonConstant(`1`)), ``, true, t)
+}
+
+func TestJSONSchemaErrors(t *testing.T) {
+	cases := [][2]string{
+		{`{"id": 1, "name": "Bob"}`, `[]`},
+		{`{"id": 0, "name": "B", "kind": "c", "alt": true}`, `[
+			{"path": "/alt", "keyword": "oneOf", "message": "value matches 0 of the schemas, expected exactly one"},
+			{"path": "/id", "keyword": "minimum", "message": "0 is less than 1"},
+			{"path": "/kind", "keyword": "enum", "message": "value is not one of the allowed values"},
+			{"path": "/name", "keyword": "minLength", "message": "length 1 is less than 2"}]`},
+		{`{"id": 2, "tags": [1]}`, `[
+			{"path": "/name", "keyword": "required", "message": "required property 'name' is missing"},
+			{"path": "/tags/0", "keyword": "type", "message": "expected string, found number"}]`},
+	}
+
+	for _, c := range cases {
+		testJSONFunc(NewJSONSchemaErrors(jsonConstant(_TEST_SCHEMA), jsonConstant(c[0])), c[1], false, t)
+	}
+}
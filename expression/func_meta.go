@@ -596,6 +596,70 @@ func (this *CurrentUsers) Constructor() FunctionConstructor {
 	return func(operands ...Expression) Function { return NewCurrentUsers() }
 }
 
+///////////////////////////////////////////////////
+//
+// CurrentUserInfo
+//
+///////////////////////////////////////////////////
+
+/*
+This represents the function CURRENT_USER_INFO(). It returns an object
+describing the user the query runs as: its id, domain and name, and
+the names of its roles and of the groups it belongs to, with both in
+grants. Row-level security policies use it to find the policies that
+apply to the user.
+*/
+type CurrentUserInfo struct {
+	NullaryFunctionBase
+}
+
+func NewCurrentUserInfo() Function {
+	rv := &CurrentUserInfo{}
+	rv.Init("current_user_info")
+	rv.expr = rv
+	return rv
+}
+
+/*
+Visitor pattern.
+*/
+func (this *CurrentUserInfo) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *CurrentUserInfo) Type() value.Type { return value.OBJECT }
+
+func (this *CurrentUserInfo) Evaluate(item value.Value, context Context) (value.Value, error) {
+	if context == nil {
+		return nil, errors.NewNilEvaluateParamError("context")
+	}
+	if uc, ok := context.(interface {
+		CurrentUserInfo() (value.Value, errors.Error)
+	}); ok {
+		rv, err := uc.CurrentUserInfo()
+		if err != nil {
+			return nil, err
+		}
+		return rv, nil
+	}
+	return value.NULL_VALUE, nil
+}
+
+func (this *CurrentUserInfo) Static() Expression {
+	return this
+}
+
+func (this *CurrentUserInfo) StaticNoVariable() Expression {
+	return this
+}
+
+/*
+Factory method pattern.
+*/
+func (this *CurrentUserInfo) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function { return NewCurrentUserInfo() }
+}
+
 ///////////////////////////////////////////////////
 //
 // DsVersion
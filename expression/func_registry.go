@@ -196,6 +196,15 @@ var _FUNCTIONS = map[string]Function{
 	"urldecode":    &URLDecode{},
 	"urlencode":    &URLEncode{},
 
+	// Fuzzy string matching
+	"edit_distance": &Levenshtein{},
+	"jaro_winkler":  &JaroWinkler{},
+	"levenshtein":   &Levenshtein{},
+	"metaphone":     &Metaphone{},
+	"similarity":    &Similarity{},
+	"soundex":       &Soundex{},
+	"trigrams":      &Trigrams{},
+
 	// Regular expressions
 	"contains_regex":   &RegexpContains{},
 	"contains_regexp":  &RegexpContains{},
@@ -339,13 +348,18 @@ var _FUNCTIONS = map[string]Function{
 	"redact":               &Redact{},
 
 	// JSON
-	"decode_json":  &JSONDecode{},
-	"encode_json":  &JSONEncode{},
-	"encoded_size": &EncodedSize{},
-	"json_decode":  &JSONDecode{},
-	"json_encode":  &JSONEncode{},
-	"pairs":        &Pairs{},
-	"poly_length":  &PolyLength{},
+	"decode_json":        &JSONDecode{},
+	"encode_json":        &JSONEncode{},
+	"encoded_size":       &EncodedSize{},
+	"json_decode":        &JSONDecode{},
+	"json_encode":        &JSONEncode{},
+	"json_merge_patch":   &JSONMergePatch{},
+	"json_patch":         &JSONPatch{},
+	"json_path_query":    &JSONPathQuery{},
+	"json_schema_errors": &JSONSchemaErrors{},
+	"json_schema_valid":  &JSONSchemaValid{},
+	"pairs":              &Pairs{},
+	"poly_length":        &PolyLength{},
 
 	// Base64
 	"base64":        &Base64Encode{},
@@ -400,14 +414,15 @@ var _FUNCTIONS = map[string]Function{
 	"abort": &Abort{},
 
 	// Meta
-	"meta":          &Meta{},
-	"min_version":   &MinVersion{},
-	"self":          &Self{},
-	"uuid":          &Uuid{},
-	"version":       &Version{},
-	"current_user":  &CurrentUser{},
-	"current_users": &CurrentUsers{},
-	"ds_version":    &DsVersion{},
+	"meta":              &Meta{},
+	"min_version":       &MinVersion{},
+	"self":              &Self{},
+	"uuid":              &Uuid{},
+	"version":           &Version{},
+	"current_user":      &CurrentUser{},
+	"current_users":     &CurrentUsers{},
+	"current_user_info": &CurrentUserInfo{},
+	"ds_version":        &DsVersion{},
 
 	// Distributed
 	"node_name": &NodeName{},
@@ -477,6 +492,7 @@ var _FUNCTIONS = map[string]Function{
 	"evaluate":           &Evaluate{},
 	"extractddl":         &ExtractDDL{},
 	"finderr":            &Finderr{},
+	"format_plan":        &FormatPlan{},
 	"indexinfo":          &IndexInfo{},
 	"len":                &Len{},
 	"mb_len":             &MBLen{},
@@ -491,6 +507,11 @@ var _FUNCTIONS = map[string]Function{
 	"_timeseries":   &TimeSeries{},
 	"recursive_cte": &RecursiveCte{},
 
+	// Time series
+	"interpolate": &Interpolate{},
+	"locf":        &Locf{},
+	"time_bucket": &TimeBucket{},
+
 	"isvector":               &IsVector{},
 	"vector_distance":        &VectorDistance{},
 	"knn_distance":           &VectorDistance{},
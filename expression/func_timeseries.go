@@ -10,8 +10,12 @@ package expression
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/value"
 )
 
@@ -580,3 +584,256 @@ func (this TimeSeriesRanges) Qualified(dstart, dend int64) bool {
 	return len(this) == 0
 
 }
+
+///////////////////////////////////////////////////
+//
+// TimeBucket
+//
+///////////////////////////////////////////////////
+
+/*
+This represents the function TIME_BUCKET(interval, ts [, origin]). It
+returns the start of the interval-long bucket holding the timestamp ts,
+buckets being aligned on origin, the Unix epoch by default. The interval
+is a duration string such as "15m" or "1d", or a number of milliseconds.
+Timestamps are epoch milliseconds or date strings; the bucket has the
+type, and for strings the format, of ts.
+*/
+type TimeBucket struct {
+	FunctionBase
+}
+
+func NewTimeBucket(operands ...Expression) Function {
+	rv := &TimeBucket{}
+	rv.Init("time_bucket", operands...)
+
+	rv.expr = rv
+	return rv
+}
+
+/*
+Visitor pattern.
+*/
+func (this *TimeBucket) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *TimeBucket) Type() value.Type { return value.JSON }
+
+func (this *TimeBucket) Evaluate(item value.Value, context Context) (value.Value, error) {
+	var args [3]value.Value
+	missing := false
+	null := false
+	for i, op := range this.operands {
+		arg, err := op.Evaluate(item, context)
+		if err != nil {
+			return nil, err
+		} else if arg.Type() == value.MISSING {
+			missing = true
+		} else if arg.Type() == value.NULL {
+			null = true
+		}
+		args[i] = arg
+	}
+	if missing {
+		return value.MISSING_VALUE, nil
+	} else if null {
+		return value.NULL_VALUE, nil
+	}
+
+	interval, ok := TimeBucketInterval(args[0])
+	if !ok {
+		return setWarning(context, errors.W_DATE_INVALID_ARGUMENT, invalidArgInfo(0, args[0]))
+	}
+	origin := 0.0
+	if args[2] != nil {
+		origin, _, ok = timeBucketMillis(args[2])
+		if !ok {
+			return setWarning(context, errors.W_DATE_INVALID_ARGUMENT, invalidArgInfo(2, args[2]))
+		}
+	}
+	ts, format, ok := timeBucketMillis(args[1])
+	if !ok {
+		return setWarning(context, errors.W_DATE_INVALID_ARGUMENT, invalidArgInfo(1, args[1]))
+	}
+
+	return timeBucketValue(origin+math.Floor((ts-origin)/interval)*interval, args[1], format), nil
+}
+
+/*
+Factory method pattern.
+*/
+func (this *TimeBucket) Constructor() FunctionConstructor {
+	return NewTimeBucket
+}
+
+func (this *TimeBucket) MinArgs() int { return 2 }
+func (this *TimeBucket) MaxArgs() int { return 3 }
+
+/*
+The bucket width of TIME_BUCKET() in milliseconds. Go durations are
+accepted, as well as whole days ("d") and weeks ("w").
+*/
+func TimeBucketInterval(v value.Value) (float64, bool) {
+	var rv float64
+	switch v.Type() {
+	case value.NUMBER:
+		rv = value.AsNumberValue(v).Float64()
+	case value.STRING:
+		s := strings.TrimSpace(v.ToString())
+		if d, err := time.ParseDuration(s); err == nil {
+			rv = float64(d / time.Millisecond)
+		} else if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+			f, err := strconv.ParseFloat(s[:n-1], 64)
+			if err != nil {
+				return 0, false
+			}
+			rv = f * 24 * 60 * 60 * 1000
+			if s[n-1] == 'w' {
+				rv *= 7
+			}
+		}
+	}
+	return rv, rv > 0 && !math.IsInf(rv, 0)
+}
+
+/*
+Returns the bucket that is n intervals after bucket, with the same type
+and format.
+*/
+func TimeBucketStep(bucket value.Value, interval float64, n int) (value.Value, bool) {
+	ms, format, ok := timeBucketMillis(bucket)
+	if !ok {
+		return nil, false
+	}
+	return timeBucketValue(ms+float64(n)*interval, bucket, format), true
+}
+
+// Returns the timestamp in milliseconds and, for strings, its format
+func timeBucketMillis(v value.Value) (float64, string, bool) {
+	switch v.Type() {
+	case value.NUMBER:
+		return value.AsNumberValue(v).Float64(), "", true
+	case value.STRING:
+		str := v.ToString()
+		format := formatFromStr(str)
+		t, err, _ := strToTime(str, format)
+		if err != nil {
+			return 0, "", false
+		}
+		return timeToMillis(t), format, true
+	}
+	return 0, "", false
+}
+
+func TimeBucketMillis(v value.Value) (float64, bool) {
+	ms, _, ok := timeBucketMillis(v)
+	return ms, ok
+}
+
+func timeBucketValue(ms float64, like value.Value, format string) value.Value {
+	if like.Type() != value.STRING {
+		return value.NewValue(ms)
+	}
+	t := millisToTime(ms)
+	if lt, err, _ := strToTime(like.ToString(), format); err == nil {
+		t = t.In(lt.Location())
+	}
+	str, err, _ := timeToStr(t, format)
+	if err != nil {
+		return value.NULL_VALUE
+	}
+	return value.NewValue(str)
+}
+
+///////////////////////////////////////////////////
+//
+// Locf
+//
+///////////////////////////////////////////////////
+
+/*
+This represents LOCF(expr), last observation carried forward. In a
+query grouped with GAPFILL, the buckets added to fill gaps take the
+value of expr in the previous bucket of their series; elsewhere it
+returns expr.
+*/
+type Locf struct {
+	UnaryFunctionBase
+}
+
+func NewLocf(operand Expression) Function {
+	rv := &Locf{}
+	rv.Init("locf", operand)
+
+	rv.expr = rv
+	return rv
+}
+
+/*
+Visitor pattern.
+*/
+func (this *Locf) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Locf) Type() value.Type { return this.operands[0].Type() }
+
+func (this *Locf) Evaluate(item value.Value, context Context) (value.Value, error) {
+	return this.operands[0].Evaluate(item, context)
+}
+
+/*
+Factory method pattern.
+*/
+func (this *Locf) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewLocf(operands[0])
+	}
+}
+
+///////////////////////////////////////////////////
+//
+// Interpolate
+//
+///////////////////////////////////////////////////
+
+/*
+This represents INTERPOLATE(expr). In a query grouped with GAPFILL,
+the buckets added to fill gaps take a value of expr linearly
+interpolated between the buckets of their series on either side, when
+both are numbers; elsewhere it returns expr.
+*/
+type Interpolate struct {
+	UnaryFunctionBase
+}
+
+func NewInterpolate(operand Expression) Function {
+	rv := &Interpolate{}
+	rv.Init("interpolate", operand)
+
+	rv.expr = rv
+	return rv
+}
+
+/*
+Visitor pattern.
+*/
+func (this *Interpolate) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitFunction(this)
+}
+
+func (this *Interpolate) Type() value.Type { return this.operands[0].Type() }
+
+func (this *Interpolate) Evaluate(item value.Value, context Context) (value.Value, error) {
+	return this.operands[0].Evaluate(item, context)
+}
+
+/*
+Factory method pattern.
+*/
+func (this *Interpolate) Constructor() FunctionConstructor {
+	return func(operands ...Expression) Function {
+		return NewInterpolate(operands[0])
+	}
+}
@@ -0,0 +1,49 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/value"
+)
+
+func TestTimeBucket(t *testing.T) {
+	cases := []struct {
+		args []interface{}
+		er   interface{}
+	}{
+		{[]interface{}{"15m", 1000000000}, 999900000},
+		{[]interface{}{3600000, 7200001}, 7200000},
+		{[]interface{}{"1h", -1}, -3600000},
+		{[]interface{}{"1h", "2026-10-18T10:42:17Z"}, "2026-10-18T10:00:00Z"},
+		{[]interface{}{"1d", "2026-10-18T10:42:17Z", "2026-01-01T06:00:00Z"}, "2026-10-18T06:00:00Z"},
+		{[]interface{}{"1w", 1000000000, 0}, 604800000},
+		{[]interface{}{"1h", nil}, nil},
+	}
+
+	for _, c := range cases {
+		operands := make(Expressions, len(c.args))
+		for i, a := range c.args {
+			operands[i] = NewConstant(a)
+		}
+		f := NewTimeBucket(operands...)
+		rv, err := f.Evaluate(nil, nil)
+		if err != nil {
+			t.Errorf("%s: received error %v", f.String(), err)
+		} else if ev := value.NewValue(c.er); ev.Collate(rv) != 0 {
+			t.Errorf("%s: mismatch received %v expected %v", f.String(), rv, ev)
+		}
+	}
+
+	rv, ok := TimeBucketStep(value.NewValue("2026-10-18T10:00:00Z"), 3600000, 2)
+	if !ok || rv.ToString() != "2026-10-18T12:00:00Z" {
+		t.Errorf("TimeBucketStep: mismatch received %v", rv)
+	}
+}
@@ -0,0 +1,466 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package jsonpath
+
+import (
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/couchbase/query/value"
+)
+
+type selectorKind int
+
+const (
+	_SEL_NAME = selectorKind(iota)
+	_SEL_WILDCARD
+	_SEL_INDEX
+	_SEL_SLICE
+	_SEL_FILTER
+)
+
+type selector struct {
+	kind   selectorKind
+	name   string
+	index  int
+	start  *int
+	end    *int
+	step   int
+	filter expr
+}
+
+/*
+A segment applies its selectors to each input node (and, for descendant
+segments, to each of its descendants). A segment with a filter and no
+selectors is a trailing filter step, which filters the input nodes
+themselves.
+*/
+type segment struct {
+	descendant bool
+	selectors  []*selector
+	filter     expr
+}
+
+/*
+Query returns the nodelist selected by the path, in document order.
+Object members are visited in name order, so that results are stable.
+*/
+func (this *Path) Query(root value.Value) []value.Value {
+	return evalSegments(this.segments, []value.Value{root}, root)
+}
+
+func evalSegments(segments []*segment, nodes []value.Value, root value.Value) []value.Value {
+	for _, seg := range segments {
+		if len(nodes) == 0 {
+			break
+		}
+
+		var next []value.Value
+		if seg.filter != nil {
+			for _, n := range nodes {
+				if seg.filter.test(n, root) {
+					next = append(next, n)
+				}
+			}
+		} else if seg.descendant {
+			for _, n := range nodes {
+				next = descend(seg.selectors, n, root, next)
+			}
+		} else {
+			for _, n := range nodes {
+				next = applySelectors(seg.selectors, n, root, next)
+			}
+		}
+		nodes = next
+	}
+
+	return nodes
+}
+
+func descend(sels []*selector, node, root value.Value, out []value.Value) []value.Value {
+	out = applySelectors(sels, node, root, out)
+	for _, c := range children(node) {
+		out = descend(sels, c, root, out)
+	}
+	return out
+}
+
+func applySelectors(sels []*selector, node, root value.Value, out []value.Value) []value.Value {
+	for _, sel := range sels {
+		out = sel.apply(node, root, out)
+	}
+	return out
+}
+
+func (this *selector) apply(node, root value.Value, out []value.Value) []value.Value {
+	switch this.kind {
+	case _SEL_NAME:
+		if node.Type() == value.OBJECT {
+			if v, ok := node.Field(this.name); ok {
+				out = append(out, v)
+			}
+		}
+	case _SEL_WILDCARD:
+		out = append(out, children(node)...)
+	case _SEL_INDEX:
+		if arr, ok := array(node); ok {
+			i := this.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				out = append(out, value.NewValue(arr[i]))
+			}
+		}
+	case _SEL_SLICE:
+		if arr, ok := array(node); ok && this.step != 0 {
+			lower, upper := sliceBounds(this.start, this.end, this.step, len(arr))
+			if this.step > 0 {
+				for i := lower; i < upper; i += this.step {
+					out = append(out, value.NewValue(arr[i]))
+				}
+			} else {
+				for i := upper; i > lower; i += this.step {
+					out = append(out, value.NewValue(arr[i]))
+				}
+			}
+		}
+	case _SEL_FILTER:
+		for _, c := range children(node) {
+			if this.filter.test(c, root) {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+/*
+Slice bounds as defined in RFC 9535 section 2.3.4.2.2.
+*/
+func sliceBounds(start, end *int, step, length int) (int, int) {
+	normalize := func(i int) int {
+		if i < 0 {
+			return length + i
+		}
+		return i
+	}
+	clamp := func(i, lo, hi int) int {
+		return max(lo, min(i, hi))
+	}
+
+	if step > 0 {
+		s, e := 0, length
+		if start != nil {
+			s = normalize(*start)
+		}
+		if end != nil {
+			e = normalize(*end)
+		}
+		return clamp(s, 0, length), clamp(e, 0, length)
+	}
+
+	s, e := length-1, -1
+	if start != nil {
+		s = normalize(*start)
+	}
+	if end != nil {
+		e = normalize(*end)
+	}
+	// for negative steps the result is (upper, lower]
+	return clamp(e, -1, length-1), clamp(s, -1, length-1)
+}
+
+func array(node value.Value) ([]interface{}, bool) {
+	if node.Type() != value.ARRAY {
+		return nil, false
+	}
+	arr, ok := node.Actual().([]interface{})
+	return arr, ok
+}
+
+func children(node value.Value) []value.Value {
+	switch node.Type() {
+	case value.ARRAY:
+		arr, _ := node.Actual().([]interface{})
+		rv := make([]value.Value, len(arr))
+		for i, e := range arr {
+			rv[i] = value.NewValue(e)
+		}
+		return rv
+	case value.OBJECT:
+		fields := node.Fields()
+		names := make([]string, 0, len(fields))
+		for n, _ := range fields {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		rv := make([]value.Value, len(names))
+		for i, n := range names {
+			rv[i] = value.NewValue(fields[n])
+		}
+		return rv
+	}
+	return nil
+}
+
+/*
+Logical expressions in filters.
+*/
+type expr interface {
+	test(current, root value.Value) bool
+}
+
+type orExpr struct {
+	left, right expr
+}
+
+func (this *orExpr) test(current, root value.Value) bool {
+	return this.left.test(current, root) || this.right.test(current, root)
+}
+
+type andExpr struct {
+	left, right expr
+}
+
+func (this *andExpr) test(current, root value.Value) bool {
+	return this.left.test(current, root) && this.right.test(current, root)
+}
+
+type notExpr struct {
+	operand expr
+}
+
+func (this *notExpr) test(current, root value.Value) bool {
+	return !this.operand.test(current, root)
+}
+
+/*
+An existence test on a query, or the result of a logical function.
+*/
+type testExpr struct {
+	operand operand
+}
+
+func (this *testExpr) test(current, root value.Value) bool {
+	switch op := this.operand.(type) {
+	case *queryOperand:
+		return len(op.nodes(current, root)) > 0
+	case *functionOperand:
+		b, _ := op.logical(current, root)
+		return b
+	}
+	return false
+}
+
+type compareExpr struct {
+	op          string
+	left, right operand
+}
+
+func (this *compareExpr) test(current, root value.Value) bool {
+	l := this.left.value(current, root)
+	r := this.right.value(current, root)
+
+	switch this.op {
+	case "==":
+		return equal(l, r)
+	case "!=":
+		return !equal(l, r)
+	case "<":
+		return less(l, r)
+	case "<=":
+		return less(l, r) || equal(l, r)
+	case ">":
+		return less(r, l)
+	case ">=":
+		return less(r, l) || equal(l, r)
+	}
+	return false
+}
+
+/*
+Nothing is represented by nil; JSON null by value.NULL_VALUE.
+*/
+func equal(l, r value.Value) bool {
+	if l == nil || r == nil {
+		return l == nil && r == nil
+	}
+	return l.Type() == r.Type() && l.Collate(r) == 0
+}
+
+func less(l, r value.Value) bool {
+	if l == nil || r == nil || l.Type() != r.Type() {
+		return false
+	}
+	switch l.Type() {
+	case value.NUMBER, value.STRING:
+		return l.Collate(r) < 0
+	}
+	return false
+}
+
+/*
+Operands of comparisons and function arguments.
+*/
+type operand interface {
+	value(current, root value.Value) value.Value
+	comparable() bool
+	testable() bool
+}
+
+type literalOperand struct {
+	val interface{}
+}
+
+func (this *literalOperand) value(current, root value.Value) value.Value {
+	return value.NewValue(this.val)
+}
+
+func (this *literalOperand) comparable() bool { return true }
+
+func (this *literalOperand) testable() bool { return false }
+
+type queryOperand struct {
+	relative bool
+	segments []*segment
+}
+
+func (this *queryOperand) nodes(current, root value.Value) []value.Value {
+	start := root
+	if this.relative {
+		start = current
+	}
+	return evalSegments(this.segments, []value.Value{start}, root)
+}
+
+func (this *queryOperand) value(current, root value.Value) value.Value {
+	nodes := this.nodes(current, root)
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	return nil
+}
+
+/*
+Only singular queries, made of name and index selectors, may be compared.
+*/
+func (this *queryOperand) comparable() bool {
+	for _, seg := range this.segments {
+		if seg.descendant || seg.filter != nil || len(seg.selectors) != 1 {
+			return false
+		}
+		if k := seg.selectors[0].kind; k != _SEL_NAME && k != _SEL_INDEX {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *queryOperand) testable() bool { return true }
+
+/*
+Function extensions, RFC 9535 section 2.4.
+*/
+type function struct {
+	nargs   int
+	logical bool
+}
+
+var _FUNCTIONS = map[string]*function{
+	"length": &function{nargs: 1},
+	"count":  &function{nargs: 1},
+	"value":  &function{nargs: 1},
+	"match":  &function{nargs: 2, logical: true},
+	"search": &function{nargs: 2, logical: true},
+}
+
+type functionOperand struct {
+	name string
+	fn   *function
+	args []interface{}
+	re   *regexp.Regexp
+}
+
+func (this *functionOperand) comparable() bool { return !this.fn.logical }
+
+func (this *functionOperand) testable() bool { return this.fn.logical }
+
+func (this *functionOperand) argValue(i int, current, root value.Value) value.Value {
+	if op, ok := this.args[i].(operand); ok {
+		return op.value(current, root)
+	}
+	return nil
+}
+
+func (this *functionOperand) argNodes(i int, current, root value.Value) []value.Value {
+	if q, ok := this.args[i].(*queryOperand); ok {
+		return q.nodes(current, root)
+	}
+	if v := this.argValue(i, current, root); v != nil {
+		return []value.Value{v}
+	}
+	return nil
+}
+
+func (this *functionOperand) value(current, root value.Value) value.Value {
+	switch this.name {
+	case "length":
+		v := this.argValue(0, current, root)
+		if v == nil {
+			return nil
+		}
+		switch v.Type() {
+		case value.STRING:
+			return value.NewValue(utf8.RuneCountInString(v.ToString()))
+		case value.ARRAY:
+			arr, _ := array(v)
+			return value.NewValue(len(arr))
+		case value.OBJECT:
+			return value.NewValue(len(v.Fields()))
+		}
+		return nil
+	case "count":
+		return value.NewValue(len(this.argNodes(0, current, root)))
+	case "value":
+		nodes := this.argNodes(0, current, root)
+		if len(nodes) == 1 {
+			return nodes[0]
+		}
+		return nil
+	}
+
+	// logical functions have no value
+	return nil
+}
+
+func (this *functionOperand) logical(current, root value.Value) (bool, bool) {
+	switch this.name {
+	case "match", "search":
+		s := this.argValue(0, current, root)
+		if s == nil || s.Type() != value.STRING {
+			return false, true
+		}
+		re := this.re
+		if re == nil {
+			p := this.argValue(1, current, root)
+			if p == nil || p.Type() != value.STRING {
+				return false, true
+			}
+			var err error
+			re, err = compileIRegexp(p.ToString(), this.name == "match")
+			if err != nil {
+				return false, true
+			}
+		}
+		return re.MatchString(s.ToString()), true
+	}
+	return false, false
+}
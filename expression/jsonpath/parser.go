@@ -0,0 +1,707 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+/*
+Package jsonpath implements JSONPath queries as specified by RFC 9535.
+
+As an extension, a query may be followed by one or more SQL/JSON style
+filter steps, "? (logical-expr)", which keep the nodes selected so far
+for which the expression, with @ bound to the node, is true:
+
+	$.a[*].b ? (@ > 3)
+
+is equivalent to $.a[*].b[?@ > 3] for scalar b, but applies to the
+selected nodes themselves rather than to their children.
+*/
+package jsonpath
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Maximum nesting of parenthesized and bracketed expressions.
+const _MAX_DEPTH = 64
+
+type Path struct {
+	text     string
+	segments []*segment
+}
+
+func (this *Path) String() string {
+	return this.text
+}
+
+type parser struct {
+	text  string
+	pos   int
+	depth int
+}
+
+type ParseError struct {
+	Pos    int
+	Reason string
+}
+
+func (this *ParseError) Error() string {
+	return fmt.Sprintf("invalid JSONPath at position %d: %s", this.Pos, this.Reason)
+}
+
+/*
+Parse a JSONPath query.
+*/
+func Parse(text string) (*Path, error) {
+	p := &parser{text: text}
+	p.skipSpace()
+	if !p.accept('$') {
+		return nil, p.error("query must start with $")
+	}
+
+	segments, err := p.segments(true)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.text) {
+		return nil, p.error("unexpected %q", p.text[p.pos:])
+	}
+
+	return &Path{text: text, segments: segments}, nil
+}
+
+func (this *parser) error(format string, args ...interface{}) error {
+	return &ParseError{Pos: this.pos, Reason: fmt.Sprintf(format, args...)}
+}
+
+func (this *parser) peek() byte {
+	if this.pos < len(this.text) {
+		return this.text[this.pos]
+	}
+	return 0
+}
+
+func (this *parser) peekAt(n int) byte {
+	if this.pos+n < len(this.text) {
+		return this.text[this.pos+n]
+	}
+	return 0
+}
+
+func (this *parser) accept(c byte) bool {
+	if this.peek() == c {
+		this.pos++
+		return true
+	}
+	return false
+}
+
+func (this *parser) acceptString(s string) bool {
+	if strings.HasPrefix(this.text[this.pos:], s) {
+		this.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (this *parser) skipSpace() {
+	for this.pos < len(this.text) {
+		switch this.text[this.pos] {
+		case ' ', '\t', '\n', '\r':
+			this.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (this *parser) enter() error {
+	this.depth++
+	if this.depth > _MAX_DEPTH {
+		return this.error("expression nested too deeply")
+	}
+	return nil
+}
+
+func (this *parser) leave() {
+	this.depth--
+}
+
+/*
+Parse segments following $ or @. Trailing filter steps are only allowed at
+the top level.
+*/
+func (this *parser) segments(top bool) ([]*segment, error) {
+	var rv []*segment
+	for {
+		save := this.pos
+		this.skipSpace()
+
+		switch {
+		case this.acceptString(".."):
+			seg, err := this.descendant()
+			if err != nil {
+				return nil, err
+			}
+			rv = append(rv, seg)
+		case this.accept('.'):
+			sel, err := this.shorthand()
+			if err != nil {
+				return nil, err
+			}
+			rv = append(rv, &segment{selectors: []*selector{sel}})
+		case this.peek() == '[':
+			sels, err := this.bracketed()
+			if err != nil {
+				return nil, err
+			}
+			rv = append(rv, &segment{selectors: sels})
+		case top && this.peek() == '?':
+			this.pos++
+			this.skipSpace()
+			if !this.accept('(') {
+				return nil, this.error("expected ( after ?")
+			}
+			expr, err := this.logicalExpr()
+			if err != nil {
+				return nil, err
+			}
+			this.skipSpace()
+			if !this.accept(')') {
+				return nil, this.error("expected )")
+			}
+			rv = append(rv, &segment{filter: expr})
+		default:
+			this.pos = save
+			return rv, nil
+		}
+	}
+}
+
+func (this *parser) descendant() (*segment, error) {
+	var sels []*selector
+	var err error
+
+	if this.peek() == '[' {
+		sels, err = this.bracketed()
+	} else {
+		var sel *selector
+		sel, err = this.shorthand()
+		sels = []*selector{sel}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &segment{descendant: true, selectors: sels}, nil
+}
+
+func (this *parser) shorthand() (*selector, error) {
+	if this.accept('*') {
+		return &selector{kind: _SEL_WILDCARD}, nil
+	}
+
+	name := this.memberName()
+	if name == "" {
+		return nil, this.error("expected member name or *")
+	}
+	return &selector{kind: _SEL_NAME, name: name}, nil
+}
+
+func (this *parser) memberName() string {
+	start := this.pos
+	for this.pos < len(this.text) {
+		r, size := utf8.DecodeRuneInString(this.text[this.pos:])
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r >= 0x80 ||
+			(this.pos > start && r >= '0' && r <= '9') {
+			this.pos += size
+		} else {
+			break
+		}
+	}
+	return this.text[start:this.pos]
+}
+
+func (this *parser) bracketed() ([]*selector, error) {
+	if err := this.enter(); err != nil {
+		return nil, err
+	}
+	defer this.leave()
+
+	this.pos++ // '['
+	var rv []*selector
+	for {
+		this.skipSpace()
+		sel, err := this.selector()
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, sel)
+
+		this.skipSpace()
+		if this.accept(']') {
+			return rv, nil
+		} else if !this.accept(',') {
+			return nil, this.error("expected , or ]")
+		}
+	}
+}
+
+func (this *parser) selector() (*selector, error) {
+	c := this.peek()
+	switch {
+	case c == '\'' || c == '"':
+		s, err := this.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return &selector{kind: _SEL_NAME, name: s}, nil
+	case c == '*':
+		this.pos++
+		return &selector{kind: _SEL_WILDCARD}, nil
+	case c == '?':
+		this.pos++
+		expr, err := this.logicalExpr()
+		if err != nil {
+			return nil, err
+		}
+		return &selector{kind: _SEL_FILTER, filter: expr}, nil
+	case c == ':' || c == '-' || (c >= '0' && c <= '9'):
+		return this.indexOrSlice()
+	}
+
+	return nil, this.error("invalid selector")
+}
+
+func (this *parser) indexOrSlice() (*selector, error) {
+	var parts [3]*int
+	n := 0
+	for {
+		this.skipSpace()
+		if c := this.peek(); c == '-' || (c >= '0' && c <= '9') {
+			i, err := this.integer()
+			if err != nil {
+				return nil, err
+			}
+			parts[n] = &i
+		}
+		this.skipSpace()
+		if n < 2 && this.accept(':') {
+			n++
+			continue
+		}
+		break
+	}
+
+	if n == 0 {
+		if parts[0] == nil {
+			return nil, this.error("expected index")
+		}
+		return &selector{kind: _SEL_INDEX, index: *parts[0]}, nil
+	}
+
+	if parts[2] != nil && *parts[2] == 0 {
+		// a step of zero selects nothing
+		return &selector{kind: _SEL_SLICE, start: parts[0], end: parts[1], step: 0}, nil
+	}
+	step := 1
+	if parts[2] != nil {
+		step = *parts[2]
+	}
+	return &selector{kind: _SEL_SLICE, start: parts[0], end: parts[1], step: step}, nil
+}
+
+func (this *parser) integer() (int, error) {
+	start := this.pos
+	this.accept('-')
+	digits := this.pos
+	for c := this.peek(); c >= '0' && c <= '9'; c = this.peek() {
+		this.pos++
+	}
+	s := this.text[start:this.pos]
+	if this.pos == digits || (this.text[digits] == '0' && this.pos-digits > 1) || s == "-0" {
+		return 0, this.error("invalid integer %q", s)
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || i > _MAX_EXACT_INT || i < -_MAX_EXACT_INT {
+		return 0, this.error("integer %q out of range", s)
+	}
+	return int(i), nil
+}
+
+// I-JSON exact integer range
+const _MAX_EXACT_INT = 1<<53 - 1
+
+func (this *parser) stringLiteral() (string, error) {
+	quote := this.text[this.pos]
+	this.pos++
+
+	var b strings.Builder
+	for {
+		if this.pos >= len(this.text) {
+			return "", this.error("unterminated string")
+		}
+		c := this.text[this.pos]
+		switch {
+		case c == quote:
+			this.pos++
+			return b.String(), nil
+		case c == '\\':
+			this.pos++
+			r, err := this.escape(quote)
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+		case c < 0x20:
+			return "", this.error("control character in string")
+		default:
+			b.WriteByte(c)
+			this.pos++
+		}
+	}
+}
+
+func (this *parser) escape(quote byte) (rune, error) {
+	c := this.peek()
+	this.pos++
+	switch c {
+	case 'b':
+		return '\b', nil
+	case 'f':
+		return '\f', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case '/', '\\':
+		return rune(c), nil
+	case 'u':
+		r, err := this.hex4()
+		if err != nil {
+			return 0, err
+		}
+		if utf16.IsSurrogate(r) {
+			if !this.acceptString("\\u") {
+				return 0, this.error("unpaired surrogate")
+			}
+			r2, err := this.hex4()
+			if err != nil {
+				return 0, err
+			}
+			r = utf16.DecodeRune(r, r2)
+			if r == utf8.RuneError {
+				return 0, this.error("invalid surrogate pair")
+			}
+		}
+		return r, nil
+	}
+
+	if c == quote {
+		return rune(c), nil
+	}
+	return 0, this.error("invalid escape")
+}
+
+func (this *parser) hex4() (rune, error) {
+	if this.pos+4 > len(this.text) {
+		return 0, this.error("invalid unicode escape")
+	}
+	r, err := strconv.ParseUint(this.text[this.pos:this.pos+4], 16, 32)
+	if err != nil {
+		return 0, this.error("invalid unicode escape")
+	}
+	this.pos += 4
+	return rune(r), nil
+}
+
+/*
+logical-expr = logical-and-expr *("||" logical-and-expr)
+*/
+func (this *parser) logicalExpr() (expr, error) {
+	if err := this.enter(); err != nil {
+		return nil, err
+	}
+	defer this.leave()
+
+	left, err := this.andExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		this.skipSpace()
+		if !this.acceptString("||") {
+			return left, nil
+		}
+		right, err := this.andExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left, right}
+	}
+}
+
+func (this *parser) andExpr() (expr, error) {
+	left, err := this.basicExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		this.skipSpace()
+		if !this.acceptString("&&") {
+			return left, nil
+		}
+		right, err := this.basicExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left, right}
+	}
+}
+
+/*
+basic-expr = paren-expr / comparison-expr / test-expr
+*/
+func (this *parser) basicExpr() (expr, error) {
+	this.skipSpace()
+	if this.peek() == '!' && this.peekAt(1) != '=' {
+		this.pos++
+		this.skipSpace()
+		if this.accept('(') {
+			e, err := this.parenRest()
+			if err != nil {
+				return nil, err
+			}
+			return &notExpr{e}, nil
+		}
+
+		op, err := this.comparable()
+		if err != nil {
+			return nil, err
+		}
+		if !op.testable() {
+			return nil, this.error("! must be followed by a query or logical function")
+		}
+		return &notExpr{&testExpr{op}}, nil
+	}
+
+	if this.accept('(') {
+		return this.parenRest()
+	}
+
+	left, err := this.comparable()
+	if err != nil {
+		return nil, err
+	}
+
+	this.skipSpace()
+	cmp := this.comparisonOp()
+	if cmp == "" {
+		if !left.testable() {
+			return nil, this.error("expected comparison")
+		}
+		return &testExpr{left}, nil
+	}
+
+	right, err := this.comparable()
+	if err != nil {
+		return nil, err
+	}
+	if !left.comparable() || !right.comparable() {
+		return nil, this.error("operands of %s must be singular queries, literals or value functions", cmp)
+	}
+	return &compareExpr{op: cmp, left: left, right: right}, nil
+}
+
+func (this *parser) parenRest() (expr, error) {
+	e, err := this.logicalExpr()
+	if err != nil {
+		return nil, err
+	}
+	this.skipSpace()
+	if !this.accept(')') {
+		return nil, this.error("expected )")
+	}
+	return e, nil
+}
+
+func (this *parser) comparisonOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if this.acceptString(op) {
+			return op
+		}
+	}
+	return ""
+}
+
+/*
+A comparable is a literal, a filter query (relative or absolute) or a
+function call.
+*/
+func (this *parser) comparable() (operand, error) {
+	this.skipSpace()
+	c := this.peek()
+	switch {
+	case c == '@' || c == '$':
+		this.pos++
+		segs, err := this.segments(false)
+		if err != nil {
+			return nil, err
+		}
+		return &queryOperand{relative: c == '@', segments: segs}, nil
+	case c == '\'' || c == '"':
+		s, err := this.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return &literalOperand{val: s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return this.number()
+	case this.acceptKeyword("true"):
+		return &literalOperand{val: true}, nil
+	case this.acceptKeyword("false"):
+		return &literalOperand{val: false}, nil
+	case this.acceptKeyword("null"):
+		return &literalOperand{val: nil}, nil
+	case c >= 'a' && c <= 'z':
+		return this.function()
+	}
+
+	return nil, this.error("expected literal, query or function")
+}
+
+func (this *parser) acceptKeyword(kw string) bool {
+	if !strings.HasPrefix(this.text[this.pos:], kw) {
+		return false
+	}
+	c := this.peekAt(len(kw))
+	if c == '_' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '(' {
+		return false
+	}
+	this.pos += len(kw)
+	return true
+}
+
+func (this *parser) number() (operand, error) {
+	start := this.pos
+	this.accept('-')
+	for c := this.peek(); (c >= '0' && c <= '9') || c == '.' || c == 'e' || c == 'E' ||
+		((c == '+' || c == '-') && (this.text[this.pos-1] == 'e' || this.text[this.pos-1] == 'E')); c = this.peek() {
+		this.pos++
+	}
+	s := this.text[start:this.pos]
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return nil, this.error("invalid number %q", s)
+	}
+	return &literalOperand{val: f}, nil
+}
+
+func (this *parser) function() (operand, error) {
+	name := ""
+	start := this.pos
+	for c := this.peek(); c == '_' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'); c = this.peek() {
+		this.pos++
+	}
+	name = this.text[start:this.pos]
+
+	fn, ok := _FUNCTIONS[name]
+	if !ok {
+		this.pos = start
+		return nil, this.error("unknown function %s", name)
+	}
+	if !this.accept('(') {
+		return nil, this.error("expected ( after %s", name)
+	}
+	if err := this.enter(); err != nil {
+		return nil, err
+	}
+	defer this.leave()
+
+	var args []interface{}
+	for {
+		this.skipSpace()
+		if len(args) == 0 && this.accept(')') {
+			break
+		}
+
+		var arg interface{}
+		save := this.pos
+		op, err := this.comparable()
+		if err == nil {
+			this.skipSpace()
+			if c := this.peek(); c == ',' || c == ')' {
+				arg = op
+			}
+		}
+		if arg == nil {
+			// logical expression argument
+			this.pos = save
+			e, err := this.logicalExpr()
+			if err != nil {
+				return nil, err
+			}
+			arg = e
+		}
+		args = append(args, arg)
+
+		this.skipSpace()
+		if this.accept(')') {
+			break
+		} else if !this.accept(',') {
+			return nil, this.error("expected , or )")
+		}
+	}
+
+	if len(args) != fn.nargs {
+		return nil, this.error("%s() takes %d arguments", name, fn.nargs)
+	}
+
+	// count() and value() take a nodelist, the others take values
+	for i, arg := range args {
+		if _, ok := arg.(*queryOperand); ok && (name == "count" || name == "value") {
+			continue
+		} else if name == "count" || name == "value" {
+			return nil, this.error("argument of %s() must be a query", name)
+		} else if op, ok := arg.(operand); !ok || !op.comparable() {
+			return nil, this.error("argument %d of %s() must be a singular query, literal or value function", i+1, name)
+		}
+	}
+
+	rv := &functionOperand{name: name, fn: fn, args: args}
+	if name == "match" || name == "search" {
+		// precompile constant patterns
+		if lit, ok := args[1].(*literalOperand); ok {
+			if s, ok := lit.val.(string); ok {
+				re, err := compileIRegexp(s, name == "match")
+				if err != nil {
+					return nil, this.error("invalid regular expression %q", s)
+				}
+				rv.re = re
+			}
+		}
+	}
+	return rv, nil
+}
+
+func compileIRegexp(s string, full bool) (*regexp.Regexp, error) {
+	if full {
+		s = "^(?:" + s + ")$"
+	}
+	return regexp.Compile(s)
+}
@@ -0,0 +1,455 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+/*
+Package jsonschema validates values against JSON Schema (draft 2020-12)
+documents.
+
+The assertion keywords of the core applicator and validation
+vocabularies are supported, as are boolean schemas and $ref references
+to JSON Pointers within the same schema document ("#/$defs/name").
+Annotation keywords, including format, are accepted and ignored, as are
+unknown keywords. Remote references are not supported.
+*/
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/couchbase/query/value"
+)
+
+// Maximum number of nested schemas entered while validating a value,
+// which bounds the recursion of self referencing schemas.
+const _MAX_DEPTH = 128
+
+type Schema struct {
+	source value.Value
+	root   *node
+}
+
+type node struct {
+	always *bool // boolean schema
+
+	ref *node
+
+	types  []string
+	enum   []value.Value
+	cnst   value.Value
+	hasCst bool
+
+	multipleOf       *float64
+	maximum          *float64
+	exclusiveMaximum *float64
+	minimum          *float64
+	exclusiveMinimum *float64
+
+	maxLength *int
+	minLength *int
+	pattern   *regexp.Regexp
+
+	prefixItems []*node
+	items       *node
+	contains    *node
+	maxContains *int
+	minContains *int
+	maxItems    *int
+	minItems    *int
+	uniqueItems bool
+
+	properties           map[string]*node
+	patternProperties    []*patternNode
+	additionalProperties *node
+	propertyNames        *node
+	required             []string
+	dependentRequired    map[string][]string
+	maxProperties        *int
+	minProperties        *int
+
+	allOf []*node
+	anyOf []*node
+	oneOf []*node
+	not   *node
+	cond  *node
+	then  *node
+	els   *node
+}
+
+type patternNode struct {
+	re     *regexp.Regexp
+	schema *node
+}
+
+/*
+CompileError reports a malformed schema, with the JSON Pointer of the
+offending location within the schema.
+*/
+type CompileError struct {
+	Path   string
+	Reason string
+}
+
+func (this *CompileError) Error() string {
+	if this.Path == "" {
+		return "invalid JSON schema: " + this.Reason
+	}
+	return fmt.Sprintf("invalid JSON schema at '%s': %s", this.Path, this.Reason)
+}
+
+type compiler struct {
+	root value.Value
+	refs map[string]*node
+}
+
+/*
+Compile a schema, which must be an object or a boolean.
+*/
+func Compile(schema value.Value) (*Schema, error) {
+	c := &compiler{root: schema, refs: make(map[string]*node)}
+	root, err := c.compile(schema, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{source: schema, root: root}, nil
+}
+
+func (this *Schema) Source() value.Value {
+	return this.source
+}
+
+func (this *compiler) error(path, format string, args ...interface{}) error {
+	return &CompileError{Path: path, Reason: fmt.Sprintf(format, args...)}
+}
+
+func (this *compiler) compile(s value.Value, path string) (*node, error) {
+	n := &node{}
+	return n, this.compileInto(n, s, path)
+}
+
+func (this *compiler) compileInto(n *node, s value.Value, path string) error {
+	switch s.Type() {
+	case value.BOOLEAN:
+		b := s.Truth()
+		n.always = &b
+		return nil
+	case value.OBJECT:
+	default:
+		return this.error(path, "schema must be an object or a boolean")
+	}
+
+	var err error
+	for name, v := range s.Fields() {
+		kv := value.NewValue(v)
+		kpath := path + "/" + escape(name)
+
+		switch name {
+		case "$ref":
+			if kv.Type() != value.STRING {
+				return this.error(kpath, "must be a string")
+			}
+			n.ref, err = this.reference(kv.ToString(), kpath)
+		case "type":
+			n.types, err = this.types(kv, kpath)
+		case "enum":
+			arr, ok := kv.Actual().([]interface{})
+			if !ok {
+				return this.error(kpath, "must be an array")
+			}
+			for _, e := range arr {
+				n.enum = append(n.enum, value.NewValue(e))
+			}
+		case "const":
+			n.cnst, n.hasCst = kv, true
+		case "multipleOf":
+			n.multipleOf, err = this.number(kv, kpath)
+			if err == nil && *n.multipleOf <= 0 {
+				err = this.error(kpath, "must be greater than 0")
+			}
+		case "maximum":
+			n.maximum, err = this.number(kv, kpath)
+		case "exclusiveMaximum":
+			n.exclusiveMaximum, err = this.number(kv, kpath)
+		case "minimum":
+			n.minimum, err = this.number(kv, kpath)
+		case "exclusiveMinimum":
+			n.exclusiveMinimum, err = this.number(kv, kpath)
+		case "maxLength":
+			n.maxLength, err = this.count(kv, kpath)
+		case "minLength":
+			n.minLength, err = this.count(kv, kpath)
+		case "pattern":
+			n.pattern, err = this.regexp(kv, kpath)
+		case "prefixItems":
+			n.prefixItems, err = this.schemaArray(kv, kpath)
+		case "items":
+			if kv.Type() == value.ARRAY {
+				// draft 2019-09 and earlier tuple form
+				n.prefixItems, err = this.schemaArray(kv, kpath)
+			} else {
+				n.items, err = this.compile(kv, kpath)
+			}
+		case "additionalItems":
+			// only meaningful with the tuple form of items
+			if _, ok := s.Field("prefixItems"); !ok {
+				if iv, ok := s.Field("items"); ok && iv.Type() == value.ARRAY {
+					n.items, err = this.compile(kv, kpath)
+				}
+			}
+		case "contains":
+			n.contains, err = this.compile(kv, kpath)
+		case "maxContains":
+			n.maxContains, err = this.count(kv, kpath)
+		case "minContains":
+			n.minContains, err = this.count(kv, kpath)
+		case "maxItems":
+			n.maxItems, err = this.count(kv, kpath)
+		case "minItems":
+			n.minItems, err = this.count(kv, kpath)
+		case "uniqueItems":
+			if kv.Type() != value.BOOLEAN {
+				return this.error(kpath, "must be a boolean")
+			}
+			n.uniqueItems = kv.Truth()
+		case "properties":
+			n.properties, err = this.schemaMap(kv, kpath)
+		case "patternProperties":
+			var m map[string]*node
+			m, err = this.schemaMap(kv, kpath)
+			for p, ps := range m {
+				var re *regexp.Regexp
+				re, err = this.regexp(value.NewValue(p), kpath+"/"+escape(p))
+				if err != nil {
+					break
+				}
+				n.patternProperties = append(n.patternProperties, &patternNode{re: re, schema: ps})
+			}
+		case "additionalProperties":
+			n.additionalProperties, err = this.compile(kv, kpath)
+		case "propertyNames":
+			n.propertyNames, err = this.compile(kv, kpath)
+		case "required":
+			n.required, err = this.strings(kv, kpath)
+		case "dependentRequired":
+			if kv.Type() != value.OBJECT {
+				return this.error(kpath, "must be an object")
+			}
+			n.dependentRequired = make(map[string][]string)
+			for p, d := range kv.Fields() {
+				n.dependentRequired[p], err = this.strings(value.NewValue(d), kpath+"/"+escape(p))
+				if err != nil {
+					break
+				}
+			}
+		case "maxProperties":
+			n.maxProperties, err = this.count(kv, kpath)
+		case "minProperties":
+			n.minProperties, err = this.count(kv, kpath)
+		case "allOf":
+			n.allOf, err = this.schemaArray(kv, kpath)
+		case "anyOf":
+			n.anyOf, err = this.schemaArray(kv, kpath)
+		case "oneOf":
+			n.oneOf, err = this.schemaArray(kv, kpath)
+		case "not":
+			n.not, err = this.compile(kv, kpath)
+		case "if":
+			n.cond, err = this.compile(kv, kpath)
+		case "then":
+			n.then, err = this.compile(kv, kpath)
+		case "else":
+			n.els, err = this.compile(kv, kpath)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+Only references to locations within the schema itself are supported.
+Each location is compiled once, so that recursive schemas terminate.
+*/
+func (this *compiler) reference(ref, path string) (*node, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, this.error(path, "unsupported reference '%s'", ref)
+	}
+	if n, ok := this.refs[ref]; ok {
+		return n, nil
+	}
+
+	ptr, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		return nil, this.error(path, "invalid reference '%s'", ref)
+	}
+	target, ok := resolvePointer(this.root, ptr)
+	if !ok {
+		return nil, this.error(path, "unresolved reference '%s'", ref)
+	}
+
+	n := &node{}
+	this.refs[ref] = n
+	return n, this.compileInto(n, target, ptr)
+}
+
+func resolvePointer(root value.Value, ptr string) (value.Value, bool) {
+	if ptr == "" {
+		return root, true
+	} else if ptr[0] != '/' {
+		return nil, false
+	}
+
+	cur := root
+	for _, t := range strings.Split(ptr[1:], "/") {
+		t = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+		switch cur.Type() {
+		case value.OBJECT:
+			v, ok := cur.Field(t)
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case value.ARRAY:
+			i, err := strconv.Atoi(t)
+			if err != nil {
+				return nil, false
+			}
+			v, ok := cur.Index(i)
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+var _TYPES = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+func (this *compiler) types(v value.Value, path string) ([]string, error) {
+	var types []string
+	if v.Type() == value.STRING {
+		types = []string{v.ToString()}
+	} else {
+		var err error
+		types, err = this.strings(v, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range types {
+		if !_TYPES[t] {
+			return nil, this.error(path, "unknown type '%s'", t)
+		}
+	}
+	return types, nil
+}
+
+func (this *compiler) strings(v value.Value, path string) ([]string, error) {
+	arr, ok := v.Actual().([]interface{})
+	if !ok {
+		return nil, this.error(path, "must be an array of strings")
+	}
+	rv := make([]string, len(arr))
+	for i, e := range arr {
+		ev := value.NewValue(e)
+		if ev.Type() != value.STRING {
+			return nil, this.error(path, "must be an array of strings")
+		}
+		rv[i] = ev.ToString()
+	}
+	return rv, nil
+}
+
+func (this *compiler) number(v value.Value, path string) (*float64, error) {
+	if v.Type() != value.NUMBER {
+		return nil, this.error(path, "must be a number")
+	}
+	f := value.AsNumberValue(v).Float64()
+	return &f, nil
+}
+
+func (this *compiler) count(v value.Value, path string) (*int, error) {
+	if v.Type() == value.NUMBER {
+		f := value.AsNumberValue(v).Float64()
+		if f >= 0 && f == math.Trunc(f) && f <= math.MaxInt32 {
+			i := int(f)
+			return &i, nil
+		}
+	}
+	return nil, this.error(path, "must be a non-negative integer")
+}
+
+func (this *compiler) regexp(v value.Value, path string) (*regexp.Regexp, error) {
+	if v.Type() != value.STRING {
+		return nil, this.error(path, "must be a string")
+	}
+	re, err := regexp.Compile(v.ToString())
+	if err != nil {
+		return nil, this.error(path, "invalid regular expression: %v", err)
+	}
+	return re, nil
+}
+
+func (this *compiler) schemaArray(v value.Value, path string) ([]*node, error) {
+	arr, ok := v.Actual().([]interface{})
+	if !ok || len(arr) == 0 {
+		return nil, this.error(path, "must be a non-empty array of schemas")
+	}
+	rv := make([]*node, len(arr))
+	for i, e := range arr {
+		n, err := this.compile(value.NewValue(e), path+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		rv[i] = n
+	}
+	return rv, nil
+}
+
+func (this *compiler) schemaMap(v value.Value, path string) (map[string]*node, error) {
+	if v.Type() != value.OBJECT {
+		return nil, this.error(path, "must be an object")
+	}
+	rv := make(map[string]*node, len(v.Fields()))
+	for name, e := range v.Fields() {
+		n, err := this.compile(value.NewValue(e), path+"/"+escape(name))
+		if err != nil {
+			return nil, err
+		}
+		rv[name] = n
+	}
+	return rv, nil
+}
+
+/*
+Escape a JSON Pointer reference token.
+*/
+func escape(token string) string {
+	if strings.IndexAny(token, "~/") < 0 {
+		return token
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
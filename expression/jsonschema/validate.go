@@ -0,0 +1,406 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/couchbase/query/value"
+)
+
+/*
+A Failure locates a value that does not conform to the schema, by means
+of the JSON Pointer of the value within the validated document, and
+names the keyword that rejected it.
+*/
+type Failure struct {
+	Path    string
+	Keyword string
+	Message string
+}
+
+func (this *Failure) String() string {
+	path := this.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s", path, this.Message)
+}
+
+func (this *Failure) Value() value.Value {
+	return value.NewValue(map[string]interface{}{
+		"path":    this.Path,
+		"keyword": this.Keyword,
+		"message": this.Message,
+	})
+}
+
+type validator struct {
+	failures []*Failure
+	limit    int
+	depth    int
+}
+
+/*
+Validate returns the failures found in doc, in document order. If limit
+is greater than zero, validation stops once limit failures are found.
+*/
+func (this *Schema) Validate(doc value.Value, limit int) []*Failure {
+	v := &validator{limit: limit}
+	v.validate(this.root, doc, "")
+	return v.failures
+}
+
+func (this *Schema) Valid(doc value.Value) bool {
+	return len(this.Validate(doc, 1)) == 0
+}
+
+func (this *validator) done() bool {
+	return this.limit > 0 && len(this.failures) >= this.limit
+}
+
+func (this *validator) fail(path, keyword, format string, args ...interface{}) {
+	if !this.done() {
+		this.failures = append(this.failures, &Failure{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+/*
+Check whether a subschema accepts the value, without recording failures.
+*/
+func (this *validator) accepts(n *node, v value.Value, path string) bool {
+	sub := &validator{limit: 1, depth: this.depth}
+	sub.validate(n, v, path)
+	return len(sub.failures) == 0
+}
+
+func (this *validator) validate(n *node, v value.Value, path string) {
+	if this.done() {
+		return
+	}
+	if n.always != nil {
+		if !*n.always {
+			this.fail(path, "false", "no value is allowed")
+		}
+		return
+	}
+
+	this.depth++
+	defer func() { this.depth-- }()
+	if this.depth > _MAX_DEPTH {
+		this.fail(path, "$ref", "maximum schema depth exceeded")
+		return
+	}
+
+	if n.ref != nil {
+		this.validate(n.ref, v, path)
+	}
+
+	if len(n.types) > 0 && !matchesType(n.types, v) {
+		this.fail(path, "type", "expected %s, found %s", strings.Join(n.types, " or "), typeName(v))
+		// other keywords would only report the same problem
+		return
+	}
+	if len(n.enum) > 0 {
+		found := false
+		for _, e := range n.enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			this.fail(path, "enum", "value is not one of the allowed values")
+		}
+	}
+	if n.hasCst && !equal(n.cnst, v) {
+		this.fail(path, "const", "value does not equal %s", n.cnst.String())
+	}
+
+	switch v.Type() {
+	case value.NUMBER:
+		this.number(n, value.AsNumberValue(v).Float64(), path)
+	case value.STRING:
+		this.string(n, v.ToString(), path)
+	case value.ARRAY:
+		arr, _ := v.Actual().([]interface{})
+		this.array(n, arr, path)
+	case value.OBJECT:
+		this.object(n, v, path)
+	}
+
+	for _, s := range n.allOf {
+		this.validate(s, v, path)
+	}
+	if len(n.anyOf) > 0 {
+		found := false
+		for _, s := range n.anyOf {
+			if this.accepts(s, v, path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			this.fail(path, "anyOf", "value does not match any of the schemas")
+		}
+	}
+	if len(n.oneOf) > 0 {
+		count := 0
+		for _, s := range n.oneOf {
+			if this.accepts(s, v, path) {
+				count++
+			}
+		}
+		if count != 1 {
+			this.fail(path, "oneOf", "value matches %d of the schemas, expected exactly one", count)
+		}
+	}
+	if n.not != nil && this.accepts(n.not, v, path) {
+		this.fail(path, "not", "value must not match the schema")
+	}
+	if n.cond != nil {
+		if this.accepts(n.cond, v, path) {
+			if n.then != nil {
+				this.validate(n.then, v, path)
+			}
+		} else if n.els != nil {
+			this.validate(n.els, v, path)
+		}
+	}
+}
+
+func (this *validator) number(n *node, f float64, path string) {
+	if n.multipleOf != nil {
+		q := f / *n.multipleOf
+		if math.IsInf(q, 0) || q != math.Trunc(q) {
+			this.fail(path, "multipleOf", "%v is not a multiple of %v", f, *n.multipleOf)
+		}
+	}
+	if n.maximum != nil && f > *n.maximum {
+		this.fail(path, "maximum", "%v is greater than %v", f, *n.maximum)
+	}
+	if n.exclusiveMaximum != nil && f >= *n.exclusiveMaximum {
+		this.fail(path, "exclusiveMaximum", "%v is not less than %v", f, *n.exclusiveMaximum)
+	}
+	if n.minimum != nil && f < *n.minimum {
+		this.fail(path, "minimum", "%v is less than %v", f, *n.minimum)
+	}
+	if n.exclusiveMinimum != nil && f <= *n.exclusiveMinimum {
+		this.fail(path, "exclusiveMinimum", "%v is not greater than %v", f, *n.exclusiveMinimum)
+	}
+}
+
+func (this *validator) string(n *node, s string, path string) {
+	if n.maxLength != nil || n.minLength != nil {
+		l := utf8.RuneCountInString(s)
+		if n.maxLength != nil && l > *n.maxLength {
+			this.fail(path, "maxLength", "length %d is greater than %d", l, *n.maxLength)
+		}
+		if n.minLength != nil && l < *n.minLength {
+			this.fail(path, "minLength", "length %d is less than %d", l, *n.minLength)
+		}
+	}
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		this.fail(path, "pattern", "value does not match pattern '%s'", n.pattern.String())
+	}
+}
+
+func (this *validator) array(n *node, arr []interface{}, path string) {
+	if n.maxItems != nil && len(arr) > *n.maxItems {
+		this.fail(path, "maxItems", "%d items is more than %d", len(arr), *n.maxItems)
+	}
+	if n.minItems != nil && len(arr) < *n.minItems {
+		this.fail(path, "minItems", "%d items is fewer than %d", len(arr), *n.minItems)
+	}
+	if n.uniqueItems {
+	outer:
+		for i := 1; i < len(arr); i++ {
+			vi := value.NewValue(arr[i])
+			for j := 0; j < i; j++ {
+				if equal(vi, value.NewValue(arr[j])) {
+					this.fail(path, "uniqueItems", "items %d and %d are equal", j, i)
+					break outer
+				}
+			}
+		}
+	}
+
+	for i, e := range arr {
+		var s *node
+		if i < len(n.prefixItems) {
+			s = n.prefixItems[i]
+		} else {
+			s = n.items
+		}
+		if s == nil {
+			continue
+		} else if s.always != nil && !*s.always {
+			this.fail(path+"/"+strconv.Itoa(i), "items", "item %d is not allowed", i)
+		} else {
+			this.validate(s, value.NewValue(e), path+"/"+strconv.Itoa(i))
+		}
+	}
+
+	if n.contains != nil {
+		count := 0
+		for i, e := range arr {
+			if this.accepts(n.contains, value.NewValue(e), path+"/"+strconv.Itoa(i)) {
+				count++
+			}
+		}
+		min := 1
+		if n.minContains != nil {
+			min = *n.minContains
+		}
+		if count < min {
+			this.fail(path, "contains", "%d matching items is fewer than %d", count, min)
+		}
+		if n.maxContains != nil && count > *n.maxContains {
+			this.fail(path, "maxContains", "%d matching items is more than %d", count, *n.maxContains)
+		}
+	}
+}
+
+func (this *validator) object(n *node, v value.Value, path string) {
+	fields := v.Fields()
+
+	if n.maxProperties != nil && len(fields) > *n.maxProperties {
+		this.fail(path, "maxProperties", "%d properties is more than %d", len(fields), *n.maxProperties)
+	}
+	if n.minProperties != nil && len(fields) < *n.minProperties {
+		this.fail(path, "minProperties", "%d properties is fewer than %d", len(fields), *n.minProperties)
+	}
+	for _, r := range n.required {
+		if _, ok := fields[r]; !ok {
+			this.fail(path+"/"+escape(r), "required", "required property '%s' is missing", r)
+		}
+	}
+	if len(n.dependentRequired) > 0 {
+		for _, p := range sortedKeys(n.dependentRequired) {
+			if _, ok := fields[p]; !ok {
+				continue
+			}
+			for _, r := range n.dependentRequired[p] {
+				if _, ok := fields[r]; !ok {
+					this.fail(path+"/"+escape(r), "dependentRequired",
+						"property '%s' is required when '%s' is present", r, p)
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(fields))
+	for name, _ := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fv := value.NewValue(fields[name])
+		fpath := path + "/" + escape(name)
+
+		if n.propertyNames != nil && !this.accepts(n.propertyNames, value.NewValue(name), fpath) {
+			this.fail(fpath, "propertyNames", "property name '%s' is not allowed", name)
+		}
+
+		matched := false
+		if s, ok := n.properties[name]; ok {
+			matched = true
+			this.validate(s, fv, fpath)
+		}
+		for _, pp := range n.patternProperties {
+			if pp.re.MatchString(name) {
+				matched = true
+				this.validate(pp.schema, fv, fpath)
+			}
+		}
+		if !matched && n.additionalProperties != nil {
+			if n.additionalProperties.always != nil && !*n.additionalProperties.always {
+				this.fail(fpath, "additionalProperties", "property '%s' is not allowed", name)
+			} else {
+				this.validate(n.additionalProperties, fv, fpath)
+			}
+		}
+	}
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k, _ := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func matchesType(types []string, v value.Value) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if v.Type() == value.NULL {
+				return true
+			}
+		case "boolean":
+			if v.Type() == value.BOOLEAN {
+				return true
+			}
+		case "object":
+			if v.Type() == value.OBJECT {
+				return true
+			}
+		case "array":
+			if v.Type() == value.ARRAY {
+				return true
+			}
+		case "number":
+			if v.Type() == value.NUMBER {
+				return true
+			}
+		case "integer":
+			if v.Type() == value.NUMBER {
+				f := value.AsNumberValue(v).Float64()
+				if f == math.Trunc(f) && !math.IsInf(f, 0) {
+					return true
+				}
+			}
+		case "string":
+			if v.Type() == value.STRING {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeName(v value.Value) string {
+	switch v.Type() {
+	case value.NULL:
+		return "null"
+	case value.BOOLEAN:
+		return "boolean"
+	case value.NUMBER:
+		return "number"
+	case value.STRING:
+		return "string"
+	case value.ARRAY:
+		return "array"
+	case value.OBJECT:
+		return "object"
+	}
+	return "missing"
+}
+
+func equal(l, r value.Value) bool {
+	return l.Type() == r.Type() && l.Collate(r) == 0
+}
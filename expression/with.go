@@ -14,6 +14,18 @@ import (
 	"github.com/couchbase/query/value"
 )
 
+/*
+Materialization hint of a common table expression: AS MATERIALIZED or AS
+NOT MATERIALIZED. Without a hint the planner chooses.
+*/
+type Materialization int
+
+const (
+	MATERIALIZE_DEFAULT Materialization = iota
+	MATERIALIZE_ALWAYS
+	MATERIALIZE_NEVER
+)
+
 type With interface {
 	Alias() string
 	Expression() Expression
@@ -25,6 +37,8 @@ type With interface {
 	RecursiveExpression() Expression
 	Config() value.Value
 	CycleFields() Expressions
+	Materialization() Materialization
+	SetMaterialization(m Materialization)
 	SplitRecursive() error
 	ErrorContext() string
 	SetErrorContext(line int, column int)
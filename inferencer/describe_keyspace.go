@@ -43,6 +43,11 @@ type DescribeOptions struct {
 	InferTimeout        int32
 	MaxSchemaMB         int32
 	Flags               Flag
+	Format              string        // if set, flavors are converted to this format (see schema_format.go)
+	SaveBaseline        string        // name under which to save the schema as a baseline (see drift.go)
+	CompareTo           value.Value   // baseline, or name of the baseline, to compare the schema with
+	DriftThreshold      float64       // shift in %docs reported as drift, in percentage points
+	Interval            time.Duration // interval at which to repeat the comparison
 }
 
 func DescribeKeyspace(context datastore.QueryContext, conn *datastore.ValueConnection, retriever DocumentRetriever,
@@ -188,6 +193,7 @@ func processWith(context datastore.QueryContext, with value.Value) (*DescribeOpt
 		InferTimeout:        60, // don't spend more than 60 seconds on any bucket
 		MaxSchemaMB:         10, // if the schema is bigger than 10MB, don't return
 		Flags:               NO_FLAGS,
+		DriftThreshold:      _DEF_DRIFT_THRESHOLD,
 	}
 
 	if !context.GetReqDeadline().IsZero() {
@@ -205,6 +211,42 @@ func processWith(context datastore.QueryContext, with value.Value) (*DescribeOpt
 
 	for fieldName, _ := range with.Fields() {
 		fv, _ := with.Field(fieldName)
+		switch fieldName {
+		case "format":
+			if fv.Type() != value.STRING {
+				return nil, errors.NewInferInvalidFormat(fv.String())
+			}
+			format := strings.ToLower(fv.ToString())
+			if !IsFormat(format) {
+				return nil, errors.NewInferInvalidFormat(fv.ToString())
+			}
+			options.Format = format
+			continue
+		case "save_baseline":
+			if fv.Type() != value.STRING || fv.ToString() == "" {
+				return nil, errors.NewInferInvalidOption(fieldName)
+			}
+			options.SaveBaseline = fv.ToString()
+			continue
+		case "compare_to":
+			if (fv.Type() != value.STRING || fv.ToString() == "") && fv.Type() != value.ARRAY {
+				return nil, errors.NewInferBaselineError("load", fv.String(),
+					fmt.Errorf("compare_to must be the name of a baseline or a schema"))
+			}
+			options.CompareTo = fv
+			continue
+		case "interval":
+			if fv.Type() != value.STRING {
+				return nil, errors.NewInferInvalidOption(fieldName)
+			}
+			d, err := time.ParseDuration(fv.ToString())
+			if err != nil || d < _MIN_DRIFT_INTERVAL {
+				return nil, errors.NewInferInvalidOption(fieldName)
+			}
+			options.Interval = d
+			continue
+		}
+
 		if fv.Type() != value.NUMBER {
 			if fieldName == "flags" {
 				if fv.Type() == value.STRING {
@@ -254,11 +296,16 @@ func processWith(context datastore.QueryContext, with value.Value) (*DescribeOpt
 			options.MaxSchemaMB = int32(v)
 		case "flags":
 			options.Flags = Flag(v)
+		case "drift_threshold":
+			options.DriftThreshold = v
 		default:
 			return nil, errors.NewInferInvalidOption(fieldName)
 		}
 	}
 
+	if options.Interval > 0 && (options.CompareTo == nil || options.CompareTo.Type() != value.STRING) {
+		return nil, errors.NewInferInvalidOption("interval")
+	}
 	return options, nil
 }
 
@@ -334,6 +381,12 @@ func (di *DefaultInferencer) InferKeyspace(context datastore.QueryContext, ks da
 		conn.Warning(err)
 	}
 
+	schema, err = finishSchema(context, ks, with, options, schema, conn)
+	if err != nil {
+		conn.Error(err)
+		return
+	}
+
 	conn.ValueChannel() <- schema
 }
 
@@ -379,5 +432,11 @@ func (di *DefaultInferencer) InferExpression(context datastore.QueryContext, exp
 		conn.Warning(err)
 	}
 
+	schema, err = finishSchema(context, nil, with, options, schema, conn)
+	if err != nil {
+		conn.Error(err)
+		return
+	}
+
 	conn.ValueChannel() <- schema
 }
@@ -0,0 +1,551 @@
+/*
+Copyright 2026-Present Couchbase, Inc.
+
+Use of this software is governed by the Business Source License included in
+the file licenses/BSL-Couchbase.txt.  As of the Change Date specified in that
+file, in accordance with the Business Source License, use of this software will
+be governed by the Apache License, Version 2.0, included in the file
+licenses/APL2.txt.
+*/
+
+package inferencer
+
+/*
+ * drift.go compares an inferred schema with a baseline saved from an earlier INFER.
+ *
+ * A baseline is the schema INFER returns, i.e. an array of flavors. It is saved, by name, in the
+ * system collection of the bucket of the keyspace inferred with
+ *
+ *     INFER keyspace WITH {"save_baseline": "name"}
+ *
+ * and compared with the current schema with
+ *
+ *     INFER keyspace WITH {"compare_to": "name"}
+ *
+ * where compare_to may also be a schema passed inline. The flavors of the two schemas are paired
+ * by their descriptors, or failing that by the top level fields they share, and the fields of each
+ * pair compared. The result is a report of flavors and fields added or removed, fields whose types
+ * changed, and fields whose %docs shifted by at least drift_threshold percentage points.
+ *
+ * With an interval, the comparison is repeated as a scheduler task, which can be cancelled by
+ * deleting it from system:tasks_cache. Drift found by any comparison is counted in system:vitals.
+ */
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/scheduler"
+	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
+)
+
+const _BASELINE = "infer_baseline::"
+const _DRIFT_TASK_CLASS = "infer_drift"
+
+// default shift in %docs, in percentage points, reported as drift
+const _DEF_DRIFT_THRESHOLD = 10.0
+
+// baselines compared at intervals shorter than this would be recompared before they finish
+const _MIN_DRIFT_INTERVAL = time.Minute
+
+// Saves, compares, or converts the schema inferred, as the options require
+func finishSchema(context datastore.QueryContext, ks datastore.Keyspace, with value.Value, options *DescribeOptions,
+	schema value.Value, conn *datastore.ValueConnection) (value.Value, errors.Error) {
+
+	if options.SaveBaseline != "" {
+		err := saveBaseline(ks, options.SaveBaseline, schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.CompareTo != nil {
+		baseline, name, err := loadBaseline(ks, options.CompareTo)
+		if err != nil {
+			return nil, err
+		}
+		keyspace := "expression"
+		if ks != nil {
+			keyspace = ks.QualifiedName()
+		}
+		report, changes, e := CompareSchemas(baseline, schema, options.DriftThreshold)
+		if e != nil {
+			return nil, errors.NewInferBaselineError("compare", name, e)
+		}
+		report["keyspace"] = keyspace
+		report["baseline"] = name
+		recordDrift(keyspace, name, changes)
+		if changes > 0 {
+			conn.Warning(errors.NewInferSchemaDrift(keyspace, name, changes))
+		}
+		if options.Interval > 0 {
+			err = scheduleDriftCheck(context, ks, with, options)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return value.NewValue(report), nil
+	}
+
+	if options.Format != "" {
+		return FormatSchema(schema, options.Format), nil
+	}
+	return schema, nil
+}
+
+/*
+Baselines
+*/
+
+func baselineCollection(ks datastore.Keyspace, name string, op string) (datastore.Keyspace, errors.Error) {
+	if ks == nil || ks.Scope() == nil {
+		return nil, errors.NewInferBaselineError(op, name, fmt.Errorf("named baselines require a keyspace"))
+	}
+	store := datastore.GetDatastore()
+	if store == nil {
+		return nil, errors.NewInferBaselineError(op, name, errors.NewNoDatastoreError())
+	}
+	b, err := store.GetSystemCollection(ks.Scope().BucketId())
+	if err == nil && b == nil {
+		err = errors.NewSystemCollectionError(ks.Scope().BucketId(), nil)
+	}
+	if err != nil {
+		return nil, errors.NewInferBaselineError(op, name, err)
+	}
+	return b, nil
+}
+
+func saveBaseline(ks datastore.Keyspace, name string, schema value.Value) errors.Error {
+	b, err := baselineCollection(ks, name, "save")
+	if err != nil {
+		return err
+	}
+
+	m := make(map[string]interface{}, 3)
+	m["keyspace"] = ks.QualifiedName()
+	m["created"] = time.Now().Format(util.DEFAULT_FORMAT)
+	m["schema"] = schema
+	pairs := make([]value.Pair, 1)
+	pairs[0].Name = _BASELINE + name
+	pairs[0].Value = value.NewAnnotatedValue(value.NewValue(m))
+
+	_, _, errs := b.Upsert(pairs, datastore.GetDurableQueryContextFor(b), true)
+	if len(errs) > 0 {
+		return errors.NewInferBaselineError("save", name, errs[0])
+	}
+	return nil
+}
+
+// Returns the baseline to compare with, and its name
+func loadBaseline(ks datastore.Keyspace, compareTo value.Value) (value.Value, string, errors.Error) {
+	if compareTo.Type() != value.STRING {
+		return compareTo, "compare_to", nil
+	}
+
+	name := compareTo.ToString()
+	b, err := baselineCollection(ks, name, "load")
+	if err != nil {
+		return nil, name, err
+	}
+	res := make(map[string]value.AnnotatedValue, 1)
+	keys := []string{_BASELINE + name}
+	errs := b.Fetch(keys, res, datastore.NULL_QUERY_CONTEXT, nil, nil, false)
+	if len(errs) > 0 {
+		if !errors.IsNotFoundError("", errs[0]) && !errs[0].HasCause(errors.E_CB_BULK_GET) {
+			return nil, name, errors.NewInferBaselineError("load", name, errs[0])
+		}
+		return nil, name, errors.NewInferBaselineNotFound(name)
+	}
+	av, ok := res[keys[0]]
+	if !ok {
+		return nil, name, errors.NewInferBaselineNotFound(name)
+	}
+	schema, ok := av.Field("schema")
+	if !ok || schema.Type() != value.ARRAY {
+		return nil, name, errors.NewInferBaselineError("load", name, fmt.Errorf("invalid baseline document"))
+	}
+	return schema, name, nil
+}
+
+/*
+Comparison
+*/
+
+type driftFlavor struct {
+	descriptor string
+	fields     map[string]*driftField
+}
+
+type driftField struct {
+	types   string
+	percent float64
+}
+
+/*
+Compares a schema with a baseline, returning the report and the number of changes found.
+Both are arrays of flavors, as returned by INFER.
+*/
+func CompareSchemas(baseline value.Value, schema value.Value, threshold float64) (map[string]interface{}, int, error) {
+	before, err := driftFlavors(baseline)
+	if err != nil {
+		return nil, 0, err
+	}
+	after, err := driftFlavors(schema)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// pair flavors with the same descriptor first, then those sharing most top level fields
+	pairs := make([]int, len(after))
+	used := make([]bool, len(before))
+	for i, a := range after {
+		pairs[i] = -1
+		if a.descriptor == "" {
+			continue
+		}
+		for j, b := range before {
+			if !used[j] && a.descriptor == b.descriptor {
+				pairs[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+	for i, a := range after {
+		if pairs[i] >= 0 {
+			continue
+		}
+		best := 0.5
+		for j, b := range before {
+			if used[j] {
+				continue
+			}
+			s := flavorSimilarity(a, b)
+			if s >= best {
+				best = s
+				pairs[i] = j
+			}
+		}
+		if pairs[i] >= 0 {
+			used[pairs[i]] = true
+		}
+	}
+
+	changes := 0
+	flavors := make([]interface{}, 0, len(after)+len(before))
+	for i, a := range after {
+		if pairs[i] < 0 {
+			changes++
+			flavors = append(flavors, map[string]interface{}{"flavor": a.descriptor, "status": "added"})
+			continue
+		}
+		report, n := compareFlavors(before[pairs[i]], a, threshold)
+		changes += n
+		flavors = append(flavors, report)
+	}
+	for j, b := range before {
+		if !used[j] {
+			changes++
+			flavors = append(flavors, map[string]interface{}{"flavor": b.descriptor, "status": "removed"})
+		}
+	}
+
+	rv := map[string]interface{}{
+		"drift":   changes > 0,
+		"changes": changes,
+		"flavors": flavors,
+	}
+	return rv, changes, nil
+}
+
+func compareFlavors(before, after *driftFlavor, threshold float64) (map[string]interface{}, int) {
+	var added, removed, typeChanges, presenceChanges []interface{}
+
+	for _, name := range sortedDriftFields(after.fields) {
+		a := after.fields[name]
+		b, ok := before.fields[name]
+		if !ok {
+			added = append(added, name)
+			continue
+		}
+		if a.types != b.types {
+			typeChanges = append(typeChanges, map[string]interface{}{"field": name, "from": b.types, "to": a.types})
+		}
+		if math.Abs(a.percent-b.percent) >= threshold {
+			presenceChanges = append(presenceChanges,
+				map[string]interface{}{"field": name, "from": b.percent, "to": a.percent})
+		}
+	}
+	for _, name := range sortedDriftFields(before.fields) {
+		if _, ok := after.fields[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	changes := len(added) + len(removed) + len(typeChanges) + len(presenceChanges)
+	rv := map[string]interface{}{"flavor": after.descriptor}
+	if changes == 0 {
+		rv["status"] = "unchanged"
+		return rv, 0
+	}
+	rv["status"] = "changed"
+	if before.descriptor != after.descriptor {
+		rv["baseline_flavor"] = before.descriptor
+	}
+	if len(added) > 0 {
+		rv["added_fields"] = added
+	}
+	if len(removed) > 0 {
+		rv["removed_fields"] = removed
+	}
+	if len(typeChanges) > 0 {
+		rv["type_changes"] = typeChanges
+	}
+	if len(presenceChanges) > 0 {
+		rv["presence_changes"] = presenceChanges
+	}
+	return rv, changes
+}
+
+// the fraction of top level fields two flavors share
+func flavorSimilarity(a, b *driftFlavor) float64 {
+	shared := 0
+	all := 0
+	for name, _ := range a.fields {
+		if strings.ContainsAny(name, ".[") {
+			continue
+		}
+		all++
+		if _, ok := b.fields[name]; ok {
+			shared++
+		}
+	}
+	for name, _ := range b.fields {
+		if strings.ContainsAny(name, ".[") {
+			continue
+		}
+		if _, ok := a.fields[name]; !ok {
+			all++
+		}
+	}
+	if all == 0 {
+		return 1.0
+	}
+	return float64(shared) / float64(all)
+}
+
+func sortedDriftFields(fields map[string]*driftField) []string {
+	rv := make([]string, 0, len(fields))
+	for name, _ := range fields {
+		rv = append(rv, name)
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+func driftFlavors(schema value.Value) ([]*driftFlavor, error) {
+	bytes, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var flavors []interface{}
+	err = json.Unmarshal(bytes, &flavors)
+	if err != nil {
+		return nil, fmt.Errorf("schema is not an array of flavors")
+	}
+
+	// the results of an INFER statement hold the array of flavors
+	if len(flavors) == 1 {
+		if inner, ok := flavors[0].([]interface{}); ok {
+			flavors = inner
+		}
+	}
+
+	rv := make([]*driftFlavor, 0, len(flavors))
+	for _, f := range flavors {
+		desc, ok := f.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("schema is not an array of flavors")
+		}
+		flavor := &driftFlavor{fields: make(map[string]*driftField)}
+		flavor.descriptor, _ = desc["Flavor"].(string)
+		driftProperties(desc, "", flavor.fields)
+		rv = append(rv, flavor)
+	}
+	return rv, nil
+}
+
+// fields are known by their path, with [] standing for the elements of arrays
+func driftProperties(desc map[string]interface{}, prefix string, fields map[string]*driftField) {
+	props, _ := desc["properties"].(map[string]interface{})
+	for name, p := range props {
+		prop, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := prefix + name
+		field := &driftField{}
+
+		switch t := prop["type"].(type) {
+		case string:
+			field.types = t
+		case []interface{}:
+			types := make([]string, 0, len(t))
+			for _, n := range t {
+				types = append(types, fmt.Sprintf("%v", n))
+			}
+			sort.Strings(types)
+			field.types = strings.Join(types, "|")
+		}
+		switch pd := prop["%docs"].(type) {
+		case float64:
+			field.percent = pd
+		case []interface{}:
+			for _, e := range pd {
+				if f, ok := e.(float64); ok {
+					field.percent += f
+				}
+			}
+		}
+		fields[path] = field
+
+		driftProperties(prop, path+".", fields)
+		switch items := prop["items"].(type) {
+		case map[string]interface{}:
+			driftProperties(items, path+"[].", fields)
+		case []interface{}:
+			for _, i := range items {
+				if item, ok := i.(map[string]interface{}); ok {
+					driftProperties(item, path+"[].", fields)
+				}
+			}
+		}
+	}
+}
+
+/*
+Scheduled comparisons
+*/
+
+type driftCheck struct {
+	baseline  string
+	statement string
+}
+
+// Schedules the next comparison, which runs the same INFER statement, without saving a baseline
+func scheduleDriftCheck(context datastore.QueryContext, ks datastore.Keyspace, with value.Value,
+	options *DescribeOptions) errors.Error {
+
+	name := options.CompareTo.ToString()
+	ctx, ok := context.(scheduler.Context)
+	if !ok || ks == nil || ks.Scope() == nil {
+		return errors.NewInferBaselineError("schedule", name, fmt.Errorf("scheduled comparisons require a keyspace"))
+	}
+
+	fields := make(map[string]interface{}, len(with.Fields()))
+	for n, v := range with.Fields() {
+		if n != "save_baseline" {
+			fields[n] = v
+		}
+	}
+	w, e := json.Marshal(fields)
+	if e != nil {
+		return errors.NewInferBaselineError("schedule", name, e)
+	}
+	s := ks.Scope()
+	statement := "INFER `" + ks.NamespaceId() + "`:`" + s.BucketId() + "`.`" + s.Name() + "`.`" + ks.Name() +
+		"` WITH " + string(w)
+
+	id, e := util.UUIDV4()
+	if e != nil {
+		return errors.NewInferBaselineError("schedule", name, e)
+	}
+	err := scheduler.ScheduleTask(id, _DRIFT_TASK_CLASS, name, options.Interval, execDriftCheck, nil,
+		&driftCheck{baseline: name, statement: statement}, statement, ctx)
+	if err != nil {
+		return errors.NewInferBaselineError("schedule", name, err)
+	}
+	return nil
+}
+
+func execDriftCheck(context scheduler.Context, parms interface{}) (interface{}, []errors.Error) {
+	check := parms.(*driftCheck)
+	rv, _, err := context.EvaluateStatement(check.statement, nil, nil, false, false, false, "")
+	if err != nil {
+		logging.Errorf("Scheduled comparison with baseline %v failed: %v", check.baseline, err)
+		return nil, []errors.Error{errors.NewInferBaselineError("compare", check.baseline, err)}
+	}
+	return rv, nil
+}
+
+/*
+Drift found, for system:vitals
+*/
+
+const _MAX_DRIFT_ENTRIES = 64
+
+type driftEntry struct {
+	baseline string
+	changes  int
+	time     time.Time
+}
+
+var drift struct {
+	sync.Mutex
+	checks   int64
+	detected int64
+	latest   map[string]*driftEntry
+}
+
+func recordDrift(keyspace string, baseline string, changes int) {
+	drift.Lock()
+	defer drift.Unlock()
+	drift.checks++
+	if changes == 0 {
+		return
+	}
+	drift.detected++
+	if drift.latest == nil {
+		drift.latest = make(map[string]*driftEntry)
+	}
+
+	// keep the most recent drift for a limited number of keyspaces
+	if _, ok := drift.latest[keyspace]; !ok && len(drift.latest) >= _MAX_DRIFT_ENTRIES {
+		oldest := ""
+		for k, v := range drift.latest {
+			if oldest == "" || v.time.Before(drift.latest[oldest].time) {
+				oldest = k
+			}
+		}
+		delete(drift.latest, oldest)
+	}
+	drift.latest[keyspace] = &driftEntry{baseline: baseline, changes: changes, time: time.Now()}
+}
+
+// Adds the counts of comparisons and drift found, and the drift last found per keyspace
+func DriftStats(prefix string, res map[string]interface{}) {
+	drift.Lock()
+	defer drift.Unlock()
+	res[prefix+"checks"] = drift.checks
+	res[prefix+"detected"] = drift.detected
+	if len(drift.latest) > 0 {
+		latest := make(map[string]interface{}, len(drift.latest))
+		for k, v := range drift.latest {
+			latest[k] = map[string]interface{}{
+				"baseline": v.baseline,
+				"changes":  v.changes,
+				"time":     v.time.Format(util.DEFAULT_FORMAT),
+			}
+		}
+		res[prefix+"latest"] = latest
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026-Present Couchbase, Inc.
+
+Use of this software is governed by the Business Source License included in
+the file licenses/BSL-Couchbase.txt.  As of the Change Date specified in that
+file, in accordance with the Business Source License, use of this software will
+be governed by the Apache License, Version 2.0, included in the file
+licenses/APL2.txt.
+*/
+
+package inferencer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/couchbase/query/value"
+)
+
+func driftSchema(t *testing.T, schema string) value.Value {
+	t.Helper()
+	var rv interface{}
+	if err := json.Unmarshal([]byte(schema), &rv); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return value.NewValue(rv)
+}
+
+const _DRIFT_BASELINE = `[
+	{"Flavor": "type = \"order\"", "properties": {
+		"id": {"%docs": 100, "type": "number"},
+		"note": {"%docs": 50, "type": "string"},
+		"total": {"%docs": 95, "type": "number"},
+		"lines": {"%docs": 100, "type": "array", "items": {"type": "object", "properties": {
+			"qty": {"%docs": 100, "type": "number"}}}}}},
+	{"Flavor": "type = \"user\"", "properties": {"name": {"%docs": 100, "type": "string"}}},
+	{"Flavor": "", "properties": {
+		"a": {"%docs": 100, "type": "string"},
+		"b": {"%docs": 100, "type": "string"},
+		"c": {"%docs": 100, "type": "string"}}}]`
+
+func TestCompareSchemas(t *testing.T) {
+	baseline := driftSchema(t, _DRIFT_BASELINE)
+
+	// a schema is unchanged from itself, including when it is the results of an INFER statement
+	report, changes, err := CompareSchemas(baseline, driftSchema(t, "["+_DRIFT_BASELINE+"]"), _DEF_DRIFT_THRESHOLD)
+	if err != nil || changes != 0 || report["drift"] != false {
+		t.Fatalf("expected no drift, received %v %v: %v", changes, report, err)
+	}
+
+	schema := driftSchema(t, `[
+		{"Flavor": "", "properties": {
+			"a": {"%docs": 100, "type": "string"},
+			"b": {"%docs": 100, "type": "string"},
+			"d": {"%docs": 100, "type": "string"}}},
+		{"Flavor": "type = \"order\"", "properties": {
+			"id": {"%docs": [90, 10], "type": ["string", "number"]},
+			"note": {"%docs": 80, "type": "string"},
+			"total": {"%docs": 100, "type": "number"},
+			"lines": {"%docs": 100, "type": "array", "items": {"type": "object", "properties": {
+				"price": {"%docs": 100, "type": "number"}}}}}},
+		{"Flavor": "type = \"invoice\"", "properties": {"amount": {"%docs": 100, "type": "number"}}}]`)
+	report, changes, err = CompareSchemas(baseline, schema, _DEF_DRIFT_THRESHOLD)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// flavors are paired by descriptor, or by the top level fields they share;
+	// shifts in %docs below the threshold are not drift
+	expected := driftSchema(t, `{"drift": true, "changes": 8, "flavors": [
+		{"flavor": "", "status": "changed", "added_fields": ["d"], "removed_fields": ["c"]},
+		{"flavor": "type = \"order\"", "status": "changed",
+			"added_fields": ["lines[].price"], "removed_fields": ["lines[].qty"],
+			"type_changes": [{"field": "id", "from": "number", "to": "number|string"}],
+			"presence_changes": [{"field": "note", "from": 50, "to": 80}]},
+		{"flavor": "type = \"invoice\"", "status": "added"},
+		{"flavor": "type = \"user\"", "status": "removed"}]}`)
+	if changes != 8 || !value.NewValue(report).EquivalentTo(expected) {
+		t.Errorf("expected %v changes\n%v\nreceived %v\n%v", 8, expected, changes, value.NewValue(report))
+	}
+
+	// flavors sharing too few fields are not paired
+	schema = driftSchema(t, `[{"Flavor": "", "properties": {
+		"a": {"%docs": 100, "type": "string"},
+		"x": {"%docs": 100, "type": "string"},
+		"y": {"%docs": 100, "type": "string"}}}]`)
+	report, _, err = CompareSchemas(driftSchema(t, `[{"Flavor": "", "properties": {
+		"a": {"%docs": 100, "type": "string"},
+		"b": {"%docs": 100, "type": "string"}}}]`), schema, _DEF_DRIFT_THRESHOLD)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected = driftSchema(t, `[{"flavor": "", "status": "added"}, {"flavor": "", "status": "removed"}]`)
+	if !value.NewValue(report["flavors"]).EquivalentTo(expected) {
+		t.Errorf("expected %v, received %v", expected, value.NewValue(report["flavors"]))
+	}
+
+	for _, s := range []string{`{"Flavor": ""}`, `[1, 2]`} {
+		if _, _, err = CompareSchemas(baseline, driftSchema(t, s), _DEF_DRIFT_THRESHOLD); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestDriftStats(t *testing.T) {
+	res := make(map[string]interface{})
+	DriftStats("infer.drift.", res)
+	checks, detected := res["infer.drift.checks"].(int64), res["infer.drift.detected"].(int64)
+
+	recordDrift("default:b.s.orders", "v1", 0)
+	recordDrift("default:b.s.orders", "v1", 3)
+	DriftStats("infer.drift.", res)
+	if res["infer.drift.checks"] != checks+2 || res["infer.drift.detected"] != detected+1 {
+		t.Errorf("expected two more checks and one more detected, received %v", res)
+	}
+	latest, _ := res["infer.drift.latest"].(map[string]interface{})
+	entry, _ := latest["default:b.s.orders"].(map[string]interface{})
+	if entry["baseline"] != "v1" || entry["changes"] != 3 {
+		t.Errorf("expected the latest drift for orders, received %v", latest)
+	}
+}
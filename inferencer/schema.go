@@ -487,10 +487,12 @@ func NewField(name string, val value.Value, arraySampleSize int32, maxNestingDep
 
 // make a Dictionary Field
 
+const _DICTIONARY_FIELD = "Dictionary(string-to-Object)"
+
 func NewDictionaryField(ftype FieldType, sampleValues value.Values) Field {
 	field := new(Field)
 	field.isDictionary = true
-	field.Name = "Dictionary(string-to-Object)"
+	field.Name = _DICTIONARY_FIELD
 	field.Kind = ftype
 	field.sampleValues = sampleValues
 	field.namesake = nil
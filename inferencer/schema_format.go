@@ -0,0 +1,712 @@
+/*
+Copyright 2026-Present Couchbase, Inc.
+
+Use of this software is governed by the Business Source License included in
+the file licenses/BSL-Couchbase.txt.  As of the Change Date specified in that
+file, in accordance with the Business Source License, use of this software will
+be governed by the Apache License, Version 2.0, included in the file
+licenses/APL2.txt.
+*/
+
+package inferencer
+
+/*
+ * schema_format.go converts flavors into formats understood by tools other than our own:
+ *
+ *  jsonschema - a JSON Schema draft 2020-12 document
+ *  avro       - an Avro record schema
+ *  typescript - a TypeScript interface declaration
+ *  go         - a Go struct type declaration
+ *
+ * The flavor's JSON description is first reduced to a neutral model of types and fields, which
+ * each format then renders. A field is required when it is present in every document (or every
+ * containing object) of the flavor, i.e. when its %docs reaches 100; otherwise it is optional.
+ * Numbers whose sample values are all whole are described as integers.
+ */
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/couchbase/query/value"
+)
+
+const (
+	FORMAT_JSONSCHEMA = "jsonschema"
+	FORMAT_AVRO       = "avro"
+	FORMAT_TYPESCRIPT = "typescript"
+	FORMAT_GO         = "go"
+)
+
+var formats = map[string]bool{
+	FORMAT_JSONSCHEMA: true,
+	FORMAT_AVRO:       true,
+	FORMAT_TYPESCRIPT: true,
+	FORMAT_GO:         true,
+}
+
+func IsFormat(name string) bool {
+	return formats[name]
+}
+
+const _JSON_SCHEMA_2020_12 = "https://json-schema.org/draft/2020-12/schema"
+
+// kinds of types in the neutral model
+const (
+	_KIND_ANY     = "any"
+	_KIND_NULL    = "null"
+	_KIND_BOOLEAN = "boolean"
+	_KIND_INTEGER = "integer"
+	_KIND_NUMBER  = "number"
+	_KIND_STRING  = "string"
+	_KIND_BINARY  = "binary"
+	_KIND_ARRAY   = "array"
+	_KIND_OBJECT  = "object"
+)
+
+type formatType struct {
+	kind   string
+	items  []*formatType  // array element alternatives
+	fields []*formatField // object fields
+	dict   *formatType    // object collapsed to a dictionary of values of this type
+}
+
+type formatField struct {
+	name     string
+	types    []*formatType // alternatives, excluding null
+	nullable bool
+	required bool
+}
+
+/*
+Converts a flavor, as marshalled by SchemaFlavor.MarshalJSON(), to the target format.
+JSON Schema and Avro schemas are returned as JSON documents, TypeScript and Go type
+declarations as strings. The name is used for the type declared.
+*/
+func FormatFlavor(flavor []byte, target string, name string) (interface{}, error) {
+	var desc map[string]interface{}
+	err := json.Unmarshal(flavor, &desc)
+	if err != nil {
+		return nil, err
+	}
+	root := formatObject(desc)
+	comment, _ := desc["Flavor"].(string)
+
+	switch target {
+	case FORMAT_JSONSCHEMA:
+		rv := jsonSchemaType(root)
+		rv["$schema"] = _JSON_SCHEMA_2020_12
+		rv["title"] = name
+		if comment != "" {
+			rv["description"] = comment
+		}
+		return rv, nil
+	case FORMAT_AVRO:
+		a := &avroNamer{names: make(map[string]bool)}
+		rv := a.record(root, name)
+		if comment != "" {
+			rv["doc"] = comment
+		}
+		return rv, nil
+	case FORMAT_TYPESCRIPT:
+		buf := bytes.NewBuffer(make([]byte, 0, 256))
+		if comment != "" {
+			buf.WriteString("/** Documents with " + strings.ReplaceAll(comment, "*/", "* /") + " */\n")
+		}
+		buf.WriteString("export interface " + goIdentifier(name) + " ")
+		typeScriptObject(buf, root, "")
+		buf.WriteString("\n")
+		return buf.String(), nil
+	case FORMAT_GO:
+		buf := bytes.NewBuffer(make([]byte, 0, 256))
+		if comment != "" {
+			buf.WriteString("// " + goIdentifier(name) + " describes documents with " + strings.ReplaceAll(comment, "\n", " ") + "\n")
+		}
+		buf.WriteString("type " + goIdentifier(name) + " ")
+		goStruct(buf, root, "")
+		buf.WriteString("\n")
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return buf.String(), nil
+		}
+		return string(formatted), nil
+	}
+	return nil, fmt.Errorf("unknown format %s", target)
+}
+
+// Converts each of the flavors of a schema returned by DescribeKeyspace to the target format
+func FormatSchema(schema value.Value, target string) value.Value {
+	flavors, ok := schema.Actual().([]interface{})
+	if !ok {
+		return schema
+	}
+	rv := make([]value.Value, len(flavors))
+	for idx, f := range flavors {
+		bytes, err := json.Marshal(f)
+		if err == nil {
+			var formatted interface{}
+			formatted, err = FormatFlavor(bytes, target, fmt.Sprintf("Flavor%d", idx+1))
+			if err == nil {
+				rv[idx] = value.NewValue(formatted)
+				continue
+			}
+		}
+		rv[idx] = value.NewValue(err.Error())
+	}
+	return value.NewValue(rv)
+}
+
+/*
+The neutral model, from the flavor's description
+*/
+
+func formatObject(desc map[string]interface{}) *formatType {
+	rv := &formatType{kind: _KIND_OBJECT}
+	props, _ := desc["properties"].(map[string]interface{})
+	names := make([]string, 0, len(props))
+	for name, _ := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := props[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field := formatProperty(name, prop)
+
+		// a dictionary is an object whose fields are all alike
+		if name == _DICTIONARY_FIELD && len(props) == 1 {
+			rv.dict = formatUnion(field.types)
+			return rv
+		}
+		rv.fields = append(rv.fields, field)
+	}
+	return rv
+}
+
+func formatProperty(name string, prop map[string]interface{}) *formatField {
+	rv := &formatField{name: name}
+
+	// namesakes, i.e. fields with values of differing types, have arrays of types, %docs and samples
+	var typeNames []string
+	var samples []interface{}
+	switch t := prop["type"].(type) {
+	case string:
+		typeNames = []string{t}
+		samples = []interface{}{prop["samples"]}
+	case []interface{}:
+		s, _ := prop["samples"].([]interface{})
+		for i, n := range t {
+			typeNames = append(typeNames, fmt.Sprintf("%v", n))
+			if i < len(s) {
+				samples = append(samples, s[i])
+			} else {
+				samples = append(samples, nil)
+			}
+		}
+	}
+
+	percent := 0.0
+	switch p := prop["%docs"].(type) {
+	case float64:
+		percent = p
+	case []interface{}:
+		for _, e := range p {
+			if f, ok := e.(float64); ok {
+				percent += f
+			}
+		}
+	}
+	rv.required = percent >= 100.0
+
+	for i, n := range typeNames {
+		switch n {
+		case "missing":
+		case "null":
+			rv.nullable = true
+		default:
+			rv.types = append(rv.types, formatScalarOrNested(n, prop, samples[i]))
+		}
+	}
+	if len(rv.types) == 0 && !rv.nullable {
+		rv.types = []*formatType{&formatType{kind: _KIND_ANY}}
+	}
+	return rv
+}
+
+func formatScalarOrNested(typeName string, desc map[string]interface{}, samples interface{}) *formatType {
+	switch typeName {
+	case "object":
+		return formatObject(desc)
+	case "array":
+		rv := &formatType{kind: _KIND_ARRAY}
+		switch items := desc["items"].(type) {
+		case map[string]interface{}:
+			rv.items = formatItems(items)
+		case []interface{}:
+			for _, i := range items {
+				if item, ok := i.(map[string]interface{}); ok {
+					rv.items = append(rv.items, formatItems(item)...)
+				}
+			}
+		}
+		return rv
+	case "number":
+		if wholeNumbers(samples) {
+			return &formatType{kind: _KIND_INTEGER}
+		}
+		return &formatType{kind: _KIND_NUMBER}
+	case "boolean":
+		return &formatType{kind: _KIND_BOOLEAN}
+	case "string":
+		return &formatType{kind: _KIND_STRING}
+	case "binary":
+		return &formatType{kind: _KIND_BINARY}
+	}
+	return &formatType{kind: _KIND_ANY}
+}
+
+func formatItems(item map[string]interface{}) []*formatType {
+	typeName, _ := item["type"].(string)
+	switch typeName {
+	case "":
+		return nil
+	case "null", "missing":
+		return []*formatType{&formatType{kind: _KIND_NULL}}
+	}
+	return []*formatType{formatScalarOrNested(typeName, item, nil)}
+}
+
+func wholeNumbers(samples interface{}) bool {
+	s, ok := samples.([]interface{})
+	if !ok || len(s) == 0 {
+		return false
+	}
+	for _, v := range s {
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// a single type standing for a set of alternatives: itself if there is only one, or any
+func formatUnion(types []*formatType) *formatType {
+	if len(types) == 1 {
+		return types[0]
+	}
+	return &formatType{kind: _KIND_ANY}
+}
+
+// the element type of an array, with null elements set apart
+func arrayElements(t *formatType) ([]*formatType, bool) {
+	var rv []*formatType
+	nullable := false
+	for _, i := range t.items {
+		if i.kind == _KIND_NULL {
+			nullable = true
+		} else {
+			rv = append(rv, i)
+		}
+	}
+	return rv, nullable
+}
+
+/*
+JSON Schema
+*/
+
+func jsonSchemaType(t *formatType) map[string]interface{} {
+	switch t.kind {
+	case _KIND_ANY:
+		return map[string]interface{}{}
+	case _KIND_BINARY:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	case _KIND_ARRAY:
+		rv := map[string]interface{}{"type": "array"}
+		elems, nullable := arrayElements(t)
+		if len(elems) > 0 || nullable {
+			rv["items"] = jsonSchemaUnion(elems, nullable)
+		}
+		return rv
+	case _KIND_OBJECT:
+		rv := map[string]interface{}{"type": "object"}
+		if t.dict != nil {
+			rv["additionalProperties"] = jsonSchemaType(t.dict)
+			return rv
+		}
+		props := make(map[string]interface{}, len(t.fields))
+		required := make([]interface{}, 0, len(t.fields))
+		for _, f := range t.fields {
+			props[f.name] = jsonSchemaUnion(f.types, f.nullable)
+			if f.required {
+				required = append(required, f.name)
+			}
+		}
+		rv["properties"] = props
+		if len(required) > 0 {
+			rv["required"] = required
+		}
+		return rv
+	}
+	return map[string]interface{}{"type": t.kind}
+}
+
+func jsonSchemaUnion(types []*formatType, nullable bool) map[string]interface{} {
+	if len(types) == 1 && !nullable {
+		return jsonSchemaType(types[0])
+	}
+
+	// simple types can be listed together, others need alternative subschemas
+	simple := true
+	for _, t := range types {
+		switch t.kind {
+		case _KIND_ANY:
+			return map[string]interface{}{}
+		case _KIND_ARRAY, _KIND_OBJECT, _KIND_BINARY:
+			simple = false
+		}
+	}
+	if simple {
+		names := make([]interface{}, 0, len(types)+1)
+		for _, t := range types {
+			names = append(names, t.kind)
+		}
+		if nullable {
+			names = append(names, _KIND_NULL)
+		}
+		if len(names) == 1 {
+			return map[string]interface{}{"type": names[0]}
+		}
+		return map[string]interface{}{"type": names}
+	}
+	alternatives := make([]interface{}, 0, len(types)+1)
+	for _, t := range types {
+		alternatives = append(alternatives, jsonSchemaType(t))
+	}
+	if nullable {
+		alternatives = append(alternatives, map[string]interface{}{"type": _KIND_NULL})
+	}
+	return map[string]interface{}{"anyOf": alternatives}
+}
+
+/*
+Avro
+
+Avro has no type for values of any type, which are described as strings holding their JSON
+text. Unions may hold only one array and one map, so further ones are dropped.
+*/
+
+type avroNamer struct {
+	names map[string]bool
+}
+
+// record names need to be unique within the schema
+func (this *avroNamer) unique(name string) string {
+	name = avroName(name)
+	rv := name
+	for i := 2; this.names[rv]; i++ {
+		rv = name + strconv.Itoa(i)
+	}
+	this.names[rv] = true
+	return rv
+}
+
+func (this *avroNamer) record(t *formatType, name string) map[string]interface{} {
+	rv := map[string]interface{}{"type": "record", "name": this.unique(name)}
+	fields := make([]interface{}, 0, len(t.fields))
+	fieldNames := make(map[string]bool, len(t.fields))
+	for _, f := range t.fields {
+		fieldName := avroName(f.name)
+		n := fieldName
+		for i := 2; fieldNames[n]; i++ {
+			n = fieldName + strconv.Itoa(i)
+		}
+		fieldNames[n] = true
+
+		field := map[string]interface{}{"name": n}
+		if n != f.name {
+			field["doc"] = f.name
+		}
+		nullable := f.nullable || !f.required
+		field["type"] = this.union(f.types, nullable, name+"_"+n)
+		if nullable {
+			field["default"] = nil
+		}
+		fields = append(fields, field)
+	}
+	rv["fields"] = fields
+	return rv
+}
+
+func (this *avroNamer) avroType(t *formatType, name string) interface{} {
+	switch t.kind {
+	case _KIND_ANY, _KIND_STRING:
+		return "string"
+	case _KIND_NULL:
+		return "null"
+	case _KIND_BOOLEAN:
+		return "boolean"
+	case _KIND_INTEGER:
+		return "long"
+	case _KIND_NUMBER:
+		return "double"
+	case _KIND_BINARY:
+		return "bytes"
+	case _KIND_ARRAY:
+		elems, nullable := arrayElements(t)
+		var items interface{} = "string"
+		if len(elems) == 0 && nullable {
+			items = "null"
+		} else if len(elems) > 0 {
+			items = this.union(elems, nullable, name+"_item")
+		}
+		return map[string]interface{}{"type": "array", "items": items}
+	case _KIND_OBJECT:
+		if t.dict != nil {
+			return map[string]interface{}{"type": "map", "values": this.avroType(t.dict, name+"_value")}
+		}
+		return this.record(t, name)
+	}
+	return "string"
+}
+
+func (this *avroNamer) union(types []*formatType, nullable bool, name string) interface{} {
+	if len(types) == 1 && !nullable {
+		return this.avroType(types[0], name)
+	}
+
+	// null comes first, so that it can be the default
+	rv := make([]interface{}, 0, len(types)+1)
+	seen := make(map[string]bool, len(types)+1)
+	if nullable {
+		rv = append(rv, "null")
+		seen["null"] = true
+	}
+	records := 0
+	for _, t := range types {
+		key := t.kind
+		if t.kind == _KIND_OBJECT && t.dict == nil {
+			records++
+			key = key + strconv.Itoa(records)
+		} else if t.kind == _KIND_ANY {
+			key = _KIND_STRING
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		rv = append(rv, this.avroType(t, name))
+	}
+	if len(rv) == 1 {
+		return rv[0]
+	}
+	return rv
+}
+
+// Avro names start with a letter or underscore, followed by letters, digits or underscores
+func avroName(name string) string {
+	buf := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			buf = append(buf, c)
+		} else {
+			buf = append(buf, '_')
+		}
+	}
+	if len(buf) == 0 || (buf[0] >= '0' && buf[0] <= '9') {
+		buf = append([]byte{'_'}, buf...)
+	}
+	return string(buf)
+}
+
+/*
+TypeScript
+*/
+
+func typeScriptObject(buf *bytes.Buffer, t *formatType, indent string) {
+	if t.dict != nil {
+		buf.WriteString("Record<string, ")
+		typeScriptType(buf, t.dict, indent)
+		buf.WriteString(">")
+		return
+	}
+	if len(t.fields) == 0 {
+		buf.WriteString("{}")
+		return
+	}
+	buf.WriteString("{\n")
+	for _, f := range t.fields {
+		buf.WriteString(indent + "  " + typeScriptName(f.name))
+		if !f.required {
+			buf.WriteString("?")
+		}
+		buf.WriteString(": ")
+		typeScriptUnion(buf, f.types, f.nullable, indent+"  ")
+		buf.WriteString(";\n")
+	}
+	buf.WriteString(indent + "}")
+}
+
+func typeScriptType(buf *bytes.Buffer, t *formatType, indent string) {
+	switch t.kind {
+	case _KIND_ANY:
+		buf.WriteString("unknown")
+	case _KIND_INTEGER, _KIND_NUMBER:
+		buf.WriteString("number")
+	case _KIND_BINARY:
+		buf.WriteString("string")
+	case _KIND_ARRAY:
+		elems, nullable := arrayElements(t)
+		if len(elems) == 0 {
+			buf.WriteString("unknown[]")
+		} else if len(elems) == 1 && !nullable && elems[0].kind != _KIND_OBJECT {
+			typeScriptType(buf, elems[0], indent)
+			buf.WriteString("[]")
+		} else {
+			buf.WriteString("Array<")
+			typeScriptUnion(buf, elems, nullable, indent)
+			buf.WriteString(">")
+		}
+	case _KIND_OBJECT:
+		typeScriptObject(buf, t, indent)
+	default:
+		buf.WriteString(t.kind)
+	}
+}
+
+func typeScriptUnion(buf *bytes.Buffer, types []*formatType, nullable bool, indent string) {
+	for i, t := range types {
+		if i > 0 {
+			buf.WriteString(" | ")
+		}
+		typeScriptType(buf, t, indent)
+	}
+	if nullable {
+		if len(types) > 0 {
+			buf.WriteString(" | ")
+		}
+		buf.WriteString("null")
+	}
+}
+
+func typeScriptName(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return strconv.Quote(name)
+		}
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
+
+/*
+Go
+
+Optional and nullable fields of simple types are pointers, so that their absence can be told
+from their zero value. Fields with values of differing types are interface{}.
+*/
+
+func goStruct(buf *bytes.Buffer, t *formatType, indent string) {
+	if t.dict != nil {
+		buf.WriteString("map[string]")
+		goType(buf, t.dict, indent)
+		return
+	}
+	if len(t.fields) == 0 {
+		buf.WriteString("struct{}")
+		return
+	}
+	buf.WriteString("struct {\n")
+	names := make(map[string]bool, len(t.fields))
+	for _, f := range t.fields {
+		ident := goIdentifier(f.name)
+		n := ident
+		for i := 2; names[n]; i++ {
+			n = ident + strconv.Itoa(i)
+		}
+		names[n] = true
+
+		buf.WriteString(indent + "\t" + n + " ")
+		if len(f.types) == 1 {
+			t := f.types[0]
+			if (f.nullable || !f.required) && t.kind != _KIND_ARRAY && t.kind != _KIND_BINARY && t.kind != _KIND_ANY &&
+				t.dict == nil {
+				buf.WriteString("*")
+			}
+			goType(buf, t, indent+"\t")
+		} else {
+			buf.WriteString("interface{}")
+		}
+		tag := strings.ReplaceAll(strings.ReplaceAll(f.name, "\\", "\\\\"), "\"", "\\\"")
+		if !f.required {
+			tag += ",omitempty"
+		}
+		buf.WriteString(" `json:\"" + strings.ReplaceAll(tag, "`", "'") + "\"`\n")
+	}
+	buf.WriteString(indent + "}")
+}
+
+func goType(buf *bytes.Buffer, t *formatType, indent string) {
+	switch t.kind {
+	case _KIND_BOOLEAN:
+		buf.WriteString("bool")
+	case _KIND_INTEGER:
+		buf.WriteString("int64")
+	case _KIND_NUMBER:
+		buf.WriteString("float64")
+	case _KIND_STRING:
+		buf.WriteString("string")
+	case _KIND_BINARY:
+		buf.WriteString("[]byte")
+	case _KIND_ARRAY:
+		buf.WriteString("[]")
+		elems, nullable := arrayElements(t)
+		if len(elems) != 1 {
+			buf.WriteString("interface{}")
+		} else {
+			if nullable && elems[0].kind != _KIND_ARRAY && elems[0].kind != _KIND_BINARY && elems[0].dict == nil {
+				buf.WriteString("*")
+			}
+			goType(buf, elems[0], indent)
+		}
+	case _KIND_OBJECT:
+		goStruct(buf, t, indent)
+	default:
+		buf.WriteString("interface{}")
+	}
+}
+
+// an exported Go identifier, in camel case, for a field name
+func goIdentifier(name string) string {
+	buf := make([]rune, 0, len(name))
+	upper := true
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			buf = append(buf, r)
+		} else {
+			upper = true
+		}
+	}
+	if len(buf) == 0 || !unicode.IsLetter(buf[0]) {
+		buf = append([]rune{'X'}, buf...)
+	}
+	return string(buf)
+}
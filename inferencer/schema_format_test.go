@@ -0,0 +1,210 @@
+/*
+Copyright 2026-Present Couchbase, Inc.
+
+Use of this software is governed by the Business Source License included in
+the file licenses/BSL-Couchbase.txt.  As of the Change Date specified in that
+file, in accordance with the Business Source License, use of this software will
+be governed by the Apache License, Version 2.0, included in the file
+licenses/APL2.txt.
+*/
+
+package inferencer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/couchbase/query/value"
+)
+
+// A flavor as returned by INFER, with a field of each kind
+const _ORDER_FLAVOR = `{"#docs": 5, "Flavor": "` + "`type` = \\\"order\\\"" + `", "type": "object", "properties": {
+	"id": {"%docs": 100, "samples": [1, 2], "type": "number"},
+	"price": {"%docs": 100, "samples": [1.5, 2], "type": "number"},
+	"note": {"%docs": 40, "samples": ["x"], "type": "string"},
+	"code": {"%docs": [60, 40], "samples": [["a"], [null]], "type": ["string", "null"]},
+	"mixed": {"%docs": [50, 50], "samples": [["a"], [1]], "type": ["string", "number"]},
+	"tags": {"%docs": 100, "items": {"type": "string"}, "type": "array"},
+	"ship-to": {"%docs": 100, "type": "object", "properties": {
+		"city": {"%docs": 100, "samples": ["Paris"], "type": "string"}}},
+	"attrs": {"%docs": 100, "type": "object", "properties": {
+		"Dictionary(string-to-Object)": {"%docs": 100, "samples": [1], "type": "number"}}}}}`
+
+func formatFlavor(t *testing.T, flavor string, target string) string {
+	t.Helper()
+	rv, err := FormatFlavor([]byte(flavor), target, "order")
+	if err != nil {
+		t.Fatalf("%s: unexpected error %v", target, err)
+	}
+	if s, ok := rv.(string); ok {
+		return s
+	}
+	bytes, err := json.Marshal(rv)
+	if err != nil {
+		t.Fatalf("%s: unexpected error %v", target, err)
+	}
+	return string(bytes)
+}
+
+func TestFormatFlavor(t *testing.T) {
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{FORMAT_JSONSCHEMA, `{"$schema":"https://json-schema.org/draft/2020-12/schema",` +
+			`"description":"` + "`type` = \\\"order\\\"" + `","properties":{` +
+			`"attrs":{"additionalProperties":{"type":"integer"},"type":"object"},` +
+			`"code":{"type":["string","null"]},"id":{"type":"integer"},"mixed":{"type":["string","integer"]},` +
+			`"note":{"type":"string"},"price":{"type":"number"},` +
+			`"ship-to":{"properties":{"city":{"type":"string"}},"required":["city"],"type":"object"},` +
+			`"tags":{"items":{"type":"string"},"type":"array"}},` +
+			`"required":["attrs","code","id","mixed","price","ship-to","tags"],"title":"order","type":"object"}`},
+		{FORMAT_AVRO, `{"doc":"` + "`type` = \\\"order\\\"" + `","fields":[` +
+			`{"name":"attrs","type":{"type":"map","values":"long"}},` +
+			`{"default":null,"name":"code","type":["null","string"]},{"name":"id","type":"long"},` +
+			`{"name":"mixed","type":["string","long"]},{"default":null,"name":"note","type":["null","string"]},` +
+			`{"name":"price","type":"double"},{"doc":"ship-to","name":"ship_to","type":` +
+			`{"fields":[{"name":"city","type":"string"}],"name":"order_ship_to","type":"record"}},` +
+			`{"name":"tags","type":{"items":"string","type":"array"}}],"name":"order","type":"record"}`},
+		{FORMAT_TYPESCRIPT, "/** Documents with `type` = \"order\" */\n" +
+			"export interface Order {\n" +
+			"  attrs: Record<string, number>;\n" +
+			"  code: string | null;\n" +
+			"  id: number;\n" +
+			"  mixed: string | number;\n" +
+			"  note?: string;\n" +
+			"  price: number;\n" +
+			"  \"ship-to\": {\n" +
+			"    city: string;\n" +
+			"  };\n" +
+			"  tags: string[];\n" +
+			"}\n"},
+		{FORMAT_GO, "// Order describes documents with `type` = \"order\"\n" +
+			"type Order struct {\n" +
+			"\tAttrs  map[string]int64 `json:\"attrs\"`\n" +
+			"\tCode   *string          `json:\"code\"`\n" +
+			"\tId     int64            `json:\"id\"`\n" +
+			"\tMixed  interface{}      `json:\"mixed\"`\n" +
+			"\tNote   *string          `json:\"note,omitempty\"`\n" +
+			"\tPrice  float64          `json:\"price\"`\n" +
+			"\tShipTo struct {\n" +
+			"\t\tCity string `json:\"city\"`\n" +
+			"\t} `json:\"ship-to\"`\n" +
+			"\tTags []string `json:\"tags\"`\n" +
+			"}\n"},
+	}
+
+	for _, test := range tests {
+		if s := formatFlavor(t, _ORDER_FLAVOR, test.target); s != test.expected {
+			t.Errorf("%s: expected\n%s\nreceived\n%s", test.target, test.expected, s)
+		}
+	}
+
+	if _, err := FormatFlavor([]byte(_ORDER_FLAVOR), "xml", "order"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+	if _, err := FormatFlavor([]byte("[1, 2]"), FORMAT_JSONSCHEMA, "order"); err == nil {
+		t.Errorf("expected an error for a flavor that is not an object")
+	}
+}
+
+func TestFormatArrays(t *testing.T) {
+	// arrays of nullable elements, of objects, and of elements of differing types
+	flavor := `{"type": "object", "properties": {
+		"scores": {"%docs": 100, "items": [{"type": "number"}, {"type": "null"}], "type": "array"},
+		"lines": {"%docs": 100, "items": {"type": "object", "properties": {
+			"qty": {"%docs": 100, "type": "number"}}}, "type": "array"},
+		"misc": {"%docs": 100, "items": [{"type": "string"}, {"type": "boolean"}], "type": "array"},
+		"blob": {"%docs": 100, "type": "binary"},
+		"any": {"%docs": 100, "type": "unknown"}}}`
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{FORMAT_JSONSCHEMA, `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{` +
+			`"any":{},"blob":{"contentEncoding":"base64","type":"string"},` +
+			`"lines":{"items":{"properties":{"qty":{"type":"number"}},"required":["qty"],"type":"object"},"type":"array"},` +
+			`"misc":{"items":{"type":["string","boolean"]},"type":"array"},` +
+			`"scores":{"items":{"type":["number","null"]},"type":"array"}},` +
+			`"required":["any","blob","lines","misc","scores"],"title":"order","type":"object"}`},
+		{FORMAT_AVRO, `{"fields":[{"name":"any","type":"string"},{"name":"blob","type":"bytes"},` +
+			`{"name":"lines","type":{"items":{"fields":[{"name":"qty","type":"double"}],"name":"order_lines_item",` +
+			`"type":"record"},"type":"array"}},{"name":"misc","type":{"items":["string","boolean"],"type":"array"}},` +
+			`{"name":"scores","type":{"items":["null","double"],"type":"array"}}],"name":"order","type":"record"}`},
+		{FORMAT_TYPESCRIPT, "export interface Order {\n" +
+			"  any: unknown;\n" +
+			"  blob: string;\n" +
+			"  lines: Array<{\n" +
+			"    qty: number;\n" +
+			"  }>;\n" +
+			"  misc: Array<string | boolean>;\n" +
+			"  scores: Array<number | null>;\n" +
+			"}\n"},
+		{FORMAT_GO, "type Order struct {\n" +
+			"\tAny   interface{} `json:\"any\"`\n" +
+			"\tBlob  []byte      `json:\"blob\"`\n" +
+			"\tLines []struct {\n" +
+			"\t\tQty float64 `json:\"qty\"`\n" +
+			"\t} `json:\"lines\"`\n" +
+			"\tMisc   []interface{} `json:\"misc\"`\n" +
+			"\tScores []*float64    `json:\"scores\"`\n" +
+			"}\n"},
+	}
+
+	for _, test := range tests {
+		if s := formatFlavor(t, flavor, test.target); s != test.expected {
+			t.Errorf("%s: expected\n%s\nreceived\n%s", test.target, test.expected, s)
+		}
+	}
+}
+
+func TestFormatNames(t *testing.T) {
+	for name, expected := range map[string]string{"ship-to": "ship_to", "2nd": "_2nd", "": "_", "été": "__t__"} {
+		if n := avroName(name); n != expected {
+			t.Errorf("avroName(%q): expected %q, received %q", name, expected, n)
+		}
+	}
+	for name, expected := range map[string]string{"ship-to": "ShipTo", "id": "Id", "2nd": "X2nd", "été": "Été"} {
+		if n := goIdentifier(name); n != expected {
+			t.Errorf("goIdentifier(%q): expected %q, received %q", name, expected, n)
+		}
+	}
+	for name, expected := range map[string]string{"ship-to": `"ship-to"`, "$id": "$id", "2nd": `"2nd"`, "": `""`} {
+		if n := typeScriptName(name); n != expected {
+			t.Errorf("typeScriptName(%q): expected %q, received %q", name, expected, n)
+		}
+	}
+
+	// record names are unique across the schema, field names within their record
+	flavor := `{"type": "object", "properties": {
+		"a-b": {"%docs": 100, "type": "object", "properties": {"x": {"%docs": 100, "type": "string"}}},
+		"a_b": {"%docs": 100, "type": "object", "properties": {"y": {"%docs": 100, "type": "string"}}}}}`
+	expected := `{"fields":[{"doc":"a-b","name":"a_b","type":{"fields":[{"name":"x","type":"string"}],` +
+		`"name":"order_a_b","type":"record"}},{"doc":"a_b","name":"a_b2","type":{"fields":[{"name":"y","type":"string"}],` +
+		`"name":"order_a_b2","type":"record"}}],"name":"order","type":"record"}`
+	if s := formatFlavor(t, flavor, FORMAT_AVRO); s != expected {
+		t.Errorf("expected\n%s\nreceived\n%s", expected, s)
+	}
+}
+
+func TestFormatSchema(t *testing.T) {
+	var flavor interface{}
+	if err := json.Unmarshal([]byte(_ORDER_FLAVOR), &flavor); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	schema := FormatSchema(value.NewValue([]interface{}{flavor, flavor}), FORMAT_TYPESCRIPT)
+	for i, name := range []string{"Flavor1", "Flavor2"} {
+		f, ok := schema.Index(i)
+		if !ok || f.Type() != value.STRING || !strings.Contains(f.ToString(), "export interface "+name+" {") {
+			t.Errorf("expected interface %s, received %v", name, f)
+		}
+	}
+
+	// what is not a list of flavors is left as it is
+	s := value.NewValue("no documents")
+	if FormatSchema(s, FORMAT_GO) != s {
+		t.Errorf("expected the schema to be left as it is")
+	}
+}
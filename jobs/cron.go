@@ -0,0 +1,215 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+A cron schedule: minute, hour, day of month, month and day of week, each
+a list of values, ranges and steps, with * for any value. Months and days
+of the week may also be given by their first three letters, and Sunday as
+either 0 or 7. As in cron, when both days are restricted a time matches
+if either does.
+*/
+type Schedule struct {
+	minute   uint64
+	hour     uint64
+	dom      uint64
+	month    uint64
+	dow      uint64
+	anyDay   bool // day of month is *
+	anyWeek  bool // day of week is *
+	location *time.Location
+}
+
+type cronField struct {
+	min   int
+	max   int
+	names []string
+}
+
+var _CRON_FIELDS = [5]cronField{
+	{0, 59, nil},
+	{0, 23, nil},
+	{1, 31, nil},
+	{1, 12, []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{0, 7, []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var _CRON_MACROS = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedules that match no time within this many years are rejected
+const _CRON_YEARS = 5
+
+func ParseSchedule(spec string, timezone string) (*Schedule, error) {
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone '%s'", timezone)
+		}
+	}
+
+	s := strings.TrimSpace(spec)
+	if m, ok := _CRON_MACROS[strings.ToLower(s)]; ok {
+		s = m
+	}
+	fields := strings.Fields(s)
+	if len(fields) != len(_CRON_FIELDS) {
+		return nil, fmt.Errorf("expected %d fields, found %d", len(_CRON_FIELDS), len(fields))
+	}
+
+	rv := &Schedule{location: loc}
+	bits := [5]*uint64{&rv.minute, &rv.hour, &rv.dom, &rv.month, &rv.dow}
+	for i, f := range fields {
+		b, err := _CRON_FIELDS[i].parse(f)
+		if err != nil {
+			return nil, err
+		}
+		*bits[i] = b
+	}
+	rv.anyDay = fields[2] == "*"
+	rv.anyWeek = fields[4] == "*"
+
+	// Sunday is both 0 and 7
+	if rv.dow&(1<<7) != 0 {
+		rv.dow |= 1
+	}
+
+	if rv.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("'%s' never occurs", spec)
+	}
+	return rv, nil
+}
+
+func (this *cronField) parse(s string) (uint64, error) {
+	var rv uint64
+	for _, part := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in '%s'", part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		low, high := this.min, this.max
+		if part != "*" {
+			r := strings.SplitN(part, "-", 2)
+			var err error
+			low, err = this.value(r[0])
+			if err != nil {
+				return 0, err
+			}
+			high = low
+			if len(r) == 2 {
+				high, err = this.value(r[1])
+				if err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// n/step runs from n to the end of the range
+				high = this.max
+			}
+			if high < low {
+				return 0, fmt.Errorf("invalid range '%s'", part)
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			rv |= 1 << uint(v)
+		}
+	}
+	return rv, nil
+}
+
+func (this *cronField) value(s string) (int, error) {
+	for i, n := range this.names {
+		if strings.EqualFold(s, n) {
+			return i + this.min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < this.min || v > this.max {
+		return 0, fmt.Errorf("'%s' is not between %d and %d", s, this.min, this.max)
+	}
+	return v, nil
+}
+
+func (this *Schedule) Location() *time.Location {
+	return this.location
+}
+
+/*
+The first time after t the schedule matches, to the minute, or the zero
+time if it matches none in the following years.
+*/
+func (this *Schedule) Next(t time.Time) time.Time {
+	loc := this.location
+	t = t.In(loc)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	limit := t.Year() + _CRON_YEARS
+
+	for t.Year() <= limit {
+		if this.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !this.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if this.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+
+			// an hour repeated as the clocks go back
+			if !next.After(t) {
+				next = next.Add(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if this.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (this *Schedule) dayMatches(t time.Time) bool {
+	dom := this.dom&(1<<uint(t.Day())) != 0
+	dow := this.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case this.anyDay && this.anyWeek:
+		return true
+	case this.anyDay:
+		return dow
+	case this.anyWeek:
+		return dom
+	}
+	return dom || dow
+}
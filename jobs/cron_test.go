@@ -0,0 +1,71 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		spec string
+		from string
+		next string
+	}{
+		{"* * * * *", "2026-10-18T10:15:30Z", "2026-10-18T10:16:00Z"},
+		{"*/15 * * * *", "2026-10-18T10:15:00Z", "2026-10-18T10:30:00Z"},
+		{"0 3 * * *", "2026-10-18T10:15:00Z", "2026-10-19T03:00:00Z"},
+		{"30 2 1 * *", "2026-10-18T10:15:00Z", "2026-11-01T02:30:00Z"},
+		{"0 0 * * mon-fri", "2026-10-17T10:15:00Z", "2026-10-19T00:00:00Z"},
+		{"0 0 * * 7", "2026-10-12T10:15:00Z", "2026-10-18T00:00:00Z"},
+		{"0 0 13 * 5", "2026-10-10T00:00:00Z", "2026-10-13T00:00:00Z"},
+		{"0 12 29 feb *", "2026-10-18T00:00:00Z", "2028-02-29T12:00:00Z"},
+		{"5,10-12 1 * * *", "2026-10-18T01:10:00Z", "2026-10-18T01:11:00Z"},
+		{"@hourly", "2026-10-18T23:59:00Z", "2026-10-19T00:00:00Z"},
+		{"@yearly", "2026-10-18T23:59:00Z", "2027-01-01T00:00:00Z"},
+	}
+
+	for _, test := range tests {
+		s, err := ParseSchedule(test.spec, "")
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.spec, err)
+			continue
+		}
+		from, _ := time.Parse(time.RFC3339, test.from)
+		next := s.Next(from).Format(time.RFC3339)
+		if next != test.next {
+			t.Errorf("%s from %s: expected %s, received %s", test.spec, test.from, test.next, next)
+		}
+	}
+}
+
+func TestScheduleTimezone(t *testing.T) {
+	s, err := ParseSchedule("0 9 * * *", "America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	from, _ := time.Parse(time.RFC3339, "2026-10-18T12:00:00Z")
+	next := s.Next(from).UTC().Format(time.RFC3339)
+	if next != "2026-10-18T13:00:00Z" {
+		t.Errorf("expected 2026-10-18T13:00:00Z, received %s", next)
+	}
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "0 0 * 13 *",
+		"5-1 * * * *", "*/0 * * * *", "0 0 31 feb *", "0 0 * * fri-mon"} {
+		if _, err := ParseSchedule(spec, ""); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+	if _, err := ParseSchedule("* * * * *", "Nowhere/Special"); err == nil {
+		t.Errorf("expected an error for an unknown time zone")
+	}
+}
@@ -0,0 +1,438 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+/*
+Package jobs stores and runs the statements users schedule with CREATE
+JOB. Each job is kept as a document in the query metadata collection,
+and each node caches them until a change is announced through a metakv
+revision counter.
+
+Jobs are run by a single query node, the leader, which claims each run
+by recording it in the job's document with the CAS it was read with, so
+that a run is never repeated as the leadership moves. Runs are scheduler
+tasks, and their outcome is found in system:tasks_cache.
+*/
+package jobs
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/couchbase/cbauth/metakv"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/distributed"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
+)
+
+const _JOB = "job::"
+const _MAX_RETRIES = 5
+const _CACHE_REVISION_PATH = "/query/jobs_cache/"
+const _CACHE_REVISION = _CACHE_REVISION_PATH + "revision"
+
+// The timeout of jobs that do not set one
+const DEFAULT_TIMEOUT = time.Hour
+
+// A stored job definition
+type Job struct {
+	Name         string
+	Schedule     string
+	Timezone     string
+	Timeout      time.Duration
+	Enabled      bool
+	Text         string
+	QueryContext string
+	Creator      string
+	Created      time.Time
+	LastRun      time.Time
+	LastNode     string
+
+	schedule *Schedule
+}
+
+func NewJob(name string, schedule string, options value.Value) (*Job, errors.Error) {
+	rv := &Job{Name: name, Schedule: schedule, Timeout: DEFAULT_TIMEOUT, Enabled: true}
+	err := rv.SetOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	return rv, rv.SetSchedule(schedule)
+}
+
+/*
+Applies the WITH options of CREATE or ALTER JOB: timeout, a duration
+string, timezone, an IANA time zone name for the schedule, and enabled.
+*/
+func (this *Job) SetOptions(options value.Value) errors.Error {
+	if options == nil {
+		return nil
+	}
+	if options.Type() != value.OBJECT {
+		return errors.NewJobError(errors.E_JOB_INVALID_OPTION, options.String(), this.Name)
+	}
+
+	tz := this.Timezone
+	for k, v := range options.Fields() {
+		val := value.NewValue(v)
+		switch strings.ToLower(k) {
+		case "timeout":
+			var d time.Duration
+			var err error
+			if val.Type() == value.STRING {
+				d, err = time.ParseDuration(val.ToString())
+			}
+			if val.Type() != value.STRING || err != nil || d <= 0 {
+				return errors.NewJobError(errors.E_JOB_INVALID_OPTION, k, this.Name)
+			}
+			this.Timeout = d
+		case "timezone":
+			if val.Type() != value.STRING {
+				return errors.NewJobError(errors.E_JOB_INVALID_OPTION, k, this.Name)
+			}
+			tz = val.ToString()
+		case "enabled":
+			if val.Type() != value.BOOLEAN {
+				return errors.NewJobError(errors.E_JOB_INVALID_OPTION, k, this.Name)
+			}
+			this.Enabled = val.Truth()
+		default:
+			return errors.NewJobError(errors.E_JOB_INVALID_OPTION, k, this.Name)
+		}
+	}
+
+	if tz != this.Timezone {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return errors.NewJobError(errors.E_JOB_INVALID_OPTION, "timezone", this.Name, err)
+		}
+		this.Timezone = tz
+		if this.Schedule != "" {
+			return this.SetSchedule(this.Schedule)
+		}
+	}
+	return nil
+}
+
+func (this *Job) SetSchedule(schedule string) errors.Error {
+	s, err := ParseSchedule(schedule, this.Timezone)
+	if err != nil {
+		return errors.NewJobError(errors.E_JOB_INVALID_SCHEDULE, schedule, this.Name, err)
+	}
+	this.Schedule = schedule
+	this.schedule = s
+	return nil
+}
+
+// When the job is next due, from its last run or its creation; the zero time if never
+func (this *Job) Next() time.Time {
+	if this.schedule == nil {
+		return time.Time{}
+	}
+	from := this.LastRun
+	if from.IsZero() {
+		from = this.Created
+	}
+	return this.schedule.Next(from)
+}
+
+func (this *Job) encode() value.Value {
+	m := map[string]interface{}{
+		"name":      this.Name,
+		"schedule":  this.Schedule,
+		"timeout":   this.Timeout.String(),
+		"enabled":   this.Enabled,
+		"statement": this.Text,
+		"creator":   this.Creator,
+		"created":   this.Created.Format(util.DEFAULT_FORMAT),
+	}
+	if this.Timezone != "" {
+		m["timezone"] = this.Timezone
+	}
+	if this.QueryContext != "" {
+		m["query_context"] = this.QueryContext
+	}
+	if !this.LastRun.IsZero() {
+		m["last_run"] = this.LastRun.Format(util.DEFAULT_FORMAT)
+		m["last_node"] = this.LastNode
+	}
+	return value.NewValue(m)
+}
+
+func decodeJob(v value.Value) *Job {
+	if v == nil || v.Type() != value.OBJECT {
+		return nil
+	}
+	rv := &Job{Timeout: DEFAULT_TIMEOUT}
+	for f, s := range map[string]*string{"name": &rv.Name, "schedule": &rv.Schedule, "timezone": &rv.Timezone,
+		"statement": &rv.Text, "query_context": &rv.QueryContext, "creator": &rv.Creator, "last_node": &rv.LastNode} {
+		if fv, ok := v.Field(f); ok && fv.Type() == value.STRING {
+			*s = fv.ToString()
+		}
+	}
+	for f, t := range map[string]*time.Time{"created": &rv.Created, "last_run": &rv.LastRun} {
+		if fv, ok := v.Field(f); ok && fv.Type() == value.STRING {
+			*t, _ = time.Parse(util.DEFAULT_FORMAT, fv.ToString())
+		}
+	}
+	if fv, ok := v.Field("timeout"); ok && fv.Type() == value.STRING {
+		if d, err := time.ParseDuration(fv.ToString()); err == nil {
+			rv.Timeout = d
+		}
+	}
+	if fv, ok := v.Field("enabled"); ok && fv.Type() == value.BOOLEAN {
+		rv.Enabled = fv.Truth()
+	}
+
+	// a job whose schedule no longer parses, as when its time zone has gone, never runs
+	if s, err := ParseSchedule(rv.Schedule, rv.Timezone); err == nil {
+		rv.schedule = s
+	} else {
+		logging.Warnf("Job %v has an invalid schedule: %v", rv.Name, err)
+	}
+	return rv
+}
+
+var cache struct {
+	sync.RWMutex
+	jobs []*Job
+	rev  int32
+}
+
+var cacheRevision int32 = 1
+
+// Starts monitoring the jobs of the cluster; to be called once at server startup
+func Init() {
+	err := metakv.Add(_CACHE_REVISION, fmtCacheRevision())
+	if err != nil && err != metakv.ErrRevMismatch {
+		logging.Warnf("Unable to start jobs cache monitor: %v", err)
+	}
+	go metakv.RunObserveChildren(_CACHE_REVISION_PATH, jobChangeMonitor, make(chan struct{}))
+}
+
+func jobChangeMonitor(kve metakv.KVEntry) error {
+	if kve.Path != _CACHE_REVISION {
+		return nil
+	}
+	node, _ := distributed.RemoteAccess().SplitKey(string(kve.Value))
+	if node == "" || node != distributed.RemoteAccess().WhoAmI() {
+		atomic.AddInt32(&cacheRevision, 1)
+	}
+	return nil
+}
+
+func nextRevision() {
+	atomic.AddInt32(&cacheRevision, 1)
+	err := metakv.Set(_CACHE_REVISION, fmtCacheRevision(), nil)
+	if err != nil && err.Error() == "Not found" {
+		err = metakv.Add(_CACHE_REVISION, fmtCacheRevision())
+	}
+	if err != nil {
+		logging.Infof("Unable to update jobs cache monitor %v", errors.NewMetaKVChangeCounterError(err))
+	}
+}
+
+func fmtCacheRevision() []byte {
+	return []byte(distributed.RemoteAccess().MakeKey(distributed.RemoteAccess().WhoAmI(),
+		strconv.Itoa(int(atomic.LoadInt32(&cacheRevision)))))
+}
+
+// Fetches the document of a job, or nil if there is none
+func fetchJob(sys datastore.Keyspace, key string) (value.AnnotatedValue, errors.Error) {
+	res := make(map[string]value.AnnotatedValue, 1)
+	errs := sys.Fetch([]string{key}, res, datastore.NULL_QUERY_CONTEXT, nil, nil, false)
+	if len(errs) > 0 {
+		if !errors.IsNotFoundError("", errs[0]) && !errs[0].HasCause(errors.E_CB_BULK_GET) {
+			return nil, errs[0]
+		}
+		return nil, nil
+	}
+	return res[key], nil
+}
+
+func CreateJob(job *Job, replace bool, requestId string) errors.Error {
+	sys, err := getQueryMetadata(true, requestId)
+	if err == nil && sys == nil {
+		err = errors.NewMissingQueryMetadataError("CREATE JOB")
+	}
+	if err != nil {
+		return errors.NewJobError(errors.E_JOB_CREATE, job.Name, err)
+	}
+
+	pairs := []value.Pair{{Name: _JOB + job.Name, Value: value.NewAnnotatedValue(job.encode())}}
+	var errs errors.Errors
+	if replace {
+		_, _, errs = sys.Upsert(pairs, datastore.GetDurableQueryContextFor(sys), true)
+	} else {
+		_, _, errs = sys.Insert(pairs, datastore.GetDurableQueryContextFor(sys), true)
+	}
+	if len(errs) > 0 {
+		if errs[0].HasCause(errors.E_DUPLICATE_KEY) {
+			return errors.NewJobError(errors.E_JOB_ALREADY_EXISTS, job.Name)
+		}
+		return errors.NewJobError(errors.E_JOB_CREATE, job.Name, errs[0])
+	}
+	nextRevision()
+	return nil
+}
+
+/*
+Applies a change to a job. The document is updated with the CAS it was
+read with, and the change retried if another node got there first; a
+change returning false leaves the job as it is.
+*/
+func modifyJob(name string, change func(job *Job) (bool, errors.Error)) errors.Error {
+	sys, err := getQueryMetadata(false, "")
+	if err != nil {
+		return err
+	} else if sys == nil {
+		return errors.NewJobError(errors.E_JOB_NOT_FOUND, name)
+	}
+
+	key := _JOB + name
+	for i := 0; ; i++ {
+		av, err := fetchJob(sys, key)
+		if err != nil {
+			return err
+		}
+		job := decodeJob(av)
+		if job == nil {
+			return errors.NewJobError(errors.E_JOB_NOT_FOUND, name)
+		}
+		ok, err := change(job)
+		if err != nil || !ok {
+			return err
+		}
+
+		nv := value.NewAnnotatedValue(job.encode())
+		nv.CopyAnnotations(av)
+		pairs := []value.Pair{{Name: key, Value: nv}}
+		_, _, errs := sys.Update(pairs, datastore.GetDurableQueryContextFor(sys), true)
+		if len(errs) == 0 {
+			break
+		}
+		if i < _MAX_RETRIES && errs[0].HasCause(errors.E_CAS_MISMATCH) {
+			continue
+		}
+		return errs[0]
+	}
+	nextRevision()
+	return nil
+}
+
+func AlterJob(name string, change func(job *Job) errors.Error) errors.Error {
+	err := modifyJob(name, func(job *Job) (bool, errors.Error) {
+		return true, change(job)
+	})
+	if err != nil {
+		switch err.Code() {
+		case errors.E_JOB_NOT_FOUND, errors.E_JOB_INVALID_SCHEDULE, errors.E_JOB_INVALID_OPTION:
+		default:
+			return errors.NewJobError(errors.E_JOB_ALTER, name, err)
+		}
+	}
+	return err
+}
+
+func DropJob(name string, failIfNotExists bool) errors.Error {
+	sys, err := getQueryMetadata(false, "")
+	if err != nil {
+		return errors.NewJobError(errors.E_JOB_DROP, name, err)
+	}
+	if sys != nil {
+		pairs := []value.Pair{{Name: _JOB + name}}
+		_, _, errs := sys.Delete(pairs, datastore.GetDurableQueryContextFor(sys), false)
+		if len(errs) == 0 {
+			nextRevision()
+			return nil
+		}
+		if !errors.IsNotFoundError("", errs[0]) {
+			return errors.NewJobError(errors.E_JOB_DROP, name, errs[0])
+		}
+	}
+	if failIfNotExists {
+		return errors.NewJobError(errors.E_JOB_NOT_FOUND, name)
+	}
+	return nil
+}
+
+// All the jobs, in order of name
+func getJobs() ([]*Job, errors.Error) {
+	rev := atomic.LoadInt32(&cacheRevision)
+
+	cache.RLock()
+	list, cached := cache.jobs, cache.rev == rev
+	cache.RUnlock()
+	if cached {
+		return list, nil
+	}
+
+	list = nil
+	err := scanJobKeys(func(key string, sys datastore.Keyspace) errors.Error {
+		av, err := fetchJob(sys, key)
+		if err != nil {
+			return err
+		}
+		if job := decodeJob(av); job != nil {
+			list = append(list, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	cache.Lock()
+	cache.jobs = list
+	cache.rev = rev
+	cache.Unlock()
+	return list, nil
+}
+
+func CountJobs() (int64, errors.Error) {
+	list, err := getJobs()
+	return int64(len(list)), err
+}
+
+func ListJobNames() ([]string, errors.Error) {
+	list, err := getJobs()
+	if err != nil {
+		return nil, err
+	}
+	rv := make([]string, len(list))
+	for i, job := range list {
+		rv[i] = job.Name
+	}
+	return rv, nil
+}
+
+// Returns the system:jobs entry for the job
+func FetchJob(name string) (value.AnnotatedValue, errors.Error) {
+	sys, err := getQueryMetadata(false, "")
+	if err != nil || sys == nil {
+		return nil, err
+	}
+	av, err := fetchJob(sys, _JOB+name)
+	if err != nil {
+		return nil, err
+	}
+	job := decodeJob(av)
+	if job == nil {
+		return nil, nil
+	}
+	rv := job.encode()
+	if next := job.Next(); job.Enabled && !next.IsZero() {
+		rv.SetField("next_run", next.Format(util.DEFAULT_FORMAT))
+	}
+	return value.NewAnnotatedValue(rv), nil
+}
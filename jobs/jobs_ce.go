@@ -0,0 +1,24 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+//
+//go:build !enterprise
+
+package jobs
+
+import (
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+)
+
+func getQueryMetadata(create bool, requestId string) (datastore.Keyspace, errors.Error) {
+	return nil, errors.NewEnterpriseFeature("Jobs", "jobs.get_query_metadata")
+}
+
+func scanJobKeys(handler func(string, datastore.Keyspace) errors.Error) errors.Error {
+	return nil
+}
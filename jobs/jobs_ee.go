@@ -0,0 +1,40 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+//
+//go:build enterprise
+
+package jobs
+
+import (
+	"github.com/couchbase/query-ee/dictionary"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+)
+
+// The query metadata collection, created on demand; nil if it does not exist
+func getQueryMetadata(create bool, requestId string) (datastore.Keyspace, errors.Error) {
+	has, err := dictionary.HasQueryMetadata(create, requestId, "CREATE JOB", true)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	store := datastore.GetDatastore()
+	if store == nil {
+		return nil, errors.NewNoDatastoreError()
+	}
+	return store.GetQueryMetadata()
+}
+
+func scanJobKeys(handler func(string, datastore.Keyspace) errors.Error) errors.Error {
+	has, err := dictionary.HasQueryMetadata(false, "", "", false)
+	if err != nil || !has {
+		return err
+	}
+	return datastore.ScanSystemCollection(dictionary.QUERY_METADATA_BUCKET, _JOB, nil, handler, nil)
+}
@@ -0,0 +1,95 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/value"
+)
+
+func TestJobOptions(t *testing.T) {
+	job, err := NewJob("purge", "0 3 * * *", nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if job.Timeout != DEFAULT_TIMEOUT || !job.Enabled || job.Timezone != "" {
+		t.Errorf("expected the default options, received %v %v %q", job.Timeout, job.Enabled, job.Timezone)
+	}
+
+	err = job.SetOptions(value.NewValue(map[string]interface{}{"timeout": "90s", "Enabled": false, "timezone": "UTC"}))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if job.Timeout != 90*time.Second || job.Enabled || job.Timezone != "UTC" {
+		t.Errorf("expected the options to be set, received %v %v %q", job.Timeout, job.Enabled, job.Timezone)
+	}
+
+	for _, options := range []interface{}{
+		[]interface{}{"timeout"},
+		map[string]interface{}{"timeout": 90},
+		map[string]interface{}{"timeout": "0s"},
+		map[string]interface{}{"enabled": "false"},
+		map[string]interface{}{"timezone": "Nowhere/Special"},
+		map[string]interface{}{"retries": 3},
+	} {
+		err = job.SetOptions(value.NewValue(options))
+		if err == nil || err.Code() != errors.E_JOB_INVALID_OPTION {
+			t.Errorf("%v: expected an invalid option, received %v", options, err)
+		}
+	}
+
+	if _, err = NewJob("purge", "0 3 * *", nil); err == nil || err.Code() != errors.E_JOB_INVALID_SCHEDULE {
+		t.Errorf("expected an invalid schedule, received %v", err)
+	}
+}
+
+func TestJobEncode(t *testing.T) {
+	job, err := NewJob("purge", "@daily", value.NewValue(map[string]interface{}{"timeout": "10m"}))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	job.Text = "DELETE FROM logs WHERE age > 30"
+	job.QueryContext = "default:b.s"
+	job.Creator = "local:admin"
+	job.Created, _ = time.Parse(time.RFC3339, "2026-10-18T10:15:00Z")
+
+	decoded := decodeJob(job.encode())
+	if decoded == nil || decoded.Name != job.Name || decoded.Schedule != job.Schedule ||
+		decoded.Timeout != job.Timeout || decoded.Enabled != job.Enabled || decoded.Text != job.Text ||
+		decoded.QueryContext != job.QueryContext || decoded.Creator != job.Creator ||
+		!decoded.Created.Equal(job.Created) || !decoded.LastRun.IsZero() {
+		t.Fatalf("expected %+v, received %+v", job, decoded)
+	}
+
+	// a job that has never run is next due from its creation, then from its last run
+	if next := decoded.Next().UTC().Format(time.RFC3339); next != "2026-10-19T00:00:00Z" {
+		t.Errorf("expected the first run on 2026-10-19T00:00:00Z, received %s", next)
+	}
+	decoded.LastRun, _ = time.Parse(time.RFC3339, "2026-10-19T00:00:00Z")
+	decoded.LastNode = "node1"
+	decoded = decodeJob(decoded.encode())
+	if decoded.LastNode != "node1" {
+		t.Errorf("expected the last node to be kept, received %q", decoded.LastNode)
+	}
+	if next := decoded.Next().UTC().Format(time.RFC3339); next != "2026-10-20T00:00:00Z" {
+		t.Errorf("expected the next run on 2026-10-20T00:00:00Z, received %s", next)
+	}
+
+	// a job whose schedule does not parse is kept but never runs
+	decoded = decodeJob(value.NewValue(map[string]interface{}{"name": "broken", "schedule": "0 3 * *"}))
+	if decoded == nil || !decoded.Next().IsZero() || decoded.Timeout != DEFAULT_TIMEOUT {
+		t.Errorf("expected a job that never runs, received %+v", decoded)
+	}
+	if decodeJob(value.NewValue("purge")) != nil {
+		t.Errorf("expected a document that is not an object not to decode")
+	}
+}
@@ -0,0 +1,211 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package jobs
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/couchbase/query/distributed"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/functions"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/scheduler"
+	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
+)
+
+const _TASK_CLASS = "job"
+
+// Jobs are looked at just after each minute starts
+const _TICK_DELAY = time.Second
+
+// The context a job runs in, with the credentials of its creator
+type Context interface {
+	scheduler.Context
+	OpenStatement(statement string, namedArgs map[string]value.Value, positionalArgs value.Values,
+		subquery, readonly bool, profileUdfExecTrees bool, funcKey string) (functions.Handle, error)
+}
+
+type ContextFunc func(job *Job) (Context, errors.Error)
+
+var runner struct {
+	sync.Mutex
+	newContext ContextFunc
+	running    map[string]bool
+}
+
+func InitJobs(newContext ContextFunc) {
+	runner.newContext = newContext
+	runner.running = make(map[string]bool)
+	go loop()
+}
+
+func loop() {
+	defer func() {
+		r := recover()
+		if r != nil {
+			logging.Stackf(logging.ERROR, "Jobs runner panic: %v", r)
+			go loop()
+		}
+	}()
+
+	for {
+		now := time.Now()
+		time.Sleep(now.Truncate(time.Minute).Add(time.Minute + _TICK_DELAY).Sub(now))
+		if IsLeader() {
+			runDue(time.Now())
+		}
+	}
+}
+
+// The leader is the query node first in order of name, or this node if it stands alone
+func IsLeader() bool {
+	ra := distributed.RemoteAccess()
+	if ra.StandAlone() {
+		return true
+	}
+	self := ra.WhoAmI()
+	nodes := ra.GetNodeNames()
+	if self == "" || len(nodes) == 0 {
+		return false
+	}
+	leader := nodes[0]
+	for _, n := range nodes[1:] {
+		if n < leader {
+			leader = n
+		}
+	}
+	return leader == self
+}
+
+func runDue(now time.Time) {
+	list, err := getJobs()
+	if err != nil {
+		logging.Errorf("Jobs: unable to load job definitions: %v", err)
+		return
+	}
+	for _, job := range list {
+		if !job.Enabled {
+			continue
+		}
+		due := job.Next()
+		if due.IsZero() || due.After(now) {
+			continue
+		}
+
+		// a job still running skips its turn
+		if !setRunning(job.Name, true) {
+			continue
+		}
+		err = startJob(job, due, now)
+		if err != nil {
+			setRunning(job.Name, false)
+			logging.Errorf("Jobs: unable to start job %v: %v", job.Name, err)
+		}
+	}
+}
+
+func setRunning(name string, running bool) bool {
+	runner.Lock()
+	defer runner.Unlock()
+	if running && runner.running[name] {
+		return false
+	}
+	if running {
+		runner.running[name] = true
+	} else {
+		delete(runner.running, name)
+	}
+	return true
+}
+
+type jobRun struct {
+	job *Job
+	due time.Time
+}
+
+/*
+Claims the run in the job's document and schedules it. A run claimed by
+another node, or the job changed since it was cached, is left alone.
+*/
+func startJob(job *Job, due time.Time, now time.Time) errors.Error {
+	claimed := false
+	err := modifyJob(job.Name, func(current *Job) (bool, errors.Error) {
+		if !current.LastRun.Equal(job.LastRun) || current.Text != job.Text || !current.Enabled {
+			return false, nil
+		}
+		current.LastRun = now
+		current.LastNode = distributed.RemoteAccess().WhoAmI()
+		claimed = true
+		return true, nil
+	})
+	if err != nil || !claimed {
+		if err == nil {
+			setRunning(job.Name, false)
+		}
+		return err
+	}
+
+	context, err := runner.newContext(job)
+	if err != nil {
+		return err
+	}
+	name, e := util.UUIDV4()
+	if e != nil {
+		return errors.NewJobError(errors.E_JOB_EXECUTION, job.Name, e)
+	}
+	return scheduler.ScheduleStoppableTask(name, _TASK_CLASS, job.Name, 0, execJob, nil,
+		&jobRun{job: job, due: due}, job.Text, context)
+}
+
+func execJob(context scheduler.Context, parms interface{}, stop <-chan bool) (interface{}, []errors.Error) {
+	run := parms.(*jobRun)
+	job := run.job
+	defer setRunning(job.Name, false)
+
+	rv := map[string]interface{}{
+		"job":       job.Name,
+		"scheduled": run.due.Format(util.DEFAULT_FORMAT),
+	}
+	ctx, ok := context.(Context)
+	if !ok {
+		return rv, []errors.Error{errors.NewJobError(errors.E_JOB_EXECUTION, job.Name)}
+	}
+	handle, err := ctx.OpenStatement(job.Text, nil, nil, false, false, false, "")
+	if err != nil {
+		return rv, []errors.Error{errors.NewJobError(errors.E_JOB_EXECUTION, job.Name, err)}
+	}
+
+	var timedOut int32
+	done := make(chan bool)
+	timer := time.NewTimer(job.Timeout)
+	go func() {
+		select {
+		case <-timer.C:
+			atomic.StoreInt32(&timedOut, 1)
+			handle.Cancel()
+		case <-stop:
+			handle.Cancel()
+		case <-done:
+		}
+	}()
+	mutations, err := handle.Complete()
+	timer.Stop()
+	close(done)
+
+	rv["mutations"] = mutations
+	if atomic.LoadInt32(&timedOut) != 0 {
+		return rv, []errors.Error{errors.NewJobError(errors.E_JOB_TIMEOUT, job.Name, job.Timeout.String())}
+	} else if err != nil {
+		return rv, []errors.Error{errors.NewJobError(errors.E_JOB_EXECUTION, job.Name, err)}
+	}
+	return rv, nil
+}
@@ -10,6 +10,7 @@ package logging
 
 import (
 	fmtpkg "fmt"
+	"hash/fnv"
 	"log"
 	"path"
 	"regexp"
@@ -48,25 +49,27 @@ func (level Level) FunctionName() string {
 }
 
 var _LEVEL_NAMES = []string{
-	DEBUG:  "DEBUG",
-	TRACE:  "TRACE",
-	INFO:   "INFO",
-	WARN:   "WARN",
-	ERROR:  "ERROR",
-	SEVERE: "SEVERE",
-	FATAL:  "FATAL",
-	NONE:   "NONE",
+	DEBUG:   "DEBUG",
+	TRACE:   "TRACE",
+	INFO:    "INFO",
+	REQUEST: "REQUEST",
+	WARN:    "WARN",
+	ERROR:   "ERROR",
+	SEVERE:  "SEVERE",
+	FATAL:   "FATAL",
+	NONE:    "NONE",
 }
 
 var _ABBREVIATED_LEVEL_NAMES = []string{
-	DEBUG:  " D ",
-	TRACE:  " T ",
-	INFO:   " I ",
-	WARN:   " W ",
-	ERROR:  " E ",
-	SEVERE: " S ",
-	FATAL:  " F ",
-	NONE:   " N ",
+	DEBUG:   " D ",
+	TRACE:   " T ",
+	INFO:    " I ",
+	REQUEST: " R ",
+	WARN:    " W ",
+	ERROR:   " E ",
+	SEVERE:  " S ",
+	FATAL:   " F ",
+	NONE:    " N ",
 }
 
 var _LEVEL_MAP = map[string]Level{
@@ -168,6 +171,32 @@ type RequestLogger interface {
 	Close()
 }
 
+// Request correlation fields, made available by the request Log passed as the last argument of the logging functions
+type RequestInfo interface {
+	RequestId() string
+	ClientContextId() string
+	Users() string
+	StatementHash() string
+}
+
+// Loggers that add request correlation fields to their records
+type CorrelatingLogger interface {
+	Logger
+	Correlate(RequestInfo) Log // returns a Log that writes records for the request
+}
+
+// Loggers that can change their record format at run time
+type FormattingLogger interface {
+	Logger
+	SetFormat(string) bool // returns false if the format is not supported
+	Format() string
+}
+
+const (
+	TEXT_FORMAT = "text"
+	JSON_FORMAT = "json"
+)
+
 var logger Logger = nil
 var curLevel Level = DEBUG // initially set to never skip
 
@@ -261,9 +290,24 @@ func SetLogger(newLogger Logger) {
 	loggerMutex.Unlock()
 }
 
+// the Log that writes a record, which is request specific when both the logger and the request support it
+// must be called with the logger mutex held
+func forRequest(rl Log) Log {
+	if rl != nil {
+		if cl, ok := logger.(CorrelatingLogger); ok {
+			if ri, ok := rl.(RequestInfo); ok {
+				return cl.Correlate(ri)
+			}
+		}
+	}
+	return logger
+}
+
 func Loga(level Level, f func() string, args ...interface{}) {
+	var rl Log
 	if len(args) > 0 {
 		if l, ok := args[0].(Log); ok {
+			rl = l
 			l.Loga(level, f)
 		}
 	}
@@ -273,13 +317,15 @@ func Loga(level Level, f func() string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Loga(level, f)
+	forRequest(rl).Loga(level, f)
 	loggerMutex.Unlock()
 }
 
 func Debuga(f func() string, args ...interface{}) {
+	var rl Log
 	if len(args) > 0 {
 		if l, ok := args[0].(Log); ok {
+			rl = l
 			l.Debuga(f)
 		}
 	}
@@ -289,16 +335,18 @@ func Debuga(f func() string, args ...interface{}) {
 	fl := getFileLine(1)
 	loggerMutex.Lock()
 	if fl != "" {
-		logger.Debuga(func() string { return f() + fl })
+		forRequest(rl).Debuga(func() string { return f() + fl })
 	} else {
-		logger.Debuga(f)
+		forRequest(rl).Debuga(f)
 	}
 	loggerMutex.Unlock()
 }
 
 func Tracea(f func() string, args ...interface{}) {
+	var rl Log
 	if len(args) > 0 {
 		if l, ok := args[0].(Log); ok {
+			rl = l
 			l.Tracea(f)
 		}
 	}
@@ -308,16 +356,18 @@ func Tracea(f func() string, args ...interface{}) {
 	fl := getFileLine(1)
 	loggerMutex.Lock()
 	if fl != "" {
-		logger.Tracea(func() string { return f() + fl })
+		forRequest(rl).Tracea(func() string { return f() + fl })
 	} else {
-		logger.Tracea(f)
+		forRequest(rl).Tracea(f)
 	}
 	loggerMutex.Unlock()
 }
 
 func Infoa(f func() string, args ...interface{}) {
+	var rl Log
 	if len(args) > 0 {
 		if l, ok := args[0].(Log); ok {
+			rl = l
 			l.Infoa(f)
 		}
 	}
@@ -325,13 +375,15 @@ func Infoa(f func() string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Infoa(f)
+	forRequest(rl).Infoa(f)
 	loggerMutex.Unlock()
 }
 
 func Warna(f func() string, args ...interface{}) {
+	var rl Log
 	if len(args) > 0 {
 		if l, ok := args[0].(Log); ok {
+			rl = l
 			l.Warna(f)
 		}
 	}
@@ -339,13 +391,15 @@ func Warna(f func() string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Warna(f)
+	forRequest(rl).Warna(f)
 	loggerMutex.Unlock()
 }
 
 func Errora(f func() string, args ...interface{}) {
+	var rl Log
 	if len(args) > 0 {
 		if l, ok := args[0].(Log); ok {
+			rl = l
 			l.Errora(f)
 		}
 	}
@@ -353,13 +407,15 @@ func Errora(f func() string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Errora(f)
+	forRequest(rl).Errora(f)
 	loggerMutex.Unlock()
 }
 
 func Severea(f func() string, args ...interface{}) {
+	var rl Log
 	if len(args) > 0 {
 		if l, ok := args[0].(Log); ok {
+			rl = l
 			l.Severea(f)
 		}
 	}
@@ -367,13 +423,15 @@ func Severea(f func() string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Severea(f)
+	forRequest(rl).Severea(f)
 	loggerMutex.Unlock()
 }
 
 func Fatala(f func() string, args ...interface{}) {
+	var rl Log
 	if len(args) > 0 {
 		if l, ok := args[0].(Log); ok {
+			rl = l
 			l.Fatala(f)
 		}
 	}
@@ -381,7 +439,7 @@ func Fatala(f func() string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Fatala(f)
+	forRequest(rl).Fatala(f)
 	loggerMutex.Unlock()
 }
 
@@ -389,9 +447,11 @@ func Fatala(f func() string, args ...interface{}) {
 
 func Logf(level Level, fmt string, args ...interface{}) {
 	n := len(args)
+	var rl Log
 	if n > 0 {
 		if l, ok := args[n-1].(Log); ok {
 			n--
+			rl = l
 			l.Logf(level, fmt, args[:n]...)
 		}
 	}
@@ -401,15 +461,17 @@ func Logf(level Level, fmt string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Logf(level, fmt, args[:n]...)
+	forRequest(rl).Logf(level, fmt, args[:n]...)
 	loggerMutex.Unlock()
 }
 
 func Debugf(fmt string, args ...interface{}) {
 	n := len(args)
+	var rl Log
 	if n > 0 {
 		if l, ok := args[n-1].(Log); ok {
 			n--
+			rl = l
 			l.Debugf(fmt, args[:n]...)
 		}
 	}
@@ -418,15 +480,17 @@ func Debugf(fmt string, args ...interface{}) {
 	}
 	fmt += getFileLine(1)
 	loggerMutex.Lock()
-	logger.Debugf(fmt, args[:n]...)
+	forRequest(rl).Debugf(fmt, args[:n]...)
 	loggerMutex.Unlock()
 }
 
 func Tracef(fmt string, args ...interface{}) {
 	n := len(args)
+	var rl Log
 	if n > 0 {
 		if l, ok := args[n-1].(Log); ok {
 			n--
+			rl = l
 			l.Tracef(fmt, args[:n]...)
 		}
 	}
@@ -435,15 +499,17 @@ func Tracef(fmt string, args ...interface{}) {
 	}
 	fmt += getFileLine(1)
 	loggerMutex.Lock()
-	logger.Tracef(fmt, args[:n]...)
+	forRequest(rl).Tracef(fmt, args[:n]...)
 	loggerMutex.Unlock()
 }
 
 func Infof(fmt string, args ...interface{}) {
 	n := len(args)
+	var rl Log
 	if n > 0 {
 		if l, ok := args[n-1].(Log); ok {
 			n--
+			rl = l
 			l.Infof(fmt, args[:n]...)
 		}
 	}
@@ -451,15 +517,17 @@ func Infof(fmt string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Infof(fmt, args[:n]...)
+	forRequest(rl).Infof(fmt, args[:n]...)
 	loggerMutex.Unlock()
 }
 
 func Warnf(fmt string, args ...interface{}) {
 	n := len(args)
+	var rl Log
 	if n > 0 {
 		if l, ok := args[n-1].(Log); ok {
 			n--
+			rl = l
 			l.Warnf(fmt, args[:n]...)
 		}
 	}
@@ -467,15 +535,17 @@ func Warnf(fmt string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Warnf(fmt, args[:n]...)
+	forRequest(rl).Warnf(fmt, args[:n]...)
 	loggerMutex.Unlock()
 }
 
 func Errorf(fmt string, args ...interface{}) {
 	n := len(args)
+	var rl Log
 	if n > 0 {
 		if l, ok := args[n-1].(Log); ok {
 			n--
+			rl = l
 			l.Errorf(fmt, args[:n]...)
 		}
 	}
@@ -483,15 +553,17 @@ func Errorf(fmt string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Errorf(fmt, args[:n]...)
+	forRequest(rl).Errorf(fmt, args[:n]...)
 	loggerMutex.Unlock()
 }
 
 func Severef(fmt string, args ...interface{}) {
 	n := len(args)
+	var rl Log
 	if n > 0 {
 		if l, ok := args[n-1].(Log); ok {
 			n--
+			rl = l
 			l.Severef(fmt, args[:n]...)
 		}
 	}
@@ -499,15 +571,17 @@ func Severef(fmt string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Severef(fmt, args[:n]...)
+	forRequest(rl).Severef(fmt, args[:n]...)
 	loggerMutex.Unlock()
 }
 
 func Fatalf(fmt string, args ...interface{}) {
 	n := len(args)
+	var rl Log
 	if n > 0 {
 		if l, ok := args[n-1].(Log); ok {
 			n--
+			rl = l
 			l.Fatalf(fmt, args[:n]...)
 		}
 	}
@@ -515,7 +589,7 @@ func Fatalf(fmt string, args ...interface{}) {
 		return
 	}
 	loggerMutex.Lock()
-	logger.Fatalf(fmt, args[:n]...)
+	forRequest(rl).Fatalf(fmt, args[:n]...)
 	loggerMutex.Unlock()
 }
 
@@ -537,6 +611,24 @@ func LogLevel() Level {
 	return rv
 }
 
+func SetLogFormat(format string) bool {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	if fl, ok := logger.(FormattingLogger); ok {
+		return fl.SetFormat(format)
+	}
+	return format == TEXT_FORMAT
+}
+
+func LogFormat() string {
+	loggerMutex.RLock()
+	defer loggerMutex.RUnlock()
+	if fl, ok := logger.(FormattingLogger); ok {
+		return fl.Format()
+	}
+	return TEXT_FORMAT
+}
+
 func Logging(l Level) bool {
 	return !skipLogging(l)
 }
@@ -752,6 +844,48 @@ func findCaller(l Level) string {
 	return ""
 }
 
+const _QUERY_PACKAGE = "github.com/couchbase/query/"
+
+// The package of the first caller outside the logging packages, relative to the query module, e.g. "planner"
+func CallerComponent() string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(2, pc)
+	if n == 0 {
+		return ""
+	}
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		pkg := frame.Function
+		if i := strings.LastIndexByte(pkg, '/'); i != -1 {
+			if j := strings.IndexByte(pkg[i:], '.'); j != -1 {
+				pkg = pkg[:i+j]
+			}
+		} else if j := strings.IndexByte(pkg, '.'); j != -1 {
+			pkg = pkg[:j]
+		}
+		pkg = strings.TrimPrefix(pkg, _QUERY_PACKAGE)
+		// the standard library log package is redirected to the logger
+		if pkg != "" && pkg != "logging" && pkg != "log" && !strings.HasPrefix(pkg, "logging/") {
+			return pkg
+		}
+		if !more {
+			break
+		}
+	}
+	return ""
+}
+
+// A short, stable identifier of a statement text, for correlating the log records of requests running the same statement
+func StatementHash(stmt string) string {
+	if stmt == "" {
+		return ""
+	}
+	h := fnv.New64a()
+	h.Write([]byte(stmt))
+	return fmtpkg.Sprintf("%016x", h.Sum64())
+}
+
 var NULL_LOG Logger = &nullLogImpl{}
 
 type nullLogImpl struct{}
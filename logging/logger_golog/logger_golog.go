@@ -9,6 +9,8 @@
 package logger_golog
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -22,6 +24,7 @@ type goLogger struct {
 	logger         *log.Logger
 	level          logging.Level
 	entryFormatter formatter
+	request        logging.RequestInfo
 }
 
 const (
@@ -40,6 +43,12 @@ func NewLogger(out io.Writer, lvl logging.Level) *goLogger {
 	return logger
 }
 
+func NewJSONLogger(out io.Writer, lvl logging.Level) *goLogger {
+	logger := NewLogger(out, lvl)
+	logger.entryFormatter = &jsonFormatter{}
+	return logger
+}
+
 // anonymous function variants
 
 func (gl *goLogger) Loga(level logging.Level, f func() string) {
@@ -125,13 +134,45 @@ func (gl *goLogger) SetLevel(level logging.Level) {
 	gl.level = level
 }
 
+/*
+goLogger implements logging.CorrelatingLogger. The returned logger shares
+the output of the original one, and is used for a single record.
+*/
+func (gl *goLogger) Correlate(request logging.RequestInfo) logging.Log {
+	rv := *gl
+	rv.request = request
+	return &rv
+}
+
+/*
+goLogger implements logging.FormattingLogger.
+*/
+func (gl *goLogger) SetFormat(format string) bool {
+	switch format {
+	case logging.TEXT_FORMAT:
+		gl.entryFormatter = &standardFormatter{}
+	case logging.JSON_FORMAT:
+		gl.entryFormatter = &jsonFormatter{}
+	default:
+		return false
+	}
+	return true
+}
+
+func (gl *goLogger) Format() string {
+	if _, ok := gl.entryFormatter.(*jsonFormatter); ok {
+		return logging.JSON_FORMAT
+	}
+	return logging.TEXT_FORMAT
+}
+
 func (gl *goLogger) log(level logging.Level, rlevel logging.Level, msg string) {
 	gl.logger.Print(gl.str(level, rlevel, msg))
 }
 
 func (gl *goLogger) str(level logging.Level, rlevel logging.Level, msg string) string {
 	tm := time.Now().Format(logging.FULL_TIMESTAMP_FORMAT)
-	return gl.entryFormatter.format(tm, level, rlevel, msg)
+	return gl.entryFormatter.format(tm, level, rlevel, gl.request, msg)
 }
 
 func (gl *goLogger) Stringf(level logging.Level, format string, args ...interface{}) string {
@@ -139,13 +180,15 @@ func (gl *goLogger) Stringf(level logging.Level, format string, args ...interfac
 }
 
 type formatter interface {
-	format(string, logging.Level, logging.Level, string) string
+	format(string, logging.Level, logging.Level, logging.RequestInfo, string) string
 }
 
 type standardFormatter struct {
 }
 
-func (*standardFormatter) format(tm string, level logging.Level, rlevel logging.Level, msg string) string {
+func (*standardFormatter) format(tm string, level logging.Level, rlevel logging.Level, request logging.RequestInfo,
+	msg string) string {
+
 	var b strings.Builder
 	b.Grow(len(tm) + len(msg) + 32)
 	b.WriteString(tm)
@@ -164,7 +207,9 @@ func (*standardFormatter) format(tm string, level logging.Level, rlevel logging.
 type textFormatter struct {
 }
 
-func (*textFormatter) format(tm string, level logging.Level, rlevel logging.Level, msg string) string {
+func (*textFormatter) format(tm string, level logging.Level, rlevel logging.Level, request logging.RequestInfo,
+	msg string) string {
+
 	b := &strings.Builder{}
 	appendKeyValue(b, _TIME, tm)
 	appendKeyValue(b, _LEVEL, level.String())
@@ -183,3 +228,52 @@ func appendKeyValue(b *strings.Builder, key, value interface{}) {
 		fmt.Fprintf(b, "%v=%v ", key, value)
 	}
 }
+
+/*
+The JSON formatter writes one object per line, with stable field names so
+that records can be ingested without parsing the message. The request
+fields are present when the record is logged on behalf of a request, and
+the statement hash is added at REQUEST, DEBUG and TRACE level.
+*/
+type jsonFormatter struct {
+}
+
+type jsonRecord struct {
+	Timestamp       string `json:"timestamp"`
+	Level           string `json:"level"`
+	RLevel          string `json:"rlevel,omitempty"`
+	Component       string `json:"component,omitempty"`
+	RequestId       string `json:"request_id,omitempty"`
+	ClientContextId string `json:"client_context_id,omitempty"`
+	User            string `json:"user,omitempty"`
+	StatementHash   string `json:"statement_hash,omitempty"`
+	Message         string `json:"message"`
+}
+
+func (*jsonFormatter) format(tm string, level logging.Level, rlevel logging.Level, request logging.RequestInfo,
+	msg string) string {
+
+	rec := &jsonRecord{
+		Timestamp: tm,
+		Level:     level.String(),
+		Component: logging.CallerComponent(),
+		Message:   strings.TrimSpace(msg),
+	}
+	if rlevel != logging.NONE {
+		rec.RLevel = rlevel.String()
+	}
+	if request != nil {
+		rec.RequestId = request.RequestId()
+		rec.ClientContextId = request.ClientContextId()
+		rec.User = request.Users()
+		if level >= logging.REQUEST {
+			rec.StatementHash = request.StatementHash()
+		}
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(rec) // cannot fail, all fields are strings
+	return b.String()
+}
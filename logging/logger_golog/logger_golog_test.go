@@ -9,6 +9,8 @@
 package logger_golog
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
@@ -67,3 +69,47 @@ func TestStub(t *testing.T) {
 
 	logMessages(logger)
 }
+
+type testRequest struct {
+}
+
+func (this *testRequest) RequestId() string       { return "1234" }
+func (this *testRequest) ClientContextId() string { return "client" }
+func (this *testRequest) Users() string           { return "local:admin" }
+func (this *testRequest) StatementHash() string   { return "abcd" }
+
+func TestJSONFormat(t *testing.T) {
+	var b bytes.Buffer
+	logger := NewJSONLogger(&b, logging.DEBUG)
+
+	checkRecord := func(level, msg string, request bool) {
+		var rec map[string]interface{}
+		err := json.Unmarshal(b.Bytes(), &rec)
+		b.Reset()
+		if err != nil {
+			t.Fatalf("invalid record: %v", err)
+		}
+		if rec["level"] != level || rec["message"] != msg || rec["component"] == nil ||
+			rec["timestamp"] == nil {
+			t.Errorf("unexpected record %v", rec)
+		}
+		_, hasId := rec["request_id"]
+		_, hasHash := rec["statement_hash"]
+		if hasId != request || hasHash != (request && level == "DEBUG") {
+			t.Errorf("unexpected request fields in %v", rec)
+		}
+	}
+
+	logger.Infof("message with <%s>", "markup")
+	checkRecord("INFO", "message with <markup>", false)
+
+	request := &testRequest{}
+	logger.Correlate(request).Infof("request message")
+	checkRecord("INFO", "request message", true)
+	logger.Correlate(request).Debugf("request message")
+	checkRecord("DEBUG", "request message", true)
+
+	if !logger.SetFormat(logging.TEXT_FORMAT) || logger.Format() != logging.TEXT_FORMAT || logger.SetFormat("xml") {
+		t.Errorf("unexpected format change result")
+	}
+}
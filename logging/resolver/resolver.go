@@ -24,6 +24,10 @@ func NewLogger(uri string) (logging.Logger, errors.Error) {
 		logger = logger_golog.NewLogger(os.Stderr, logging.INFO)
 		logging.SetLogger(logger)
 		return logger, nil
+	case uri == "json":
+		logger = logger_golog.NewJSONLogger(os.Stderr, logging.INFO)
+		logging.SetLogger(logger)
+		return logger, nil
 
 	// these are request loggers
 
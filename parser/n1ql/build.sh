@@ -45,5 +45,9 @@ EOF
 fi
 go_tool $GOYACC n1ql.y
 
+# the syntax help and TAB completion of the cbq shell are generated from the grammar
+echo ../../shell/cbq/command/build.sh ../..
+bash ../../shell/cbq/command/build.sh ../..
+
 echo go build $*
 go build $*
@@ -151,6 +151,9 @@ type lexer struct {
 	hasSaved               bool
 	saved                  int
 	lval                   yySymType
+	prev                   int
+	depth                  int   // of parentheses
+	groupBy                []int // depths of the GROUP BY term lists being read
 	stop                   bool
 	udfExpr                bool
 	optimHints             *algebra.OptimHints
@@ -170,6 +173,58 @@ func newLexer(nex *Lexer, log logging.Log) *lexer {
 }
 
 func (this *lexer) Lex(lval *yySymType) int {
+	rv := this.lex(lval)
+	this.track(rv)
+	this.prev = rv
+	return rv
+}
+
+/*
+Follow the nesting of parentheses and the GROUP BY term lists, so that
+GAPFILL is only read as a keyword after the terms of a GROUP BY.
+*/
+func (this *lexer) track(tok int) {
+	switch tok {
+	case LPAREN:
+		this.depth++
+	case RPAREN:
+		this.depth--
+		for n := len(this.groupBy); n > 0 && this.groupBy[n-1] > this.depth; n-- {
+			this.groupBy = this.groupBy[:n-1]
+		}
+	case BY:
+		if this.prev == GROUP {
+			this.groupBy = append(this.groupBy, this.depth)
+		}
+	case GAPFILL, GROUP, LETTING, HAVING, WINDOW, ORDER, LIMIT, OFFSET, UNION, INTERSECT, EXCEPT, SEMI:
+		if this.inGroupBy() {
+			this.groupBy = this.groupBy[:len(this.groupBy)-1]
+		}
+	}
+}
+
+func (this *lexer) inGroupBy() bool {
+	n := len(this.groupBy)
+	return n > 0 && this.groupBy[n-1] == this.depth
+}
+
+/*
+Whether a token can end an expression or an alias, and so a GROUP BY
+or a FROM term: GAPFILL and ASOF are never read as keywords after an
+operator, a comma, FROM, AS, a dot or a colon.
+*/
+func endsTerm(tok int) bool {
+	switch tok {
+	case IDENT, IDENT_ICASE, STR, NUM, INT, NAMED_PARAM, POSITIONAL_PARAM, NEXT_PARAM,
+		RPAREN, RBRACKET, RBRACKET_ICASE, RBRACE, TRUE, FALSE, NULL, MISSING, VALUED, KNOWN, END,
+		DEFAULT, USER, USERS, SEQUENCE, DENSE, MULTI, SPARSE, VECTOR, CYCLE, SOURCE, CATALOG,
+		CONSUME, TYPE, SNAPSHOT, TIMESTAMP, CREDENTIALSTORE, EXTERNAL:
+		return true
+	}
+	return false
+}
+
+func (this *lexer) lex(lval *yySymType) int {
 	if this.stop {
 		return 0
 	}
@@ -184,6 +239,87 @@ func (this *lexer) Lex(lval *yySymType) int {
 
 	rv := this.nex.Lex(lval)
 
+	// REFRESH is not reserved: it is only a keyword when followed by MATERIALIZED
+	if rv == IDENT && strings.EqualFold(this.nex.Text(), "refresh") {
+		this.hasSaved = true
+		oldLval := *lval
+		this.saved = this.nex.Lex(lval)
+		this.lval = *lval
+		*lval = oldLval
+		if this.saved == MATERIALIZED {
+			return REFRESH
+		}
+		return rv
+	}
+
+	// GAPFILL is not reserved either: it is only a keyword right after
+	// the terms of a GROUP BY, where an identifier would be an alias
+	if rv == IDENT && this.inGroupBy() && endsTerm(this.prev) && strings.EqualFold(this.nex.Text(), "gapfill") {
+		this.hasSaved = true
+		oldLval := *lval
+		this.saved = this.nex.Lex(lval)
+		this.lval = *lval
+		*lval = oldLval
+		switch this.saved {
+		case LPAREN, RPAREN, GROUP, LETTING, HAVING, WINDOW, ORDER, LIMIT, OFFSET, UNION, INTERSECT, EXCEPT, SEMI, 0:
+			return GAPFILL
+		}
+		return rv
+	}
+
+	// ASOF is not reserved: it is only a keyword right after a FROM term,
+	// when followed by a join type or JOIN. There an identifier would be
+	// an implicit alias, which must then be given with AS.
+	if rv == IDENT && endsTerm(this.prev) && strings.EqualFold(this.nex.Text(), "asof") {
+		this.hasSaved = true
+		oldLval := *lval
+		this.saved = this.nex.Lex(lval)
+		this.lval = *lval
+		*lval = oldLval
+		switch this.saved {
+		case JOIN, INNER, LEFT:
+			return ASOF
+		}
+		return rv
+	}
+
+	// JOB is not reserved: it is only a keyword straight after CREATE [OR REPLACE], ALTER or DROP
+	if rv == IDENT && strings.EqualFold(this.nex.Text(), "job") {
+		switch this.prev {
+		case CREATE, REPLACE, ALTER, DROP:
+			return JOB
+		}
+	}
+
+	// POLICY is not reserved either, and is only a keyword in the same places, or after MASKING
+	if rv == IDENT && strings.EqualFold(this.nex.Text(), "policy") {
+		switch this.prev {
+		case CREATE, REPLACE, DROP, MASKING:
+			return POLICY
+		}
+	}
+
+	// nor is MASKING
+	if rv == IDENT && strings.EqualFold(this.nex.Text(), "masking") {
+		switch this.prev {
+		case CREATE, REPLACE, DROP:
+			return MASKING
+		}
+	}
+
+	// EXEMPT is only a keyword when followed by ROLE, at the end of CREATE MASKING POLICY
+	if rv == IDENT && this.prev == RPAREN && strings.EqualFold(this.nex.Text(), "exempt") {
+		this.hasSaved = true
+		oldLval := *lval
+		this.saved = this.nex.Lex(lval)
+		this.lval = *lval
+		*lval = oldLval
+		if this.saved == ROLE {
+			return EXEMPT
+		}
+		return rv
+	}
+
 	// we are going to treat identifiers specially to resolve
 	// shift reduce conflicts on namespaces
 	if rv != IDENT && rv != DEFAULT {
@@ -238,6 +374,53 @@ func (this *lexer) Remainder(offset int) string {
 	return strings.TrimLeft(this.text[offset:], " \t")
 }
 
+/*
+Returns the remainder after skipping the given number of words, for
+options following a token that records its offset.
+*/
+func (this *lexer) RemainderAfter(offset int, words int) string {
+	s := this.Remainder(offset)
+	for ; words > 0; words-- {
+		i := 0
+		for i < len(s) && (s[i] == '_' || unicode.IsLetter(rune(s[i])) || unicode.IsDigit(rune(s[i]))) {
+			i++
+		}
+		s = strings.TrimLeft(s[i:], " \t\r\n")
+	}
+	return s
+}
+
+/*
+Returns the remainder after skipping the given number of words and then
+an object, for options given as an object literal.
+*/
+func (this *lexer) RemainderAfterObject(offset int, words int) string {
+	s := this.RemainderAfter(offset, words)
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return strings.TrimLeft(s[i+1:], " \t\r\n")
+			}
+		}
+	}
+	return s
+}
+
 func (this *lexer) ErrorWithContext(s string, line int, column int) {
 	if line != 0 {
 		ectx := errors.NewErrorContext(line, column).Error()
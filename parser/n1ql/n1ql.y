@@ -27,6 +27,15 @@ type nameValueContext struct {
     column int
 }
 
+// EXPLAIN ANALYZE runs the statement, so it is limited to queries and DML
+func explainAnalyzeStatement(stmt algebra.Statement) bool {
+    switch stmt.Type() {
+    case "SELECT", "INSERT", "UPSERT", "UPDATE", "DELETE", "MERGE":
+        return true
+    }
+    return false
+}
+
 %}
 
 %union {
@@ -70,6 +79,7 @@ indexRef         *algebra.IndexRef
 subqueryTerm     *algebra.SubqueryTerm
 path             expression.Path
 group            *algebra.Group
+gapfill          *algebra.GapFill
 resultTerm       *algebra.ResultTerm
 resultTerms      algebra.ResultTerms
 projection       *algebra.Projection
@@ -303,6 +313,13 @@ column int
 %token REALM
 %token RECURSIVE
 %token REDUCE
+%token REFRESH
+%token GAPFILL
+%token ASOF
+%token JOB
+%token POLICY
+%token MASKING
+%token EXEMPT
 %token RENAME
 %token REPLACE
 %token RESPECT
@@ -415,7 +432,7 @@ column int
 %type <s>                STR
 %type <s>                IDENT IDENT_ICASE NAMESPACE_ID DEFAULT USER USERS SEQUENCE CYCLE
 %type <s>                VECTOR DENSE SPARSE MULTI CONSUME
-%type <s>                CATALOG SOURCE TYPE SNAPSHOT TIMESTAMP CREDENTIALSTORE EXTERNAL ORDER
+%type <s>                CATALOG SOURCE TYPE SNAPSHOT TIMESTAMP CREDENTIALSTORE EXTERNAL ORDER GAPFILL ASOF
 %type <s>                permitted_identifiers alias_identifiers perm_ident_or_str
 %type <identifier>       ident ident_icase
 %type <s>                REPLACE
@@ -481,6 +498,7 @@ column int
 %type <withclause>       with
 %type <expr>             opt_where where opt_filter
 %type <group>            opt_group group
+%type <gapfill>          opt_gapfill
 %type <expr>             opt_group_as
 %type <bindings>         opt_letting letting
 %type <expr>             opt_having having
@@ -506,7 +524,7 @@ column int
 %type <statement>        scope_stmt create_scope drop_scope
 %type <statement>        transaction_stmt start_transaction commit_transaction rollback_transaction
 %type <statement>        savepoint set_transaction_isolation
-%type <statement>        collection_stmt create_collection drop_collection flush_collection
+%type <statement>        collection_stmt create_collection drop_collection flush_collection truncate
 %type <statement>        external_collection_stmt create_external_collection alter_collection
 %type <statement>        role_stmt grant_role revoke_role
 %type <statement>        user_stmt create_user alter_user drop_user
@@ -587,8 +605,20 @@ column int
 %type <exprs>              opt_exclude
 
 %type <cyclecheck>         opt_cycle_clause
+%type <n>                  opt_materialization
 %type <statement>          sequence_stmt create_sequence drop_sequence alter_sequence
 %type <keyspacePath>       sequence_full_name
+
+%type <statement>          view_stmt create_view drop_view refresh_view
+%type <keyspacePath>       view_full_name
+%type <s>                  view_object_name
+%type <statement>          trigger_stmt create_trigger drop_trigger
+%type <s>                  trigger_event
+%type <statement>          job_stmt create_job alter_job drop_job
+%type <s>                  opt_job_schedule
+%type <statement>          policy_stmt create_policy drop_policy create_masking_policy drop_masking_policy
+%type <s>                  policy_event
+%type <ss>                 policy_grantees opt_policy_exempt
 %type <s>                  opt_namespace_name sequence_object_name
 %type <ss>                 sequence_next sequence_prev
 %type <expr>               sequence_expr
@@ -666,6 +696,12 @@ alias_identifiers
 |
 ORDER
 { $$ = $1 }
+|
+GAPFILL
+{ $$ = $1 }
+|
+ASOF
+{ $$ = $1 }
 ;
 
 perm_ident_or_str:
@@ -719,6 +755,14 @@ transaction_stmt
 |
 sequence_stmt
 |
+view_stmt
+|
+trigger_stmt
+|
+job_stmt
+|
+policy_stmt
+|
 credentialstore_stmt
 ;
 
@@ -741,7 +785,69 @@ INDEX
 explain:
 EXPLAIN stmt
 {
-    $$ = algebra.NewExplain($2, yylex.(*lexer).Remainder($<tokOffset>1))
+    $$ = algebra.NewExplain($2, yylex.(*lexer).Remainder($<tokOffset>1), "")
+}
+|
+EXPLAIN IDENT IDENT stmt
+{
+    if strings.ToLower($2) != "format" || !algebra.IsExplainFormat($3) {
+        yylex.(*lexer).ErrorWithContext("EXPLAIN FORMAT must be followed by JSON, TEXT or DOT", $<line>2, $<column>2)
+    }
+    $$ = algebra.NewExplain($4, yylex.(*lexer).RemainderAfter($<tokOffset>1, 2), $3)
+}
+|
+EXPLAIN ANALYZE stmt
+{
+    if !explainAnalyzeStatement($3) {
+        yylex.(*lexer).ErrorWithContext("EXPLAIN ANALYZE is only supported for SELECT and data modification statements",
+            $<line>2, $<column>2)
+    }
+    $$ = algebra.NewExplainAnalyze($3, yylex.(*lexer).RemainderAfter($<tokOffset>1, 1), "")
+}
+|
+EXPLAIN IDENT IDENT ANALYZE stmt
+{
+    if strings.ToLower($2) != "format" || !algebra.IsExplainFormat($3) {
+        yylex.(*lexer).ErrorWithContext("EXPLAIN FORMAT must be followed by JSON, TEXT or DOT", $<line>2, $<column>2)
+    }
+    if !explainAnalyzeStatement($5) {
+        yylex.(*lexer).ErrorWithContext("EXPLAIN ANALYZE is only supported for SELECT and data modification statements",
+            $<line>4, $<column>4)
+    }
+    $$ = algebra.NewExplainAnalyze($5, yylex.(*lexer).RemainderAfter($<tokOffset>1, 3), $3)
+}
+|
+EXPLAIN ANALYZE WITH object stmt
+{
+    if !explainAnalyzeStatement($5) {
+        yylex.(*lexer).ErrorWithContext("EXPLAIN ANALYZE is only supported for SELECT and data modification statements",
+            $<line>2, $<column>2)
+    }
+    explain := algebra.NewExplainAnalyze($5, yylex.(*lexer).RemainderAfterObject($<tokOffset>1, 2), "")
+    if options := $4.Value(); options == nil {
+        yylex.(*lexer).ErrorWithContext("EXPLAIN ANALYZE options must be static", $<line>3, $<column>3)
+    } else if err := explain.SetAnalyzeOptions(options); err != nil {
+        yylex.(*lexer).ErrorWithContext(err.Error(), $<line>3, $<column>3)
+    }
+    $$ = explain
+}
+|
+EXPLAIN IDENT IDENT ANALYZE WITH object stmt
+{
+    if strings.ToLower($2) != "format" || !algebra.IsExplainFormat($3) {
+        yylex.(*lexer).ErrorWithContext("EXPLAIN FORMAT must be followed by JSON, TEXT or DOT", $<line>2, $<column>2)
+    }
+    if !explainAnalyzeStatement($7) {
+        yylex.(*lexer).ErrorWithContext("EXPLAIN ANALYZE is only supported for SELECT and data modification statements",
+            $<line>4, $<column>4)
+    }
+    explain := algebra.NewExplainAnalyze($7, yylex.(*lexer).RemainderAfterObject($<tokOffset>1, 4), $3)
+    if options := $6.Value(); options == nil {
+        yylex.(*lexer).ErrorWithContext("EXPLAIN ANALYZE options must be static", $<line>5, $<column>5)
+    } else if err := explain.SetAnalyzeOptions(options); err != nil {
+        yylex.(*lexer).ErrorWithContext(err.Error(), $<line>5, $<column>5)
+    }
+    $$ = explain
 }
 ;
 
@@ -1003,6 +1109,8 @@ drop_collection
 alter_collection
 |
 flush_collection
+|
+truncate
 ;
 
 external_collection_stmt:
@@ -1531,6 +1639,13 @@ from_term opt_join_type JOIN LATERAL simple_from_term ON expr
     $$ = algebra.NewAnsiJoin($1, $2, $5, $7)
 }
 |
+/* the lexer reads ASOF as a keyword in place of an implicit alias */
+from_term ASOF opt_join_type JOIN simple_from_term ON expr
+{
+    $5.SetAnsiJoin()
+    $$ = algebra.NewAsofJoin($1, $3, $5, $7)
+}
+|
 from_term opt_join_type NEST simple_from_term ON expr
 {
     $4.SetAnsiNest()
@@ -2048,13 +2163,31 @@ with_term:
 /* we want expressions in parentesheses, but don't want to be
    forced to have subquery expressions in nested parentheses
  */
-alias AS paren_expr opt_cycle_clause opt_option_clause
+alias AS opt_materialization paren_expr opt_cycle_clause opt_option_clause
 {
-    $$ = algebra.NewWith($1, $3,nil, false, $5, $4)
+    $$ = algebra.NewWith($1, $4,nil, false, $6, $5)
+    $$.SetMaterialization(expression.Materialization($3))
     $$.SetErrorContext($<line>1, $<column>1)
 }
 ;
 
+opt_materialization:
+/* empty */
+{
+    $$ = int64(expression.MATERIALIZE_DEFAULT)
+}
+|
+MATERIALIZED
+{
+    $$ = int64(expression.MATERIALIZE_ALWAYS)
+}
+|
+NOT MATERIALIZED
+{
+    $$ = int64(expression.MATERIALIZE_NEVER)
+}
+;
+
 opt_option_clause:
 {
     $$ = nil
@@ -2122,16 +2255,17 @@ group
 ;
 
 group:
-GROUP BY group_terms opt_group_as opt_letting opt_having
+GROUP BY group_terms opt_gapfill opt_group_as opt_letting opt_having
 {
     as := ""
-    if $4 != nil {
-        as = $4.Alias()
+    if $5 != nil {
+        as = $5.Alias()
     }
-    g := algebra.NewGroup($3, $5, $6, as)
-    if $4 != nil {
-        g.SetAsErrorContext($4.GetErrorContext())
+    g := algebra.NewGroup($3, $6, $7, as)
+    if $5 != nil {
+        g.SetAsErrorContext($5.GetErrorContext())
     }
+    g.SetGapFill($4)
     $$ = g
 }
 |
@@ -2196,6 +2330,23 @@ HAVING expr
 }
 ;
 
+opt_gapfill:
+/* empty */
+{
+    $$ = nil
+}
+|
+GAPFILL
+{
+    $$ = algebra.NewGapFill(nil, nil)
+}
+|
+GAPFILL LPAREN expr COMMA expr RPAREN
+{
+    $$ = algebra.NewGapFill($3, $5)
+}
+;
+
 opt_group_as:
 /* empty */
 {
@@ -3883,6 +4034,27 @@ FLUSH
 TRUNCATE
 ;
 
+/*************************************************
+ *
+ * TRUNCATE [TABLE]
+ *
+ *************************************************/
+
+truncate:
+TRUNCATE named_keyspace_ref
+{
+    $$ = algebra.NewTruncate($2)
+}
+|
+TRUNCATE IDENT named_keyspace_ref
+{
+    if strings.ToLower($2) != "table" {
+        yylex.(*lexer).ErrorWithContext("TRUNCATE must be followed by TABLE or a keyspace", $<line>2, $<column>2)
+    }
+    $$ = algebra.NewTruncate($3)
+}
+;
+
 /*************************************************
  *
  * CREATE EXTERNAL COLLECTION
@@ -6806,6 +6978,352 @@ sequence_prev
  *
  *************************************************/
 
+/*************************************************
+ *
+ * CREATE / DROP VIEW
+ *
+ *************************************************/
+
+view_object_name:
+permitted_identifiers
+{
+  $$ = strings.TrimSpace($1)
+  if $$ != $1 || $$ == "" {
+    return yylex.(*lexer).FatalError(fmt.Sprintf("Invalid identifier '%v'", $1), $<line>1, $<column>1)
+  }
+}
+|
+_invalid_case_insensitive_identifier
+{
+    return yylex.(*lexer).FatalError("Invalid view name", $<line>1, $<column>1)
+}
+;
+
+view_full_name:
+opt_namespace_name view_object_name
+{
+    p, err := algebra.NewVariablePathWithContext($2, $1, yylex.(*lexer).QueryContext())
+    if err != nil {
+        return yylex.(*lexer).FatalError(err.Error(), $<line>2, $<column>2)
+    }
+    if p == nil || p.Scope() == "" {
+        return yylex.(*lexer).FatalError("Invalid view name", $<line>2, $<column>2)
+    }
+    $$ = p
+}
+|
+opt_namespace_name path_part DOT path_part DOT view_object_name
+{
+    $$ = algebra.NewPathLong($1, $2, $4, $6)
+}
+|
+opt_namespace_name path_part DOT view_object_name
+{
+    p, err := algebra.NewPathFromElementsWithContext([]string{$2, $4}, $1, yylex.(*lexer).QueryContext())
+    if err != nil {
+        return yylex.(*lexer).FatalError(err.Error(), $<line>2, $<column>2)
+    }
+    if p == nil || p.Scope() == "" {
+        return yylex.(*lexer).FatalError("Invalid view name", $<line>2, $<column>2)
+    }
+    $$ = p
+}
+;
+
+view_stmt:
+create_view
+|
+drop_view
+|
+refresh_view
+;
+
+create_view:
+CREATE opt_replace VIEW view_full_name
+{
+    // names in the definition are relative to the scope of the view
+    yylex.(*lexer).PushQueryContext($4.QueryContext())
+}
+AS fullselect
+{
+    yylex.(*lexer).PopQueryContext()
+    if yylex.(*lexer).paramCount > 0 {
+        return yylex.(*lexer).FatalError("View definitions cannot have parameters", $<line>7, $<column>7)
+    }
+    text := strings.TrimRight(yylex.(*lexer).Remainder($<tokOffset>6), " \t\r\n;")
+    $$ = algebra.NewCreateView($4, $7, text, $4.QueryContext(), $2.Value().Truth(), false, nil)
+}
+|
+CREATE MATERIALIZED VIEW view_full_name opt_with_clause
+{
+    yylex.(*lexer).PushQueryContext($4.QueryContext())
+}
+AS fullselect
+{
+    yylex.(*lexer).PopQueryContext()
+    if yylex.(*lexer).paramCount > 0 {
+        return yylex.(*lexer).FatalError("View definitions cannot have parameters", $<line>8, $<column>8)
+    }
+    text := strings.TrimRight(yylex.(*lexer).Remainder($<tokOffset>7), " \t\r\n;")
+    $$ = algebra.NewCreateView($4, $8, text, $4.QueryContext(), false, true, $5)
+}
+;
+
+drop_view:
+DROP VIEW view_full_name opt_if_exists
+{
+    $$ = algebra.NewDropView($3, $4, false)
+}
+|
+DROP VIEW IF EXISTS view_full_name
+{
+    $$ = algebra.NewDropView($5, false, false)
+}
+|
+DROP MATERIALIZED VIEW view_full_name opt_if_exists
+{
+    $$ = algebra.NewDropView($4, $5, true)
+}
+|
+DROP MATERIALIZED VIEW IF EXISTS view_full_name
+{
+    $$ = algebra.NewDropView($6, false, true)
+}
+;
+
+refresh_view:
+REFRESH MATERIALIZED VIEW view_full_name
+{
+    $$ = algebra.NewRefreshView($4, false)
+}
+|
+REFRESH MATERIALIZED VIEW view_full_name IDENT
+{
+    if strings.ToLower($5) != "incremental" {
+        yylex.(*lexer).ErrorWithContext("REFRESH MATERIALIZED VIEW can only be followed by INCREMENTAL", $<line>5, $<column>5)
+    }
+    $$ = algebra.NewRefreshView($4, true)
+}
+;
+
+trigger_stmt:
+create_trigger
+|
+drop_trigger
+;
+
+create_trigger:
+CREATE opt_replace TRIGGER permitted_identifiers IDENT trigger_event ON named_keyspace_ref FOR EACH IDENT
+EXECUTE FUNCTION func_name LPAREN opt_exprs RPAREN
+{
+    timing := strings.ToLower($5)
+    if timing != "before" && timing != "after" {
+        return yylex.(*lexer).FatalError("Trigger timing must be BEFORE or AFTER", $<line>5, $<column>5)
+    }
+    if strings.ToLower($11) != "row" {
+        return yylex.(*lexer).FatalError("Triggers can only be defined FOR EACH ROW", $<line>11, $<column>11)
+    }
+    if yylex.(*lexer).paramCount > 0 {
+        return yylex.(*lexer).FatalError("Trigger definitions cannot have parameters", $<line>16, $<column>16)
+    }
+    $$ = algebra.NewCreateTrigger($4, $8, timing, $6, $14, $16, yylex.(*lexer).QueryContext(), $2.Value().Truth())
+}
+;
+
+trigger_event:
+INSERT
+{
+    $$ = "insert"
+}
+|
+UPDATE
+{
+    $$ = "update"
+}
+|
+DELETE
+{
+    $$ = "delete"
+}
+;
+
+drop_trigger:
+DROP TRIGGER permitted_identifiers ON named_keyspace_ref
+{
+    $$ = algebra.NewDropTrigger($3, $5, true)
+}
+|
+DROP TRIGGER IF EXISTS permitted_identifiers ON named_keyspace_ref
+{
+    $$ = algebra.NewDropTrigger($5, $7, false)
+}
+;
+
+job_stmt:
+create_job
+|
+alter_job
+|
+drop_job
+;
+
+create_job:
+CREATE opt_replace JOB permitted_identifiers IDENT STR opt_with_clause AS stmt
+{
+    if strings.ToLower($5) != "schedule" {
+        return yylex.(*lexer).FatalError("Job name must be followed by SCHEDULE", $<line>5, $<column>5)
+    }
+    if yylex.(*lexer).paramCount > 0 {
+        return yylex.(*lexer).FatalError("Job definitions cannot have parameters", $<line>9, $<column>9)
+    }
+    text := strings.TrimRight(yylex.(*lexer).Remainder($<tokOffset>8), " \t\r\n;")
+    $$ = algebra.NewCreateJob($4, $6, $7, $9, text, yylex.(*lexer).QueryContext(), $2.Value().Truth())
+}
+;
+
+alter_job:
+ALTER JOB permitted_identifiers opt_job_schedule opt_with_clause
+{
+    if $4 == "" && $5 == nil {
+        return yylex.(*lexer).FatalError("ALTER JOB requires a SCHEDULE, WITH options or AS statement", $<line>3, $<column>3)
+    }
+    $$ = algebra.NewAlterJob($3, $4, $5, nil, "", "")
+}
+|
+ALTER JOB permitted_identifiers opt_job_schedule opt_with_clause AS stmt
+{
+    if yylex.(*lexer).paramCount > 0 {
+        return yylex.(*lexer).FatalError("Job definitions cannot have parameters", $<line>7, $<column>7)
+    }
+    text := strings.TrimRight(yylex.(*lexer).Remainder($<tokOffset>6), " \t\r\n;")
+    $$ = algebra.NewAlterJob($3, $4, $5, $7, text, yylex.(*lexer).QueryContext())
+}
+;
+
+opt_job_schedule:
+/* empty */
+{
+    $$ = ""
+}
+|
+IDENT STR
+{
+    if strings.ToLower($1) != "schedule" {
+        return yylex.(*lexer).FatalError("Expected SCHEDULE", $<line>1, $<column>1)
+    }
+    if $2 == "" {
+        return yylex.(*lexer).FatalError("Job schedule cannot be empty", $<line>2, $<column>2)
+    }
+    $$ = $2
+}
+;
+
+drop_job:
+DROP JOB permitted_identifiers
+{
+    $$ = algebra.NewDropJob($3, true)
+}
+|
+DROP JOB IF EXISTS permitted_identifiers
+{
+    $$ = algebra.NewDropJob($5, false)
+}
+;
+
+policy_stmt:
+create_policy
+|
+drop_policy
+|
+create_masking_policy
+|
+drop_masking_policy
+;
+
+create_policy:
+CREATE opt_replace POLICY permitted_identifiers ON named_keyspace_ref FOR policy_event TO policy_grantees
+USING LPAREN expr RPAREN
+{
+    if yylex.(*lexer).paramCount > 0 {
+        return yylex.(*lexer).FatalError("Policy definitions cannot have parameters", $<line>13, $<column>13)
+    }
+    $$ = algebra.NewCreatePolicy($4, $6, $8, $10, $13, $2.Value().Truth())
+}
+;
+
+policy_event:
+SELECT
+{
+    $$ = "select"
+}
+|
+UPDATE
+{
+    $$ = "update"
+}
+|
+DELETE
+{
+    $$ = "delete"
+}
+;
+
+policy_grantees:
+PUBLIC
+{
+    $$ = nil
+}
+|
+role_list
+;
+
+drop_policy:
+DROP POLICY permitted_identifiers ON named_keyspace_ref
+{
+    $$ = algebra.NewDropPolicy($3, $5, false, true)
+}
+|
+DROP POLICY IF EXISTS permitted_identifiers ON named_keyspace_ref
+{
+    $$ = algebra.NewDropPolicy($5, $7, false, false)
+}
+;
+
+create_masking_policy:
+CREATE opt_replace MASKING POLICY permitted_identifiers ON named_keyspace_ref LPAREN expr RPAREN
+USING LPAREN expr RPAREN opt_policy_exempt
+{
+    if yylex.(*lexer).paramCount > 0 {
+        return yylex.(*lexer).FatalError("Policy definitions cannot have parameters", $<line>13, $<column>13)
+    }
+    $$ = algebra.NewCreateMaskingPolicy($5, $7, $9, $13, $15, $2.Value().Truth())
+}
+;
+
+opt_policy_exempt:
+/* empty */
+{
+    $$ = nil
+}
+|
+EXEMPT ROLE role_list
+{
+    $$ = $3
+}
+;
+
+drop_masking_policy:
+DROP MASKING POLICY permitted_identifiers ON named_keyspace_ref
+{
+    $$ = algebra.NewDropPolicy($4, $6, true, true)
+}
+|
+DROP MASKING POLICY IF EXISTS permitted_identifiers ON named_keyspace_ref
+{
+    $$ = algebra.NewDropPolicy($6, $8, true, false)
+}
+;
+
 credentialstore_stmt:
 create_credentialstore
 |
@@ -0,0 +1,159 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package n1ql
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+)
+
+func parseSubselect(t *testing.T, s string) *algebra.Subselect {
+	t.Helper()
+	SetNamespaces(map[string]interface{}{"default": true})
+	stmt, err := ParseStatement(s)
+	if err != nil {
+		t.Fatalf("ParseStatement(%q): %v", s, err)
+	}
+	sel, ok := stmt.(*algebra.Select)
+	if !ok {
+		t.Fatalf("ParseStatement(%q): expected a SELECT, found %T", s, stmt)
+	}
+	sub, ok := sel.Subresult().(*algebra.Subselect)
+	if !ok {
+		t.Fatalf("ParseStatement(%q): expected a subselect, found %T", s, sel.Subresult())
+	}
+	return sub
+}
+
+func TestGapfill(t *testing.T) {
+	// gapfill is still an identifier everywhere but right after the terms of a GROUP BY
+	for _, s := range []string{
+		"SELECT COUNT(gapfill) FROM ks",
+		"SELECT x FROM ks WHERE x = gapfill",
+		"SELECT LOWER(gapfill) FROM ks",
+		"SELECT gapfill FROM ks ORDER BY 1",
+		"SELECT gapfill FROM ks GROUP BY gapfill ORDER BY gapfill",
+		"SELECT COUNT(*) FROM ks GROUP BY x, gapfill HAVING COUNT(*) > 1",
+		"SELECT COUNT(*) FROM ks GROUP BY x + gapfill ORDER BY 1",
+		"SELECT COUNT(*) FROM ks GROUP BY x AS gapfill ORDER BY gapfill",
+		"SELECT COUNT(*) FROM ks GROUP BY ks.gapfill",
+		"SELECT COUNT(*) FROM ks GROUP BY x LETTING gapfill = 1 ORDER BY gapfill",
+		"SELECT t.gapfill FROM (SELECT gapfill FROM ks GROUP BY gapfill) AS t GROUP BY t.gapfill",
+	} {
+		sub := parseSubselect(t, s)
+		if g := sub.Group(); g != nil && g.GapFill() != nil {
+			t.Errorf("ParseStatement(%q): unexpected GAPFILL", s)
+		}
+	}
+
+	for _, s := range []string{
+		"SELECT COUNT(*) FROM ks GROUP BY TIME_BUCKET(\"1h\", ts) GAPFILL",
+		"SELECT COUNT(*) FROM ks GROUP BY TIME_BUCKET(\"1h\", ts) GAPFILL ORDER BY 1",
+		"SELECT COUNT(*) FROM ks GROUP BY TIME_BUCKET(\"1h\", ts) AS hour gapfill LETTING c = 1 HAVING c > 0",
+		"SELECT COUNT(*) FROM ks GROUP BY gapfill, TIME_BUCKET(\"1h\", ts) GAPFILL (\"2026-10-18\", \"2026-10-19\")",
+		"SELECT COUNT(*) FROM ks GROUP BY TIME_BUCKET(\"1h\", ts) GAPFILL LIMIT 10",
+	} {
+		sub := parseSubselect(t, s)
+		if g := sub.Group(); g == nil || g.GapFill() == nil {
+			t.Errorf("ParseStatement(%q): expected GAPFILL", s)
+		}
+	}
+
+	// in a subquery
+	s := "SELECT t.c FROM (SELECT COUNT(*) AS c FROM ks GROUP BY TIME_BUCKET(\"1h\", ts) GAPFILL) AS t"
+	from, ok := parseSubselect(t, s).From().(*algebra.SubqueryTerm)
+	if !ok {
+		t.Fatalf("ParseStatement(%q): expected a subquery term", s)
+	}
+	sub, ok := from.Subquery().Subresult().(*algebra.Subselect)
+	if !ok || sub.Group() == nil || sub.Group().GapFill() == nil {
+		t.Errorf("ParseStatement(%q): expected GAPFILL in the subquery", s)
+	}
+}
+
+func TestAsofJoin(t *testing.T) {
+	// asof is still an alias after AS or in backticks, and a keyspace name
+	for _, s := range []string{
+		"SELECT * FROM a AS asof JOIN b ON asof.id = b.id",
+		"SELECT * FROM a AS asof LEFT JOIN b ON asof.id = b.id",
+		"SELECT * FROM a AS asof INNER JOIN b ON asof.id = b.id",
+		"SELECT * FROM a `asof` JOIN b ON `asof`.id = b.id",
+		"SELECT * FROM asof JOIN b ON asof.id = b.id",
+		"SELECT * FROM default:asof LEFT JOIN b ON asof.id = b.id",
+	} {
+		if _, ok := parseSubselect(t, s).From().(*algebra.AnsiJoin); !ok {
+			t.Errorf("ParseStatement(%q): expected an ANSI join", s)
+		}
+	}
+
+	for _, c := range []struct {
+		text  string
+		outer bool
+	}{
+		{"SELECT * FROM trades AS t ASOF JOIN quotes AS q ON t.sym = q.sym AND t.ts >= q.ts", false},
+		{"SELECT * FROM trades AS t asof INNER JOIN quotes AS q ON t.sym = q.sym AND t.ts >= q.ts", false},
+		{"SELECT * FROM trades AS t ASOF LEFT JOIN quotes AS q ON t.sym = q.sym AND t.ts >= q.ts", true},
+		{"SELECT * FROM trades t ASOF LEFT OUTER JOIN quotes q ON t.sym = q.sym AND t.ts <= q.ts", true},
+
+		// in place of an implicit alias, asof is the keyword
+		{"SELECT * FROM trades asof JOIN quotes ON trades.ts >= quotes.ts", false},
+		{"SELECT * FROM trades asof LEFT JOIN quotes ON trades.ts >= quotes.ts", true},
+	} {
+		j, ok := parseSubselect(t, c.text).From().(*algebra.AsofJoin)
+		if !ok {
+			t.Errorf("ParseStatement(%q): expected an ASOF join", c.text)
+		} else if j.Outer() != c.outer {
+			t.Errorf("ParseStatement(%q): expected outer %v", c.text, c.outer)
+		}
+	}
+}
+
+func TestExplainAnalyzeRollback(t *testing.T) {
+	SetNamespaces(map[string]interface{}{"default": true})
+	for _, c := range []struct {
+		text     string
+		rollback bool
+		stmt     string
+	}{
+		{"EXPLAIN ANALYZE DELETE FROM ks", true, "DELETE FROM ks"},
+		{"EXPLAIN ANALYZE WITH {\"rollback\": true} DELETE FROM ks", true, "DELETE FROM ks"},
+		{"EXPLAIN ANALYZE WITH {\"rollback\": false} DELETE FROM ks", false, "DELETE FROM ks"},
+		{"EXPLAIN FORMAT TEXT ANALYZE WITH {\"rollback\": false} UPDATE ks SET x = \"}\"", false,
+			"UPDATE ks SET x = \"}\""},
+		{"EXPLAIN ANALYZE WITH w AS (SELECT 1) SELECT * FROM w", true, "WITH w AS (SELECT 1) SELECT * FROM w"},
+	} {
+		stmt, err := ParseStatement(c.text)
+		if err != nil {
+			t.Errorf("ParseStatement(%q): %v", c.text, err)
+			continue
+		}
+		explain, ok := stmt.(*algebra.Explain)
+		if !ok || !explain.Analyze() {
+			t.Errorf("ParseStatement(%q): expected EXPLAIN ANALYZE, found %T", c.text, stmt)
+			continue
+		}
+		if explain.Rollback() != c.rollback {
+			t.Errorf("ParseStatement(%q): expected rollback %v", c.text, c.rollback)
+		}
+		if explain.Text() != c.stmt {
+			t.Errorf("ParseStatement(%q): expected text %q, got %q", c.text, c.stmt, explain.Text())
+		}
+	}
+
+	for _, s := range []string{
+		"EXPLAIN ANALYZE WITH {\"rollback\": 0} DELETE FROM ks",
+		"EXPLAIN ANALYZE WITH {\"commit\": true} DELETE FROM ks",
+		"EXPLAIN ANALYZE WITH {\"rollback\": $1} DELETE FROM ks",
+	} {
+		if _, err := ParseStatement(s); err == nil {
+			t.Errorf("ParseStatement(%q): expected an error", s)
+		}
+	}
+}
@@ -27,6 +27,7 @@ type SendDelete struct {
 	limit        expression.Expression
 	validateKeys bool
 	fastDiscard  bool // if the execution phase should discard items without sending them downstream
+	unfetched    bool // if items are keys or index entries rather than the documents
 }
 
 func NewSendDelete(keyspace datastore.Keyspace, ksref *algebra.KeyspaceRef, limit expression.Expression,
@@ -62,6 +63,14 @@ func (this *SendDelete) ValidateKeys() bool {
 	return this.validateKeys
 }
 
+func (this *SendDelete) SetUnfetched(on bool) {
+	this.unfetched = on
+}
+
+func (this *SendDelete) Unfetched() bool {
+	return this.unfetched
+}
+
 func (this *SendDelete) Term() *algebra.KeyspaceRef {
 	return this.term
 }
@@ -95,6 +104,10 @@ func (this *SendDelete) MarshalBase(f func(map[string]interface{})) map[string]i
 		r["validate_keys"] = this.validateKeys
 	}
 
+	if this.unfetched {
+		r["unfetched"] = this.unfetched
+	}
+
 	if optEstimate := marshalOptEstimate(&this.optEstimate); optEstimate != nil {
 		r["optimizer_estimates"] = optEstimate
 	}
@@ -121,6 +134,7 @@ func (this *SendDelete) UnmarshalJSON(body []byte) error {
 		OptEstimate  map[string]interface{} `json:"optimizer_estimates"`
 		ValidateKeys bool                   `json:"validate_keys"`
 		FastDiscard  bool                   `json:"fast_discard"`
+		Unfetched    bool                   `json:"unfetched"`
 	}
 
 	err := json.Unmarshal(body, &_unmarshalled)
@@ -131,6 +145,7 @@ func (this *SendDelete) UnmarshalJSON(body []byte) error {
 	this.alias = _unmarshalled.Alias
 	this.validateKeys = _unmarshalled.ValidateKeys
 	this.fastDiscard = _unmarshalled.FastDiscard
+	this.unfetched = _unmarshalled.Unfetched
 
 	if _unmarshalled.Limit != "" {
 		this.limit, err = parser.Parse(_unmarshalled.Limit)
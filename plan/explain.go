@@ -19,13 +19,20 @@ type Explain struct {
 	qp         *QueryPlan
 	text       string
 	optimHints *algebra.OptimHints
+	analyze    bool
+	rollback   bool
+	format     string
 }
 
-func NewExplain(qp *QueryPlan, text string, optimHints *algebra.OptimHints) *Explain {
+func NewExplain(qp *QueryPlan, text string, optimHints *algebra.OptimHints, analyze, rollback bool,
+	format string) *Explain {
 	return &Explain{
 		qp:         qp,
 		text:       text,
 		optimHints: optimHints,
+		analyze:    analyze,
+		rollback:   rollback,
+		format:     format,
 	}
 }
 
@@ -41,6 +48,25 @@ func (this *Explain) Plan() Operator {
 	return this.qp.op
 }
 
+// EXPLAIN ANALYZE runs the statement
+func (this *Explain) Analyze() bool {
+	return this.analyze
+}
+
+// EXPLAIN ANALYZE undoes the changes of the statement
+func (this *Explain) Rollback() bool {
+	return this.rollback
+}
+
+// output format, empty or json for the plan itself
+func (this *Explain) Format() string {
+	return this.format
+}
+
+func (this *Explain) Readonly() bool {
+	return !this.analyze || this.qp.op.Readonly()
+}
+
 func (this *Explain) MarshalJSON() ([]byte, error) {
 	return json.Marshal(this.MarshalBase(nil))
 }
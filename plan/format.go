@@ -0,0 +1,972 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/expression"
+)
+
+/*
+Render an operator tree for people rather than programs: TEXT is an
+indented operator tree, and DOT is a Graphviz digraph. annotate, if not
+nil, returns additional attributes for an operator, such as its
+execution statistics.
+*/
+func Format(op Operator, format string, annotate func(Operator) []string) (string, error) {
+	return render([]Operator{op}, nil, format, annotate)
+}
+
+/*
+Render the explained plan followed by the plans of its subqueries.
+*/
+func (this *Explain) Render(annotate func(Operator) []string) (string, error) {
+	ops := []Operator{this.qp.op}
+	titles := []string{""}
+
+	subqueries := make([]*algebra.Select, 0, len(this.qp.subqueries))
+	for t := range this.qp.subqueries {
+		subqueries = append(subqueries, t)
+	}
+	sort.Slice(subqueries, func(i, j int) bool {
+		return subqueries[i].String() < subqueries[j].String()
+	})
+	for _, t := range subqueries {
+		ops = append(ops, this.qp.subqueries[t])
+		titles = append(titles, t.String())
+	}
+	return render(ops, titles, this.format, annotate)
+}
+
+func render(ops []Operator, titles []string, format string, annotate func(Operator) []string) (string, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case "", algebra.EXPLAIN_FORMAT_JSON:
+		bytes, err := json.MarshalIndent(ops[0], "", "    ")
+		return string(bytes), err
+	case algebra.EXPLAIN_FORMAT_TEXT, algebra.EXPLAIN_FORMAT_DOT:
+	default:
+		return "", fmt.Errorf("Unknown plan format %s", format)
+	}
+
+	f := &formatter{annotate: annotate}
+	nodes := make([]*formatNode, 0, len(ops))
+	for i, op := range ops {
+		n, err := f.visit(op)
+		if err != nil {
+			return "", err
+		}
+
+		// subqueries are separate trees headed by their text
+		if i < len(titles) && titles[i] != "" {
+			n = &formatNode{operator: "Subquery", attrs: []string{titles[i]}, children: []*formatNode{n}}
+		}
+		nodes = append(nodes, n)
+	}
+
+	var sb strings.Builder
+	if format == algebra.EXPLAIN_FORMAT_DOT {
+		sb.WriteString("digraph plan {\n\tnode [shape=box, fontname=\"Courier\"];\n")
+		next := 0
+		for _, n := range nodes {
+			n.dot(&sb, &next)
+		}
+		sb.WriteString("}\n")
+	} else {
+		for i, n := range nodes {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			n.text(&sb, 0)
+		}
+	}
+	return sb.String(), nil
+}
+
+/*
+Render a plan stored as JSON, as found in system:prepareds and
+system:completed_requests.
+*/
+func FormatJSON(body []byte, format string) (string, error) {
+	var op_type struct {
+		Operator string `json:"#operator"`
+	}
+
+	err := json.Unmarshal(body, &op_type)
+	if err != nil {
+		return "", err
+	}
+	op, err := MakeOperator(op_type.Operator, body, nil)
+	if err != nil {
+		return "", err
+	}
+	return Format(op, format, nil)
+}
+
+type formatNode struct {
+	operator string
+	attrs    []string
+	children []*formatNode
+}
+
+func (this *formatNode) text(sb *strings.Builder, depth int) {
+	if depth > 0 {
+		sb.WriteString(strings.Repeat("   ", depth-1))
+		sb.WriteString("-> ")
+	}
+	sb.WriteString(this.operator)
+	if len(this.attrs) > 0 {
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(this.attrs, "; "))
+		sb.WriteString(")")
+	}
+	sb.WriteString("\n")
+	for _, c := range this.children {
+		c.text(sb, depth+1)
+	}
+}
+
+func (this *formatNode) dot(sb *strings.Builder, next *int) int {
+	id := *next
+	*next++
+
+	label := dotEscape(this.operator) + "\\n"
+	for _, a := range this.attrs {
+		label += dotEscape(a) + "\\l"
+	}
+	fmt.Fprintf(sb, "\tn%d [label=\"%s\"];\n", id, label)
+	for _, c := range this.children {
+		child := c.dot(sb, next)
+		fmt.Fprintf(sb, "\tn%d -> n%d;\n", id, child)
+	}
+	return id
+}
+
+func dotEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+type formatter struct {
+	annotate func(Operator) []string
+}
+
+func (this *formatter) visit(op Operator) (*formatNode, error) {
+	n, err := op.Accept(this)
+	if err != nil {
+		return nil, err
+	}
+	return n.(*formatNode), nil
+}
+
+/*
+A node with the given attributes, the optimizer estimates, and the
+given children.
+*/
+func (this *formatter) node(op Operator, name string, attrs []string, children ...Operator) (*formatNode, error) {
+	rv := &formatNode{operator: name}
+	for _, a := range attrs {
+		if a != "" {
+			rv.attrs = append(rv.attrs, a)
+		}
+	}
+	if cost := op.Cost(); cost > 0.0 {
+		rv.attrs = append(rv.attrs, "cost: "+formatFloat(cost))
+	}
+	if cardinality := op.Cardinality(); cardinality > 0.0 {
+		rv.attrs = append(rv.attrs, "cardinality: "+formatFloat(cardinality))
+	}
+	if this.annotate != nil {
+		rv.attrs = append(rv.attrs, this.annotate(op)...)
+	}
+	for _, c := range children {
+		if c == nil {
+			continue
+		}
+		child, err := this.visit(c)
+		if err != nil {
+			return nil, err
+		}
+		rv.children = append(rv.children, child)
+	}
+	return rv, nil
+}
+
+func (this *formatter) leaf(op Operator, name string, attrs ...string) (interface{}, error) {
+	return this.node(op, name, attrs)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', 6, 64)
+}
+
+func attr(name string, value string) string {
+	if value == "" {
+		return ""
+	}
+	return name + ": " + value
+}
+
+func exprAttr(name string, expr expression.Expression) string {
+	if expr == nil {
+		return ""
+	}
+	return attr(name, expr.String())
+}
+
+func exprsAttr(name string, exprs expression.Expressions) string {
+	if len(exprs) == 0 {
+		return ""
+	}
+	s := make([]string, len(exprs))
+	for i, e := range exprs {
+		s[i] = e.String()
+	}
+	return attr(name, strings.Join(s, ", "))
+}
+
+func aggregatesAttr(aggs algebra.Aggregates) string {
+	if len(aggs) == 0 {
+		return ""
+	}
+	s := make([]string, len(aggs))
+	for i, a := range aggs {
+		s[i] = a.String()
+	}
+	return attr("aggregates", strings.Join(s, ", "))
+}
+
+func termAttr(term *algebra.KeyspaceTerm) string {
+	if term == nil {
+		return ""
+	}
+	name := term.PathString()
+	if name == "" {
+		name = term.Keyspace()
+	}
+	if alias := term.Alias(); alias != "" && alias != term.Keyspace() {
+		name += " as " + alias
+	}
+	return attr("keyspace", name)
+}
+
+func refAttr(ref *algebra.KeyspaceRef) string {
+	if ref == nil {
+		return ""
+	}
+	name := ref.FullName()
+	if alias := ref.Alias(); alias != "" && alias != ref.Keyspace() {
+		name += " as " + alias
+	}
+	return attr("keyspace", name)
+}
+
+func indexAttr(index datastore.Index) string {
+	if index == nil {
+		return ""
+	}
+	return attr("index", index.Name())
+}
+
+func joinAttr(kind string, outer bool) string {
+	if outer {
+		return attr("join", "left outer "+kind)
+	}
+	return attr("join", "inner "+kind)
+}
+
+func coveringAttr(covering bool) string {
+	if covering {
+		return "covering"
+	}
+	return ""
+}
+
+func spansAttr(spans Spans2) string {
+	if len(spans) == 0 {
+		return ""
+	}
+	s := make([]string, 0, len(spans))
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		r := make([]string, 0, len(span.Ranges))
+		for _, rg := range span.Ranges {
+			if rg != nil {
+				r = append(r, formatRange(rg.Low, rg.High, rg.Inclusion))
+			}
+		}
+		s = append(s, strings.Join(r, " "))
+	}
+	return attr("spans", strings.Join(s, " OR "))
+}
+
+func legacySpansAttr(spans Spans) string {
+	if len(spans) == 0 {
+		return ""
+	}
+	s := make([]string, 0, len(spans))
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		var low, high expression.Expression
+		if len(span.Range.Low) > 0 {
+			low = expression.NewArrayConstruct(span.Range.Low...)
+		}
+		if len(span.Range.High) > 0 {
+			high = expression.NewArrayConstruct(span.Range.High...)
+		}
+		s = append(s, formatRange(low, high, span.Range.Inclusion))
+	}
+	return attr("spans", strings.Join(s, " OR "))
+}
+
+func formatRange(low, high expression.Expression, inclusion datastore.Inclusion) string {
+	var sb strings.Builder
+	if inclusion&datastore.LOW != 0 {
+		sb.WriteString("[")
+	} else {
+		sb.WriteString("(")
+	}
+	if low != nil {
+		sb.WriteString(low.String())
+	}
+	sb.WriteString(", ")
+	if high != nil {
+		sb.WriteString(high.String())
+	}
+	if inclusion&datastore.HIGH != 0 {
+		sb.WriteString("]")
+	} else {
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+func secondaryScans(scans []SecondaryScan) []Operator {
+	rv := make([]Operator, len(scans))
+	for i, s := range scans {
+		rv[i] = s
+	}
+	return rv
+}
+
+// Scan
+
+func (this *formatter) VisitPrimaryScan(op *PrimaryScan) (interface{}, error) {
+	return this.leaf(op, "PrimaryScan", indexAttr(op.Index()), termAttr(op.Term()), exprAttr("limit", op.Limit()))
+}
+
+func (this *formatter) VisitPrimaryScan3(op *PrimaryScan3) (interface{}, error) {
+	return this.leaf(op, "PrimaryScan3", indexAttr(op.Index()), termAttr(op.Term()), exprAttr("offset", op.Offset()),
+		exprAttr("limit", op.Limit()))
+}
+
+func (this *formatter) VisitIndexScan(op *IndexScan) (interface{}, error) {
+	return this.leaf(op, "IndexScan", indexAttr(op.Index()), termAttr(op.Term()), legacySpansAttr(op.Spans()),
+		coveringAttr(op.Covering()), exprAttr("limit", op.Limit()))
+}
+
+func (this *formatter) VisitIndexScan2(op *IndexScan2) (interface{}, error) {
+	return this.leaf(op, "IndexScan2", indexAttr(op.Index()), termAttr(op.Term()), spansAttr(op.Spans()),
+		coveringAttr(op.Covering()), exprAttr("offset", op.Offset()), exprAttr("limit", op.Limit()))
+}
+
+func (this *formatter) VisitIndexScan3(op *IndexScan3) (interface{}, error) {
+	return this.leaf(op, "IndexScan3", indexAttr(op.Index()), termAttr(op.Term()), spansAttr(op.Spans()),
+		coveringAttr(op.Covering()), exprAttr("filter", op.Filter()), exprAttr("offset", op.Offset()),
+		exprAttr("limit", op.Limit()))
+}
+
+func (this *formatter) VisitKeyScan(op *KeyScan) (interface{}, error) {
+	return this.leaf(op, "KeyScan", exprAttr("keys", op.Keys()))
+}
+
+func (this *formatter) VisitValueScan(op *ValueScan) (interface{}, error) {
+	return this.leaf(op, "ValueScan", attr("values", strconv.Itoa(len(op.Values()))))
+}
+
+func (this *formatter) VisitDummyScan(op *DummyScan) (interface{}, error) {
+	return this.leaf(op, "DummyScan")
+}
+
+func (this *formatter) VisitExternalScan(op *ExternalScan) (interface{}, error) {
+	return this.leaf(op, "ExternalScan", termAttr(op.Term()), exprAttr("filter", op.Filter()))
+}
+
+func (this *formatter) VisitCountScan(op *CountScan) (interface{}, error) {
+	return this.leaf(op, "CountScan", termAttr(op.Term()))
+}
+
+func (this *formatter) VisitIndexCountScan(op *IndexCountScan) (interface{}, error) {
+	return this.leaf(op, "IndexCountScan", indexAttr(op.Index()), termAttr(op.Term()), legacySpansAttr(op.Spans()))
+}
+
+func (this *formatter) VisitIndexCountScan2(op *IndexCountScan2) (interface{}, error) {
+	return this.leaf(op, "IndexCountScan2", indexAttr(op.Index()), termAttr(op.Term()), spansAttr(op.Spans()))
+}
+
+func (this *formatter) VisitIndexCountDistinctScan2(op *IndexCountDistinctScan2) (interface{}, error) {
+	return this.leaf(op, "IndexCountDistinctScan2", indexAttr(op.Index()), termAttr(op.Term()), spansAttr(op.Spans()))
+}
+
+func (this *formatter) VisitDistinctScan(op *DistinctScan) (interface{}, error) {
+	return this.node(op, "DistinctScan", []string{exprAttr("limit", op.Limit())}, op.Scan())
+}
+
+func (this *formatter) VisitUnionScan(op *UnionScan) (interface{}, error) {
+	return this.node(op, "UnionScan", []string{exprAttr("limit", op.Limit())}, secondaryScans(op.Scans())...)
+}
+
+func (this *formatter) VisitIntersectScan(op *IntersectScan) (interface{}, error) {
+	return this.node(op, "IntersectScan", []string{exprAttr("limit", op.Limit())}, secondaryScans(op.Scans())...)
+}
+
+func (this *formatter) VisitOrderedIntersectScan(op *OrderedIntersectScan) (interface{}, error) {
+	return this.node(op, "OrderedIntersectScan", []string{exprAttr("limit", op.Limit())}, secondaryScans(op.Scans())...)
+}
+
+func (this *formatter) VisitExpressionScan(op *ExpressionScan) (interface{}, error) {
+	return this.node(op, "ExpressionScan", []string{exprAttr("expr", op.FromExpr()), attr("as", op.Alias()),
+		exprAttr("filter", op.Filter())}, op.SubqueryPlan())
+}
+
+// FTS Search
+
+func (this *formatter) VisitIndexFtsSearch(op *IndexFtsSearch) (interface{}, error) {
+	return this.leaf(op, "IndexFtsSearch", indexAttr(op.Index()), termAttr(op.Term()), coveringAttr(op.Covering()))
+}
+
+// Fetch
+
+func (this *formatter) VisitFetch(op *Fetch) (interface{}, error) {
+	return this.leaf(op, "Fetch", termAttr(op.Term()))
+}
+
+func (this *formatter) VisitDummyFetch(op *DummyFetch) (interface{}, error) {
+	return this.leaf(op, "DummyFetch", termAttr(op.Term()))
+}
+
+// Join
+
+func (this *formatter) VisitJoin(op *Join) (interface{}, error) {
+	return this.leaf(op, "Join", joinAttr("lookup join", op.Outer()), termAttr(op.Term()), exprAttr("on", op.OnFilter()))
+}
+
+func (this *formatter) VisitIndexJoin(op *IndexJoin) (interface{}, error) {
+	return this.leaf(op, "IndexJoin", joinAttr("index join", op.Outer()), indexAttr(op.Index()), termAttr(op.Term()),
+		attr("for", op.For()))
+}
+
+func (this *formatter) VisitNest(op *Nest) (interface{}, error) {
+	return this.leaf(op, "Nest", joinAttr("lookup nest", op.Outer()), termAttr(op.Term()), exprAttr("on", op.OnFilter()))
+}
+
+func (this *formatter) VisitIndexNest(op *IndexNest) (interface{}, error) {
+	return this.leaf(op, "IndexNest", joinAttr("index nest", op.Outer()), indexAttr(op.Index()), termAttr(op.Term()),
+		attr("for", op.For()))
+}
+
+func (this *formatter) VisitUnnest(op *Unnest) (interface{}, error) {
+	var term string
+	if op.Term() != nil {
+		term = op.Term().String()
+	}
+	return this.leaf(op, "Unnest", attr("unnest", term), exprAttr("filter", op.Filter()))
+}
+
+func (this *formatter) VisitNLJoin(op *NLJoin) (interface{}, error) {
+	return this.node(op, "NestedLoopJoin", []string{joinAttr("nested loop join", op.Outer()), attr("as", op.Alias()),
+		exprAttr("on", op.Onclause())}, op.Child())
+}
+
+func (this *formatter) VisitNLNest(op *NLNest) (interface{}, error) {
+	return this.node(op, "NestedLoopNest", []string{joinAttr("nested loop nest", op.Outer()), attr("as", op.Alias()),
+		exprAttr("on", op.Onclause())}, op.Child())
+}
+
+func (this *formatter) VisitHashJoin(op *HashJoin) (interface{}, error) {
+	return this.node(op, "HashJoin", []string{joinAttr("hash join", op.Outer()), exprsAttr("build", op.BuildExprs()),
+		exprsAttr("probe", op.ProbeExprs()), exprAttr("on", op.Onclause())}, op.Child())
+}
+
+func (this *formatter) VisitAsofJoin(op *AsofJoin) (interface{}, error) {
+	return this.node(op, "AsofJoin", []string{joinAttr("asof join", op.Outer()), exprsAttr("build", op.BuildExprs()),
+		exprsAttr("probe", op.ProbeExprs()), exprAttr("on", op.Onclause())}, op.Child())
+}
+
+func (this *formatter) VisitHashNest(op *HashNest) (interface{}, error) {
+	return this.node(op, "HashNest", []string{joinAttr("hash nest", op.Outer()), exprsAttr("build", op.BuildExprs()),
+		exprsAttr("probe", op.ProbeExprs()), exprAttr("on", op.Onclause())}, op.Child())
+}
+
+// Let + Letting, With
+
+func (this *formatter) VisitLet(op *Let) (interface{}, error) {
+	return this.leaf(op, "Let", attr("bindings", op.Bindings().String()))
+}
+
+func (this *formatter) VisitWith(op *With) (interface{}, error) {
+	return this.node(op, "With", nil, op.Child())
+}
+
+// Filter
+
+func (this *formatter) VisitFilter(op *Filter) (interface{}, error) {
+	return this.leaf(op, "Filter", exprAttr("condition", op.Condition()))
+}
+
+// Group
+
+func (this *formatter) VisitInitialGroup(op *InitialGroup) (interface{}, error) {
+	return this.leaf(op, "InitialGroup", exprsAttr("keys", op.Keys()), aggregatesAttr(op.Aggregates()))
+}
+
+func (this *formatter) VisitIntermediateGroup(op *IntermediateGroup) (interface{}, error) {
+	return this.leaf(op, "IntermediateGroup", exprsAttr("keys", op.Keys()), aggregatesAttr(op.Aggregates()))
+}
+
+func (this *formatter) VisitFinalGroup(op *FinalGroup) (interface{}, error) {
+	return this.leaf(op, "FinalGroup", exprsAttr("keys", op.Keys()), aggregatesAttr(op.Aggregates()))
+}
+
+func (this *formatter) VisitGapFill(op *GapFill) (interface{}, error) {
+	return this.leaf(op, "GapFill", exprsAttr("keys", op.Keys()), exprsAttr("fills", op.Fills()))
+}
+
+// Window functions
+
+func (this *formatter) VisitWindowAggregate(op *WindowAggregate) (interface{}, error) {
+	return this.leaf(op, "WindowAggregate", aggregatesAttr(op.Aggregates()))
+}
+
+// Project
+
+func (this *formatter) VisitInitialProject(op *InitialProject) (interface{}, error) {
+	var projection string
+	if op.Projection() != nil {
+		projection = op.Projection().String()
+	}
+	return this.leaf(op, "InitialProject", attr("projection", projection))
+}
+
+func (this *formatter) VisitIndexCountProject(op *IndexCountProject) (interface{}, error) {
+	var projection string
+	if op.Projection() != nil {
+		projection = op.Projection().String()
+	}
+	return this.leaf(op, "IndexCountProject", attr("projection", projection))
+}
+
+// Distinct
+
+func (this *formatter) VisitDistinct(op *Distinct) (interface{}, error) {
+	return this.leaf(op, "Distinct")
+}
+
+// All
+
+func (this *formatter) VisitAll(op *All) (interface{}, error) {
+	return this.leaf(op, "All")
+}
+
+// Set operators
+
+func (this *formatter) VisitUnionAll(op *UnionAll) (interface{}, error) {
+	return this.node(op, "UnionAll", nil, op.Children()...)
+}
+
+func (this *formatter) VisitIntersectAll(op *IntersectAll) (interface{}, error) {
+	return this.node(op, "IntersectAll", nil, op.First(), op.Second())
+}
+
+func (this *formatter) VisitExceptAll(op *ExceptAll) (interface{}, error) {
+	return this.node(op, "ExceptAll", nil, op.First(), op.Second())
+}
+
+// Order
+
+func (this *formatter) VisitOrder(op *Order) (interface{}, error) {
+	return this.leaf(op, "Order", attr("by", op.Terms().String()))
+}
+
+// Paging
+
+func (this *formatter) VisitOffset(op *Offset) (interface{}, error) {
+	return this.leaf(op, "Offset", exprAttr("expr", op.Expression()))
+}
+
+func (this *formatter) VisitLimit(op *Limit) (interface{}, error) {
+	return this.leaf(op, "Limit", exprAttr("expr", op.Expression()))
+}
+
+// Insert
+
+func (this *formatter) VisitSendInsert(op *SendInsert) (interface{}, error) {
+	return this.leaf(op, "SendInsert", refAttr(op.Term()))
+}
+
+// Upsert
+
+func (this *formatter) VisitSendUpsert(op *SendUpsert) (interface{}, error) {
+	return this.leaf(op, "SendUpsert", refAttr(op.Term()))
+}
+
+// Delete
+
+func (this *formatter) VisitSendDelete(op *SendDelete) (interface{}, error) {
+	return this.leaf(op, "SendDelete", refAttr(op.Term()), exprAttr("limit", op.Limit()))
+}
+
+// Update
+
+func (this *formatter) VisitClone(op *Clone) (interface{}, error) {
+	return this.leaf(op, "Clone")
+}
+
+func (this *formatter) VisitSet(op *Set) (interface{}, error) {
+	return this.leaf(op, "Set")
+}
+
+func (this *formatter) VisitUnset(op *Unset) (interface{}, error) {
+	return this.leaf(op, "Unset")
+}
+
+func (this *formatter) VisitSendUpdate(op *SendUpdate) (interface{}, error) {
+	return this.leaf(op, "SendUpdate", refAttr(op.Term()), exprAttr("limit", op.Limit()))
+}
+
+// Merge
+
+func (this *formatter) VisitMerge(op *Merge) (interface{}, error) {
+	return this.node(op, "Merge", []string{refAttr(op.KeyspaceRef()), exprAttr("key", op.Key())},
+		op.Update(), op.Delete(), op.Insert())
+}
+
+// Framework
+
+func (this *formatter) VisitAlias(op *Alias) (interface{}, error) {
+	return this.leaf(op, "Alias", attr("as", op.Alias()))
+}
+
+func (this *formatter) VisitAuthorize(op *Authorize) (interface{}, error) {
+	return this.node(op, "Authorize", nil, op.Child())
+}
+
+func (this *formatter) VisitParallel(op *Parallel) (interface{}, error) {
+	return this.node(op, "Parallel", nil, op.Child())
+}
+
+func (this *formatter) VisitSequence(op *Sequence) (interface{}, error) {
+	return this.node(op, "Sequence", nil, op.Children()...)
+}
+
+func (this *formatter) VisitDiscard(op *Discard) (interface{}, error) {
+	return this.leaf(op, "Discard")
+}
+
+func (this *formatter) VisitStream(op *Stream) (interface{}, error) {
+	return this.leaf(op, "Stream")
+}
+
+func (this *formatter) VisitCollect(op *Collect) (interface{}, error) {
+	return this.leaf(op, "Collect")
+}
+
+func (this *formatter) VisitReceive(op *Receive) (interface{}, error) {
+	return this.leaf(op, "Receive")
+}
+
+// Index DDL
+
+func (this *formatter) VisitCreatePrimaryIndex(op *CreatePrimaryIndex) (interface{}, error) {
+	return this.leaf(op, "CreatePrimaryIndex")
+}
+
+func (this *formatter) VisitCreateIndex(op *CreateIndex) (interface{}, error) {
+	return this.leaf(op, "CreateIndex")
+}
+
+func (this *formatter) VisitDropIndex(op *DropIndex) (interface{}, error) {
+	return this.leaf(op, "DropIndex")
+}
+
+func (this *formatter) VisitAlterIndex(op *AlterIndex) (interface{}, error) {
+	return this.leaf(op, "AlterIndex")
+}
+
+func (this *formatter) VisitBuildIndexes(op *BuildIndexes) (interface{}, error) {
+	return this.leaf(op, "BuildIndexes")
+}
+
+// Buckets DDL
+
+func (this *formatter) VisitCreateBucket(op *CreateBucket) (interface{}, error) {
+	return this.leaf(op, "CreateBucket")
+}
+
+func (this *formatter) VisitAlterBucket(op *AlterBucket) (interface{}, error) {
+	return this.leaf(op, "AlterBucket")
+}
+
+func (this *formatter) VisitDropBucket(op *DropBucket) (interface{}, error) {
+	return this.leaf(op, "DropBucket")
+}
+
+// Catalog DDL
+
+func (this *formatter) VisitCreateCatalog(op *CreateCatalog) (any, error) {
+	return this.leaf(op, "CreateCatalog")
+}
+
+func (this *formatter) VisitAlterCatalog(op *AlterCatalog) (any, error) {
+	return this.leaf(op, "AlterCatalog")
+}
+
+func (this *formatter) VisitDropCatalog(op *DropCatalog) (any, error) {
+	return this.leaf(op, "DropCatalog")
+}
+
+// Scope and Collection  DDL
+
+func (this *formatter) VisitCreateScope(op *CreateScope) (interface{}, error) {
+	return this.leaf(op, "CreateScope")
+}
+
+func (this *formatter) VisitDropScope(op *DropScope) (interface{}, error) {
+	return this.leaf(op, "DropScope")
+}
+
+func (this *formatter) VisitCreateCollection(op *CreateCollection) (interface{}, error) {
+	return this.leaf(op, "CreateCollection")
+}
+
+func (this *formatter) VisitDropCollection(op *DropCollection) (interface{}, error) {
+	return this.leaf(op, "DropCollection")
+}
+
+func (this *formatter) VisitFlushCollection(op *FlushCollection) (interface{}, error) {
+	return this.leaf(op, "FlushCollection")
+}
+
+func (this *formatter) VisitTruncate(op *Truncate) (interface{}, error) {
+	return this.leaf(op, "Truncate")
+}
+
+func (this *formatter) VisitAlterCollection(op *AlterCollection) (any, error) {
+	return this.leaf(op, "AlterCollection")
+}
+
+// Users
+
+func (this *formatter) VisitCreateUser(op *CreateUser) (interface{}, error) {
+	return this.leaf(op, "CreateUser")
+}
+
+func (this *formatter) VisitAlterUser(op *AlterUser) (interface{}, error) {
+	return this.leaf(op, "AlterUser")
+}
+
+func (this *formatter) VisitDropUser(op *DropUser) (interface{}, error) {
+	return this.leaf(op, "DropUser")
+}
+
+// Groups
+
+func (this *formatter) VisitCreateGroup(op *CreateGroup) (interface{}, error) {
+	return this.leaf(op, "CreateGroup")
+}
+
+func (this *formatter) VisitAlterGroup(op *AlterGroup) (interface{}, error) {
+	return this.leaf(op, "AlterGroup")
+}
+
+func (this *formatter) VisitDropGroup(op *DropGroup) (interface{}, error) {
+	return this.leaf(op, "DropGroup")
+}
+
+// Roles
+
+func (this *formatter) VisitGrantRole(op *GrantRole) (interface{}, error) {
+	return this.leaf(op, "GrantRole")
+}
+
+func (this *formatter) VisitRevokeRole(op *RevokeRole) (interface{}, error) {
+	return this.leaf(op, "RevokeRole")
+}
+
+// Explain
+
+func (this *formatter) VisitExplain(op *Explain) (interface{}, error) {
+	return this.node(op, "Explain", nil, op.Plan())
+}
+
+// Explain Function
+
+func (this *formatter) VisitExplainFunction(op *ExplainFunction) (interface{}, error) {
+	return this.leaf(op, "ExplainFunction")
+}
+
+// Prepare
+
+func (this *formatter) VisitPrepare(op *Prepare) (interface{}, error) {
+	var prepared Operator
+	if op.Plan() != nil {
+		prepared = op.Plan().Operator
+	}
+	return this.node(op, "Prepare", nil, prepared)
+}
+
+// Infer
+
+func (this *formatter) VisitInferKeyspace(op *InferKeyspace) (interface{}, error) {
+	return this.leaf(op, "InferKeyspace")
+}
+
+func (this *formatter) VisitInferExpression(op *InferExpression) (interface{}, error) {
+	return this.leaf(op, "InferExpression")
+}
+
+// Function statements
+
+func (this *formatter) VisitCreateFunction(op *CreateFunction) (interface{}, error) {
+	return this.leaf(op, "CreateFunction")
+}
+
+func (this *formatter) VisitDropFunction(op *DropFunction) (interface{}, error) {
+	return this.leaf(op, "DropFunction")
+}
+
+func (this *formatter) VisitExecuteFunction(op *ExecuteFunction) (interface{}, error) {
+	return this.leaf(op, "ExecuteFunction")
+}
+
+// Index Advisor
+
+func (this *formatter) VisitIndexAdvice(op *IndexAdvice) (interface{}, error) {
+	return this.leaf(op, "IndexAdvice")
+}
+
+func (this *formatter) VisitAdvise(op *Advise) (interface{}, error) {
+	return this.leaf(op, "Advise")
+}
+
+// Update Statistics
+
+func (this *formatter) VisitUpdateStatistics(op *UpdateStatistics) (interface{}, error) {
+	return this.leaf(op, "UpdateStatistics")
+}
+
+// Transactions
+
+func (this *formatter) VisitStartTransaction(op *StartTransaction) (interface{}, error) {
+	return this.leaf(op, "StartTransaction")
+}
+
+func (this *formatter) VisitCommitTransaction(op *CommitTransaction) (interface{}, error) {
+	return this.leaf(op, "CommitTransaction")
+}
+
+func (this *formatter) VisitRollbackTransaction(op *RollbackTransaction) (interface{}, error) {
+	return this.leaf(op, "RollbackTransaction")
+}
+
+func (this *formatter) VisitTransactionIsolation(op *TransactionIsolation) (interface{}, error) {
+	return this.leaf(op, "TransactionIsolation")
+}
+
+func (this *formatter) VisitSavepoint(op *Savepoint) (interface{}, error) {
+	return this.leaf(op, "Savepoint")
+}
+
+// Sequences
+
+func (this *formatter) VisitCreateSequence(op *CreateSequence) (interface{}, error) {
+	return this.leaf(op, "CreateSequence")
+}
+
+func (this *formatter) VisitDropSequence(op *DropSequence) (interface{}, error) {
+	return this.leaf(op, "DropSequence")
+}
+
+func (this *formatter) VisitAlterSequence(op *AlterSequence) (interface{}, error) {
+	return this.leaf(op, "AlterSequence")
+}
+
+// Views
+
+func (this *formatter) VisitCreateView(op *CreateView) (interface{}, error) {
+	return this.leaf(op, "CreateView")
+}
+
+func (this *formatter) VisitDropView(op *DropView) (interface{}, error) {
+	return this.leaf(op, "DropView")
+}
+
+func (this *formatter) VisitRefreshView(op *RefreshView) (interface{}, error) {
+	return this.leaf(op, "RefreshView")
+}
+
+// Triggers
+
+func (this *formatter) VisitCreateTrigger(op *CreateTrigger) (interface{}, error) {
+	return this.leaf(op, "CreateTrigger")
+}
+
+func (this *formatter) VisitDropTrigger(op *DropTrigger) (interface{}, error) {
+	return this.leaf(op, "DropTrigger")
+}
+
+// Jobs
+
+func (this *formatter) VisitCreateJob(op *CreateJob) (interface{}, error) {
+	return this.leaf(op, "CreateJob")
+}
+
+func (this *formatter) VisitAlterJob(op *AlterJob) (interface{}, error) {
+	return this.leaf(op, "AlterJob")
+}
+
+func (this *formatter) VisitDropJob(op *DropJob) (interface{}, error) {
+	return this.leaf(op, "DropJob")
+}
+
+// Policies
+
+func (this *formatter) VisitCreatePolicy(op *CreatePolicy) (interface{}, error) {
+	return this.leaf(op, "CreatePolicy")
+}
+
+func (this *formatter) VisitDropPolicy(op *DropPolicy) (interface{}, error) {
+	return this.leaf(op, "DropPolicy")
+}
+
+// CredentialStore
+
+func (this *formatter) VisitCreateCredentialStore(op *CreateCredentialStore) (any, error) {
+	return this.leaf(op, "CreateCredentialStore")
+}
+
+func (this *formatter) VisitAlterCredentialStore(op *AlterCredentialStore) (any, error) {
+	return this.leaf(op, "AlterCredentialStore")
+}
+
+func (this *formatter) VisitDropCredentialStore(op *DropCredentialStore) (any, error) {
+	return this.leaf(op, "DropCredentialStore")
+}
@@ -0,0 +1,175 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/expression"
+)
+
+/*
+Adds the groups missing from GROUP BY ... GAPFILL: one per empty
+TIME_BUCKET() bucket of each series, a series being the groups with the
+same other keys. Follows the final grouping; not parallelizable.
+*/
+type GapFill struct {
+	readonly
+	optEstimate
+	keys       expression.Expressions
+	bucket     int                    // position of the TIME_BUCKET() key
+	fills      expression.Expressions // LOCF() and INTERPOLATE() calls
+	aggregates algebra.Aggregates
+	start      expression.Expression
+	end        expression.Expression
+}
+
+func NewGapFill(keys expression.Expressions, bucket int, fills expression.Expressions, aggregates algebra.Aggregates,
+	start, end expression.Expression, cost, cardinality float64, size int64, frCost float64) *GapFill {
+	rv := &GapFill{
+		keys:       keys,
+		bucket:     bucket,
+		fills:      fills,
+		aggregates: aggregates,
+		start:      start,
+		end:        end,
+	}
+	setOptEstimate(&rv.optEstimate, cost, cardinality, size, frCost)
+	return rv
+}
+
+func (this *GapFill) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitGapFill(this)
+}
+
+func (this *GapFill) New() Operator {
+	return &GapFill{}
+}
+
+func (this *GapFill) Keys() expression.Expressions {
+	return this.keys
+}
+
+func (this *GapFill) Bucket() int {
+	return this.bucket
+}
+
+func (this *GapFill) Fills() expression.Expressions {
+	return this.fills
+}
+
+func (this *GapFill) Aggregates() algebra.Aggregates {
+	return this.aggregates
+}
+
+func (this *GapFill) Start() expression.Expression {
+	return this.start
+}
+
+func (this *GapFill) End() expression.Expression {
+	return this.end
+}
+
+func (this *GapFill) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *GapFill) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "GapFill"}
+	keylist := make([]string, 0, len(this.keys))
+	for _, key := range this.keys {
+		keylist = append(keylist, key.String())
+	}
+	r["group_keys"] = keylist
+	r["bucket"] = this.bucket
+	if len(this.fills) > 0 {
+		fills := make([]string, 0, len(this.fills))
+		for _, fill := range this.fills {
+			fills = append(fills, fill.String())
+		}
+		r["fills"] = fills
+	}
+	s := make([]interface{}, 0, len(this.aggregates))
+	for _, agg := range this.aggregates {
+		s = append(s, agg.String())
+	}
+	r["aggregates"] = s
+	if this.start != nil {
+		r["start"] = this.start.String()
+		r["end"] = this.end.String()
+	}
+	if optEstimate := marshalOptEstimate(&this.optEstimate); optEstimate != nil {
+		r["optimizer_estimates"] = optEstimate
+	}
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *GapFill) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_           string                 `json:"#operator"`
+		Keys        []string               `json:"group_keys"`
+		Bucket      int                    `json:"bucket"`
+		Fills       []string               `json:"fills"`
+		Aggs        []string               `json:"aggregates"`
+		Start       string                 `json:"start"`
+		End         string                 `json:"end"`
+		OptEstimate map[string]interface{} `json:"optimizer_estimates"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	this.keys = make(expression.Expressions, len(_unmarshalled.Keys))
+	for i, key := range _unmarshalled.Keys {
+		this.keys[i], err = this.parseExpression(key)
+		if err != nil {
+			return err
+		}
+	}
+	this.bucket = _unmarshalled.Bucket
+
+	if len(_unmarshalled.Fills) > 0 {
+		this.fills = make(expression.Expressions, len(_unmarshalled.Fills))
+		for i, fill := range _unmarshalled.Fills {
+			this.fills[i], err = this.parseExpression(fill)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	this.aggregates = make(algebra.Aggregates, len(_unmarshalled.Aggs))
+	for i, agg := range _unmarshalled.Aggs {
+		agg_expr, err := this.parseExpression(agg)
+		if err != nil {
+			return err
+		}
+		this.aggregates[i], _ = agg_expr.(algebra.Aggregate)
+	}
+
+	if _unmarshalled.Start != "" {
+		this.start, err = this.parseExpression(_unmarshalled.Start)
+		if err != nil {
+			return err
+		}
+		this.end, err = this.parseExpression(_unmarshalled.End)
+		if err != nil {
+			return err
+		}
+	}
+
+	unmarshalOptEstimate(&this.optEstimate, _unmarshalled.OptEstimate)
+	return nil
+}
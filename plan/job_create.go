@@ -0,0 +1,90 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/value"
+)
+
+// Create job
+type CreateJob struct {
+	ddl
+	node *algebra.CreateJob
+}
+
+func NewCreateJob(node *algebra.CreateJob) *CreateJob {
+	return &CreateJob{
+		node: node,
+	}
+}
+
+func (this *CreateJob) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateJob(this)
+}
+
+func (this *CreateJob) New() Operator {
+	return &CreateJob{}
+}
+
+func (this *CreateJob) Node() *algebra.CreateJob {
+	return this.node
+}
+
+func (this *CreateJob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *CreateJob) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "CreateJob"}
+	r["name"] = this.node.Name()
+	r["schedule"] = this.node.Schedule()
+	if this.node.With() != nil {
+		r["with"] = this.node.With()
+	}
+	r["text"] = this.node.Text()
+	if this.node.QueryContext() != "" {
+		r["query_context"] = this.node.QueryContext()
+	}
+	if this.node.Replace() {
+		r["replace"] = true
+	}
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *CreateJob) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_            string          `json:"#operator"`
+		Name         string          `json:"name"`
+		Schedule     string          `json:"schedule"`
+		With         json.RawMessage `json:"with"`
+		Text         string          `json:"text"`
+		QueryContext string          `json:"query_context"`
+		Replace      bool            `json:"replace"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	var with value.Value
+	if len(_unmarshalled.With) > 0 {
+		with = value.NewValue([]byte(_unmarshalled.With))
+	}
+
+	this.node = algebra.NewCreateJob(_unmarshalled.Name, _unmarshalled.Schedule, with, nil, _unmarshalled.Text,
+		_unmarshalled.QueryContext, _unmarshalled.Replace)
+	return nil
+}
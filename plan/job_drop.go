@@ -0,0 +1,69 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+)
+
+// Drop job
+type DropJob struct {
+	ddl
+	node *algebra.DropJob
+}
+
+func NewDropJob(node *algebra.DropJob) *DropJob {
+	return &DropJob{
+		node: node,
+	}
+}
+
+func (this *DropJob) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropJob(this)
+}
+
+func (this *DropJob) New() Operator {
+	return &DropJob{}
+}
+
+func (this *DropJob) Node() *algebra.DropJob {
+	return this.node
+}
+
+func (this *DropJob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *DropJob) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "DropJob"}
+	r["name"] = this.node.Name()
+	r["failIfNotExists"] = this.node.FailIfNotExists()
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *DropJob) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_               string `json:"#operator"`
+		Name            string `json:"name"`
+		FailIfNotExists bool   `json:"failIfNotExists"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	this.node = algebra.NewDropJob(_unmarshalled.Name, _unmarshalled.FailIfNotExists)
+	return nil
+}
@@ -0,0 +1,277 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/expression/parser"
+)
+
+/*
+ASOF JOIN. The child is the right-hand side, read whole and ordered on
+buildTime for each value of buildExprs; objects of the left-hand side
+are then matched to the nearest object on probeTime, before it
+(probeTime >= buildTime), or after it if forward is set.
+*/
+type AsofJoin struct {
+	readonly
+	optEstimate
+	outer        bool
+	onclause     expression.Expression
+	child        Operator
+	buildExprs   expression.Expressions
+	probeExprs   expression.Expressions
+	buildTime    expression.Expression
+	probeTime    expression.Expression
+	forward      bool
+	strict       bool
+	buildAliases []string
+	filter       expression.Expression
+}
+
+func NewAsofJoin(join *algebra.AsofJoin, child Operator, buildExprs, probeExprs expression.Expressions,
+	buildTime, probeTime expression.Expression, forward, strict bool, buildAliases []string,
+	onclause, filter expression.Expression, cost, cardinality float64, size int64, frCost float64) *AsofJoin {
+	rv := &AsofJoin{
+		outer:        join.Outer(),
+		onclause:     onclause,
+		child:        child,
+		buildExprs:   buildExprs,
+		probeExprs:   probeExprs,
+		buildTime:    buildTime,
+		probeTime:    probeTime,
+		forward:      forward,
+		strict:       strict,
+		buildAliases: buildAliases,
+		filter:       filter,
+	}
+	setOptEstimate(&rv.optEstimate, cost, cardinality, size, frCost)
+	return rv
+}
+
+func (this *AsofJoin) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitAsofJoin(this)
+}
+
+func (this *AsofJoin) New() Operator {
+	return &AsofJoin{}
+}
+
+func (this *AsofJoin) Outer() bool {
+	return this.outer
+}
+
+func (this *AsofJoin) Onclause() expression.Expression {
+	return this.onclause
+}
+
+func (this *AsofJoin) Child() Operator {
+	return this.child
+}
+
+func (this *AsofJoin) BuildExprs() expression.Expressions {
+	return this.buildExprs
+}
+
+func (this *AsofJoin) ProbeExprs() expression.Expressions {
+	return this.probeExprs
+}
+
+func (this *AsofJoin) BuildTime() expression.Expression {
+	return this.buildTime
+}
+
+func (this *AsofJoin) ProbeTime() expression.Expression {
+	return this.probeTime
+}
+
+func (this *AsofJoin) Forward() bool {
+	return this.forward
+}
+
+func (this *AsofJoin) Strict() bool {
+	return this.strict
+}
+
+func (this *AsofJoin) BuildAliases() []string {
+	return this.buildAliases
+}
+
+func (this *AsofJoin) Filter() expression.Expression {
+	return this.filter
+}
+
+func (this *AsofJoin) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *AsofJoin) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "AsofJoin"}
+	r["on_clause"] = this.onclause.String()
+
+	if this.outer {
+		r["outer"] = this.outer
+	}
+
+	buildList := make([]string, 0, len(this.buildExprs))
+	for _, build := range this.buildExprs {
+		buildList = append(buildList, build.String())
+	}
+	r["build_exprs"] = buildList
+
+	probeList := make([]string, 0, len(this.probeExprs))
+	for _, probe := range this.probeExprs {
+		probeList = append(probeList, probe.String())
+	}
+	r["probe_exprs"] = probeList
+
+	r["build_time"] = this.buildTime.String()
+	r["probe_time"] = this.probeTime.String()
+	if this.forward {
+		r["forward"] = this.forward
+	}
+	if this.strict {
+		r["strict"] = this.strict
+	}
+
+	r["build_aliases"] = this.buildAliases
+
+	if this.filter != nil {
+		r["filter"] = this.filter.String()
+	}
+
+	if optEstimate := marshalOptEstimate(&this.optEstimate); optEstimate != nil {
+		r["optimizer_estimates"] = optEstimate
+	}
+
+	if f != nil {
+		f(r)
+	} else {
+		r["~child"] = this.child
+	}
+	return r
+}
+
+func (this *AsofJoin) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_            string                 `json:"#operator"`
+		Onclause     string                 `json:"on_clause"`
+		Outer        bool                   `json:"outer"`
+		BuildExprs   []string               `json:"build_exprs"`
+		ProbeExprs   []string               `json:"probe_exprs"`
+		BuildTime    string                 `json:"build_time"`
+		ProbeTime    string                 `json:"probe_time"`
+		Forward      bool                   `json:"forward"`
+		Strict       bool                   `json:"strict"`
+		BuildAliases []string               `json:"build_aliases"`
+		Filter       string                 `json:"filter"`
+		OptEstimate  map[string]interface{} `json:"optimizer_estimates"`
+		Child        json.RawMessage        `json:"~child"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	this.onclause, err = this.parseExpression(_unmarshalled.Onclause)
+	if err != nil {
+		return err
+	}
+
+	this.outer = _unmarshalled.Outer
+	this.forward = _unmarshalled.Forward
+	this.strict = _unmarshalled.Strict
+
+	this.buildExprs = make(expression.Expressions, len(_unmarshalled.BuildExprs))
+	for i, build := range _unmarshalled.BuildExprs {
+		this.buildExprs[i], err = parser.Parse(build)
+		if err != nil {
+			return err
+		}
+	}
+
+	this.probeExprs = make(expression.Expressions, len(_unmarshalled.ProbeExprs))
+	for i, probe := range _unmarshalled.ProbeExprs {
+		this.probeExprs[i], err = parser.Parse(probe)
+		if err != nil {
+			return err
+		}
+	}
+
+	this.buildTime, err = parser.Parse(_unmarshalled.BuildTime)
+	if err != nil {
+		return err
+	}
+	this.probeTime, err = parser.Parse(_unmarshalled.ProbeTime)
+	if err != nil {
+		return err
+	}
+
+	this.buildAliases = _unmarshalled.BuildAliases
+
+	if _unmarshalled.Filter != "" {
+		this.filter, err = parser.Parse(_unmarshalled.Filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	unmarshalOptEstimate(&this.optEstimate, _unmarshalled.OptEstimate)
+
+	raw_child := _unmarshalled.Child
+	var child_type struct {
+		Op_name string `json:"#operator"`
+	}
+
+	err = json.Unmarshal(raw_child, &child_type)
+	if err != nil {
+		return err
+	}
+
+	planContext := this.PlanContext()
+
+	this.child, err = MakeOperator(child_type.Op_name, raw_child, planContext)
+	if err != nil {
+		return err
+	}
+
+	if planContext != nil {
+		for _, expr := range []expression.Expression{this.onclause, this.filter, this.buildTime, this.probeTime} {
+			if expr != nil {
+				_, err = planContext.Map(expr)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err = this.buildExprs.MapExpressions(planContext)
+		if err != nil {
+			return err
+		}
+		err = this.probeExprs.MapExpressions(planContext)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (this *AsofJoin) verify(prepared *Prepared) errors.Error {
+	return this.child.verify(prepared)
+}
+
+func (this *AsofJoin) keyspaceReferences(prepared *Prepared) {
+	this.child.keyspaceReferences(prepared)
+}
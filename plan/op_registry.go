@@ -73,6 +73,7 @@ var _OPERATORS = map[string]Operator{
 	"IndexJoin":      &IndexJoin{},
 	"NestedLoopJoin": &NLJoin{},
 	"HashJoin":       &HashJoin{},
+	"AsofJoin":       &AsofJoin{},
 	"Nest":           &Nest{},
 	"IndexNest":      &IndexNest{},
 	"NestedLoopNest": &NLNest{},
@@ -99,6 +100,7 @@ var _OPERATORS = map[string]Operator{
 	"InitialGroup":      &InitialGroup{},
 	"IntermediateGroup": &IntermediateGroup{},
 	"FinalGroup":        &FinalGroup{},
+	"GapFill":           &GapFill{},
 
 	// Window functions
 	"WindowAggregate": &WindowAggregate{},
@@ -183,6 +185,7 @@ var _OPERATORS = map[string]Operator{
 	"DropCollection":   &DropCollection{},
 	"FlushCollection":  &FlushCollection{},
 	"AlterCollection":  &AlterCollection{},
+	"Truncate":         &Truncate{},
 
 	// Functions
 	"CreateFunction":  &CreateFunction{},
@@ -205,6 +208,24 @@ var _OPERATORS = map[string]Operator{
 	"AlterSequence":  &AlterSequence{},
 	"DropSequence":   &DropSequence{},
 
+	// Views
+	"CreateView":  &CreateView{},
+	"DropView":    &DropView{},
+	"RefreshView": &RefreshView{},
+
+	// Triggers
+	"CreateTrigger": &CreateTrigger{},
+	"DropTrigger":   &DropTrigger{},
+
+	// Jobs
+	"CreateJob": &CreateJob{},
+	"AlterJob":  &AlterJob{},
+	"DropJob":   &DropJob{},
+
+	// Policies
+	"CreatePolicy": &CreatePolicy{},
+	"DropPolicy":   &DropPolicy{},
+
 	// Users
 	"CreateUser": &CreateUser{},
 	"AlterUser":  &AlterUser{},
@@ -0,0 +1,132 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression/parser"
+)
+
+// Create policy
+type CreatePolicy struct {
+	ddl
+	keyspace datastore.Keyspace
+	node     *algebra.CreatePolicy
+}
+
+func NewCreatePolicy(keyspace datastore.Keyspace, node *algebra.CreatePolicy) *CreatePolicy {
+	return &CreatePolicy{
+		keyspace: keyspace,
+		node:     node,
+	}
+}
+
+func (this *CreatePolicy) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreatePolicy(this)
+}
+
+func (this *CreatePolicy) New() Operator {
+	return &CreatePolicy{}
+}
+
+func (this *CreatePolicy) Keyspace() datastore.Keyspace {
+	return this.keyspace
+}
+
+func (this *CreatePolicy) Node() *algebra.CreatePolicy {
+	return this.node
+}
+
+func (this *CreatePolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *CreatePolicy) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "CreatePolicy"}
+	this.node.Keyspace().MarshalKeyspace(r)
+	r["name"] = this.node.Name()
+	r["event"] = this.node.Event()
+	if this.node.Masking() {
+		r["column"] = this.node.Column().String()
+	}
+	if len(this.node.Roles()) > 0 {
+		r["roles"] = this.node.Roles()
+	}
+	r["predicate"] = this.node.Predicate().String()
+	if this.node.Replace() {
+		r["replace"] = true
+	}
+
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *CreatePolicy) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_         string   `json:"#operator"`
+		Namespace string   `json:"namespace"`
+		Bucket    string   `json:"bucket"`
+		Scope     string   `json:"scope"`
+		Keyspace  string   `json:"keyspace"`
+		Name      string   `json:"name"`
+		Event     string   `json:"event"`
+		Column    string   `json:"column"`
+		Roles     []string `json:"roles"`
+		Predicate string   `json:"predicate"`
+		Replace   bool     `json:"replace"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	ksref := algebra.NewKeyspaceRefFromPath(algebra.NewPathShortOrLong(_unmarshalled.Namespace, _unmarshalled.Bucket,
+		_unmarshalled.Scope, _unmarshalled.Keyspace), "")
+	this.keyspace, err = datastore.GetKeyspace(ksref.Path().Parts()...)
+	if err != nil {
+		return err
+	}
+
+	predicate, err := parser.Parse(_unmarshalled.Predicate)
+	if err != nil {
+		return err
+	}
+
+	if _unmarshalled.Column != "" {
+		column, err := parser.Parse(_unmarshalled.Column)
+		if err != nil {
+			return err
+		}
+		this.node = algebra.NewCreateMaskingPolicy(_unmarshalled.Name, ksref, column, predicate,
+			_unmarshalled.Roles, _unmarshalled.Replace)
+		return nil
+	}
+
+	this.node = algebra.NewCreatePolicy(_unmarshalled.Name, ksref, _unmarshalled.Event, _unmarshalled.Roles,
+		predicate, _unmarshalled.Replace)
+	return nil
+}
+
+func (this *CreatePolicy) verify(prepared *Prepared) errors.Error {
+	var err errors.Error
+
+	this.keyspace, err = verifyKeyspace(this.keyspace, prepared)
+	return err
+}
+
+func (this *CreatePolicy) keyspaceReferences(prepared *Prepared) {
+	prepared.addKeyspaceReference(this.keyspace)
+}
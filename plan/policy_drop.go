@@ -0,0 +1,108 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+)
+
+// Drop policy
+type DropPolicy struct {
+	ddl
+	keyspace datastore.Keyspace
+	node     *algebra.DropPolicy
+}
+
+func NewDropPolicy(keyspace datastore.Keyspace, node *algebra.DropPolicy) *DropPolicy {
+	return &DropPolicy{
+		keyspace: keyspace,
+		node:     node,
+	}
+}
+
+func (this *DropPolicy) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropPolicy(this)
+}
+
+func (this *DropPolicy) New() Operator {
+	return &DropPolicy{}
+}
+
+func (this *DropPolicy) Keyspace() datastore.Keyspace {
+	return this.keyspace
+}
+
+func (this *DropPolicy) Node() *algebra.DropPolicy {
+	return this.node
+}
+
+func (this *DropPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *DropPolicy) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "DropPolicy"}
+	this.node.Keyspace().MarshalKeyspace(r)
+	r["name"] = this.node.Name()
+	if this.node.Masking() {
+		r["masking"] = true
+	}
+
+	// invert so the default if not present is to fail if not exists
+	r["ifExists"] = !this.node.FailIfNotExists()
+
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *DropPolicy) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_         string `json:"#operator"`
+		Namespace string `json:"namespace"`
+		Bucket    string `json:"bucket"`
+		Scope     string `json:"scope"`
+		Keyspace  string `json:"keyspace"`
+		Name      string `json:"name"`
+		Masking   bool   `json:"masking"`
+		IfExists  bool   `json:"ifExists"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	ksref := algebra.NewKeyspaceRefFromPath(algebra.NewPathShortOrLong(_unmarshalled.Namespace, _unmarshalled.Bucket,
+		_unmarshalled.Scope, _unmarshalled.Keyspace), "")
+	this.keyspace, err = datastore.GetKeyspace(ksref.Path().Parts()...)
+	if err != nil {
+		return err
+	}
+
+	// invert IfExists to obtain FailIfExists
+	this.node = algebra.NewDropPolicy(_unmarshalled.Name, ksref, _unmarshalled.Masking, !_unmarshalled.IfExists)
+	return nil
+}
+
+func (this *DropPolicy) verify(prepared *Prepared) errors.Error {
+	var err errors.Error
+
+	this.keyspace, err = verifyKeyspace(this.keyspace, prepared)
+	return err
+}
+
+func (this *DropPolicy) keyspaceReferences(prepared *Prepared) {
+	prepared.addKeyspaceReference(this.keyspace)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/expression"
 	"github.com/couchbase/query/expression/parser"
+	"github.com/couchbase/query/policies"
 	"github.com/couchbase/query/util"
 	"github.com/couchbase/query/value"
 )
@@ -64,6 +65,7 @@ type Prepared struct {
 	txPrepareds        map[string]*Prepared
 	udfSubqPlans       []byte
 	planVersion        int
+	policyRevision     int32 // row-level security policies the plan applies
 	errCount           int
 	fatalError         bool
 
@@ -100,6 +102,7 @@ func NewPrepared(operator Operator, signature value.Value, indexScanKeyspaces ma
 		optimHints:         optimHints,
 		indexScanKeyspaces: indexScanKeyspaces,
 		planVersion:        planVersion,
+		policyRevision:     policies.Revision(),
 		subqueryPlans:      subqueryPlans,
 	}
 	p.countScan = p.hasCountScan()
@@ -610,6 +613,11 @@ func (this *Prepared) addKeyspaceMetadata(ksMeta datastore.KeyspaceMetadata) {
 
 func (this *Prepared) MetadataCheck() bool {
 
+	// policies may have been created or dropped since the plan was built
+	if this.policyRevision != policies.Revision() {
+		return false
+	}
+
 	// check that metadata is the same for the indexers involved
 	for _, idx := range this.indexers {
 		idx.indexer.Refresh()
@@ -631,6 +639,9 @@ func (this *Prepared) MetadataCheck() bool {
 
 // verify prepared+subquery plans
 func (this *Prepared) Verify() errors.Error {
+	if this.policyRevision != policies.Revision() {
+		return errors.NewPlanVerificationError("Row-level security policies have changed", nil)
+	}
 	err := this.Operator.verify(this)
 	if err == nil {
 		subqueryPlans := this.GetSubqueryPlans(false)
@@ -712,6 +723,8 @@ func operatorHasCountScan(op Operator) bool {
 		return operatorHasCountScan(o.child)
 	case *HashJoin:
 		return operatorHasCountScan(o.child)
+	case *AsofJoin:
+		return operatorHasCountScan(o.child)
 	case *HashNest:
 		return operatorHasCountScan(o.child)
 	case *Merge:
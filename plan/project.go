@@ -149,6 +149,17 @@ func (this *InitialProject) MarshalBase(f func(map[string]interface{})) map[stri
 	if this.discardOriginal {
 		r["discard_original"] = this.discardOriginal
 	}
+	if masks := this.projection.Masks(); len(masks) > 0 {
+		m := make([]interface{}, len(masks))
+		for i, mask := range masks {
+			m[i] = map[string]interface{}{
+				"alias": mask.Alias,
+				"path":  mask.Path,
+				"mask":  mask.Mask.String(),
+			}
+		}
+		r["masks"] = m
+	}
 	if f != nil {
 		f(r)
 	}
@@ -170,6 +181,11 @@ func (this *InitialProject) UnmarshalJSON(body []byte) error {
 		Exclude       expression.Expressions `json:"exclude"`
 		Bindings      []string               `json:"bindings"`
 		DiscardOrig   bool                   `json:"discard_original"`
+		Masks         []*struct {
+			Alias string   `json:"alias"`
+			Path  []string `json:"path"`
+			Mask  string   `json:"mask"`
+		} `json:"masks"`
 	}
 
 	err := json.Unmarshal(body, &_unmarshalled)
@@ -190,6 +206,17 @@ func (this *InitialProject) UnmarshalJSON(body []byte) error {
 	}
 	projection := algebra.NewProjection(_unmarshalled.Distinct, terms, _unmarshalled.Exclude)
 	projection.SetRaw(_unmarshalled.Raw)
+	if len(_unmarshalled.Masks) > 0 {
+		masks := make(algebra.ProjectionMasks, len(_unmarshalled.Masks))
+		for i, mask := range _unmarshalled.Masks {
+			expr, err := this.parseExpression(mask.Mask)
+			if err != nil {
+				return err
+			}
+			masks[i] = &algebra.ProjectionMask{Alias: mask.Alias, Path: mask.Path, Mask: expr}
+		}
+		projection.SetMasks(masks)
+	}
 	results := projection.Terms()
 	project_terms := make(ProjectTerms, len(results))
 
@@ -25,6 +25,7 @@ type ExpressionScan struct {
 	nested_loop bool
 	filter      expression.Expression
 	subqPlan    Operator
+	buffer      int
 }
 
 func NewExpressionScan(fromExpr expression.Expression, alias string, correlated, nested_loop bool,
@@ -76,6 +77,15 @@ func (this *ExpressionScan) SetFilter(filter expression.Expression) {
 	this.filter = filter
 }
 
+// The expression is a CTE whose rows are read from the n-th buffer of the With operator
+func (this *ExpressionScan) Buffer() int {
+	return this.buffer
+}
+
+func (this *ExpressionScan) SetBuffer(n int) {
+	this.buffer = n
+}
+
 // subqPlan: in case a SubqueryTerm is used under inner of a nested-loop join, we put an
 // ExpressionScan on top of the subquery; in this case we need to add the query plan of
 // the subquery in the "~subqueries" section of explain plan.
@@ -105,6 +115,9 @@ func (this *ExpressionScan) MarshalBase(f func(map[string]interface{})) map[stri
 	if this.filter != nil {
 		r["filter"] = this.filter.String()
 	}
+	if this.buffer > 0 {
+		r["buffer"] = this.buffer
+	}
 	if this.HasBuildBitFilter() {
 		this.marshalBuildBitFilters(r)
 	}
@@ -125,6 +138,7 @@ func (this *ExpressionScan) UnmarshalJSON(body []byte) error {
 		UnCorrelated    bool                   `json:"uncorrelated"`
 		NestedLoop      bool                   `json:"nested_loop"`
 		Filter          string                 `json:"filter"`
+		Buffer          int                    `json:"buffer"`
 		OptEstimate     map[string]interface{} `json:"optimizer_estimates"`
 		BuildBitFilters []json.RawMessage      `json:"build_bit_filters"`
 	}
@@ -145,6 +159,7 @@ func (this *ExpressionScan) UnmarshalJSON(body []byte) error {
 	// no info in the plan, then assume correlated is true.
 	this.correlated = !_unmarshalled.UnCorrelated
 	this.nested_loop = _unmarshalled.NestedLoop
+	this.buffer = _unmarshalled.Buffer
 
 	if _unmarshalled.Filter != "" {
 		this.filter, err = parser.Parse(_unmarshalled.Filter)
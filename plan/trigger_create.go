@@ -0,0 +1,143 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/expression/parser"
+	"github.com/couchbase/query/functions/storage"
+)
+
+// Create trigger
+type CreateTrigger struct {
+	ddl
+	keyspace datastore.Keyspace
+	node     *algebra.CreateTrigger
+}
+
+func NewCreateTrigger(keyspace datastore.Keyspace, node *algebra.CreateTrigger) *CreateTrigger {
+	return &CreateTrigger{
+		keyspace: keyspace,
+		node:     node,
+	}
+}
+
+func (this *CreateTrigger) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateTrigger(this)
+}
+
+func (this *CreateTrigger) New() Operator {
+	return &CreateTrigger{}
+}
+
+func (this *CreateTrigger) Keyspace() datastore.Keyspace {
+	return this.keyspace
+}
+
+func (this *CreateTrigger) Node() *algebra.CreateTrigger {
+	return this.node
+}
+
+func (this *CreateTrigger) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *CreateTrigger) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "CreateTrigger"}
+	this.node.Keyspace().MarshalKeyspace(r)
+	r["name"] = this.node.Name()
+	r["timing"] = this.node.Timing()
+	r["event"] = this.node.Event()
+	identity := make(map[string]interface{})
+	this.node.Function().Signature(identity)
+	r["identity"] = identity
+	if len(this.node.Args()) > 0 {
+		args := make([]string, len(this.node.Args()))
+		for i, arg := range this.node.Args() {
+			args[i] = arg.String()
+		}
+		r["args"] = args
+	}
+	if this.node.QueryContext() != "" {
+		r["query_context"] = this.node.QueryContext()
+	}
+	if this.node.Replace() {
+		r["replace"] = true
+	}
+
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *CreateTrigger) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_            string          `json:"#operator"`
+		Namespace    string          `json:"namespace"`
+		Bucket       string          `json:"bucket"`
+		Scope        string          `json:"scope"`
+		Keyspace     string          `json:"keyspace"`
+		Name         string          `json:"name"`
+		Timing       string          `json:"timing"`
+		Event        string          `json:"event"`
+		Identity     json.RawMessage `json:"identity"`
+		Args         []string        `json:"args"`
+		QueryContext string          `json:"query_context"`
+		Replace      bool            `json:"replace"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	ksref := algebra.NewKeyspaceRefFromPath(algebra.NewPathShortOrLong(_unmarshalled.Namespace, _unmarshalled.Bucket,
+		_unmarshalled.Scope, _unmarshalled.Keyspace), "")
+	this.keyspace, err = datastore.GetKeyspace(ksref.Path().Parts()...)
+	if err != nil {
+		return err
+	}
+
+	function, err := storage.MakeName(_unmarshalled.Identity)
+	if err != nil {
+		return err
+	}
+
+	var args expression.Expressions
+	if len(_unmarshalled.Args) > 0 {
+		args = make(expression.Expressions, len(_unmarshalled.Args))
+		for i, arg := range _unmarshalled.Args {
+			args[i], err = parser.Parse(arg)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	this.node = algebra.NewCreateTrigger(_unmarshalled.Name, ksref, _unmarshalled.Timing, _unmarshalled.Event, function,
+		args, _unmarshalled.QueryContext, _unmarshalled.Replace)
+	return nil
+}
+
+func (this *CreateTrigger) verify(prepared *Prepared) errors.Error {
+	var err errors.Error
+
+	this.keyspace, err = verifyKeyspace(this.keyspace, prepared)
+	return err
+}
+
+func (this *CreateTrigger) keyspaceReferences(prepared *Prepared) {
+	prepared.addKeyspaceReference(this.keyspace)
+}
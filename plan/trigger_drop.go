@@ -0,0 +1,104 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+)
+
+// Drop trigger
+type DropTrigger struct {
+	ddl
+	keyspace datastore.Keyspace
+	node     *algebra.DropTrigger
+}
+
+func NewDropTrigger(keyspace datastore.Keyspace, node *algebra.DropTrigger) *DropTrigger {
+	return &DropTrigger{
+		keyspace: keyspace,
+		node:     node,
+	}
+}
+
+func (this *DropTrigger) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitDropTrigger(this)
+}
+
+func (this *DropTrigger) New() Operator {
+	return &DropTrigger{}
+}
+
+func (this *DropTrigger) Keyspace() datastore.Keyspace {
+	return this.keyspace
+}
+
+func (this *DropTrigger) Node() *algebra.DropTrigger {
+	return this.node
+}
+
+func (this *DropTrigger) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *DropTrigger) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "DropTrigger"}
+	this.node.Keyspace().MarshalKeyspace(r)
+	r["name"] = this.node.Name()
+
+	// invert so the default if not present is to fail if not exists
+	r["ifExists"] = !this.node.FailIfNotExists()
+
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *DropTrigger) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_         string `json:"#operator"`
+		Namespace string `json:"namespace"`
+		Bucket    string `json:"bucket"`
+		Scope     string `json:"scope"`
+		Keyspace  string `json:"keyspace"`
+		Name      string `json:"name"`
+		IfExists  bool   `json:"ifExists"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	ksref := algebra.NewKeyspaceRefFromPath(algebra.NewPathShortOrLong(_unmarshalled.Namespace, _unmarshalled.Bucket,
+		_unmarshalled.Scope, _unmarshalled.Keyspace), "")
+	this.keyspace, err = datastore.GetKeyspace(ksref.Path().Parts()...)
+	if err != nil {
+		return err
+	}
+
+	// invert IfExists to obtain FailIfExists
+	this.node = algebra.NewDropTrigger(_unmarshalled.Name, ksref, !_unmarshalled.IfExists)
+	return nil
+}
+
+func (this *DropTrigger) verify(prepared *Prepared) errors.Error {
+	var err errors.Error
+
+	this.keyspace, err = verifyKeyspace(this.keyspace, prepared)
+	return err
+}
+
+func (this *DropTrigger) keyspaceReferences(prepared *Prepared) {
+	prepared.addKeyspaceReference(this.keyspace)
+}
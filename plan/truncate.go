@@ -0,0 +1,97 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+)
+
+// Truncate keyspace
+type Truncate struct {
+	ddl
+	keyspace datastore.Keyspace
+	node     *algebra.Truncate
+}
+
+func NewTruncate(keyspace datastore.Keyspace, node *algebra.Truncate) *Truncate {
+	return &Truncate{
+		keyspace: keyspace,
+		node:     node,
+	}
+}
+
+func (this *Truncate) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitTruncate(this)
+}
+
+func (this *Truncate) New() Operator {
+	return &Truncate{}
+}
+
+func (this *Truncate) Keyspace() datastore.Keyspace {
+	return this.keyspace
+}
+
+func (this *Truncate) Node() *algebra.Truncate {
+	return this.node
+}
+
+func (this *Truncate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *Truncate) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "Truncate"}
+	this.node.Keyspace().MarshalKeyspace(r)
+
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *Truncate) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_         string `json:"#operator"`
+		Namespace string `json:"namespace"`
+		Bucket    string `json:"bucket"`
+		Scope     string `json:"scope"`
+		Keyspace  string `json:"keyspace"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	ksref := algebra.NewKeyspaceRefFromPath(algebra.NewPathShortOrLong(_unmarshalled.Namespace, _unmarshalled.Bucket,
+		_unmarshalled.Scope, _unmarshalled.Keyspace), "")
+	this.keyspace, err = datastore.GetKeyspace(ksref.Path().Parts()...)
+	if err != nil {
+		return err
+	}
+
+	this.node = algebra.NewTruncate(ksref)
+	return nil
+}
+
+func (this *Truncate) verify(prepared *Prepared) errors.Error {
+	var err errors.Error
+
+	this.keyspace, err = verifyKeyspace(this.keyspace, prepared)
+	return err
+}
+
+func (this *Truncate) keyspaceReferences(prepared *Prepared) {
+	prepared.addKeyspaceReference(this.keyspace)
+}
@@ -0,0 +1,117 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/value"
+)
+
+type CreateView struct {
+	ddl
+	node *algebra.CreateView
+}
+
+func NewCreateView(node *algebra.CreateView) *CreateView {
+	return &CreateView{
+		node: node,
+	}
+}
+
+func (this *CreateView) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitCreateView(this)
+}
+
+func (this *CreateView) New() Operator {
+	return &CreateView{}
+}
+
+func (this *CreateView) Node() *algebra.CreateView {
+	return this.node
+}
+
+func (this *CreateView) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *CreateView) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "CreateView"}
+	this.node.MarshalName(r)
+	r["text"] = this.node.Text()
+	if this.node.QueryContext() != "" {
+		r["query_context"] = this.node.QueryContext()
+	}
+	if this.node.Replace() {
+		r["replace"] = true
+	}
+	if this.node.Materialized() {
+		r["materialized"] = true
+	}
+	if this.node.With() != nil {
+		r["with"] = this.node.With()
+	}
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *CreateView) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_            string          `json:"#operator"`
+		Namespace    string          `json:"namespace"`
+		Bucket       string          `json:"bucket"`
+		Scope        string          `json:"scope"`
+		Keyspace     string          `json:"keyspace"`
+		Text         string          `json:"text"`
+		QueryContext string          `json:"query_context"`
+		Replace      bool            `json:"replace"`
+		Materialized bool            `json:"materialized"`
+		With         json.RawMessage `json:"with"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	if _unmarshalled.Scope != "" {
+		_, err = datastore.GetScope(_unmarshalled.Namespace, _unmarshalled.Bucket, _unmarshalled.Scope)
+		if err != nil {
+			return err
+		}
+	}
+
+	var with value.Value
+	if len(_unmarshalled.With) > 0 {
+		with = value.NewValue([]byte(_unmarshalled.With))
+	}
+
+	path := algebra.NewPathLong(_unmarshalled.Namespace, _unmarshalled.Bucket, _unmarshalled.Scope, _unmarshalled.Keyspace)
+	this.node = algebra.NewCreateView(path, nil, _unmarshalled.Text, _unmarshalled.QueryContext, _unmarshalled.Replace,
+		_unmarshalled.Materialized, with)
+	return nil
+}
+
+func (this *CreateView) verify(prepared *Prepared) errors.Error {
+	var err errors.Error
+	if this.node.Name().Scope() != "" {
+		scope, err := datastore.GetScope(this.node.Name().Namespace(), this.node.Name().Bucket(), this.node.Name().Scope())
+		if err != nil {
+			return errors.NewPlanVerificationError(fmt.Sprintf("Scope: %s.%s not found", this.node.Name().Bucket(), this.node.Name().Scope()), err)
+		}
+		_, err = verifyScope(scope, prepared)
+	}
+	return err
+}
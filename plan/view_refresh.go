@@ -0,0 +1,97 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+)
+
+type RefreshView struct {
+	ddl
+	node *algebra.RefreshView
+}
+
+func NewRefreshView(node *algebra.RefreshView) *RefreshView {
+	return &RefreshView{
+		node: node,
+	}
+}
+
+func (this *RefreshView) Accept(visitor Visitor) (interface{}, error) {
+	return visitor.VisitRefreshView(this)
+}
+
+func (this *RefreshView) New() Operator {
+	return &RefreshView{}
+}
+
+func (this *RefreshView) Node() *algebra.RefreshView {
+	return this.node
+}
+
+func (this *RefreshView) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.MarshalBase(nil))
+}
+
+func (this *RefreshView) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
+	r := map[string]interface{}{"#operator": "RefreshView"}
+	this.node.MarshalName(r)
+	if this.node.Incremental() {
+		r["incremental"] = true
+	}
+
+	if f != nil {
+		f(r)
+	}
+	return r
+}
+
+func (this *RefreshView) UnmarshalJSON(body []byte) error {
+	var _unmarshalled struct {
+		_           string `json:"#operator"`
+		Namespace   string `json:"namespace"`
+		Bucket      string `json:"bucket"`
+		Scope       string `json:"scope"`
+		Keyspace    string `json:"keyspace"`
+		Incremental bool   `json:"incremental"`
+	}
+
+	err := json.Unmarshal(body, &_unmarshalled)
+	if err != nil {
+		return err
+	}
+
+	if _unmarshalled.Scope != "" {
+		_, err = datastore.GetScope(_unmarshalled.Namespace, _unmarshalled.Bucket, _unmarshalled.Scope)
+		if err != nil {
+			return err
+		}
+	}
+
+	path := algebra.NewPathLong(_unmarshalled.Namespace, _unmarshalled.Bucket, _unmarshalled.Scope, _unmarshalled.Keyspace)
+	this.node = algebra.NewRefreshView(path, _unmarshalled.Incremental)
+	return nil
+}
+
+func (this *RefreshView) verify(prepared *Prepared) errors.Error {
+	var err errors.Error
+	if this.node.Name().Scope() != "" {
+		scope, err := datastore.GetScope(this.node.Name().Namespace(), this.node.Name().Bucket(), this.node.Name().Scope())
+		if err != nil {
+			return errors.NewPlanVerificationError(fmt.Sprintf("Scope: %s.%s not found", this.node.Name().Bucket(), this.node.Name().Scope()), err)
+		}
+		_, err = verifyScope(scope, prepared)
+	}
+	return err
+}
@@ -45,6 +45,7 @@ type Visitor interface {
 	VisitNLJoin(op *NLJoin) (interface{}, error)
 	VisitNLNest(op *NLNest) (interface{}, error)
 	VisitHashJoin(op *HashJoin) (interface{}, error)
+	VisitAsofJoin(op *AsofJoin) (interface{}, error)
 	VisitHashNest(op *HashNest) (interface{}, error)
 
 	// Let + Letting, With
@@ -58,6 +59,7 @@ type Visitor interface {
 	VisitInitialGroup(op *InitialGroup) (interface{}, error)
 	VisitIntermediateGroup(op *IntermediateGroup) (interface{}, error)
 	VisitFinalGroup(op *FinalGroup) (interface{}, error)
+	VisitGapFill(op *GapFill) (interface{}, error)
 
 	// Window functions
 	VisitWindowAggregate(op *WindowAggregate) (interface{}, error)
@@ -136,6 +138,7 @@ type Visitor interface {
 	VisitCreateCollection(op *CreateCollection) (interface{}, error)
 	VisitDropCollection(op *DropCollection) (interface{}, error)
 	VisitFlushCollection(op *FlushCollection) (interface{}, error)
+	VisitTruncate(op *Truncate) (interface{}, error)
 	VisitAlterCollection(op *AlterCollection) (any, error)
 
 	// Users
@@ -189,6 +192,24 @@ type Visitor interface {
 	VisitDropSequence(op *DropSequence) (interface{}, error)
 	VisitAlterSequence(op *AlterSequence) (interface{}, error)
 
+	// Views
+	VisitCreateView(op *CreateView) (interface{}, error)
+	VisitDropView(op *DropView) (interface{}, error)
+	VisitRefreshView(op *RefreshView) (interface{}, error)
+
+	// Triggers
+	VisitCreateTrigger(op *CreateTrigger) (interface{}, error)
+	VisitDropTrigger(op *DropTrigger) (interface{}, error)
+
+	// Jobs
+	VisitCreateJob(op *CreateJob) (interface{}, error)
+	VisitAlterJob(op *AlterJob) (interface{}, error)
+	VisitDropJob(op *DropJob) (interface{}, error)
+
+	// Policies
+	VisitCreatePolicy(op *CreatePolicy) (interface{}, error)
+	VisitDropPolicy(op *DropPolicy) (interface{}, error)
+
 	// CredentialStore
 	VisitCreateCredentialStore(op *CreateCredentialStore) (any, error)
 	VisitAlterCredentialStore(op *AlterCredentialStore) (any, error)
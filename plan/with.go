@@ -22,6 +22,7 @@ type With struct {
 	readonly
 	optEstimate
 	bindings *algebra.WithClause
+	buffered map[string]int
 	child    Operator
 }
 
@@ -47,6 +48,19 @@ func (this *With) Bindings() *algebra.WithClause {
 	return this.bindings
 }
 
+/*
+CTEs read only by expression scans, with the number of scans. Their rows
+are held in buffers that can spill to disk, one for each scan, rather
+than bound as an array.
+*/
+func (this *With) Buffered() map[string]int {
+	return this.buffered
+}
+
+func (this *With) SetBuffered(buffered map[string]int) {
+	this.buffered = buffered
+}
+
 func (this *With) Readonly() bool {
 	return this.child.Readonly()
 }
@@ -63,6 +77,9 @@ func (this *With) MarshalBase(f func(map[string]interface{})) map[string]interfa
 	r := map[string]interface{}{"#operator": "With"}
 	r["recursive"] = this.bindings.IsRecursive()
 	r["bindings"] = this.bindings.Bindings()
+	if len(this.buffered) > 0 {
+		r["buffered"] = this.buffered
+	}
 	if optEstimate := marshalOptEstimate(&this.optEstimate); optEstimate != nil {
 		r["optimizer_estimates"] = optEstimate
 	}
@@ -79,6 +96,7 @@ func (this *With) UnmarshalJSON(body []byte) error {
 		_           string                 `json:"#operator"`
 		Recursive   bool                   `json:"recursive"`
 		Bindings    json.RawMessage        `json:"bindings"`
+		Buffered    map[string]int         `json:"buffered"`
 		Child       json.RawMessage        `json:"~child"`
 		OptEstimate map[string]interface{} `json:"optimizer_estimates"`
 	}
@@ -99,6 +117,7 @@ func (this *With) UnmarshalJSON(body []byte) error {
 
 	this.bindings = algebra.NewWithClause(_unmarshalled.Recursive, withs)
 	this.bindings.SetBindings(withs)
+	this.buffered = _unmarshalled.Buffered
 
 	err = json.Unmarshal(_unmarshalled.Child, &child_type)
 	if err != nil {
@@ -122,13 +141,14 @@ func (this *With) UnmarshalJSON(body []byte) error {
 
 func (this *With) unmarshalWiths(body []byte) (expression.Withs, error) {
 	var _unmarshalled []struct {
-		Alias   string          `json:"alias"`
-		Expr    string          `json:"expr"`
-		Rexpr   string          `json:"rexpr"`
-		Isunion bool            `json:"is_union"`
-		Config  json.RawMessage `json:"config"`
-		Cycle   json.RawMessage `json:"cycle"`
-		Var     string          `json:"var"` // for plan from pre-7.6 server
+		Alias        string          `json:"alias"`
+		Expr         string          `json:"expr"`
+		Rexpr        string          `json:"rexpr"`
+		Isunion      bool            `json:"is_union"`
+		Config       json.RawMessage `json:"config"`
+		Cycle        json.RawMessage `json:"cycle"`
+		Materialized *bool           `json:"materialized"`
+		Var          string          `json:"var"` // for plan from pre-7.6 server
 	}
 
 	err := json.Unmarshal(body, &_unmarshalled)
@@ -179,6 +199,13 @@ func (this *With) unmarshalWiths(body []byte) (expression.Withs, error) {
 			}
 		}
 		withs[i] = algebra.NewWith(alias, expr, rexpr, union, config, cycle)
+		if with.Materialized != nil {
+			if *with.Materialized {
+				withs[i].SetMaterialization(expression.MATERIALIZE_ALWAYS)
+			} else {
+				withs[i].SetMaterialization(expression.MATERIALIZE_NEVER)
+			}
+		}
 	}
 
 	return withs, nil
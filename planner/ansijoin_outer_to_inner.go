@@ -150,6 +150,13 @@ func (this *ansijoinOuterToInner) VisitAnsiJoin(node *algebra.AnsiJoin) (interfa
 	return nil, nil
 }
 
+func (this *ansijoinOuterToInner) VisitAsofJoin(node *algebra.AsofJoin) (interface{}, error) {
+	// an ASOF join stays outer: the nearest object is chosen before
+	// the WHERE clause is applied
+	_, err := node.Left().Accept(this)
+	return nil, err
+}
+
 func (this *ansijoinOuterToInner) VisitNest(node *algebra.Nest) (interface{}, error) {
 	// no mixing of lookup nest and ANSI JOIN/NEST
 	return nil, nil
@@ -27,7 +27,7 @@ func Build(stmt algebra.Statement, datastore, systemstore datastore.Datastore,
 	*plan.QueryPlan, map[string]bool, error, map[string]time.Duration) {
 
 	builder := newBuilder(datastore, systemstore, namespace, subquery, context)
-	if context.UseCBO() && context.Optimizer() != nil {
+	if (context.UseCBO() || optStatisticsCBO()) && context.Optimizer() != nil {
 		builder.useCBO = true
 		checkCostModel(context.FeatureControls())
 	}
@@ -41,6 +41,16 @@ func Build(stmt algebra.Statement, datastore, systemstore datastore.Datastore,
 		builder.setBuilderFlag(BUILDER_PLAN_SUBQUERY)
 	}
 
+	err := builder.expandViews(stmt)
+	if err != nil {
+		return nil, nil, err, builder.subTimes
+	}
+
+	err = builder.applyPolicies(stmt)
+	if err != nil {
+		return nil, nil, err, builder.subTimes
+	}
+
 	p, err := stmt.Accept(builder)
 
 	if err != nil {
@@ -57,6 +67,9 @@ func Build(stmt algebra.Statement, datastore, systemstore datastore.Datastore,
 		if err != nil {
 			return nil, nil, err, builder.subTimes
 		}
+		for _, ks := range builder.viewSources {
+			privs.Add(ks, auth.PRIV_QUERY_SELECT, auth.PRIV_PROPS_NONE)
+		}
 
 		if stream {
 
@@ -245,6 +258,9 @@ type builder struct {
 	vectors              expression.Expressions
 	subqCoveringInfo     map[*algebra.Subselect]CoveringSubqInfo
 	initialProjection    *algebra.Projection
+	viewSources          []string                        // keyspaces read through materialized views
+	withBuffers          map[*algebra.ExpressionTerm]int // references to buffered CTEs
+	masks                map[expression.Expression]bool  // masks substituted for masked paths
 }
 
 func (this *builder) Copy() *builder {
@@ -274,6 +290,7 @@ func (this *builder) Copy() *builder {
 		partialSortTermCount: this.partialSortTermCount,
 		arrayId:              this.arrayId,
 		initialProjection:    this.initialProjection,
+		withBuffers:          this.withBuffers,
 		// the following fields are setup during planning process and thus not copied:
 		// children, subChildren, coveringScan, coveredUnnests, countScan, orderScan, lastOp
 		// subqCoveringInfo
@@ -19,6 +19,7 @@ import (
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/logging"
 	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/triggers"
 )
 
 func getScope(credentials *auth.Credentials, retry bool, parts ...string) (datastore.Scope, errors.Error) {
@@ -97,6 +98,26 @@ func (this *builder) VisitFlushCollection(stmt *algebra.FlushCollection) (interf
 	return plan.NewQueryPlan(plan.NewFlushCollection(keyspace, stmt)), nil
 }
 
+func (this *builder) VisitTruncate(stmt *algebra.Truncate) (interface{}, error) {
+	ksref := stmt.Keyspace()
+	keyspace, err := this.getNameKeyspace(ksref, false, true, stmt.Type())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := keyspace.(datastore.TruncatingKeyspace); !ok || ksref.IsSystem() {
+		return nil, errors.NewNoTruncateError(ksref.Path().SimpleString())
+	}
+
+	// documents are removed without being read, so DELETE triggers could not see them
+	deleteTriggers, err := keyspaceTriggers(keyspace, triggers.DELETE)
+	if err != nil {
+		return nil, err
+	} else if len(deleteTriggers) > 0 {
+		return nil, errors.NewTriggerError(errors.E_TRIGGER_UNSUPPORTED, ksref.Path().SimpleString(), "TRUNCATE")
+	}
+	return plan.NewQueryPlan(plan.NewTruncate(keyspace, stmt)), nil
+}
+
 func (this *builder) VisitAlterCollection(stmt *algebra.AlterCollection) (any, error) {
 	ksref := stmt.Keyspace()
 	scope, err := getScope(this.context.Credentials(), false, ksref.Path().Parts()...)
@@ -0,0 +1,57 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of the
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/plan"
+)
+
+func TestTruncate(t *testing.T) {
+	withMockDatastore(t)
+
+	n1ql.SetNamespaces(map[string]interface{}{"p0": true})
+	truncate := func(text, namespace string) (algebra.Statement, interface{}, error) {
+		stmt, err := n1ql.ParseStatement2(text, namespace, "")
+		if err != nil {
+			t.Fatalf("n1ql.ParseStatement2(%q): %v", text, err)
+		}
+		qp, err := stmt.Accept(newBuilder(nil, nil, "p0", false, &PrepareContext{}))
+		return stmt, qp, err
+	}
+
+	// TRUNCATE needs the truncate privilege on the keyspace, which is checked against delete
+	stmt, qp, err := truncate("TRUNCATE p0:b0", "p0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := qp.(*plan.QueryPlan).PlanOp().(*plan.Truncate); !ok {
+		t.Fatalf("Expected a truncate plan, found %v", qp.(*plan.QueryPlan).PlanOp())
+	}
+	if stmt.String() != "TRUNCATE `p0`:`b0`" {
+		t.Fatalf("Unexpected statement text %v", stmt.String())
+	}
+	privs, err := stmt.Privileges()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(privs.List) != 1 || privs.List[0].Priv != auth.PRIV_QUERY_TRUNCATE || privs.List[0].Target != "p0:b0" {
+		t.Fatalf("Expected the truncate privilege on p0:b0, found %v", privs.List)
+	}
+
+	// system keyspaces cannot be truncated, even when the keyspace supports it
+	_, _, err = truncate("TRUNCATE b0", datastore.SYSTEM_NAMESPACE)
+	expectErrorCode(t, err, errors.E_NO_TRUNCATE)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/couchbase/query/algebra"
 	"github.com/couchbase/query/plan"
 	base "github.com/couchbase/query/plannerbase"
+	"github.com/couchbase/query/triggers"
+	"github.com/couchbase/query/views"
 )
 
 func (this *builder) VisitDelete(stmt *algebra.Delete) (interface{}, error) {
@@ -34,7 +36,14 @@ func (this *builder) VisitDelete(stmt *algebra.Delete) (interface{}, error) {
 		return nil, err
 	}
 
-	mustFetch := stmt.Returning() != nil || this.context.DeltaKeyspaces() != nil
+	// triggers see the whole of the documents deleted
+	deleteTriggers, err := keyspaceTriggers(keyspace, triggers.DELETE)
+	if err != nil {
+		return nil, err
+	}
+
+	mustFetch := stmt.Returning() != nil || this.context.DeltaKeyspaces() != nil ||
+		views.Tracking(keyspace.QualifiedName()) || len(deleteTriggers) > 0
 	optimHints := stmt.OptimHints()
 	optimHints, err = this.beginMutate(keyspace, ksref, stmt.Keys(), stmt.Indexes(), stmt.Limit(), stmt.Offset(),
 		mustFetch, optimHints, stmt.Let())
@@ -66,6 +75,7 @@ func (this *builder) VisitDelete(stmt *algebra.Delete) (interface{}, error) {
 
 	sd := plan.NewSendDelete(keyspace, ksref, stmt.Limit(), cost, cardinality, size, frCost, stmt.Returning() == nil)
 	sd.SetValidateKeys(stmt.ValidateKeys())
+	sd.SetUnfetched(!hasFetch(this.subChildren))
 	deleteSubChildren = append(deleteSubChildren, sd)
 
 	if stmt.Returning() != nil {
@@ -125,3 +135,15 @@ func (this *builder) VisitDelete(stmt *algebra.Delete) (interface{}, error) {
 	qp.SetPlanOp(plan.NewSequence(this.children...))
 	return qp, nil
 }
+
+// the triggers of a keyspace for an event; a variable, so that tests can supply their own
+var keyspaceTriggers = triggers.KeyspaceTriggers
+
+func hasFetch(ops []plan.Operator) bool {
+	for _, op := range ops {
+		if _, ok := op.(*plan.Fetch); ok {
+			return true
+		}
+	}
+	return false
+}
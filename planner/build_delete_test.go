@@ -0,0 +1,119 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/triggers"
+)
+
+// Replaces the triggers kept in system collections
+func withTriggers(t *testing.T, list ...*triggers.Trigger) {
+	saved := keyspaceTriggers
+	keyspaceTriggers = func(ks datastore.Keyspace, event string) ([]*triggers.Trigger, errors.Error) {
+		var rv []*triggers.Trigger
+		for _, tr := range list {
+			if tr.Keyspace == ks.QualifiedName() && tr.Event == event {
+				rv = append(rv, tr)
+			}
+		}
+		return rv, nil
+	}
+	t.Cleanup(func() { keyspaceTriggers = saved })
+}
+
+func explainDelete(t *testing.T, text string) string {
+	t.Helper()
+	n1ql.SetNamespaces(map[string]interface{}{"p0": true})
+	stmt, err := n1ql.ParseStatement2(text, "p0", "")
+	if err != nil {
+		t.Fatalf("n1ql.ParseStatement2(%q): %v", text, err)
+	}
+	qp, err := stmt.Accept(newBuilder(nil, nil, "p0", false, &PrepareContext{}))
+	if err != nil {
+		t.Fatalf("Unexpected error planning %q: %v", text, err)
+	}
+	bytes, err := json.Marshal(qp.(*plan.QueryPlan).PlanOp())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return string(bytes)
+}
+
+func TestDeleteFetch(t *testing.T) {
+	withMockDatastore(t)
+
+	// deletes that need no document only read keys, or index entries
+	s := explainDelete(t, "DELETE FROM p0:b0 USE KEYS [\"1\", \"2\"]")
+	if !strings.Contains(s, `"#operator":"DummyFetch"`) || !strings.Contains(s, `"unfetched":true`) {
+		t.Fatalf("Expected the keys not to be fetched, found %v", s)
+	}
+	s = explainDelete(t, "DELETE FROM p0:b0 WHERE META().id > \"1\"")
+	if !strings.Contains(s, `"covers"`) || !strings.Contains(s, `"unfetched":true`) {
+		t.Fatalf("Expected a covered delete, found %v", s)
+	}
+
+	s = explainDelete(t, "DELETE FROM p0:b0 USE KEYS [\"1\", \"2\"] RETURNING b0.name")
+	if !strings.Contains(s, `"#operator":"Fetch"`) || strings.Contains(s, `"unfetched"`) {
+		t.Fatalf("Expected the documents to be fetched, found %v", s)
+	}
+}
+
+func TestDeleteTriggers(t *testing.T) {
+	withMockDatastore(t)
+
+	// DELETE triggers see the documents they delete, even where a delete needs no document
+	withTriggers(t, &triggers.Trigger{Name: "audit", Keyspace: "p0:b0", Timing: triggers.BEFORE, Event: triggers.DELETE})
+	for _, text := range []string{
+		"DELETE FROM p0:b0 USE KEYS [\"1\", \"2\"]",
+		"DELETE FROM p0:b0 WHERE META().id > \"1\"",
+	} {
+		s := explainDelete(t, text)
+		if !strings.Contains(s, `"#operator":"Fetch"`) || strings.Contains(s, `"unfetched"`) ||
+			strings.Contains(s, `"covers"`) {
+			t.Errorf("Expected %q to fetch the documents, found %v", text, s)
+		}
+	}
+
+	// triggers on other events do not
+	withTriggers(t, &triggers.Trigger{Name: "audit", Keyspace: "p0:b0", Timing: triggers.AFTER, Event: triggers.UPDATE})
+	if s := explainDelete(t, "DELETE FROM p0:b0 USE KEYS [\"1\"]"); strings.Contains(s, `"#operator":"Fetch"`) {
+		t.Errorf("Expected the keys not to be fetched, found %v", s)
+	}
+}
+
+func TestTruncateTriggers(t *testing.T) {
+	withMockDatastore(t)
+
+	n1ql.SetNamespaces(map[string]interface{}{"p0": true})
+	truncate := func() error {
+		stmt, err := n1ql.ParseStatement2("TRUNCATE p0:b0", "p0", "")
+		if err != nil {
+			t.Fatalf("n1ql.ParseStatement2: %v", err)
+		}
+		_, err = stmt.Accept(newBuilder(nil, nil, "p0", false, &PrepareContext{}))
+		return err
+	}
+
+	// TRUNCATE fires no triggers, so it is refused where DELETE triggers would not see the documents
+	withTriggers(t, &triggers.Trigger{Name: "audit", Keyspace: "p0:b0", Timing: triggers.AFTER, Event: triggers.DELETE})
+	expectErrorCode(t, truncate(), errors.E_TRIGGER_UNSUPPORTED)
+
+	withTriggers(t, &triggers.Trigger{Name: "audit", Keyspace: "p0:b0", Timing: triggers.BEFORE, Event: triggers.INSERT})
+	if err := truncate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
@@ -19,6 +19,7 @@ func (this *builder) VisitExplain(stmt *algebra.Explain) (interface{}, error) {
 		return nil, err
 	}
 
-	op := plan.NewExplain(qp.(*plan.QueryPlan), stmt.Text(), stmt.Statement().OptimHints())
+	op := plan.NewExplain(qp.(*plan.QueryPlan), stmt.Text(), stmt.Statement().OptimHints(),
+		stmt.Analyze(), stmt.Rollback(), stmt.Format())
 	return plan.NewQueryPlan(op), nil
 }
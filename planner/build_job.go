@@ -0,0 +1,40 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/jobs"
+	"github.com/couchbase/query/plan"
+)
+
+func (this *builder) VisitCreateJob(stmt *algebra.CreateJob) (interface{}, error) {
+	_, err := jobs.NewJob(stmt.Name(), stmt.Schedule(), stmt.With())
+	if err != nil {
+		return nil, err
+	}
+	return plan.NewQueryPlan(plan.NewCreateJob(stmt)), nil
+}
+
+// The schedule and options are checked again against the stored job when it is altered
+func (this *builder) VisitAlterJob(stmt *algebra.AlterJob) (interface{}, error) {
+	job := &jobs.Job{Name: stmt.Name()}
+	err := job.SetOptions(stmt.With())
+	if err == nil && stmt.Schedule() != "" {
+		err = job.SetSchedule(stmt.Schedule())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan.NewQueryPlan(plan.NewAlterJob(stmt)), nil
+}
+
+func (this *builder) VisitDropJob(stmt *algebra.DropJob) (interface{}, error) {
+	return plan.NewQueryPlan(plan.NewDropJob(stmt)), nil
+}
@@ -0,0 +1,93 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of the
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/plan"
+)
+
+func TestJobs(t *testing.T) {
+	stmt := mustParseStatement(t, "CREATE OR REPLACE JOB purge SCHEDULE \"0 3 * * *\" "+
+		"WITH {\"timeout\": \"10m\", \"timezone\": \"UTC\"} AS DELETE FROM default:b.s.logs WHERE age > 30;")
+	qp, err := stmt.Accept(newRewriteBuilder())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := qp.(*plan.QueryPlan).PlanOp().(*plan.CreateJob); !ok {
+		t.Fatalf("Expected a CREATE JOB plan, found %v", qp.(*plan.QueryPlan).PlanOp())
+	}
+	// the statement is kept as written
+	if s := stmt.String(); s != "CREATE OR REPLACE JOB `purge` SCHEDULE \"0 3 * * *\" "+
+		"WITH {\"timeout\":\"10m\",\"timezone\":\"UTC\"} AS DELETE FROM default:b.s.logs WHERE age > 30" {
+		t.Fatalf("Unexpected statement text %v", s)
+	}
+
+	// the job needs the privileges of its statement as well as those to manage functions
+	privs, err := stmt.Privileges()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	found := make(map[auth.Privilege]string, 2)
+	privs.ForEach(func(pair auth.PrivilegePair) {
+		found[pair.Priv] = pair.Target
+	})
+	if _, ok := found[auth.PRIV_QUERY_MANAGE_FUNCTIONS]; !ok || found[auth.PRIV_QUERY_DELETE] != "default:b.s.logs" {
+		t.Fatalf("Expected the privileges to manage functions and to delete from logs, found %v", privs.List)
+	}
+
+	stmt = mustParseStatement(t, "ALTER JOB purge WITH {\"enabled\": false}")
+	if qp, err = stmt.Accept(newRewriteBuilder()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := qp.(*plan.QueryPlan).PlanOp().(*plan.AlterJob); !ok {
+		t.Fatalf("Expected an ALTER JOB plan, found %v", qp.(*plan.QueryPlan).PlanOp())
+	}
+
+	stmt = mustParseStatement(t, "DROP JOB IF EXISTS purge")
+	if qp, err = stmt.Accept(newRewriteBuilder()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := qp.(*plan.QueryPlan).PlanOp().(*plan.DropJob); !ok {
+		t.Fatalf("Expected a DROP JOB plan, found %v", qp.(*plan.QueryPlan).PlanOp())
+	}
+
+	// schedules and options are checked when the statement is planned
+	for _, c := range []struct {
+		text string
+		code errors.ErrorCode
+	}{
+		{"CREATE JOB purge SCHEDULE \"0 25 * * *\" AS DELETE FROM default:b.s.logs", errors.E_JOB_INVALID_SCHEDULE},
+		{"CREATE JOB purge SCHEDULE \"@daily\" WITH {\"timeout\": \"-1s\"} AS DELETE FROM default:b.s.logs",
+			errors.E_JOB_INVALID_OPTION},
+		{"CREATE JOB purge SCHEDULE \"@daily\" WITH {\"retries\": 3} AS DELETE FROM default:b.s.logs",
+			errors.E_JOB_INVALID_OPTION},
+		{"ALTER JOB purge SCHEDULE \"every day\"", errors.E_JOB_INVALID_SCHEDULE},
+		{"ALTER JOB purge WITH {\"timezone\": \"Nowhere/Special\"}", errors.E_JOB_INVALID_OPTION},
+		{"ALTER JOB purge WITH {\"enabled\": \"yes\"}", errors.E_JOB_INVALID_OPTION},
+	} {
+		_, err = mustParseStatement(t, c.text).Accept(newRewriteBuilder())
+		expectErrorCode(t, err, c.code)
+	}
+
+	// job definitions are whole statements
+	for _, s := range []string{
+		"CREATE JOB purge SCHEDULE \"@daily\" AS DELETE FROM default:b.s.logs WHERE age > $1",
+		"CREATE JOB purge EVERY \"@daily\" AS DELETE FROM default:b.s.logs",
+		"ALTER JOB purge",
+	} {
+		if _, err := n1ql.ParseStatement2(s, "default", ""); err == nil {
+			t.Errorf("Expected %q not to parse", s)
+		}
+	}
+}
@@ -2308,6 +2308,13 @@ func getBuildBFInfo(buildInfosMap map[string]*base.BuildInfo,
 			err = skipBuildAlias(buildInfosMap, op.Alias())
 		case *plan.HashNest:
 			err = skipBuildAlias(buildInfosMap, op.BuildAlias())
+		case *plan.AsofJoin:
+			for _, a := range op.BuildAliases() {
+				err = skipBuildAlias(buildInfosMap, a)
+				if err != nil {
+					return
+				}
+			}
 		case *plan.HashJoin:
 			if !op.Outer() {
 				err = getBuildBFInfo(buildInfosMap, buildAliases, op.Child())
@@ -2374,6 +2381,8 @@ func checkJoinFilterHint(baseKeyspaces map[string]*base.BaseKeyspace, ops ...pla
 			err = checkJoinFilterHint(baseKeyspaces, op.Children()...)
 		case *plan.HashJoin:
 			err = checkJoinFilterHint(baseKeyspaces, op.Child())
+		case *plan.AsofJoin:
+			err = checkJoinFilterHint(baseKeyspaces, op.Child())
 		case *plan.HashNest:
 			err = checkJoinFilterHint(baseKeyspaces, op.Child())
 		case *plan.NLJoin:
@@ -0,0 +1,208 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/plan"
+)
+
+func (this *builder) VisitAsofJoin(node *algebra.AsofJoin) (interface{}, error) {
+	if algebra.GetKeyspaceTerm(node.PrimaryTerm()) != nil &&
+		this.group == nil {
+		this.resetProjection()
+		this.resetIndexGroupAggs()
+		this.resetOffsetLimit()
+	} else {
+		this.resetPushDowns()
+	}
+
+	_, err := node.Left().Accept(this)
+	if err != nil {
+		return nil, err
+	}
+
+	join, err := this.buildAsofJoin(node)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(this.subChildren) > 0 {
+		this.addChildren(this.addSubchildrenParallel())
+	}
+	this.addChildren(join)
+
+	return nil, this.processKeyspaceDone(node.Alias())
+}
+
+/*
+The right-hand side is planned as the build side of a hash join on the
+equality terms of the ON-clause, which it always is for an outer join;
+the order of the left-hand side, and any index order it is read in, is
+kept.
+*/
+func (this *builder) buildAsofJoin(node *algebra.AsofJoin) (*plan.AsofJoin, error) {
+	right := node.Right()
+	if ksterm := algebra.GetKeyspaceTerm(right); ksterm != nil {
+		right = ksterm
+	}
+	alias := right.Alias()
+
+	terms := asofTerms(node.Onclause(), nil)
+	pos, rightFirst, err := this.asofTimeTerm(alias, terms)
+	if err != nil {
+		return nil, err
+	}
+
+	others := make(expression.Expressions, 0, len(terms)-1)
+	others = append(others, terms[:pos]...)
+	others = append(others, terms[pos+1:]...)
+	var onclause expression.Expression
+	switch len(others) {
+	case 0:
+		return nil, errors.NewAsofJoinOnclauseError(alias, "an equality term between the two sides is required")
+	case 1:
+		onclause = others[0]
+	default:
+		onclause = expression.NewAnd(others...)
+	}
+
+	// the time term is left out: it chooses among the objects matching the others
+	err = this.processOnclause(alias, onclause, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	this.extractKeyspacePredicates(nil, onclause)
+
+	baseKeyspace, _ := this.baseKeyspaces[alias]
+	if len(baseKeyspace.Filters()) > 0 {
+		baseKeyspace.Filters().ClearPlanFlags()
+	}
+
+	filter, _, err := this.getFilter(alias, true, true, onclause)
+	if err != nil {
+		return nil, err
+	}
+
+	child, buildExprs, probeExprs, buildAliases, _, newOnclause, newFilter, _, _, cost, cardinality, size, frCost, err :=
+		this.buildHashJoinOp(right, node.Left(), true, node.Onclause(), filter, "join", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if child == nil {
+		return nil, errors.NewAsofJoinOnclauseError(alias, "an equality term between the two sides is required")
+	}
+	if newOnclause == nil {
+		newOnclause, newFilter = node.Onclause(), filter
+	}
+
+	// the time term, cover transformed along with the ON-clause
+	covered := asofTerms(newOnclause, nil)
+	if len(covered) != len(terms) {
+		return nil, errors.NewPlanInternalError("buildAsofJoin: ON-clause terms changed by cover transformation")
+	}
+	first, second, strict, ok := asofComparison(covered[pos])
+	if !ok {
+		return nil, errors.NewPlanInternalError("buildAsofJoin: time term changed by cover transformation")
+	}
+
+	// right <= left looks back from the left time; left <= right looks ahead
+	buildTime, probeTime, forward := first, second, false
+	if !rightFirst {
+		buildTime, probeTime, forward = second, first, true
+	}
+
+	if !this.joinEnum() {
+		err = this.markOptimHints(alias, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return plan.NewAsofJoin(node, child, buildExprs, probeExprs, buildTime, probeTime, forward, strict,
+		buildAliases, newOnclause, newFilter, cost, cardinality, size, frCost), nil
+}
+
+/*
+The position of the single inequality term of the ON-clause comparing
+an expression of the right-hand side alone to one of the left-hand side,
+and whether the right-hand side is the lesser.
+*/
+func (this *builder) asofTimeTerm(alias string, terms expression.Expressions) (int, bool, error) {
+	aliases := map[string]string{alias: alias}
+	pos := -1
+	rightFirst := false
+	for i, term := range terms {
+		first, second, _, ok := asofComparison(term)
+		if !ok {
+			continue
+		}
+		firstRef := expression.HasSingleKeyspaceReference(first, alias, this.keyspaceNames)
+		secondRef := expression.HasSingleKeyspaceReference(second, alias, this.keyspaceNames)
+		other := second
+		if secondRef {
+			other = first
+		}
+		if firstRef == secondRef || expression.HasKeyspaceReferences(other, aliases) ||
+			!expression.HasKeyspaceReferences(other, this.keyspaceNames) {
+			continue
+		}
+		if pos >= 0 {
+			return -1, false, errors.NewAsofJoinOnclauseError(alias,
+				"only one inequality term between the two sides is allowed")
+		}
+		pos = i
+		rightFirst = firstRef
+	}
+
+	if pos < 0 {
+		return -1, false, errors.NewAsofJoinOnclauseError(alias,
+			"an inequality term between the times of the two sides is required")
+	}
+	return pos, rightFirst, nil
+}
+
+// The operands of a <= or < comparison; >= and > are parsed as these
+func asofComparison(expr expression.Expression) (first, second expression.Expression, strict, ok bool) {
+	switch expr := expr.(type) {
+	case *expression.LE:
+		return expr.First(), expr.Second(), false, true
+	case *expression.LT:
+		return expr.First(), expr.Second(), true, true
+	}
+	return nil, nil, false, false
+}
+
+func asofTerms(expr expression.Expression, terms expression.Expressions) expression.Expressions {
+	if and, ok := expr.(*expression.And); ok {
+		for _, op := range and.Operands() {
+			terms = asofTerms(op, terms)
+		}
+		return terms
+	}
+	return append(terms, expr)
+}
+
+// Join enumeration does not consider ASOF joins
+func hasAsofJoin(term algebra.FromTerm) bool {
+	for term != nil {
+		switch t := term.(type) {
+		case *algebra.AsofJoin:
+			return true
+		case algebra.JoinTerm:
+			term = t.Left()
+		default:
+			return false
+		}
+	}
+	return false
+}
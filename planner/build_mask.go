@@ -0,0 +1,727 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"strings"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/policies"
+)
+
+/*
+Masking policies are applied alongside row-level security policies. The
+masked paths of the documents a subselect projects are replaced at
+runtime, as each document is projected, so that whole documents and
+stars are masked too. Every other clause sees the documents as read, so
+masked paths referenced there are rewritten to their mask, and a masked
+document, or any path that contains a masked one, referenced as a whole
+outside the projection, is refused. Covering indexes are not used for
+masked keyspaces, so that a masked path is never read from an index.
+*/
+
+type columnMask struct {
+	path []string
+	mask expression.Expression
+}
+
+// the masks of the aliases of one subselect; an alias without masks
+// shadows the masks of an outer alias of the same name
+type maskScope struct {
+	parent  *maskScope
+	aliases map[string][]*columnMask
+	runtime bool
+}
+
+func newMaskScope(parent *maskScope) *maskScope {
+	return &maskScope{parent: parent, aliases: make(map[string][]*columnMask)}
+}
+
+// the same aliases, masked at runtime as the projection is evaluated
+func (this *maskScope) projection() *maskScope {
+	return &maskScope{parent: this.parent, aliases: this.aliases, runtime: true}
+}
+
+func (this *maskScope) lookup(alias string) ([]*columnMask, bool) {
+	for s := this; s != nil; s = s.parent {
+		if masks, ok := s.aliases[alias]; ok {
+			return masks, s.runtime
+		}
+	}
+	return nil, false
+}
+
+func (this *maskScope) projectionMasks() algebra.ProjectionMasks {
+	var rv algebra.ProjectionMasks
+	for alias, masks := range this.aliases {
+		for _, m := range masks {
+			rv = append(rv, &algebra.ProjectionMask{Alias: alias, Path: m.path, Mask: m.mask})
+		}
+	}
+	return rv
+}
+
+func (this *builder) applyMasks(stmt algebra.Statement) error {
+	this.masks = make(map[expression.Expression]bool)
+	switch stmt := stmt.(type) {
+	case *algebra.Select:
+		return this.selectMasks(stmt, nil)
+	case *algebra.Insert:
+		if stmt.Select() != nil {
+			return this.selectMasks(stmt.Select(), nil)
+		}
+	case *algebra.Upsert:
+		if stmt.Select() != nil {
+			return this.selectMasks(stmt.Select(), nil)
+		}
+	case *algebra.Update:
+		return this.updateMasks(stmt)
+	case *algebra.Delete:
+		return this.deleteMasks(stmt)
+	case *algebra.Merge:
+		return this.mergeMasks(stmt)
+	case *algebra.Explain, *algebra.Advise, *algebra.Prepare:
+		return nil
+	}
+	return this.subqueryMasks(stmt.Expressions(), nil)
+}
+
+func (this *builder) selectMasks(sel *algebra.Select, scope *maskScope) error {
+	if sel.PoliciesApplied() {
+		return nil
+	}
+	if with := sel.With(); with != nil {
+		if err := with.MapExpressions(newMaskMapper(this, scope, nil, false)); err != nil {
+			return err
+		}
+	}
+	level := scope
+	if sub, ok := sel.Subresult().(*algebra.Subselect); ok {
+		var err error
+		level, err = this.subselectMasks(sub, scope)
+		if err != nil {
+			return err
+		}
+	} else if err := this.subresultMasks(sel.Subresult(), scope); err != nil {
+		return err
+	}
+	if sel.Order() != nil {
+		return sel.Order().MapExpressions(newMaskMapper(this, level, nil, false))
+	}
+	return nil
+}
+
+func (this *builder) subresultMasks(subresult algebra.Subresult, scope *maskScope) error {
+	switch node := subresult.(type) {
+	case *algebra.Subselect:
+		_, err := this.subselectMasks(node, scope)
+		return err
+	case *algebra.SelectTerm:
+		return this.selectMasks(node.Select(), scope)
+	case interface {
+		First() algebra.Subresult
+		Second() algebra.Subresult
+	}:
+		if err := this.subresultMasks(node.First(), scope); err != nil {
+			return err
+		}
+		return this.subresultMasks(node.Second(), scope)
+	}
+	return nil
+}
+
+// the scope returned applies to the ORDER BY of the enclosing select
+func (this *builder) subselectMasks(node *algebra.Subselect, scope *maskScope) (*maskScope, error) {
+	level := newMaskScope(scope)
+	if node.From() != nil {
+		if err := this.fromMasks(node.From(), level); err != nil {
+			return nil, err
+		}
+	}
+
+	mapper := newMaskMapper(this, level, nil, false)
+	if node.From() != nil {
+		if err := this.mapFromMasks(node.From(), level, mapper); err != nil {
+			return nil, err
+		}
+	}
+	if node.Let() != nil {
+		if err := node.Let().MapExpressions(mapper); err != nil {
+			return nil, err
+		}
+	}
+	if node.Where() != nil {
+		where, err := mapper.Map(node.Where())
+		if err != nil {
+			return nil, err
+		}
+		node.SetWhere(where)
+	}
+	group := node.Group()
+	if group != nil {
+		if group.GroupAs() != "" && len(level.projectionMasks()) > 0 {
+			return nil, errors.NewPolicyError(errors.E_POLICY_UNSUPPORTED, maskedKeyspaces(node.From()), "GROUP AS")
+		}
+		if err := group.MapExpressions(mapper); err != nil {
+			return nil, err
+		}
+	}
+	if node.Window() != nil {
+		if err := node.Window().MapExpressions(mapper); err != nil {
+			return nil, err
+		}
+	}
+
+	// grouped documents are projected through their group keys, so
+	// the projection is rewritten like the other clauses
+	projection := node.Projection()
+	if group != nil {
+		return level, projection.MapExpressions(mapper)
+	}
+	if err := projection.MapExpressions(newMaskMapper(this, level.projection(), level, false)); err != nil {
+		return nil, err
+	}
+	projection.SetMasks(level.projectionMasks())
+	return level, nil
+}
+
+// collects the aliases of the FROM clause, and the masks of its keyspaces
+func (this *builder) fromMasks(term algebra.FromTerm, level *maskScope) error {
+	switch term := term.(type) {
+	case *algebra.KeyspaceTerm:
+		return this.keyspaceMasks(term, level, "")
+	case *algebra.ExpressionTerm:
+		if ksterm := algebra.GetKeyspaceTerm(term); ksterm != nil {
+			return this.keyspaceMasks(ksterm, level, "")
+		}
+		level.aliases[term.Alias()] = nil
+	case *algebra.SubqueryTerm:
+		level.aliases[term.Alias()] = nil
+	case *algebra.AnsiNest:
+		if err := this.fromMasks(term.Left(), level); err != nil {
+			return err
+		}
+		if ksterm := algebra.GetKeyspaceTerm(term.Right()); ksterm != nil {
+			return this.keyspaceMasks(ksterm, level, "NEST")
+		}
+		return this.fromMasks(term.Right(), level)
+	case *algebra.Nest:
+		if err := this.fromMasks(term.Left(), level); err != nil {
+			return err
+		}
+		return this.keyspaceMasks(term.Right(), level, "NEST ON KEYS")
+	case *algebra.IndexNest:
+		if err := this.fromMasks(term.Left(), level); err != nil {
+			return err
+		}
+		return this.keyspaceMasks(term.Right(), level, "NEST ON KEY ... FOR")
+	case *algebra.AnsiJoin:
+		if err := this.fromMasks(term.Left(), level); err != nil {
+			return err
+		}
+		return this.fromMasks(term.Right(), level)
+	case *algebra.AsofJoin:
+		if err := this.fromMasks(term.Left(), level); err != nil {
+			return err
+		}
+		return this.fromMasks(term.Right(), level)
+	case *algebra.Join:
+		if err := this.fromMasks(term.Left(), level); err != nil {
+			return err
+		}
+		return this.keyspaceMasks(term.Right(), level, "")
+	case *algebra.IndexJoin:
+		if err := this.fromMasks(term.Left(), level); err != nil {
+			return err
+		}
+		return this.keyspaceMasks(term.Right(), level, "")
+	case algebra.JoinTerm:
+		if err := this.fromMasks(term.Left(), level); err != nil {
+			return err
+		}
+		level.aliases[term.Alias()] = nil
+	}
+	return nil
+}
+
+// a non empty operation is one the masks of the keyspace cannot be applied to
+func (this *builder) keyspaceMasks(term *algebra.KeyspaceTerm, level *maskScope, operation string) error {
+	masks, err := this.columnMasks(term.Path(), term.Alias())
+	if err != nil {
+		return err
+	}
+	if len(masks) > 0 {
+		if operation != "" {
+			return errors.NewPolicyError(errors.E_POLICY_UNSUPPORTED, term.Path().SimpleString(), operation)
+		}
+		term.SetMasked()
+	}
+	level.aliases[term.Alias()] = masks
+	return nil
+}
+
+// rewrites the expressions of the FROM clause, and plans its derived tables
+func (this *builder) mapFromMasks(term algebra.FromTerm, level *maskScope, mapper expression.Mapper) error {
+	switch term := term.(type) {
+	case *algebra.KeyspaceTerm:
+		return term.MapExpressions(mapper)
+	case *algebra.ExpressionTerm:
+		return term.MapExpressions(mapper)
+	case *algebra.SubqueryTerm:
+		return this.selectMasks(term.Subquery(), level.parent)
+	case *algebra.AnsiJoin:
+		if err := this.mapFromMasks(term.Left(), level, mapper); err != nil {
+			return err
+		}
+		if err := this.mapFromMasks(term.Right(), level, mapper); err != nil {
+			return err
+		}
+		onclause, err := mapper.Map(term.Onclause())
+		if err != nil {
+			return err
+		}
+		term.SetOnclause(onclause)
+	case *algebra.AnsiNest:
+		if err := this.mapFromMasks(term.Left(), level, mapper); err != nil {
+			return err
+		}
+		if err := this.mapFromMasks(term.Right(), level, mapper); err != nil {
+			return err
+		}
+		onclause, err := mapper.Map(term.Onclause())
+		if err != nil {
+			return err
+		}
+		term.SetOnclause(onclause)
+	case *algebra.AsofJoin:
+		if err := this.mapFromMasks(term.Left(), level, mapper); err != nil {
+			return err
+		}
+		if err := this.mapFromMasks(term.Right(), level, mapper); err != nil {
+			return err
+		}
+		onclause, err := mapper.Map(term.Onclause())
+		if err != nil {
+			return err
+		}
+		term.SetOnclause(onclause)
+	case *algebra.Unnest:
+		if err := this.mapFromMasks(term.Left(), level, mapper); err != nil {
+			return err
+		}
+		return term.MapExpression(mapper)
+	case interface {
+		Left() algebra.FromTerm
+		Right() *algebra.KeyspaceTerm
+	}:
+		if err := this.mapFromMasks(term.Left(), level, mapper); err != nil {
+			return err
+		}
+		return term.Right().MapExpressions(mapper)
+	}
+	return nil
+}
+
+func (this *builder) updateMasks(stmt *algebra.Update) error {
+	if stmt.PoliciesApplied() {
+		return nil
+	}
+	ksref := stmt.KeyspaceRef()
+	level := newMaskScope(nil)
+	masks, err := this.columnMasks(ksref.Path(), ksref.Alias())
+	if err != nil {
+		return err
+	} else if len(masks) == 0 {
+		return this.subqueryMasks(stmt.Expressions(), nil)
+	}
+	level.aliases[ksref.Alias()] = masks
+
+	mapper := newMaskMapper(this, level, nil, false)
+	if stmt.Keys() != nil {
+		if err := this.subqueryMasks(expression.Expressions{stmt.Keys()}, nil); err != nil {
+			return err
+		}
+	}
+	if stmt.Let() != nil {
+		if err := stmt.Let().MapExpressions(mapper); err != nil {
+			return err
+		}
+	}
+	if stmt.Set() != nil {
+		if err := setMasks(stmt.Set(), mapper, newMaskMapper(this, level, nil, true)); err != nil {
+			return err
+		}
+	}
+	if stmt.Where() != nil {
+		where, err := mapper.Map(stmt.Where())
+		if err != nil {
+			return err
+		}
+		stmt.SetWhere(where)
+	}
+	return returningMasks(stmt.Returning(), level, mapper)
+}
+
+func (this *builder) deleteMasks(stmt *algebra.Delete) error {
+	if stmt.PoliciesApplied() {
+		return nil
+	}
+	ksref := stmt.KeyspaceRef()
+	level := newMaskScope(nil)
+	masks, err := this.columnMasks(ksref.Path(), ksref.Alias())
+	if err != nil {
+		return err
+	} else if len(masks) == 0 {
+		return this.subqueryMasks(stmt.Expressions(), nil)
+	}
+	level.aliases[ksref.Alias()] = masks
+
+	mapper := newMaskMapper(this, level, nil, false)
+	if stmt.Keys() != nil {
+		if err := this.subqueryMasks(expression.Expressions{stmt.Keys()}, nil); err != nil {
+			return err
+		}
+	}
+	if stmt.Let() != nil {
+		if err := stmt.Let().MapExpressions(mapper); err != nil {
+			return err
+		}
+	}
+	if stmt.Where() != nil {
+		where, err := mapper.Map(stmt.Where())
+		if err != nil {
+			return err
+		}
+		stmt.SetWhere(where)
+	}
+	return returningMasks(stmt.Returning(), level, mapper)
+}
+
+func (this *builder) mergeMasks(stmt *algebra.Merge) error {
+	if stmt.PoliciesApplied() {
+		return nil
+	}
+	level := newMaskScope(nil)
+	source := stmt.Source()
+	if term := source.From(); term != nil {
+		if err := this.keyspaceMasks(term, level, ""); err != nil {
+			return err
+		}
+	} else if term := algebra.GetKeyspaceTerm(source.ExpressionTerm()); term != nil {
+		if err := this.keyspaceMasks(term, level, ""); err != nil {
+			return err
+		}
+	}
+	ksref := stmt.KeyspaceRef()
+	masks, err := this.columnMasks(ksref.Path(), ksref.Alias())
+	if err != nil {
+		return err
+	}
+	level.aliases[ksref.Alias()] = masks
+	if len(level.projectionMasks()) == 0 {
+		return this.subqueryMasks(stmt.Expressions(), nil)
+	}
+	if term := source.SubqueryTerm(); term != nil {
+		if err := this.selectMasks(term.Subquery(), nil); err != nil {
+			return err
+		}
+	}
+
+	mapper := newMaskMapper(this, level, nil, false)
+	if term := source.ExpressionTerm(); term != nil {
+		if err := term.MapExpressions(mapper); err != nil {
+			return err
+		}
+	}
+	if stmt.On() != nil {
+		on, err := mapper.Map(stmt.On())
+		if err != nil {
+			return err
+		}
+		stmt.SetOn(on)
+	}
+	if stmt.Let() != nil {
+		if err := stmt.Let().MapExpressions(mapper); err != nil {
+			return err
+		}
+	}
+	actions := stmt.Actions()
+	if act := actions.Update(); act != nil {
+		if act.Set() != nil {
+			if err := setMasks(act.Set(), mapper, newMaskMapper(this, level, nil, true)); err != nil {
+				return err
+			}
+		}
+		if act.Where() != nil {
+			where, err := mapper.Map(act.Where())
+			if err != nil {
+				return err
+			}
+			act.SetWhere(where)
+		}
+	}
+	if act := actions.Delete(); act != nil && act.Where() != nil {
+		where, err := mapper.Map(act.Where())
+		if err != nil {
+			return err
+		}
+		act.SetWhere(where)
+	}
+	if act := actions.Insert(); act != nil {
+		if err := act.MapExpressions(mapper); err != nil {
+			return err
+		}
+	}
+	return returningMasks(stmt.Returning(), level, mapper)
+}
+
+// the paths SET assigns are left as they are, and masked paths may not
+// be navigated by an UPDATE FOR
+func setMasks(set *algebra.Set, mapper, check *maskMapper) error {
+	for _, term := range set.Terms() {
+		value, err := mapper.Map(term.Value())
+		if err != nil {
+			return err
+		}
+		term.SetValue(value)
+		if term.UpdateFor() != nil {
+			if err := term.UpdateFor().MapExpressions(check); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func returningMasks(returning *algebra.Projection, level *maskScope, mapper *maskMapper) error {
+	if returning == nil {
+		return nil
+	}
+	err := returning.MapExpressions(newMaskMapper(mapper.builder, level.projection(), level, false))
+	if err != nil {
+		return err
+	}
+	returning.SetMasks(level.projectionMasks())
+	return nil
+}
+
+func (this *builder) subqueryMasks(exprs expression.Expressions, scope *maskScope) error {
+	subqueries, err := expression.ListSubqueries(exprs, false)
+	if err != nil {
+		return err
+	}
+	for _, s := range subqueries {
+		if subq, ok := s.(*algebra.Subquery); ok {
+			err = this.selectMasks(subq.Select(), scope)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+The masks of a keyspace, formalized to its alias. Each mask is applied to
+the users not exempt from it, which is checked when the statement runs.
+*/
+func (this *builder) columnMasks(path *algebra.Path, alias string) ([]*columnMask, error) {
+	if path == nil || path.IsSystem() {
+		return nil, nil
+	}
+	list, err := keyspacePolicies(path, policies.MASK)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	masks := make([]*columnMask, 0, len(list))
+	for _, p := range list {
+		def, err := p.Definition(parseMaskingPolicy)
+		if err != nil {
+			return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_DEFINITION, p.FullName(), err)
+		}
+		exprs := def.(expression.Expressions).Copy()
+		for i, expr := range exprs {
+			exprs[i], err = expression.NewSelfFormalizer(alias, nil).Map(expr)
+			if err != nil {
+				return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_DEFINITION, p.FullName(), err)
+			}
+		}
+		column, mask := exprs[0], exprs[1]
+		root, fields, ok := maskPath(column)
+		if !ok || root != alias || len(fields) == 0 {
+			return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_DEFINITION, p.FullName(), column.String())
+		}
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = f.Second().(*expression.FieldName).Alias()
+		}
+		if !p.Public() {
+			mask = expression.NewSearchedCase(expression.WhenTerms{
+				&expression.WhenTerm{When: grantedTo(p.Roles), Then: column}}, mask)
+		}
+		masks = append(masks, &columnMask{path: names, mask: mask})
+	}
+	return masks, nil
+}
+
+func parseMaskingPolicy(p *policies.Policy) (interface{}, error) {
+	column, err := n1ql.ParseExpression(p.Column)
+	if err != nil {
+		return nil, err
+	}
+	mask, err := n1ql.ParseExpression(p.Predicate)
+	if err != nil {
+		return nil, err
+	}
+	return expression.Expressions{column, mask}, nil
+}
+
+// the keyspace alias a path such as alias.address.zip starts from, and its fields
+func maskPath(expr expression.Expression) (string, []*expression.Field, bool) {
+	var fields []*expression.Field
+	for {
+		switch e := expr.(type) {
+		case *expression.Field:
+			if _, ok := e.Second().(*expression.FieldName); !ok {
+				return "", nil, false
+			}
+			fields = append(fields, e)
+			expr = e.First()
+		case *expression.Identifier:
+			if e.IsBindingVariable() {
+				return "", nil, false
+			}
+			for i, j := 0, len(fields)-1; i < j; i, j = i+1, j-1 {
+				fields[i], fields[j] = fields[j], fields[i]
+			}
+			return e.Identifier(), fields, true
+		default:
+			return "", nil, false
+		}
+	}
+}
+
+func maskedKeyspaces(term algebra.FromTerm) string {
+	var names []string
+	for term != nil {
+		var ksterm *algebra.KeyspaceTerm
+		switch t := term.(type) {
+		case *algebra.KeyspaceTerm:
+			ksterm = t
+		case *algebra.ExpressionTerm:
+			ksterm = algebra.GetKeyspaceTerm(t)
+		case interface{ Right() *algebra.KeyspaceTerm }:
+			ksterm = t.Right()
+		case interface{ Right() algebra.SimpleFromTerm }:
+			ksterm = algebra.GetKeyspaceTerm(t.Right())
+		}
+		if ksterm != nil && ksterm.IsMasked() {
+			names = append(names, ksterm.Path().SimpleString())
+		}
+		if join, ok := term.(algebra.JoinTerm); ok {
+			term = join.Left()
+		} else {
+			term = nil
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+/*
+Rewrites the masked paths of an expression to their masks. The aliases of
+a scope masked at runtime are left as they are, other than in the operands
+of aggregates, which are evaluated over the documents as read.
+*/
+type maskMapper struct {
+	expression.MapperBase
+
+	builder *builder
+	scope   *maskScope
+	raw     *maskScope
+	check   bool
+}
+
+func newMaskMapper(builder *builder, scope, raw *maskScope, check bool) *maskMapper {
+	rv := &maskMapper{builder: builder, scope: scope, raw: raw, check: check}
+	rv.SetMapper(rv)
+	rv.SetMapFunc(rv.mapMasks)
+	return rv
+}
+
+func (this *maskMapper) mapMasks(expr expression.Expression) (expression.Expression, error) {
+	if this.builder.masks[expr] {
+		return expr, nil
+	}
+	switch e := expr.(type) {
+	case *algebra.Subquery:
+		return expr, this.builder.selectMasks(e.Select(), this.scope)
+	case algebra.Aggregate:
+		if this.raw != nil {
+			return expr, expr.MapChildren(newMaskMapper(this.builder, this.raw, nil, this.check))
+		}
+	case *expression.Field, *expression.Identifier:
+		if alias, fields, ok := maskPath(expr); ok {
+			return this.mapPath(expr, alias, fields)
+		}
+	case expression.Function:
+		switch e.Name() {
+		case "meta", "search_meta", "search_score":
+			return expr, nil
+		}
+	}
+	return expr, expr.MapChildren(this)
+}
+
+func (this *maskMapper) mapPath(expr expression.Expression, alias string, fields []*expression.Field) (
+	expression.Expression, error) {
+
+	masks, runtime := this.scope.lookup(alias)
+	if runtime {
+		return expr, nil
+	}
+	for _, m := range masks {
+		if maskPrefix(m.path, fields) < len(m.path) {
+			continue
+		}
+		if this.check {
+			return nil, errors.NewPolicyError(errors.E_POLICY_MASKED_REFERENCE, expr.String())
+		}
+		rv := m.mask.Copy()
+		this.builder.masks[rv] = true
+		for _, f := range fields[len(m.path):] {
+			field := expression.NewField(rv, f.Second())
+			field.SetCaseInsensitive(f.CaseInsensitive())
+			rv = field
+		}
+		return rv, nil
+	}
+	for _, m := range masks {
+		if maskPrefix(m.path, fields) == len(fields) {
+			return nil, errors.NewPolicyError(errors.E_POLICY_MASKED_REFERENCE, expr.String())
+		}
+	}
+	return expr, nil
+}
+
+// the number of leading fields that match the masked path
+func maskPrefix(path []string, fields []*expression.Field) int {
+	n := 0
+	for n < len(path) && n < len(fields) {
+		name := fields[n].Second().(*expression.FieldName).Alias()
+		if name != path[n] && !(fields[n].CaseInsensitive() && strings.EqualFold(name, path[n])) {
+			break
+		}
+		n++
+	}
+	return n
+}
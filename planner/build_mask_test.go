@@ -0,0 +1,149 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of the
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/policies"
+)
+
+func maskPolicies(t *testing.T) {
+	withPolicies(t,
+		&policies.Policy{Name: "emails", Keyspace: "orders", Event: policies.MASK, Column: "email", Predicate: "\"***\""},
+		&policies.Policy{Name: "zips", Keyspace: "orders", Event: policies.MASK, Column: "address.zip",
+			Roles: []string{"support"}, Predicate: "\"00000\""})
+}
+
+func TestProjectionMasks(t *testing.T) {
+	maskPolicies(t)
+
+	// the projection is masked as documents are projected, the other clauses are rewritten to the masks
+	stmt, err := rewriteStatement(t, "SELECT o.email, o.name FROM default:b.s.orders AS o "+
+		"WHERE o.email LIKE \"%@x\" AND o.address.zip.code = 1 ORDER BY o.email")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	node := subselectOf(t, stmt)
+	if s := node.Projection().String(); s != "(`o`.`email`), (`o`.`name`)" {
+		t.Fatalf("Expected the projection to be left to runtime masking, found %v", s)
+	}
+	masks := node.Projection().Masks()
+	if len(masks) != 2 {
+		t.Fatalf("Expected the masks on email and address.zip, found %v", masks)
+	}
+	for _, m := range masks {
+		if m.Alias != "o" || (strings.Join(m.Path, ".") != "email" && strings.Join(m.Path, ".") != "address.zip") {
+			t.Fatalf("Unexpected mask of %v on %v", m.Alias, m.Path)
+		}
+	}
+	if s := node.Where().String(); s != "((\"***\" like \"%@x\") and ((case when array_contains_any("+
+		"(current_user_info().`grants`), [\"support\"]) then ((`o`.`address`).`zip`) else \"00000\" end.`code`) = 1))" {
+		t.Fatalf("Expected the WHERE clause to read the masks, exempting the grantees, found %v", s)
+	}
+	if s := stmt.(*algebra.Select).Order().Terms()[0].Expression().String(); s != "\"***\"" {
+		t.Fatalf("Expected ORDER BY on the mask, found %v", s)
+	}
+	if term := node.From().(*algebra.KeyspaceTerm); !term.IsMasked() {
+		t.Fatalf("Expected the keyspace to be marked masked, so that it is not covered")
+	}
+
+	// grouped documents are projected through their masked group keys
+	stmt, err = rewriteStatement(t, "SELECT o.email, COUNT(o.email) AS n FROM default:b.s.orders AS o GROUP BY o.email")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s := stmt.String(); s != "select \"***\", count(\"***\") as `n` from `default`:`b`.`s`.`orders` as `o`  group by \"***\"" {
+		t.Fatalf("Expected the group keys and projection to be masked, found %v", s)
+	}
+
+	// subqueries are masked too
+	stmt, err = rewriteStatement(t, "SELECT c.name, (SELECT RAW x.email FROM default:b.s.orders AS x) AS e "+
+		"FROM default:b.s.customers AS c")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	subq := subselectOf(t, stmt).Projection().Terms()[1].Expression().(*algebra.Subquery)
+	if m := subselectOf(t, subq.Select()).Projection().Masks(); len(m) != 2 || m[0].Alias != "x" {
+		t.Fatalf("Expected the subquery projection to be masked, found %v", m)
+	}
+	if m := subselectOf(t, stmt).Projection().Masks(); len(m) != 0 {
+		t.Fatalf("Expected no masks on customers, found %v", m)
+	}
+
+	for _, c := range []struct {
+		text string
+		code errors.ErrorCode
+	}{
+		// a document holding masked data can only be used as a whole in the projection
+		{"SELECT o.address FROM default:b.s.orders AS o WHERE o.address = {}", errors.E_POLICY_MASKED_REFERENCE},
+		{"SELECT o.email FROM default:b.s.orders AS o GROUP BY o.email GROUP AS g", errors.E_POLICY_UNSUPPORTED},
+		{"SELECT c.name FROM default:b.s.customers AS c NEST default:b.s.orders AS o ON KEYS c.oids",
+			errors.E_POLICY_UNSUPPORTED},
+	} {
+		_, err = rewriteStatement(t, c.text)
+		expectErrorCode(t, err, c.code)
+	}
+}
+
+func TestMutationMasks(t *testing.T) {
+	maskPolicies(t)
+
+	// updates cannot copy masked data, and see documents as projected
+	stmt, err := rewriteStatement(t, "UPDATE default:b.s.orders AS o SET o.name = o.email WHERE o.email = \"a\" RETURNING o.*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	update := stmt.(*algebra.Update)
+	if s := update.Set().Terms()[0].Value().String(); s != "\"***\"" {
+		t.Fatalf("Expected the masked value to be set, found %v", s)
+	}
+	if s := update.Where().String(); s != "(\"***\" = \"a\")" {
+		t.Fatalf("Expected the WHERE clause to read the mask, found %v", s)
+	}
+	if m := update.Returning().Masks(); len(m) != 2 {
+		t.Fatalf("Expected the returned documents to be masked, found %v", m)
+	}
+
+	_, err = rewriteStatement(t, "UPDATE default:b.s.orders AS o SET e.verified = true FOR e IN o.email END")
+	expectErrorCode(t, err, errors.E_POLICY_MASKED_REFERENCE)
+
+	// a column that is not a path of the collection is refused
+	withPolicies(t, &policies.Policy{Name: "upper", Keyspace: "orders", Event: policies.MASK, Column: "UPPER(email)",
+		Predicate: "\"***\""})
+	_, err = rewriteStatement(t, "SELECT o.email FROM default:b.s.orders AS o")
+	expectErrorCode(t, err, errors.E_POLICY_INVALID_DEFINITION)
+}
+
+func TestMaskPlan(t *testing.T) {
+	withMockDatastore(t)
+	withPolicies(t, &policies.Policy{Name: "emails", Keyspace: "b0", Event: policies.MASK, Column: "email",
+		Predicate: "\"***\""})
+	n1ql.SetNamespaces(map[string]interface{}{"p0": true})
+
+	stmt, err := n1ql.ParseStatement2("SELECT b.* FROM p0:b0 AS b", "p0", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	qp, _, err, _ := Build(stmt, datastore.GetDatastore(), datastore.GetSystemstore(), "p0", false, false, false,
+		&PrepareContext{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	bytes, _ := json.Marshal(qp.PlanOp())
+	if s := string(bytes); !strings.Contains(s, `"masks":[{"alias":"b","mask":"\"***\"","path":["email"]}]`) {
+		t.Fatalf("Expected the projection of the documents to be masked, found %v", s)
+	}
+}
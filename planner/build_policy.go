@@ -0,0 +1,399 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/policies"
+)
+
+func (this *builder) VisitCreatePolicy(stmt *algebra.CreatePolicy) (interface{}, error) {
+	ksref := stmt.Keyspace()
+	keyspace, err := this.getNameKeyspace(ksref, false, true, stmt.Type())
+	if err != nil {
+		return nil, err
+	}
+	if ksref.IsSystem() {
+		return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_KEYSPACE, ksref.Path().SimpleString())
+	}
+	return plan.NewQueryPlan(plan.NewCreatePolicy(keyspace, stmt)), nil
+}
+
+func (this *builder) VisitDropPolicy(stmt *algebra.DropPolicy) (interface{}, error) {
+	ksref := stmt.Keyspace()
+	keyspace, err := this.getNameKeyspace(ksref, false, false, stmt.Type())
+	if err != nil {
+		return nil, err
+	}
+	if ksref.IsSystem() {
+		return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_KEYSPACE, ksref.Path().SimpleString())
+	}
+	return plan.NewQueryPlan(plan.NewDropPolicy(keyspace, stmt)), nil
+}
+
+/*
+Row-level security policies are applied before the statement is planned,
+after views have been expanded. The predicate of each policy on a keyspace
+the statement reads is ANDed into the WHERE clause, or the ON clause of the
+join that reads it, so that it restricts every access path the planner
+goes on to consider, covering index scans included. The policies of one
+keyspace are ORed, each restricted to its grantees, which are checked when
+the statement runs, so that a plan can be shared among users.
+*/
+func (this *builder) applyPolicies(stmt algebra.Statement) error {
+	if err := this.applyMasks(stmt); err != nil {
+		return err
+	}
+	switch stmt := stmt.(type) {
+	case *algebra.Select:
+		return this.selectPolicies(stmt)
+	case *algebra.Insert:
+		if stmt.Select() != nil {
+			if err := this.selectPolicies(stmt.Select()); err != nil {
+				return err
+			}
+		}
+	case *algebra.Upsert:
+		// an UPSERT replaces existing documents without reading them, so it cannot be held to UPDATE policies
+		if err := unsupportedEventPolicies(stmt.KeyspaceRef().Path(), policies.UPDATE, "UPSERT"); err != nil {
+			return err
+		}
+		if stmt.Select() != nil {
+			if err := this.selectPolicies(stmt.Select()); err != nil {
+				return err
+			}
+		}
+	case *algebra.Update:
+		if !stmt.PoliciesApplied() {
+			stmt.SetPoliciesApplied()
+			filter, err := this.policyFilter(stmt.KeyspaceRef().Path(), stmt.KeyspaceRef().Alias(), policies.UPDATE)
+			if err != nil {
+				return err
+			}
+			stmt.SetWhere(andPolicyFilter(stmt.Where(), filter))
+		}
+	case *algebra.Delete:
+		if !stmt.PoliciesApplied() {
+			stmt.SetPoliciesApplied()
+			filter, err := this.policyFilter(stmt.KeyspaceRef().Path(), stmt.KeyspaceRef().Alias(), policies.DELETE)
+			if err != nil {
+				return err
+			}
+			stmt.SetWhere(andPolicyFilter(stmt.Where(), filter))
+		}
+	case *algebra.Merge:
+		if !stmt.PoliciesApplied() {
+			stmt.SetPoliciesApplied()
+			if err := this.mergePolicies(stmt); err != nil {
+				return err
+			}
+		}
+	case *algebra.Truncate:
+		return unsupportedEventPolicies(stmt.Keyspace().Path(), policies.DELETE, "TRUNCATE")
+	case *algebra.Explain:
+		return this.applyPolicies(stmt.Statement())
+	case *algebra.Advise:
+		return this.applyPolicies(stmt.Statement())
+	case *algebra.Prepare:
+		return this.applyPolicies(stmt.Statement())
+	}
+	return this.subqueryPolicies(stmt.Expressions())
+}
+
+func (this *builder) mergePolicies(stmt *algebra.Merge) error {
+	source := stmt.Source()
+	if term := source.From(); term != nil {
+		if err := this.unsupportedPolicies(term, "MERGE source"); err != nil {
+			return err
+		}
+	} else if term := algebra.GetKeyspaceTerm(source.ExpressionTerm()); term != nil {
+		if err := this.unsupportedPolicies(term, "MERGE source"); err != nil {
+			return err
+		}
+	} else if term := source.SubqueryTerm(); term != nil {
+		if err := this.selectPolicies(term.Subquery()); err != nil {
+			return err
+		}
+	}
+
+	ksref := stmt.KeyspaceRef()
+	actions := stmt.Actions()
+	if act := actions.Update(); act != nil {
+		filter, err := this.policyFilter(ksref.Path(), ksref.Alias(), policies.UPDATE)
+		if err != nil {
+			return err
+		}
+		act.SetWhere(andPolicyFilter(act.Where(), filter))
+	}
+	if act := actions.Delete(); act != nil {
+		filter, err := this.policyFilter(ksref.Path(), ksref.Alias(), policies.DELETE)
+		if err != nil {
+			return err
+		}
+		act.SetWhere(andPolicyFilter(act.Where(), filter))
+	}
+	return nil
+}
+
+func (this *builder) selectPolicies(sel *algebra.Select) error {
+	if sel.PoliciesApplied() {
+		return nil
+	}
+	sel.SetPoliciesApplied()
+	err := this.subresultPolicies(sel.Subresult())
+	if err != nil {
+		return err
+	}
+	return this.subqueryPolicies(sel.Expressions())
+}
+
+func (this *builder) subqueryPolicies(exprs expression.Expressions) error {
+	subqueries, err := expression.ListSubqueries(exprs, false)
+	if err != nil {
+		return err
+	}
+	for _, s := range subqueries {
+		if subq, ok := s.(*algebra.Subquery); ok {
+			err = this.selectPolicies(subq.Select())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (this *builder) subresultPolicies(subresult algebra.Subresult) error {
+	switch node := subresult.(type) {
+	case *algebra.Subselect:
+		if node.From() == nil {
+			return nil
+		}
+		filter, err := this.fromPolicies(node.From())
+		if err != nil {
+			return err
+		}
+		node.SetWhere(andPolicyFilter(node.Where(), filter))
+		return nil
+	case *algebra.SelectTerm:
+		return this.selectPolicies(node.Select())
+	case interface {
+		First() algebra.Subresult
+		Second() algebra.Subresult
+	}:
+		err := this.subresultPolicies(node.First())
+		if err != nil {
+			return err
+		}
+		return this.subresultPolicies(node.Second())
+	}
+	return nil
+}
+
+// the filter returned applies to the WHERE clause of the subselect
+func (this *builder) fromPolicies(term algebra.FromTerm) (expression.Expression, error) {
+	switch term := term.(type) {
+	case *algebra.KeyspaceTerm:
+		return this.policyFilter(term.Path(), term.Alias(), policies.SELECT)
+	case *algebra.ExpressionTerm:
+		if ksterm := algebra.GetKeyspaceTerm(term); ksterm != nil {
+			return this.policyFilter(ksterm.Path(), ksterm.Alias(), policies.SELECT)
+		}
+	case *algebra.SubqueryTerm:
+		return nil, this.selectPolicies(term.Subquery())
+	case *algebra.AnsiJoin:
+		filter, err := this.fromPolicies(term.Left())
+		if err != nil {
+			return nil, err
+		}
+		onclause, err := this.joinPolicies(term.Right(), term.Onclause())
+		if err != nil {
+			return nil, err
+		}
+		term.SetOnclause(onclause)
+		return filter, nil
+	case *algebra.AnsiNest:
+		filter, err := this.fromPolicies(term.Left())
+		if err != nil {
+			return nil, err
+		}
+		onclause, err := this.joinPolicies(term.Right(), term.Onclause())
+		if err != nil {
+			return nil, err
+		}
+		term.SetOnclause(onclause)
+		return filter, nil
+	case *algebra.AsofJoin:
+		filter, err := this.fromPolicies(term.Left())
+		if err != nil {
+			return nil, err
+		}
+		onclause, err := this.joinPolicies(term.Right(), term.Onclause())
+		if err != nil {
+			return nil, err
+		}
+		term.SetOnclause(onclause)
+		return filter, nil
+	case *algebra.Join:
+		filter, err := this.fromPolicies(term.Left())
+		if err != nil {
+			return nil, err
+		}
+		if term.Outer() {
+			return nil, this.unsupportedPolicies(term.Right(), "LEFT OUTER JOIN ON KEYS")
+		}
+		right, err := this.policyFilter(term.Right().Path(), term.Right().Alias(), policies.SELECT)
+		if err != nil {
+			return nil, err
+		}
+		return andPolicyFilter(filter, right), nil
+	case *algebra.Nest:
+		filter, err := this.fromPolicies(term.Left())
+		if err != nil {
+			return nil, err
+		}
+		return filter, this.unsupportedPolicies(term.Right(), "NEST ON KEYS")
+	case *algebra.IndexJoin:
+		filter, err := this.fromPolicies(term.Left())
+		if err != nil {
+			return nil, err
+		}
+		return filter, this.unsupportedPolicies(term.Right(), "JOIN ON KEY ... FOR")
+	case *algebra.IndexNest:
+		filter, err := this.fromPolicies(term.Left())
+		if err != nil {
+			return nil, err
+		}
+		return filter, this.unsupportedPolicies(term.Right(), "NEST ON KEY ... FOR")
+	case algebra.JoinTerm:
+		return this.fromPolicies(term.Left())
+	}
+	return nil, nil
+}
+
+// the ON clause of an ANSI JOIN or NEST, with the policies of its right hand side
+func (this *builder) joinPolicies(right algebra.SimpleFromTerm, onclause expression.Expression) (
+	expression.Expression, error) {
+
+	if subq, ok := right.(*algebra.SubqueryTerm); ok {
+		return onclause, this.selectPolicies(subq.Subquery())
+	}
+	ksterm := algebra.GetKeyspaceTerm(right)
+	if ksterm == nil {
+		return onclause, nil
+	}
+	filter, err := this.policyFilter(ksterm.Path(), ksterm.Alias(), policies.SELECT)
+	if err != nil {
+		return nil, err
+	}
+	return andPolicyFilter(onclause, filter), nil
+}
+
+func (this *builder) unsupportedPolicies(term *algebra.KeyspaceTerm, operation string) error {
+	return unsupportedEventPolicies(term.Path(), policies.SELECT, operation)
+}
+
+// refuses an operation that cannot apply the policies of the keyspace for the event
+func unsupportedEventPolicies(path *algebra.Path, event string, operation string) error {
+	if path == nil || path.IsSystem() {
+		return nil
+	}
+	list, err := keyspacePolicies(path, event)
+	if err != nil {
+		return err
+	}
+	if len(list) > 0 {
+		return errors.NewPolicyError(errors.E_POLICY_UNSUPPORTED, path.SimpleString(), operation)
+	}
+	return nil
+}
+
+// whether the keyspace has policies for the event, assuming it has if they cannot be found out
+func hasPolicies(path *algebra.Path, event string) bool {
+	if path == nil || path.IsSystem() {
+		return false
+	}
+	list, err := keyspacePolicies(path, event)
+	return err != nil || len(list) > 0
+}
+
+// the policies of the keyspace for the event; a variable, so that tests can supply their own
+var keyspacePolicies = func(path *algebra.Path, event string) ([]*policies.Policy, errors.Error) {
+	ks, _ := datastore.GetKeyspace(path.Parts()...)
+	if ks == nil {
+		return nil, nil
+	}
+	return policies.KeyspacePolicies(ks, event)
+}
+
+/*
+The condition under which a document of the keyspace may be accessed for
+the event, or nil if the keyspace has no policies for it. A keyspace with
+policies for the event, none of which is granted to the user, yields no
+documents.
+*/
+func (this *builder) policyFilter(path *algebra.Path, alias string, event string) (expression.Expression, error) {
+	if path == nil || path.IsSystem() {
+		return nil, nil
+	}
+	list, err := keyspacePolicies(path, event)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	var filter expression.Expression
+	for _, p := range list {
+		def, err := p.Definition(parsePolicy)
+		if err != nil {
+			return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_DEFINITION, p.FullName(), err)
+		}
+		cond, err := expression.NewSelfFormalizer(alias, nil).Map(def.(expression.Expression).Copy())
+		if err != nil {
+			return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_DEFINITION, p.FullName(), err)
+		}
+		if !p.Public() {
+			cond = expression.NewAnd(grantedTo(p.Roles), cond)
+		}
+		if filter == nil {
+			filter = cond
+		} else {
+			filter = expression.NewOr(filter, cond)
+		}
+	}
+	return filter, nil
+}
+
+// whether any of the roles is granted to the user running the statement
+func grantedTo(roles []string) expression.Expression {
+	grantees := make(expression.Expressions, len(roles))
+	for i, r := range roles {
+		grantees[i] = expression.NewConstant(r)
+	}
+	return expression.NewArrayContainsAny(
+		expression.NewField(expression.NewCurrentUserInfo(), expression.NewFieldName("grants", false)),
+		expression.NewArrayConstruct(grantees...))
+}
+
+func parsePolicy(p *policies.Policy) (interface{}, error) {
+	return n1ql.ParseExpression(p.Predicate)
+}
+
+func andPolicyFilter(cond, filter expression.Expression) expression.Expression {
+	if filter == nil {
+		return cond
+	} else if cond == nil {
+		return filter
+	}
+	return expression.NewAnd(cond, filter)
+}
@@ -0,0 +1,293 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of the
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/policies"
+	"github.com/couchbase/query/views"
+)
+
+// Views, policies and materialized views are expanded and applied as Build does.
+func rewriteStatement(t *testing.T, s string) (algebra.Statement, error) {
+	t.Helper()
+	stmt := mustParseStatement(t, s)
+	builder := newRewriteBuilder()
+	err := builder.expandViews(stmt)
+	if err == nil {
+		err = builder.applyPolicies(stmt)
+	}
+	return stmt, err
+}
+
+// Replaces the policies kept in system collections by a list, matched on collection name.
+func withPolicies(t *testing.T, list ...*policies.Policy) {
+	saved := keyspacePolicies
+	keyspacePolicies = func(path *algebra.Path, event string) ([]*policies.Policy, errors.Error) {
+		var rv []*policies.Policy
+		for _, p := range list {
+			if p.Keyspace == path.Keyspace() && p.Event == event {
+				rv = append(rv, p)
+			}
+		}
+		return rv, nil
+	}
+	t.Cleanup(func() { keyspacePolicies = saved })
+}
+
+func tenantPolicy(event string) *policies.Policy {
+	return &policies.Policy{Name: "tenant_" + event, Keyspace: "orders", Event: event,
+		Roles: []string{"acme_reader"}, Predicate: "tenant = \"acme\""}
+}
+
+func TestSelectPolicies(t *testing.T) {
+	withPolicies(t, tenantPolicy(policies.SELECT))
+
+	stmt, err := rewriteStatement(t, "SELECT o.amount FROM default:b.s.orders AS o WHERE o.amount > 10")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	where := subselectOf(t, stmt).Where().String()
+	if !strings.Contains(where, "\"acme\"") || !strings.Contains(where, "acme_reader") ||
+		!strings.Contains(where, "10") {
+		t.Fatalf("Policy not added to the WHERE clause: %v", where)
+	}
+
+	// the policy of the right hand side of an ANSI JOIN goes into the ON clause
+	stmt, err = rewriteStatement(t, "SELECT c.name, o.amount FROM default:b.s.customers AS c "+
+		"JOIN default:b.s.orders AS o ON c.id = o.cid")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	node := subselectOf(t, stmt)
+	if node.Where() != nil {
+		t.Fatalf("Policy of the joined collection added to the WHERE clause: %v", node.Where())
+	}
+	join, ok := node.From().(*algebra.AnsiJoin)
+	if !ok || !strings.Contains(join.Onclause().String(), "\"acme\"") {
+		t.Fatalf("Policy not added to the ON clause: %v", node.From())
+	}
+
+	// subqueries are restricted too
+	stmt, err = rewriteStatement(t, "SELECT c.name FROM default:b.s.customers AS c "+
+		"WHERE c.id IN (SELECT RAW o.cid FROM default:b.s.orders AS o)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(subselectOf(t, stmt).Where().String(), "\"acme\"") {
+		t.Fatalf("Policy not added to the subquery: %v", subselectOf(t, stmt).Where())
+	}
+}
+
+func TestMutationPolicies(t *testing.T) {
+	withPolicies(t, tenantPolicy(policies.UPDATE), tenantPolicy(policies.DELETE))
+
+	stmt, err := rewriteStatement(t, "UPDATE default:b.s.orders AS o SET o.status = \"closed\" WHERE o.amount = 0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if where := stmt.(*algebra.Update).Where(); where == nil || !strings.Contains(where.String(), "\"acme\"") {
+		t.Fatalf("Policy not added to UPDATE: %v", where)
+	}
+
+	stmt, err = rewriteStatement(t, "DELETE FROM default:b.s.orders AS o")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if where := stmt.(*algebra.Delete).Where(); where == nil || !strings.Contains(where.String(), "\"acme\"") {
+		t.Fatalf("Policy not added to DELETE: %v", where)
+	}
+
+	// neither can be held to the policies of the documents they replace or remove
+	_, err = rewriteStatement(t, "UPSERT INTO default:b.s.orders (KEY, VALUE) VALUES (\"o1\", {\"amount\": 1})")
+	expectErrorCode(t, err, errors.E_POLICY_UNSUPPORTED)
+	_, err = rewriteStatement(t, "TRUNCATE default:b.s.orders")
+	expectErrorCode(t, err, errors.E_POLICY_UNSUPPORTED)
+
+	// INSERT only creates documents
+	_, err = rewriteStatement(t, "INSERT INTO default:b.s.orders (KEY, VALUE) VALUES (\"o1\", {\"amount\": 1})")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestMaterializedRewritePolicies(t *testing.T) {
+	withViews(t, map[string]*views.View{
+		"totals": {Text: "SELECT o.region, SUM(o.amount) AS total FROM default:b.s.orders AS o GROUP BY o.region",
+			Materialized: true, Rewrite: true},
+	})
+	query := "SELECT o.region, SUM(o.amount) AS total FROM default:b.s.orders AS o GROUP BY o.region"
+	source := func(t *testing.T, stmt algebra.Statement) string {
+		term, ok := subselectOf(t, stmt).From().(*algebra.KeyspaceTerm)
+		if !ok {
+			t.Fatalf("Expected a keyspace, found %v", subselectOf(t, stmt).From())
+		}
+		return term.Path().Keyspace()
+	}
+
+	stmt, err := rewriteStatement(t, query)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ks := source(t, stmt); ks != "totals" {
+		t.Fatalf("Expected the query to read the view, found %v", ks)
+	}
+
+	// the rows of the view would hold every tenant's
+	for _, p := range []*policies.Policy{tenantPolicy(policies.SELECT),
+		{Name: "emails", Keyspace: "orders", Event: policies.MASK, Column: "email", Predicate: "\"***\""}} {
+
+		t.Run(p.Event, func(t *testing.T) {
+			withPolicies(t, p)
+			stmt, err := rewriteStatement(t, query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ks := source(t, stmt); ks != "orders" {
+				t.Fatalf("Query rewritten to read %v despite the policies of the collection", ks)
+			}
+		})
+	}
+	stmt, _ = rewriteStatement(t, query)
+	if ks := source(t, stmt); ks != "totals" {
+		t.Fatalf("Expected the query to read the view once the policies are gone, found %v", ks)
+	}
+}
+
+func TestPolicyFilters(t *testing.T) {
+	withPolicies(t, tenantPolicy(policies.SELECT),
+		&policies.Policy{Name: "public", Keyspace: "orders", Event: policies.SELECT, Predicate: "amount < 100"})
+
+	// the policies of a collection are ORed, those granted to roles checked against the user
+	stmt, err := rewriteStatement(t, "SELECT o.amount FROM default:b.s.orders AS o")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	where := subselectOf(t, stmt).Where().String()
+	if where != "((array_contains_any((current_user_info().`grants`), [\"acme_reader\"]) and "+
+		"((`o`.`tenant`) = \"acme\")) or ((`o`.`amount`) < 100))" {
+		t.Fatalf("Unexpected policy filter %v", where)
+	}
+
+	// policies are applied once, however often the statement is rewritten
+	if err = newRewriteBuilder().applyPolicies(stmt); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if again := subselectOf(t, stmt).Where().String(); again != where {
+		t.Fatalf("Policies applied twice: %v", again)
+	}
+
+	// every query block of a set operation, and the statement being explained, are restricted
+	for _, s := range []string{
+		"SELECT o.amount FROM default:b.s.orders AS o UNION SELECT o.amount FROM default:b.s.orders AS o",
+		"EXPLAIN SELECT o.amount FROM default:b.s.orders AS o",
+	} {
+		stmt, err = rewriteStatement(t, s)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if n := strings.Count(stmt.String(), "acme_reader"); n != strings.Count(s, "SELECT") {
+			t.Errorf("Expected %q to be restricted in each query block, found %v", s, stmt)
+		}
+	}
+
+	// inner joins ON KEYS are restricted in the WHERE clause
+	stmt, err = rewriteStatement(t, "SELECT c.name FROM default:b.s.customers AS c JOIN default:b.s.orders AS o ON KEYS c.oid")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if where := subselectOf(t, stmt).Where(); where == nil || !strings.Contains(where.String(), "(`o`.`tenant`)") {
+		t.Fatalf("Policy not added to the WHERE clause: %v", where)
+	}
+	for _, s := range []string{
+		"SELECT c.name FROM default:b.s.customers AS c LEFT JOIN default:b.s.orders AS o ON KEYS c.oid",
+		"SELECT c.name FROM default:b.s.customers AS c NEST default:b.s.orders AS o ON KEYS c.oids",
+	} {
+		_, err = rewriteStatement(t, s)
+		expectErrorCode(t, err, errors.E_POLICY_UNSUPPORTED)
+	}
+
+	// views are restricted by the policies of the collections they read
+	withViews(t, map[string]*views.View{"big": {Text: "SELECT x.id, x.amount FROM default:b.s.orders AS x WHERE x.amount > 50"}})
+	stmt, err = rewriteStatement(t, "SELECT v.id FROM default:b.s.big AS v")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body := subselectOf(t, viewTerm(t, subselectOf(t, stmt).From()).Subquery())
+	if where := body.Where().String(); !strings.Contains(where, "(`x`.`tenant`)") {
+		t.Fatalf("Policy not added to the definition of the view: %v", where)
+	}
+}
+
+func TestMergePolicies(t *testing.T) {
+	withPolicies(t, tenantPolicy(policies.UPDATE), tenantPolicy(policies.DELETE))
+
+	stmt, err := rewriteStatement(t, "MERGE INTO default:b.s.orders AS o USING [{\"id\": \"o1\"}] AS s ON o.id = s.id "+
+		"WHEN MATCHED THEN UPDATE SET o.status = \"closed\" WHEN MATCHED THEN DELETE WHERE o.amount = 0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	actions := stmt.(*algebra.Merge).Actions()
+	if where := actions.Update().Where(); where == nil || !strings.Contains(where.String(), "(`o`.`tenant`)") {
+		t.Fatalf("Policy not added to the UPDATE action: %v", where)
+	}
+	if where := actions.Delete().Where().String(); !strings.Contains(where, "(`o`.`amount`) = 0") ||
+		!strings.Contains(where, "(`o`.`tenant`)") {
+		t.Fatalf("Policy not added to the DELETE action: %v", where)
+	}
+
+	// a source read without a query block cannot be filtered
+	withPolicies(t, tenantPolicy(policies.SELECT))
+	_, err = rewriteStatement(t, "MERGE INTO default:b.s.archive AS a USING default:b.s.orders AS o ON a.id = o.id "+
+		"WHEN NOT MATCHED THEN INSERT (KEY o.id, VALUE o)")
+	expectErrorCode(t, err, errors.E_POLICY_UNSUPPORTED)
+	stmt, err = rewriteStatement(t, "MERGE INTO default:b.s.archive AS a USING (SELECT o.* FROM default:b.s.orders AS o) AS s "+
+		"ON a.id = s.id WHEN NOT MATCHED THEN INSERT (KEY s.id, VALUE s)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s := stmt.String(); !strings.Contains(s, "acme_reader") {
+		t.Fatalf("Policy not added to the MERGE source: %v", s)
+	}
+
+	// a predicate that does not parse is reported, naming the policy
+	withPolicies(t, &policies.Policy{Name: "broken", Keyspace: "orders", Event: policies.SELECT, Predicate: "tenant ="})
+	_, err = rewriteStatement(t, "SELECT 1 FROM default:b.s.orders AS o")
+	expectErrorCode(t, err, errors.E_POLICY_INVALID_DEFINITION)
+}
+
+func TestPolicyPlan(t *testing.T) {
+	withMockDatastore(t)
+	withPolicies(t, &policies.Policy{Name: "tenant", Keyspace: "b0", Event: policies.SELECT,
+		Roles: []string{"acme_reader"}, Predicate: "tenant = \"acme\""})
+	n1ql.SetNamespaces(map[string]interface{}{"p0": true})
+
+	// the filter restricts the documents read, and the plan holds the roles it was made for
+	stmt, err := n1ql.ParseStatement2("SELECT b.id FROM p0:b0 AS b WHERE b.id > 5", "p0", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	qp, _, err, _ := Build(stmt, datastore.GetDatastore(), datastore.GetSystemstore(), "p0", false, false, false,
+		&PrepareContext{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	bytes, _ := json.Marshal(qp.PlanOp())
+	if s := string(bytes); !strings.Contains(s, `"#operator":"Filter"`) || !strings.Contains(s, "acme_reader") ||
+		!strings.Contains(s, "(`b`.`tenant`)") {
+		t.Fatalf("Expected the policy to filter the documents read, found %v", s)
+	}
+}
@@ -26,8 +26,8 @@ func (this *builder) buildCovering(indexes, unnestIndexes, flex map[datastore.In
 	searchSargables []*indexEntry, unnests []*algebra.Unnest) (
 	scan plan.SecondaryScan, sargLength int, err error) {
 
-	// covering turrned off or ANSI NEST, or system keyspace
-	if this.cover == nil || node.IsAnsiNest() || baseKeyspace.IsSystem() {
+	// covering turrned off or ANSI NEST, or system keyspace, or masked by a policy
+	if this.cover == nil || node.IsAnsiNest() || baseKeyspace.IsSystem() || node.IsMasked() {
 		return
 	}
 
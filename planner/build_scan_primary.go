@@ -58,7 +58,8 @@ func (this *builder) buildPrimaryScan(keyspace datastore.Keyspace, node *algebra
 		size := OPT_SIZE_NOT_AVAIL
 		frCost := OPT_COST_NOT_AVAIL
 		if this.useCBO && this.keyspaceUseCBO(node.Alias()) {
-			cost, cardinality, size, frCost = primaryIndexScanCost(primary, this.context.RequestId(), this.context)
+			cost, cardinality, size, frCost = primaryIndexScanCost(primary, baseKeyspace.Keyspace(), this.context.RequestId(),
+				this.context)
 		}
 
 		skipNewKeys := false
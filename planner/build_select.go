@@ -36,6 +36,7 @@ func (this *builder) VisitSelect(stmt *algebra.Select) (interface{}, error) {
 	prevInclWith := stmt.IncludeWith()
 	prevAliases := this.aliases
 	prevSetOpDistinct := this.setOpDistinct
+	prevWithBuffers := this.withBuffers
 
 	defer func() {
 		this.node = prevNode
@@ -50,6 +51,7 @@ func (this *builder) VisitSelect(stmt *algebra.Select) (interface{}, error) {
 		stmt.SetIncludeWith(prevInclWith)
 		this.aliases = prevAliases
 		this.setOpDistinct = prevSetOpDistinct
+		this.withBuffers = prevWithBuffers
 	}()
 
 	// Since this is the root Select being planned - disinclude its With expressions from cover transformation
@@ -90,6 +92,10 @@ func (this *builder) VisitSelect(stmt *algebra.Select) (interface{}, error) {
 		this.cover = stmt
 	}
 
+	this.inlineWiths(stmt)
+	this.withBuffers = nil
+	buffered := this.bufferWiths(stmt)
+
 	qp := plan.NewQueryPlan(nil)
 	err = this.chkBldSubqueries(stmt, qp)
 	if err != nil {
@@ -121,7 +127,9 @@ func (this *builder) VisitSelect(stmt *algebra.Select) (interface{}, error) {
 		if this.useCBO {
 			cost, cardinality, size, frCost = getWithCost(subOp, with.Bindings())
 		}
-		subOp = plan.NewWith(with, subOp, cost, cardinality, size, frCost)
+		withOp := plan.NewWith(with, subOp, cost, cardinality, size, frCost)
+		withOp.SetBuffered(buffered)
+		subOp = withOp
 
 		this.aliases = make(map[string]bool, len(with.Bindings()))
 		for _, w := range with.Bindings() {
@@ -336,6 +344,8 @@ func addFromSubqueries(qp *plan.QueryPlan, optimHints *algebra.OptimHints, ops .
 			addFromSubqueries(qp, optimHints, op.Child())
 		case *plan.HashJoin:
 			addFromSubqueries(qp, optimHints, op.Child())
+		case *plan.AsofJoin:
+			addFromSubqueries(qp, optimHints, op.Child())
 		case *plan.HashNest:
 			addFromSubqueries(qp, optimHints, op.Child())
 		case *plan.UnionAll:
@@ -372,6 +382,8 @@ func (this *builder) coverFromSubqueries(ops ...plan.Operator) (err error) {
 			err = this.coverFromSubqueries(op.Child())
 		case *plan.HashJoin:
 			err = this.coverFromSubqueries(op.Child())
+		case *plan.AsofJoin:
+			err = this.coverFromSubqueries(op.Child())
 		case *plan.HashNest:
 			err = this.coverFromSubqueries(op.Child())
 		case *plan.UnionAll:
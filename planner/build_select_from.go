@@ -18,10 +18,12 @@ import (
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/expression"
 	"github.com/couchbase/query/extparams"
+	"github.com/couchbase/query/parser/n1ql"
 	"github.com/couchbase/query/plan"
 	base "github.com/couchbase/query/plannerbase"
 	"github.com/couchbase/query/util"
 	"github.com/couchbase/query/value"
+	"github.com/couchbase/query/views"
 )
 
 const _MAX_EARLY_PROJECTION = 15
@@ -43,6 +45,15 @@ func (this *builder) visitFrom(node *algebra.Subselect, group *algebra.Group,
 		this.maxParallelism = 1
 		this.resetPushDowns()
 	} else if node.From() != nil {
+		if this.useCBO && !this.indexAdvisor {
+			if from := optReorderJoins(node, this.context); from != nil {
+				// plan the reordered joins, leaving the statement as it was written
+				origFrom := node.From()
+				node.SetFrom(from)
+				defer node.SetFrom(origFrom)
+			}
+		}
+
 		prevFrom := this.from
 		this.from = node.From()
 		defer func() { this.from = prevFrom }()
@@ -191,7 +202,7 @@ func (this *builder) visitFrom(node *algebra.Subselect, group *algebra.Group,
 
 		orderedHint := hasOrderedHint(node.OptimHints())
 		if this.useCBO && !this.indexAdvisor && this.context.Optimizer() != nil &&
-			!orderedHint && len(this.baseKeyspaces) > 1 &&
+			!orderedHint && len(this.baseKeyspaces) > 1 && !hasAsofJoin(node.From()) &&
 			util.IsFeatureEnabled(this.context.FeatureControls(), util.N1QL_JOIN_ENUMERATION) {
 			var limit, offset expression.Expression
 			var order *algebra.Order
@@ -669,6 +680,10 @@ func (this *builder) VisitSubqueryTerm(node *algebra.SubqueryTerm) (interface{},
 			subqInJoinEnum = this.setSubqInJoinEnum()
 		}
 		subquery := node.Subquery()
+		if (node.View() != nil || node.IsInlinedWith()) && !node.ViewPushed() {
+			node.SetViewPushed()
+			pushViewFilters(subquery, baseKeyspace)
+		}
 		qp, err := subquery.Accept(this)
 		this.subquery = sa
 		if this.hasBuilderFlag(BUILDER_NL_INNER) {
@@ -779,8 +794,12 @@ func (this *builder) VisitExpressionTerm(node *algebra.ExpressionTerm) (interfac
 			}
 		}
 
-		this.addChildren(plan.NewExpressionScan(node.ExpressionTerm(), alias, node.IsCorrelated(),
-			this.hasBuilderFlag(BUILDER_NL_INNER), filter, cost, cardinality, size, frCost))
+		exprScan := plan.NewExpressionScan(node.ExpressionTerm(), alias, node.IsCorrelated(),
+			this.hasBuilderFlag(BUILDER_NL_INNER), filter, cost, cardinality, size, frCost)
+		if n, ok := this.withBuffers[node]; ok {
+			exprScan.SetBuffer(n)
+		}
+		this.addChildren(exprScan)
 	}
 
 	if !this.joinEnum() && !node.IsAnsiJoinOp() {
@@ -1903,3 +1922,352 @@ func fieldPathSegments(expr expression.Expression, alias string) (segments []str
 	}
 	return strings.Split(path, "."), true
 }
+
+/*
+Views are expanded before the statement is planned, so that privileges
+are checked against the keyspaces a view reads from. A keyspace term that
+does not name a keyspace but a view is replaced by a subquery term over
+the view definition, which is then planned as any other derived table.
+*/
+func (this *builder) expandViews(stmt algebra.Statement) error {
+	switch stmt := stmt.(type) {
+	case *algebra.Select:
+		return this.expandSelectViews(stmt, nil)
+	case *algebra.Insert:
+		if stmt.Select() != nil {
+			if err := this.expandSelectViews(stmt.Select(), insertTarget(stmt.KeyspaceRef())); err != nil {
+				return err
+			}
+		}
+	case *algebra.Upsert:
+		if stmt.Select() != nil {
+			if err := this.expandSelectViews(stmt.Select(), insertTarget(stmt.KeyspaceRef())); err != nil {
+				return err
+			}
+		}
+	case *algebra.Explain:
+		return this.expandViews(stmt.Statement())
+	case *algebra.Advise:
+		return this.expandViews(stmt.Statement())
+	case *algebra.Prepare:
+		return this.expandViews(stmt.Statement())
+	}
+	return this.expandSubqueryViews(stmt.Expressions(), nil)
+}
+
+// a materialized view is not read while it is being populated
+func insertTarget(ksref *algebra.KeyspaceRef) []string {
+	if ksref.Path() == nil {
+		return nil
+	}
+	return []string{ksref.Path().FullName()}
+}
+
+func (this *builder) expandSelectViews(sel *algebra.Select, expanding []string) error {
+	err := this.expandSubresultViews(sel.Subresult(), sel.Order(), expanding)
+	if err != nil {
+		return err
+	}
+	return this.expandSubqueryViews(sel.Expressions(), expanding)
+}
+
+func (this *builder) expandSubqueryViews(exprs expression.Expressions, expanding []string) error {
+	subqueries, err := expression.ListSubqueries(exprs, false)
+	if err != nil {
+		return err
+	}
+	for _, s := range subqueries {
+		if subq, ok := s.(*algebra.Subquery); ok {
+			err = this.expandSelectViews(subq.Select(), expanding)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (this *builder) expandSubresultViews(subresult algebra.Subresult, order *algebra.Order, expanding []string) error {
+	switch node := subresult.(type) {
+	case *algebra.Subselect:
+		if node.From() == nil {
+			return nil
+		}
+		from, err := algebra.ReplaceViewTerms(node.From(), func(term *algebra.KeyspaceTerm) (algebra.SimpleFromTerm, error) {
+			return this.expandView(term, node, order, expanding)
+		})
+		if err != nil {
+			return err
+		}
+		node.SetFrom(from)
+		err = this.expandDerivedViews(from, expanding)
+		if err != nil {
+			return err
+		}
+		this.rewriteMaterialized(node, order, expanding)
+		return nil
+	case *algebra.SelectTerm:
+		return this.expandSelectViews(node.Select(), expanding)
+	case interface {
+		First() algebra.Subresult
+		Second() algebra.Subresult
+	}:
+		err := this.expandSubresultViews(node.First(), nil, expanding)
+		if err != nil {
+			return err
+		}
+		return this.expandSubresultViews(node.Second(), nil, expanding)
+	}
+	return nil
+}
+
+// views referenced in derived tables written in the query
+func (this *builder) expandDerivedViews(term algebra.FromTerm, expanding []string) error {
+	switch term := term.(type) {
+	case *algebra.SubqueryTerm:
+		if term.View() == nil {
+			return this.expandSubresultViews(term.Subquery().Subresult(), term.Subquery().Order(), expanding)
+		}
+	case *algebra.AnsiJoin:
+		err := this.expandDerivedViews(term.Left(), expanding)
+		if err != nil {
+			return err
+		}
+		return this.expandDerivedViews(term.Right(), expanding)
+	case *algebra.AsofJoin:
+		err := this.expandDerivedViews(term.Left(), expanding)
+		if err != nil {
+			return err
+		}
+		return this.expandDerivedViews(term.Right(), expanding)
+	case *algebra.AnsiNest:
+		err := this.expandDerivedViews(term.Left(), expanding)
+		if err != nil {
+			return err
+		}
+		return this.expandDerivedViews(term.Right(), expanding)
+	case algebra.JoinTerm:
+		return this.expandDerivedViews(term.Left(), expanding)
+	}
+	return nil
+}
+
+func (this *builder) expandView(term *algebra.KeyspaceTerm, node *algebra.Subselect, order *algebra.Order,
+	expanding []string) (algebra.SimpleFromTerm, error) {
+
+	path := term.Path()
+	if path == nil || !path.IsCollection() || path.IsSystem() {
+		return term, nil
+	}
+	if _, err := datastore.GetKeyspace(path.Parts()...); err == nil {
+		return term, nil
+	}
+	view, verr := getView(path)
+	if verr != nil || view == nil {
+		return term, verr
+	}
+
+	name := path.SimpleString()
+	for _, v := range expanding {
+		if v == name {
+			return nil, errors.NewViewError(errors.E_VIEW_RECURSIVE, name)
+		}
+	}
+	if term.Keys() != nil || term.Indexes() != nil {
+		return nil, errors.NewViewError(errors.E_VIEW_USE_KEYS_INDEX, name)
+	}
+
+	stmt, err := n1ql.ParseStatement2(view.Text, path.Namespace(), view.QueryContext)
+	if err != nil {
+		return nil, errors.NewViewError(errors.E_VIEW_INVALID_DEFINITION, name, err)
+	}
+	body, ok := stmt.(*algebra.Select)
+	if !ok || body.ParamsCount() > 0 {
+		return nil, errors.NewViewError(errors.E_VIEW_INVALID_DEFINITION, name)
+	}
+
+	expanding = append(expanding, name)
+	err = this.expandSelectViews(body, expanding)
+	if err != nil {
+		return nil, err
+	}
+
+	pruneViewProjection(body, term.Alias(), node, order)
+	return algebra.NewViewTerm(term, body), nil
+}
+
+// the view stored under a path; a variable, so that tests can supply their own
+var getView = views.GetView
+
+/*
+A view can take predicates and lose unused projection terms when its
+definition is a single query block producing one row per row read.
+*/
+func simpleView(body *algebra.Select) *algebra.Subselect {
+	if body.Order() != nil || body.Limit() != nil || body.Offset() != nil {
+		return nil
+	}
+	node, ok := body.Subresult().(*algebra.Subselect)
+	if !ok || node.Group() != nil || node.Window() != nil {
+		return nil
+	}
+	projection := node.Projection()
+	if projection.Distinct() || projection.Raw() || len(projection.Exclude()) > 0 {
+		return nil
+	}
+	for _, term := range projection.Terms() {
+		if term.Star() {
+			return nil
+		}
+	}
+	aggs, windowAggs, err := allAggregates(node, nil)
+	if err != nil || len(aggs) > 0 || len(windowAggs) > 0 {
+		return nil
+	}
+	return node
+}
+
+/*
+Drop the projection terms of a view that the enclosing query block never
+references. Any reference to the view alias other than a plain field,
+including from correlated subqueries, leaves the projection as it is.
+*/
+func pruneViewProjection(body *algebra.Select, alias string, node *algebra.Subselect, order *algebra.Order) {
+	inner := simpleView(body)
+	if inner == nil {
+		return
+	}
+
+	exprs := node.Expressions()
+	if order != nil {
+		exprs = append(exprs, order.Expressions()...)
+	}
+	for _, term := range node.Projection().Terms() {
+		if term.Star() && term.Expression() == nil {
+			return
+		}
+	}
+	refs := make(map[string]bool)
+	for _, expr := range exprs {
+		if !viewReferences(expr, alias, refs) {
+			return
+		}
+	}
+
+	terms := inner.Projection().Terms()
+	pruned := make(algebra.ResultTerms, 0, len(refs))
+	for _, term := range terms {
+		if refs[term.Alias()] {
+			pruned = append(pruned, algebra.NewResultTerm(term.Expression(), false, term.Alias()))
+		}
+	}
+	if len(pruned) == len(terms) {
+		return
+	} else if len(pruned) == 0 {
+		pruned = append(pruned, algebra.NewResultTerm(terms[0].Expression(), false, terms[0].Alias()))
+	}
+	inner.SetProjection(algebra.NewProjection(false, pruned, nil))
+}
+
+func viewReferences(expr expression.Expression, alias string, refs map[string]bool) bool {
+	switch expr := expr.(type) {
+	case *expression.Field:
+		if id, ok := expr.First().(*expression.Identifier); ok && id.Identifier() == alias {
+			name, ok := expr.Second().(*expression.FieldName)
+			if !ok || expr.CaseInsensitive() {
+				return false
+			}
+			refs[name.Alias()] = true
+			return true
+		}
+	case *expression.Identifier:
+		return expr.Identifier() != alias
+	case *expression.Self:
+		// SELECT * and SELECT SELF use the whole of the view
+		return false
+	case *algebra.Subquery:
+		if expr.IsCorrelated() {
+			return false
+		}
+	}
+	for _, child := range expr.Children() {
+		if !viewReferences(child, alias, refs) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Push the predicates on a view, or on an inlined CTE, into its definition,
+so that they can be used for index selection within the definition. Predicates are copied, not
+moved: they are still applied to the output of the view.
+*/
+func pushViewFilters(body *algebra.Select, baseKeyspace *base.BaseKeyspace) {
+	inner := simpleView(body)
+	if inner == nil {
+		return
+	}
+
+	terms := make(map[string]expression.Expression, len(inner.Projection().Terms()))
+	for _, term := range inner.Projection().Terms() {
+		terms[term.Alias()] = term.Expression()
+	}
+
+	alias := baseKeyspace.Name()
+	var pushed expression.Expressions
+	for _, fl := range baseKeyspace.Filters() {
+		// WHERE clause predicates on the inner side of an outer join are
+		// applied after the join, and cannot be evaluated before it
+		if fl.IsJoin() || fl.HasSubq() || fl.HasVolatileExpr() || (baseKeyspace.IsOuter() && !fl.IsOnclause()) {
+			continue
+		}
+		expr, ok := mapViewFilter(fl.FltrExpr().Copy(), alias, terms)
+		if ok {
+			pushed = append(pushed, expr)
+		}
+	}
+	if len(pushed) == 0 {
+		return
+	}
+	if inner.Where() != nil {
+		pushed = append(expression.Expressions{inner.Where()}, pushed...)
+	}
+	if len(pushed) == 1 {
+		inner.SetWhere(pushed[0])
+	} else {
+		inner.SetWhere(expression.NewAnd(pushed...))
+	}
+}
+
+// replace the references to the view fields with their definitions
+func mapViewFilter(expr expression.Expression, alias string, terms map[string]expression.Expression) (
+	expression.Expression, bool) {
+
+	switch e := expr.(type) {
+	case *expression.Field:
+		if id, ok := e.First().(*expression.Identifier); ok && id.Identifier() == alias {
+			name, ok := e.Second().(*expression.FieldName)
+			if !ok || e.CaseInsensitive() {
+				return nil, false
+			}
+			def, ok := terms[name.Alias()]
+			if !ok || def.HasVolatileExpr() {
+				return nil, false
+			}
+			return def.Copy(), true
+		}
+	case *expression.Identifier, *expression.Self:
+		return nil, false
+	}
+
+	children := expr.Children()
+	for i, child := range children {
+		mapped, ok := mapViewFilter(child, alias, terms)
+		if !ok {
+			return nil, false
+		}
+		children[i] = mapped
+	}
+	return expr, true
+}
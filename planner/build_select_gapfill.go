@@ -0,0 +1,102 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/plan"
+)
+
+/*
+GROUP BY ... GAPFILL adds groups after the final grouping, with no
+documents to evaluate the group keys or the LOCF() and INTERPOLATE()
+calls of the projection, LETTING, HAVING and ORDER BY over. These are
+replaced by covers, which the GapFill operator sets on every group.
+*/
+func (this *builder) buildGapFill(node *algebra.Subselect, group *algebra.Group, aggs algebra.Aggregates) (
+	*plan.GapFill, error) {
+
+	bucket := -1
+	for i, key := range group.By() {
+		if cover, ok := key.(*expression.Cover); ok {
+			key = cover.Covered()
+		}
+		fn, ok := key.(*expression.TimeBucket)
+		if !ok {
+			continue
+		}
+		if bucket >= 0 {
+			return nil, errors.NewGapFillGroupError("only one GROUP BY term can be a TIME_BUCKET() call")
+		}
+		for j, op := range fn.Operands() {
+			if j != 1 && op.Static() == nil {
+				return nil, errors.NewGapFillGroupError("the TIME_BUCKET() interval and origin must not depend on the documents")
+			}
+		}
+		bucket = i
+	}
+	if bucket < 0 {
+		return nil, errors.NewGapFillGroupError("one GROUP BY term must be a TIME_BUCKET() call")
+	}
+
+	mapper := newGapFillMapper(group.By(), group.Letting())
+	err := node.Projection().MapExpressions(mapper)
+	if err == nil {
+		err = group.MapAggregateExpressions(mapper)
+	}
+	if err == nil && this.order != nil {
+		err = this.order.MapExpressions(mapper)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gapfill := group.GapFill()
+	return plan.NewGapFill(group.By(), bucket, mapper.fills, sortAggregatesSlice(aggs), gapfill.Start(), gapfill.End(),
+		OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL), nil
+}
+
+type gapFillMapper struct {
+	expression.MapperBase
+
+	fills expression.Expressions
+}
+
+func newGapFillMapper(keys expression.Expressions, letting expression.Bindings) *gapFillMapper {
+	rv := &gapFillMapper{}
+	rv.SetMapFunc(func(expr expression.Expression) (expression.Expression, error) {
+		switch expr.(type) {
+		case algebra.Aggregate:
+			return expr, nil
+		case *expression.Locf, *expression.Interpolate:
+			if dependsOnLet(expr, letting) {
+				return nil, errors.NewGapFillGroupError("the operand of " + expr.String() +
+					" cannot refer to LETTING variables")
+			}
+			for _, fill := range rv.fills {
+				if fill.EquivalentTo(expr) {
+					return expression.NewCover(fill), nil
+				}
+			}
+			rv.fills = append(rv.fills, expr)
+			return expression.NewCover(expr), nil
+		}
+
+		for _, key := range keys {
+			if key.EquivalentTo(expr) {
+				return expression.NewCover(key), nil
+			}
+		}
+		return expr, expr.MapChildren(rv)
+	})
+	rv.SetMapper(rv)
+	return rv
+}
@@ -0,0 +1,316 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/policies"
+	"github.com/couchbase/query/views"
+)
+
+/*
+A query block that groups a collection the same way as a materialized
+view created WITH {"rewrite": true} is rewritten to read the rows of the
+view instead: the view must read the same collection, its WHERE clause
+must be implied by conjuncts of the query's, and the GROUP BY keys must
+be the same. What the query computes from the groups, and the remaining
+conjuncts of its WHERE clause, must then be expressible on the projected
+fields of the view.
+
+The view is used as of its last refresh. Only views in the scope of the
+collection are considered, and only if the requester can read them; the
+privileges on the collection are still checked. The rows of a view are
+the same for every user, so a collection with row-level security or
+masking policies is never rewritten: its policies could not be applied.
+*/
+func (this *builder) rewriteMaterialized(node *algebra.Subselect, order *algebra.Order, expanding []string) {
+	term, ok := node.From().(*algebra.KeyspaceTerm)
+	if !ok || term.Path() == nil || !term.Path().IsCollection() || term.Path().IsSystem() ||
+		term.Keys() != nil || term.Indexes() != nil || term.HasSnapshotOption() {
+		return
+	}
+	group := node.Group()
+	if group == nil || len(group.By()) == 0 || group.Letting() != nil || group.GroupAs() != "" ||
+		node.Let() != nil || node.Window() != nil {
+		return
+	}
+	projection := node.Projection()
+	if projection.Raw() || len(projection.Exclude()) > 0 {
+		return
+	}
+	for _, t := range projection.Terms() {
+		if t.Star() {
+			return
+		}
+	}
+	exprs := node.Expressions()
+	if order != nil {
+		exprs = append(exprs, order.Expressions()...)
+	}
+	if subqueries, err := expression.ListSubqueries(exprs, false); err != nil || len(subqueries) > 0 {
+		return
+	}
+
+	if hasPolicies(term.Path(), policies.SELECT) || hasPolicies(term.Path(), policies.MASK) {
+		return
+	}
+
+	for _, mv := range rewriteViews(term.Path().ScopePath()) {
+		if this.rewriteToView(node, order, term, mv, expanding) {
+			return
+		}
+	}
+}
+
+// the materialized views of a scope that queries may be rewritten to; a variable, so that tests can supply their own
+var rewriteViews = views.RewriteViews
+
+func (this *builder) rewriteToView(node *algebra.Subselect, order *algebra.Order, term *algebra.KeyspaceTerm,
+	mv *views.MaterializedView, expanding []string) bool {
+
+	name := mv.Path.FullName()
+	for _, v := range expanding {
+		if v == name {
+			return false
+		}
+	}
+	stmt, err := n1ql.ParseStatement2(mv.View.Text, mv.Path.Namespace(), mv.View.QueryContext)
+	if err != nil {
+		return false
+	}
+	body, ok := stmt.(*algebra.Select)
+	if !ok || body.ParamsCount() > 0 {
+		return false
+	}
+	agg, _ := views.NewAggregateView(body)
+	if agg == nil || agg.Term.Path().FullName() != term.Path().FullName() {
+		return false
+	}
+
+	// the definition of the view, in terms of the query's alias
+	alias := term.Alias()
+	targets := make(expression.Expressions, len(agg.Terms))
+	for i, t := range agg.Terms {
+		targets[i] = renameAlias(t.Expression(), agg.Term.Alias(), alias)
+	}
+
+	keys := node.Group().By()
+	if len(keys) != len(agg.Keys) {
+		return false
+	}
+	for _, key := range agg.Keys {
+		if !containsEquivalent(keys, renameAlias(key, agg.Term.Alias(), alias)) {
+			return false
+		}
+	}
+	for _, key := range keys {
+		found := false
+		for _, vkey := range agg.Keys {
+			if key.EquivalentTo(renameAlias(vkey, agg.Term.Alias(), alias)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	var conjuncts expression.Expressions
+	if node.Where() != nil {
+		conjuncts = splitConjuncts(node.Where())
+	}
+	var residual expression.Expressions
+	if agg.Where != nil {
+		used := make([]bool, len(conjuncts))
+		for _, vc := range splitConjuncts(renameAlias(agg.Where, agg.Term.Alias(), alias)) {
+			found := false
+			for i, qc := range conjuncts {
+				if !used[i] && qc.EquivalentTo(vc) {
+					used[i] = true
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		for i, qc := range conjuncts {
+			if !used[i] {
+				residual = append(residual, qc)
+			}
+		}
+	} else {
+		residual = conjuncts
+	}
+
+	// every expression of the query block must map onto the view's fields
+	mapper := newViewMapper(alias, targets, agg.Terms)
+	check := append(expression.Expressions{}, residual...)
+	if having := node.Group().Having(); having != nil {
+		check = append(check, having)
+	}
+	for _, t := range node.Projection().Terms() {
+		check = append(check, t.Expression())
+	}
+	if order != nil {
+		check = append(check, order.Expressions()...)
+	}
+	for _, expr := range check {
+		mapped, err := mapper.Map(expr.Copy())
+		if err != nil || !mapper.mapped(mapped) {
+			return false
+		}
+	}
+
+	creds := this.context.Credentials()
+	if creds != nil {
+		privs := auth.NewPrivileges()
+		privs.Add(mv.Path.FullName(), auth.PRIV_QUERY_SELECT, auth.PRIV_PROPS_NONE)
+		if this.datastore.Authorize(privs, creds) != nil {
+			return false
+		}
+	}
+
+	where := make(expression.Expressions, 0, len(residual)+1)
+	for _, expr := range residual {
+		mapped, err := mapper.Map(expr)
+		if err != nil {
+			return false
+		}
+		where = append(where, mapped)
+	}
+	if having := node.Group().Having(); having != nil {
+		mapped, err := mapper.Map(having)
+		if err != nil {
+			return false
+		}
+		where = append(where, mapped)
+	}
+	if node.Projection().MapExpressions(mapper) != nil {
+		return false
+	}
+	if order != nil && order.MapExpressions(mapper) != nil {
+		return false
+	}
+
+	switch len(where) {
+	case 0:
+		node.SetWhere(nil)
+	case 1:
+		node.SetWhere(where[0])
+	default:
+		node.SetWhere(expression.NewAnd(where...))
+	}
+	node.SetGroup(nil)
+	node.SetFrom(algebra.NewKeyspaceTermFromPath(mv.Path, alias, nil, nil))
+	this.viewSources = append(this.viewSources, term.Path().FullName())
+	return true
+}
+
+func splitConjuncts(expr expression.Expression) expression.Expressions {
+	if and, ok := expr.(*expression.And); ok {
+		var rv expression.Expressions
+		for _, op := range and.Operands() {
+			rv = append(rv, splitConjuncts(op)...)
+		}
+		return rv
+	}
+	return expression.Expressions{expr}
+}
+
+func containsEquivalent(exprs expression.Expressions, expr expression.Expression) bool {
+	for _, e := range exprs {
+		if e.EquivalentTo(expr) {
+			return true
+		}
+	}
+	return false
+}
+
+// a copy of the expression, with references to one keyspace alias made to another
+func renameAlias(expr expression.Expression, from, to string) expression.Expression {
+	expr = expr.Copy()
+	if from == to {
+		return expr
+	}
+	rv := &aliasRenamer{}
+	rv.SetMapFunc(func(expr expression.Expression) (expression.Expression, error) {
+		if id, ok := expr.(*expression.Identifier); ok && id.IsKeyspaceAlias() && id.Identifier() == from {
+			ident := expression.NewIdentifier(to)
+			ident.SetKeyspaceAlias(true)
+			return ident, nil
+		}
+		return expr, expr.MapChildren(rv)
+	})
+	rv.SetMapper(rv)
+	mapped, err := rv.Map(expr)
+	if err != nil {
+		return expr
+	}
+	return mapped
+}
+
+type aliasRenamer struct {
+	expression.MapperBase
+}
+
+/*
+Replaces the parts of an expression that the view computes by the
+fields that hold them. References to the keyspace alias or aggregates
+left over once mapped cannot be computed from the view.
+*/
+type viewMapper struct {
+	expression.MapperBase
+
+	alias  string
+	fields map[expression.Expression]bool
+}
+
+func newViewMapper(alias string, targets expression.Expressions, terms algebra.ResultTerms) *viewMapper {
+	rv := &viewMapper{alias: alias, fields: make(map[expression.Expression]bool)}
+	rv.SetMapFunc(func(expr expression.Expression) (expression.Expression, error) {
+		for i, target := range targets {
+			if expr.EquivalentTo(target) {
+				ident := expression.NewIdentifier(alias)
+				ident.SetKeyspaceAlias(true)
+				field := expression.NewField(ident, expression.NewFieldName(terms[i].Alias(), false))
+				rv.fields[field] = true
+				return field, nil
+			}
+		}
+		return expr, expr.MapChildren(rv)
+	})
+	rv.SetMapper(rv)
+	return rv
+}
+
+func (this *viewMapper) mapped(expr expression.Expression) bool {
+	if this.fields[expr] {
+		return true
+	}
+	switch e := expr.(type) {
+	case *expression.Identifier:
+		if e.Identifier() == this.alias {
+			return false
+		}
+	case algebra.Aggregate, *expression.Meta, *expression.Self:
+		return false
+	}
+	for _, child := range expr.Children() {
+		if !this.mapped(child) {
+			return false
+		}
+	}
+	return true
+}
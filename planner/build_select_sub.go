@@ -347,7 +347,14 @@ func (this *builder) VisitSubselect(node *algebra.Subselect) (interface{}, error
 		}
 
 		if group != nil {
-			this.visitGroup(group, aggs)
+			var gapfill *plan.GapFill
+			if group.GapFill() != nil {
+				gapfill, err = this.buildGapFill(node, group, aggs)
+				if err != nil {
+					return nil, err
+				}
+			}
+			this.visitGroup(group, aggs, gapfill)
 		}
 
 		if len(windowAggs) > 0 {
@@ -473,7 +480,7 @@ func (this *builder) addLetAndPredicate(let expression.Bindings, pred expression
 	}
 }
 
-func (this *builder) visitGroup(group *algebra.Group, aggs algebra.Aggregates) {
+func (this *builder) visitGroup(group *algebra.Group, aggs algebra.Aggregates, gapfill *plan.GapFill) {
 
 	// If Index aggregates are not partial(i.e full) donot add the group operators
 
@@ -525,6 +532,10 @@ func (this *builder) visitGroup(group *algebra.Group, aggs algebra.Aggregates) {
 			costFinal, cardinalityFinal, size, costFinal))
 	}
 
+	if gapfill != nil {
+		this.addChildren(gapfill)
+	}
+
 	this.addLetAndPredicate(group.Letting(), group.Having())
 }
 
@@ -1048,6 +1059,12 @@ func constrainGroupTerm(expr expression.Expression, groupKeys expression.Express
 func (this *builder) setIndexGroupAggs(group *algebra.Group, aggs algebra.Aggregates, let expression.Bindings) {
 
 	if group != nil {
+		// Groups are added after the final grouping
+		if group.GapFill() != nil {
+			this.resetPushDowns()
+			return
+		}
+
 		// Group or Aggregates Depends on LET disable pushdowns
 		for _, expr := range group.By() {
 			if !expr.IndexAggregatable() || dependsOnLet(expr, let) {
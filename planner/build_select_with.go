@@ -0,0 +1,220 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/parser/n1ql"
+)
+
+/*
+A common table expression is either materialized, evaluated once by the
+With operator, or inlined: each reference to it in a FROM clause is
+replaced by a subquery term over its definition, so that predicates on
+the reference are pushed into the definition and can use its indexes.
+
+AS MATERIALIZED and AS NOT MATERIALIZED force the choice. Otherwise a
+query that does not depend on the enclosing query is inlined when it is
+referenced once, from a FROM clause of the query block defining it.
+*/
+func (this *builder) inlineWiths(stmt *algebra.Select) {
+	with := stmt.With()
+	if with == nil {
+		return
+	}
+
+	bindings := with.Bindings()
+	kept := make(expression.Withs, 0, len(bindings))
+	for _, b := range bindings {
+		if !this.inlineWith(stmt, b) {
+			kept = append(kept, b)
+		}
+	}
+
+	if len(kept) == 0 {
+		stmt.SetWith(nil)
+	} else if len(kept) < len(bindings) {
+		with.SetBindings(kept)
+	}
+}
+
+// Returns whether the CTE is no longer referenced
+func (this *builder) inlineWith(stmt *algebra.Select, with expression.With) bool {
+	if with.IsRecursive() || with.Materialization() == expression.MATERIALIZE_ALWAYS {
+		return false
+	}
+	subq, ok := with.Expression().(*algebra.Subquery)
+	if !ok || subq.IsCorrelated() {
+		return false
+	}
+
+	alias := with.Alias()
+	terms := withTerms(stmt.Subresult(), alias, nil)
+	if len(terms) == 0 {
+		return false
+	}
+	all := withReferences(stmt.Expressions(), alias) == len(terms)
+	if with.Materialization() == expression.MATERIALIZE_DEFAULT && (!all || len(terms) > 1) {
+		return false
+	}
+
+	// the definition itself can only go to one reference, and only if the
+	// CTE is not bound as well; the others get a copy parsed from its text,
+	// in which references to other CTEs would not resolve
+	copies := len(terms)
+	if all {
+		copies--
+	}
+	bodies := make(map[*algebra.ExpressionTerm]*algebra.Select, len(terms))
+	if copies > 0 {
+		if withReferences(subq.Select().Expressions(), "") > 0 {
+			return false
+		}
+		text := subq.Select().String()
+		for _, term := range terms[:copies] {
+			body, err := n1ql.ParseStatement2(text, this.namespace, this.context.QueryContext())
+			if err != nil {
+				return false
+			}
+			sel, ok := body.(*algebra.Select)
+			if !ok || this.expandSelectViews(sel, nil) != nil {
+				return false
+			}
+			bodies[term] = sel
+		}
+	}
+	if all {
+		bodies[terms[len(terms)-1]] = subq.Select()
+	}
+
+	replaceWithTerms(stmt.Subresult(), bodies)
+	return all
+}
+
+/*
+The references to CTEs that are only read by expression scans in the FROM
+clauses of the query block defining them, each query block reading them
+at most once. Rows are then read from buffers that can spill to disk, one
+for each reference. Only CTEs of the statement itself are considered.
+*/
+func (this *builder) bufferWiths(stmt *algebra.Select) map[string]int {
+	with := stmt.With()
+	if with == nil || this.subquery {
+		return nil
+	}
+
+	var rv map[string]int
+	for _, b := range with.Bindings() {
+		if b.IsRecursive() {
+			continue
+		}
+		subq, ok := b.Expression().(*algebra.Subquery)
+		if !ok || subq.IsCorrelated() {
+			continue
+		}
+
+		alias := b.Alias()
+		terms := withTerms(stmt.Subresult(), alias, nil)
+		if len(terms) == 0 || withReferences(stmt.Expressions(), alias) != len(terms) ||
+			!singleWithTerms(stmt.Subresult(), alias) {
+			continue
+		}
+
+		if rv == nil {
+			rv = make(map[string]int, len(with.Bindings()))
+			this.withBuffers = make(map[*algebra.ExpressionTerm]int, len(terms))
+		}
+		rv[alias] = len(terms)
+		for i, term := range terms {
+			this.withBuffers[term] = i + 1
+		}
+	}
+	return rv
+}
+
+// The query blocks of a set operation, not those in parentheses
+func withSubselects(subresult algebra.Subresult, subselects []*algebra.Subselect) []*algebra.Subselect {
+	switch subresult := subresult.(type) {
+	case *algebra.Subselect:
+		subselects = append(subselects, subresult)
+	case interface {
+		First() algebra.Subresult
+		Second() algebra.Subresult
+	}:
+		subselects = withSubselects(subresult.First(), subselects)
+		subselects = withSubselects(subresult.Second(), subselects)
+	}
+	return subselects
+}
+
+// The FROM clause references to a CTE
+func withTerms(subresult algebra.Subresult, alias string, terms []*algebra.ExpressionTerm) []*algebra.ExpressionTerm {
+	for _, node := range withSubselects(subresult, nil) {
+		if node.From() == nil {
+			continue
+		}
+		algebra.ReplaceFromTerms(node.From(), func(term algebra.SimpleFromTerm) (algebra.SimpleFromTerm, error) {
+			if isWithTerm(term, alias) {
+				terms = append(terms, term.(*algebra.ExpressionTerm))
+			}
+			return term, nil
+		})
+	}
+	return terms
+}
+
+func singleWithTerms(subresult algebra.Subresult, alias string) bool {
+	for _, node := range withSubselects(subresult, nil) {
+		if node.From() != nil && len(withTerms(node, alias, nil)) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func isWithTerm(term algebra.SimpleFromTerm, alias string) bool {
+	exprTerm, ok := term.(*algebra.ExpressionTerm)
+	if !ok || exprTerm.IsKeyspace() {
+		return false
+	}
+	ident, ok := exprTerm.ExpressionTerm().(*expression.Identifier)
+	return ok && ident.IsWithAlias() && ident.Identifier() == alias
+}
+
+func replaceWithTerms(subresult algebra.Subresult, bodies map[*algebra.ExpressionTerm]*algebra.Select) {
+	for _, node := range withSubselects(subresult, nil) {
+		if node.From() == nil {
+			continue
+		}
+		from, _ := algebra.ReplaceFromTerms(node.From(), func(term algebra.SimpleFromTerm) (algebra.SimpleFromTerm, error) {
+			if exprTerm, ok := term.(*algebra.ExpressionTerm); ok {
+				if body, ok := bodies[exprTerm]; ok {
+					return algebra.NewWithTerm(exprTerm, body), nil
+				}
+			}
+			return term, nil
+		})
+		node.SetFrom(from)
+	}
+}
+
+// Number of references to a CTE, or to any CTE if alias is empty; the alias of a
+// FROM clause reference that is not renamed is not a reference to the CTE
+func withReferences(exprs expression.Expressions, alias string) int {
+	n := 0
+	for _, expr := range exprs {
+		if ident, ok := expr.(*expression.Identifier); ok && ident.IsWithAlias() && !ident.IsExprTermAlias() &&
+			(alias == "" || ident.Identifier() == alias) {
+			n++
+		}
+		n += withReferences(expr.Children(), alias)
+	}
+	return n
+}
@@ -0,0 +1,165 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of the
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/views"
+)
+
+// The CTEs still bound, and the FROM clause terms, once CTEs are inlined
+func inlinedWiths(t *testing.T, s string) ([]string, []algebra.SimpleFromTerm) {
+	t.Helper()
+	stmt := mustParseStatement(t, s).(*algebra.Select)
+	newRewriteBuilder().inlineWiths(stmt)
+
+	var kept []string
+	if stmt.With() != nil {
+		for _, b := range stmt.With().Bindings() {
+			kept = append(kept, b.Alias())
+		}
+	}
+	var terms []algebra.SimpleFromTerm
+	for _, node := range withSubselects(stmt.Subresult(), nil) {
+		algebra.ReplaceFromTerms(node.From(), func(term algebra.SimpleFromTerm) (algebra.SimpleFromTerm, error) {
+			terms = append(terms, term)
+			return term, nil
+		})
+	}
+	return kept, terms
+}
+
+func isInlined(term algebra.SimpleFromTerm) bool {
+	subq, ok := term.(*algebra.SubqueryTerm)
+	return ok && subq.IsInlinedWith()
+}
+
+func TestInlineWiths(t *testing.T) {
+	withViews(t, map[string]*views.View{})
+
+	// a CTE read once from the FROM clause is inlined, keeping its alias
+	kept, terms := inlinedWiths(t, "WITH o AS (SELECT x.* FROM default:b.s.orders AS x) "+
+		"SELECT o.id FROM o WHERE o.status = \"open\"")
+	if len(kept) != 0 || len(terms) != 1 || !isInlined(terms[0]) || terms[0].Alias() != "o" {
+		t.Fatalf("Expected o to be inlined, found %v bound and %v", kept, terms)
+	}
+
+	// a CTE read twice is materialized unless told otherwise
+	s := "SELECT a.id FROM o AS a JOIN o AS b ON a.pid = b.id"
+	kept, terms = inlinedWiths(t, "WITH o AS (SELECT x.* FROM default:b.s.orders AS x) "+s)
+	if len(kept) != 1 || isInlined(terms[0]) || isInlined(terms[1]) {
+		t.Fatalf("Expected o to be materialized, found %v bound and %v", kept, terms)
+	}
+	kept, terms = inlinedWiths(t, "WITH o AS NOT MATERIALIZED (SELECT x.* FROM default:b.s.orders AS x) "+s)
+	if len(kept) != 0 || !isInlined(terms[0]) || !isInlined(terms[1]) {
+		t.Fatalf("Expected both references to o to be inlined, found %v bound and %v", kept, terms)
+	}
+	if terms[0].(*algebra.SubqueryTerm).Subquery() == terms[1].(*algebra.SubqueryTerm).Subquery() {
+		t.Fatalf("Expected each reference to o to get its own copy of the definition")
+	}
+	if !terms[1].IsAnsiJoin() {
+		t.Fatalf("Expected the inlined reference to keep its join, found %v", terms[1])
+	}
+
+	// a CTE that is also read outside the FROM clause stays bound
+	kept, terms = inlinedWiths(t, "WITH o AS NOT MATERIALIZED (SELECT RAW x.id FROM default:b.s.orders AS x) "+
+		"SELECT a FROM o AS a WHERE ARRAY_LENGTH(o) > 1")
+	if len(kept) != 1 || !isInlined(terms[0]) {
+		t.Fatalf("Expected o to be inlined and still bound, found %v bound and %v", kept, terms)
+	}
+
+	for _, s := range []string{
+		// told to materialize
+		"WITH o AS MATERIALIZED (SELECT x.* FROM default:b.s.orders AS x) SELECT o.id FROM o",
+		// not a query
+		"WITH o AS ([1, 2, 3]) SELECT o FROM o",
+		// copies of a definition cannot read other CTEs
+		"WITH p AS (SELECT x.* FROM default:b.s.orders AS x), o AS NOT MATERIALIZED (SELECT p.* FROM p) " +
+			"SELECT a.id FROM o AS a JOIN o AS b ON a.pid = b.id",
+		// recursive
+		"WITH RECURSIVE o AS (SELECT 1 AS n UNION SELECT o.n + 1 AS n FROM o WHERE o.n < 3) SELECT o.n FROM o",
+	} {
+		kept, terms = inlinedWiths(t, s)
+		for _, term := range terms {
+			if isInlined(term) && term.Alias() == "o" {
+				t.Errorf("Expected o not to be inlined in %q", s)
+			}
+		}
+		if len(kept) == 0 || kept[len(kept)-1] != "o" {
+			t.Errorf("Expected o to stay bound in %q, found %v", s, kept)
+		}
+	}
+}
+
+func TestBufferWiths(t *testing.T) {
+	// a CTE read once by each query block gets a buffer per reference
+	stmt := mustParseStatement(t, "WITH o AS MATERIALIZED (SELECT x.* FROM default:b.s.orders AS x) "+
+		"SELECT o.id FROM o WHERE o.status = \"open\" UNION ALL SELECT o.id FROM o WHERE o.total > 10").(*algebra.Select)
+	builder := newRewriteBuilder()
+	if buffered := builder.bufferWiths(stmt); len(buffered) != 1 || buffered["o"] != 2 {
+		t.Fatalf("Expected two buffers for o, found %v", buffered)
+	}
+	if len(builder.withBuffers) != 2 {
+		t.Fatalf("Expected both references to o to read a buffer, found %v", builder.withBuffers)
+	}
+
+	for _, s := range []string{
+		// read twice by the same query block
+		"WITH o AS MATERIALIZED (SELECT x.* FROM default:b.s.orders AS x) " +
+			"SELECT a.id FROM o AS a JOIN o AS b ON a.pid = b.id",
+		// read outside the FROM clause
+		"WITH o AS MATERIALIZED (SELECT RAW x.id FROM default:b.s.orders AS x) SELECT a FROM o AS a WHERE 1 IN o",
+	} {
+		stmt = mustParseStatement(t, s).(*algebra.Select)
+		if buffered := newRewriteBuilder().bufferWiths(stmt); len(buffered) != 0 {
+			t.Errorf("Expected no buffers for %q, found %v", s, buffered)
+		}
+	}
+}
+
+func TestInlineWithPlan(t *testing.T) {
+	withMockDatastore(t)
+	withViews(t, map[string]*views.View{})
+	n1ql.SetNamespaces(map[string]interface{}{"p0": true})
+
+	explain := func(text string) string {
+		stmt, err := n1ql.ParseStatement2(text, "p0", "")
+		if err != nil {
+			t.Fatalf("n1ql.ParseStatement2(%q): %v", text, err)
+		}
+		qp, err := stmt.Accept(newBuilder(nil, nil, "p0", false, &PrepareContext{}))
+		if err != nil {
+			t.Fatalf("Unexpected error planning %q: %v", text, err)
+		}
+		bytes, err := json.Marshal(qp.(*plan.QueryPlan).PlanOp())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return string(bytes)
+	}
+
+	// the definition is planned in place of the reference: the keyspace is scanned, no CTE is bound
+	s := explain("WITH o AS (SELECT x.* FROM p0:b0 AS x) SELECT o.id FROM o WHERE o.status = \"open\"")
+	if strings.Contains(s, `"#operator":"With"`) || !strings.Contains(s, `"#operator":"PrimaryScan"`) ||
+		!strings.Contains(s, `{"#operator":"Alias","as":"o"}`) {
+		t.Fatalf("Expected o to be scanned in place, found %v", s)
+	}
+
+	// a materialized CTE is bound once and read by an expression scan
+	s = explain("WITH o AS MATERIALIZED (SELECT x.* FROM p0:b0 AS x) SELECT o.id FROM o WHERE o.status = \"open\"")
+	if !strings.Contains(s, `"#operator":"With"`) || !strings.Contains(s, `"#operator":"ExpressionScan"`) {
+		t.Fatalf("Expected o to be materialized, found %v", s)
+	}
+}
@@ -0,0 +1,43 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/functions"
+	"github.com/couchbase/query/plan"
+)
+
+func (this *builder) VisitCreateTrigger(stmt *algebra.CreateTrigger) (interface{}, error) {
+	ksref := stmt.Keyspace()
+	keyspace, err := this.getNameKeyspace(ksref, false, true, stmt.Type())
+	if err != nil {
+		return nil, err
+	}
+	if ksref.IsSystem() {
+		return nil, errors.NewTriggerError(errors.E_TRIGGER_INVALID_KEYSPACE, ksref.Path().SimpleString())
+	}
+	if !functions.PreLoad(stmt.Function()) {
+		return nil, errors.NewMissingFunctionError(stmt.Function().Key())
+	}
+	return plan.NewQueryPlan(plan.NewCreateTrigger(keyspace, stmt)), nil
+}
+
+func (this *builder) VisitDropTrigger(stmt *algebra.DropTrigger) (interface{}, error) {
+	ksref := stmt.Keyspace()
+	keyspace, err := this.getNameKeyspace(ksref, false, false, stmt.Type())
+	if err != nil {
+		return nil, err
+	}
+	if ksref.IsSystem() {
+		return nil, errors.NewTriggerError(errors.E_TRIGGER_INVALID_KEYSPACE, ksref.Path().SimpleString())
+	}
+	return plan.NewQueryPlan(plan.NewDropTrigger(keyspace, stmt)), nil
+}
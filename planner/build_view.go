@@ -0,0 +1,48 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/plan"
+)
+
+func (this *builder) VisitCreateView(stmt *algebra.CreateView) (interface{}, error) {
+	if stmt.Name().Scope() == "" {
+		return nil, errors.NewViewError(errors.E_VIEW_INVALID_NAME, stmt.Name().SimpleString())
+	}
+	err := validateSequencePath(this.context.Credentials(), stmt.Name())
+	if err != nil {
+		return nil, err
+	}
+	return plan.NewQueryPlan(plan.NewCreateView(stmt)), nil
+}
+
+func (this *builder) VisitDropView(stmt *algebra.DropView) (interface{}, error) {
+	if stmt.Name().Scope() == "" {
+		return nil, errors.NewViewError(errors.E_VIEW_INVALID_NAME, stmt.Name().SimpleString())
+	}
+	err := validateSequencePath(this.context.Credentials(), stmt.Name())
+	if err != nil {
+		return nil, err
+	}
+	return plan.NewQueryPlan(plan.NewDropView(stmt)), nil
+}
+
+func (this *builder) VisitRefreshView(stmt *algebra.RefreshView) (interface{}, error) {
+	if stmt.Name().Scope() == "" {
+		return nil, errors.NewViewError(errors.E_VIEW_INVALID_NAME, stmt.Name().SimpleString())
+	}
+	err := validateSequencePath(this.context.Credentials(), stmt.Name())
+	if err != nil {
+		return nil, err
+	}
+	return plan.NewQueryPlan(plan.NewRefreshView(stmt)), nil
+}
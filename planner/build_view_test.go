@@ -0,0 +1,227 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of the
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/views"
+)
+
+// Replaces the views kept in system collections, by collection name; those that rewrite
+// queries are returned for every scope.
+func withViews(t *testing.T, list map[string]*views.View) {
+	savedGet, savedRewrite := getView, rewriteViews
+	getView = func(path *algebra.Path) (*views.View, errors.Error) {
+		return list[path.Keyspace()], nil
+	}
+	rewriteViews = func(scope *algebra.Path) []*views.MaterializedView {
+		var rv []*views.MaterializedView
+		for name, v := range list {
+			if v.Materialized && v.Rewrite {
+				path := algebra.NewPathFromElements(algebra.ParsePath(scope.FullName() + "." + name))
+				rv = append(rv, &views.MaterializedView{Path: path, View: v})
+			}
+		}
+		return rv
+	}
+	t.Cleanup(func() { getView, rewriteViews = savedGet, savedRewrite })
+}
+
+// Views are expanded as Build does
+func expandStatement(t *testing.T, s string) (algebra.Statement, error) {
+	t.Helper()
+	stmt := mustParseStatement(t, s)
+	err := newRewriteBuilder().expandViews(stmt)
+	return stmt, err
+}
+
+func viewTerm(t *testing.T, from algebra.FromTerm) *algebra.SubqueryTerm {
+	t.Helper()
+	term, ok := from.(*algebra.SubqueryTerm)
+	if !ok || term.View() == nil {
+		t.Fatalf("Expected an expanded view, found %v", from)
+	}
+	return term
+}
+
+// The targets of the SELECT privileges a statement needs
+func selectTargets(t *testing.T, stmt algebra.Statement) []string {
+	t.Helper()
+	privs, err := stmt.Privileges()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var rv []string
+	privs.ForEach(func(pair auth.PrivilegePair) {
+		if pair.Priv == auth.PRIV_QUERY_SELECT {
+			rv = append(rv, pair.Target)
+		}
+	})
+	return rv
+}
+
+func TestExpandViews(t *testing.T) {
+	withViews(t, map[string]*views.View{
+		"active": {Text: "SELECT c.id, c.name, c.email FROM default:b.s.customers AS c WHERE c.active"},
+		"recent": {Text: "SELECT a.id, a.name FROM default:b.s.active AS a WHERE a.id > 100"},
+	})
+
+	// the view is replaced by its definition, keeping its alias
+	stmt, err := expandStatement(t, "SELECT v.name FROM default:b.s.active AS v WHERE v.id = 7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	term := viewTerm(t, subselectOf(t, stmt).From())
+	if term.Alias() != "v" || term.View().Keyspace() != "active" {
+		t.Fatalf("Expected view active as v, found %v as %v", term.View(), term.Alias())
+	}
+	body := subselectOf(t, term.Subquery())
+	if !strings.Contains(body.Where().String(), "active") {
+		t.Fatalf("Expected the WHERE clause of the view, found %v", body.Where())
+	}
+
+	// projection terms the query never references are dropped
+	terms := body.Projection().Terms()
+	if len(terms) != 2 || terms[0].Alias() != "id" || terms[1].Alias() != "name" {
+		t.Fatalf("Expected the view to project id and name only, found %v", body.Projection())
+	}
+
+	// the privileges are those on the collection the view reads, not on the view
+	targets := selectTargets(t, stmt)
+	if len(targets) != 1 || !strings.HasSuffix(targets[0], "customers") {
+		t.Fatalf("Expected SELECT on customers only, found %v", targets)
+	}
+
+	// views over views, and views in subqueries, are expanded too
+	stmt, err = expandStatement(t, "SELECT 1 FROM default:b.s.orders AS o "+
+		"WHERE o.cid IN (SELECT RAW r.id FROM default:b.s.recent AS r)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	targets = selectTargets(t, stmt)
+	if len(targets) != 2 || !strings.HasSuffix(strings.Join(targets, " "), "orders default:b.s.customers") {
+		t.Fatalf("Expected SELECT on orders and customers, found %v", targets)
+	}
+
+	// SELECT * keeps every projection term
+	stmt, err = expandStatement(t, "SELECT * FROM default:b.s.active AS v")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	term = viewTerm(t, subselectOf(t, stmt).From())
+	if n := len(subselectOf(t, term.Subquery()).Projection().Terms()); n != 3 {
+		t.Fatalf("Expected the view to project all 3 fields, found %d", n)
+	}
+}
+
+func TestExpandViewErrors(t *testing.T) {
+	withViews(t, map[string]*views.View{
+		"loop1":  {Text: "SELECT l.id FROM default:b.s.loop2 AS l"},
+		"loop2":  {Text: "SELECT l.id FROM default:b.s.loop1 AS l"},
+		"params": {Text: "SELECT c.id FROM default:b.s.customers AS c WHERE c.id = $1"},
+		"purge":  {Text: "DELETE FROM default:b.s.customers"},
+		"active": {Text: "SELECT c.id FROM default:b.s.customers AS c WHERE c.active"},
+	})
+
+	_, err := expandStatement(t, "SELECT 1 FROM default:b.s.loop1 AS v")
+	expectErrorCode(t, err, errors.E_VIEW_RECURSIVE)
+
+	for _, s := range []string{"SELECT 1 FROM default:b.s.params AS v", "SELECT 1 FROM default:b.s.purge AS v"} {
+		_, err = expandStatement(t, s)
+		expectErrorCode(t, err, errors.E_VIEW_INVALID_DEFINITION)
+	}
+
+	_, err = expandStatement(t, "SELECT 1 FROM default:b.s.active AS v USE KEYS \"k1\"")
+	expectErrorCode(t, err, errors.E_VIEW_USE_KEYS_INDEX)
+
+	// a collection that is not a view is left alone
+	stmt, err := expandStatement(t, "SELECT 1 FROM default:b.s.customers AS c")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := subselectOf(t, stmt).From().(*algebra.KeyspaceTerm); !ok {
+		t.Fatalf("Expected a keyspace, found %v", subselectOf(t, stmt).From())
+	}
+}
+
+func TestMaterializedRewrite(t *testing.T) {
+	withViews(t, map[string]*views.View{
+		"open_totals": {Text: "SELECT x.region, COUNT(*) AS n, SUM(x.amount) AS total FROM default:b.s.orders AS x " +
+			"WHERE x.status = \"open\" GROUP BY x.region", Materialized: true, Rewrite: true},
+		"unused": {Text: "SELECT x.region, COUNT(*) AS n FROM default:b.s.orders AS x GROUP BY x.region",
+			Materialized: true},
+	})
+
+	// the query reads the rows of the view, with what is left of its WHERE and HAVING clauses
+	stmt, err := expandStatement(t, "SELECT o.region, SUM(o.amount) AS total FROM default:b.s.orders AS o "+
+		"WHERE o.status = \"open\" AND o.region != \"north\" GROUP BY o.region HAVING SUM(o.amount) > 10 "+
+		"ORDER BY SUM(o.amount) DESC")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	node := subselectOf(t, stmt)
+	term, ok := node.From().(*algebra.KeyspaceTerm)
+	if !ok || term.Path().Keyspace() != "open_totals" || term.Alias() != "o" {
+		t.Fatalf("Expected the query to read open_totals as o, found %v", node.From())
+	}
+	if node.Group() != nil {
+		t.Fatalf("Expected the GROUP BY clause to be dropped, found %v", node.Group())
+	}
+	where := node.Where().String()
+	if where != "((not ((`o`.`region`) = \"north\")) and (10 < (`o`.`total`)))" {
+		t.Fatalf("Expected the remaining conditions on the fields of the view, found %v", where)
+	}
+	if expr := node.Projection().Terms()[1].Expression().String(); expr != "(`o`.`total`)" {
+		t.Fatalf("Expected SUM(o.amount) to be read from the view, found %v", expr)
+	}
+	if expr := stmt.(*algebra.Select).Order().Terms()[0].Expression().String(); expr != "(`o`.`total`)" {
+		t.Fatalf("Expected ORDER BY on the field of the view, found %v", expr)
+	}
+
+	// queries the view cannot answer are left alone
+	for _, s := range []string{
+		// the view only holds open orders
+		"SELECT o.region, SUM(o.amount) AS total FROM default:b.s.orders AS o GROUP BY o.region",
+		// other groups
+		"SELECT o.status, COUNT(*) AS n FROM default:b.s.orders AS o WHERE o.status = \"open\" GROUP BY o.status",
+		// an aggregate the view does not compute
+		"SELECT o.region, MAX(o.amount) AS m FROM default:b.s.orders AS o WHERE o.status = \"open\" GROUP BY o.region",
+		// a condition on a field the view does not keep
+		"SELECT o.region, COUNT(*) AS n FROM default:b.s.orders AS o WHERE o.status = \"open\" AND o.amount > 5 " +
+			"GROUP BY o.region",
+		// USE KEYS is not overridden
+		"SELECT o.region, COUNT(*) AS n FROM default:b.s.orders AS o USE KEYS [\"k1\"] WHERE o.status = \"open\" " +
+			"GROUP BY o.region",
+	} {
+		stmt, err := expandStatement(t, s)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if term, ok := subselectOf(t, stmt).From().(*algebra.KeyspaceTerm); !ok || term.Path().Keyspace() != "orders" {
+			t.Errorf("Expected %q to read orders, found %v", s, subselectOf(t, stmt).From())
+		}
+	}
+
+	// a view is not read while it is being populated
+	stmt, err = expandStatement(t, "UPSERT INTO default:b.s.open_totals (KEY k, VALUE v) "+
+		"SELECT TO_STRING(o.region) AS k, {o.region, \"n\": COUNT(*)} AS v FROM default:b.s.orders AS o "+
+		"WHERE o.status = \"open\" GROUP BY o.region")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	node = subselectOf(t, stmt.(*algebra.Upsert).Select())
+	if term, ok := node.From().(*algebra.KeyspaceTerm); !ok || term.Path().Keyspace() != "orders" {
+		t.Fatalf("Expected the view to be populated from orders, found %v", node.From())
+	}
+}
@@ -0,0 +1,83 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of the
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package planner
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/datastore/mock"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/parser/n1ql"
+)
+
+func mustParseStatement(t *testing.T, s string) algebra.Statement {
+	t.Helper()
+	n1ql.SetNamespaces(map[string]interface{}{"default": true})
+	stmt, err := n1ql.ParseStatement2(s, "default", "")
+	if err != nil {
+		t.Fatalf("n1ql.ParseStatement2(%q): %v", s, err)
+	}
+	return stmt
+}
+
+// A builder without a datastore, enough to rewrite statements before they are planned.
+func newRewriteBuilder() *builder {
+	return newBuilder(nil, nil, "default", false, &PrepareContext{})
+}
+
+func subselectOf(t *testing.T, stmt algebra.Statement) *algebra.Subselect {
+	t.Helper()
+	sel, ok := stmt.(*algebra.Select)
+	if !ok {
+		t.Fatalf("Expected a SELECT, found %T", stmt)
+	}
+	node, ok := sel.Subresult().(*algebra.Subselect)
+	if !ok {
+		t.Fatalf("Expected a query block, found %T", sel.Subresult())
+	}
+	return node
+}
+
+func expectErrorCode(t *testing.T, err error, code errors.ErrorCode) {
+	t.Helper()
+	if e, ok := err.(errors.Error); !ok || e.Code() != code {
+		t.Fatalf("Expected error %v, found %v", code, err)
+	}
+}
+
+// A system store that serves the keyspaces of the mock store
+type mockSystemstore struct {
+	datastore.Datastore
+}
+
+func (this *mockSystemstore) NamespaceById(id string) (datastore.Namespace, errors.Error) {
+	return this.Datastore.NamespaceById("p0")
+}
+
+func (this *mockSystemstore) PrivilegesFromPath(fullname string, keyspace string, privilege auth.Privilege,
+	privs *auth.Privileges) {
+}
+
+// Plans against a mock store with the single keyspace p0:b0
+func withMockDatastore(t *testing.T) {
+	m, err := mock.NewDatastore("mock:namespaces=1,keyspaces=1,items=10")
+	if err != nil {
+		t.Fatalf("failed to create mock store: %v", err)
+	}
+	savedStore, savedSystem := datastore.GetDatastore(), datastore.GetSystemstore()
+	datastore.SetDatastore(m)
+	datastore.SetSystemstore(&mockSystemstore{m})
+	t.Cleanup(func() {
+		datastore.SetDatastore(savedStore)
+		datastore.SetSystemstore(savedSystem)
+	})
+}
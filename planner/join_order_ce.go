@@ -0,0 +1,265 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+//
+//go:build !enterprise
+
+package planner
+
+import (
+	"math"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/statistics"
+	"github.com/couchbase/query/util"
+)
+
+// The qualified name of a keyspace, and its number of documents, -1 if it has no statistics
+var keyspaceDocCount = func(path *algebra.Path) (string, int64) {
+	ks, err := datastore.GetKeyspace(path.Parts()...)
+	if err != nil || ks == nil {
+		return "", -1
+	}
+	return ks.QualifiedName(), statistics.DocCount(ks.QualifiedName())
+}
+
+type joinPred struct {
+	expr    expression.Expression
+	aliases map[string]string
+	on      bool // from an ON clause
+	connect bool // can serve as join condition
+}
+
+/*
+Returns the FROM clause of a query block with its inner joins in a cheaper
+order, or nil if it is best planned as it is. Starting from the keyspace
+with the fewest documents left once its own filters apply, keyspaces are
+added one at a time, each time the one joined to those before it that
+leaves the fewest rows.
+
+Only a FROM clause made of keyspaces joined by inner ANSI joins, or by
+commas, with no hints and with statistics on every keyspace, is
+reordered. ON-clause terms move to the first join where all the
+keyspaces they refer to are available. The query block is left alone:
+the clause returned is built from copies of its keyspace terms.
+*/
+func optReorderJoins(node *algebra.Subselect, context *PrepareContext) algebra.FromTerm {
+	if !util.IsFeatureEnabled(context.FeatureControls(), util.N1QL_JOIN_ENUMERATION) {
+		return nil
+	}
+	if hints := node.OptimHints(); hints != nil && len(hints.Hints()) > 0 {
+		return nil
+	}
+
+	terms, onclauses, comma, ok := flattenInnerJoins(node.From())
+	if !ok || len(terms) < 2 {
+		return nil
+	}
+
+	keyspaces := make(map[string]string, len(terms))
+	for _, term := range terms {
+		if term.Path() == nil {
+			return nil
+		}
+		name, docCount := keyspaceDocCount(term.Path())
+		if docCount < 0 {
+			return nil
+		}
+		if _, ok := keyspaces[term.Alias()]; ok {
+			return nil
+		}
+		keyspaces[term.Alias()] = name
+	}
+
+	var preds []*joinPred
+	addPreds := func(expr expression.Expression, on bool) bool {
+		if expr == nil {
+			return true
+		}
+		for _, t := range andTerms(expr) {
+			aliases, err := expression.CountKeySpaces(t, keyspaces)
+			if err != nil {
+				return false
+			}
+			preds = append(preds, &joinPred{expr: t, aliases: aliases, on: on,
+				connect: on != comma && len(aliases) > 1})
+		}
+		return true
+	}
+	if !addPreds(node.Where(), false) || !addPreds(onclauses, true) {
+		return nil
+	}
+
+	cards := make([]float64, len(terms))
+	first := 0
+	for i, term := range terms {
+		sel := 1.0
+		for _, p := range preds {
+			if _, ok := p.aliases[term.Alias()]; ok && len(p.aliases) == 1 {
+				sel *= exprSelec(keyspaces, p.expr)
+			}
+		}
+		_, docCount := keyspaceDocCount(term.Path())
+		cards[i] = math.Max(float64(docCount)*sel, 1.0)
+		if cards[i] < cards[first] {
+			first = i
+		}
+	}
+
+	order := []int{first}
+	joined := map[string]bool{terms[first].Alias(): true}
+	card := cards[first]
+	for len(order) < len(terms) {
+		best, bestCard := -1, 0.0
+		for i, term := range terms {
+			alias := term.Alias()
+			if joined[alias] {
+				continue
+			}
+			connected, sel := false, 1.0
+			for _, p := range preds {
+				if _, ok := p.aliases[alias]; !ok || len(p.aliases) < 2 || !joinedWith(p.aliases, joined, alias) {
+					continue
+				}
+				connected = connected || p.connect
+				sel *= exprSelec(keyspaces, p.expr)
+			}
+			if !connected {
+				continue
+			}
+			if c := card * cards[i] * sel; best < 0 || c < bestCard {
+				best, bestCard = i, c
+			}
+		}
+		if best < 0 {
+			// a keyspace is not joined to the others: leave the query alone
+			return nil
+		}
+		order = append(order, best)
+		joined[terms[best].Alias()] = true
+		card = math.Max(bestCard, 1.0)
+	}
+
+	reordered := false
+	for i, o := range order {
+		if i != o {
+			reordered = true
+			break
+		}
+	}
+	if !reordered {
+		return nil
+	}
+
+	// each ON-clause term goes to the first join that has all it refers to
+	ons := make([]expression.Expressions, len(order))
+	if !comma {
+		for _, p := range preds {
+			if !p.on {
+				continue
+			}
+			step := 1
+			available := map[string]bool{terms[order[0]].Alias(): true}
+			for ; step < len(order); step++ {
+				available[terms[order[step]].Alias()] = true
+				if joinedWith(p.aliases, available, "") {
+					break
+				}
+			}
+			ons[step] = append(ons[step], p.expr)
+		}
+	}
+
+	var from algebra.FromTerm
+	for step, i := range order {
+		term := terms[i].Copy()
+		term.UnsetJoinProps()
+		term.SetProperty(term.Property() &^ algebra.TERM_COMMA_JOIN)
+		if step == 0 {
+			from = term
+			continue
+		}
+		var onclause expression.Expression
+		if comma {
+			term.SetCommaJoin()
+		} else {
+			term.SetAnsiJoin()
+			if len(ons[step]) == 1 {
+				onclause = ons[step][0]
+			} else {
+				onclause = expression.NewAnd(ons[step]...)
+			}
+		}
+		from = algebra.NewAnsiJoin(from, false, term, onclause)
+	}
+	return from
+}
+
+/*
+The keyspaces of a FROM clause made only of keyspaces and inner joins,
+with the terms of their ON clauses, and whether the joins are all
+comma-separated.
+*/
+func flattenInnerJoins(from algebra.FromTerm) (terms []*algebra.KeyspaceTerm, onclauses expression.Expression,
+	comma, ok bool) {
+
+	var ons expression.Expressions
+	nComma := 0
+	for from != nil {
+		switch f := from.(type) {
+		case *algebra.AnsiJoin:
+			right, ok := f.Right().(*algebra.KeyspaceTerm)
+			if !ok || f.Outer() || !reorderable(right) {
+				return nil, nil, false, false
+			}
+			if right.IsCommaJoin() {
+				nComma++
+			} else if f.Onclause() != nil {
+				ons = append(ons, f.Onclause())
+			}
+			terms = append([]*algebra.KeyspaceTerm{right}, terms...)
+			from = f.Left()
+		case *algebra.KeyspaceTerm:
+			if !reorderable(f) {
+				return nil, nil, false, false
+			}
+			terms = append([]*algebra.KeyspaceTerm{f}, terms...)
+			from = nil
+		default:
+			return nil, nil, false, false
+		}
+	}
+
+	if nComma > 0 && nComma != len(terms)-1 {
+		return nil, nil, false, false
+	}
+	switch len(ons) {
+	case 0:
+	case 1:
+		onclauses = ons[0]
+	default:
+		onclauses = expression.NewAnd(ons...)
+	}
+	return terms, onclauses, nComma > 0, true
+}
+
+func reorderable(term *algebra.KeyspaceTerm) bool {
+	return term.JoinHint() == algebra.JOIN_HINT_NONE && term.JoinKeys() == nil &&
+		!term.IsAnsiNest() && !term.IsIndexJoinNest() && !term.IsLateralJoin() && !term.IsInCorrSubq()
+}
+
+// Whether all aliases other than alias are joined
+func joinedWith(aliases map[string]string, joined map[string]bool, alias string) bool {
+	for a, _ := range aliases {
+		if a != alias && !joined[a] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of the
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+//
+//go:build !enterprise
+
+package planner
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+)
+
+// Replaces the statistics gathered on keyspaces by document counts, by collection name.
+func withDocCounts(t *testing.T, counts map[string]int64) {
+	saved := keyspaceDocCount
+	keyspaceDocCount = func(path *algebra.Path) (string, int64) {
+		if count, ok := counts[path.Keyspace()]; ok {
+			return path.FullName(), count
+		}
+		return "", -1
+	}
+	t.Cleanup(func() { keyspaceDocCount = saved })
+}
+
+func TestReorderJoins(t *testing.T) {
+	withDocCounts(t, map[string]int64{"orders": 100000, "customers": 1000, "regions": 10})
+
+	// the smallest keyspace goes first, then those joined to it, smallest first
+	stmt := mustParseStatement(t, "SELECT 1 FROM default:b.s.orders AS o JOIN default:b.s.customers AS c "+
+		"ON o.cid = c.id JOIN default:b.s.regions AS r ON c.rid = r.id")
+	node := subselectOf(t, stmt)
+	written := node.From().String()
+	from := optReorderJoins(node, &PrepareContext{})
+	if from == nil {
+		t.Fatalf("Joins not reordered")
+	}
+	terms, _, _, ok := flattenInnerJoins(from)
+	if !ok || len(terms) != 3 {
+		t.Fatalf("Expected three inner joins, found %v", from)
+	}
+	for i, alias := range []string{"r", "c", "o"} {
+		if terms[i].Alias() != alias {
+			t.Fatalf("Expected %v at position %d, found %v", alias, i, from)
+		}
+	}
+	join := from.(*algebra.AnsiJoin)
+	if join.Onclause() == nil || join.Onclause().String() != "((`o`.`cid`) = (`c`.`id`))" {
+		t.Fatalf("Expected the ON clause of orders to join it to customers, found %v", join.Onclause())
+	}
+
+	// the statement is left as it was written
+	if node.From().String() != written {
+		t.Fatalf("Statement changed by reordering: %v", node.From())
+	}
+	if first := node.From().PrimaryTerm(); first.Alias() != "o" {
+		t.Fatalf("Expected orders to remain first in the statement, found %v", first.Alias())
+	}
+	if orig, _, _, _ := flattenInnerJoins(node.From()); !orig[2].IsAnsiJoin() || terms[0].IsAnsiJoin() {
+		t.Fatalf("Expected the terms of the statement to keep their join properties")
+	}
+
+	// joins already in order, and joins on keyspaces without statistics, are left alone
+	for _, s := range []string{
+		"SELECT 1 FROM default:b.s.regions AS r JOIN default:b.s.customers AS c ON c.rid = r.id",
+		"SELECT 1 FROM default:b.s.orders AS o JOIN default:b.s.items AS i ON o.id = i.oid",
+		"SELECT /*+ ORDERED */ 1 FROM default:b.s.orders AS o JOIN default:b.s.regions AS r ON o.rid = r.id",
+		"SELECT 1 FROM default:b.s.orders AS o LEFT JOIN default:b.s.regions AS r ON o.rid = r.id",
+	} {
+		if from := optReorderJoins(subselectOf(t, mustParseStatement(t, s)), &PrepareContext{}); from != nil {
+			t.Errorf("Expected %q to be left alone, found %v", s, from)
+		}
+	}
+}
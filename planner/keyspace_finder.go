@@ -154,6 +154,19 @@ func (this *keyspaceFinder) VisitAnsiJoin(node *algebra.AnsiJoin) (interface{},
 	return nil, err
 }
 
+func (this *keyspaceFinder) VisitAsofJoin(node *algebra.AsofJoin) (interface{}, error) {
+	// the right-hand side is subservient even for an inner ASOF join: the
+	// WHERE clause applies to the nearest object only, and cannot be used
+	// to choose it
+	err := this.visitJoin(node.Left(), node.Right(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	this.arrayId, err = expression.AssignArrayId(node.Onclause(), this.arrayId)
+	return nil, err
+}
+
 func (this *keyspaceFinder) VisitNest(node *algebra.Nest) (interface{}, error) {
 	return nil, this.visitJoin(node.Left(), node.Right(), false)
 }
@@ -906,6 +906,8 @@ func (this *builder) RemoveFromSubqueries(ops ...plan.Operator) {
 			this.RemoveFromSubqueries(op.Child())
 		case *plan.HashJoin:
 			this.RemoveFromSubqueries(op.Child())
+		case *plan.AsofJoin:
+			this.RemoveFromSubqueries(op.Child())
 		case *plan.HashNest:
 			this.RemoveFromSubqueries(op.Child())
 		case *plan.With:
@@ -0,0 +1,39 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+//
+//go:build !enterprise
+
+package planner
+
+import (
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/plan"
+)
+
+/*
+The community edition optimizer. It does no join enumeration: joins are
+put in order before planning (see optReorderJoins), and the query block
+is then planned the rule-based way, with costs guiding index choice.
+*/
+type ceOptimizer struct {
+}
+
+func NewOptimizer() Optimizer {
+	return &ceOptimizer{}
+}
+
+func (this *ceOptimizer) Copy() Optimizer {
+	return &ceOptimizer{}
+}
+
+func (this *ceOptimizer) OptimizeQueryBlock(builder Builder, node algebra.Node, limit, offset expression.Expression,
+	order *algebra.Order, distinct algebra.ResultTerms, advisorValidate bool) (
+	[]plan.Operator, []plan.Operator, []plan.CoveringOperator, expression.Expression, bool, error) {
+	return nil, nil, nil, nil, false, nil
+}
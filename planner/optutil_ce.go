@@ -11,36 +11,256 @@
 package planner
 
 import (
+	"math"
+
 	"github.com/couchbase/query/algebra"
 	"github.com/couchbase/query/datastore"
 	"github.com/couchbase/query/errors"
 	"github.com/couchbase/query/expression"
 	"github.com/couchbase/query/plan"
 	base "github.com/couchbase/query/plannerbase"
+	"github.com/couchbase/query/statistics"
+	"github.com/couchbase/query/value"
+)
+
+/*
+The community edition cost model works off the statistics gathered by
+UPDATE STATISTICS into the statistics package, which only datastores with
+no statistics of their own (file, mock) provide. Keyspaces without
+statistics have no document count, which keeps them on rule-based planning.
+
+Costs are in arbitrary units: what matters is that they order plans the
+same way every time for the same statistics.
+*/
+const (
+	_COST_SCAN_START  = 1.0  // starting an index scan
+	_COST_INDEX_ENTRY = 0.1  // reading one index entry
+	_COST_FETCH       = 1.0  // fetching one document
+	_COST_EVAL        = 0.02 // evaluating an expression on one document
+	_COST_HASH        = 0.05 // building or probing one hash table entry
+	_COST_SORT        = 0.01 // one comparison during a sort
+	_COST_WRITE       = 2.0  // writing one document
+
+	_INDEX_ENTRY_SIZE = int64(64)
+)
+
+// Selectivities used when there is no histogram on the expression
+const (
+	_DEF_EQ_SELEC    = 0.1
+	_DEF_RANGE_SELEC = 1.0 / 3.0
+	_DEF_IN_SELEC    = 0.2
+	_DEF_LIKE_SELEC  = 0.1
+	_DEF_SELEC       = 0.5
 )
 
 func checkCostModel(featureControls uint64) {
 	// no-op
 }
 
+/*
+Without use_cbo, which needs the CBO feature of Enterprise Edition, costs are
+still estimated once UPDATE STATISTICS has gathered statistics, and only for
+keyspaces that have them.
+*/
+func optStatisticsCBO() bool {
+	return statistics.Gathered()
+}
+
 func optDocCount(keyspace string, useCBO bool) int64 {
-	return -1
+	if !useCBO {
+		return -1
+	}
+	return statistics.DocCount(keyspace)
 }
 
 func optFilterSelectivity(filter *base.Filter, advisorValidate bool, context *PrepareContext) {
-	return
+	sel, arrSel := optExprSelec(filter.Keyspaces(), filter.FltrExpr(), advisorValidate, context)
+	filter.SetSelec(sel)
+	filter.SetArraySelec(arrSel)
 }
+
 func optExprSelec(keyspaces map[string]string, pred expression.Expression, advisorValidate bool,
 	context *PrepareContext) (float64, float64) {
+	for _, ks := range keyspaces {
+		if statistics.DocCount(ks) >= 0 {
+			sel := exprSelec(keyspaces, pred)
+			return sel, sel
+		}
+	}
 	return OPT_SELEC_NOT_AVAIL, OPT_SELEC_NOT_AVAIL
 }
 
+func exprSelec(keyspaces map[string]string, pred expression.Expression) float64 {
+	if v := pred.Value(); v != nil {
+		if v.Truth() {
+			return 1.0
+		}
+		return 0.0
+	}
+
+	switch pred := pred.(type) {
+	case *expression.And:
+		sel := 1.0
+		for _, op := range pred.Operands() {
+			sel *= exprSelec(keyspaces, op)
+		}
+		return sel
+	case *expression.Or:
+		sel := 1.0
+		for _, op := range pred.Operands() {
+			sel *= 1.0 - exprSelec(keyspaces, op)
+		}
+		return 1.0 - sel
+	case *expression.Not:
+		return 1.0 - exprSelec(keyspaces, pred.Operand())
+	case *expression.Eq:
+		return eqSelec(keyspaces, pred.First(), pred.Second())
+	case *expression.LT:
+		return compSelec(keyspaces, pred.First(), pred.Second(), false)
+	case *expression.LE:
+		return compSelec(keyspaces, pred.First(), pred.Second(), true)
+	case *expression.Between:
+		ops := pred.Operands()
+		h := keyHistogram(keyspaces, ops[0])
+		low, high := ops[1].Value(), ops[2].Value()
+		if h == nil || low == nil || high == nil {
+			return _DEF_RANGE_SELEC * _DEF_RANGE_SELEC
+		}
+		return statistics.RangeSelec(h, low, high, true, true)
+	case *expression.In:
+		h := keyHistogram(keyspaces, pred.First())
+		list := pred.Second().Value()
+		if h == nil || list == nil || list.Type() != value.ARRAY {
+			return _DEF_IN_SELEC
+		}
+		vals, _ := list.Actual().([]interface{})
+		sel := 0.0
+		for _, v := range expression.SortValArr(vals) {
+			sel += statistics.EqSelec(h, value.NewValue(v))
+		}
+		return math.Min(sel, 1.0)
+	case *expression.Like:
+		return _DEF_LIKE_SELEC
+	case *expression.IsNull:
+		if h := keyHistogram(keyspaces, pred.Operand()); h != nil {
+			return statistics.NullSelec(h)
+		}
+		return _DEF_EQ_SELEC
+	case *expression.IsMissing:
+		if h := keyHistogram(keyspaces, pred.Operand()); h != nil {
+			return statistics.MissingSelec(h)
+		}
+		return _DEF_EQ_SELEC
+	case *expression.IsNotMissing:
+		if h := keyHistogram(keyspaces, pred.Operand()); h != nil {
+			return 1.0 - statistics.MissingSelec(h)
+		}
+		return 1.0 - _DEF_EQ_SELEC
+	case *expression.IsNotNull, *expression.IsValued:
+		if h := keyHistogram(keyspaces, pred.Children()[0]); h != nil {
+			return statistics.ValuedSelec(h)
+		}
+		return 1.0 - _DEF_EQ_SELEC
+	case *expression.IsNotValued:
+		if h := keyHistogram(keyspaces, pred.Operand()); h != nil {
+			return 1.0 - statistics.ValuedSelec(h)
+		}
+		return _DEF_EQ_SELEC
+	}
+	return _DEF_SELEC
+}
+
+func eqSelec(keyspaces map[string]string, first, second expression.Expression) float64 {
+	if first.Value() != nil {
+		first, second = second, first
+	}
+	h := keyHistogram(keyspaces, first)
+	if v := second.Value(); v != nil {
+		if h == nil {
+			return _DEF_EQ_SELEC
+		}
+		return statistics.EqSelec(h, v)
+	}
+
+	// join predicate: each value on one side matches 1/NDV of the other side
+	h2 := keyHistogram(keyspaces, second)
+	ndv := 0.0
+	if h != nil {
+		ndv = statistics.NDV(h)
+	}
+	if h2 != nil {
+		ndv = math.Max(ndv, statistics.NDV(h2))
+	}
+	if ndv == 0.0 {
+		for _, e := range []expression.Expression{first, second} {
+			if ks := exprKeyspace(keyspaces, e); ks != "" {
+				ndv = math.Max(ndv, float64(statistics.DocCount(ks)))
+			}
+		}
+	}
+	if ndv < 1.0 {
+		return _DEF_EQ_SELEC
+	}
+	return 1.0 / ndv
+}
+
+// first < second, or first <= second
+func compSelec(keyspaces map[string]string, first, second expression.Expression, incl bool) float64 {
+	key, v, less := first, second.Value(), true
+	if v == nil {
+		key, v, less = second, first.Value(), false
+	}
+	if v == nil {
+		return _DEF_RANGE_SELEC
+	}
+	h := keyHistogram(keyspaces, key)
+	if h == nil {
+		return _DEF_RANGE_SELEC
+	}
+
+	// comparisons only hold between values of the same type
+	low, high, lowIncl, highIncl, ok := statistics.TypeRange(v)
+	if !ok {
+		low, high = nil, nil
+	}
+	if less {
+		return statistics.RangeSelec(h, low, v, lowIncl, incl)
+	}
+	return statistics.RangeSelec(h, v, high, incl, highIncl)
+}
+
+// The histogram on an expression over a single keyspace
+func keyHistogram(keyspaces map[string]string, expr expression.Expression) *datastore.Histogram {
+	ks := exprKeyspace(keyspaces, expr)
+	if ks == "" {
+		return nil
+	}
+	return statistics.GetHistogram(ks, expr)
+}
+
+func exprKeyspace(keyspaces map[string]string, expr expression.Expression) string {
+	refs, err := expression.CountKeySpaces(expr, keyspaces)
+	if err != nil || len(refs) != 1 {
+		return ""
+	}
+	for _, ks := range refs {
+		return ks
+	}
+	return ""
+}
+
 func optDefInSelec(keyspace, alias string, key expression.Expression, advisorValidate bool) float64 {
-	return OPT_SELEC_NOT_AVAIL
+	if statistics.DocCount(keyspace) < 0 {
+		return OPT_SELEC_NOT_AVAIL
+	}
+	return _DEF_IN_SELEC
 }
 
 func optDefLikeSelec(keyspace, key string, advisorValidate bool) float64 {
-	return OPT_SELEC_NOT_AVAIL
+	if statistics.DocCount(keyspace) < 0 {
+		return OPT_SELEC_NOT_AVAIL
+	}
+	return _DEF_LIKE_SELEC
 }
 
 func optMarkIndexFilters(keys, includes expression.Expressions, spans, includeSpans plan.Spans2,
@@ -62,16 +282,80 @@ func optCheckUnnestRangeExprs(primKeyspace, unnestKeyspace *base.BaseKeyspace, u
 	// no-op
 }
 
-func primaryIndexScanCost(primary datastore.PrimaryIndex, requestId string, context *PrepareContext) (
+func primaryIndexScanCost(primary datastore.PrimaryIndex, keyspace, requestId string, context *PrepareContext) (
 	float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	docCount := statistics.DocCount(keyspace)
+	if docCount < 0 {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	cardinality := math.Max(float64(docCount), 1.0)
+	return _COST_SCAN_START + cardinality*_COST_INDEX_ENTRY, cardinality, _INDEX_ENTRY_SIZE,
+		_COST_SCAN_START + _COST_INDEX_ENTRY
 }
 
 func indexScanCost(entry *indexEntry, sargKeys, sargIncludes expression.Expressions,
 	requestId string, spans, includeSpans SargSpans, alias, keyspace string, limit, offset int64,
 	advisorValidate bool, context *PrepareContext) (float64, float64, float64, int64, float64, error) {
-	return OPT_COST_NOT_AVAIL, OPT_SELEC_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL,
-		errors.NewPlanInternalError("indexScanCost: unexpected in community edition")
+	docCount := statistics.DocCount(keyspace)
+	if docCount < 0 {
+		return OPT_COST_NOT_AVAIL, OPT_SELEC_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL,
+			OPT_COST_NOT_AVAIL, nil
+	}
+
+	selec := sargSpansSelec(spans)
+	if includeSpans != nil {
+		selec *= sargSpansSelec(includeSpans)
+	}
+	cardinality := math.Max(selec*float64(docCount), 1.0)
+	if limit > 0 {
+		cardinality = math.Min(cardinality, float64(limit+offset))
+	}
+	size := _INDEX_ENTRY_SIZE * int64(len(sargKeys)+len(sargIncludes)+1)
+	return _COST_SCAN_START + cardinality*_COST_INDEX_ENTRY, selec, cardinality, size,
+		_COST_SCAN_START + _COST_INDEX_ENTRY, nil
+}
+
+/*
+Fraction of the index the spans cover. Spans of a term add up; so do
+those of a union, while those of an intersection multiply. Within a span
+the keys are taken as independent.
+*/
+func sargSpansSelec(spans SargSpans) float64 {
+	sel := 1.0
+	switch spans := spans.(type) {
+	case *TermSpans:
+		sel = 0.0
+		for _, span := range spans.spans {
+			sel += span2Selec(span)
+		}
+	case *UnionSpans:
+		sel = 0.0
+		for _, s := range spans.spans {
+			sel += sargSpansSelec(s)
+		}
+	case *IntersectSpans:
+		for _, s := range spans.spans {
+			sel *= sargSpansSelec(s)
+		}
+	}
+	return math.Min(sel, 1.0)
+}
+
+func span2Selec(span *plan.Span2) float64 {
+	sel := 1.0
+	for _, rg := range span.Ranges {
+		s1, s2 := rg.Selec1, rg.Selec2
+		switch {
+		case s1 >= 0.0 && s2 >= 0.0:
+			// both bounds were estimated alone: the range is what both keep
+			sel *= math.Max(s1+s2-1.0, s1*s2)
+		case s1 >= 0.0:
+			sel *= s1
+		case s2 >= 0.0:
+			sel *= s2
+		}
+	}
+	return sel
 }
 
 func getIndexMinMaxCost(alias, keyspace string, indexKey expression.Expression,
@@ -96,7 +380,14 @@ func getKeyScanCost(keys expression.Expression) (float64, float64, int64, float6
 }
 
 func getFetchCost(keyspaceName string, cardinality float64) (float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	docSize := statistics.AvgDocSize(keyspaceName)
+	if docSize < 0 || cardinality <= 0.0 {
+		return OPT_COST_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	if docSize == 0 {
+		docSize = 1
+	}
+	return cardinality * _COST_FETCH, docSize, _COST_FETCH
 }
 
 func getDistinctScanCost(index datastore.Index, cardinality float64, spans plan.Spans2,
@@ -120,15 +411,70 @@ func getCountScanCost() (float64, float64, int64, float64) {
 	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
 }
 
-func getNLJoinCost(left, right plan.Operator, filters base.Filters, outer bool, op string) (
-	float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+func opCostAvail(ops ...plan.Operator) bool {
+	for _, op := range ops {
+		if op == nil || !costAvail(op.Cost(), op.Cardinality(), op.Size(), op.FrCost()) {
+			return false
+		}
+	}
+	return true
+}
+
+func costAvail(cost, cardinality float64, size int64, frCost float64) bool {
+	return cost > 0.0 && cardinality > 0.0 && size > 0 && frCost > 0.0
 }
 
+/*
+The inner side of a nested-loop join is planned with the join filters
+applied, so its cardinality is already per outer document.
+*/
+func getNLJoinCost(left, right plan.Operator, filters base.Filters, outer bool, op string) (
+	float64, float64, int64, float64) {
+	if !opCostAvail(left, right) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	cardinality := left.Cardinality() * right.Cardinality()
+	if outer {
+		cardinality = math.Max(cardinality, left.Cardinality())
+	}
+	cost := left.Cost() + left.Cardinality()*right.Cost()
+	return cost, math.Max(cardinality, 1.0), left.Size() + right.Size(), left.FrCost() + right.FrCost()
+}
+
+/*
+Both sides of a hash join are planned independently, so the join filters
+apply to their product. Unless forced, the smaller side is built.
+*/
 func getHashJoinCost(left, right plan.Operator, buildExprs, probeExprs expression.Expressions,
 	buildRight, force bool, filters base.Filters, outer bool, op string) (
 	float64, float64, int64, float64, bool) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL, false
+	if !opCostAvail(left, right) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL, false
+	}
+	if !force {
+		buildRight = outer || right.Cardinality() <= left.Cardinality()
+	}
+	cardinality := left.Cardinality() * right.Cardinality() * joinFiltersSelec(filters)
+	if outer {
+		cardinality = math.Max(cardinality, left.Cardinality())
+	}
+	build, probe := left, right
+	if buildRight {
+		build, probe = right, left
+	}
+	cost := left.Cost() + right.Cost() + (left.Cardinality()+right.Cardinality())*_COST_HASH
+	frCost := build.Cost() + probe.FrCost() + _COST_HASH
+	return cost, math.Max(cardinality, 1.0), left.Size() + right.Size(), frCost, buildRight
+}
+
+func joinFiltersSelec(filters base.Filters) float64 {
+	sel := 1.0
+	for _, fl := range filters {
+		if fl.IsJoin() && fl.Selec() > 0.0 {
+			sel *= fl.Selec()
+		}
+	}
+	return sel
 }
 
 func getLookupJoinCost(left plan.Operator, outer bool, right *algebra.KeyspaceTerm,
@@ -161,40 +507,124 @@ func getUnnestCost(node *algebra.Unnest, lastOp plan.Operator,
 
 func getSimpleFromTermCost(left, right plan.Operator, filters base.Filters, outer bool, op string) (
 	float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	return getNLJoinCost(left, right, filters, outer, op)
 }
 
 func getSimpleFilterCost(alias string, cost, cardinality, selec float64, size int64, frCost float64) (
 	float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !costAvail(cost, cardinality, size, frCost) || selec < 0.0 {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	return cost + cardinality*_COST_EVAL, math.Max(cardinality*selec, 1.0), size, frCost + _COST_EVAL
 }
 
 func getFilterCost(lastOp plan.Operator, expr expression.Expression,
 	baseKeyspaces map[string]*base.BaseKeyspace, keyspaceNames map[string]string,
 	alias string, advisorValidate bool, context *PrepareContext) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
-}
-
+	if !opCostAvail(lastOp) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	return getFilterCostWithInput(expr, baseKeyspaces, keyspaceNames, alias, lastOp.Cost(),
+		lastOp.Cardinality(), lastOp.Size(), lastOp.FrCost(), advisorValidate, context)
+}
+
+/*
+The scan feeding a filter may have applied some of its predicates already.
+Those on the filter's own keyspace are thus estimated against the whole
+keyspace, and cap the input rather than scale it down again.
+*/
 func getFilterCostWithInput(expr expression.Expression, baseKeyspaces map[string]*base.BaseKeyspace,
 	keyspaceNames map[string]string, alias string, cost, cardinality float64, size int64, frCost float64,
 	advisorValidate bool, context *PrepareContext) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !costAvail(cost, cardinality, size, frCost) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+
+	var local, other expression.Expressions
+	for _, term := range andTerms(expr) {
+		refs, err := expression.CountKeySpaces(term, keyspaceNames)
+		if err != nil {
+			return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+		}
+		if _, ok := refs[alias]; ok && len(refs) == 1 {
+			local = append(local, term)
+		} else {
+			other = append(other, term)
+		}
+	}
+
+	outCard := cardinality
+	if len(local) > 0 {
+		sel, _ := optExprSelec(keyspaceNames, expression.NewAnd(local...), advisorValidate, context)
+		baseKeyspace, ok := baseKeyspaces[alias]
+		if sel < 0.0 || !ok || baseKeyspace.DocCount() < 0 {
+			return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+		}
+		outCard = math.Min(outCard, sel*float64(baseKeyspace.DocCount()))
+	}
+	if len(other) > 0 {
+		sel, _ := optExprSelec(keyspaceNames, expression.NewAnd(other...), advisorValidate, context)
+		if sel < 0.0 {
+			return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+		}
+		outCard *= sel
+	}
+	return cost + cardinality*_COST_EVAL, math.Max(outCard, 1.0), size, frCost + _COST_EVAL
+}
+
+func andTerms(expr expression.Expression) expression.Expressions {
+	if and, ok := expr.(*expression.And); ok {
+		var rv expression.Expressions
+		for _, op := range and.Operands() {
+			rv = append(rv, andTerms(op)...)
+		}
+		return rv
+	}
+	return expression.Expressions{expr}
+}
+
+func passThroughCost(lastOp plan.Operator, unitCost float64) (float64, float64, int64, float64) {
+	if !opCostAvail(lastOp) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	return lastOp.Cost() + lastOp.Cardinality()*unitCost, lastOp.Cardinality(), lastOp.Size(),
+		lastOp.FrCost() + unitCost
 }
 
 func getLetCost(lastOp plan.Operator) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	return passThroughCost(lastOp, _COST_EVAL)
 }
 
 func getWithCost(lastOp plan.Operator, with expression.Withs) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	return passThroughCost(lastOp, _COST_EVAL)
 }
 
 func getOffsetCost(lastOp plan.Operator, noffset int64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !opCostAvail(lastOp) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	cardinality := lastOp.Cardinality()
+	if noffset > 0 {
+		cardinality = math.Max(cardinality-float64(noffset), 1.0)
+	}
+	return lastOp.Cost(), cardinality, lastOp.Size(), lastOp.FrCost()
 }
 
+// A limit stops its input early, saving the cost of the documents it never asks for
 func getLimitCost(lastOp plan.Operator, nlimit, noffset int64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !opCostAvail(lastOp) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	cost, cardinality := lastOp.Cost(), lastOp.Cardinality()
+	if noffset < 0 {
+		noffset = 0
+	}
+	if nlimit >= 0 && float64(nlimit+noffset) < cardinality {
+		fraction := float64(nlimit+noffset) / cardinality
+		cost = lastOp.FrCost() + (cost-lastOp.FrCost())*fraction
+		cardinality = math.Max(float64(nlimit), 1.0)
+	}
+	return cost, cardinality, lastOp.Size(), lastOp.FrCost()
 }
 
 func getUnnestPredSelec(pred expression.Expression, variable string, mapping expression.Expression,
@@ -208,84 +638,186 @@ func optChooseIntersectScan(keyspace datastore.Keyspace, sargables map[datastore
 	return sargables
 }
 
+// The cost returned is that of the sort alone
 func getSortCost(totalSize int64, nterms int, cardinality float64, limit, offset int64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if cardinality <= 0.0 || totalSize <= 0 {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	if nterms < 1 {
+		nterms = 1
+	}
+	cost := math.Max(cardinality*math.Log2(cardinality+1.0), 1.0) * float64(nterms) * _COST_SORT
+	if offset < 0 {
+		offset = 0
+	}
+	if limit >= 0 && float64(limit+offset) < cardinality {
+		cardinality = math.Max(float64(limit+offset), 1.0)
+	}
+	return cost, cardinality, totalSize, cost
 }
 
 func getInitialProjectCost(projection *algebra.Projection, cost, cardinality float64,
 	size int64, frCost float64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !costAvail(cost, cardinality, size, frCost) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	nterms := float64(len(projection.Terms()))
+	return cost + cardinality*nterms*_COST_EVAL, cardinality, size, frCost + nterms*_COST_EVAL
+}
+
+/*
+The number of groups is the product of the number of distinct values of
+the group keys, as far as histograms tell, and at most the number of
+documents grouped.
+*/
+func groupCardinality(exprs expression.Expressions, keyspaces map[string]string, cardinality float64) float64 {
+	groups := 1.0
+	for _, expr := range exprs {
+		h := keyHistogram(keyspaces, expr)
+		if h == nil {
+			return cardinality
+		}
+		groups *= statistics.NDV(h)
+	}
+	return math.Max(math.Min(groups, cardinality), 1.0)
 }
 
 func getGroupCosts(group *algebra.Group, aggregates algebra.Aggregates, cost, cardinality float64,
 	size int64, keyspaces map[string]string, maxParallelism int) (
 	float64, float64, float64, float64, float64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL
+	if cost <= 0.0 || cardinality <= 0.0 || size <= 0 {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL,
+			OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL
+	}
+	if maxParallelism < 1 {
+		maxParallelism = 1
+	}
+	groups := groupCardinality(group.By(), keyspaces, cardinality)
+	aggCost := _COST_HASH + float64(len(aggregates))*_COST_EVAL
+
+	costInitial := cost + cardinality*aggCost
+	cardInitial := math.Min(groups*float64(maxParallelism), cardinality)
+	costIntermediate := costInitial + cardInitial*aggCost
+	costFinal := costIntermediate + groups*aggCost
+	return costInitial, cardInitial, costIntermediate, cardInitial, costFinal, groups
 }
 
 func getDistinctCost(terms algebra.ResultTerms, cost, cardinality float64, size int64, frCost float64,
 	keyspaces map[string]string) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !costAvail(cost, cardinality, size, frCost) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	exprs := make(expression.Expressions, 0, len(terms))
+	for _, term := range terms {
+		if term.Star() {
+			exprs = nil
+			break
+		}
+		exprs = append(exprs, term.Expression())
+	}
+	distinct := cardinality
+	if len(exprs) > 0 {
+		distinct = groupCardinality(exprs, keyspaces, cardinality)
+	}
+	return cost + cardinality*_COST_HASH, distinct, size, frCost + _COST_HASH
 }
 
 func getUnionDistinctCost(cost, cardinality float64, first, second plan.Operator, compatible bool) (float64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL
+	if cost <= 0.0 || cardinality <= 0.0 {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL
+	}
+	return cost + cardinality*_COST_HASH, cardinality
 }
 
 func getUnionAllCost(first, second plan.Operator, compatible bool) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !opCostAvail(first, second) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	size := first.Size()
+	if second.Size() > size {
+		size = second.Size()
+	}
+	return first.Cost() + second.Cost(), first.Cardinality() + second.Cardinality(), size,
+		math.Min(first.FrCost(), second.FrCost())
 }
 
 func getIntersectAllCost(first, second plan.Operator, compatible bool) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !opCostAvail(first, second) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	cost := first.Cost() + second.Cost() + (first.Cardinality()+second.Cardinality())*_COST_HASH
+	return cost, math.Min(first.Cardinality(), second.Cardinality()), first.Size(), second.Cost() + first.FrCost()
 }
 
 func getExceptAllCost(first, second plan.Operator, compatible bool) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !opCostAvail(first, second) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	cost := first.Cost() + second.Cost() + (first.Cardinality()+second.Cardinality())*_COST_HASH
+	return cost, first.Cardinality(), first.Size(), second.Cost() + first.FrCost()
+}
+
+func writeCost(cost, cardinality float64, size int64, frCost float64) (float64, float64, int64, float64) {
+	if !costAvail(cost, cardinality, size, frCost) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	return cost + cardinality*_COST_WRITE, cardinality, size, frCost + _COST_WRITE
 }
 
 func getInsertCost(key, value, options, limit expression.Expression, cost, cardinality float64,
 	size int64, frCost float64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	return writeCost(cost, cardinality, size, frCost)
 }
 
 func getUpsertCost(key, value, options expression.Expression, cost, cardinality float64,
 	size int64, frCost float64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	return writeCost(cost, cardinality, size, frCost)
 }
 
 func getDeleteCost(limit expression.Expression, cost, cardinality float64,
 	size int64, frCost float64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	return writeCost(cost, cardinality, size, frCost)
 }
 
 func getCloneCost(cost, cardinality float64, size int64, frCost float64) (
 	float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !costAvail(cost, cardinality, size, frCost) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	return cost + cardinality*_COST_EVAL, cardinality, size, frCost + _COST_EVAL
 }
 
 func getUpdateSetCost(set *algebra.Set, cost, cardinality float64,
 	size int64, frCost float64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	return getCloneCost(cost, cardinality, size, frCost)
 }
 
 func getUpdateUnsetCost(unset *algebra.Unset, cost, cardinality float64,
 	size int64, frCost float64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	return getCloneCost(cost, cardinality, size, frCost)
 }
 
 func getUpdateSendCost(limit expression.Expression, cost, cardinality float64,
 	size int64, frCost float64) (float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	return writeCost(cost, cardinality, size, frCost)
 }
 
 func getWindowAggCost(aggs algebra.Aggregates, cost, cardinality float64, size int64, frCost float64) (
 	float64, float64, int64, float64) {
-	return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	if !costAvail(cost, cardinality, size, frCost) {
+		return OPT_COST_NOT_AVAIL, OPT_CARD_NOT_AVAIL, OPT_SIZE_NOT_AVAIL, OPT_COST_NOT_AVAIL
+	}
+	aggCost := float64(len(aggs)) * _COST_EVAL
+	return cost + cardinality*aggCost, cardinality, size, frCost + aggCost
 }
 
 func getKeyspaceSize(keyspace string) int64 {
-	return OPT_SIZE_NOT_AVAIL
+	docCount := statistics.DocCount(keyspace)
+	docSize := statistics.AvgDocSize(keyspace)
+	if docCount < 0 || docSize < 0 {
+		return OPT_SIZE_NOT_AVAIL
+	}
+	return docCount * docSize
 }
 
 func optGetJoinFilterSelec(selec, cardinality float64) float64 {
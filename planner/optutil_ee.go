@@ -76,7 +76,7 @@ func optCheckUnnestRangeExprs(primKeyspace, unnestKeyspace *base.BaseKeyspace, u
 	optutil.CheckUnnestRangeExprs(primKeyspace, unnestKeyspace, unnestExpr, advisorValidate, context)
 }
 
-func primaryIndexScanCost(primary datastore.PrimaryIndex, requestId string, context *PrepareContext) (
+func primaryIndexScanCost(primary datastore.PrimaryIndex, keyspace, requestId string, context *PrepareContext) (
 	float64, float64, int64, float64) {
 	return optutil.CalcPrimaryIndexScanCost(primary, requestId, context)
 }
@@ -725,3 +725,13 @@ func adjustAvgDistSelec(ks *base.BaseKeyspace, advisorValidate bool, baseKeyspac
 func hasQueryMetadata(create bool, requestId, createReason string, waitOnCreate bool) (bool, errors.Error) {
 	return dictionary.HasQueryMetadata(create, requestId, createReason, waitOnCreate)
 }
+
+func optReorderJoins(node *algebra.Subselect, context *PrepareContext) algebra.FromTerm {
+	// joins are enumerated by the optimizer
+	return nil
+}
+
+func optStatisticsCBO() bool {
+	// statistics are only used when use_cbo is set
+	return false
+}
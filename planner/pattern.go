@@ -16,6 +16,7 @@ import (
 	"github.com/couchbase/query/expression"
 	base "github.com/couchbase/query/plannerbase"
 	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
 )
 
 func (this *builder) PatternFor(baseKeyspace *base.BaseKeyspace, indexes []datastore.Index,
@@ -30,9 +31,11 @@ func (this *builder) PatternFor(baseKeyspace *base.BaseKeyspace, indexes []datas
 	defer _PATTERN_INDEX_POOL.Put(suffixes)
 	tokens := _PATTERN_INDEX_POOL.Get()
 	defer _PATTERN_INDEX_POOL.Put(tokens)
+	trigrams := _PATTERN_INDEX_POOL.Get()
+	defer _PATTERN_INDEX_POOL.Put(trigrams)
 
-	collectPatternIndexes(pred, indexes, formalizer, suffixes, tokens)
-	if len(suffixes) == 0 && len(tokens) == 0 {
+	collectPatternIndexes(pred, indexes, formalizer, suffixes, tokens, trigrams)
+	if len(suffixes) == 0 && len(tokens) == 0 && len(trigrams) == 0 {
 		return nil
 	}
 
@@ -44,7 +47,7 @@ func (this *builder) PatternFor(baseKeyspace *base.BaseKeyspace, indexes []datas
 		return err
 	}
 
-	pat := newPattern(suffixes, tokens)
+	pat := newPattern(suffixes, tokens, trigrams)
 	rv, err := pred.Accept(pat)
 	if err != nil {
 		return err
@@ -81,12 +84,14 @@ type pattern struct {
 
 	suffixes map[string]string
 	tokens   map[string]string
+	trigrams map[string]string
 }
 
-func newPattern(suffixes, tokens map[string]string) *pattern {
+func newPattern(suffixes, tokens, trigrams map[string]string) *pattern {
 	rv := &pattern{
 		suffixes: suffixes,
 		tokens:   tokens,
+		trigrams: trigrams,
 	}
 
 	rv.SetMapper(rv)
@@ -108,6 +113,55 @@ func (this *pattern) VisitLike(expr *expression.Like) (interface{}, error) {
 	return expression.NewAnd(expr, any), nil
 }
 
+/*
+SIMILARITY(x, q) > t, with t >= 0, implies that x and q share at least one
+trigram. If TRIGRAMS(x) is array indexed, add that as an index pre-filter.
+*/
+func (this *pattern) VisitLT(expr *expression.LT) (interface{}, error) {
+	if any := this.similarityFilter(expr.First(), expr.Second(), false); any != nil {
+		return expression.NewAnd(expr, any), nil
+	}
+	return this.MapperBase.VisitLT(expr)
+}
+
+func (this *pattern) VisitLE(expr *expression.LE) (interface{}, error) {
+	if any := this.similarityFilter(expr.First(), expr.Second(), true); any != nil {
+		return expression.NewAnd(expr, any), nil
+	}
+	return this.MapperBase.VisitLE(expr)
+}
+
+func (this *pattern) similarityFilter(threshold, other expression.Expression, inclusive bool) expression.Expression {
+	sim, ok := other.(*expression.Similarity)
+	if !ok || len(this.trigrams) == 0 {
+		return nil
+	}
+
+	tv := threshold.Value()
+	if tv == nil || tv.Type() != value.NUMBER {
+		return nil
+	}
+
+	t := value.AsNumberValue(tv).Float64()
+	if t < 0.0 || (inclusive && t == 0.0) {
+		return nil
+	}
+
+	source, query := sim.First(), sim.Second()
+	variable, ok := this.trigrams[source.String()]
+	if !ok {
+		source, query = query, source
+		variable, ok = this.trigrams[source.String()]
+		if !ok {
+			return nil
+		}
+	}
+
+	binding := expression.NewSimpleBinding(variable, expression.NewTrigrams(source))
+	sat := expression.NewIn(expression.NewIdentifier(variable), expression.NewTrigrams(query.Copy()))
+	return expression.NewAny(expression.Bindings{binding}, sat)
+}
+
 func (this *pattern) VisitFunction(expr expression.Function) (interface{}, error) {
 	switch expr := expr.(type) {
 	case *expression.Contains:
@@ -236,7 +290,7 @@ func (this *pattern) visitRegexpLike(expr *expression.RegexpLike) (interface{},
 }
 
 func collectPatternIndexes(pred expression.Expression, indexes []datastore.Index,
-	formalizer *expression.Formalizer, suffixes, tokens map[string]string) {
+	formalizer *expression.Formalizer, suffixes, tokens, trigrams map[string]string) {
 
 	var err error
 outer:
@@ -271,6 +325,9 @@ outer:
 				tokVar := _DEFAULT_SUFFIXES_VARIABLE
 				tok, _ := all.Array().(*expression.Tokens)
 
+				triVar := _DEFAULT_TRIGRAMS_VARIABLE
+				tri, _ := all.Array().(*expression.Trigrams)
+
 				if array, ok := all.Array().(*expression.Array); ok && len(array.Bindings()) == 1 {
 					binding := array.Bindings()[0]
 
@@ -284,6 +341,10 @@ outer:
 						if tok, ok = binding.Expression().(*expression.Tokens); ok {
 							tokVar = binding.Variable()
 						}
+
+						if tri, ok = binding.Expression().(*expression.Trigrams); ok {
+							triVar = binding.Variable()
+						}
 					}
 				}
 
@@ -312,6 +373,19 @@ outer:
 					tokens[op.String()] = tokVar
 					continue outer
 				}
+
+				if tri != nil {
+					op := tri.Operand().Copy()
+					formalizer.SetIndexScope()
+					op, err = formalizer.Map(op)
+					formalizer.ClearIndexScope()
+					if err != nil {
+						continue outer
+					}
+
+					trigrams[op.String()] = triVar
+					continue outer
+				}
 			}
 		}
 	}
@@ -320,3 +394,4 @@ outer:
 var _PATTERN_INDEX_POOL = util.NewStringStringPool(64)
 var _DEFAULT_SUFFIXES_VARIABLE = "s"
 var _DEFAULT_TOKENS_VARIABLE = "t"
+var _DEFAULT_TRIGRAMS_VARIABLE = "g"
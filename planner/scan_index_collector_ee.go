@@ -237,6 +237,10 @@ func (this *scanIdxCol) VisitHashJoin(op *plan.HashJoin) (interface{}, error) {
 	return nil, nil
 }
 
+func (this *scanIdxCol) VisitAsofJoin(op *plan.AsofJoin) (interface{}, error) {
+	return nil, nil
+}
+
 func (this *scanIdxCol) VisitHashNest(op *plan.HashNest) (interface{}, error) {
 	return nil, nil
 }
@@ -267,6 +271,10 @@ func (this *scanIdxCol) VisitFinalGroup(op *plan.FinalGroup) (interface{}, error
 	return nil, nil
 }
 
+func (this *scanIdxCol) VisitGapFill(op *plan.GapFill) (interface{}, error) {
+	return nil, nil
+}
+
 // Window functions
 func (this *scanIdxCol) VisitWindowAggregate(op *plan.WindowAggregate) (interface{}, error) {
 	return nil, nil
@@ -461,6 +469,10 @@ func (this *scanIdxCol) VisitFlushCollection(op *plan.FlushCollection) (interfac
 	return nil, nil
 }
 
+func (this *scanIdxCol) VisitTruncate(op *plan.Truncate) (interface{}, error) {
+	return nil, nil
+}
+
 // Users
 func (this *scanIdxCol) VisitCreateUser(op *plan.CreateUser) (interface{}, error) {
 	return nil, nil
@@ -697,6 +709,46 @@ func (this *scanIdxCol) VisitAlterSequence(op *plan.AlterSequence) (interface{},
 	return nil, nil
 }
 
+func (this *scanIdxCol) VisitCreateView(op *plan.CreateView) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *scanIdxCol) VisitDropView(op *plan.DropView) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *scanIdxCol) VisitRefreshView(op *plan.RefreshView) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *scanIdxCol) VisitCreateTrigger(op *plan.CreateTrigger) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *scanIdxCol) VisitDropTrigger(op *plan.DropTrigger) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *scanIdxCol) VisitCreateJob(op *plan.CreateJob) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *scanIdxCol) VisitAlterJob(op *plan.AlterJob) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *scanIdxCol) VisitDropJob(op *plan.DropJob) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *scanIdxCol) VisitCreatePolicy(op *plan.CreatePolicy) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *scanIdxCol) VisitDropPolicy(op *plan.DropPolicy) (interface{}, error) {
+	return nil, nil
+}
+
 func (this *scanIdxCol) VisitCreateCredentialStore(op *plan.CreateCredentialStore) (any, error) {
 	return nil, nil
 }
@@ -215,6 +215,14 @@ func (this *collector) VisitHashJoin(plop *plan.HashJoin) (interface{}, error) {
 	return nil, nil
 }
 
+func (this *collector) VisitAsofJoin(plop *plan.AsofJoin) (interface{}, error) {
+	_, err := plop.Child().Accept(this)
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
 func (this *collector) VisitNest(plop *plan.Nest) (interface{}, error) {
 	return nil, nil
 }
@@ -271,6 +279,10 @@ func (this *collector) VisitFinalGroup(plop *plan.FinalGroup) (interface{}, erro
 	return nil, nil
 }
 
+func (this *collector) VisitGapFill(plop *plan.GapFill) (interface{}, error) {
+	return nil, nil
+}
+
 func (this *collector) VisitWindowAggregate(plop *plan.WindowAggregate) (interface{}, error) {
 	return nil, nil
 }
@@ -481,6 +493,10 @@ func (this *collector) VisitFlushCollection(plop *plan.FlushCollection) (interfa
 	return nil, nil
 }
 
+func (this *collector) VisitTruncate(plop *plan.Truncate) (interface{}, error) {
+	return nil, nil
+}
+
 func (this *collector) VisitPrepare(plop *plan.Prepare) (interface{}, error) {
 	_, err := plop.Plan().Accept(this)
 	if err != nil {
@@ -569,6 +585,46 @@ func (this *collector) VisitAlterSequence(plop *plan.AlterSequence) (interface{}
 	return nil, nil
 }
 
+func (this *collector) VisitCreateView(plop *plan.CreateView) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *collector) VisitDropView(plop *plan.DropView) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *collector) VisitRefreshView(plop *plan.RefreshView) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *collector) VisitCreateTrigger(plop *plan.CreateTrigger) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *collector) VisitDropTrigger(plop *plan.DropTrigger) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *collector) VisitCreateJob(plop *plan.CreateJob) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *collector) VisitAlterJob(plop *plan.AlterJob) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *collector) VisitDropJob(plop *plan.DropJob) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *collector) VisitCreatePolicy(plop *plan.CreatePolicy) (interface{}, error) {
+	return nil, nil
+}
+
+func (this *collector) VisitDropPolicy(plop *plan.DropPolicy) (interface{}, error) {
+	return nil, nil
+}
+
 func (this *collector) VisitCreateCredentialStore(plop *plan.CreateCredentialStore) (any, error) {
 	return nil, nil
 }
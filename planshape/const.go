@@ -125,6 +125,19 @@ const (
 	CREATECREDENTIALSTORE
 	ALTERCREDENTIALSTORE
 	DROPCREDENTIALSTORE
+	CREATEVIEW
+	DROPVIEW
+	REFRESHVIEW
+	TRUNCATE
+	CREATETRIGGER
+	DROPTRIGGER
+	GAPFILL
+	ASOFJOIN
+	CREATEJOB
+	ALTERJOB
+	DROPJOB
+	CREATEPOLICY
+	DROPPOLICY
 )
 
 const (
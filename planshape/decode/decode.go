@@ -146,6 +146,18 @@ var simple = map[byte]string{
 	planshape.CREATECREDENTIALSTORE: "CreateCredentialStore",
 	planshape.ALTERCREDENTIALSTORE:  "AlterCredentialStore",
 	planshape.DROPCREDENTIALSTORE:   "DropCredentialStore",
+	planshape.CREATEVIEW:            "CreateView",
+	planshape.DROPVIEW:              "DropView",
+	planshape.REFRESHVIEW:           "RefreshView",
+	planshape.TRUNCATE:              "Truncate",
+	planshape.CREATETRIGGER:         "CreateTrigger",
+	planshape.DROPTRIGGER:           "DropTrigger",
+	planshape.GAPFILL:               "GapFill",
+	planshape.CREATEJOB:             "CreateJob",
+	planshape.ALTERJOB:              "AlterJob",
+	planshape.DROPJOB:               "DropJob",
+	planshape.CREATEPOLICY:          "CreatePolicy",
+	planshape.DROPPOLICY:            "DropPolicy",
 }
 
 func decodePSElem(buf []byte, i io.Reader, o io.StringWriter) bool {
@@ -257,6 +269,8 @@ func decodePSElem(buf []byte, i io.Reader, o io.StringWriter) bool {
 		return simpleChildren(buf, i, o, "HashJoin")
 	case planshape.HASHNEST:
 		return simpleChildren(buf, i, o, "HashNest")
+	case planshape.ASOFJOIN:
+		return simpleChildren(buf, i, o, "AsofJoin")
 	case planshape.WITH:
 		return simpleChildren(buf, i, o, "With")
 	case planshape.UNIONALL:
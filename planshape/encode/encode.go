@@ -335,6 +335,21 @@ func (this *planShape) VisitFinalGroup(op *execution.FinalGroup) (interface{}, e
 	return nil, nil
 }
 
+func (this *planShape) VisitAsofJoin(op *execution.AsofJoin) (interface{}, error) {
+	this.add(planshape.ASOFJOIN)
+	_, e := op.Child().Accept(this)
+	if e != nil {
+		return nil, e
+	}
+	this.addNUL()
+	return nil, nil
+}
+
+func (this *planShape) VisitGapFill(op *execution.GapFill) (interface{}, error) {
+	this.add(planshape.GAPFILL)
+	return nil, nil
+}
+
 func (this *planShape) VisitWindowAggregate(op *execution.WindowAggregate) (interface{}, error) {
 	this.add(planshape.WINDOWAGGREGATE)
 	return nil, nil
@@ -596,6 +611,11 @@ func (this *planShape) VisitFlushCollection(op *execution.FlushCollection) (inte
 	return nil, nil
 }
 
+func (this *planShape) VisitTruncate(op *execution.Truncate) (interface{}, error) {
+	this.add(planshape.TRUNCATE)
+	return nil, nil
+}
+
 func (this *planShape) VisitGrantRole(op *execution.GrantRole) (interface{}, error) {
 	this.add(planshape.GRANTROLE)
 	return nil, nil
@@ -716,6 +736,56 @@ func (this *planShape) VisitDropCredentialStore(op *execution.DropCredentialStor
 	return nil, nil
 }
 
+func (this *planShape) VisitCreateView(op *execution.CreateView) (interface{}, error) {
+	this.add(planshape.CREATEVIEW)
+	return nil, nil
+}
+
+func (this *planShape) VisitDropView(op *execution.DropView) (interface{}, error) {
+	this.add(planshape.DROPVIEW)
+	return nil, nil
+}
+
+func (this *planShape) VisitRefreshView(op *execution.RefreshView) (interface{}, error) {
+	this.add(planshape.REFRESHVIEW)
+	return nil, nil
+}
+
+func (this *planShape) VisitCreateTrigger(op *execution.CreateTrigger) (interface{}, error) {
+	this.add(planshape.CREATETRIGGER)
+	return nil, nil
+}
+
+func (this *planShape) VisitDropTrigger(op *execution.DropTrigger) (interface{}, error) {
+	this.add(planshape.DROPTRIGGER)
+	return nil, nil
+}
+
+func (this *planShape) VisitCreateJob(op *execution.CreateJob) (interface{}, error) {
+	this.add(planshape.CREATEJOB)
+	return nil, nil
+}
+
+func (this *planShape) VisitAlterJob(op *execution.AlterJob) (interface{}, error) {
+	this.add(planshape.ALTERJOB)
+	return nil, nil
+}
+
+func (this *planShape) VisitDropJob(op *execution.DropJob) (interface{}, error) {
+	this.add(planshape.DROPJOB)
+	return nil, nil
+}
+
+func (this *planShape) VisitCreatePolicy(op *execution.CreatePolicy) (interface{}, error) {
+	this.add(planshape.CREATEPOLICY)
+	return nil, nil
+}
+
+func (this *planShape) VisitDropPolicy(op *execution.DropPolicy) (interface{}, error) {
+	this.add(planshape.DROPPOLICY)
+	return nil, nil
+}
+
 func (this *planShape) VisitCreateBucket(op *execution.CreateBucket) (interface{}, error) {
 	this.add(planshape.CREATEBUCKET)
 	return nil, nil
@@ -0,0 +1,598 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+/*
+Package policies stores the row-level security policies defined on
+collections in the bucket's system collection, alongside UDFs, views and
+triggers. All the policies of a collection are kept in a single document,
+so that the planner can find them with one fetch, and each node caches
+them until a change is announced through a metakv revision counter.
+
+A policy is kept as the text of its predicate, relative to the documents
+of the collection as index keys are, together with the statement it
+applies to and the roles and groups it is granted to. The planner parses
+the predicate and adds it to every read of the collection by the
+statement; prepared plans record the revision they were built under and
+are prepared again when it changes.
+*/
+package policies
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/couchbase/cbauth/metakv"
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/distributed"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/value"
+)
+
+const _POLICY = "policy::"
+const _BATCH_SIZE = 512
+const _MAX_RETRIES = 5
+const _CACHE_REVISION_PATH = "/query/policies_cache/"
+const _CACHE_REVISION = _CACHE_REVISION_PATH + "revision"
+
+// The statements a policy applies to, or MASK for masking policies
+const (
+	SELECT = "select"
+	UPDATE = "update"
+	DELETE = "delete"
+	MASK   = "mask"
+)
+
+// The grantee of a policy that applies to every user
+const PUBLIC = "public"
+
+/*
+A stored policy definition. A masking policy replaces the value of the
+field Column by its Predicate for all users but those with the Roles
+listed.
+*/
+type Policy struct {
+	Name      string
+	Keyspace  string
+	Event     string
+	Column    string
+	Roles     []string
+	Predicate string
+
+	parseOnce  sync.Once
+	definition interface{}
+	parseErr   error
+}
+
+func (this *Policy) FullName() string {
+	return FullName(this.Name, this.Keyspace)
+}
+
+// Whether the policy applies to all users rather than to a list of roles and groups
+func (this *Policy) Public() bool {
+	return len(this.Roles) == 0
+}
+
+/*
+The parsed predicate of the policy. Parsing is left to the caller, as
+this package sits below the parser, and is only done once per cached
+definition.
+*/
+func (this *Policy) Definition(parse func(*Policy) (interface{}, error)) (interface{}, error) {
+	this.parseOnce.Do(func() {
+		this.definition, this.parseErr = parse(this)
+	})
+	return this.definition, this.parseErr
+}
+
+func FullName(name string, keyspace string) string {
+	return name + " on " + keyspace
+}
+
+type cacheEntry struct {
+	policies []*Policy
+	rev      int32
+}
+
+var cache struct {
+	sync.RWMutex
+	keyspaces map[string]*cacheEntry
+}
+
+var cacheRevision int32 = 1
+
+func init() {
+	cache.keyspaces = make(map[string]*cacheEntry)
+}
+
+// Starts monitoring the policies of the cluster; to be called once at server startup
+func Init() {
+	err := metakv.Add(_CACHE_REVISION, fmtCacheRevision())
+	if err != nil && err != metakv.ErrRevMismatch {
+		logging.Warnf("Unable to start policies cache monitor: %v", err)
+	}
+	go metakv.RunObserveChildren(_CACHE_REVISION_PATH, policyChangeMonitor, make(chan struct{}))
+}
+
+func policyChangeMonitor(kve metakv.KVEntry) error {
+	if kve.Path != _CACHE_REVISION {
+		return nil
+	}
+	node, _ := distributed.RemoteAccess().SplitKey(string(kve.Value))
+	if node == "" || node != distributed.RemoteAccess().WhoAmI() {
+		atomic.AddInt32(&cacheRevision, 1)
+	}
+	return nil
+}
+
+func nextRevision() {
+	atomic.AddInt32(&cacheRevision, 1)
+	err := metakv.Set(_CACHE_REVISION, fmtCacheRevision(), nil)
+	if err != nil && err.Error() == "Not found" {
+		err = metakv.Add(_CACHE_REVISION, fmtCacheRevision())
+	}
+	if err != nil {
+		logging.Infof("Unable to update policies cache monitor %v", errors.NewMetaKVChangeCounterError(err))
+	}
+}
+
+func fmtCacheRevision() []byte {
+	return []byte(distributed.RemoteAccess().MakeKey(distributed.RemoteAccess().WhoAmI(),
+		strconv.Itoa(int(atomic.LoadInt32(&cacheRevision)))))
+}
+
+/*
+The revision of the policies known to this node. Plans are built against
+a revision, and are no longer valid once it has changed.
+*/
+func Revision() int32 {
+	return atomic.LoadInt32(&cacheRevision)
+}
+
+// The collection underlying a keyspace, which for a bucket is its default collection
+func collectionPath(ks datastore.Keyspace) *algebra.Path {
+	elements := algebra.ParsePath(ks.QualifiedName())
+	if len(elements) != 4 || elements[0] == datastore.SYSTEM_NAMESPACE {
+		return nil
+	}
+	return algebra.NewPathFromElements(elements)
+}
+
+func getStorageKey(uid string, scope string, collection string) string {
+	return _POLICY + uid + "::" + scope + "." + collection
+}
+
+func getCacheKey(namespace string, bucket string, key string) string {
+	return namespace + ":" + bucket + "." + trimPrefixAndScopeUid(key)
+}
+
+func trimPrefixAndScopeUid(key string) string {
+	if len(key) > len(_POLICY)+10 && strings.HasPrefix(key, _POLICY) {
+		key = key[len(_POLICY)+10:]
+	}
+	return key
+}
+
+func getSystemCollection(bucket string) (datastore.Keyspace, errors.Error) {
+	store := datastore.GetDatastore()
+	if store == nil {
+		return nil, errors.NewNoDatastoreError()
+	}
+	ks, err := store.GetSystemCollection(bucket)
+	if err == nil && ks == nil {
+		err = errors.NewPolicyError(errors.E_POLICY_NOT_ENABLED, bucket, err)
+	} else if err != nil && err.Code() == errors.E_CB_SCOPE_NOT_FOUND {
+		err = errors.NewPolicyError(errors.E_POLICY_NOT_ENABLED, bucket, nil)
+	}
+	return ks, err
+}
+
+// Fetches the document holding the policies of a collection, or nil if there is none
+func fetchPolicies(sys datastore.Keyspace, key string) (value.AnnotatedValue, errors.Error) {
+	res := make(map[string]value.AnnotatedValue, 1)
+	errs := sys.Fetch([]string{key}, res, datastore.NULL_QUERY_CONTEXT, nil, nil, false)
+	if len(errs) > 0 {
+		if !errors.IsNotFoundError("", errs[0]) && !errs[0].HasCause(errors.E_CB_BULK_GET) {
+			return nil, errs[0]
+		}
+		return nil, nil
+	}
+	return res[key], nil
+}
+
+func decodePolicies(av value.AnnotatedValue, keyspace string) []*Policy {
+	if av == nil {
+		return nil
+	}
+	list, ok := av.Field("policies")
+	if !ok || list.Type() != value.ARRAY {
+		return nil
+	}
+	entries := list.Actual().([]interface{})
+	rv := make([]*Policy, 0, len(entries))
+	for _, e := range entries {
+		entry := value.NewValue(e)
+		p := &Policy{Keyspace: keyspace}
+		for f, s := range map[string]*string{"name": &p.Name, "event": &p.Event, "column": &p.Column,
+			"predicate": &p.Predicate} {
+			if v, ok := entry.Field(f); ok && v.Type() == value.STRING {
+				*s = v.ToString()
+			}
+		}
+		if v, ok := entry.Field("roles"); ok && v.Type() == value.ARRAY {
+			for _, r := range v.Actual().([]interface{}) {
+				if r, ok := r.(string); ok {
+					p.Roles = append(p.Roles, r)
+				}
+			}
+		}
+		rv = append(rv, p)
+	}
+	return rv
+}
+
+func encodePolicies(keyspace string, list []*Policy) value.Value {
+	entries := make([]interface{}, len(list))
+	for i, p := range list {
+		entry := map[string]interface{}{
+			"name":      p.Name,
+			"event":     p.Event,
+			"predicate": p.Predicate,
+		}
+		if p.Column != "" {
+			entry["column"] = p.Column
+		}
+		if len(p.Roles) > 0 {
+			roles := make([]interface{}, len(p.Roles))
+			for j, r := range p.Roles {
+				roles[j] = r
+			}
+			entry["roles"] = roles
+		}
+		entries[i] = entry
+	}
+	return value.NewValue(map[string]interface{}{
+		"keyspace": keyspace,
+		"policies": entries,
+	})
+}
+
+/*
+Applies a change to the policies of a collection. The document is updated
+with the CAS it was read with, and the change retried if another node got
+there first.
+*/
+func modifyPolicies(ks datastore.Keyspace,
+	change func(list []*Policy) ([]*Policy, errors.Error)) errors.Error {
+
+	path := collectionPath(ks)
+	if path == nil {
+		return errors.NewPolicyError(errors.E_POLICY_INVALID_KEYSPACE, ks.QualifiedName())
+	}
+	uid, err := datastore.GetScopeUid(path.Namespace(), path.Bucket(), path.Scope())
+	if err != nil {
+		return err
+	}
+	sys, err := getSystemCollection(path.Bucket())
+	if err != nil {
+		return err
+	}
+	if sys.ScopeId() == path.Scope() {
+		return errors.NewPolicyError(errors.E_POLICY_INVALID_KEYSPACE, path.SimpleString())
+	}
+
+	key := getStorageKey(uid, path.Scope(), path.Keyspace())
+	for i := 0; ; i++ {
+		av, err := fetchPolicies(sys, key)
+		if err != nil {
+			return err
+		}
+		list, err := change(decodePolicies(av, path.SimpleString()))
+		if err != nil {
+			return err
+		}
+
+		pairs := make([]value.Pair, 1)
+		pairs[0].Name = key
+		nv := value.NewAnnotatedValue(encodePolicies(path.Keyspace(), list))
+		var errs errors.Errors
+		if av == nil {
+			pairs[0].Value = nv
+			_, _, errs = sys.Insert(pairs, datastore.GetDurableQueryContextFor(sys), true)
+		} else {
+			nv.CopyAnnotations(av)
+			pairs[0].Value = nv
+			_, _, errs = sys.Update(pairs, datastore.GetDurableQueryContextFor(sys), true)
+		}
+		if len(errs) == 0 {
+			break
+		}
+		if i < _MAX_RETRIES && (errs[0].HasCause(errors.E_DUPLICATE_KEY) || errs[0].HasCause(errors.E_CAS_MISMATCH)) {
+			continue
+		}
+		return errs[0]
+	}
+	nextRevision()
+	return nil
+}
+
+// Row-level security and masking policies share their names
+func CreatePolicy(ks datastore.Keyspace, name string, event string, column string, roles []string, predicate string,
+	replace bool) errors.Error {
+
+	fullName := FullName(name, ks.QualifiedName())
+	err := modifyPolicies(ks, func(list []*Policy) ([]*Policy, errors.Error) {
+		p := &Policy{Name: name, Event: event, Column: column, Roles: roles, Predicate: predicate}
+		for i := range list {
+			if list[i].Name == name {
+				if !replace {
+					return nil, errors.NewPolicyError(errors.E_POLICY_ALREADY_EXISTS, fullName)
+				}
+				list[i] = p
+				return list, nil
+			}
+		}
+		return append(list, p), nil
+	})
+	if err != nil && err.Code() != errors.E_POLICY_ALREADY_EXISTS && err.Code() != errors.E_POLICY_INVALID_KEYSPACE {
+		return errors.NewPolicyError(errors.E_POLICY_CREATE, fullName, err)
+	}
+	return err
+}
+
+// DROP POLICY only drops row-level security policies, and DROP MASKING POLICY masking policies
+func DropPolicy(ks datastore.Keyspace, name string, masking bool, failIfNotExists bool) errors.Error {
+	fullName := FullName(name, ks.QualifiedName())
+	err := modifyPolicies(ks, func(list []*Policy) ([]*Policy, errors.Error) {
+		for i := range list {
+			if list[i].Name == name && (list[i].Event == MASK) == masking {
+				return append(list[:i], list[i+1:]...), nil
+			}
+		}
+		return nil, errors.NewPolicyError(errors.E_POLICY_NOT_FOUND, fullName)
+	})
+	if err != nil {
+		switch err.Code() {
+		case errors.E_POLICY_NOT_FOUND:
+			if !failIfNotExists {
+				return nil
+			}
+		case errors.E_POLICY_INVALID_KEYSPACE:
+		default:
+			return errors.NewPolicyError(errors.E_POLICY_DROP, fullName, err)
+		}
+	}
+	return err
+}
+
+/*
+Returns the policies defined on a keyspace for a statement, in order of
+name. Keyspaces other than collections, or buckets used as their default
+collection, never have policies.
+*/
+func KeyspacePolicies(ks datastore.Keyspace, event string) ([]*Policy, errors.Error) {
+	path := collectionPath(ks)
+	if path == nil {
+		return nil, nil
+	}
+	name := path.SimpleString()
+	rev := atomic.LoadInt32(&cacheRevision)
+
+	cache.RLock()
+	entry, ok := cache.keyspaces[name]
+	cache.RUnlock()
+	if !ok || entry.rev != rev {
+		list, err := loadPolicies(path)
+		if err != nil {
+			return nil, err
+		}
+		entry = &cacheEntry{policies: list, rev: rev}
+		cache.Lock()
+		cache.keyspaces[name] = entry
+		cache.Unlock()
+	}
+
+	var rv []*Policy
+	for _, p := range entry.policies {
+		if p.Event == event {
+			rv = append(rv, p)
+		}
+	}
+	return rv, nil
+}
+
+func loadPolicies(path *algebra.Path) ([]*Policy, errors.Error) {
+	sys, err := datastore.GetDatastore().GetSystemCollection(path.Bucket())
+	if err != nil {
+		if err.Code() == errors.E_CB_SCOPE_NOT_FOUND || errors.IsNotFoundError("", err) {
+			// no system collection, no policies
+			return nil, nil
+		}
+		return nil, err
+	} else if sys == nil || sys.ScopeId() == path.Scope() {
+		return nil, nil
+	}
+	uid, err := datastore.GetScopeUid(path.Namespace(), path.Bucket(), path.Scope())
+	if err != nil {
+		return nil, err
+	}
+	av, err := fetchPolicies(sys, getStorageKey(uid, path.Scope(), path.Keyspace()))
+	if err != nil {
+		return nil, err
+	}
+	list := decodePolicies(av, path.SimpleString())
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Lists the full names of the policies defined on the collections of a scope
+func ListPolicyKeys(namespace string, bucket string, scope string, limit int64) ([]string, errors.Error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	res := make([]string, 0, 32)
+	uid, err := datastore.GetScopeUid(namespace, bucket, scope)
+	if err != nil {
+		return res, nil
+	}
+	prefix := _POLICY + uid + "::" + scope + "."
+
+	datastore.ScanSystemCollection(bucket, prefix,
+		func(systemCollection datastore.Keyspace) errors.Error {
+			if systemCollection.ScopeId() == scope {
+				// there are no policies in the _system scope
+				return errors.NewPolicyError(errors.E_POLICY_NOT_FOUND, scope) // will just stop the scan
+			}
+			return nil
+		},
+		func(key string, systemCollection datastore.Keyspace) errors.Error {
+			av, err := fetchPolicies(systemCollection, key)
+			if err != nil {
+				return nil
+			}
+			for _, p := range decodePolicies(av, "") {
+				if limit <= 0 {
+					return errors.NewPolicyError(errors.E_POLICY_NOT_FOUND, key) // will just stop the scan
+				}
+				res = append(res, getCacheKey(namespace, bucket, key)+"."+p.Name)
+				limit--
+			}
+			return nil
+		}, nil)
+
+	return res, nil
+}
+
+// Returns the system:policies entry for the policy
+func FetchPolicy(name string) (value.AnnotatedValue, errors.Error) {
+	var elements []string
+	if i := strings.IndexByte(name, ':'); i > 0 {
+		elements = append([]string{name[:i]}, strings.SplitN(name[i+1:], ".", 4)...)
+	}
+	if len(elements) != 5 {
+		return nil, errors.NewPolicyError(errors.E_POLICY_NOT_FOUND, name)
+	}
+	path := algebra.NewPathLong(elements[0], elements[1], elements[2], elements[3])
+	uid, err := datastore.GetScopeUid(elements[0], elements[1], elements[2])
+	if err != nil {
+		return nil, nil
+	}
+	sys, err := getSystemCollection(elements[1])
+	if err != nil {
+		return nil, nil
+	}
+	av, err := fetchPolicies(sys, getStorageKey(uid, elements[2], elements[3]))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range decodePolicies(av, path.SimpleString()) {
+		if p.Name != elements[4] {
+			continue
+		}
+		m := make(map[string]interface{})
+		m["namespace"] = elements[0]
+		m["namespace_id"] = elements[0]
+		m["bucket"] = elements[1]
+		m["scope_id"] = elements[2]
+		m["keyspace_id"] = elements[3]
+		m["name"] = p.Name
+		m["path"] = path.ProtectedString()
+		m["event"] = strings.ToUpper(p.Event)
+		if p.Event == MASK {
+			exempt := make([]interface{}, len(p.Roles))
+			for i, r := range p.Roles {
+				exempt[i] = r
+			}
+			m["column"] = p.Column
+			m["exempt"] = exempt
+			m["mask"] = p.Predicate
+			return value.NewAnnotatedValue(value.NewValue(m)), nil
+		}
+		roles := []interface{}{strings.ToUpper(PUBLIC)}
+		if !p.Public() {
+			roles = make([]interface{}, len(p.Roles))
+			for i, r := range p.Roles {
+				roles[i] = r
+			}
+		}
+		m["roles"] = roles
+		m["predicate"] = p.Predicate
+		return value.NewAnnotatedValue(value.NewValue(m)), nil
+	}
+	return nil, nil
+}
+
+// Removes the policies of a collection that has been dropped
+func DropKeyspacePolicies(namespace string, bucket string, scope string, uid string, collection string) errors.Error {
+	sys, err := getSystemCollection(bucket)
+	if err != nil {
+		return err
+	}
+	pairs := make([]value.Pair, 1)
+	pairs[0].Name = getStorageKey(uid, scope, collection)
+	_, _, errs := sys.Delete(pairs, datastore.GetDurableQueryContextFor(sys), false)
+	if len(errs) > 0 && !errors.IsNotFoundError("", errs[0]) {
+		return errors.NewPolicyError(errors.E_POLICY_DROP_ALL, bucket+"."+scope+"."+collection+".*", errs[0])
+	}
+	nextRevision()
+	return nil
+}
+
+// Removes the policies of all the collections of a scope that has been dropped
+func DropAllPolicies(namespace string, bucket string, scope string, uid string) errors.Error {
+	var lastError errors.Error
+	pairs := make([]value.Pair, 0, _BATCH_SIZE)
+	errorCount := 0
+
+	prefix := _POLICY
+	if scope != "" {
+		prefix += uid + "::" + scope + "."
+	}
+	var qcontext datastore.QueryContext
+	flush := func(systemCollection datastore.Keyspace) {
+		_, _, errs := systemCollection.Delete(pairs, qcontext, true)
+		if len(errs) > 0 {
+			errorCount += len(errs)
+			lastError = errors.NewPolicyError(errors.E_POLICY_DROP_ALL, bucket+"."+scope+".*", errs[0])
+		}
+		pairs = pairs[:0]
+	}
+	err := datastore.ScanSystemCollection(bucket, prefix,
+		func(systemCollection datastore.Keyspace) errors.Error {
+			qcontext = datastore.GetDurableQueryContextFor(systemCollection)
+			return nil
+		},
+		func(key string, systemCollection datastore.Keyspace) errors.Error {
+			pairs = append(pairs, value.Pair{Name: key})
+			if len(pairs) >= _BATCH_SIZE {
+				flush(systemCollection)
+			}
+			return nil
+		},
+		func(systemCollection datastore.Keyspace) errors.Error {
+			if len(pairs) > 0 {
+				flush(systemCollection)
+			}
+			return nil
+		})
+	if err != nil && err.Code() == errors.E_CB_KEYSPACE_NOT_FOUND {
+		logging.Debugf("%v:%v.%v %v", namespace, bucket, scope, err)
+		return nil
+	}
+	if err != nil && lastError == nil {
+		lastError = err
+	}
+	nextRevision()
+	logging.Debugf("%v:%v.%v %v - %v", namespace, bucket, scope, errorCount, lastError)
+	return lastError
+}
@@ -80,6 +80,10 @@ func (this *Rewrite) VisitFlushCollection(stmt *algebra.FlushCollection) (interf
 	return stmt, stmt.MapExpressions(this)
 }
 
+func (this *Rewrite) VisitTruncate(stmt *algebra.Truncate) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
 func (this *Rewrite) VisitCreateFunction(stmt *algebra.CreateFunction) (interface{}, error) {
 	return stmt, stmt.MapExpressions(this)
 }
@@ -104,6 +108,46 @@ func (this *Rewrite) VisitAlterSequence(stmt *algebra.AlterSequence) (interface{
 	return stmt, stmt.MapExpressions(this)
 }
 
+func (this *Rewrite) VisitCreateView(stmt *algebra.CreateView) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
+func (this *Rewrite) VisitDropView(stmt *algebra.DropView) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
+func (this *Rewrite) VisitRefreshView(stmt *algebra.RefreshView) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
+func (this *Rewrite) VisitCreateTrigger(stmt *algebra.CreateTrigger) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
+func (this *Rewrite) VisitDropTrigger(stmt *algebra.DropTrigger) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
+func (this *Rewrite) VisitCreateJob(stmt *algebra.CreateJob) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
+func (this *Rewrite) VisitAlterJob(stmt *algebra.AlterJob) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
+func (this *Rewrite) VisitDropJob(stmt *algebra.DropJob) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
+func (this *Rewrite) VisitCreatePolicy(stmt *algebra.CreatePolicy) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
+func (this *Rewrite) VisitDropPolicy(stmt *algebra.DropPolicy) (interface{}, error) {
+	return stmt, stmt.MapExpressions(this)
+}
+
 func (this *Rewrite) VisitCreateCredentialStore(stmt *algebra.CreateCredentialStore) (any, error) {
 	return stmt, stmt.MapExpressions(this)
 }
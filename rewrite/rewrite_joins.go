@@ -32,6 +32,10 @@ func (this *Rewrite) VisitAnsiJoin(node *algebra.AnsiJoin) (r interface{}, err e
 	return node, this.visitJoin(node.Left(), node.Right())
 }
 
+func (this *Rewrite) VisitAsofJoin(node *algebra.AsofJoin) (r interface{}, err error) {
+	return node, this.visitJoin(node.Left(), node.Right())
+}
+
 func (this *Rewrite) VisitNest(node *algebra.Nest) (interface{}, error) {
 	return node, this.visitJoin(node.Left(), node.Right())
 }
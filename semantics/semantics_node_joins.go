@@ -28,7 +28,7 @@ func (this *SemChecker) visitJoin(left algebra.FromTerm, right algebra.SimpleFro
 func (this *SemChecker) VisitJoin(node *algebra.Join) (interface{}, error) {
 	left := skipUnnest(node.Left())
 	switch left := left.(type) {
-	case *algebra.AnsiJoin:
+	case *algebra.AnsiJoin, *algebra.AsofJoin:
 		return nil, errors.NewMixedJoinError("ANSI JOIN", left.Alias(), "non ANSI JOIN",
 			node.Alias(), "semantics.visit_join.ansi_mixed_join")
 	case *algebra.AnsiNest:
@@ -58,7 +58,7 @@ func (this *SemChecker) VisitJoin(node *algebra.Join) (interface{}, error) {
 func (this *SemChecker) VisitIndexJoin(node *algebra.IndexJoin) (interface{}, error) {
 	left := skipUnnest(node.Left())
 	switch left := left.(type) {
-	case *algebra.AnsiJoin:
+	case *algebra.AnsiJoin, *algebra.AsofJoin:
 		return nil, errors.NewMixedJoinError("ANSI JOIN", left.Alias(), "non ANSI JOIN",
 			node.Alias(), "semantics.visit_index_join.ansi_mixed_join")
 	case *algebra.AnsiNest:
@@ -115,10 +115,42 @@ func (this *SemChecker) VisitAnsiJoin(node *algebra.AnsiJoin) (r interface{}, er
 	return nil, err
 }
 
+func (this *SemChecker) VisitAsofJoin(node *algebra.AsofJoin) (r interface{}, err error) {
+	left := skipUnnest(node.Left())
+	switch left := left.(type) {
+	case *algebra.Join, *algebra.IndexJoin:
+		return nil, errors.NewMixedJoinError("non ANSI JOIN", left.Alias(), "ASOF JOIN",
+			node.Alias(), "semantics.visit_asof_join.ansi_mixed_join")
+	case *algebra.Nest, *algebra.IndexNest:
+		return nil, errors.NewMixedJoinError("non ANSI NEST", left.Alias(), "ASOF JOIN",
+			node.Alias(), "semantics.visit_asof_join.ansi_mixed_join")
+	}
+
+	right := node.Right()
+	if right.JoinHint() != algebra.JOIN_HINT_NONE {
+		return nil, errors.NewJoinNestNoJoinHintError("ASOF JOIN", right.Alias(), "semantics.visit_asof_join.no_join_hint")
+	}
+
+	if err = this.visitJoin(node.Left(), node.Right()); err != nil {
+		return nil, err
+	}
+
+	this.setSemFlag(_SEM_ON)
+	_, err = this.Map(node.Onclause())
+	this.unsetSemFlag(_SEM_ON)
+
+	if this.hasSemFlag(_SEM_WITH_RECURSIVE) && node.Outer() {
+		// LEFT Outer JOIN not allowed as CTE can become infinite recursion
+		return nil, errors.NewRecursionUnsupportedError("OUTER JOIN", "may lead to potential infinite recursion")
+	}
+
+	return nil, err
+}
+
 func (this *SemChecker) VisitNest(node *algebra.Nest) (interface{}, error) {
 	left := skipUnnest(node.Left())
 	switch left := left.(type) {
-	case *algebra.AnsiJoin:
+	case *algebra.AnsiJoin, *algebra.AsofJoin:
 		return nil, errors.NewMixedJoinError("ANSI JOIN", left.Alias(), "non ANSI NEST",
 			node.Alias(), "semantics.visit_nest.ansi_mixed_join")
 	case *algebra.AnsiNest:
@@ -143,7 +175,7 @@ func (this *SemChecker) VisitNest(node *algebra.Nest) (interface{}, error) {
 func (this *SemChecker) VisitIndexNest(node *algebra.IndexNest) (interface{}, error) {
 	left := skipUnnest(node.Left())
 	switch left := left.(type) {
-	case *algebra.AnsiJoin:
+	case *algebra.AnsiJoin, *algebra.AsofJoin:
 		return nil, errors.NewMixedJoinError("ANSI JOIN", left.Alias(), "non ANSI NEST",
 			node.Alias(), "semantics.visit_index_nest.ansi_mixed_join")
 	case *algebra.AnsiNest:
@@ -360,6 +360,13 @@ func (this *SemChecker) VisitDropCollection(stmt *algebra.DropCollection) (inter
 	return nil, stmt.MapExpressions(this)
 }
 
+func (this *SemChecker) VisitTruncate(stmt *algebra.Truncate) (interface{}, error) {
+	if stmt.Keyspace().Path() == nil {
+		return nil, errors.NewFieldEmpty(stmt.Type(), "keyspace")
+	}
+	return nil, stmt.MapExpressions(this)
+}
+
 func (this *SemChecker) VisitFlushCollection(stmt *algebra.FlushCollection) (interface{}, error) {
 	if stmt.Keyspace().Path().Namespace() == "" {
 		return nil, errors.NewFieldEmpty(stmt.Type(), "namespace")
@@ -428,6 +435,126 @@ func (this *SemChecker) VisitAlterSequence(stmt *algebra.AlterSequence) (interfa
 	return nil, stmt.MapExpressions(this)
 }
 
+func (this *SemChecker) VisitCreateView(stmt *algebra.CreateView) (interface{}, error) {
+	saveStmtType := this.stmtType
+	defer func() { this.stmtType = saveStmtType }()
+	this.stmtType = stmt.Body().Type()
+
+	return stmt.Body().Accept(this)
+}
+
+func (this *SemChecker) VisitDropView(stmt *algebra.DropView) (interface{}, error) {
+	return nil, stmt.MapExpressions(this)
+}
+
+func (this *SemChecker) VisitRefreshView(stmt *algebra.RefreshView) (interface{}, error) {
+	return nil, stmt.MapExpressions(this)
+}
+
+func (this *SemChecker) VisitCreateTrigger(stmt *algebra.CreateTrigger) (interface{}, error) {
+	if stmt.Keyspace().Path() == nil {
+		return nil, errors.NewFieldEmpty(stmt.Type(), "keyspace")
+	}
+	return nil, stmt.MapExpressions(this)
+}
+
+func (this *SemChecker) VisitDropTrigger(stmt *algebra.DropTrigger) (interface{}, error) {
+	if stmt.Keyspace().Path() == nil {
+		return nil, errors.NewFieldEmpty(stmt.Type(), "keyspace")
+	}
+	return nil, stmt.MapExpressions(this)
+}
+
+func (this *SemChecker) VisitCreateJob(stmt *algebra.CreateJob) (interface{}, error) {
+	if !this.hasSemFlag(_SEM_ENTERPRISE) {
+		return nil, errors.NewEnterpriseFeature("Jobs", "semantics.visit_create_job")
+	}
+	return this.visitJobBody(stmt.Name(), stmt.Body())
+}
+
+func (this *SemChecker) VisitAlterJob(stmt *algebra.AlterJob) (interface{}, error) {
+	if !this.hasSemFlag(_SEM_ENTERPRISE) {
+		return nil, errors.NewEnterpriseFeature("Jobs", "semantics.visit_alter_job")
+	}
+	if stmt.Body() == nil {
+		return nil, stmt.MapExpressions(this)
+	}
+	return this.visitJobBody(stmt.Name(), stmt.Body())
+}
+
+// A job runs a single statement on its own: not one that is part of a transaction, nor one about jobs
+func (this *SemChecker) visitJobBody(name string, body algebra.Statement) (interface{}, error) {
+	switch body.(type) {
+	case *algebra.StartTransaction, *algebra.CommitTransaction, *algebra.RollbackTransaction,
+		*algebra.Savepoint, *algebra.TransactionIsolation,
+		*algebra.CreateJob, *algebra.AlterJob, *algebra.DropJob:
+		return nil, errors.NewJobError(errors.E_JOB_INVALID_DEFINITION, name)
+	}
+
+	saveStmtType := this.stmtType
+	defer func() { this.stmtType = saveStmtType }()
+	this.stmtType = body.Type()
+
+	return body.Accept(this)
+}
+
+func (this *SemChecker) VisitDropJob(stmt *algebra.DropJob) (interface{}, error) {
+	if !this.hasSemFlag(_SEM_ENTERPRISE) {
+		return nil, errors.NewEnterpriseFeature("Jobs", "semantics.visit_drop_job")
+	}
+	return nil, stmt.MapExpressions(this)
+}
+
+/*
+A policy predicate is added to the WHERE clause of the statements it
+applies to, and is held to the same rules. It cannot use subqueries, as
+they could read the keyspace it protects, nor parameters.
+*/
+func (this *SemChecker) VisitCreatePolicy(stmt *algebra.CreatePolicy) (interface{}, error) {
+	if !this.hasSemFlag(_SEM_ENTERPRISE) {
+		return nil, errors.NewEnterpriseFeature("Row-level security policies", "semantics.visit_create_policy")
+	}
+	if stmt.Keyspace().Path() == nil {
+		return nil, errors.NewFieldEmpty(stmt.Type(), "keyspace")
+	}
+	subqueries, err := expression.ListSubqueries(stmt.Expressions(), false)
+	if err != nil {
+		return nil, err
+	}
+	if len(subqueries) > 0 {
+		return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_DEFINITION, stmt.Name())
+	}
+	if stmt.Masking() {
+		// the masked column is a path of fields
+		for expr := stmt.Column(); ; {
+			if field, ok := expr.(*expression.Field); ok && field.Second().Value() != nil &&
+				field.Second().Value().Type() == value.STRING {
+				expr = field.First()
+			} else if _, ok := expr.(*expression.Identifier); ok {
+				break
+			} else {
+				return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_DEFINITION, stmt.Name())
+			}
+		}
+	} else if stmt.Predicate().Value() != nil && stmt.Predicate().Value().Type() != value.BOOLEAN {
+		return nil, errors.NewPolicyError(errors.E_POLICY_INVALID_DEFINITION, stmt.Name())
+	}
+
+	this.setSemFlag(_SEM_WHERE)
+	defer this.unsetSemFlag(_SEM_WHERE)
+	return nil, stmt.MapExpressions(this)
+}
+
+func (this *SemChecker) VisitDropPolicy(stmt *algebra.DropPolicy) (interface{}, error) {
+	if !this.hasSemFlag(_SEM_ENTERPRISE) {
+		return nil, errors.NewEnterpriseFeature("Row-level security policies", "semantics.visit_drop_policy")
+	}
+	if stmt.Keyspace().Path() == nil {
+		return nil, errors.NewFieldEmpty(stmt.Type(), "keyspace")
+	}
+	return nil, stmt.MapExpressions(this)
+}
+
 func (this *SemChecker) VisitCreateCredentialStore(stmt *algebra.CreateCredentialStore) (any, error) {
 	if !this.hasSemFlag(_SEM_ENTERPRISE) {
 		return nil, errors.NewEnterpriseFeature(strings.ReplaceAll(stmt.Type(), "_", " "), "semantics.visit_create_credentialstore")
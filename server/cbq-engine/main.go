@@ -18,6 +18,8 @@ import (
 	"runtime"
 	"runtime/debug"
 	"runtime/pprof"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -29,9 +31,11 @@ import (
 	acct_resolver "github.com/couchbase/query/accounting/resolver"
 	"github.com/couchbase/query/audit"
 	"github.com/couchbase/query/aus"
+	"github.com/couchbase/query/auth"
 	config_resolver "github.com/couchbase/query/clustering/resolver"
 	"github.com/couchbase/query/datastore"
 	datastore_package "github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/datastore/couchbase"
 	"github.com/couchbase/query/datastore/resolver"
 	"github.com/couchbase/query/datastore/system"
 	"github.com/couchbase/query/encryption"
@@ -42,15 +46,18 @@ import (
 	"github.com/couchbase/query/functions"
 	"github.com/couchbase/query/functions/constructor"
 	"github.com/couchbase/query/functions/storage"
+	"github.com/couchbase/query/jobs"
 	"github.com/couchbase/query/logging"
 	"github.com/couchbase/query/logging/event"
 	log_resolver "github.com/couchbase/query/logging/resolver"
 	"github.com/couchbase/query/memory"
+	"github.com/couchbase/query/policies"
 	"github.com/couchbase/query/prepareds"
 	"github.com/couchbase/query/scheduler"
 	server_package "github.com/couchbase/query/server"
 	control "github.com/couchbase/query/server/control/couchbase"
 	"github.com/couchbase/query/server/http"
+	"github.com/couchbase/query/server/pgwire"
 	queryMetakv "github.com/couchbase/query/server/settings/couchbase"
 	"github.com/couchbase/query/settings"
 	stats "github.com/couchbase/query/system"
@@ -117,15 +124,33 @@ var MUTATION_LIMIT = flag.Int64("mutation-limit", 0,
 	"Maximum LIMIT for data modification statements; use zero or negative value to disable")
 var HTTP_ADDR = flag.String("http", _DEF_HTTP, "HTTP service address")
 var HTTPS_ADDR = flag.String("https", _DEF_HTTPS, "HTTPS service address")
+var PGWIRE_ADDR = flag.String("pgwire", "", "PostgreSQL wire protocol service address; empty to disable")
+var PGWIRE_CLEARTEXT = flag.Bool("pgwire-cleartext", false,
+	"Accept PostgreSQL wire protocol connections that are not encrypted, and their cleartext passwords")
 
 var CA_FILE = flag.String("cafile", "", "HTTPS CA certificates")
 var CERT_FILE = flag.String("certfile", "", "HTTPS certificate chain file")
 var KEY_FILE = flag.String("keyfile", "", "HTTPS private key file")
 
+var JWT_JWKS = flag.String("jwt-jwks", "", "JWKS file or http(s) URL with the keys of bearer tokens; empty to disable bearer tokens")
+var JWT_ISSUERS = flag.String("jwt-issuers", "", "Comma separated issuers of bearer tokens; empty to accept any")
+var JWT_AUDIENCE = flag.String("jwt-audience", "", "Comma separated audiences bearer tokens must be intended for one of; empty to accept any")
+var JWT_CLOCK_SKEW = flag.Duration("jwt-clock-skew", auth.DEF_JWT_CLOCK_SKEW, "Clock skew allowed for the expiry and validity of bearer tokens")
+var JWT_USER_CLAIM = flag.String("jwt-user-claim", auth.DEF_JWT_USER_CLAIM, "Claim of bearer tokens naming the user")
+var JWT_ROLES_CLAIM = flag.String("jwt-roles-claim", auth.DEF_JWT_ROLES_CLAIM, "Claim of bearer tokens listing the roles of the user")
+var JWT_DOMAIN = flag.String("jwt-domain", auth.DEF_JWT_DOMAIN, "Domain of the users bearer tokens name: local or external")
+
+var AUDIT_FILE = flag.String("audit-file", "", "File audit records are written to as JSON lines, instead of the audit daemon")
+var AUDIT_FILE_SIZE = flag.Int64("audit-file-size", audit.DEF_AUDIT_FILE_SIZE, "Size in bytes at which the audit file is rotated")
+var AUDIT_FILE_COUNT = flag.Int("audit-file-count", audit.DEF_AUDIT_FILE_COUNT, "Number of rotated audit files kept")
+var AUDIT_SYSLOG = flag.String("audit-syslog", "", "Syslog audit records are sent to, instead of the audit daemon: udp://, tcp://, tls:// or unix:// address")
+var AUDIT_WEBHOOK = flag.String("audit-webhook", "", "http(s) URL audit records are posted to, instead of the audit daemon")
+var AUDIT_DISABLED_EVENTS = flag.String("audit-disabled-events", "", "Comma separated ids of events not audited to the audit file, syslog or webhook, when there are no cluster audit settings")
+
 var IPv6 = flag.String("ipv6", server_package.TCP_OPT, "Query is IPv6 compliant")
 var IPv4 = flag.String("ipv4", server_package.TCP_REQ, "Query uses IPv4 listeners only")
 
-var LOGGER = flag.String("logger", "", "Logger implementation")
+var LOGGER = flag.String("logger", "", "Logger implementation: golog, json")
 var LOG_LEVEL = flag.String("loglevel", "info", "Log level: debug, trace, info, warn, error, severe, none")
 var LOG_DIR = flag.String("logDir", "", "Path to the Couchbase server log directory")
 var DEBUG = flag.Bool("debug", false, "Debug mode")
@@ -419,7 +444,12 @@ func main() {
 	server.SetGCPercent(*_GOGC_PERCENT)
 	server.SetRequestErrorLimit(*REQUEST_ERROR_LIMIT)
 
-	audit.StartAuditService(*DATASTORE, server.Servicers()+server.PlusServicers())
+	sinks, disabledEvents, e := auditSinks(encryptionMgr)
+	if e != nil {
+		logging.Errorf("Cannot start audit: %v", e)
+		os.Exit(1)
+	}
+	audit.StartAuditService(*DATASTORE, server.Servicers()+server.PlusServicers(), sinks, disabledEvents)
 
 	// report any non cluster-setting options
 	logging.Infoa(func() string {
@@ -447,6 +477,22 @@ func main() {
 		)
 	})
 
+	if *JWT_JWKS != "" {
+		er := auth.SetJWTConfig(&auth.JWTConfig{
+			Issuers:    flagList(*JWT_ISSUERS),
+			JWKS:       *JWT_JWKS,
+			Audience:   flagList(*JWT_AUDIENCE),
+			ClockSkew:  *JWT_CLOCK_SKEW,
+			UserClaim:  *JWT_USER_CLAIM,
+			RolesClaim: *JWT_ROLES_CLAIM,
+			Domain:     *JWT_DOMAIN,
+		})
+		if er != nil {
+			logging.Errorf("Cannot enable bearer tokens: %v", er)
+			os.Exit(1)
+		}
+	}
+
 	settings.InitSettings()
 	server_package.InitAWR() // start before endpoints but after server init
 
@@ -465,6 +511,13 @@ func main() {
 	server.SetSettingsCallback(endpoint.SettingsCallback)
 
 	constructor.Init(endpoint.Router(), server.Servicers(), "")
+
+	// the caches of cluster wide metadata are invalidated through metakv
+	if _, ok := datastore.(datastore_package.CouchbaseDatastore); ok {
+		couchbase.InitValidation()
+		jobs.Init()
+		policies.Init()
+	}
 	tenant.Start(endpoint, *UUID, *REGULATOR_SETTINGS_FILE)
 
 	/*  Retrieve the necessary encryption-at-rest keys from cbauth before starting listeners
@@ -498,6 +551,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *PGWIRE_ADDR != "" {
+		er = pgwire.NewEndpoint(server, *PGWIRE_ADDR, endpoint.TLSConfig, *PGWIRE_CLEARTEXT).Listen()
+		if er != nil {
+			logging.Errorf("cbq-engine (PGWIRE_ADDR %v) exiting with error: %v", *PGWIRE_ADDR, er)
+			os.Exit(1)
+		}
+	}
+
 	// topology awareness - after listeners are ready to handle requests
 	_ = control.NewManager(*UUID)
 
@@ -512,6 +573,12 @@ func main() {
 	// Initialize configurations for AUS
 	aus.InitAus(server)
 
+	// Start running scheduled jobs
+	server.InitJobs()
+
+	// Start terminating requests that match the kill rules in system:settings
+	server.StartKillRules()
+
 	signalCatcher(server, endpoint)
 }
 
@@ -567,6 +634,53 @@ const _MAX_MEMORY_ABOVE_LIMIT = 8 * util.GiB
 const _PER_SERVICER_MIN_MEMORY = 128 * util.MiB
 const _MIN_MEMORY_LIMIT = util.GiB
 
+// the items of a comma separated flag
+func flagList(list string) []string {
+	var rv []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			rv = append(rv, item)
+		}
+	}
+	return rv
+}
+
+// the sinks audit records are written to instead of the audit daemon, if any
+func auditSinks(encryptionProvider encryption.EncryptionProvider) ([]audit.AuditSink, []uint32, error) {
+	var sinks []audit.AuditSink
+	if *AUDIT_FILE != "" {
+		sink, err := audit.NewFileSink(*AUDIT_FILE, *AUDIT_FILE_SIZE, *AUDIT_FILE_COUNT, encryptionProvider)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if *AUDIT_SYSLOG != "" {
+		sink, err := audit.NewSyslogSink(*AUDIT_SYSLOG)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if *AUDIT_WEBHOOK != "" {
+		sink, err := audit.NewWebhookSink(*AUDIT_WEBHOOK)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	var disabled []uint32
+	for _, item := range flagList(*AUDIT_DISABLED_EVENTS) {
+		id, err := strconv.ParseUint(item, 10, 32)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid audit event id %v", item)
+		}
+		disabled = append(disabled, uint32(id))
+	}
+	return sinks, disabled, nil
+}
+
 func setMemoryLimit(ml int64) {
 	var extra string
 	var oml int64
@@ -23,6 +23,7 @@ const (
 	DEBUG                 = "debug"
 	KEEPALIVELENGTH       = "keep-alive-length"
 	LOGLEVEL              = "loglevel"
+	LOGFORMAT             = "log-format"
 	MAXPARALLELISM        = "max-parallelism"
 	MEMPROFILE            = "memprofile"
 	REQUESTSIZECAP        = "request-size-cap"
@@ -74,6 +75,7 @@ var CHECKERS = map[string]Checker{
 	CPUPROFILE:            checkString,
 	DEBUG:                 checkBool,
 	LOGLEVEL:              checkLogLevel,
+	LOGFORMAT:             checkLogFormat,
 	MAXPARALLELISM:        checkNumber,
 	MEMPROFILE:            checkString,
 	REQUESTSIZECAP:        checkNumber,
@@ -244,6 +246,11 @@ func checkLogLevel(val interface{}) (bool, errors.Error) {
 	return ok, nil
 }
 
+func checkLogFormat(val interface{}) (bool, errors.Error) {
+	format, is_string := val.(string)
+	return is_string && (format == logging.TEXT_FORMAT || format == logging.JSON_FORMAT), nil
+}
+
 func checkPath(val interface{}) (bool, errors.Error) {
 	s, ok := val.(string)
 	if ok && s != "" {
@@ -89,6 +89,7 @@ type RequestLogEntry struct {
 	PositionalArgs           value.Values
 	MemoryQuota              uint64
 	UsedMemory               uint64
+	KillRule                 string
 	Users                    string
 	RemoteAddr               string
 	UserAgent                string
@@ -640,6 +641,7 @@ func LogRequest(request_time, service_time, transactionElapsedTime time.Duration
 	re.PhaseOperators = request.FmtPhaseOperators()
 	re.PhaseTimes = request.RawPhaseTimes()
 	re.UsedMemory = request.UsedMemory()
+	re.KillRule = request.KillRule()
 
 	var start execution.Operator
 	if !request.Sensitive() {
@@ -851,6 +853,9 @@ func (request *RequestLogEntry) Format(profiling bool, redact bool, durStyle uti
 	if request.UsedMemory != 0 {
 		reqMap["usedMemory"] = request.UsedMemory
 	}
+	if request.KillRule != "" {
+		reqMap["killRule"] = request.KillRule
+	}
 	if request.SessionMemory != 0 {
 		reqMap["sessionMemory"] = request.SessionMemory
 	}
@@ -217,6 +217,13 @@ func (this *HttpEndpoint) Listen() error {
 	return nil
 }
 
+// The current TLS configuration of the encrypted port, nil until it has been set up
+func (this *HttpEndpoint) TLSConfig() *tls.Config {
+	this.tlsConfigLock.RLock()
+	defer this.tlsConfigLock.RUnlock()
+	return this.tlsConfig
+}
+
 // Creates/ dynamically updates the TLS configuration for the encrypted port.
 // Once the TLS config is successfully updated, the secure listeners are only started if the listener has not been started already.
 func (this *HttpEndpoint) ListenTLS() error {
@@ -506,6 +513,7 @@ func (this *HttpEndpoint) registerHandlers(staticPath string) {
 	this.router = router.NewRouter()
 
 	this.router.Map(servicePrefix, this.ServeHTTP, "GET", "POST")
+	this.router.Map(subscribePrefix, this.ServeSubscribe, "GET", "POST")
 
 	// TODO: Deprecate (remove) this binding
 	this.router.Map("/query", this.ServeHTTP, "GET", "POST")
@@ -689,7 +697,7 @@ func ServicePrefix() string {
 	return servicePrefix
 }
 
-// activeHttpRequests implements server.ActiveRequests for http requests
+// activeHttpRequests implements server.ActiveRequests for http requests, and for those of the other endpoints
 type activeHttpRequests struct {
 	cache  *util.GenCache
 	server *server.Server
@@ -703,11 +711,7 @@ func NewActiveRequests(server *server.Server) server.ActiveRequests {
 }
 
 func (this *activeHttpRequests) Put(req server.Request) errors.Error {
-	http_req, is_http := req.(*httpRequest)
-	if !is_http {
-		return errors.NewServiceErrorHttpReq(req.Id().String())
-	}
-	this.cache.FastAdd(http_req, http_req.Id().String())
+	this.cache.FastAdd(req, req.Id().String())
 	return nil
 }
 
@@ -716,7 +720,7 @@ func (this *activeHttpRequests) Get(id string, f func(server.Request)) errors.Er
 
 	if f != nil {
 		dummyF = func(e interface{}) {
-			r := e.(*httpRequest)
+			r := e.(server.Request)
 			f(r)
 		}
 	}
@@ -731,8 +735,7 @@ func (this *activeHttpRequests) Delete(id string, stop bool, f func(r server.Req
 			return false
 		}
 		if stop {
-			req := e.(*httpRequest)
-			req.Stop(server.STOPPED)
+			r.Stop(server.STOPPED)
 		}
 		return true
 	})
@@ -746,7 +749,7 @@ func (this *activeHttpRequests) Count() (int, errors.Error) {
 
 func (this *activeHttpRequests) ForEach(nonBlocking func(string, server.Request) bool, blocking func() bool) {
 	dummyF := func(id string, r interface{}) bool {
-		return nonBlocking(id, r.(*httpRequest))
+		return nonBlocking(id, r.(server.Request))
 	}
 	this.cache.ForEach(dummyF, blocking)
 }
@@ -213,7 +213,14 @@ func newHttpRequest(rv *httpRequest, resp http.ResponseWriter, req *http.Request
 		creds, err1 = httpArgs.getCredentials()
 
 		if err1 == nil {
-			creds.HttpRequest = req
+			if token, ok := auth.GetBearerToken(req); ok && auth.BearerEnabled() {
+				creds, err1 = bearerCredentials(req, token, creds)
+			} else {
+				creds.HttpRequest = req
+			}
+		}
+
+		if err1 == nil {
 			rv.SetCredentials(creds)
 
 			// This means we got creds. Now we need to see if they are authorized users.
@@ -1763,6 +1770,35 @@ func (this *urlArgs) getTristateVal(field string, v interface{}) (value.Tristate
 	return tristate_value, nil
 }
 
+/*
+The credentials of a request with a bearer token, which runs as the user
+the token identifies, with that user's privileges on a cluster. The roles
+the token carries are kept with it, but grant no privileges. A token
+cannot be combined with the creds parameter.
+*/
+func bearerCredentials(req *http.Request, token string, creds *auth.Credentials) (*auth.Credentials, errors.Error) {
+	if len(creds.Users()) > 0 {
+		return nil, errors.NewAdminAuthError(nil, "cause: a bearer token cannot be combined with creds")
+	}
+	identity, err := auth.VerifyBearerToken(token)
+	if err != nil {
+		return nil, errors.NewAdminAuthError(err, "cause: invalid bearer token")
+	}
+	rv, err1 := server.OnBehalfCredentials(identity.Name())
+	if err1 != nil {
+		return nil, err1
+	}
+
+	// a standalone node has no users to act on behalf of, and relies on the token alone
+	if rv == nil {
+		rv = auth.NewCredentials()
+		rv.HttpRequest = req
+		rv.AuthenticatedUsers = auth.AuthenticatedUsers{identity.Name()}
+	}
+	rv.Bearer = identity
+	return rv, nil
+}
+
 func (this *urlArgs) getCredentials() (*auth.Credentials, errors.Error) {
 	creds := auth.NewCredentials()
 	creds_field, err := this.formValue(_CREDS)
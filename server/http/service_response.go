@@ -94,6 +94,8 @@ func mapErrorToHttpResponse(err errors.Error, def int) int {
 		return http.StatusUnauthorized
 	case errors.E_PARSE_SYNTAX: // parse error range
 		return http.StatusBadRequest
+	case errors.E_SUBSCRIBE_STATEMENT, errors.E_SUBSCRIBE_NOT_SUPPORTED:
+		return http.StatusBadRequest
 	case errors.E_PLAN, errors.E_NO_SUCH_PREPARED: // plan error range
 		return http.StatusNotFound
 	case errors.E_INDEX_ALREADY_EXISTS:
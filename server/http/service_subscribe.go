@@ -0,0 +1,459 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/plannerbase"
+	"github.com/couchbase/query/policies"
+	"github.com/couchbase/query/value"
+)
+
+const (
+	subscribePrefix = "/query/subscribe"
+
+	_SUBSCRIBE_BATCH     = 256
+	_SUBSCRIBE_HEARTBEAT = 15 * time.Second
+)
+
+/*
+A continuous query: a projection and a filter over the documents of a
+keyspace, applied to each document as it changes.
+*/
+type subscription struct {
+	keyspace   datastore.Keyspace
+	source     datastore.ChangeSource
+	alias      string
+	where      expression.Expression
+	projection *algebra.Projection
+	terms      expression.Expressions // projection term expressions, parameters replaced
+	deletes    bool                   // whether to send the keys of all deleted documents
+	context    expression.Context
+	datastore  datastore.Datastore
+	privs      *auth.Privileges
+	creds      *auth.Credentials
+	authorized time.Time // when the privileges were last checked
+}
+
+/*
+Serves /query/subscribe. The statement is registered over its keyspace
+and matching documents are then streamed as server-sent events, each
+carrying the number of the change as its id. A client resumes after
+the last event it received by passing that id as the Last-Event-ID
+header, or as the cursor parameter; without one, the stream starts with
+the next change.
+
+A deleted document can no longer be matched against the filter, so the
+keys of deletions are only sent when the client asks for all of them.
+
+The stream is read from the keyspace change source at the pace the
+client reads it, so a slow client never holds up writers; a client
+falling too far behind gets an error event and has to start over.
+
+The privileges of the statement are checked again at every heartbeat
+interval, so that a stream ends with an error event once its user has
+lost them, whether or not documents are changing.
+*/
+func (this *HttpEndpoint) ServeSubscribe(resp http.ResponseWriter, req *http.Request) {
+	flusher, ok := resp.(http.Flusher)
+	if !ok {
+		writeError(resp, errors.NewServiceErrorHttpMethod(req.Method))
+		return
+	}
+	if this.server.ShuttingDown() {
+		writeError(resp, errors.NewServiceShuttingDownError())
+		return
+	}
+
+	sub, since, heartbeat, err := this.newSubscription(req)
+	if err != nil {
+		writeError(resp, err)
+		return
+	}
+
+	header := resp.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	resp.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	logging.Infof("Subscription from '%v' to %v started at %v", req.RemoteAddr, sub.keyspace.QualifiedName(), since)
+	defer logging.Infof("Subscription from '%v' to %v ended", req.RemoteAddr, sub.keyspace.QualifiedName())
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	var buf bytes.Buffer
+	for {
+		changes, wait, ok := sub.source.Changes(since, _SUBSCRIBE_BATCH)
+		if !ok {
+			writeErrorEvent(resp, flusher, &buf, errors.NewSubscribeCursorError(since, sub.keyspace.QualifiedName()))
+			return
+		}
+
+		if wait != nil {
+			select {
+			case <-wait:
+				continue
+			case <-ticker.C:
+				if err := sub.authorize(); err != nil {
+					writeErrorEvent(resp, flusher, &buf, err)
+					return
+				}
+				if _, er := resp.Write([]byte(": heartbeat\n\n")); er != nil {
+					return
+				}
+				flusher.Flush()
+				if this.server.ShuttingDown() {
+					return
+				}
+				continue
+			case <-req.Context().Done():
+				return
+			}
+		}
+
+		// policies created and privileges revoked since the subscription started end it
+		if err := sub.checkPolicies(); err != nil {
+			writeErrorEvent(resp, flusher, &buf, err)
+			return
+		}
+		if time.Since(sub.authorized) >= heartbeat {
+			if err := sub.authorize(); err != nil {
+				writeErrorEvent(resp, flusher, &buf, err)
+				return
+			}
+		}
+
+		buf.Reset()
+		for _, c := range changes {
+			since = c.Seqno
+			if c.Deleted {
+				if sub.deletes {
+					writeEvent(&buf, c.Seqno, "delete", map[string]interface{}{"key": c.Key})
+				}
+				continue
+			}
+			row, err := sub.evaluate(c)
+			if err != nil {
+				writeEvent(&buf, c.Seqno, "error", err)
+			} else if row != nil {
+				writeEvent(&buf, c.Seqno, "change", map[string]interface{}{"key": c.Key, "value": row})
+			}
+		}
+		if buf.Len() > 0 {
+			if _, er := resp.Write(buf.Bytes()); er != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(buf *bytes.Buffer, id uint64, event string, data interface{}) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		b, _ = json.Marshal(errors.NewServiceErrorBadValue(err, "event"))
+		event = "error"
+	}
+	if id > 0 {
+		fmt.Fprintf(buf, "id: %d\n", id)
+	}
+	fmt.Fprintf(buf, "event: %s\ndata: %s\n\n", event, b)
+}
+
+// The last event of a stream
+func writeErrorEvent(resp http.ResponseWriter, flusher http.Flusher, buf *bytes.Buffer, err errors.Error) {
+	buf.Reset()
+	writeEvent(buf, 0, "error", err)
+	resp.Write(buf.Bytes())
+	flusher.Flush()
+}
+
+func (this *HttpEndpoint) newSubscription(req *http.Request) (*subscription, uint64, time.Duration, errors.Error) {
+	text := req.FormValue("statement")
+	if text == "" {
+		return nil, 0, 0, errors.NewServiceErrorMissingValue("statement")
+	}
+
+	heartbeat := _SUBSCRIBE_HEARTBEAT
+	if h := req.FormValue("heartbeat"); h != "" {
+		d, er := time.ParseDuration(h)
+		if er != nil || d <= 0 {
+			return nil, 0, 0, errors.NewServiceErrorBadValue(er, "heartbeat")
+		}
+		heartbeat = d
+	}
+
+	var deletes bool
+	if d := req.FormValue("deletes"); d != "" {
+		var er error
+		deletes, er = strconv.ParseBool(d)
+		if er != nil {
+			return nil, 0, 0, errors.NewServiceErrorBadValue(er, "deletes")
+		}
+	}
+
+	stmt, er := n1ql.ParseStatement2(text, this.server.Namespace(), req.FormValue("query_context"))
+	if er != nil {
+		if e, ok := er.(errors.Error); ok {
+			return nil, 0, 0, e
+		}
+		return nil, 0, 0, errors.NewParseSyntaxError(er, "")
+	}
+	sub, err := newSubscription(stmt)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	sub.deletes = deletes
+
+	sub.datastore = datastore.GetDatastore()
+	sub.privs, err = stmt.Privileges()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	sub.creds = this.getCredentialsFromRequest(sub.datastore, req)
+	err = sub.authorize()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	named, positional, err := subscriptionArgs(req)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	exprs := append(expression.Expressions{sub.where}, sub.terms...)
+	for i, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		expr, er = plannerbase.ReplaceParameters(expr, named, positional)
+		if er != nil {
+			return nil, 0, 0, errors.NewServiceErrorBadValue(er, "args")
+		}
+		exprs[i] = expr
+	}
+	sub.where = exprs[0]
+	copy(sub.terms, exprs[1:])
+
+	since := sub.source.LastSeqno()
+	cursor := req.Header.Get("Last-Event-ID")
+	if c := req.FormValue("cursor"); c != "" {
+		cursor = c
+	}
+	if cursor != "" {
+		since, er = strconv.ParseUint(cursor, 10, 64)
+		if er != nil {
+			return nil, 0, 0, errors.NewServiceErrorBadValue(er, "cursor")
+		}
+	}
+	return sub, since, heartbeat, nil
+}
+
+// Named arguments are passed as $name parameters, positional ones as the args array
+func subscriptionArgs(req *http.Request) (map[string]value.Value, value.Values, errors.Error) {
+	var named map[string]value.Value
+	var positional value.Values
+
+	for name, vals := range req.Form {
+		if !strings.HasPrefix(name, "$") || len(vals) == 0 {
+			continue
+		}
+		v := value.NewValue([]byte(vals[0]))
+		if v.Type() == value.BINARY {
+			return nil, nil, errors.NewServiceErrorBadValue(nil, name)
+		}
+		if named == nil {
+			named = make(map[string]value.Value)
+		}
+		named[name[1:]] = v
+	}
+
+	if args := req.FormValue("args"); args != "" {
+		a, ok := value.NewValue([]byte(args)).Actual().([]interface{})
+		if !ok {
+			return nil, nil, errors.NewServiceErrorBadValue(nil, "args")
+		}
+		positional = make(value.Values, len(a))
+		for i := range a {
+			positional[i] = value.NewValue(a[i])
+		}
+	}
+	return named, positional, nil
+}
+
+// Only a projection and a filter over a single keyspace can be applied to documents one at a time
+func newSubscription(stmt algebra.Statement) (*subscription, errors.Error) {
+	sel, ok := stmt.(*algebra.Select)
+	if !ok {
+		return nil, errors.NewSubscribeStatementError("not a SELECT statement")
+	}
+	if sel.With() != nil || sel.Order() != nil || sel.Limit() != nil || sel.Offset() != nil {
+		return nil, errors.NewSubscribeStatementError("WITH, ORDER BY, LIMIT and OFFSET are not allowed")
+	}
+	node, ok := sel.Subresult().(*algebra.Subselect)
+	if !ok {
+		return nil, errors.NewSubscribeStatementError("set operations are not allowed")
+	}
+	term, ok := node.From().(*algebra.KeyspaceTerm)
+	if !ok || term.Path() == nil {
+		return nil, errors.NewSubscribeStatementError("the FROM clause must be a single keyspace")
+	}
+	if term.Keys() != nil || term.Indexes() != nil {
+		return nil, errors.NewSubscribeStatementError("USE KEYS and USE INDEX are not allowed")
+	}
+	if node.Let() != nil || node.Group() != nil || node.Window() != nil {
+		return nil, errors.NewSubscribeStatementError("LET, GROUP BY and WINDOW are not allowed")
+	}
+	projection := node.Projection()
+	if projection.Distinct() || len(projection.Exclude()) > 0 {
+		return nil, errors.NewSubscribeStatementError("DISTINCT and EXCLUDE are not allowed")
+	}
+	subqueries, err := sel.Subqueries()
+	if err != nil {
+		return nil, err
+	} else if len(subqueries) > 0 {
+		return nil, errors.NewSubscribeStatementError("subqueries are not allowed")
+	}
+	for _, expr := range sel.Expressions() {
+		if hasAggregate(expr) {
+			return nil, errors.NewSubscribeStatementError("aggregates are not allowed")
+		}
+	}
+
+	ks, err := datastore.GetKeyspace(term.Path().Parts()...)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := ks.(datastore.ChangeSource)
+	if !ok {
+		return nil, errors.NewSubscribeNotSupportedError(ks.QualifiedName())
+	}
+
+	sub := &subscription{
+		keyspace:   ks,
+		source:     source,
+		alias:      term.Alias(),
+		where:      node.Where(),
+		projection: projection,
+		context:    expression.NewIndexContext(),
+	}
+	if err = sub.checkPolicies(); err != nil {
+		return nil, err
+	}
+
+	sub.terms = make(expression.Expressions, len(projection.Terms()))
+	for i, t := range projection.Terms() {
+		sub.terms[i] = t.Expression()
+	}
+	return sub, nil
+}
+
+// Documents are streamed as they are stored, so neither the policies nor the masks of a keyspace could be applied to them
+func (this *subscription) checkPolicies() errors.Error {
+	for _, event := range []string{policies.SELECT, policies.MASK} {
+		list, err := policies.KeyspacePolicies(this.keyspace, event)
+		if err != nil {
+			return err
+		} else if len(list) > 0 {
+			return errors.NewPolicyError(errors.E_POLICY_UNSUPPORTED, this.keyspace.QualifiedName(), "subscriptions")
+		}
+	}
+	return nil
+}
+
+// The credentials of the request are checked again, as the user may have been removed or lost a role since
+func (this *subscription) authorize() errors.Error {
+	this.authorized = time.Now()
+	return this.datastore.Authorize(this.privs, this.creds)
+}
+
+func hasAggregate(expr expression.Expression) bool {
+	if _, ok := expr.(algebra.Aggregate); ok {
+		return true
+	}
+	for _, child := range expr.Children() {
+		if hasAggregate(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// The projected document, or nil if it does not match
+func (this *subscription) evaluate(c *datastore.Change) (interface{}, errors.Error) {
+	doc := value.NewAnnotatedValue(c.Value)
+	doc.SetId(c.Key)
+	sv := value.NewScopeValue(make(map[string]interface{}, 1), nil)
+	sv.SetField(this.alias, doc)
+	item := value.NewAnnotatedValue(sv)
+
+	if this.where != nil {
+		v, err := this.where.Evaluate(item, this.context)
+		if err != nil {
+			return nil, errors.NewEvaluationError(err, "WHERE")
+		}
+		if !v.Truth() {
+			return nil, nil
+		}
+	}
+
+	if this.projection.Raw() {
+		v, err := this.terms[0].Evaluate(item, this.context)
+		if err != nil {
+			return nil, errors.NewEvaluationError(err, "projection")
+		}
+		return v.Actual(), nil
+	}
+
+	rv := make(map[string]interface{}, len(this.terms))
+	for i, t := range this.projection.Terms() {
+		if alias := t.Alias(); alias != "" {
+			v, err := this.terms[i].Evaluate(item, this.context)
+			if err != nil {
+				return nil, errors.NewEvaluationError(err, "projection")
+			}
+			if v.Type() != value.MISSING {
+				rv[alias] = v.Actual()
+			}
+			continue
+		}
+
+		// star
+		var starval value.Value = item
+		if this.terms[i] != nil {
+			var err error
+			starval, err = this.terms[i].Evaluate(item, this.context)
+			if err != nil {
+				return nil, errors.NewEvaluationError(err, "projection")
+			}
+		}
+		if fields, ok := starval.Actual().(map[string]interface{}); ok {
+			for k, v := range fields {
+				rv[k] = v
+			}
+		}
+	}
+	return rv, nil
+}
@@ -0,0 +1,203 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package server
+
+import (
+	"time"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/execution"
+	"github.com/couchbase/query/jobs"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/memory"
+	"github.com/couchbase/query/tenant"
+	"github.com/couchbase/query/timestamp"
+	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
+)
+
+// Starts running the jobs scheduled with CREATE JOB
+func (this *Server) InitJobs() {
+	jobs.InitJobs(this.newJobContext)
+}
+
+/*
+The execution context for a run of a job. Its statement is executed with
+the credentials of the creator of the job, by way of this node's
+administrator acting on their behalf, and stopped by the job's timeout.
+*/
+func (this *Server) newJobContext(job *jobs.Job) (jobs.Context, errors.Error) {
+	creds, err := OnBehalfCredentials(job.Creator)
+	if err != nil {
+		return nil, err
+	}
+	requestId, e := util.UUIDV4()
+	if e != nil {
+		return nil, errors.NewJobError(errors.E_JOB_EXECUTION, job.Name, e)
+	}
+
+	ctx := execution.NewContext(requestId, this.Datastore(), this.Systemstore(), "default", false,
+		this.MaxParallelism(), this.ScanCap(), this.PipelineCap(), this.PipelineBatch(), nil, nil,
+		creds, datastore.NOT_SET, &jobScanVectorSource{}, &jobOutput{}, nil, this.MaxIndexAPI(),
+		util.GetN1qlFeatureControl(), job.QueryContext, util.IsFeatureEnabled(util.GetN1qlFeatureControl(), util.N1QL_FLEXINDEX),
+		util.IsFeatureEnabled(util.GetN1qlFeatureControl(), util.N1QL_CBO), GetNewOptimizer(), datastore.DEF_KVTIMEOUT,
+		job.Timeout, logging.NONE)
+	ctx.SetReqDeadline(time.Now().Add(job.Timeout))
+	ctx.SetUsers(job.Creator)
+
+	memQuota := this.MemoryQuota()
+	if memQuota > 0 {
+		ctx.SetMemoryQuota(memQuota)
+	}
+	if memQuota > 0 || memory.Quota() > 0 {
+		ctx.SetMemorySession(memory.Register())
+	}
+	return ctx, nil
+}
+
+// implements timestamp.ScanVectorSource for jobs, which do not use scan vectors
+type jobScanVectorSource struct {
+}
+
+func (this *jobScanVectorSource) ScanVector(namespace_id string, keyspace_name string) timestamp.Vector {
+	return this
+}
+
+func (this *jobScanVectorSource) Entries() []timestamp.Entry {
+	return nil
+}
+
+func (this *jobScanVectorSource) Type() int32 {
+	return timestamp.NO_VECTORS
+}
+
+// execution.Output implementation for runs of jobs, whose results are not kept
+type jobOutput struct {
+	err errors.Error
+}
+
+func (this *jobOutput) SetUp() {
+}
+
+func (this *jobOutput) Result(item value.AnnotatedValue) bool {
+	return this.err == nil
+}
+
+func (this *jobOutput) CloseResults() {
+}
+
+func (this *jobOutput) Abort(err errors.Error) {
+	this.Error(err)
+}
+
+func (this *jobOutput) Fatal(err errors.Error) {
+	this.Error(err)
+}
+
+func (this *jobOutput) Error(err errors.Error) {
+	if this.err == nil {
+		this.err = err
+	}
+}
+
+func (this *jobOutput) SetErrors(errs errors.Errors) {
+	for _, err := range errs {
+		this.Error(err)
+	}
+}
+
+func (this *jobOutput) Warning(wrn errors.Error) {
+}
+
+func (this *jobOutput) Errors() []errors.Error {
+	if this.err == nil {
+		return nil
+	}
+	return []errors.Error{this.err}
+}
+
+func (this *jobOutput) AddMutationCount(i uint64) {
+}
+
+func (this *jobOutput) MutationCount() uint64 {
+	return 0
+}
+
+func (this *jobOutput) SetSortCount(i uint64) {
+}
+
+func (this *jobOutput) SortCount() uint64 {
+	return 0
+}
+
+func (this *jobOutput) AddPhaseCount(p execution.Phases, c uint64) {
+}
+
+func (this *jobOutput) AddPhaseOperator(p execution.Phases) {
+}
+
+func (this *jobOutput) FmtPhaseCounts() map[string]interface{} {
+	return nil
+}
+
+func (this *jobOutput) FmtPhaseOperators() map[string]interface{} {
+	return nil
+}
+
+func (this *jobOutput) AddPhaseTime(phase execution.Phases, duration time.Duration) {
+}
+
+func (this *jobOutput) FmtPhaseTimes(s util.DurationStyle) map[string]interface{} {
+	return nil
+}
+
+func (this *jobOutput) RawPhaseTimes() map[string]interface{} {
+	return nil
+}
+
+func (this *jobOutput) FmtOptimizerEstimates(op execution.Operator) map[string]interface{} {
+	return nil
+}
+
+func (this *jobOutput) TrackMemory(size uint64) {
+}
+
+func (this *jobOutput) SetTransactionStartTime(t time.Time) {
+}
+
+func (this *jobOutput) AddTenantUnits(s tenant.Service, ru tenant.Unit) {
+}
+
+func (this *jobOutput) AddCpuTime(d time.Duration) {
+}
+
+func (this *jobOutput) AddIoTime(d time.Duration) {
+}
+
+func (this *jobOutput) AddWaitTime(d time.Duration) {
+}
+
+func (this *jobOutput) Loga(l logging.Level, f func() string) {
+}
+
+func (this *jobOutput) LogLevel() logging.Level {
+	return logging.INFO
+}
+
+func (this *jobOutput) GetErrorLimit() int {
+	return 1
+}
+
+func (this *jobOutput) GetErrorCount() int {
+	if this.err == nil {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,143 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package server
+
+import (
+	"time"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/settings"
+	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
+)
+
+const _KILL_RULE_INTERVAL = time.Second
+
+type killRuleChecker struct{}
+
+// a condition must be a predicate that can be evaluated against a single request
+func (this *killRuleChecker) CheckKillRule(condition string) errors.Error {
+	_, err := compileKillRule(condition)
+	return err
+}
+
+func compileKillRule(condition string) (expression.Expression, errors.Error) {
+	expr, err := n1ql.ParseExpression(condition)
+	if err != nil {
+		return nil, errors.NewParseSyntaxError(err, condition)
+	}
+	if !isKillRuleExpression(expr) {
+		return nil, errors.NewParseSyntaxError(nil, "subqueries and aggregates are not allowed in a kill rule")
+	}
+	return expr, nil
+}
+
+func isKillRuleExpression(expr expression.Expression) bool {
+	switch expr.(type) {
+	case *algebra.Subquery, algebra.Aggregate:
+		return false
+	}
+	for _, child := range expr.Children() {
+		if !isKillRuleExpression(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks the running requests against the enabled kill rules, and terminates those that match one.
+type killRuleMonitor struct {
+	compiled map[string]expression.Expression // by condition, nil if it is not valid
+	context  expression.Context
+}
+
+func (this *Server) StartKillRules() {
+	settings.SetKillRuleChecker(&killRuleChecker{})
+	monitor := &killRuleMonitor{
+		compiled: make(map[string]expression.Expression),
+		context:  expression.NewIndexContext(),
+	}
+	go monitor.run()
+}
+
+func (this *killRuleMonitor) run() {
+	ticker := time.NewTicker(_KILL_RULE_INTERVAL)
+	defer func() {
+		ticker.Stop()
+		// cannot panic and die
+		e := recover()
+		if e != nil {
+			logging.Stackf(logging.ERROR, "Kill rule monitor failed with: %v.  Restarting.", e)
+			go this.run()
+		}
+	}()
+
+	for range ticker.C {
+		rules := settings.GetKillRules()
+		this.compile(rules)
+		if len(rules) == 0 {
+			continue
+		}
+
+		// evaluate outside of the active requests cache lock
+		var requests []Request
+		ActiveRequestsForEach(func(id string, request Request) bool {
+			if request.State() == RUNNING {
+				requests = append(requests, request)
+			}
+			return true
+		}, nil)
+
+		for _, request := range requests {
+			this.check(request, rules)
+		}
+	}
+}
+
+// compiles the conditions of new rules, and forgets those of rules that have gone
+func (this *killRuleMonitor) compile(rules []*settings.KillRule) {
+	conditions := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		conditions[rule.Condition] = true
+		if _, ok := this.compiled[rule.Condition]; ok {
+			continue
+		}
+		expr, err := compileKillRule(rule.Condition)
+		if err != nil {
+			logging.Errorf("Kill rule '%v' ignored: %v", rule.Name, err)
+		}
+		this.compiled[rule.Condition] = expr
+	}
+	for condition := range this.compiled {
+		if !conditions[condition] {
+			delete(this.compiled, condition)
+		}
+	}
+}
+
+func (this *killRuleMonitor) check(request Request, rules []*settings.KillRule) {
+	item := value.NewValue(request.Format(util.SECONDS, false, false, false))
+	for _, rule := range rules {
+		expr := this.compiled[rule.Condition]
+		if expr == nil {
+			continue
+		}
+		v, err := expr.Evaluate(item, this.context)
+		if err != nil || !v.Truth() {
+			continue
+		}
+		logging.Infof("Request %v terminated by kill rule '%v': %v", request.Id().String(), rule.Name, rule.Condition)
+		request.Kill(rule.Name, rule.Condition)
+		return
+	}
+}
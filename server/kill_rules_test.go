@@ -0,0 +1,113 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/settings"
+	"github.com/couchbase/query/util"
+)
+
+type killRuleId string
+
+func (this killRuleId) String() string {
+	return string(this)
+}
+
+// A request that only knows its active requests entry, and which rule killed it
+type killRuleRequest struct {
+	Request
+	id       string
+	item     map[string]interface{}
+	killedBy string
+}
+
+func (this *killRuleRequest) Id() RequestID {
+	return killRuleId(this.id)
+}
+
+func (this *killRuleRequest) Format(durStyle util.DurationStyle, controls bool, prof bool,
+	redact bool) map[string]interface{} {
+	return this.item
+}
+
+func (this *killRuleRequest) Kill(rule, condition string) {
+	this.killedBy = rule
+}
+
+func TestCompileKillRule(t *testing.T) {
+	for _, condition := range []string{
+		"users = 'local:reports' AND (usedMemory > 2147483648 OR phaseCounts.`fetch` > 10000000)",
+		"ANY s IN statement SATISFIES s = 'x' END",
+		"LOWER(statement) LIKE 'select%'",
+	} {
+		if _, err := compileKillRule(condition); err != nil {
+			t.Errorf("%v: unexpected error %v", condition, err)
+		}
+	}
+
+	// a rule is evaluated against one request at a time
+	for _, condition := range []string{
+		"usedMemory >",
+		"COUNT(*) > 10",
+		"users IN (SELECT RAW name FROM system:user_info)",
+		"ARRAY_LENGTH(ARRAY SUM(x) FOR x IN phaseCounts END) > 0",
+	} {
+		if _, err := compileKillRule(condition); err == nil {
+			t.Errorf("%v: expected the condition to be refused", condition)
+		}
+	}
+	if err := (&killRuleChecker{}).CheckKillRule("MAX(usedMemory) > 0"); err == nil {
+		t.Errorf("expected the checker to refuse an aggregate")
+	}
+}
+
+func TestKillRuleMonitor(t *testing.T) {
+	monitor := &killRuleMonitor{
+		compiled: make(map[string]expression.Expression),
+		context:  expression.NewIndexContext(),
+	}
+	memory := &settings.KillRule{Name: "memory", Condition: "usedMemory > 1000", Enabled: true}
+	broken := &settings.KillRule{Name: "broken", Condition: "usedMemory >", Enabled: true}
+	reports := &settings.KillRule{Name: "reports", Condition: "users = 'local:reports'", Enabled: true}
+
+	// invalid conditions are remembered, so that they are not compiled again
+	monitor.compile([]*settings.KillRule{memory, broken})
+	if len(monitor.compiled) != 2 || monitor.compiled[memory.Condition] == nil ||
+		monitor.compiled[broken.Condition] != nil {
+		t.Fatalf("expected the memory rule to compile and the broken one not to, found %v", monitor.compiled)
+	}
+	// the conditions of rules that have gone are forgotten
+	monitor.compile([]*settings.KillRule{broken, reports})
+	if _, ok := monitor.compiled[memory.Condition]; ok || len(monitor.compiled) != 2 {
+		t.Fatalf("expected the memory rule to be forgotten, found %v", monitor.compiled)
+	}
+	rules := []*settings.KillRule{broken, memory, reports}
+	monitor.compile(rules)
+
+	for _, c := range []struct {
+		item     map[string]interface{}
+		killedBy string
+	}{
+		{map[string]interface{}{"usedMemory": 10, "users": "local:admin"}, ""},
+		{map[string]interface{}{"users": "local:admin"}, ""},
+		{map[string]interface{}{"usedMemory": 5000, "users": "local:admin"}, "memory"},
+		// the first matching rule kills the request
+		{map[string]interface{}{"usedMemory": 5000, "users": "local:reports"}, "memory"},
+		{map[string]interface{}{"usedMemory": 10, "users": "local:reports"}, "reports"},
+	} {
+		request := &killRuleRequest{id: "r1", item: c.item}
+		monitor.check(request, rules)
+		if request.killedBy != c.killedBy {
+			t.Errorf("%v: expected the request to be killed by %q, found %q", c.item, c.killedBy, request.killedBy)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/distributed"
+	"github.com/couchbase/query/errors"
+)
+
+/*
+Credentials for a user, in the domain:user form, whose identity the query
+service has established itself, as the creator of a job or the bearer of
+a token. This node's administrator acts on their behalf, so that their
+privileges apply. A standalone node has no administrator, and returns no
+credentials.
+*/
+func OnBehalfCredentials(user string) (*auth.Credentials, errors.Error) {
+	if distributed.RemoteAccess().StandAlone() {
+		return nil, nil
+	}
+
+	node := distributed.RemoteAccess().WhoAmI()
+	if node == "" {
+		return nil, errors.NewNoAdminPrivilegeError(fmt.Errorf("cannot establish node name"))
+	}
+	admin, err := datastore.AdminCreds(node)
+	if err != nil {
+		return nil, errors.NewNoAdminPrivilegeError(err)
+	}
+	up := admin.UsersAndPasswords()
+	if len(up) < 2 {
+		return nil, errors.NewNoAdminPrivilegeError(fmt.Errorf("no administrator credentials"))
+	}
+
+	name, domain := datastore.DecodeName(user)
+	req, e := http.NewRequest("POST", "/query/service", nil)
+	if e != nil {
+		return nil, errors.NewNoAdminPrivilegeError(e)
+	}
+	req.SetBasicAuth(up[0], up[1])
+	req.Header.Set("cb-on-behalf-of", base64.StdEncoding.EncodeToString([]byte(name+":"+domain)))
+
+	creds := auth.NewCredentials()
+	creds.HttpRequest = req
+	return creds, nil
+}
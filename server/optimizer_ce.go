@@ -15,5 +15,5 @@ import (
 )
 
 func GetNewOptimizer() planner.Optimizer {
-	return nil
+	return planner.NewOptimizer()
 }
@@ -0,0 +1,168 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+/*
+Package pgwire implements a listener speaking version 3 of the PostgreSQL
+frontend/backend protocol, so that PostgreSQL drivers and tools can submit
+N1QL statements.
+
+Connections authenticate with a cleartext password, which is checked
+against the datastore as for the http endpoint, so they must be encrypted:
+SSL requests are accepted with the certificates of the encrypted http port,
+and unencrypted connections are refused unless the endpoint is explicitly
+allowed to accept them. GSSAPI encryption is not supported. The database
+startup parameter, if given, is the query context. Both the simple and the
+extended query protocols are supported: statements parsed with the extended
+protocol are prepared in the prepared statement cache, and $n, ? and $name
+parameters are bound by position, with the named parameters following the
+positional ones in order of appearance.
+
+Wire protocol requests are listed in system:active_requests, where they can
+be cancelled and are subject to kill rules. An Execute with a maximum row
+count suspends its portal once that many rows are sent; the portal is
+closed, as in PostgreSQL, by Sync outside a transaction or as another
+statement is run.
+*/
+package pgwire
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/server"
+)
+
+type Endpoint struct {
+	sync.Mutex
+	server    *server.Server
+	addr      string
+	tlsConfig func() *tls.Config // the current TLS configuration, nil if there are no certificates
+	cleartext bool               // whether unencrypted connections are accepted
+	listener  net.Listener
+	sessions  map[int32]*session
+	nextPid   int32
+}
+
+func NewEndpoint(srv *server.Server, addr string, tlsConfig func() *tls.Config, cleartext bool) *Endpoint {
+	return &Endpoint{
+		server:    srv,
+		addr:      addr,
+		tlsConfig: tlsConfig,
+		cleartext: cleartext,
+		sessions:  make(map[int32]*session),
+	}
+}
+
+/*
+The configuration of the encrypted http port, which may be refreshed at any
+time, without the application protocols it negotiates.
+*/
+func (this *Endpoint) serverTLSConfig() *tls.Config {
+	if this.tlsConfig == nil {
+		return nil
+	}
+	config := this.tlsConfig()
+	if config == nil {
+		return nil
+	}
+	config = config.Clone()
+	config.NextProtos = []string{"postgresql"}
+	return config
+}
+
+func (this *Endpoint) Listen() error {
+	listener, err := net.Listen("tcp", this.addr)
+	if err != nil {
+		return fmt.Errorf("Failed to start PostgreSQL wire protocol service: %v", err)
+	}
+	this.Lock()
+	this.listener = listener
+	this.Unlock()
+
+	go this.serve(listener)
+	logging.Infof("PostgreSQL wire protocol service started on %v", listener.Addr())
+	return nil
+}
+
+func (this *Endpoint) serve(listener net.Listener) {
+	for {
+		c, err := listener.Accept()
+		if err != nil {
+			this.Lock()
+			closed := this.listener != listener
+			this.Unlock()
+			if closed {
+				return
+			}
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+			logging.Errorf("PostgreSQL wire protocol service stopped: %v", err)
+			return
+		}
+		go newSession(this, c).serve()
+	}
+}
+
+func (this *Endpoint) Close() error {
+	this.Lock()
+	listener := this.listener
+	this.listener = nil
+	this.Unlock()
+
+	if listener == nil {
+		return nil
+	}
+	return listener.Close()
+}
+
+/*
+Sessions are registered under their process id, which, with the secret
+key, identifies them in cancel requests.
+*/
+func (this *Endpoint) register(s *session) {
+	var b [4]byte
+	rand.Read(b[:])
+	s.secret = int32(binary.BigEndian.Uint32(b[:]))
+
+	this.Lock()
+	for {
+		this.nextPid++
+		if this.nextPid <= 0 {
+			this.nextPid = 1
+		}
+		if _, ok := this.sessions[this.nextPid]; !ok {
+			break
+		}
+	}
+	s.pid = this.nextPid
+	this.sessions[s.pid] = s
+	this.Unlock()
+}
+
+func (this *Endpoint) unregister(s *session) {
+	this.Lock()
+	if this.sessions[s.pid] == s {
+		delete(this.sessions, s.pid)
+	}
+	this.Unlock()
+}
+
+func (this *Endpoint) cancel(pid, secret int32) {
+	this.Lock()
+	s, ok := this.sessions[pid]
+	this.Unlock()
+	if ok && s.secret == secret {
+		s.cancel()
+	}
+}
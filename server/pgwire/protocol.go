@@ -0,0 +1,460 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package pgwire
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/couchbase/query/value"
+)
+
+/*
+Message framing for version 3 of the PostgreSQL frontend/backend
+protocol. Every message but the startup packet starts with a type byte,
+followed by a 32 bit big endian length that includes itself.
+*/
+
+const (
+	_PROTOCOL_VERSION = 196608 // 3.0
+	_CANCEL_REQUEST   = 80877102
+	_SSL_REQUEST      = 80877103
+	_GSSENC_REQUEST   = 80877104
+)
+
+// frontend messages
+const (
+	_MSG_BIND      = 'B'
+	_MSG_CLOSE     = 'C'
+	_MSG_DESCRIBE  = 'D'
+	_MSG_EXECUTE   = 'E'
+	_MSG_FLUSH     = 'H'
+	_MSG_PARSE     = 'P'
+	_MSG_PASSWORD  = 'p'
+	_MSG_QUERY     = 'Q'
+	_MSG_SYNC      = 'S'
+	_MSG_TERMINATE = 'X'
+)
+
+// backend messages
+const (
+	_MSG_AUTHENTICATION       = 'R'
+	_MSG_BACKEND_KEY_DATA     = 'K'
+	_MSG_BIND_COMPLETE        = '2'
+	_MSG_CLOSE_COMPLETE       = '3'
+	_MSG_COMMAND_COMPLETE     = 'C'
+	_MSG_DATA_ROW             = 'D'
+	_MSG_EMPTY_QUERY_RESPONSE = 'I'
+	_MSG_ERROR_RESPONSE       = 'E'
+	_MSG_NO_DATA              = 'n'
+	_MSG_NOTICE_RESPONSE      = 'N'
+	_MSG_PARAMETER_DESC       = 't'
+	_MSG_PARAMETER_STATUS     = 'S'
+	_MSG_PARSE_COMPLETE       = '1'
+	_MSG_PORTAL_SUSPENDED     = 's'
+	_MSG_READY_FOR_QUERY      = 'Z'
+	_MSG_ROW_DESCRIPTION      = 'T'
+)
+
+// authentication request codes
+const (
+	_AUTH_OK                 = 0
+	_AUTH_CLEARTEXT_PASSWORD = 3
+)
+
+// transaction status indicators in ReadyForQuery
+const (
+	_TX_IDLE   = 'I'
+	_TX_ACTIVE = 'T'
+)
+
+/*
+An error detected by the front end itself rather than by the query
+service, with its SQLSTATE.
+*/
+type pgError struct {
+	code string
+	msg  string
+}
+
+func (this *pgError) Error() string {
+	return this.msg
+}
+
+func newPgError(code string, f string, args ...interface{}) error {
+	return &pgError{code: code, msg: fmt.Sprintf(f, args...)}
+}
+
+/*
+A connection frames messages over a network connection. Reads are only
+ever done by the session goroutine, whereas writes are serialized by a
+mutex, since results are written by the execution operators.
+*/
+type conn struct {
+	sync.Mutex
+	net     net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	out     []byte
+	maxSize int
+	failed  error
+}
+
+func newConn(c net.Conn, maxSize int) *conn {
+	return &conn{
+		net:     c,
+		reader:  bufio.NewReader(c),
+		writer:  bufio.NewWriter(c),
+		maxSize: maxSize,
+	}
+}
+
+/*
+Switch to TLS after an SSLRequest has been accepted. Anything the client
+sent after the request would have been sent in the clear, and could have
+been injected by a third party, so it is a protocol violation.
+*/
+func (this *conn) startTLS(config *tls.Config) error {
+	if this.reader.Buffered() > 0 {
+		return newPgError(_SQLSTATE_PROTOCOL_VIOLATION, "received unencrypted data after SSL request")
+	}
+	c := tls.Server(this.net, config)
+	if err := c.Handshake(); err != nil {
+		return err
+	}
+	this.net = c
+	this.reader = bufio.NewReader(c)
+	this.writer = bufio.NewWriter(c)
+	return nil
+}
+
+func (this *conn) encrypted() bool {
+	_, ok := this.net.(*tls.Conn)
+	return ok
+}
+
+func (this *conn) readInt32() (int32, error) {
+	var b [4]byte
+	_, err := io.ReadFull(this.reader, b[:])
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(b[:])), nil
+}
+
+func (this *conn) readBody(length int32) ([]byte, error) {
+	if length < 4 || (this.maxSize > 0 && int(length) > this.maxSize) {
+		return nil, newPgError(_SQLSTATE_PROTOCOL_VIOLATION, "invalid message length %v", length)
+	}
+	b := make([]byte, length-4)
+	_, err := io.ReadFull(this.reader, b)
+	return b, err
+}
+
+/*
+The startup packet has no type byte.
+*/
+func (this *conn) readStartup() ([]byte, error) {
+	length, err := this.readInt32()
+	if err != nil {
+		return nil, err
+	}
+	return this.readBody(length)
+}
+
+func (this *conn) readMessage() (byte, []byte, error) {
+	typ, err := this.reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, err := this.readInt32()
+	if err != nil {
+		return 0, nil, err
+	}
+	b, err := this.readBody(length)
+	return typ, b, err
+}
+
+/*
+Outgoing messages are built in place with begin(), the put methods and
+end(), with the connection locked, and are flushed explicitly.
+*/
+func (this *conn) begin(typ byte) {
+	this.out = append(this.out[:0], typ, 0, 0, 0, 0)
+}
+
+func (this *conn) putByte(b byte) {
+	this.out = append(this.out, b)
+}
+
+func (this *conn) putInt16(i int16) {
+	this.out = binary.BigEndian.AppendUint16(this.out, uint16(i))
+}
+
+func (this *conn) putInt32(i int32) {
+	this.out = binary.BigEndian.AppendUint32(this.out, uint32(i))
+}
+
+func (this *conn) putString(s string) {
+	this.out = append(this.out, s...)
+	this.out = append(this.out, 0)
+}
+
+func (this *conn) putBytes(b []byte) {
+	this.out = append(this.out, b...)
+}
+
+func (this *conn) end() error {
+	if this.failed != nil {
+		return this.failed
+	}
+	binary.BigEndian.PutUint32(this.out[1:5], uint32(len(this.out)-1))
+	_, err := this.writer.Write(this.out)
+	if err != nil {
+		this.failed = err
+	}
+	return err
+}
+
+func (this *conn) flush() error {
+	if this.failed != nil {
+		return this.failed
+	}
+	err := this.writer.Flush()
+	if err != nil {
+		this.failed = err
+	}
+	return err
+}
+
+/*
+Convenience methods for the simpler backend messages.
+*/
+func (this *conn) send(typ byte) error {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(typ)
+	return this.end()
+}
+
+func (this *conn) sendAuthentication(code int32) error {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(_MSG_AUTHENTICATION)
+	this.putInt32(code)
+	return this.end()
+}
+
+func (this *conn) sendParameterStatus(name, val string) error {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(_MSG_PARAMETER_STATUS)
+	this.putString(name)
+	this.putString(val)
+	return this.end()
+}
+
+func (this *conn) sendBackendKeyData(pid, secret int32) error {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(_MSG_BACKEND_KEY_DATA)
+	this.putInt32(pid)
+	this.putInt32(secret)
+	return this.end()
+}
+
+func (this *conn) sendReadyForQuery(status byte) error {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(_MSG_READY_FOR_QUERY)
+	this.putByte(status)
+	if err := this.end(); err != nil {
+		return err
+	}
+	return this.flush()
+}
+
+func (this *conn) sendCommandComplete(tag string) error {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(_MSG_COMMAND_COMPLETE)
+	this.putString(tag)
+	return this.end()
+}
+
+func (this *conn) sendParameterDescription(oids []uint32) error {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(_MSG_PARAMETER_DESC)
+	this.putInt16(int16(len(oids)))
+	for _, oid := range oids {
+		this.putInt32(int32(oid))
+	}
+	return this.end()
+}
+
+func (this *conn) sendRowDescription(columns []*column, formats []int16) error {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(_MSG_ROW_DESCRIPTION)
+	this.putInt16(int16(len(columns)))
+	for i, c := range columns {
+		this.putString(c.name)
+		this.putInt32(0) // table oid
+		this.putInt16(0) // column attribute number
+		this.putInt32(int32(c.oid))
+		this.putInt16(c.size())
+		this.putInt32(-1) // type modifier
+		this.putInt16(resultFormat(formats, i))
+	}
+	return this.end()
+}
+
+/*
+Returns the size of the row, or an error if a value cannot be encoded
+in the requested format.
+*/
+func (this *conn) sendDataRow(values []value.Value, columns []*column, formats []int16) (int, error) {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(_MSG_DATA_ROW)
+	this.putInt16(int16(len(values)))
+	for i, v := range values {
+		if v == nil || v.Type() <= value.NULL {
+			this.putInt32(-1)
+			continue
+		}
+		b, ok := encodeValue(v, columns[i].oid, resultFormat(formats, i))
+		if !ok {
+			return 0, newPgError(_SQLSTATE_INVALID_BINARY_REPRESENTATION,
+				"value of column \"%s\" cannot be sent in binary format", columns[i].name)
+		}
+		this.putInt32(int32(len(b)))
+		this.putBytes(b)
+	}
+	return len(this.out), this.end()
+}
+
+/*
+Error and notice fields.
+*/
+const (
+	_FIELD_SEVERITY          = 'S'
+	_FIELD_SEVERITY_LOCALIZE = 'V'
+	_FIELD_CODE              = 'C'
+	_FIELD_MESSAGE           = 'M'
+	_FIELD_DETAIL            = 'D'
+)
+
+func (this *conn) sendError(typ byte, severity, code, msg, detail string) error {
+	this.Lock()
+	defer this.Unlock()
+	this.begin(typ)
+	this.putByte(_FIELD_SEVERITY)
+	this.putString(severity)
+	this.putByte(_FIELD_SEVERITY_LOCALIZE)
+	this.putString(severity)
+	this.putByte(_FIELD_CODE)
+	this.putString(code)
+	this.putByte(_FIELD_MESSAGE)
+	this.putString(msg)
+	if detail != "" {
+		this.putByte(_FIELD_DETAIL)
+		this.putString(detail)
+	}
+	this.putByte(0)
+	return this.end()
+}
+
+func (this *conn) close() error {
+	this.Lock()
+	this.flush()
+	this.Unlock()
+	return this.net.Close()
+}
+
+/*
+A reader over the body of a frontend message. Reading past the end
+of the message sets a protocol error and returns zero values.
+*/
+type msgReader struct {
+	b   []byte
+	err error
+}
+
+func (this *msgReader) short() {
+	if this.err == nil {
+		this.err = newPgError(_SQLSTATE_PROTOCOL_VIOLATION, "message too short")
+	}
+	this.b = nil
+}
+
+func (this *msgReader) byte() byte {
+	if len(this.b) < 1 {
+		this.short()
+		return 0
+	}
+	rv := this.b[0]
+	this.b = this.b[1:]
+	return rv
+}
+
+func (this *msgReader) int16() int16 {
+	if len(this.b) < 2 {
+		this.short()
+		return 0
+	}
+	rv := int16(binary.BigEndian.Uint16(this.b))
+	this.b = this.b[2:]
+	return rv
+}
+
+func (this *msgReader) int32() int32 {
+	if len(this.b) < 4 {
+		this.short()
+		return 0
+	}
+	rv := int32(binary.BigEndian.Uint32(this.b))
+	this.b = this.b[4:]
+	return rv
+}
+
+func (this *msgReader) string() string {
+	for i, c := range this.b {
+		if c == 0 {
+			rv := string(this.b[:i])
+			this.b = this.b[i+1:]
+			return rv
+		}
+	}
+	this.short()
+	return ""
+}
+
+/*
+A length prefixed value, nil for a length of -1, which is NULL.
+*/
+func (this *msgReader) bytes() []byte {
+	l := this.int32()
+	if l < 0 || this.err != nil {
+		return nil
+	}
+	if int(l) > len(this.b) {
+		this.short()
+		return nil
+	}
+	rv := this.b[:l:l]
+	this.b = this.b[l:]
+	return rv
+}
@@ -0,0 +1,323 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package pgwire
+
+import (
+	"time"
+
+	"github.com/couchbase/query/accounting"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/execution"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/prepareds"
+	"github.com/couchbase/query/server"
+	"github.com/couchbase/query/server/http"
+	"github.com/couchbase/query/timestamp"
+	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
+)
+
+/*
+A pgRequest is a statement submitted over the wire protocol. It goes
+through the same servicers, admission control and prepared statement
+cache as the http requests, and is logged in completed requests.
+
+Results are either streamed to the client as DataRow messages, or, for
+the statements run internally by the session, collected. An Execute with
+a maximum row count suspends the request once it has sent that many
+rows, until the portal is executed again or closed.
+*/
+type pgRequest struct {
+	server.BaseRequest
+	session *session
+
+	// columns and formats of the rows sent to the client
+	columns  []*column
+	formats  []int16
+	describe bool
+
+	// rows for internal statements
+	collect bool
+	rows    []value.Value
+
+	// closed once the signature is known
+	ready chan bool
+
+	// rows sent by the current Execute, and the most it may send, 0 for no limit
+	sent    int
+	maxRows int
+	resumed bool
+
+	// signalled as the request is suspended, and given the maximum row count of the next Execute
+	suspended chan bool
+	resume    chan int
+
+	// closed once the request has completed
+	done chan bool
+
+	resultCount            int
+	resultSize             int
+	txId                   string
+	executionTime          time.Duration
+	elapsedTime            time.Duration
+	transactionElapsedTime time.Duration
+	admissionWaitTime      time.Duration
+}
+
+func newPgRequest(s *session, statement string) *pgRequest {
+	rv := &pgRequest{
+		session:   s,
+		ready:     make(chan bool),
+		suspended: make(chan bool),
+		resume:    make(chan int),
+		done:      make(chan bool),
+	}
+	server.NewBaseRequest(&rv.BaseRequest)
+	rv.SetRequestTime(time.Now())
+	rv.SetStatement(statement)
+	rv.SetCredentials(s.creds)
+	rv.SetQueryContext(s.queryContext)
+	rv.SetUserAgent(s.userAgent)
+	rv.SetRemoteAddr(s.remoteAddr)
+	rv.SetSignature(value.TRUE)
+	rv.SetScanConfiguration(&scanConfigImpl{})
+	rv.SetTxId(s.txId)
+	return rv
+}
+
+func (this *pgRequest) Output() execution.Output {
+	return this
+}
+
+func (this *pgRequest) Fail(err errors.Error) {
+	if this.ServiceTime().IsZero() {
+		this.SetServiceTime()
+	}
+	this.SetState(server.FATAL)
+	this.Error(err)
+}
+
+func (this *pgRequest) Failed(srvr *server.Server) {
+	this.markTimeOfCompletion(time.Now())
+	this.Stop(server.FATAL)
+}
+
+func (this *pgRequest) CompletedNaturalRequest(srvr *server.Server) {
+	this.markTimeOfCompletion(time.Now())
+	this.Stop(server.COMPLETED)
+}
+
+func (this *pgRequest) IncrementStatementCount() {
+	// NOTHING TO DO
+}
+
+func (this *pgRequest) Execute(srvr *server.Server, context *execution.Context, reqType string, signature value.Value,
+	startTx bool) {
+
+	if this.describe {
+		this.columns = columns(signature, reqType)
+		if this.columns != nil {
+			this.session.conn.sendRowDescription(this.columns, this.formats)
+		}
+	}
+	close(this.ready)
+
+	// wait for somebody to tell us we're done, or toast
+	select {
+	case <-this.Results():
+		this.Stop(server.COMPLETED)
+	case <-this.StopExecute():
+
+		// wait for operator before continuing
+		<-this.Results()
+	}
+
+	success := this.State() == server.COMPLETED && len(this.Errors()) == 0
+	if err, _ := context.DoStatementComplete(reqType, success); err != nil {
+		this.Error(err)
+	} else if context.TxContext() != nil && startTx {
+		this.SetTransactionStartTime(context.TxContext().TxStartTime())
+		this.SetTxTimeout(context.TxContext().TxTimeout())
+	}
+
+	now := time.Now()
+	this.Output().AddPhaseTime(execution.RUN, now.Sub(this.ExecTime()))
+	this.markTimeOfCompletion(now)
+}
+
+func (this *pgRequest) markTimeOfCompletion(now time.Time) {
+	if !this.ServiceTime().IsZero() {
+		this.executionTime = now.Sub(this.ServiceTime())
+	}
+	this.elapsedTime = now.Sub(this.RequestTime())
+	if !this.TransactionStartTime().IsZero() {
+		this.transactionElapsedTime = now.Sub(this.TransactionStartTime())
+	}
+}
+
+func (this *pgRequest) Expire(state server.State, timeout time.Duration) {
+	this.Error(errors.NewTimeoutError(util.FormatDuration(timeout, this.DurationStyle())))
+	this.Stop(state)
+}
+
+func (this *pgRequest) Halt(err errors.Error) {
+	if this.State() == server.RUNNING {
+		this.Abort(err)
+	}
+}
+
+func (this *pgRequest) SetUp() {
+}
+
+func (this *pgRequest) Alive() bool {
+	return !this.session.closed()
+}
+
+func (this *pgRequest) Result(item value.AnnotatedValue) bool {
+	select {
+	case <-this.ready:
+	case <-this.StopExecute():
+		return false
+	}
+
+	this.resultCount++
+
+	// the transaction id is the only interesting part of START TRANSACTION
+	if this.Type() == "START_TRANSACTION" {
+		if txId, ok := item.Field("txid"); ok && txId.Type() == value.STRING {
+			this.txId = txId.ToString()
+		}
+	}
+
+	if this.collect {
+		this.rows = append(this.rows, item.Copy())
+		return true
+	}
+	if this.columns == nil {
+		return true
+	}
+
+	size, err := this.session.conn.sendDataRow(rowValues(item, this.columns), this.columns, this.formats)
+	if err != nil {
+		if _, ok := err.(*pgError); ok {
+			this.Abort(errors.NewServiceErrorBadValue(err, "result format"))
+			return false
+		}
+		this.SetState(server.CLOSED)
+		return false
+	}
+	this.resultSize += size
+	this.sent++
+	if this.maxRows > 0 && this.sent >= this.maxRows {
+		return this.suspend()
+	}
+	return true
+}
+
+/*
+Wait for the portal to be executed again, returning false if the request
+is stopped instead, as when the portal is closed.
+*/
+func (this *pgRequest) suspend() bool {
+	select {
+	case this.suspended <- true:
+	case <-this.StopExecute():
+		return false
+	}
+	select {
+	case n := <-this.resume:
+		this.resumed = true
+		this.sent = 0
+		this.maxRows = n
+		return true
+	case <-this.StopExecute():
+		return false
+	}
+}
+
+func (this *pgRequest) Loga(l logging.Level, f func() string) {
+}
+
+func (this *pgRequest) LogLevel() logging.Level {
+	return logging.NONE
+}
+
+func (this *pgRequest) SetAdmissionWaitTime(d time.Duration) {
+	this.admissionWaitTime = d
+}
+
+func (this *pgRequest) AdmissionWaitTime() time.Duration {
+	return this.admissionWaitTime
+}
+
+/*
+Update the metrics and completed requests, as the http endpoint does.
+*/
+func (this *pgRequest) doStats(srvr *server.Server) {
+	prepared := this.Prepared() != nil
+
+	prepareds.RecordPreparedMetrics(this.Prepared(), this.elapsedTime, this.executionTime)
+	accounting.RecordMetrics(this.elapsedTime, this.executionTime, this.transactionElapsedTime, this.resultCount,
+		this.resultSize, this.GetErrorCount(), this.GetWarningCount(), this.Errors(), this.Type(),
+		prepared, (this.State() != server.COMPLETED),
+		this.Natural() != "", this.NaturalOutput(),
+		int(this.PhaseOperator(execution.INDEX_SCAN)),
+		int(this.PhaseOperator(execution.PRIMARY_SCAN)),
+		int(this.PhaseOperator(execution.INDEX_SCAN_GSI)),
+		int(this.PhaseOperator(execution.PRIMARY_SCAN_GSI)),
+		int(this.PhaseOperator(execution.INDEX_SCAN_FTS)),
+		int(this.PhaseOperator(execution.PRIMARY_SCAN_FTS)),
+		int(this.PhaseOperator(execution.INDEX_SCAN_SEQ)),
+		int(this.PhaseOperator(execution.PRIMARY_SCAN_SEQ)),
+		int(this.PhaseOperator(execution.FTS_SEARCH)),
+		int(this.PhaseOperator(execution.INDEX_SCAN_CVI)),
+		int(this.PhaseOperator(execution.INDEX_SCAN_HVI)),
+		int(this.PhaseOperator(execution.FTS_SEARCH_SVI)),
+		int(this.PhaseOperator(execution.VECTOR_DISTANCE)),
+		int(this.PhaseOperator(execution.EXTERNAL_SCAN)),
+		int(this.PhaseOperator(execution.APPROX_VECTOR_DISTANCE)),
+		string(this.ScanConsistency()), this.UsedMemory())
+
+	// there is no http request for the qualifiers to look at
+	this.CompleteRequest(this.elapsedTime, this.executionTime, this.transactionElapsedTime, this.resultCount,
+		this.resultSize, this.GetErrorCount(), nil, srvr,
+		int64(this.PhaseCount(execution.INDEX_SCAN_SEQ)+this.PhaseCount(execution.PRIMARY_SCAN_SEQ)), false)
+}
+
+/*
+Wire protocol requests have no scan consistency parameters, and use
+the default consistency, or that of the transaction.
+*/
+type scanConfigImpl struct {
+	scan_level datastore.ScanConsistency
+}
+
+func (this *scanConfigImpl) ScanConsistency() datastore.ScanConsistency {
+	if this == nil {
+		return datastore.NOT_SET
+	}
+	return this.scan_level
+}
+
+func (this *scanConfigImpl) ScanWait() time.Duration {
+	return 0
+}
+
+func (this *scanConfigImpl) SetScanConsistency(consistency datastore.ScanConsistency) interface{} {
+	if this == nil {
+		this = &scanConfigImpl{}
+	}
+	this.scan_level = consistency
+	return this
+}
+
+func (this *scanConfigImpl) ScanVectorSource() timestamp.ScanVectorSource {
+	return &http.ZeroScanVectorSource{}
+}
@@ -0,0 +1,188 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package pgwire
+
+import (
+	"strconv"
+	"strings"
+)
+
+/*
+Light weight scanning of statement text, for what the front end needs
+to know before the statement reaches the parser: where statements end,
+and which parameters they use.
+*/
+
+/*
+Returns the index just past the string literal, quoted identifier or
+comment starting at i, or i if there is none.
+*/
+func skip(s string, i int) int {
+	switch c := s[i]; c {
+	case '\'', '"', '`':
+		for j := i + 1; j < len(s); j++ {
+			switch s[j] {
+			case '\\':
+				j++
+			case c:
+				// a doubled quote is an escaped quote
+				if j+1 < len(s) && s[j+1] == c {
+					j++
+				} else {
+					return j + 1
+				}
+			}
+		}
+		return len(s)
+	case '-':
+		if i+1 < len(s) && s[i+1] == '-' {
+			if j := strings.IndexByte(s[i:], '\n'); j >= 0 {
+				return i + j + 1
+			}
+			return len(s)
+		}
+	case '/':
+		if i+1 < len(s) && s[i+1] == '*' {
+			if j := strings.Index(s[i+2:], "*/"); j >= 0 {
+				return i + 2 + j + 2
+			}
+			return len(s)
+		}
+	}
+	return i
+}
+
+/*
+Split a simple query into its statements, dropping empty ones.
+*/
+func splitStatements(query string) []string {
+	var rv []string
+
+	start := 0
+	for i := 0; i < len(query); {
+		if j := skip(query, i); j > i {
+			i = j
+			continue
+		}
+		if query[i] == ';' {
+			rv = appendStatement(rv, query[start:i])
+			start = i + 1
+		}
+		i++
+	}
+	return appendStatement(rv, query[start:])
+}
+
+func appendStatement(stmts []string, stmt string) []string {
+	stmt = strings.TrimSpace(stmt)
+	if stmt == "" {
+		return stmts
+	}
+	return append(stmts, stmt)
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+/*
+Returns the number of positional parameters, which is the highest $n,
+or the number of ? if greater, and the named parameters in order of
+first appearance.
+*/
+func scanParameters(stmt string) (int, []string) {
+	var named []string
+	positional := 0
+	next := 0
+
+	for i := 0; i < len(stmt); {
+		if j := skip(stmt, i); j > i {
+			i = j
+			continue
+		}
+		c := stmt[i]
+		switch {
+		case c == '?':
+			if i+1 < len(stmt) && stmt[i+1] == '?' {
+
+				// ?? is the random element operator
+				i += 2
+				continue
+			}
+			next++
+		case (c == '$' || c == '@') && (i == 0 || !isIdentChar(stmt[i-1])):
+			j := i + 1
+			for j < len(stmt) && isIdentChar(stmt[j]) {
+				j++
+			}
+			name := stmt[i+1 : j]
+			if name != "" {
+				if name[0] >= '0' && name[0] <= '9' {
+					if n, err := strconv.Atoi(name); err == nil && n > positional {
+						positional = n
+					}
+				} else if !contains(named, name) {
+					named = append(named, name)
+				}
+			}
+			i = j
+			continue
+		case isIdentChar(c):
+
+			// skip identifiers, so that a$b is not a parameter
+			for i < len(stmt) && isIdentChar(stmt[i]) {
+				i++
+			}
+			continue
+		}
+		i++
+	}
+	if next > positional {
+		positional = next
+	}
+	return positional, named
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func firstWord(stmt string) string {
+	stmt = strings.TrimLeft(stmt, " \t\r\n(")
+	i := 0
+	for i < len(stmt) && isIdentChar(stmt[i]) {
+		i++
+	}
+	return strings.ToLower(stmt[:i])
+}
+
+/*
+Some statements sent by drivers as a matter of course have no N1QL
+equivalent. SET, other than SET TRANSACTION, is accepted and ignored,
+and a bare BEGIN starts a transaction. Returns the statement to run,
+or the command tag of a statement handled locally.
+*/
+func localCommand(stmt string) (string, string) {
+	switch firstWord(stmt) {
+	case "set":
+		if firstWord(strings.TrimSpace(stmt)[3:]) != "transaction" {
+			return "", "SET"
+		}
+	case "begin":
+		if strings.EqualFold(strings.TrimSpace(stmt), "begin") {
+			return "BEGIN WORK", ""
+		}
+	}
+	return stmt, ""
+}
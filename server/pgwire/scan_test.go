@@ -0,0 +1,140 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package pgwire
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/couchbase/query/value"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		query string
+		stmts []string
+	}{
+		{"", nil},
+		{" ; ;", nil},
+		{"SELECT 1", []string{"SELECT 1"}},
+		{"SELECT 1; SELECT 2;", []string{"SELECT 1", "SELECT 2"}},
+		{"SELECT ';' ; SELECT \"a;b\"", []string{"SELECT ';'", "SELECT \"a;b\""}},
+		{"SELECT 'it''s;' ; SELECT `x;y`", []string{"SELECT 'it''s;'", "SELECT `x;y`"}},
+		{"SELECT 1 -- a; comment\n; SELECT /* ; */ 2", []string{"SELECT 1 -- a; comment", "SELECT /* ; */ 2"}},
+	}
+
+	for _, test := range tests {
+		stmts := splitStatements(test.query)
+		if !reflect.DeepEqual(stmts, test.stmts) {
+			t.Errorf("splitStatements(%q): expected %q, got %q", test.query, test.stmts, stmts)
+		}
+	}
+}
+
+func TestScanParameters(t *testing.T) {
+	tests := []struct {
+		stmt       string
+		positional int
+		named      []string
+	}{
+		{"SELECT 1", 0, nil},
+		{"SELECT $1, $3", 3, nil},
+		{"SELECT ?, ?", 2, nil},
+		{"SELECT ??[1, 2], ?", 1, nil},
+		{"SELECT $name, @other, $name", 0, []string{"name", "other"}},
+		{"SELECT $2, $a FROM b WHERE c = ?", 2, []string{"a"}},
+		{"SELECT '$1', \"?\", `$x` -- $2\n", 0, nil},
+		{"SELECT a$b FROM c", 0, nil},
+	}
+
+	for _, test := range tests {
+		positional, named := scanParameters(test.stmt)
+		if positional != test.positional || !reflect.DeepEqual(named, test.named) {
+			t.Errorf("scanParameters(%q): expected %v %q, got %v %q", test.stmt, test.positional, test.named,
+				positional, named)
+		}
+	}
+}
+
+func TestLocalCommand(t *testing.T) {
+	tests := []struct {
+		stmt string
+		text string
+		tag  string
+	}{
+		{"SELECT 1", "SELECT 1", ""},
+		{"SET extra_float_digits = 3", "", "SET"},
+		{"set client_encoding to 'UTF8'", "", "SET"},
+		{"SET TRANSACTION ISOLATION LEVEL READ COMMITTED", "SET TRANSACTION ISOLATION LEVEL READ COMMITTED", ""},
+		{"begin", "BEGIN WORK", ""},
+		{"BEGIN TRANSACTION", "BEGIN TRANSACTION", ""},
+	}
+
+	for _, test := range tests {
+		text, tag := localCommand(test.stmt)
+		if text != test.text || tag != test.tag {
+			t.Errorf("localCommand(%q): expected %q %q, got %q %q", test.stmt, test.text, test.tag, text, tag)
+		}
+	}
+}
+
+func TestParameterRoundTrip(t *testing.T) {
+	tests := []struct {
+		val    interface{}
+		oid    uint32
+		format int16
+	}{
+		{true, _OID_BOOL, _FORMAT_TEXT},
+		{false, _OID_BOOL, _FORMAT_BINARY},
+		{"hello", _OID_TEXT, _FORMAT_TEXT},
+		{"hello", _OID_TEXT, _FORMAT_BINARY},
+		{[]byte{0, 1, 0xfe}, _OID_BYTEA, _FORMAT_TEXT},
+		{[]byte{0, 1, 0xfe}, _OID_BYTEA, _FORMAT_BINARY},
+		{map[string]interface{}{"a": []interface{}{1.0, "b"}}, _OID_JSON, _FORMAT_TEXT},
+		{map[string]interface{}{"a": []interface{}{1.0, "b"}}, _OID_JSON, _FORMAT_BINARY},
+	}
+
+	for _, test := range tests {
+		v := value.NewValue(test.val)
+		b, ok := encodeValue(v, test.oid, test.format)
+		if !ok {
+			t.Errorf("encodeValue(%v, %v, %v) failed", v, test.oid, test.format)
+			continue
+		}
+		d, err := decodeParameter(b, test.oid, test.format)
+		if err != nil {
+			t.Errorf("decodeParameter(%q, %v, %v): %v", b, test.oid, test.format, err)
+			continue
+		}
+		if !v.Equals(d).Truth() {
+			t.Errorf("round trip of %v as %v/%v: got %v", v, test.oid, test.format, d)
+		}
+	}
+}
+
+func TestEncodeNumeric(t *testing.T) {
+	tests := []struct {
+		val  interface{}
+		text string
+	}{
+		{int64(42), "42"},
+		{-1.5, "-1.5"},
+	}
+
+	for _, test := range tests {
+		v := value.NewValue(test.val)
+		b, ok := encodeValue(v, _OID_NUMERIC, _FORMAT_TEXT)
+		if !ok || string(b) != test.text {
+			t.Errorf("encodeValue(%v) as text: expected %q, got %q", v, test.text, b)
+		}
+		if b, ok = encodeValue(v, _OID_NUMERIC, _FORMAT_BINARY); !ok || len(b) < 8 {
+			t.Errorf("encodeValue(%v) as binary numeric: got %v", v, b)
+		}
+	}
+}
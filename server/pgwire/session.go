@@ -0,0 +1,867 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package pgwire
+
+import (
+	"crypto/tls"
+	go_errors "errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/auth"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/plan"
+	"github.com/couchbase/query/prepareds"
+	"github.com/couchbase/query/server"
+	"github.com/couchbase/query/settings"
+	"github.com/couchbase/query/util"
+	"github.com/couchbase/query/value"
+)
+
+// SQLSTATE codes
+const (
+	_SQLSTATE_WARNING                       = "01000"
+	_SQLSTATE_PROTOCOL_VIOLATION            = "08P01"
+	_SQLSTATE_FEATURE_NOT_SUPPORTED         = "0A000"
+	_SQLSTATE_INVALID_BINARY_REPRESENTATION = "22P03"
+	_SQLSTATE_INVALID_TEXT_REPRESENTATION   = "22P02"
+	_SQLSTATE_UNIQUE_VIOLATION              = "23505"
+	_SQLSTATE_READ_ONLY_TRANSACTION         = "25006"
+	_SQLSTATE_INVALID_STATEMENT_NAME        = "26000"
+	_SQLSTATE_INVALID_AUTHORIZATION         = "28000"
+	_SQLSTATE_INVALID_PASSWORD              = "28P01"
+	_SQLSTATE_INVALID_CURSOR_NAME           = "34000"
+	_SQLSTATE_SYNTAX_ERROR                  = "42601"
+	_SQLSTATE_INSUFFICIENT_PRIVILEGE        = "42501"
+	_SQLSTATE_DUPLICATE_STATEMENT           = "42P05"
+	_SQLSTATE_TOO_MANY_CONNECTIONS          = "53300"
+	_SQLSTATE_QUERY_CANCELED                = "57014"
+	_SQLSTATE_ADMIN_SHUTDOWN                = "57P01"
+	_SQLSTATE_INTERNAL_ERROR                = "XX000"
+)
+
+const (
+	_SEVERITY_ERROR   = "ERROR"
+	_SEVERITY_FATAL   = "FATAL"
+	_SEVERITY_WARNING = "WARNING"
+)
+
+// reported to clients, which check it to enable features
+const _SERVER_VERSION = "14.0"
+
+// the error has been sent to the client already
+var errReported = go_errors.New("error reported")
+
+/*
+A statement parsed with the extended query protocol. Statements that
+can be prepared are added to the prepared statement cache; the others
+are run as ad hoc statements at every execution.
+*/
+type statement struct {
+	text       string
+	local      string
+	prepared   *plan.Prepared
+	columns    []*column
+	oids       []uint32
+	positional int
+	named      []string
+}
+
+func (this *statement) empty() bool {
+	return this.text == "" && this.local == ""
+}
+
+/*
+A statement with bound parameters.
+*/
+type portal struct {
+	stmt       *statement
+	positional value.Values
+	named      map[string]value.Value
+	formats    []int16
+	req        *pgRequest // suspended by the maximum row count of Execute
+}
+
+type session struct {
+	sync.Mutex
+	endpoint     *Endpoint
+	conn         *conn
+	pid          int32
+	secret       int32
+	creds        *auth.Credentials
+	queryContext string
+	userAgent    string
+	remoteAddr   string
+	txId         string
+	statements   map[string]*statement
+	portals      map[string]*portal
+	current      *pgRequest
+	done         int32
+
+	// after an error, extended query messages are skipped up to Sync
+	skipToSync bool
+}
+
+func newSession(endpoint *Endpoint, c net.Conn) *session {
+	return &session{
+		endpoint:   endpoint,
+		conn:       newConn(c, endpoint.server.RequestSizeCap()),
+		remoteAddr: c.RemoteAddr().String(),
+		userAgent:  "pgwire",
+		statements: make(map[string]*statement),
+		portals:    make(map[string]*portal),
+	}
+}
+
+func (this *session) serve() {
+	defer func() {
+		atomic.StoreInt32(&this.done, 1)
+		this.endpoint.unregister(this)
+		this.closeSuspended()
+		this.conn.close()
+	}()
+
+	if !this.startup() {
+		return
+	}
+
+	for {
+		typ, b, err := this.conn.readMessage()
+		if err != nil {
+			if err != io.EOF {
+				this.fatal(err)
+			}
+			return
+		}
+		if !this.process(typ, &msgReader{b: b}) {
+			return
+		}
+	}
+}
+
+func (this *session) closed() bool {
+	return atomic.LoadInt32(&this.done) != 0
+}
+
+/*
+Stop the statement being executed, if any.
+*/
+func (this *session) cancel() {
+	this.Lock()
+	req := this.current
+	this.Unlock()
+	if req != nil {
+		req.Stop(server.STOPPED)
+	}
+}
+
+func (this *session) setCurrent(req *pgRequest) {
+	this.Lock()
+	this.current = req
+	this.Unlock()
+}
+
+/*
+Handle the startup packet and authenticate the user.
+*/
+func (this *session) startup() bool {
+	var params map[string]string
+
+	for params == nil {
+		b, err := this.conn.readStartup()
+		if err != nil {
+			return false
+		}
+		r := &msgReader{b: b}
+		switch code := r.int32(); code {
+		case _SSL_REQUEST, _GSSENC_REQUEST:
+			var config *tls.Config
+			if code == _SSL_REQUEST && !this.conn.encrypted() {
+				config = this.endpoint.serverTLSConfig()
+			}
+
+			// without a configuration, the client either goes on in the clear or gives up
+			this.conn.Lock()
+			if config != nil {
+				this.conn.writer.WriteByte('S')
+			} else {
+				this.conn.writer.WriteByte('N')
+			}
+			err = this.conn.flush()
+			this.conn.Unlock()
+			if err != nil {
+				return false
+			}
+			if config != nil {
+				if err = this.conn.startTLS(config); err != nil {
+					logging.Infof("PostgreSQL wire protocol TLS handshake with %v failed: %v", this.remoteAddr, err)
+					if _, ok := err.(*pgError); ok {
+						this.fatal(err)
+					}
+					return false
+				}
+			}
+		case _CANCEL_REQUEST:
+			pid := r.int32()
+			secret := r.int32()
+			if r.err == nil {
+				this.endpoint.cancel(pid, secret)
+			}
+			return false
+		case _PROTOCOL_VERSION:
+			params = make(map[string]string)
+			for {
+				name := r.string()
+				if name == "" || r.err != nil {
+					break
+				}
+				params[name] = r.string()
+			}
+			if r.err != nil {
+				this.fatal(r.err)
+				return false
+			}
+		default:
+			this.fatal(newPgError(_SQLSTATE_FEATURE_NOT_SUPPORTED, "unsupported frontend protocol %d.%d",
+				code>>16, code&0xffff))
+			return false
+		}
+	}
+
+	if !this.conn.encrypted() && !this.endpoint.cleartext {
+		this.fatal(newPgError(_SQLSTATE_INVALID_AUTHORIZATION, "connection requires encryption: SSL is not in use"))
+		return false
+	}
+
+	user := params["user"]
+	if user == "" {
+		this.fatal(newPgError(_SQLSTATE_INVALID_AUTHORIZATION, "no user name specified in startup packet"))
+		return false
+	}
+	if db := params["database"]; db != "" && db != user {
+		if err := algebra.ValidateQueryContext(db); err != nil {
+			this.fatal(err)
+			return false
+		}
+		this.queryContext = db
+	}
+	if app := params["application_name"]; app != "" {
+		this.userAgent = "pgwire (" + app + ")"
+	}
+
+	this.conn.sendAuthentication(_AUTH_CLEARTEXT_PASSWORD)
+	if this.conn.flush() != nil {
+		return false
+	}
+	typ, b, err := this.conn.readMessage()
+	if err != nil {
+		return false
+	}
+	r := &msgReader{b: b}
+	password := r.string()
+	if typ != _MSG_PASSWORD || r.err != nil {
+		this.fatal(newPgError(_SQLSTATE_PROTOCOL_VIOLATION, "expected password response"))
+		return false
+	}
+
+	creds := &auth.Credentials{}
+	creds.Set(user, password)
+	err1 := datastore.GetDatastore().Authorize(nil, creds)
+	if err1 != nil || len(creds.AuthenticatedUsers) == 0 {
+		logging.Infof("PostgreSQL wire protocol authentication failed for <ud>%v</ud> from %v: %v",
+			user, this.remoteAddr, err1)
+		this.fatal(newPgError(_SQLSTATE_INVALID_PASSWORD, "password authentication failed for user \"%s\"", user))
+		return false
+	}
+	this.creds = creds
+
+	this.endpoint.register(this)
+	this.conn.sendAuthentication(_AUTH_OK)
+	this.conn.sendParameterStatus("server_version", _SERVER_VERSION)
+	this.conn.sendParameterStatus("server_encoding", "UTF8")
+	this.conn.sendParameterStatus("client_encoding", "UTF8")
+	this.conn.sendParameterStatus("DateStyle", "ISO")
+	this.conn.sendParameterStatus("integer_datetimes", "on")
+	this.conn.sendParameterStatus("standard_conforming_strings", "on")
+	this.conn.sendParameterStatus("TimeZone", "UTC")
+	this.conn.sendBackendKeyData(this.pid, this.secret)
+	return this.ready() == nil
+}
+
+func (this *session) ready() error {
+	status := byte(_TX_IDLE)
+	if this.txId != "" {
+		status = _TX_ACTIVE
+	}
+	return this.conn.sendReadyForQuery(status)
+}
+
+/*
+Process a message, returning false if the connection is to be closed.
+*/
+func (this *session) process(typ byte, r *msgReader) bool {
+	var err error
+
+	switch typ {
+	case _MSG_QUERY:
+		err = this.query(r)
+		if err == nil {
+			err = this.ready()
+		}
+		return err == nil
+	case _MSG_TERMINATE:
+		return false
+	case _MSG_SYNC:
+		this.skipToSync = false
+
+		// as in PostgreSQL, portals do not outlive an implicit transaction
+		if this.txId == "" {
+			this.closeSuspended()
+		}
+		return this.ready() == nil
+	case _MSG_FLUSH:
+		return this.conn.flush() == nil
+	}
+
+	if this.skipToSync {
+		return true
+	}
+
+	switch typ {
+	case _MSG_PARSE:
+		err = this.parse(r)
+	case _MSG_BIND:
+		err = this.bind(r)
+	case _MSG_DESCRIBE:
+		err = this.describe(r)
+	case _MSG_EXECUTE:
+		err = this.execute(r)
+	case _MSG_CLOSE:
+		err = this.closeMsg(r)
+	default:
+		this.fatal(newPgError(_SQLSTATE_PROTOCOL_VIOLATION, "invalid frontend message type %d", typ))
+		return false
+	}
+
+	if err == nil {
+		return true
+	} else if err == errReported {
+		this.skipToSync = true
+		return true
+	} else if _, ok := err.(*pgError); ok {
+		this.skipToSync = true
+		return this.sendError(_SEVERITY_ERROR, err) == nil
+	}
+	return false
+}
+
+func (this *session) fatal(err error) {
+	this.sendError(_SEVERITY_FATAL, err)
+	this.conn.flush()
+}
+
+func (this *session) sendError(severity string, err error) error {
+	switch err := err.(type) {
+	case *pgError:
+		return this.conn.sendError(_MSG_ERROR_RESPONSE, severity, err.code, err.msg, "")
+	case errors.Error:
+		return this.conn.sendError(_MSG_ERROR_RESPONSE, severity, sqlState(err), err.Error(),
+			fmt.Sprintf("error %d", err.Code()))
+	}
+	return this.conn.sendError(_MSG_ERROR_RESPONSE, severity, _SQLSTATE_INTERNAL_ERROR, err.Error(), "")
+}
+
+/*
+Simple query protocol: run the statements in order, stopping at the
+first one that fails.
+*/
+func (this *session) query(r *msgReader) error {
+	text := r.string()
+	if r.err != nil {
+		return r.err
+	}
+
+	stmts := splitStatements(text)
+	if len(stmts) == 0 {
+		return this.conn.send(_MSG_EMPTY_QUERY_RESPONSE)
+	}
+	for _, s := range stmts {
+		text, local := localCommand(s)
+		if local != "" {
+			if err := this.conn.sendCommandComplete(local); err != nil {
+				return err
+			}
+			continue
+		}
+
+		req := newPgRequest(this, text)
+		req.describe = true
+		ok, err := this.run(req)
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+	}
+	return nil
+}
+
+/*
+Extended query protocol.
+*/
+func (this *session) parse(r *msgReader) error {
+	name := r.string()
+	text := r.string()
+	n := r.int16()
+	oids := make([]uint32, 0, n)
+	for i := int16(0); i < n; i++ {
+		oids = append(oids, uint32(r.int32()))
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if _, ok := this.statements[name]; ok && name != "" {
+		return newPgError(_SQLSTATE_DUPLICATE_STATEMENT, "prepared statement \"%s\" already exists", name)
+	}
+
+	stmt := &statement{}
+	stmts := splitStatements(text)
+	if len(stmts) > 1 {
+		return newPgError(_SQLSTATE_SYNTAX_ERROR, "cannot insert multiple commands into a prepared statement")
+	} else if len(stmts) == 1 {
+		stmt.text, stmt.local = localCommand(stmts[0])
+	}
+	stmt.positional, stmt.named = scanParameters(stmt.text)
+	count := stmt.positional + len(stmt.named)
+	if len(oids) > count {
+		return newPgError(_SQLSTATE_PROTOCOL_VIOLATION, "statement has %d parameters, %d parameter types supplied",
+			count, len(oids))
+	}
+	stmt.oids = make([]uint32, count)
+	copy(stmt.oids, oids)
+
+	if stmt.text != "" {
+		if preparable(stmt.text) {
+			err := this.prepare(stmt)
+			if err != nil {
+				return err
+			}
+		} else {
+			stmt.columns = []*column{&column{name: _UNNAMED_COLUMN, oid: _OID_JSON, raw: true}}
+		}
+	}
+
+	this.statements[name] = stmt
+	return this.conn.send(_MSG_PARSE_COMPLETE)
+}
+
+/*
+Add the statement to the prepared statement cache with an anonymous
+PREPARE, which names the statement after its text.
+*/
+func (this *session) prepare(stmt *statement) error {
+	req := newPgRequest(this, "PREPARE "+stmt.text)
+	req.SetTxId("")
+	req.collect = true
+	ok, err := this.run(req)
+	if err != nil {
+		return err
+	} else if !ok {
+		return errReported
+	}
+
+	var name string
+	if len(req.rows) > 0 {
+		if n, ok := req.rows[0].Field("name"); ok && n.Type() == value.STRING {
+			name = n.ToString()
+		}
+	}
+	if name == "" {
+		return newPgError(_SQLSTATE_INTERNAL_ERROR, "unable to prepare statement")
+	}
+
+	var phaseTime time.Duration
+	prepared, err1 := prepareds.GetPreparedWithContext(name, this.queryContext, nil,
+		prepareds.OPT_TRACK|prepareds.OPT_REMOTE|prepareds.OPT_VERIFY, &phaseTime,
+		settings.GetPlanStabilityMode(), settings.GetPlanStabilityErrorPolicy(), datastore.NOT_SET, logging.NULL_LOG)
+	if err1 != nil {
+		if err := this.sendError(_SEVERITY_ERROR, err1); err != nil {
+			return err
+		}
+		return errReported
+	}
+	stmt.prepared = prepared
+	stmt.columns = columns(prepared.Signature(), prepared.Type())
+	return nil
+}
+
+/*
+Only the statements allowed in PREPARE are prepared.
+*/
+func preparable(text string) bool {
+	word := firstWord(text)
+	return word != "explain" && word != "advise" && word != "prepare" && word != "execute"
+}
+
+func (this *session) bind(r *msgReader) error {
+	portalName := r.string()
+	stmtName := r.string()
+	n := r.int16()
+	paramFormats := make([]int16, 0, n)
+	for i := int16(0); i < n; i++ {
+		paramFormats = append(paramFormats, r.int16())
+	}
+	n = r.int16()
+	params := make([][]byte, 0, n)
+	for i := int16(0); i < n; i++ {
+		params = append(params, r.bytes())
+	}
+	n = r.int16()
+	formats := make([]int16, 0, n)
+	for i := int16(0); i < n; i++ {
+		formats = append(formats, r.int16())
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	stmt, ok := this.statements[stmtName]
+	if !ok {
+		return newPgError(_SQLSTATE_INVALID_STATEMENT_NAME, "prepared statement \"%s\" does not exist", stmtName)
+	}
+	if len(params) != len(stmt.oids) {
+		return newPgError(_SQLSTATE_PROTOCOL_VIOLATION,
+			"bind message supplies %d parameters, but prepared statement \"%s\" requires %d",
+			len(params), stmtName, len(stmt.oids))
+	}
+	for _, f := range formats {
+		if f != _FORMAT_TEXT && f != _FORMAT_BINARY {
+			return newPgError(_SQLSTATE_PROTOCOL_VIOLATION, "invalid format code %d", f)
+		}
+	}
+
+	args := make(value.Values, len(params))
+	for i, p := range params {
+		v, err := decodeParameter(p, stmt.oids[i], resultFormat(paramFormats, i))
+		if err != nil {
+			return err
+		}
+		args[i] = v
+	}
+
+	p := &portal{stmt: stmt, formats: formats}
+	if stmt.positional > 0 {
+		p.positional = args[:stmt.positional]
+	}
+	if len(stmt.named) > 0 {
+		p.named = make(map[string]value.Value, len(stmt.named))
+		for i, name := range stmt.named {
+			p.named[name] = args[stmt.positional+i]
+		}
+	}
+	if old, ok := this.portals[portalName]; ok {
+		this.closePortal(old)
+	}
+	this.portals[portalName] = p
+	return this.conn.send(_MSG_BIND_COMPLETE)
+}
+
+func (this *session) describe(r *msgReader) error {
+	kind := r.byte()
+	name := r.string()
+	if r.err != nil {
+		return r.err
+	}
+
+	var cols []*column
+	var formats []int16
+	switch kind {
+	case 'S':
+		stmt, ok := this.statements[name]
+		if !ok {
+			return newPgError(_SQLSTATE_INVALID_STATEMENT_NAME, "prepared statement \"%s\" does not exist", name)
+		}
+
+		// parameters of unknown type are described as text
+		oids := make([]uint32, len(stmt.oids))
+		for i, oid := range stmt.oids {
+			if oid == _OID_UNKNOWN {
+				oid = _OID_TEXT
+			}
+			oids[i] = oid
+		}
+		if err := this.conn.sendParameterDescription(oids); err != nil {
+			return err
+		}
+		cols = stmt.columns
+	case 'P':
+		p, ok := this.portals[name]
+		if !ok {
+			return newPgError(_SQLSTATE_INVALID_CURSOR_NAME, "portal \"%s\" does not exist", name)
+		}
+		cols = p.stmt.columns
+		formats = p.formats
+	default:
+		return newPgError(_SQLSTATE_PROTOCOL_VIOLATION, "invalid DESCRIBE message subtype %d", kind)
+	}
+
+	if cols == nil {
+		return this.conn.send(_MSG_NO_DATA)
+	}
+	return this.conn.sendRowDescription(cols, formats)
+}
+
+func (this *session) execute(r *msgReader) error {
+	name := r.string()
+	maxRows := r.int32()
+	if r.err != nil {
+		return r.err
+	}
+
+	p, ok := this.portals[name]
+	if !ok {
+		return newPgError(_SQLSTATE_INVALID_CURSOR_NAME, "portal \"%s\" does not exist", name)
+	}
+	if p.req != nil {
+		req := p.req
+		p.req = nil
+		select {
+		case req.resume <- int(maxRows):
+		case <-req.done:
+		}
+		return this.result(p, req)
+	}
+	switch {
+	case p.stmt.empty():
+		return this.conn.send(_MSG_EMPTY_QUERY_RESPONSE)
+	case p.stmt.local != "":
+		return this.conn.sendCommandComplete(p.stmt.local)
+	}
+
+	var req *pgRequest
+	if p.stmt.prepared != nil {
+		req = newPgRequest(this, "")
+		req.SetPrepared(p.stmt.prepared)
+	} else {
+		req = newPgRequest(this, p.stmt.text)
+	}
+	req.SetPositionalArgs(p.positional)
+	req.SetNamedArgs(p.named)
+	req.columns = p.stmt.columns
+	req.formats = p.formats
+	req.maxRows = int(maxRows)
+	this.closeSuspended()
+	this.start(req)
+	return this.result(p, req)
+}
+
+/*
+Send the outcome of the request of a portal, keeping the request in the
+portal if it has been suspended.
+*/
+func (this *session) result(p *portal, req *pgRequest) error {
+	ok, err := this.wait(req)
+	if err != nil {
+		return err
+	} else if !ok {
+		return errReported
+	}
+	if req.State() == server.RUNNING {
+		p.req = req
+		return this.conn.send(_MSG_PORTAL_SUSPENDED)
+	}
+	return nil
+}
+
+func (this *session) closeMsg(r *msgReader) error {
+	kind := r.byte()
+	name := r.string()
+	if r.err != nil {
+		return r.err
+	}
+
+	switch kind {
+	case 'S':
+		delete(this.statements, name)
+	case 'P':
+		if p, ok := this.portals[name]; ok {
+			this.closePortal(p)
+		}
+		delete(this.portals, name)
+	default:
+		return newPgError(_SQLSTATE_PROTOCOL_VIOLATION, "invalid CLOSE message subtype %d", kind)
+	}
+	return this.conn.send(_MSG_CLOSE_COMPLETE)
+}
+
+/*
+Service a request, and send its outcome. Returns false if the
+request failed, in which case the error has been sent. A non nil
+error means that the connection has failed.
+*/
+func (this *session) run(req *pgRequest) (bool, error) {
+	this.closeSuspended()
+	this.start(req)
+	return this.wait(req)
+}
+
+/*
+Requests are serviced apart from the session, so that one suspended by
+the maximum row count of Execute can be resumed by a later message. A
+suspended request holds its servicer, and, within a transaction, the
+transaction, so it is closed as any other statement is run.
+*/
+func (this *session) start(req *pgRequest) {
+	srvr := this.endpoint.server
+	go func() {
+		this.submit(srvr, req)
+		req.doStats(srvr)
+		close(req.done)
+	}()
+}
+
+/*
+Wait for a request to complete and send its outcome, or for it to be
+suspended, in which case it is still running.
+*/
+func (this *session) wait(req *pgRequest) (bool, error) {
+	this.setCurrent(req)
+	select {
+	case <-req.suspended:
+		this.setCurrent(nil)
+		return true, nil
+	case <-req.done:
+	}
+	this.setCurrent(nil)
+
+	if req.txId != "" {
+		this.txId = req.txId
+	}
+	switch req.Type() {
+	case "COMMIT", "ROLLBACK":
+		this.txId = ""
+	}
+
+	for _, w := range req.Warnings() {
+		err := this.conn.sendError(_MSG_NOTICE_RESPONSE, _SEVERITY_WARNING, _SQLSTATE_WARNING, w.Error(),
+			fmt.Sprintf("warning %d", w.Code()))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	errs := req.Errors()
+	if len(errs) == 0 && req.State() != server.COMPLETED {
+		err := newPgError(_SQLSTATE_QUERY_CANCELED, "canceling statement due to user request")
+		return false, this.sendError(_SEVERITY_ERROR, err)
+	}
+	if len(errs) > 0 {
+		return false, this.sendError(_SEVERITY_ERROR, errs[0])
+	}
+	if req.collect {
+		return true, nil
+	}
+	return true, this.conn.sendCommandComplete(commandTag(req))
+}
+
+func (this *session) submit(srvr *server.Server, req *pgRequest) {
+	if srvr.ShuttingDown() && req.TxId() == "" &&
+		!util.IsFeatureEnabled(util.GetN1qlFeatureControl(), util.N1QL_PART_GRACEFUL) {
+
+		if srvr.ShutDown() {
+			req.Fail(errors.NewServiceShutDownError())
+		} else {
+			req.Fail(errors.NewServiceShuttingDownError())
+		}
+		req.Failed(srvr)
+		return
+	}
+
+	// listed in system:active_requests, where it can be cancelled, and checked by kill rules
+	server.ActiveRequestsPut(req)
+	defer server.ActiveRequestsRemove(req.Id().String())
+
+	if req.ScanConsistency() == datastore.UNBOUNDED && req.TxId() == "" {
+		srvr.ServiceRequest(req)
+	} else {
+		srvr.PlusServiceRequest(req)
+	}
+}
+
+/*
+Stop the request of a suspended portal, which can no longer be executed.
+*/
+func (this *session) closePortal(p *portal) {
+	if p.req != nil {
+		p.req.Stop(server.STOPPED)
+		<-p.req.done
+		p.req = nil
+	}
+}
+
+func (this *session) closeSuspended() {
+	for name, p := range this.portals {
+		if p.req != nil {
+			this.closePortal(p)
+			delete(this.portals, name)
+		}
+	}
+}
+
+func commandTag(req *pgRequest) string {
+	switch t := req.Type(); t {
+	case "SELECT":
+		// a resumed portal reports the rows of its last Execute
+		if req.resumed {
+			return "SELECT " + strconv.Itoa(req.sent)
+		}
+		return "SELECT " + strconv.Itoa(req.resultCount)
+	case "INSERT", "UPSERT":
+		return "INSERT 0 " + strconv.FormatUint(req.MutationCount(), 10)
+	case "UPDATE", "DELETE", "MERGE":
+		return t + " " + strconv.FormatUint(req.MutationCount(), 10)
+	case "START_TRANSACTION":
+		return "BEGIN"
+	case "ROLLBACK_SAVEPOINT":
+		return "ROLLBACK"
+	default:
+		return strings.ReplaceAll(t, "_", " ")
+	}
+}
+
+func sqlState(err errors.Error) string {
+	switch code := err.Code(); {
+	case code == errors.E_NO_SUCH_PREPARED:
+		return _SQLSTATE_INVALID_STATEMENT_NAME
+	case code == errors.E_SERVICE_REQUEST_QUEUE_FULL:
+		return _SQLSTATE_TOO_MANY_CONNECTIONS
+	case code == errors.E_SERVICE_TIMEOUT:
+		return _SQLSTATE_QUERY_CANCELED
+	case code == errors.E_SERVICE_SHUTTING_DOWN, code == errors.E_SERVICE_SHUT_DOWN:
+		return _SQLSTATE_ADMIN_SHUTDOWN
+	case code == errors.E_SERVICE_READONLY:
+		return _SQLSTATE_READ_ONLY_TRANSACTION
+	case code == errors.E_DATASTORE_AUTHORIZATION, code == errors.E_DATASTORE_INSUFFICIENT_CREDENTIALS:
+		return _SQLSTATE_INSUFFICIENT_PRIVILEGE
+	case code == errors.E_DUPLICATE_KEY:
+		return _SQLSTATE_UNIQUE_VIOLATION
+	case code >= errors.E_PARSE_SYNTAX && code < errors.E_PARSE_SYNTAX+1000:
+		return _SQLSTATE_SYNTAX_ERROR
+	}
+	return _SQLSTATE_INTERNAL_ERROR
+}
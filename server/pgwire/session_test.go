@@ -0,0 +1,127 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package pgwire
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTLSConfig(t *testing.T) *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+}
+
+// Starts the startup of a session over a pipe, returning the client end
+func startSession(t *testing.T, endpoint *Endpoint) net.Conn {
+	client, server := net.Pipe()
+	s := &session{endpoint: endpoint, conn: newConn(server, 0), remoteAddr: "pipe"}
+	go func() {
+		s.startup()
+		server.Close()
+	}()
+	t.Cleanup(func() { client.Close() })
+	client.SetDeadline(time.Now().Add(10 * time.Second))
+	return client
+}
+
+func writeStartup(t *testing.T, c net.Conn, code int32, params ...string) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b[4:], uint32(code))
+	for _, p := range params {
+		b = append(append(b, p...), 0)
+	}
+	if len(params) > 0 {
+		b = append(b, 0)
+	}
+	binary.BigEndian.PutUint32(b, uint32(len(b)))
+	if _, err := c.Write(b); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readReply(t *testing.T, c net.Conn) (byte, string) {
+	var h [5]byte
+	if _, err := io.ReadFull(c, h[:]); err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, binary.BigEndian.Uint32(h[1:])-4)
+	if _, err := io.ReadFull(c, b); err != nil {
+		t.Fatal(err)
+	}
+	return h[0], string(b)
+}
+
+func TestStartupEncryption(t *testing.T) {
+	config := testTLSConfig(t)
+	endpoint := &Endpoint{tlsConfig: func() *tls.Config { return config }}
+
+	// an SSL request is accepted, and the password requested over TLS
+	c := startSession(t, endpoint)
+	writeStartup(t, c, _SSL_REQUEST)
+	var answer [1]byte
+	if _, err := io.ReadFull(c, answer[:]); err != nil || answer[0] != 'S' {
+		t.Fatalf("Expected the SSL request to be accepted, found %q, %v", answer[0], err)
+	}
+	tc := tls.Client(c, &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"postgresql"}})
+	writeStartup(t, tc, _PROTOCOL_VERSION, "user", "alice")
+	if typ, _ := readReply(t, tc); typ != _MSG_AUTHENTICATION {
+		t.Fatalf("Expected an authentication request, found %q", typ)
+	}
+	if p := tc.ConnectionState().NegotiatedProtocol; p != "postgresql" {
+		t.Fatalf("Expected the postgresql protocol to be negotiated, found %q", p)
+	}
+
+	// unencrypted connections are refused
+	c = startSession(t, endpoint)
+	writeStartup(t, c, _PROTOCOL_VERSION, "user", "alice")
+	if typ, msg := readReply(t, c); typ != _MSG_ERROR_RESPONSE || !strings.Contains(msg, _SQLSTATE_INVALID_AUTHORIZATION) {
+		t.Fatalf("Expected the unencrypted connection to be refused, found %q %q", typ, msg)
+	}
+
+	// unless they are allowed
+	endpoint.cleartext = true
+	c = startSession(t, endpoint)
+	writeStartup(t, c, _PROTOCOL_VERSION, "user", "alice")
+	if typ, _ := readReply(t, c); typ != _MSG_AUTHENTICATION {
+		t.Fatalf("Expected an authentication request, found %q", typ)
+	}
+
+	// without certificates, SSL requests are declined
+	endpoint.tlsConfig = func() *tls.Config { return nil }
+	c = startSession(t, endpoint)
+	writeStartup(t, c, _SSL_REQUEST)
+	if _, err := io.ReadFull(c, answer[:]); err != nil || answer[0] != 'N' {
+		t.Fatalf("Expected the SSL request to be declined, found %q, %v", answer[0], err)
+	}
+}
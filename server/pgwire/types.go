@@ -0,0 +1,472 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package pgwire
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/couchbase/query/value"
+)
+
+// type oids, from pg_type
+const (
+	_OID_UNKNOWN = 0
+	_OID_BOOL    = 16
+	_OID_BYTEA   = 17
+	_OID_CHAR    = 18
+	_OID_NAME    = 19
+	_OID_INT8    = 20
+	_OID_INT2    = 21
+	_OID_INT4    = 23
+	_OID_TEXT    = 25
+	_OID_JSON    = 114
+	_OID_FLOAT4  = 700
+	_OID_FLOAT8  = 701
+	_OID_UNTYPED = 705
+	_OID_BPCHAR  = 1042
+	_OID_VARCHAR = 1043
+	_OID_NUMERIC = 1700
+	_OID_JSONB   = 3802
+)
+
+const (
+	_FORMAT_TEXT   = 0
+	_FORMAT_BINARY = 1
+)
+
+// the name PostgreSQL gives to unnamed result columns
+const _UNNAMED_COLUMN = "?column?"
+
+/*
+A result column. The star column holds the fields of a result that are
+not projected by any other column, as a single JSON object.
+*/
+type column struct {
+	name string
+	oid  uint32
+	star bool
+	raw  bool
+}
+
+func (this *column) size() int16 {
+	switch this.oid {
+	case _OID_BOOL:
+		return 1
+	}
+	return -1
+}
+
+/*
+Derive the result columns from the signature of a statement. Projections
+have an object signature mapping each alias to its type; RAW projections
+and most other statements have a single type name. Statements with no
+signature produce no rows, except for those listed in rowStatements.
+*/
+func columns(signature value.Value, stmtType string) []*column {
+	if _, ok := noRowStatements[stmtType]; ok {
+		return nil
+	}
+	if signature == nil {
+		if _, ok := rowStatements[stmtType]; ok {
+			return []*column{&column{name: _UNNAMED_COLUMN, oid: _OID_JSON, raw: true}}
+		}
+		return nil
+	}
+
+	switch signature.Type() {
+	case value.STRING:
+		return []*column{&column{name: _UNNAMED_COLUMN, oid: typeOid(signature.ToString()), raw: true}}
+	case value.OBJECT:
+		var order []string
+		if av, ok := signature.(value.AnnotatedValue); ok {
+			order = av.ProjectionOrder()
+		}
+		fields := signature.Fields()
+		if len(order) == 0 {
+			order = make([]string, 0, len(fields))
+			for name, _ := range fields {
+				order = append(order, name)
+			}
+			sort.Strings(order)
+		}
+		rv := make([]*column, 0, len(order))
+		for _, name := range order {
+			t, _ := signature.Field(name)
+			if name == "*" {
+				rv = append(rv, &column{name: name, oid: _OID_JSON, star: true})
+			} else if t != nil && t.Type() == value.STRING {
+				rv = append(rv, &column{name: name, oid: typeOid(t.ToString())})
+			} else {
+				rv = append(rv, &column{name: name, oid: _OID_JSON})
+			}
+		}
+		return rv
+	}
+	return []*column{&column{name: _UNNAMED_COLUMN, oid: _OID_JSON, raw: true}}
+}
+
+// statements that return rows without a signature
+var rowStatements = map[string]bool{
+	"INFER":            true,
+	"EXECUTE_FUNCTION": true,
+}
+
+// transaction control statements return no rows, as in PostgreSQL
+var noRowStatements = map[string]bool{
+	"START_TRANSACTION":         true,
+	"COMMIT":                    true,
+	"ROLLBACK":                  true,
+	"ROLLBACK_SAVEPOINT":        true,
+	"SAVEPOINT":                 true,
+	"SET_TRANSACTION_ISOLATION": true,
+}
+
+func typeOid(name string) uint32 {
+	switch name {
+	case value.BOOLEAN.String():
+		return _OID_BOOL
+	case value.NUMBER.String():
+		return _OID_NUMERIC
+	case value.STRING.String():
+		return _OID_TEXT
+	case value.BINARY.String():
+		return _OID_BYTEA
+	}
+	return _OID_JSON
+}
+
+/*
+The format of the i-th result column, as requested by Bind: no formats
+means text for all columns, a single format applies to all columns.
+*/
+func resultFormat(formats []int16, i int) int16 {
+	switch len(formats) {
+	case 0:
+		return _FORMAT_TEXT
+	case 1:
+		return formats[0]
+	}
+	if i < len(formats) {
+		return formats[i]
+	}
+	return _FORMAT_TEXT
+}
+
+/*
+The values of the columns of a result item. A nil value is NULL.
+*/
+func rowValues(item value.Value, columns []*column) []value.Value {
+	rv := make([]value.Value, len(columns))
+	if len(columns) == 1 && columns[0].raw {
+		rv[0] = item
+		return rv
+	}
+
+	var star int = -1
+	for i, c := range columns {
+		if c.star {
+			star = i
+			continue
+		}
+		if v, ok := item.Field(c.name); ok {
+			rv[i] = v
+		}
+	}
+	if star >= 0 {
+		rest := make(map[string]interface{}, len(item.Fields()))
+		for name, v := range item.Fields() {
+			if !projected(name, columns) {
+				rest[name] = v
+			}
+		}
+		rv[star] = value.NewValue(rest)
+	}
+	return rv
+}
+
+func projected(name string, columns []*column) bool {
+	for _, c := range columns {
+		if !c.star && c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Encode a column value in the requested format. MISSING and NULL map
+to SQL NULL, and values that do not match the type of the column are
+sent as JSON text.
+*/
+func encodeValue(v value.Value, oid uint32, format int16) ([]byte, bool) {
+	if v == nil || v.Type() <= value.NULL {
+		return nil, true
+	}
+
+	if format == _FORMAT_BINARY {
+		switch oid {
+		case _OID_BOOL:
+			if v.Type() == value.BOOLEAN {
+				if v.Truth() {
+					return []byte{1}, true
+				}
+				return []byte{0}, true
+			}
+		case _OID_NUMERIC:
+			if v.Type() == value.NUMBER {
+				return encodeNumeric(v), true
+			}
+		case _OID_BYTEA:
+			if b, ok := v.Actual().([]byte); ok {
+				return b, true
+			}
+		case _OID_TEXT:
+			if v.Type() == value.STRING {
+				return []byte(v.ToString()), true
+			}
+		case _OID_JSON:
+			b, err := v.MarshalJSON()
+			return b, err == nil
+		}
+		return nil, false
+	}
+
+	switch v.Type() {
+	case value.BOOLEAN:
+		if oid == _OID_BOOL {
+			if v.Truth() {
+				return []byte{'t'}, true
+			}
+			return []byte{'f'}, true
+		}
+	case value.STRING:
+		if oid == _OID_TEXT {
+			return []byte(v.ToString()), true
+		}
+	case value.BINARY:
+		if b, ok := v.Actual().([]byte); ok {
+			return []byte("\\x" + hexEncode(b)), true
+		}
+	}
+	b, err := v.MarshalJSON()
+	return b, err == nil
+}
+
+func hexEncode(b []byte) string {
+	const digits = "0123456789abcdef"
+	rv := make([]byte, 2*len(b))
+	for i, c := range b {
+		rv[2*i] = digits[c>>4]
+		rv[2*i+1] = digits[c&0x0f]
+	}
+	return string(rv)
+}
+
+const (
+	_NUMERIC_POS  = 0x0000
+	_NUMERIC_NEG  = 0x4000
+	_NUMERIC_NAN  = 0xC000
+	_NUMERIC_PINF = 0xD000
+	_NUMERIC_NINF = 0xF000
+)
+
+/*
+The binary numeric format is a sequence of base 10000 digits, with the
+weight of the first digit, the sign and the number of decimal digits
+after the point.
+*/
+func encodeNumeric(v value.Value) []byte {
+	var s string
+	switch a := v.Actual().(type) {
+	case int64:
+		s = strconv.FormatInt(a, 10)
+	case float64:
+		switch {
+		case math.IsNaN(a):
+			return numericHeader(nil, 0, _NUMERIC_NAN, 0)
+		case math.IsInf(a, 1):
+			return numericHeader(nil, 0, _NUMERIC_PINF, 0)
+		case math.IsInf(a, -1):
+			return numericHeader(nil, 0, _NUMERIC_NINF, 0)
+		}
+		s = strconv.FormatFloat(a, 'f', -1, 64)
+	default:
+		s = strconv.FormatFloat(value.AsNumberValue(v).Float64(), 'f', -1, 64)
+	}
+
+	sign := _NUMERIC_POS
+	if strings.HasPrefix(s, "-") {
+		sign = _NUMERIC_NEG
+		s = s[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	intPart = strings.TrimLeft(intPart, "0")
+	dscale := len(fracPart)
+
+	// pad both parts to whole groups of 4 digits
+	if r := len(intPart) % 4; r != 0 {
+		intPart = strings.Repeat("0", 4-r) + intPart
+	}
+	if r := len(fracPart) % 4; r != 0 {
+		fracPart = fracPart + strings.Repeat("0", 4-r)
+	}
+	weight := len(intPart)/4 - 1
+	all := intPart + fracPart
+
+	digits := make([]int16, 0, len(all)/4)
+	for i := 0; i < len(all); i += 4 {
+		d, _ := strconv.Atoi(all[i : i+4])
+		digits = append(digits, int16(d))
+	}
+
+	// strip leading and trailing zero digits
+	for len(digits) > 0 && digits[0] == 0 {
+		digits = digits[1:]
+		weight--
+	}
+	for len(digits) > 0 && digits[len(digits)-1] == 0 {
+		digits = digits[:len(digits)-1]
+	}
+	if len(digits) == 0 {
+		weight = 0
+		sign = _NUMERIC_POS
+	}
+	return numericHeader(digits, weight, sign, dscale)
+}
+
+func numericHeader(digits []int16, weight int, sign int, dscale int) []byte {
+	rv := make([]byte, 8+2*len(digits))
+	binary.BigEndian.PutUint16(rv[0:], uint16(len(digits)))
+	binary.BigEndian.PutUint16(rv[2:], uint16(int16(weight)))
+	binary.BigEndian.PutUint16(rv[4:], uint16(sign))
+	binary.BigEndian.PutUint16(rv[6:], uint16(dscale))
+	for i, d := range digits {
+		binary.BigEndian.PutUint16(rv[8+2*i:], uint16(d))
+	}
+	return rv
+}
+
+/*
+Decode a bound parameter. Text parameters of unspecified type are
+taken to be JSON if they parse as such, and strings otherwise.
+*/
+func decodeParameter(b []byte, oid uint32, format int16) (value.Value, error) {
+	if b == nil {
+		return value.NULL_VALUE, nil
+	}
+
+	if format == _FORMAT_BINARY {
+		switch oid {
+		case _OID_BOOL:
+			if len(b) == 1 {
+				return value.NewValue(b[0] != 0), nil
+			}
+		case _OID_INT2:
+			if len(b) == 2 {
+				return value.NewValue(int64(int16(binary.BigEndian.Uint16(b)))), nil
+			}
+		case _OID_INT4:
+			if len(b) == 4 {
+				return value.NewValue(int64(int32(binary.BigEndian.Uint32(b)))), nil
+			}
+		case _OID_INT8:
+			if len(b) == 8 {
+				return value.NewValue(int64(binary.BigEndian.Uint64(b))), nil
+			}
+		case _OID_FLOAT4:
+			if len(b) == 4 {
+				return value.NewValue(float64(math.Float32frombits(binary.BigEndian.Uint32(b)))), nil
+			}
+		case _OID_FLOAT8:
+			if len(b) == 8 {
+				return value.NewValue(math.Float64frombits(binary.BigEndian.Uint64(b))), nil
+			}
+		case _OID_TEXT, _OID_VARCHAR, _OID_BPCHAR, _OID_CHAR, _OID_NAME, _OID_UNKNOWN, _OID_UNTYPED:
+			return value.NewValue(string(b)), nil
+		case _OID_JSON:
+			return parseJSON(b)
+		case _OID_JSONB:
+			// version 1 of the jsonb binary format is the JSON text
+			if len(b) > 0 && b[0] == 1 {
+				return parseJSON(b[1:])
+			}
+		case _OID_BYTEA:
+			return value.NewBinaryValue(b), nil
+		default:
+			return nil, newPgError(_SQLSTATE_FEATURE_NOT_SUPPORTED, "unsupported binary format for parameter type %v", oid)
+		}
+		return nil, newPgError(_SQLSTATE_INVALID_BINARY_REPRESENTATION, "invalid binary value for parameter type %v", oid)
+	}
+
+	s := string(b)
+	switch oid {
+	case _OID_TEXT, _OID_VARCHAR, _OID_BPCHAR, _OID_CHAR, _OID_NAME:
+		return value.NewValue(s), nil
+	case _OID_BOOL:
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "t", "true", "y", "yes", "on", "1":
+			return value.TRUE_VALUE, nil
+		case "f", "false", "n", "no", "off", "0":
+			return value.FALSE_VALUE, nil
+		}
+		return nil, newPgError(_SQLSTATE_INVALID_TEXT_REPRESENTATION, "invalid input syntax for type boolean: \"%s\"", s)
+	case _OID_INT2, _OID_INT4, _OID_INT8:
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return nil, newPgError(_SQLSTATE_INVALID_TEXT_REPRESENTATION, "invalid input syntax for type integer: \"%s\"", s)
+		}
+		return value.NewValue(i), nil
+	case _OID_FLOAT4, _OID_FLOAT8, _OID_NUMERIC:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, newPgError(_SQLSTATE_INVALID_TEXT_REPRESENTATION, "invalid input syntax for type numeric: \"%s\"", s)
+		}
+		return value.NewValue(f), nil
+	case _OID_JSON, _OID_JSONB:
+		return parseJSON(b)
+	case _OID_BYTEA:
+		if strings.HasPrefix(s, "\\x") {
+			return hexDecode(s[2:])
+		}
+		return value.NewBinaryValue(b), nil
+	}
+
+	v := value.NewValue(b)
+	if v.Type() == value.BINARY {
+		return value.NewValue(s), nil
+	}
+	return v, nil
+}
+
+func parseJSON(b []byte) (value.Value, error) {
+	v := value.NewValue(b)
+	if v.Type() == value.BINARY {
+		return nil, newPgError(_SQLSTATE_INVALID_TEXT_REPRESENTATION, "invalid input syntax for type json")
+	}
+	return v, nil
+}
+
+func hexDecode(s string) (value.Value, error) {
+	if len(s)%2 != 0 {
+		return nil, newPgError(_SQLSTATE_INVALID_TEXT_REPRESENTATION, "invalid hexadecimal data: odd number of digits")
+	}
+	rv := make([]byte, len(s)/2)
+	for i := 0; i < len(rv); i++ {
+		b, err := strconv.ParseUint(s[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return nil, newPgError(_SQLSTATE_INVALID_TEXT_REPRESENTATION, "invalid hexadecimal digit")
+		}
+		rv[i] = byte(b)
+	}
+	return value.NewBinaryValue(rv), nil
+}
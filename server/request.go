@@ -222,6 +222,9 @@ type Request interface {
 	NotifyStop(stop execution.Operator)
 	Failed(server *Server)
 	Expire(state State, timeout time.Duration)
+	Stop(state State)
+	Kill(rule, condition string)
+	KillRule() string
 	SortCount() uint64
 	State() State
 	SetState(State)
@@ -339,6 +342,13 @@ func ActiveRequestsCount() (int, errors.Error) {
 	return 0, nil
 }
 
+func ActiveRequestsPut(request Request) errors.Error {
+	if actives != nil {
+		return actives.Put(request)
+	}
+	return nil
+}
+
 func ActiveRequestsDelete(id string) bool {
 	if actives != nil {
 		return actives.Delete(id, true, nil)
@@ -353,6 +363,14 @@ func ActiveRequestsDeleteFunc(id string, f func(Request) bool) bool {
 	return false
 }
 
+// removes a request that has completed, without stopping it
+func ActiveRequestsRemove(id string) bool {
+	if actives != nil {
+		return actives.Delete(id, false, nil)
+	}
+	return false
+}
+
 func ActiveRequestsGet(id string, f func(Request)) errors.Error {
 	if actives != nil {
 		return actives.Get(id, f)
@@ -402,6 +420,7 @@ type BaseRequest struct {
 	namespace            string
 	timeout              time.Duration
 	timer                *time.Timer
+	killRule             string
 	maxParallelism       int
 	scanCap              int64
 	pipelineCap          int64
@@ -913,6 +932,21 @@ func (this State) StateName() string {
 	return states[int(this)]
 }
 
+// terminates the request for matching the condition of a kill rule
+func (this *BaseRequest) Kill(rule, condition string) {
+	this.Lock()
+	this.killRule = rule
+	this.Unlock()
+	this.Error(errors.NewKillRuleError(rule, condition))
+	this.Stop(STOPPED)
+}
+
+func (this *BaseRequest) KillRule() string {
+	this.RLock()
+	defer this.RUnlock()
+	return this.killRule
+}
+
 func (this *BaseRequest) Halted() bool {
 
 	// we purposly do not take the lock
@@ -1101,6 +1101,8 @@ func (this *Server) setupRequestContext(request Request) bool {
 	context.SetUserAgent(request.UserAgent())
 	context.SetUsers(datastore.CredsString(request.Credentials()))
 	context.SetRemoteAddr(request.RemoteAddr())
+	context.SetClientContextId(request.ClientID().String())
+	context.SetStatement(request.Statement())
 	context.SetScanReportWait(request.ScanReportWait())
 
 	if request.TxId() != "" {
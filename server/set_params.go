@@ -57,6 +57,13 @@ var _SETTERS = map[string]Setter{
 		s.SetLogLevel(value)
 		return nil
 	},
+	LOGFORMAT: func(s *Server, o interface{}) errors.Error {
+		value, _ := o.(string)
+		if !logging.SetLogFormat(value) {
+			return errors.NewServiceErrorBadValue(nil, "settings")
+		}
+		return nil
+	},
 	MAXPARALLELISM: func(s *Server, o interface{}) errors.Error {
 		value := getNumber(o)
 		s.SetMaxParallelism(int(value))
@@ -570,6 +577,7 @@ func FillSettings(settings map[string]interface{}, srvr *Server) map[string]inte
 	settings[TIMEOUTSETTING] = srvr.Timeout()
 	settings[KEEPALIVELENGTH] = srvr.KeepAlive()
 	settings[LOGLEVEL] = srvr.LogLevel()
+	settings[LOGFORMAT] = logging.LogFormat()
 	threshold, _ := RequestsGetQualifier("threshold", "")
 	settings[CMPTHRESHOLD] = threshold
 	settings[CMPLIMIT] = RequestsLimit()
@@ -0,0 +1,132 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package settings
+
+import (
+	"fmt"
+
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/value"
+)
+
+/*
+ * Kill rules:
+ *
+ * A running request that matches the condition of a kill rule is terminated, with an error naming
+ * the rule. The condition is a predicate over the request as system:active_requests shows it, times
+ * being in seconds, and is checked periodically on every node for the requests running on it:
+ *
+ *   UPDATE system:settings SET kill_rules = [
+ *     {"name": "reports", "condition": "users = 'local:reports' AND (usedMemory > 2147483648 OR phaseCounts.`fetch` > 10000000)"}
+ *   ]
+ *
+ * A rule is disabled, without being removed, with "enabled": false.
+ */
+type KillRule struct {
+	Name      string
+	Condition string
+	Enabled   bool
+}
+
+// represents the server, which checks that the condition of a rule is a valid predicate
+type KillRuleChecker interface {
+	CheckKillRule(condition string) errors.Error
+}
+
+var killRuleChecker KillRuleChecker
+
+func SetKillRuleChecker(checker KillRuleChecker) {
+	killRuleChecker = checker
+}
+
+// the enabled kill rules
+func GetKillRules() []*KillRule {
+	if globalSettings == nil {
+		return nil
+	}
+
+	var rules []*KillRule
+	globalSettings.RLock()
+	list, _ := globalSettings.settings[KILL_RULES].([]interface{})
+	for _, r := range list {
+		rule, ok := r.(map[string]interface{})
+		if !ok || rule["enabled"] != true {
+			continue
+		}
+		name, _ := rule["name"].(string)
+		condition, _ := rule["condition"].(string)
+		rules = append(rules, &KillRule{Name: name, Condition: condition, Enabled: true})
+	}
+	globalSettings.RUnlock()
+	return rules
+}
+
+// the rules with defaults filled in, or an error if any is invalid
+func validateKillRules(val interface{}) ([]interface{}, errors.Error) {
+	if actual, ok := val.(value.Value); ok {
+		val = actual.Actual()
+	}
+	if val == nil {
+		return nil, nil
+	}
+	list, ok := val.([]interface{})
+	if !ok {
+		return nil, errors.NewSettingsInvalidType(KILL_RULES, "array", val)
+	}
+
+	rules := make([]interface{}, 0, len(list))
+	names := make(map[string]bool, len(list))
+	for i, r := range list {
+		if actual, ok := r.(value.Value); ok {
+			r = actual.Actual()
+		}
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, errors.NewSettingsInvalidType(fmt.Sprintf("%s[%d]", KILL_RULES, i), "object", r)
+		}
+
+		var name, condition string
+		enabled := true
+		for k, v := range rule {
+			if actual, ok := v.(value.Value); ok {
+				v = actual.Actual()
+			}
+			switch k {
+			case "name":
+				name, ok = v.(string)
+			case "condition":
+				condition, ok = v.(string)
+			case "enabled":
+				enabled, ok = v.(bool)
+			default:
+				return nil, errors.NewSettingsError(nil, fmt.Sprintf("Invalid field '%s' in %s[%d]", k, KILL_RULES, i))
+			}
+			if !ok {
+				return nil, errors.NewSettingsInvalidType(fmt.Sprintf("%s[%d].%s", KILL_RULES, i, k), "", v)
+			}
+		}
+		if name == "" || condition == "" {
+			return nil, errors.NewSettingsError(nil, fmt.Sprintf("A name and a condition are required for %s[%d]",
+				KILL_RULES, i))
+		}
+		if names[name] {
+			return nil, errors.NewSettingsError(nil, fmt.Sprintf("Duplicate kill rule '%s'", name))
+		}
+		names[name] = true
+
+		if killRuleChecker != nil {
+			err := killRuleChecker.CheckKillRule(condition)
+			if err != nil {
+				return nil, errors.NewSettingsError(err, fmt.Sprintf("Invalid condition for kill rule '%s'", name))
+			}
+		}
+		rules = append(rules, map[string]interface{}{"name": name, "condition": condition, "enabled": enabled})
+	}
+	return rules, nil
+}
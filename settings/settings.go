@@ -28,6 +28,7 @@ const (
 
 const (
 	PLAN_STABILITY = "plan_stability"
+	KILL_RULES     = "kill_rules"
 )
 
 func InitSettings() {
@@ -92,6 +93,9 @@ func processSettings(val []byte, thisNode string) {
 	for k, v := range globalSettings.settings {
 		if _, ok := vmap[k]; !ok {
 			removed[k] = v
+			if k == KILL_RULES {
+				delete(globalSettings.settings, k)
+			}
 		}
 	}
 	for k, v := range vmap {
@@ -103,6 +107,15 @@ func processSettings(val []byte, thisNode string) {
 			} else {
 				globalSettings.settings[k] = planStability
 			}
+		case KILL_RULES:
+			killRules, err := validateKillRules(v)
+			if err != nil {
+				logging.Errorf("SETTINGS: Error processing kill rules: %v", err)
+			} else if killRules == nil {
+				delete(globalSettings.settings, k)
+			} else {
+				globalSettings.settings[k] = killRules
+			}
 		default:
 			invalid[k] = v
 		}
@@ -121,8 +134,9 @@ func processSettings(val []byte, thisNode string) {
 }
 
 func defaultSettings() map[string]interface{} {
-	rv := map[string]interface{}{
-		PLAN_STABILITY: defaultPlanStabilitySettings(),
+	rv := map[string]interface{}{}
+	if PlanStabilityAvailable() {
+		rv[PLAN_STABILITY] = defaultPlanStabilitySettings()
 	}
 	globalSettings.Lock()
 	globalSettings.settings = rv
@@ -143,7 +157,7 @@ func FetchSettings(remap bool) (map[string]interface{}, errors.Error) {
 	}
 	delete(vmap, "node")
 
-	if remap {
+	if remap && PlanStabilityAvailable() {
 		if planStability, ok := vmap[PLAN_STABILITY]; ok {
 			remapPlanStability, err := remapPlanStabilitySetting(planStability)
 			if err != nil {
@@ -166,12 +180,21 @@ func UpdateSettings(enterprise bool, requestId string, settings interface{}) (er
 	}
 
 	hasPlanStability := false
+	var killRules []interface{}
 	var invalid []string
 	for k, v := range settingsMap {
 		switch k {
 		case PLAN_STABILITY:
 			// valid setting
 			hasPlanStability = true
+		case KILL_RULES:
+			// validated before any setting is changed
+			var err errors.Error
+			killRules, err = validateKillRules(v)
+			if err != nil {
+				logging.Errorf("SETTINGS: Invalid kill rules specified in UPDATE statement: %v", err)
+				return err, nil
+			}
 		default:
 			invalid = append(invalid, fmt.Sprintf("'%s':'%v'", k, v))
 		}
@@ -183,7 +206,7 @@ func UpdateSettings(enterprise bool, requestId string, settings interface{}) (er
 	}
 
 	// if the new document does not contain plan stability settings (e.g. UNSET used), use default
-	if !hasPlanStability {
+	if !hasPlanStability && PlanStabilityAvailable() {
 		settingsMap[PLAN_STABILITY] = defaultPlanStabilitySettings()
 	}
 
@@ -207,6 +230,13 @@ func UpdateSettings(enterprise bool, requestId string, settings interface{}) (er
 		}
 	}
 
+	// kill rules absent from the new document (e.g. UNSET used) are removed
+	if killRules != nil {
+		globalSettings.setSetting(KILL_RULES, killRules)
+	} else {
+		globalSettings.deleteSetting(KILL_RULES)
+	}
+
 	return nil, nil
 }
 
@@ -246,6 +276,8 @@ func (this *querySettings) getAllSettings() map[string]interface{} {
 				vmap[kk] = vv
 			}
 			allSettings[k] = vmap
+		case []interface{}:
+			allSettings[k] = append([]interface{}(nil), setting...)
 		case bool, string, int64, float64, int32, float32, int, uint, uint32, uint64, uintptr:
 			allSettings[k] = setting
 		default:
@@ -263,6 +295,12 @@ func (this *querySettings) setSetting(name string, value interface{}) {
 	this.Unlock()
 }
 
+func (this *querySettings) deleteSetting(name string) {
+	this.Lock()
+	delete(this.settings, name)
+	this.Unlock()
+}
+
 func (this *querySettings) getSetting(name string) interface{} {
 	var rv interface{}
 	this.RLock()
@@ -276,6 +314,8 @@ func (this *querySettings) getSetting(name string) interface{} {
 				vmap[k] = v
 			}
 			rv = vmap
+		case []interface{}:
+			rv = append([]interface{}(nil), setting...)
 		case bool, string, int64, float64, int32, float32, int, uint, uint32, uint64, uintptr:
 			rv = setting
 		default:
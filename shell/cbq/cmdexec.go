@@ -158,6 +158,12 @@ func command_query(line string, liner *liner.State) (errors.ErrorCode, string) {
 					break
 				}
 			}
+
+			// names offered for completion may have changed
+			switch strings.ToLower(strings.SplitN(line, " ", 2)[0]) {
+			case "create", "drop", "alter":
+				command.ResetCompletionNames()
+			}
 		}
 
 	}
@@ -25,7 +25,7 @@ func (this *Alias) Name() string {
 }
 
 func (this *Alias) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Alias) MinArgs() int {
@@ -219,6 +219,20 @@ sed -n '/^%%/,$ p' ${BASEPATH}/parser/n1ql/n1ql.y \
   | sed -e '/^[^A-Za-z_]/s/\/\*/£\n/;/^[^A-Za-z_]/s/\*\//£\n/;/^[^A-Za-z_]/s/\([:;|/*]\)/\1\n/g' \
   | awk "${AC}" >> "${FILE}"
 
+# keywords are the case insensitive words matched by the lexer
+cat - << EOF >> "${FILE}"
+
+var statement_keywords = []string{
+EOF
+
+grep -v 'return _' ${BASEPATH}/parser/n1ql/n1ql.nex \
+  | sed -n 's#^/\(\(\[[a-zA-Z][a-zA-Z]\]\|\[_\]\)\{1,\}\)/.*#\1#p' \
+  | sed 's/\[\(.\)[a-zA-Z]*\]/\1/g' \
+  | tr 'a-z' 'A-Z' | grep -v '^_' | LC_ALL=C sort -u \
+  | sed 's/.*/\t"&",/' >> "${FILE}"
+
+echo "}" >> "${FILE}"
+
 go fmt ${FILE} 2>/dev/null
 if [ $? -ne 0 ]
 then
@@ -35,6 +35,7 @@ const (
 	REDIRECT_CMD            = "REDIRECT"
 	REFRESH_CLUSTER_MAP_CMD = "REFRESH_CLUSTER_MAP"
 	SYNTAX_CMD              = "SYNTAX"
+	EXPLAIN_CMD             = "EXPLAIN"
 )
 
 const (
@@ -128,6 +129,9 @@ var COMMAND_LIST = map[string]ShellCommand{
 	"\\redirect": &Redirect{},
 
 	"\\refresh_cluster_map": &Refresh_cluster_map{},
+
+	/* Query Plans */
+	"\\explain": &Explain{},
 }
 
 /*
@@ -636,6 +636,8 @@ func printDesc(cmdname string) (errors.ErrorCode, string) {
 		_, err = OUTPUT.WriteString(DREFRESH_CLUSTERMAP)
 	case SYNTAX_CMD:
 		_, err = OUTPUT.WriteString(DSYNTAX)
+	case EXPLAIN_CMD:
+		_, err = OUTPUT.WriteString(DEXPLAIN)
 	default:
 		_, err = OUTPUT.WriteString(DDEFAULT)
 	}
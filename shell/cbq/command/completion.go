@@ -0,0 +1,359 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package command
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/couchbase/godbc/n1ql"
+)
+
+/*
+TAB completion. The word under the cursor is completed according to
+what precedes it: shell commands and their arguments, keyspace, index
+and function names, and otherwise keywords.
+*/
+
+const _WORD_DELIMITERS = " \t\r\n(),;=<>+*/%[]{}!|'\""
+
+/*
+Keyspace, index and function names are fetched from the system
+keyspaces the first time they are needed, and kept for as long as the
+connection is, or until a statement changes them.
+*/
+type completionNames struct {
+	sync.Mutex
+	db        n1ql.N1qlDB
+	keyspaces []string
+	indexes   []string
+	functions []string
+	loaded    bool
+}
+
+var names completionNames
+
+func ResetCompletionNames() {
+	names.Lock()
+	names.loaded = false
+	names.db = nil
+	names.Unlock()
+}
+
+func (this *completionNames) get() ([]string, []string, []string) {
+	this.Lock()
+	defer this.Unlock()
+	if DbN1ql == nil {
+		return nil, nil, nil
+	}
+	if !this.loaded || this.db != DbN1ql {
+		this.db = DbN1ql
+		this.keyspaces = keyspaceNames(queryNames(DbN1ql,
+			"SELECT RAW [k.`namespace`, k.`bucket`, k.`scope`, k.name] FROM system:keyspaces AS k"))
+		this.indexes = identifierNames(queryNames(DbN1ql,
+			"SELECT DISTINCT RAW [i.name] FROM system:indexes AS i"))
+		this.functions = identifierNames(queryNames(DbN1ql,
+			"SELECT DISTINCT RAW [f.identity.name] FROM system:functions AS f"))
+		this.loaded = true
+	}
+	return this.keyspaces, this.indexes, this.functions
+}
+
+// errors, including a lack of permissions, just mean there is nothing to offer
+func queryNames(db n1ql.N1qlDB, stmt string) [][]interface{} {
+	rows, err := db.QueryRaw(stmt)
+	if rows == nil {
+		return nil
+	}
+	defer rows.Close()
+	if err != nil {
+		return nil
+	}
+
+	var res struct {
+		Results [][]interface{} `json:"results"`
+	}
+	if json.NewDecoder(rows).Decode(&res) != nil {
+		return nil
+	}
+	return res.Results
+}
+
+func identifierNames(rows [][]interface{}) []string {
+	rv := make([]string, 0, len(rows))
+	for _, r := range rows {
+		if len(r) == 1 {
+			if s, ok := r[0].(string); ok {
+				rv = append(rv, quoteIdentifier(s))
+			}
+		}
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+/*
+Buckets are offered with their namespace if it is not the default, and
+collections with their full path.
+*/
+func keyspaceNames(rows [][]interface{}) []string {
+	rv := make([]string, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, r := range rows {
+		var path []string
+		ns := ""
+		for i, p := range r {
+			s, ok := p.(string)
+			if !ok {
+				continue
+			}
+			if i == 0 {
+				ns = s
+			} else {
+				path = append(path, quoteIdentifier(s))
+			}
+		}
+		if len(path) == 0 {
+			continue
+		}
+		name := strings.Join(path, ".")
+		if ns != "" && ns != "default" {
+			name = ns + ":" + name
+		}
+		if !seen[name] {
+			seen[name] = true
+			rv = append(rv, name)
+		}
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+func quoteIdentifier(s string) string {
+	for i, c := range s {
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9')) {
+			return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+		}
+	}
+	if s == "" || isKeyword(s) {
+		return "`" + s + "`"
+	}
+	return s
+}
+
+var keywords map[string]bool
+
+func init() {
+	keywords = make(map[string]bool, len(statement_keywords))
+	for _, k := range statement_keywords {
+		keywords[k] = true
+	}
+}
+
+func isKeyword(s string) bool {
+	return keywords[strings.ToUpper(s)]
+}
+
+/*
+Complete implements the word completer of the line editors: it returns
+the text before the word being completed, the candidates for the word,
+and the text after it. pos is in runes.
+*/
+func Complete(line string, pos int) (string, []string, string) {
+	r := []rune(line)
+	if pos > len(r) {
+		pos = len(r)
+	}
+	start := pos
+	for start > 0 && !strings.ContainsRune(_WORD_DELIMITERS, r[start-1]) {
+		start--
+	}
+	before := string(r[:start])
+	word := string(r[start:pos])
+	head := before
+	tail := string(r[pos:])
+
+	if inString(before) {
+		return head, nil, tail
+	}
+
+	words := strings.Fields(strings.TrimSpace(before))
+	var candidates []string
+	if strings.HasPrefix(strings.TrimSpace(before+word), "\\") {
+		candidates = shellCandidates(words, word)
+	} else {
+		candidates = statementCandidates(words, word)
+	}
+	return head, candidates, tail
+}
+
+// a word inside a string literal is not completed; quoted identifiers are
+func inString(s string) bool {
+	quote := rune(0)
+	escape := false
+	for _, c := range s {
+		switch {
+		case escape:
+			escape = false
+		case c == '\\' && quote != 0:
+			escape = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		}
+	}
+	return quote != 0
+}
+
+func shellCandidates(words []string, word string) []string {
+	if len(words) == 0 {
+		if strings.HasPrefix(word, "\\\\") {
+			var aliases []string
+			for k := range AliasCommand {
+				aliases = append(aliases, "\\\\"+k)
+			}
+			sort.Strings(aliases)
+			return matching(aliases, word, true)
+		}
+		var cmds []string
+		for _, k := range _SORTED_CMD_LIST {
+			if COMMAND_LIST[k].CommandCompletion() {
+				cmds = append(cmds, k)
+			}
+		}
+		return matching(cmds, word, false)
+	}
+
+	var args []string
+	switch strings.ToLower(words[0]) {
+	case "\\set", "\\unset", "\\push", "\\pop":
+		if len(words) > 1 {
+			return nil
+		}
+		return matching(variableNames(), word, true)
+	case "\\help":
+		for _, k := range _SORTED_CMD_LIST {
+			if COMMAND_LIST[k].CommandCompletion() {
+				args = append(args, k[1:])
+			}
+		}
+	case "\\unalias":
+		for k := range AliasCommand {
+			args = append(args, k)
+		}
+		sort.Strings(args)
+		return matching(args, word, true)
+	case "\\syntax":
+		for k := range statement_syntax {
+			if !strings.HasPrefix(k, "[") {
+				args = append(args, k)
+			}
+		}
+		sort.Strings(args)
+	case "\\source", "\\redirect":
+		if len(words) > 1 {
+			return nil
+		}
+		files, _ := filepath.Glob(word + "*")
+		return files
+	default:
+		return nil
+	}
+	return matching(args, word, false)
+}
+
+func variableNames() []string {
+	var rv []string
+	for k := range PreDefSV {
+		rv = append(rv, k)
+	}
+	for k := range QueryParam {
+		rv = append(rv, "-"+k)
+	}
+	for k := range NamedParam {
+		rv = append(rv, "-$"+k)
+	}
+	for k := range UserDefSV {
+		rv = append(rv, "$"+k)
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+func statementCandidates(words []string, word string) []string {
+	if word == "" {
+		return nil
+	}
+
+	first := ""
+	prev := ""
+	if len(words) > 0 {
+		first = strings.ToUpper(words[0])
+		prev = strings.ToUpper(words[len(words)-1])
+	}
+
+	switch prev {
+	case "FROM", "JOIN", "NEST", "INTO", "UPDATE", "KEYSPACE", "INFER", "TRUNCATE":
+		keyspaces, _, _ := names.get()
+		return matching(keyspaces, word, true)
+	case "ON":
+		if first == "CREATE" || first == "BUILD" {
+			keyspaces, _, _ := names.get()
+			return matching(keyspaces, word, true)
+		}
+	case "INDEX":
+		if first == "DROP" || first == "ALTER" {
+			_, indexes, _ := names.get()
+			return matching(indexes, word, true)
+		}
+	case "FUNCTION":
+		if first != "CREATE" {
+			_, _, functions := names.get()
+			return matching(functions, word, true)
+		}
+	}
+
+	rv := matching(statement_keywords, word, false)
+	if !strings.ContainsAny(word, ".`:") {
+		_, _, functions := names.get()
+		rv = append(rv, matching(functions, word, true)...)
+	}
+	return rv
+}
+
+/*
+Candidates starting with the word. Matching is case insensitive unless
+exact is set, and keywords and commands follow the case of the word.
+*/
+func matching(candidates []string, word string, exact bool) []string {
+	var rv []string
+	lower := word != strings.ToUpper(word)
+	upper := word != strings.ToLower(word)
+	for _, c := range candidates {
+		if exact {
+			if strings.HasPrefix(c, word) {
+				rv = append(rv, c)
+			}
+		} else if len(c) >= len(word) && strings.EqualFold(c[:len(word)], word) {
+			if lower {
+				c = strings.ToLower(c)
+			} else if upper {
+				c = strings.ToUpper(c)
+			}
+			rv = append(rv, c)
+		}
+	}
+	return rv
+}
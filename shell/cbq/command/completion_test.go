@@ -0,0 +1,63 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package command
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	AliasCommand["completetest"] = "select 1"
+	defer delete(AliasCommand, "completetest")
+
+	tests := []struct {
+		line        string
+		pos         int
+		head        string
+		completions []string
+		tail        string
+	}{
+		{"\\ec", 3, "", []string{"\\echo"}, ""},
+		{"\\EC", 3, "", []string{"\\ECHO"}, ""},
+		{"\\help dis", 9, "\\help ", []string{"disconnect"}, ""},
+		{"\\unalias completet", 18, "\\unalias ", []string{"completetest"}, ""},
+		{"\\\\completet", 11, "", []string{"\\\\completetest"}, ""},
+		{"\\set hist", 9, "\\set ", []string{"histfile"}, ""},
+		{"selec", 5, "", []string{"select"}, ""},
+		{"SELECT * FROM b WHER", 20, "SELECT * FROM b ", []string{"WHERE"}, ""},
+		{"select 1 unio; x", 13, "select 1 ", []string{"union"}, "; x"},
+		{"select 'whe", 11, "select '", nil, ""},
+		{"select ", 7, "select ", nil, ""},
+	}
+
+	for _, test := range tests {
+		head, completions, tail := Complete(test.line, test.pos)
+		if head != test.head || !reflect.DeepEqual(completions, test.completions) || tail != test.tail {
+			t.Errorf("Complete(%q, %d): expected %q %q %q, got %q %q %q", test.line, test.pos,
+				test.head, test.completions, test.tail, head, completions, tail)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"beer":          "beer",
+		"travel-sample": "`travel-sample`",
+		"_default":      "_default",
+		"user":          "`user`",
+		"1st":           "`1st`",
+	}
+
+	for name, expected := range tests {
+		if quoted := quoteIdentifier(name); quoted != expected {
+			t.Errorf("quoteIdentifier(%q): expected %q, got %q", name, expected, quoted)
+		}
+	}
+}
@@ -25,7 +25,7 @@ func (this *Connect) Name() string {
 }
 
 func (this *Connect) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Connect) MinArgs() int {
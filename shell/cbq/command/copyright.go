@@ -22,7 +22,7 @@ func (this *Copyright) Name() string {
 }
 
 func (this *Copyright) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Copyright) MinArgs() int {
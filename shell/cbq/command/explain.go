@@ -0,0 +1,119 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package command
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/couchbase/query/errors"
+)
+
+/* Explain Command */
+type Explain struct {
+	ShellCommand
+}
+
+func (this *Explain) Name() string {
+	return "EXPLAIN"
+}
+
+func (this *Explain) CommandCompletion() bool {
+	return true
+}
+
+func (this *Explain) MinArgs() int {
+	return ONE_ARG
+}
+
+func (this *Explain) MaxArgs() int {
+	return MAX_ARGS
+}
+
+/*
+Run EXPLAIN FORMAT on the statement and print the plan as returned, so
+that the text and dot renderings are readable rather than JSON strings.
+*/
+func (this *Explain) ExecCommand(args []string) (errors.ErrorCode, string) {
+	if len(args) < this.MinArgs() {
+		return errors.E_SHELL_TOO_FEW_ARGS, ""
+	}
+
+	format := "TEXT"
+	switch strings.ToUpper(args[0]) {
+	case "TEXT", "DOT", "JSON":
+		format = strings.ToUpper(args[0])
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return errors.E_SHELL_TOO_FEW_ARGS, ""
+	}
+	if DbN1ql == nil {
+		return errors.E_SHELL_NO_CONNECTION, ""
+	}
+
+	stmt := strings.TrimSuffix(strings.TrimSpace(strings.Join(args, " ")), ";")
+	rows, err := DbN1ql.QueryRaw("EXPLAIN FORMAT " + format + " " + stmt)
+	if rows == nil {
+		if err != nil {
+			return errors.E_SHELL_DRIVER_QUERY_METHOD, err.Error()
+		}
+		return 0, ""
+	}
+	defer rows.Close()
+	if err != nil {
+		return errors.E_SHELL_DRIVER_QUERY_METHOD, err.Error()
+	}
+
+	var res struct {
+		Results []interface{} `json:"results"`
+	}
+	err = json.NewDecoder(rows).Decode(&res)
+	if err != nil {
+		return errors.E_SHELL_DRIVER_QUERY_METHOD, err.Error()
+	}
+
+	var werr error
+	for _, r := range res.Results {
+		s, ok := r.(string)
+		if !ok {
+			b, err := json.MarshalIndent(r, "", "    ")
+			if err != nil {
+				return errors.E_SHELL_DRIVER_QUERY_METHOD, err.Error()
+			}
+			s = string(b)
+		}
+		if !strings.HasSuffix(s, NEWLINE) {
+			s += NEWLINE
+		}
+		_, werr = OUTPUT.WriteString(s)
+		if werr != nil {
+			break
+		}
+	}
+	if werr != nil {
+		return errors.E_SHELL_WRITER_OUTPUT, werr.Error()
+	}
+	return 0, ""
+}
+
+func (this *Explain) PrintHelp(desc bool) (errors.ErrorCode, string) {
+	_, werr := OUTPUT.WriteString(HEXPLAIN)
+	if desc {
+		err_code, err_str := printDesc(this.Name())
+		if err_code != 0 {
+			return err_code, err_str
+		}
+	}
+	_, werr = OUTPUT.WriteString(NEWLINE)
+	if werr != nil {
+		return errors.E_SHELL_WRITER_OUTPUT, werr.Error()
+	}
+	return 0, ""
+}
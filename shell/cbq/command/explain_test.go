@@ -0,0 +1,40 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package command
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/errors"
+)
+
+/*
+   Test the \EXPLAIN command without a connection.
+*/
+
+func TestExplain(t *testing.T) {
+	explain := COMMAND_LIST["\\explain"]
+
+	errCode, _ := explain.ExecCommand([]string{})
+	if errCode != errors.E_SHELL_TOO_FEW_ARGS {
+		t.Errorf("Expected too few args, got %v", errCode)
+	}
+
+	errCode, _ = explain.ExecCommand([]string{"dot"})
+	if errCode != errors.E_SHELL_TOO_FEW_ARGS {
+		t.Errorf("Expected too few args for a format without a statement, got %v", errCode)
+	}
+
+	if DbN1ql == nil {
+		errCode, _ = explain.ExecCommand([]string{"select", "1"})
+		if errCode != errors.E_SHELL_NO_CONNECTION {
+			t.Errorf("Expected no connection, got %v", errCode)
+		}
+	}
+}
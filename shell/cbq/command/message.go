@@ -124,6 +124,7 @@ const (
 	HSOURCE             = "\\SOURCE filename\n"
 	HREFRESH_CLUSTERMAP = "\\REFRESH_CLUSTER_MAP\n"
 	HSYNTAX             = "\\SYNTAX [args ...]\n"
+	HEXPLAIN            = "\\EXPLAIN [TEXT | DOT | JSON] statement\n"
 
 	//Messages to print description of shell commands. D-> Description
 	DALIAS = " Create an alias (name) for input value. value can be shell command, " +
@@ -188,6 +189,11 @@ const (
 	DREFRESH_CLUSTERMAP = "Refresh the list of query APIs to reflect input service url as cluster. " +
 		"\tExample : \n\t\t \\REFRESH_CLUSTER_MAP;\n"
 
+	DEXPLAIN = "Display the plan of the input statement, as an indented tree (TEXT, the default), " +
+		"a Graphviz digraph (DOT) or JSON.\n" +
+		"\tExample : \n\t        \\EXPLAIN SELECT * FROM `travel-sample` WHERE type = \"hotel\";" +
+		"\n\t        \\EXPLAIN DOT SELECT * FROM `travel-sample`;\n"
+
 	// SQL++ statement help
 	DSYNTAX = "\nBasic syntax help information for SQL++ statements." +
 		"\nNOTE: This lists syntax that will parse, but this does not imply it is supported.\n" +
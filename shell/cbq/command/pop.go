@@ -27,7 +27,7 @@ func (this *Pop) Name() string {
 }
 
 func (this *Pop) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Pop) MinArgs() int {
@@ -26,7 +26,7 @@ func (this *Push) Name() string {
 }
 
 func (this *Push) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Push) MinArgs() int {
@@ -27,7 +27,7 @@ func (this *Redirect) Name() string {
 }
 
 func (this *Redirect) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Redirect) MinArgs() int {
@@ -22,7 +22,7 @@ func (this *Source) Name() string {
 }
 
 func (this *Source) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Source) MinArgs() int {
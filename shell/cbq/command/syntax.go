@@ -26,7 +26,7 @@ func (this *Syntax) Name() string {
 }
 
 func (this *Syntax) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Syntax) MinArgs() int {
@@ -34,6 +34,8 @@ var statement_syntax = map[string][][]string{
 	"permitted_identifiers": [][]string{
 		[]string{"alias_identifiers"},
 		[]string{"ORDER"},
+		[]string{"GAPFILL"},
+		[]string{"ASOF"},
 	},
 	"perm_ident_or_str": [][]string{
 		[]string{"permitted_identifiers"},
@@ -59,6 +61,10 @@ var statement_syntax = map[string][][]string{
 		[]string{"function_statement"},
 		[]string{"transaction_statement"},
 		[]string{"sequence_statement"},
+		[]string{"view_statement"},
+		[]string{"trigger_statement"},
+		[]string{"job_statement"},
+		[]string{"policy_statement"},
 		[]string{"credentialstore_statement"},
 	},
 	"advise": [][]string{
@@ -69,6 +75,11 @@ var statement_syntax = map[string][][]string{
 	},
 	"explain": [][]string{
 		[]string{"EXPLAIN", "statement"},
+		[]string{"EXPLAIN", "<identifier>", "<identifier>", "statement"},
+		[]string{"EXPLAIN", "ANALYZE", "statement"},
+		[]string{"EXPLAIN", "<identifier>", "<identifier>", "ANALYZE", "statement"},
+		[]string{"EXPLAIN", "ANALYZE", "WITH", "object", "statement"},
+		[]string{"EXPLAIN", "<identifier>", "<identifier>", "ANALYZE", "WITH", "object", "statement"},
 	},
 	"explain_function": [][]string{
 		[]string{"EXPLAIN", "FUNCTION", "func_name"},
@@ -160,6 +171,7 @@ var statement_syntax = map[string][][]string{
 		[]string{"drop_collection"},
 		[]string{"alter_collection"},
 		[]string{"flush_collection"},
+		[]string{"truncate"},
 	},
 	"external_collection_statement": [][]string{
 		[]string{"create_external_collection"},
@@ -286,6 +298,7 @@ var statement_syntax = map[string][][]string{
 		[]string{"from_term", "[join_type]", "unnest", "expression", "[as_alias]"},
 		[]string{"from_term", "[join_type]", "JOIN", "simple_from_term", "ON", "expression"},
 		[]string{"from_term", "[join_type]", "JOIN", "LATERAL", "simple_from_term", "ON", "expression"},
+		[]string{"from_term", "ASOF", "[join_type]", "JOIN", "simple_from_term", "ON", "expression"},
 		[]string{"from_term", "[join_type]", "NEST", "simple_from_term", "ON", "expression"},
 		[]string{"from_term", "[join_type]", "NEST", "LATERAL", "simple_from_term", "ON", "expression"},
 		[]string{"simple_from_term", "RIGHT", "[outer]", "JOIN", "simple_from_term", "ON", "expression"},
@@ -399,7 +412,11 @@ var statement_syntax = map[string][][]string{
 		[]string{"with_list", "COMMA", "with_term"},
 	},
 	"with_term": [][]string{
-		[]string{"alias", "AS", "paren_expr", "[cycle_clause]", "[option_clause]"},
+		[]string{"alias", "AS", "[materialization]", "paren_expr", "[cycle_clause]", "[option_clause]"},
+	},
+	"[materialization]": [][]string{
+		[]string{"MATERIALIZED"},
+		[]string{"NOT", "MATERIALIZED"},
 	},
 	"[option_clause]": [][]string{
 		[]string{"OPTIONS", "object"},
@@ -411,7 +428,7 @@ var statement_syntax = map[string][][]string{
 		[]string{"WHERE", "expression"},
 	},
 	"group": [][]string{
-		[]string{"GROUP", "BY", "group_terms", "[group_as]", "[letting]", "[having]"},
+		[]string{"GROUP", "BY", "group_terms", "[gapfill]", "[group_as]", "[letting]", "[having]"},
 		[]string{"letting"},
 	},
 	"group_terms": [][]string{
@@ -427,6 +444,10 @@ var statement_syntax = map[string][][]string{
 	"having": [][]string{
 		[]string{"HAVING", "expression"},
 	},
+	"[gapfill]": [][]string{
+		[]string{"GAPFILL"},
+		[]string{"GAPFILL", "LPAREN", "expression", "COMMA", "expression", "RPAREN"},
+	},
 	"[group_as]": [][]string{
 		[]string{"GROUP", "AS", "permitted_identifiers"},
 	},
@@ -817,6 +838,10 @@ var statement_syntax = map[string][][]string{
 		[]string{"FLUSH"},
 		[]string{"TRUNCATE"},
 	},
+	"truncate": [][]string{
+		[]string{"TRUNCATE", "named_keyspace_ref"},
+		[]string{"TRUNCATE", "<identifier>", "named_keyspace_ref"},
+	},
 	"create_external_collection": [][]string{
 		[]string{"CREATE", "EXTERNAL", "COLLECTION", "[if_not_exists]", "named_keyspace_ref", "ON", "perm_ident_or_str", "AT", "perm_ident_or_str", "with_clause"},
 	},
@@ -1408,6 +1433,100 @@ var statement_syntax = map[string][][]string{
 		[]string{"sequence_next"},
 		[]string{"sequence_prev"},
 	},
+	"view_object_name": [][]string{
+		[]string{"permitted_identifiers"},
+	},
+	"view_full_name": [][]string{
+		[]string{"[namespace_name]", "view_object_name"},
+		[]string{"[namespace_name]", "path_part", "DOT", "path_part", "DOT", "view_object_name"},
+		[]string{"[namespace_name]", "path_part", "DOT", "view_object_name"},
+	},
+	"view_statement": [][]string{
+		[]string{"create_view"},
+		[]string{"drop_view"},
+		[]string{"refresh_view"},
+	},
+	"create_view": [][]string{
+		[]string{"CREATE", "[replace]", "VIEW", "view_full_name", "AS", "fullselect"},
+		[]string{"CREATE", "MATERIALIZED", "VIEW", "view_full_name", "[with_clause]", "AS", "fullselect"},
+	},
+	"drop_view": [][]string{
+		[]string{"DROP", "VIEW", "view_full_name", "[if_exists]"},
+		[]string{"DROP", "VIEW", "IF", "EXISTS", "view_full_name"},
+		[]string{"DROP", "MATERIALIZED", "VIEW", "view_full_name", "[if_exists]"},
+		[]string{"DROP", "MATERIALIZED", "VIEW", "IF", "EXISTS", "view_full_name"},
+	},
+	"refresh_view": [][]string{
+		[]string{"REFRESH", "MATERIALIZED", "VIEW", "view_full_name"},
+		[]string{"REFRESH", "MATERIALIZED", "VIEW", "view_full_name", "<identifier>"},
+	},
+	"trigger_statement": [][]string{
+		[]string{"create_trigger"},
+		[]string{"drop_trigger"},
+	},
+	"create_trigger": [][]string{
+		[]string{"CREATE", "[replace]", "TRIGGER", "permitted_identifiers", "<identifier>", "trigger_event", "ON", "named_keyspace_ref", "FOR", "EACH", "<identifier>", "EXECUTE", "FUNCTION", "func_name", "LPAREN", "[exprs]", "RPAREN"},
+	},
+	"trigger_event": [][]string{
+		[]string{"INSERT"},
+		[]string{"UPDATE"},
+		[]string{"DELETE"},
+	},
+	"drop_trigger": [][]string{
+		[]string{"DROP", "TRIGGER", "permitted_identifiers", "ON", "named_keyspace_ref"},
+		[]string{"DROP", "TRIGGER", "IF", "EXISTS", "permitted_identifiers", "ON", "named_keyspace_ref"},
+	},
+	"job_statement": [][]string{
+		[]string{"create_job"},
+		[]string{"alter_job"},
+		[]string{"drop_job"},
+	},
+	"create_job": [][]string{
+		[]string{"CREATE", "[replace]", "JOB", "permitted_identifiers", "<identifier>", "<quoted string>", "[with_clause]", "AS", "statement"},
+	},
+	"alter_job": [][]string{
+		[]string{"ALTER", "JOB", "permitted_identifiers", "[job_schedule]", "[with_clause]"},
+		[]string{"ALTER", "JOB", "permitted_identifiers", "[job_schedule]", "[with_clause]", "AS", "statement"},
+	},
+	"[job_schedule]": [][]string{
+		[]string{"<identifier>", "<quoted string>"},
+	},
+	"drop_job": [][]string{
+		[]string{"DROP", "JOB", "permitted_identifiers"},
+		[]string{"DROP", "JOB", "IF", "EXISTS", "permitted_identifiers"},
+	},
+	"policy_statement": [][]string{
+		[]string{"create_policy"},
+		[]string{"drop_policy"},
+		[]string{"create_masking_policy"},
+		[]string{"drop_masking_policy"},
+	},
+	"create_policy": [][]string{
+		[]string{"CREATE", "[replace]", "POLICY", "permitted_identifiers", "ON", "named_keyspace_ref", "FOR", "policy_event", "TO", "policy_grantees", "USING", "LPAREN", "expression", "RPAREN"},
+	},
+	"policy_event": [][]string{
+		[]string{"SELECT"},
+		[]string{"UPDATE"},
+		[]string{"DELETE"},
+	},
+	"policy_grantees": [][]string{
+		[]string{"PUBLIC"},
+		[]string{"role_list"},
+	},
+	"drop_policy": [][]string{
+		[]string{"DROP", "POLICY", "permitted_identifiers", "ON", "named_keyspace_ref"},
+		[]string{"DROP", "POLICY", "IF", "EXISTS", "permitted_identifiers", "ON", "named_keyspace_ref"},
+	},
+	"create_masking_policy": [][]string{
+		[]string{"CREATE", "[replace]", "MASKING", "POLICY", "permitted_identifiers", "ON", "named_keyspace_ref", "LPAREN", "expression", "RPAREN", "USING", "LPAREN", "expression", "RPAREN", "[policy_exempt]"},
+	},
+	"[policy_exempt]": [][]string{
+		[]string{"EXEMPT", "ROLE", "role_list"},
+	},
+	"drop_masking_policy": [][]string{
+		[]string{"DROP", "MASKING", "POLICY", "permitted_identifiers", "ON", "named_keyspace_ref"},
+		[]string{"DROP", "MASKING", "POLICY", "IF", "EXISTS", "permitted_identifiers", "ON", "named_keyspace_ref"},
+	},
 	"credentialstore_statement": [][]string{
 		[]string{"create_credentialstore"},
 		[]string{"alter_credentialstore"},
@@ -1423,3 +1542,240 @@ var statement_syntax = map[string][][]string{
 		[]string{"DROP", "CREDENTIALSTORE", "[if_exists]", "perm_ident_or_str"},
 	},
 }
+
+var statement_keywords = []string{
+	"ADVISE",
+	"ALL",
+	"ALTER",
+	"ANALYZE",
+	"AND",
+	"ANY",
+	"ARRAY",
+	"AS",
+	"ASC",
+	"AT",
+	"BEGIN",
+	"BETWEEN",
+	"BINARY",
+	"BOOLEAN",
+	"BREAK",
+	"BUCKET",
+	"BUILD",
+	"BY",
+	"CACHE",
+	"CALL",
+	"CASE",
+	"CAST",
+	"CATALOG",
+	"CLUSTER",
+	"COLLATE",
+	"COLLECTION",
+	"COMMIT",
+	"COMMITTED",
+	"CONNECT",
+	"CONSUME",
+	"CONTINUE",
+	"CREATE",
+	"CREDENTIALSTORE",
+	"CURRENT",
+	"CYCLE",
+	"DATABASE",
+	"DATASET",
+	"DATASTORE",
+	"DECLARE",
+	"DECREMENT",
+	"DEFAULT",
+	"DELETE",
+	"DENSE",
+	"DERIVED",
+	"DESC",
+	"DESCRIBE",
+	"DISTINCT",
+	"DO",
+	"DROP",
+	"EACH",
+	"ELEMENT",
+	"ELSE",
+	"END",
+	"ESCAPE",
+	"EVERY",
+	"EXCEPT",
+	"EXCLUDE",
+	"EXECUTE",
+	"EXISTS",
+	"EXPLAIN",
+	"EXTERNAL",
+	"FALSE",
+	"FETCH",
+	"FILTER",
+	"FIRST",
+	"FLATTEN",
+	"FLATTEN_KEYS",
+	"FLUSH",
+	"FOLLOWING",
+	"FOR",
+	"FORCE",
+	"FROM",
+	"FTS",
+	"FUNCTION",
+	"GOLANG",
+	"GRANT",
+	"GROUP",
+	"GROUPS",
+	"GSI",
+	"HASH",
+	"HAVING",
+	"IF",
+	"IGNORE",
+	"ILIKE",
+	"IN",
+	"INCLUDE",
+	"INCREMENT",
+	"INDEX",
+	"INFER",
+	"INLINE",
+	"INNER",
+	"INSERT",
+	"INTERSECT",
+	"INTO",
+	"IS",
+	"ISOLATION",
+	"JAVASCRIPT",
+	"JOIN",
+	"KEY",
+	"KEYS",
+	"KEYSPACE",
+	"KNOWN",
+	"LANGUAGE",
+	"LAST",
+	"LATERAL",
+	"LEFT",
+	"LET",
+	"LETTING",
+	"LEVEL",
+	"LIKE",
+	"LIMIT",
+	"LSM",
+	"MAP",
+	"MAPPING",
+	"MATCHED",
+	"MATERIALIZED",
+	"MAXVALUE",
+	"MERGE",
+	"MINVALUE",
+	"MISSING",
+	"MULTI",
+	"NAMESPACE",
+	"NEST",
+	"NEXT",
+	"NEXTVAL",
+	"NL",
+	"NO",
+	"NOT",
+	"NTH_VALUE",
+	"NULL",
+	"NULLS",
+	"NUMBER",
+	"OBJECT",
+	"OFFSET",
+	"ON",
+	"OPTION",
+	"OPTIONS",
+	"OR",
+	"ORDER",
+	"OTHERS",
+	"OUTER",
+	"OVER",
+	"PARSE",
+	"PARTITION",
+	"PASSWORD",
+	"PATH",
+	"POOL",
+	"PRECEDING",
+	"PREPARE",
+	"PREV",
+	"PREVIOUS",
+	"PREVVAL",
+	"PRIMARY",
+	"PRIVATE",
+	"PRIVILEGE",
+	"PROBE",
+	"PROCEDURE",
+	"PUBLIC",
+	"RANGE",
+	"RAW",
+	"READ",
+	"REALM",
+	"RECURSIVE",
+	"REDUCE",
+	"RENAME",
+	"REPLACE",
+	"RESPECT",
+	"RESTART",
+	"RESTRICT",
+	"RETURN",
+	"RETURNING",
+	"REVOKE",
+	"RIGHT",
+	"ROLE",
+	"ROLES",
+	"ROLLBACK",
+	"ROW",
+	"ROWS",
+	"SATISFIES",
+	"SAVE",
+	"SAVEPOINT",
+	"SCHEMA",
+	"SCOPE",
+	"SELECT",
+	"SELF",
+	"SEQUENCE",
+	"SET",
+	"SHOW",
+	"SNAPSHOT",
+	"SOME",
+	"SOURCE",
+	"SPARSE",
+	"START",
+	"STATISTICS",
+	"STRING",
+	"SYSTEM",
+	"THEN",
+	"TIES",
+	"TIMESTAMP",
+	"TO",
+	"TRAN",
+	"TRANSACTION",
+	"TRIGGER",
+	"TRUE",
+	"TRUNCATE",
+	"TYPE",
+	"UNBOUNDED",
+	"UNDER",
+	"UNION",
+	"UNIQUE",
+	"UNKNOWN",
+	"UNNEST",
+	"UNSET",
+	"UPDATE",
+	"UPSERT",
+	"USE",
+	"USER",
+	"USERS",
+	"USING",
+	"VALIDATE",
+	"VALUE",
+	"VALUED",
+	"VALUES",
+	"VECTOR",
+	"VIA",
+	"VIEW",
+	"WHEN",
+	"WHERE",
+	"WHILE",
+	"WINDOW",
+	"WITH",
+	"WITHIN",
+	"WORK",
+	"XOR",
+}
@@ -22,7 +22,7 @@ func (this *Unalias) Name() string {
 }
 
 func (this *Unalias) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Unalias) MinArgs() int {
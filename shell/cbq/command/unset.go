@@ -26,7 +26,7 @@ func (this *Unset) Name() string {
 }
 
 func (this *Unset) CommandCompletion() bool {
-	return false
+	return true
 }
 
 func (this *Unset) MinArgs() int {
@@ -161,6 +161,7 @@ func HandleInteractiveMode(prompt string) {
 		return
 	}
 	liner.SetMultiLineMode(!viModeSingleLineFlag)
+	liner.SetWordCompleter(command.Complete)
 	liner.SetCommandCallback(func(args ...string) string {
 		var err_code errors.ErrorCode
 		var err_string string
@@ -135,3 +135,12 @@ func (s *State) SetCommandCallback(f func(...string) string) {
 		s.vi.SetCommandCallback(f)
 	}
 }
+
+func (s *State) SetWordCompleter(f pliner.WordCompleter) {
+	if !s.viMode {
+		s.orig.SetTabCompletionStyle(pliner.TabPrints)
+		s.orig.SetWordCompleter(f)
+	} else {
+		s.vi.SetWordCompleter(f)
+	}
+}
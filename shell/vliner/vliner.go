@@ -126,6 +126,7 @@ type State struct {
 	termLines       []termLine
 
 	commandCallback func(...string) string
+	completer       pliner.WordCompleter
 }
 
 type digraph struct {
@@ -727,6 +728,11 @@ func (s *State) SetCommandCallback(f func(...string) string) {
 	s.commandCallback = f
 }
 
+// completion is only available in insert mode; repeated TABs cycle through the candidates
+func (s *State) SetWordCompleter(f pliner.WordCompleter) {
+	s.completer = f
+}
+
 // these persist across invocations in contrast to shell-vi-mode equivalents in order to help with repeated statement invocations
 var fact, fr rune
 var curHist int = -1
@@ -3128,6 +3134,10 @@ func (s *State) inputText(prompt string, prefix []rune, suffix []rune, fixedSuff
 	pos := 0
 	i := 0
 
+	var completions []string
+	var compHead, compTail []rune
+	compNext := 0
+
 	done := -1
 	for done = -1; -1 == done; {
 		// It is simpler when dealing with multiple display character runes to draw the entire input & calculate the cursor
@@ -3146,8 +3156,29 @@ func (s *State) inputText(prompt string, prefix []rune, suffix []rune, fixedSuff
 		if nil != err {
 			return nil, -1
 		}
+		if _ASCII_TAB != r {
+			completions = nil
+		}
 		if _REPLAY_END == r {
 			continue
+		} else if _ASCII_TAB == r && nil != s.completer {
+			if nil == completions {
+				// the completer sees the whole line but only the input text can be replaced
+				head, c, tail := s.completer(string(prefix)+string(input), len(prefix)+pos)
+				h := []rune(head)
+				if 0 == len(c) || len(prefix) > len(h) {
+					s.alert()
+					continue
+				}
+				completions = c
+				compHead = h[len(prefix):]
+				compTail = []rune(tail)
+				compNext = 0
+			}
+			c := []rune(completions[compNext])
+			compNext = (compNext + 1) % len(completions)
+			input = append(append(append(make([]rune, 0, cap(input)), compHead...), c...), compTail...)
+			pos = len(compHead) + len(c)
 		} else if s.controlChars[ccVINTR] == r { // Ctrl+C typically
 			input = input[:0]
 			if s.interruptAborts {
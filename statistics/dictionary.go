@@ -0,0 +1,146 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+/*
+Package statistics keeps the optimizer statistics gathered by UPDATE STATISTICS
+on stores with no statistics service of their own (the file and mock datastores),
+and estimates selectivities from them.
+
+Statistics are held in memory, per keyspace, and are lost on restart.
+*/
+package statistics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/expression"
+)
+
+type keyspaceStats struct {
+	docCount   int64
+	avgDocSize int64
+	histograms map[string]*datastore.Histogram
+	updated    time.Time
+}
+
+var dictionary struct {
+	sync.RWMutex
+	keyspaces map[string]*keyspaceStats
+}
+
+func init() {
+	dictionary.keyspaces = make(map[string]*keyspaceStats)
+}
+
+// Number of documents in the keyspace when statistics were last gathered, -1 if none were
+func DocCount(keyspace string) int64 {
+	dictionary.RLock()
+	defer dictionary.RUnlock()
+	ks, ok := dictionary.keyspaces[keyspace]
+	if !ok {
+		return -1
+	}
+	return ks.docCount
+}
+
+// Whether statistics were gathered on any keyspace
+func Gathered() bool {
+	dictionary.RLock()
+	defer dictionary.RUnlock()
+	return len(dictionary.keyspaces) > 0
+}
+
+// Average document size in bytes, -1 if no statistics were gathered
+func AvgDocSize(keyspace string) int64 {
+	dictionary.RLock()
+	defer dictionary.RUnlock()
+	ks, ok := dictionary.keyspaces[keyspace]
+	if !ok {
+		return -1
+	}
+	return ks.avgDocSize
+}
+
+// The histogram on an expression, which is matched regardless of the keyspace alias used
+func GetHistogram(keyspace string, expr expression.Expression) *datastore.Histogram {
+	dictionary.RLock()
+	defer dictionary.RUnlock()
+	ks, ok := dictionary.keyspaces[keyspace]
+	if !ok {
+		return nil
+	}
+	return ks.histograms[Key(expr)]
+}
+
+func setKeyspaceStats(keyspace string, docCount, avgDocSize int64, histograms []*datastore.Histogram) {
+	dictionary.Lock()
+	defer dictionary.Unlock()
+	ks, ok := dictionary.keyspaces[keyspace]
+	if !ok {
+		ks = &keyspaceStats{histograms: make(map[string]*datastore.Histogram, len(histograms))}
+		dictionary.keyspaces[keyspace] = ks
+	}
+	ks.docCount = docCount
+	ks.avgDocSize = avgDocSize
+	ks.updated = time.Now()
+	for _, h := range histograms {
+		ks.histograms[Key(h.Key())] = h
+	}
+}
+
+// Drops the histograms on the given expressions, or all statistics for the keyspace if none are given
+func deleteKeyspaceStats(keyspace string, terms expression.Expressions) {
+	dictionary.Lock()
+	defer dictionary.Unlock()
+	if len(terms) == 0 {
+		delete(dictionary.keyspaces, keyspace)
+		return
+	}
+	ks, ok := dictionary.keyspaces[keyspace]
+	if !ok {
+		return
+	}
+	for _, t := range terms {
+		delete(ks.histograms, Key(t))
+	}
+}
+
+const _STAT_ALIAS = "$stat"
+
+/*
+The dictionary key of an expression: its text once the keyspace alias is
+replaced by a fixed name, so that statistics gathered on `orders`.`price`
+are found for `o`.`price`.
+*/
+func Key(expr expression.Expression) string {
+	return statExpr(expr).String()
+}
+
+func statExpr(expr expression.Expression) expression.Expression {
+	rv := &aliasRenamer{}
+	rv.SetMapFunc(func(expr expression.Expression) (expression.Expression, error) {
+		if id, ok := expr.(*expression.Identifier); ok && id.IsKeyspaceAlias() {
+			ident := expression.NewIdentifier(_STAT_ALIAS)
+			ident.SetKeyspaceAlias(true)
+			return ident, nil
+		}
+		return expr, expr.MapChildren(rv)
+	})
+	rv.SetMapper(rv)
+	mapped, err := rv.Map(expr.Copy())
+	if err != nil {
+		return expr
+	}
+	return mapped
+}
+
+type aliasRenamer struct {
+	expression.MapperBase
+}
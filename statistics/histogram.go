@@ -0,0 +1,230 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package statistics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+// Resolution is the percentage of documents each histogram bin holds
+const (
+	DEF_RESOLUTION = 1.0
+	MIN_RESOLUTION = 0.02
+	MAX_RESOLUTION = 5.0
+)
+
+/*
+Builds an equi-depth histogram from the values an expression takes in
+sampleSize documents, documents where it is MISSING being left out of
+values. A value found in more than one bin's worth of documents gets an
+overflow bin of its own, and the remaining values, in collation order,
+are split into bins of roughly equal size, never across equal values.
+
+Bin sizes and distinct counts are fractions of sampleSize.
+*/
+func buildHistogram(keyspace string, key expression.Expression, docCount, sampleSize int64,
+	values value.Values, resolution float64) *datastore.Histogram {
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].Collate(values[j]) < 0
+	})
+
+	n := float64(sampleSize)
+	binSize := int(math.Ceil(n * resolution / 100.0))
+	if binSize < 1 {
+		binSize = 1
+	}
+
+	var distrib datastore.DistBins
+	var ovrflow datastore.OverflowBins
+	var max value.Value
+	ndv, count, distinct := 0, 0, 0
+	for i := 0; i < len(values); {
+		j := i + 1
+		for j < len(values) && values[j].Collate(values[i]) == 0 {
+			j++
+		}
+		freq := j - i
+		ndv++
+		if freq > binSize {
+			ovrflow = append(ovrflow, datastore.NewOverflowBin(float64(freq)/n, values[i]))
+		} else {
+			count += freq
+			distinct++
+			max = values[i]
+			if count >= binSize {
+				distrib = append(distrib, datastore.NewDistBin(float64(count)/n, float64(distinct)/n, max))
+				count, distinct = 0, 0
+			}
+		}
+		i = j
+	}
+	if count > 0 {
+		distrib = append(distrib, datastore.NewDistBin(float64(count)/n, float64(distinct)/n, max))
+	}
+
+	fdistincts := 0.0
+	if n > 0 {
+		fdistincts = float64(ndv) / n
+	}
+
+	rv := &datastore.Histogram{}
+	rv.SetHistogram(datastore.HISTOGRAM_VERSION, keyspace, key, docCount, sampleSize, resolution,
+		fdistincts, 0.0, 0.0, 0.0, distrib, ovrflow, time.Now())
+	return rv
+}
+
+// Number of distinct values, MISSING aside
+func NDV(h *datastore.Histogram) float64 {
+	return math.Max(math.Round(h.Fdistincts()*float64(h.SampleSize())), 1.0)
+}
+
+// Fraction of documents in which the expression is neither MISSING nor NULL
+func ValuedSelec(h *datastore.Histogram) float64 {
+	return RangeSelec(h, value.NULL_VALUE, nil, false, false)
+}
+
+// Fraction of documents in which the expression is NULL
+func NullSelec(h *datastore.Histogram) float64 {
+	return EqSelec(h, value.NULL_VALUE)
+}
+
+// Fraction of documents in which the expression is MISSING
+func MissingSelec(h *datastore.Histogram) float64 {
+	return math.Max(1.0-RangeSelec(h, nil, nil, false, false), 0.0)
+}
+
+// Fraction of documents in which the expression equals v
+func EqSelec(h *datastore.Histogram, v value.Value) float64 {
+	for _, o := range h.Ovrflow() {
+		if o.Val().Collate(v) == 0 {
+			return o.Size()
+		}
+	}
+	n := float64(h.SampleSize())
+	for _, b := range h.Distrib() {
+		if v.Collate(b.Max()) <= 0 {
+			return math.Max(b.Size()/(b.Distinct()*n), minSelec(h))
+		}
+	}
+	return minSelec(h)
+}
+
+/*
+Fraction of documents in which the expression lies between low and high,
+a nil bound being open. Bins straddling a bound are interpolated when
+both the bin and the bound are numbers, and count for half otherwise.
+*/
+func RangeSelec(h *datastore.Histogram, low, high value.Value, lowIncl, highIncl bool) float64 {
+	sel := 0.0
+	for _, o := range h.Ovrflow() {
+		if inRange(o.Val(), low, high, lowIncl, highIncl) {
+			sel += o.Size()
+		}
+	}
+
+	// each bin holds the values above the previous bin's maximum, up to its own
+	var prev value.Value
+	for _, b := range h.Distrib() {
+		sel += b.Size() * binFraction(prev, b.Max(), low, high, lowIncl, highIncl)
+		prev = b.Max()
+	}
+	return math.Min(sel, 1.0)
+}
+
+/*
+The range of values of the same type as v, for comparisons that only
+hold between values of the same type.
+*/
+func TypeRange(v value.Value) (low, high value.Value, lowIncl, highIncl, ok bool) {
+	switch v.Type() {
+	case value.BOOLEAN:
+		return value.FALSE_VALUE, value.TRUE_VALUE, true, true, true
+	case value.NUMBER:
+		return value.TRUE_VALUE, value.EMPTY_STRING_VALUE, false, false, true
+	case value.STRING:
+		return value.EMPTY_STRING_VALUE, value.EMPTY_ARRAY_VALUE, true, false, true
+	case value.ARRAY:
+		return value.EMPTY_ARRAY_VALUE, _EMPTY_OBJECT, true, false, true
+	}
+	return nil, nil, false, false, false
+}
+
+var _EMPTY_OBJECT = value.NewValue(map[string]interface{}{})
+
+func minSelec(h *datastore.Histogram) float64 {
+	if h.SampleSize() <= 0 {
+		return 0.0
+	}
+	return 1.0 / float64(h.SampleSize())
+}
+
+func inRange(v, low, high value.Value, lowIncl, highIncl bool) bool {
+	if low != nil {
+		c := v.Collate(low)
+		if c < 0 || (c == 0 && !lowIncl) {
+			return false
+		}
+	}
+	if high != nil {
+		c := v.Collate(high)
+		if c > 0 || (c == 0 && !highIncl) {
+			return false
+		}
+	}
+	return true
+}
+
+// Fraction of the bin (lo, hi] that lies within the range
+func binFraction(lo, hi, low, high value.Value, lowIncl, highIncl bool) float64 {
+	if high != nil && lo != nil && lo.Collate(high) >= 0 {
+		return 0.0
+	}
+	if low != nil {
+		c := hi.Collate(low)
+		if c < 0 || (c == 0 && !lowIncl) {
+			return 0.0
+		}
+	}
+
+	lowIn := low == nil || (lo != nil && lo.Collate(low) >= 0)
+	highIn := high == nil || inRange(hi, nil, high, false, highIncl)
+	if lowIn && highIn {
+		return 1.0
+	}
+
+	if lo == nil || lo.Type() != value.NUMBER || hi.Type() != value.NUMBER {
+		return 0.5
+	}
+	from := value.AsNumberValue(lo).Float64()
+	to := value.AsNumberValue(hi).Float64()
+	if to <= from {
+		return 0.5
+	}
+	a, b := from, to
+	if !lowIn {
+		if low.Type() != value.NUMBER {
+			return 0.5
+		}
+		a = value.AsNumberValue(low).Float64()
+	}
+	if !highIn {
+		if high.Type() != value.NUMBER {
+			return 0.5
+		}
+		b = value.AsNumberValue(high).Float64()
+	}
+	return math.Min(math.Max((b-a)/(to-from), 0.0), 1.0)
+}
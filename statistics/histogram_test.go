@@ -0,0 +1,49 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package statistics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+func testSelec(name string, sel, expected float64, t *testing.T) {
+	if math.Abs(sel-expected) > 1e-9 {
+		t.Errorf("%s: mismatch received %v expected %v", name, sel, expected)
+	}
+}
+
+// 110 documents: 0 to 89 once each, 100 ten times, and ten without the field.
+// At a resolution of 5% a bin holds 6 documents, so 100 gets an overflow bin
+// and 0 to 89 are split into 15 bins.
+func TestHistogram(t *testing.T) {
+	values := make(value.Values, 0, 100)
+	for i := 10; i > 0; i-- {
+		values = append(values, value.NewValue(100))
+	}
+	for i := 89; i >= 0; i-- {
+		values = append(values, value.NewValue(i))
+	}
+	h := buildHistogram("default:ks", expression.NewIdentifier("f"), 110, 110, values, 5.0)
+
+	if len(h.Ovrflow()) != 1 || len(h.Distrib()) != 15 {
+		t.Fatalf("received %d overflow bins and %d bins, expected 1 and 15", len(h.Ovrflow()), len(h.Distrib()))
+	}
+	testSelec("NDV", NDV(h), 91, t)
+	testSelec("EqSelec(100)", EqSelec(h, value.NewValue(100)), 10.0/110.0, t)
+	testSelec("EqSelec(5)", EqSelec(h, value.NewValue(5)), 1.0/110.0, t)
+	testSelec("MissingSelec", MissingSelec(h), 10.0/110.0, t)
+	testSelec("NullSelec", NullSelec(h), 1.0/110.0, t)
+	testSelec("RangeSelec(<=44)", RangeSelec(h, nil, value.NewValue(44), false, true), 45.0/110.0, t)
+	testSelec("RangeSelec(<=47)", RangeSelec(h, nil, value.NewValue(47), false, true), 48.0/110.0, t)
+	testSelec("RangeSelec(>89)", RangeSelec(h, value.NewValue(89), nil, false, false), 10.0/110.0, t)
+}
@@ -0,0 +1,232 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package statistics
+
+import (
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
+)
+
+const _FETCH_BATCH = 512
+
+/*
+StatUpdater for datastores that keep no statistics of their own. It scans
+the keyspace through its primary index, in index order, so that the same
+documents always yield the same histograms.
+*/
+type statUpdater struct {
+}
+
+func NewStatUpdater() datastore.StatUpdater {
+	return &statUpdater{}
+}
+
+func (this *statUpdater) Name() datastore.StatUpdaterType {
+	return datastore.UPDSTAT_DEFAULT
+}
+
+func (this *statUpdater) UpdateStatistics(ks datastore.Keyspace, indexes []datastore.Index, terms expression.Expressions,
+	with value.Value, conn *datastore.ValueConnection, exContext interface{}, internal bool, inAus bool) {
+
+	defer close(conn.ValueChannel())
+
+	context, ok := exContext.(interface {
+		datastore.QueryContext
+		datastore.Context
+		expression.Context
+	})
+	if !ok {
+		conn.Error(errors.NewUpdateStatisticsError("invalid context", nil))
+		return
+	}
+
+	resolution, sampleSize, err := parseWith(with)
+	if err != nil {
+		conn.Error(err)
+		return
+	}
+
+	terms, err = indexTerms(ks, indexes, terms)
+	if err != nil {
+		conn.Error(err)
+		return
+	}
+
+	keys, err := scanKeys(ks, sampleSize, context)
+	if err != nil {
+		conn.Error(errors.NewUpdateStatisticsError("error scanning keyspace "+ks.QualifiedName(), err))
+		return
+	}
+
+	exprs := make(expression.Expressions, len(terms))
+	for i, t := range terms {
+		exprs[i] = statExpr(t)
+	}
+	values := make([]value.Values, len(terms))
+	var totalSize int64
+	for i := 0; i < len(keys); i += _FETCH_BATCH {
+		j := i + _FETCH_BATCH
+		if j > len(keys) {
+			j = len(keys)
+		}
+		docs := make(map[string]value.AnnotatedValue, j-i)
+		errs := ks.Fetch(keys[i:j], docs, context, nil, nil, false)
+		if len(errs) > 0 {
+			conn.Error(errors.NewUpdateStatisticsError("error fetching documents from "+ks.QualifiedName(), errs[0]))
+			return
+		}
+		for _, k := range keys[i:j] {
+			doc, ok := docs[k]
+			if !ok || doc == nil {
+				continue
+			}
+			totalSize += int64(doc.Size())
+			item := value.NewAnnotatedValue(make(map[string]interface{}, 1))
+			item.SetField(_STAT_ALIAS, doc)
+			for n, expr := range exprs {
+				v, e := expr.Evaluate(item, context)
+				if e != nil {
+					conn.Error(errors.NewUpdateStatisticsError("error evaluating "+terms[n].String(), e))
+					return
+				}
+				if v.Type() != value.MISSING {
+					values[n] = append(values[n], v)
+				}
+			}
+		}
+	}
+
+	docCount, err := ks.Count(context)
+	if err != nil {
+		conn.Error(err)
+		return
+	}
+	sampled := int64(len(keys))
+	avgDocSize := int64(0)
+	if sampled > 0 {
+		avgDocSize = totalSize / sampled
+	}
+
+	histograms := make([]*datastore.Histogram, len(terms))
+	for i, t := range terms {
+		histograms[i] = buildHistogram(ks.QualifiedName(), t, docCount, sampled, values[i], resolution)
+	}
+	setKeyspaceStats(ks.QualifiedName(), docCount, avgDocSize, histograms)
+}
+
+func (this *statUpdater) DeleteStatistics(ks datastore.Keyspace, terms expression.Expressions,
+	conn *datastore.ValueConnection, exContext interface{}) {
+
+	defer close(conn.ValueChannel())
+	deleteKeyspaceStats(ks.QualifiedName(), terms)
+}
+
+func parseWith(with value.Value) (resolution float64, sampleSize int64, err errors.Error) {
+	resolution = DEF_RESOLUTION
+	if with == nil {
+		return
+	}
+	if with.Type() != value.OBJECT {
+		return 0.0, 0, errors.NewWithInvalidValueError("with", "must be an object")
+	}
+	for name, v := range with.Fields() {
+		val := value.NewValue(v)
+		switch name {
+		case "resolution":
+			if val.Type() != value.NUMBER {
+				return 0.0, 0, errors.NewWithInvalidValueError(name, "must be a number")
+			}
+			resolution = value.AsNumberValue(val).Float64()
+			if resolution < MIN_RESOLUTION || resolution > MAX_RESOLUTION {
+				return 0.0, 0, errors.NewWithInvalidValueError(name, "must be between 0.02 and 5.0")
+			}
+		case "sample_size":
+			if val.Type() != value.NUMBER {
+				return 0.0, 0, errors.NewWithInvalidValueError(name, "must be a number")
+			}
+			sampleSize = value.AsNumberValue(val).Int64()
+			if sampleSize < 0 {
+				return 0.0, 0, errors.NewWithInvalidValueError(name, "must not be negative")
+			}
+		default:
+			return 0.0, 0, errors.NewWithInvalidOptionError(name)
+		}
+	}
+	return
+}
+
+// With indexes, statistics are gathered on their keys rather than on terms
+func indexTerms(ks datastore.Keyspace, indexes []datastore.Index, terms expression.Expressions) (
+	expression.Expressions, errors.Error) {
+
+	if len(indexes) == 0 {
+		return terms, nil
+	}
+	rv := make(expression.Expressions, 0, len(indexes))
+	for _, index := range indexes {
+		for _, key := range index.RangeKey() {
+			formalizer := expression.NewKeyspaceFormalizer(ks.Name(), nil)
+			key, err := formalizer.Map(key.Copy())
+			if err != nil {
+				return nil, errors.NewUpdateStatisticsError("invalid key in index "+index.Name(), err)
+			}
+			found := false
+			for _, t := range rv {
+				if t.EquivalentTo(key) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				rv = append(rv, key)
+			}
+		}
+	}
+	return rv, nil
+}
+
+func scanKeys(ks datastore.Keyspace, limit int64, context interface {
+	datastore.QueryContext
+	datastore.Context
+}) ([]string, errors.Error) {
+
+	indexer, err := ks.Indexer(datastore.DEFAULT)
+	if err != nil {
+		return nil, err
+	}
+	primaries, err := indexer.PrimaryIndexes()
+	if err != nil {
+		return nil, err
+	} else if len(primaries) == 0 {
+		return nil, errors.NewUpdateStatisticsError("no primary index on "+ks.QualifiedName(), nil)
+	}
+
+	iconn := datastore.NewIndexConnection(context)
+	defer iconn.Dispose()
+	go primaries[0].ScanEntries(context.RequestId(), limit, datastore.UNBOUNDED, nil, iconn)
+
+	var keys []string
+	for {
+		entry, ok := iconn.Sender().GetEntry()
+		if !ok || entry == nil {
+			break
+		}
+		keys = append(keys, entry.PrimaryKey)
+		if limit > 0 && int64(len(keys)) >= limit {
+			iconn.SendStop()
+			break
+		}
+	}
+	if errs := iconn.GetErrors(); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return keys, nil
+}
@@ -10,7 +10,7 @@ package transactions
 
 func IsValidStatement(txId, stmtType string, tximplicit, allow bool) (bool, string) {
 	switch stmtType {
-	case "SELECT", "UPDATE", "INSERT", "UPSERT", "DELETE", "MERGE":
+	case "SELECT", "UPDATE", "INSERT", "UPSERT", "DELETE", "MERGE", "TRUNCATE":
 		return true, ""
 	case "EXECUTE", "PREPARE":
 		return true, ""
@@ -0,0 +1,547 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+/*
+Package triggers stores the DML triggers defined on collections in the
+bucket's system collection, alongside UDFs, sequences and views. All the
+triggers of a collection are kept in a single document, so that the send
+operators can find them with one fetch, and each node caches them until
+a change is announced through a metakv revision counter.
+
+A trigger is kept as the text of its CREATE TRIGGER statement together
+with the query context it was created under, which the send operators
+parse to find the function to execute and its arguments.
+*/
+package triggers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/couchbase/cbauth/metakv"
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/distributed"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/value"
+)
+
+const _TRIGGER = "trigger::"
+const _BATCH_SIZE = 512
+const _MAX_RETRIES = 5
+const _CACHE_REVISION_PATH = "/query/triggers_cache/"
+const _CACHE_REVISION = _CACHE_REVISION_PATH + "revision"
+
+// When a trigger fires
+const (
+	BEFORE = "before"
+	AFTER  = "after"
+)
+
+// What a trigger fires on
+const (
+	INSERT = "insert"
+	UPDATE = "update"
+	DELETE = "delete"
+)
+
+// A stored trigger definition
+type Trigger struct {
+	Name         string
+	Keyspace     string
+	Timing       string
+	Event        string
+	Text         string
+	QueryContext string
+
+	parseOnce  sync.Once
+	definition interface{}
+	parseErr   error
+}
+
+func (this *Trigger) FullName() string {
+	return FullName(this.Name, this.Keyspace)
+}
+
+/*
+The parsed definition of the trigger. Parsing is left to the caller, as
+this package sits below the parser, and is only done once per cached
+definition.
+*/
+func (this *Trigger) Definition(parse func(*Trigger) (interface{}, error)) (interface{}, error) {
+	this.parseOnce.Do(func() {
+		this.definition, this.parseErr = parse(this)
+	})
+	return this.definition, this.parseErr
+}
+
+func FullName(name string, keyspace string) string {
+	return name + " on " + keyspace
+}
+
+type cacheEntry struct {
+	triggers []*Trigger
+	rev      int32
+}
+
+var cache struct {
+	sync.RWMutex
+	keyspaces map[string]*cacheEntry
+}
+
+var cacheRevision int32 = 1
+
+func init() {
+	cache.keyspaces = make(map[string]*cacheEntry)
+	err := metakv.Add(_CACHE_REVISION, fmtCacheRevision())
+	if err != nil && err != metakv.ErrRevMismatch {
+		logging.Warnf("Unable to start triggers cache monitor: %v", err)
+	}
+	go metakv.RunObserveChildren(_CACHE_REVISION_PATH, triggerChangeMonitor, make(chan struct{}))
+}
+
+func triggerChangeMonitor(kve metakv.KVEntry) error {
+	if kve.Path != _CACHE_REVISION {
+		return nil
+	}
+	node, _ := distributed.RemoteAccess().SplitKey(string(kve.Value))
+	if node == "" || node != distributed.RemoteAccess().WhoAmI() {
+		atomic.AddInt32(&cacheRevision, 1)
+	}
+	return nil
+}
+
+func nextRevision() {
+	atomic.AddInt32(&cacheRevision, 1)
+	err := metakv.Set(_CACHE_REVISION, fmtCacheRevision(), nil)
+	if err != nil && err.Error() == "Not found" {
+		err = metakv.Add(_CACHE_REVISION, fmtCacheRevision())
+	}
+	if err != nil {
+		logging.Infof("Unable to update triggers cache monitor %v", errors.NewMetaKVChangeCounterError(err))
+	}
+}
+
+func fmtCacheRevision() []byte {
+	return []byte(distributed.RemoteAccess().MakeKey(distributed.RemoteAccess().WhoAmI(),
+		strconv.Itoa(int(atomic.LoadInt32(&cacheRevision)))))
+}
+
+// The collection underlying a keyspace, which for a bucket is its default collection
+func collectionPath(ks datastore.Keyspace) *algebra.Path {
+	elements := algebra.ParsePath(ks.QualifiedName())
+	if len(elements) != 4 || elements[0] == datastore.SYSTEM_NAMESPACE {
+		return nil
+	}
+	return algebra.NewPathFromElements(elements)
+}
+
+func getStorageKey(uid string, scope string, collection string) string {
+	return _TRIGGER + uid + "::" + scope + "." + collection
+}
+
+func getCacheKey(namespace string, bucket string, key string) string {
+	return namespace + ":" + bucket + "." + trimPrefixAndScopeUid(key)
+}
+
+func trimPrefixAndScopeUid(key string) string {
+	if len(key) > len(_TRIGGER)+10 && strings.HasPrefix(key, _TRIGGER) {
+		key = key[len(_TRIGGER)+10:]
+	}
+	return key
+}
+
+func getSystemCollection(bucket string) (datastore.Keyspace, errors.Error) {
+	store := datastore.GetDatastore()
+	if store == nil {
+		return nil, errors.NewNoDatastoreError()
+	}
+	ks, err := store.GetSystemCollection(bucket)
+	if err == nil && ks == nil {
+		err = errors.NewTriggerError(errors.E_TRIGGER_NOT_ENABLED, bucket, err)
+	} else if err != nil && err.Code() == errors.E_CB_SCOPE_NOT_FOUND {
+		err = errors.NewTriggerError(errors.E_TRIGGER_NOT_ENABLED, bucket, nil)
+	}
+	return ks, err
+}
+
+// Fetches the document holding the triggers of a collection, or nil if there is none
+func fetchTriggers(sys datastore.Keyspace, key string) (value.AnnotatedValue, errors.Error) {
+	res := make(map[string]value.AnnotatedValue, 1)
+	errs := sys.Fetch([]string{key}, res, datastore.NULL_QUERY_CONTEXT, nil, nil, false)
+	if len(errs) > 0 {
+		if !errors.IsNotFoundError("", errs[0]) && !errs[0].HasCause(errors.E_CB_BULK_GET) {
+			return nil, errs[0]
+		}
+		return nil, nil
+	}
+	return res[key], nil
+}
+
+func decodeTriggers(av value.AnnotatedValue, keyspace string) []*Trigger {
+	if av == nil {
+		return nil
+	}
+	list, ok := av.Field("triggers")
+	if !ok || list.Type() != value.ARRAY {
+		return nil
+	}
+	entries := list.Actual().([]interface{})
+	rv := make([]*Trigger, 0, len(entries))
+	for _, e := range entries {
+		entry := value.NewValue(e)
+		t := &Trigger{Keyspace: keyspace}
+		for f, s := range map[string]*string{"name": &t.Name, "timing": &t.Timing, "event": &t.Event,
+			"text": &t.Text, "query_context": &t.QueryContext} {
+			if v, ok := entry.Field(f); ok && v.Type() == value.STRING {
+				*s = v.ToString()
+			}
+		}
+		rv = append(rv, t)
+	}
+	return rv
+}
+
+func encodeTriggers(keyspace string, list []*Trigger) value.Value {
+	entries := make([]interface{}, len(list))
+	for i, t := range list {
+		entry := map[string]interface{}{
+			"name":   t.Name,
+			"timing": t.Timing,
+			"event":  t.Event,
+			"text":   t.Text,
+		}
+		if t.QueryContext != "" {
+			entry["query_context"] = t.QueryContext
+		}
+		entries[i] = entry
+	}
+	return value.NewValue(map[string]interface{}{
+		"keyspace": keyspace,
+		"triggers": entries,
+	})
+}
+
+/*
+Applies a change to the triggers of a collection. The document is updated
+with the CAS it was read with, and the change retried if another node got
+there first.
+*/
+func modifyTriggers(ks datastore.Keyspace,
+	change func(list []*Trigger) ([]*Trigger, errors.Error)) errors.Error {
+
+	path := collectionPath(ks)
+	if path == nil {
+		return errors.NewTriggerError(errors.E_TRIGGER_INVALID_KEYSPACE, ks.QualifiedName())
+	}
+	uid, err := datastore.GetScopeUid(path.Namespace(), path.Bucket(), path.Scope())
+	if err != nil {
+		return err
+	}
+	sys, err := getSystemCollection(path.Bucket())
+	if err != nil {
+		return err
+	}
+	if sys.ScopeId() == path.Scope() {
+		return errors.NewTriggerError(errors.E_TRIGGER_INVALID_KEYSPACE, path.SimpleString())
+	}
+
+	key := getStorageKey(uid, path.Scope(), path.Keyspace())
+	for i := 0; ; i++ {
+		av, err := fetchTriggers(sys, key)
+		if err != nil {
+			return err
+		}
+		list, err := change(decodeTriggers(av, path.SimpleString()))
+		if err != nil {
+			return err
+		}
+
+		pairs := make([]value.Pair, 1)
+		pairs[0].Name = key
+		nv := value.NewAnnotatedValue(encodeTriggers(path.Keyspace(), list))
+		var errs errors.Errors
+		if av == nil {
+			pairs[0].Value = nv
+			_, _, errs = sys.Insert(pairs, datastore.GetDurableQueryContextFor(sys), true)
+		} else {
+			nv.CopyAnnotations(av)
+			pairs[0].Value = nv
+			_, _, errs = sys.Update(pairs, datastore.GetDurableQueryContextFor(sys), true)
+		}
+		if len(errs) == 0 {
+			break
+		}
+		if i < _MAX_RETRIES && (errs[0].HasCause(errors.E_DUPLICATE_KEY) || errs[0].HasCause(errors.E_CAS_MISMATCH)) {
+			continue
+		}
+		return errs[0]
+	}
+	nextRevision()
+	return nil
+}
+
+func CreateTrigger(ks datastore.Keyspace, name string, timing string, event string, text string,
+	queryContext string, replace bool) errors.Error {
+
+	fullName := FullName(name, ks.QualifiedName())
+	err := modifyTriggers(ks, func(list []*Trigger) ([]*Trigger, errors.Error) {
+		t := &Trigger{Name: name, Timing: timing, Event: event, Text: text, QueryContext: queryContext}
+		for i := range list {
+			if list[i].Name == name {
+				if !replace {
+					return nil, errors.NewTriggerError(errors.E_TRIGGER_ALREADY_EXISTS, fullName)
+				}
+				list[i] = t
+				return list, nil
+			}
+		}
+		return append(list, t), nil
+	})
+	if err != nil && err.Code() != errors.E_TRIGGER_ALREADY_EXISTS && err.Code() != errors.E_TRIGGER_INVALID_KEYSPACE {
+		return errors.NewTriggerError(errors.E_TRIGGER_CREATE, fullName, err)
+	}
+	return err
+}
+
+func DropTrigger(ks datastore.Keyspace, name string, failIfNotExists bool) errors.Error {
+	fullName := FullName(name, ks.QualifiedName())
+	err := modifyTriggers(ks, func(list []*Trigger) ([]*Trigger, errors.Error) {
+		for i := range list {
+			if list[i].Name == name {
+				return append(list[:i], list[i+1:]...), nil
+			}
+		}
+		return nil, errors.NewTriggerError(errors.E_TRIGGER_NOT_FOUND, fullName)
+	})
+	if err != nil {
+		switch err.Code() {
+		case errors.E_TRIGGER_NOT_FOUND:
+			if !failIfNotExists {
+				return nil
+			}
+		case errors.E_TRIGGER_INVALID_KEYSPACE:
+		default:
+			return errors.NewTriggerError(errors.E_TRIGGER_DROP, fullName, err)
+		}
+	}
+	return err
+}
+
+/*
+Returns the triggers defined on a keyspace that fire on an event, in order
+of name. Keyspaces other than collections, or buckets used as their
+default collection, never have triggers.
+*/
+func KeyspaceTriggers(ks datastore.Keyspace, event string) ([]*Trigger, errors.Error) {
+	path := collectionPath(ks)
+	if path == nil {
+		return nil, nil
+	}
+	name := path.SimpleString()
+	rev := atomic.LoadInt32(&cacheRevision)
+
+	cache.RLock()
+	entry, ok := cache.keyspaces[name]
+	cache.RUnlock()
+	if !ok || entry.rev != rev {
+		list, err := loadTriggers(path)
+		if err != nil {
+			return nil, err
+		}
+		entry = &cacheEntry{triggers: list, rev: rev}
+		cache.Lock()
+		cache.keyspaces[name] = entry
+		cache.Unlock()
+	}
+
+	var rv []*Trigger
+	for _, t := range entry.triggers {
+		if t.Event == event {
+			rv = append(rv, t)
+		}
+	}
+	return rv, nil
+}
+
+func loadTriggers(path *algebra.Path) ([]*Trigger, errors.Error) {
+	sys, err := datastore.GetDatastore().GetSystemCollection(path.Bucket())
+	if err != nil {
+		if err.Code() == errors.E_CB_SCOPE_NOT_FOUND || errors.IsNotFoundError("", err) {
+			// no system collection, no triggers
+			return nil, nil
+		}
+		return nil, err
+	} else if sys == nil || sys.ScopeId() == path.Scope() {
+		return nil, nil
+	}
+	uid, err := datastore.GetScopeUid(path.Namespace(), path.Bucket(), path.Scope())
+	if err != nil {
+		return nil, err
+	}
+	av, err := fetchTriggers(sys, getStorageKey(uid, path.Scope(), path.Keyspace()))
+	if err != nil {
+		return nil, err
+	}
+	list := decodeTriggers(av, path.SimpleString())
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Lists the full names of the triggers defined on the collections of a scope
+func ListTriggerKeys(namespace string, bucket string, scope string, limit int64) ([]string, errors.Error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	res := make([]string, 0, 32)
+	uid, err := datastore.GetScopeUid(namespace, bucket, scope)
+	if err != nil {
+		return res, nil
+	}
+	prefix := _TRIGGER + uid + "::" + scope + "."
+
+	datastore.ScanSystemCollection(bucket, prefix,
+		func(systemCollection datastore.Keyspace) errors.Error {
+			if systemCollection.ScopeId() == scope {
+				// there are no triggers in the _system scope
+				return errors.NewTriggerError(errors.E_TRIGGER_NOT_FOUND, scope) // will just stop the scan
+			}
+			return nil
+		},
+		func(key string, systemCollection datastore.Keyspace) errors.Error {
+			av, err := fetchTriggers(systemCollection, key)
+			if err != nil {
+				return nil
+			}
+			for _, t := range decodeTriggers(av, "") {
+				if limit <= 0 {
+					return errors.NewTriggerError(errors.E_TRIGGER_NOT_FOUND, key) // will just stop the scan
+				}
+				res = append(res, getCacheKey(namespace, bucket, key)+"."+t.Name)
+				limit--
+			}
+			return nil
+		}, nil)
+
+	return res, nil
+}
+
+// Returns the system:triggers entry for the trigger
+func FetchTrigger(name string) (value.AnnotatedValue, errors.Error) {
+	var elements []string
+	if i := strings.IndexByte(name, ':'); i > 0 {
+		elements = append([]string{name[:i]}, strings.SplitN(name[i+1:], ".", 4)...)
+	}
+	if len(elements) != 5 {
+		return nil, errors.NewTriggerError(errors.E_TRIGGER_NOT_FOUND, name)
+	}
+	path := algebra.NewPathLong(elements[0], elements[1], elements[2], elements[3])
+	uid, err := datastore.GetScopeUid(elements[0], elements[1], elements[2])
+	if err != nil {
+		return nil, nil
+	}
+	sys, err := getSystemCollection(elements[1])
+	if err != nil {
+		return nil, nil
+	}
+	av, err := fetchTriggers(sys, getStorageKey(uid, elements[2], elements[3]))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range decodeTriggers(av, path.SimpleString()) {
+		if t.Name != elements[4] {
+			continue
+		}
+		m := make(map[string]interface{})
+		m["namespace"] = elements[0]
+		m["namespace_id"] = elements[0]
+		m["bucket"] = elements[1]
+		m["scope_id"] = elements[2]
+		m["keyspace_id"] = elements[3]
+		m["name"] = t.Name
+		m["path"] = path.ProtectedString()
+		m["timing"] = strings.ToUpper(t.Timing)
+		m["event"] = strings.ToUpper(t.Event)
+		m["definition"] = t.Text
+		if t.QueryContext != "" {
+			m["query_context"] = t.QueryContext
+		}
+		return value.NewAnnotatedValue(value.NewValue(m)), nil
+	}
+	return nil, nil
+}
+
+// Removes the triggers of a collection that has been dropped
+func DropKeyspaceTriggers(namespace string, bucket string, scope string, uid string, collection string) errors.Error {
+	sys, err := getSystemCollection(bucket)
+	if err != nil {
+		return err
+	}
+	pairs := make([]value.Pair, 1)
+	pairs[0].Name = getStorageKey(uid, scope, collection)
+	_, _, errs := sys.Delete(pairs, datastore.GetDurableQueryContextFor(sys), false)
+	if len(errs) > 0 && !errors.IsNotFoundError("", errs[0]) {
+		return errors.NewTriggerError(errors.E_TRIGGER_DROP_ALL, bucket+"."+scope+"."+collection+".*", errs[0])
+	}
+	nextRevision()
+	return nil
+}
+
+// Removes the triggers of all the collections of a scope that has been dropped
+func DropAllTriggers(namespace string, bucket string, scope string, uid string) errors.Error {
+	var lastError errors.Error
+	pairs := make([]value.Pair, 0, _BATCH_SIZE)
+	errorCount := 0
+
+	prefix := _TRIGGER
+	if scope != "" {
+		prefix += uid + "::" + scope + "."
+	}
+	var qcontext datastore.QueryContext
+	flush := func(systemCollection datastore.Keyspace) {
+		_, _, errs := systemCollection.Delete(pairs, qcontext, true)
+		if len(errs) > 0 {
+			errorCount += len(errs)
+			lastError = errors.NewTriggerError(errors.E_TRIGGER_DROP_ALL, bucket+"."+scope+".*", errs[0])
+		}
+		pairs = pairs[:0]
+	}
+	err := datastore.ScanSystemCollection(bucket, prefix,
+		func(systemCollection datastore.Keyspace) errors.Error {
+			qcontext = datastore.GetDurableQueryContextFor(systemCollection)
+			return nil
+		},
+		func(key string, systemCollection datastore.Keyspace) errors.Error {
+			pairs = append(pairs, value.Pair{Name: key})
+			if len(pairs) >= _BATCH_SIZE {
+				flush(systemCollection)
+			}
+			return nil
+		},
+		func(systemCollection datastore.Keyspace) errors.Error {
+			if len(pairs) > 0 {
+				flush(systemCollection)
+			}
+			return nil
+		})
+	if err != nil && err.Code() == errors.E_CB_KEYSPACE_NOT_FOUND {
+		logging.Debugf("%v:%v.%v %v", namespace, bucket, scope, err)
+		return nil
+	}
+	if err != nil && lastError == nil {
+		lastError = err
+	}
+	nextRevision()
+	logging.Debugf("%v:%v.%v %v - %v", namespace, bucket, scope, errorCount, lastError)
+	return lastError
+}
@@ -108,11 +108,7 @@ func NewHashTable(purpose int, cardinality float64, arrLen int) *HashTable {
 	if purpose == HASH_TABLE_FOR_INLIST {
 		defSlots = _MIN_HASH_TABLE_SIZE_INLIST
 	}
-	rv.maxSlots = _DEF_MAX_HASH_TABLE_SIZE
-	// for now N1QL_HASH_TABLE_SIZE only works at cluster-level
-	if !IsFeatureEnabled(GetN1qlFeatureControl(), N1QL_HASH_TABLE_SIZE) {
-		rv.maxSlots *= 4
-	}
+	rv.maxSlots = MaxHashTableSize()
 	slots := int(math.Ceil(cardinality / HTLoadThreshold))
 	if slots <= defSlots {
 		// this includes the case where size is not valid (i.e. -1)
@@ -294,6 +290,16 @@ func (this *HashTable) Iterate() interface{} {
 	return nil
 }
 
+// the maximum number of slots of a hash table
+func MaxHashTableSize() int {
+	maxSlots := _DEF_MAX_HASH_TABLE_SIZE
+	// for now N1QL_HASH_TABLE_SIZE only works at cluster-level
+	if !IsFeatureEnabled(GetN1qlFeatureControl(), N1QL_HASH_TABLE_SIZE) {
+		maxSlots *= 4
+	}
+	return maxSlots
+}
+
 func (this *HashTable) loadFactor() float64 {
 	return float64(this.distinct) / float64(len(this.entries))
 }
@@ -0,0 +1,148 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package views
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/couchbase/query/value"
+)
+
+/*
+Incremental refresh applies the mutations that the DML operators of this
+node have made to the source collection of a view since its last full
+refresh on this node. Mutations made through other nodes or outside of
+the query service are not seen: a full refresh brings the view back in
+line with its source.
+
+The log of a view is dropped, and the next incremental refresh becomes a
+full one, whenever it cannot be relied on: when it grows past its limit,
+on UPSERT, whose before images are unknown, on failed mutations, and on
+mutations within transactions, which may be rolled back.
+*/
+const _MAX_DELTAS = 100000
+
+// A mutation of a document, before and after; either may be nil
+type Delta struct {
+	Old value.Value
+	New value.Value
+}
+
+type deltaLog struct {
+	keyspace string
+	deltas   []Delta
+	valid    bool
+}
+
+var tracker struct {
+	sync.Mutex
+	count     int32
+	views     map[string]*deltaLog
+	keyspaces map[string][]*deltaLog
+}
+
+func init() {
+	tracker.views = make(map[string]*deltaLog)
+	tracker.keyspaces = make(map[string][]*deltaLog)
+}
+
+// Whether the mutations of a keyspace, by qualified name, are being logged
+func Tracking(keyspace string) bool {
+	if atomic.LoadInt32(&tracker.count) == 0 {
+		return false
+	}
+	tracker.Lock()
+	_, ok := tracker.keyspaces[keyspace]
+	tracker.Unlock()
+	return ok
+}
+
+// Start a new log for a view, as a full refresh of the view begins
+func StartTracking(view string, keyspace string) {
+	tracker.Lock()
+	defer tracker.Unlock()
+	stopTracking(view)
+	log := &deltaLog{keyspace: keyspace, valid: true}
+	tracker.views[view] = log
+	tracker.keyspaces[keyspace] = append(tracker.keyspaces[keyspace], log)
+	atomic.StoreInt32(&tracker.count, int32(len(tracker.views)))
+}
+
+func StopTracking(view string) {
+	tracker.Lock()
+	stopTracking(view)
+	atomic.StoreInt32(&tracker.count, int32(len(tracker.views)))
+	tracker.Unlock()
+}
+
+func stopTracking(view string) {
+	log, ok := tracker.views[view]
+	if !ok {
+		return
+	}
+	delete(tracker.views, view)
+	logs := tracker.keyspaces[log.keyspace]
+	for i, l := range logs {
+		if l == log {
+			logs = append(logs[:i], logs[i+1:]...)
+			break
+		}
+	}
+	if len(logs) == 0 {
+		delete(tracker.keyspaces, log.keyspace)
+	} else {
+		tracker.keyspaces[log.keyspace] = logs
+	}
+}
+
+func RecordMutations(keyspace string, deltas []Delta) {
+	if len(deltas) == 0 {
+		return
+	}
+	tracker.Lock()
+	for _, log := range tracker.keyspaces[keyspace] {
+		if !log.valid {
+			continue
+		} else if len(log.deltas)+len(deltas) > _MAX_DELTAS {
+			log.valid = false
+			log.deltas = nil
+		} else {
+			log.deltas = append(log.deltas, deltas...)
+		}
+	}
+	tracker.Unlock()
+}
+
+// The logs of the keyspace can no longer be used for incremental refreshes
+func InvalidateMutations(keyspace string) {
+	tracker.Lock()
+	for _, log := range tracker.keyspaces[keyspace] {
+		log.valid = false
+		log.deltas = nil
+	}
+	tracker.Unlock()
+}
+
+/*
+Returns the mutations logged for a view, and starts a new log. The
+second return value is false if there is no usable log, in which case a
+full refresh is needed.
+*/
+func TakeMutations(view string) ([]Delta, bool) {
+	tracker.Lock()
+	defer tracker.Unlock()
+	log, ok := tracker.views[view]
+	if !ok || !log.valid {
+		return nil, false
+	}
+	rv := log.deltas
+	log.deltas = nil
+	return rv, true
+}
@@ -0,0 +1,352 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package views
+
+import (
+	"sync"
+	"time"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/value"
+)
+
+/*
+A materialized view keeps its rows in a collection of the same name as
+the view, in the scope of the view, so that queries on the view read
+the collection like any other. The definition is stored as for ordinary
+views, flagged as materialized.
+*/
+func CreateMaterializedView(context datastore.QueryContext, path *algebra.Path, text string, queryContext string,
+	with value.Value) errors.Error {
+
+	if path.Scope() == "" {
+		return errors.NewViewError(errors.E_VIEW_INVALID_NAME, path.SimpleString())
+	}
+	name := path.SimpleString()
+
+	rewrite, err := materializedOptions(name, with)
+	if err != nil {
+		return err
+	}
+	s, err := validateScope(path)
+	if err != nil {
+		return errors.NewViewError(errors.E_VIEW_CREATE, name, err)
+	}
+	b, err := getSystemCollection(path.Bucket())
+	if err != nil {
+		return err
+	}
+	if b.ScopeId() == path.Scope() {
+		return errors.NewViewError(errors.E_VIEW_INVALID_NAME, name)
+	}
+	if ks, _ := s.KeyspaceByName(path.Keyspace()); ks != nil {
+		return errors.NewViewError(errors.E_VIEW_ALREADY_EXISTS, name)
+	}
+
+	pairs := make([]value.Pair, 1)
+	pairs[0].Name = getStorageKey(path, s.Uid())
+	m := make(map[string]interface{}, 4)
+	m["text"] = text
+	m["query_context"] = queryContext
+	m["materialized"] = true
+	m["rewrite"] = rewrite
+	pairs[0].Value = value.NewAnnotatedValue(value.NewValue(m))
+
+	_, _, errs := b.Insert(pairs, datastore.GetDurableQueryContextFor(b), true)
+	if len(errs) > 0 {
+		if errs[0].HasCause(errors.E_DUPLICATE_KEY) {
+			return errors.NewViewError(errors.E_VIEW_ALREADY_EXISTS, name)
+		}
+		return errors.NewViewError(errors.E_VIEW_CREATE, name, errs[0])
+	}
+
+	err = s.CreateCollection(context, path.Keyspace(), "", "", nil)
+	if err != nil {
+		_, _, errs = b.Delete(pairs, datastore.GetDurableQueryContextFor(b), true)
+		if len(errs) > 0 {
+			logging.Warnf("Materialized view %v: failed to remove definition: %v", name, errs[0])
+		}
+		return errors.NewViewError(errors.E_VIEW_CREATE, name, err)
+	}
+	invalidateMaterialized(path)
+	return nil
+}
+
+func materializedOptions(name string, with value.Value) (bool, errors.Error) {
+	rewrite := false
+	if with == nil {
+		return rewrite, nil
+	}
+	if with.Type() != value.OBJECT {
+		return rewrite, errors.NewViewError(errors.E_VIEW_OPTION, with.String(), name)
+	}
+	for k, v := range with.Fields() {
+		switch k {
+		case "rewrite":
+			if v, ok := v.(bool); ok {
+				rewrite = v
+				continue
+			}
+		}
+		return rewrite, errors.NewViewError(errors.E_VIEW_OPTION, k, name)
+	}
+	return rewrite, nil
+}
+
+// drops the backing collection of a materialized view whose definition is being removed
+func dropMaterialized(context datastore.QueryContext, path *algebra.Path, s datastore.Scope) errors.Error {
+	StopTracking(path.SimpleString())
+	invalidateMaterialized(path)
+	if ks, _ := s.KeyspaceByName(path.Keyspace()); ks == nil {
+		return nil
+	}
+	return s.DropCollection(context, path.Keyspace())
+}
+
+/*
+Materialized views that allow query rewrite are cached per scope, so
+that planning a query does not scan the system collection every time.
+Views created or dropped on other nodes are seen when the entry expires.
+*/
+const _MATERIALIZED_CACHE_TTL = 30 * time.Second
+
+type MaterializedView struct {
+	Path *algebra.Path
+	View *View
+}
+
+type materializedEntry struct {
+	views  []*MaterializedView
+	loaded time.Time
+}
+
+var materializedCache struct {
+	sync.Mutex
+	scopes map[string]*materializedEntry
+}
+
+func init() {
+	materializedCache.scopes = make(map[string]*materializedEntry)
+}
+
+func invalidateMaterialized(path *algebra.Path) {
+	materializedCache.Lock()
+	delete(materializedCache.scopes, path.ScopePath().FullName())
+	materializedCache.Unlock()
+}
+
+// Returns the materialized views of a scope that queries can be rewritten to read
+func RewriteViews(scope *algebra.Path) []*MaterializedView {
+	key := scope.FullName()
+	materializedCache.Lock()
+	entry, ok := materializedCache.scopes[key]
+	materializedCache.Unlock()
+	if ok && time.Since(entry.loaded) < _MATERIALIZED_CACHE_TTL {
+		return entry.views
+	}
+
+	entry = &materializedEntry{loaded: time.Now()}
+	names, _ := ListViewKeys(scope.Namespace(), scope.Bucket(), scope.Scope(), _BATCH_SIZE)
+	for _, name := range names {
+		elements := algebra.ParsePath(name)
+		if len(elements) != 4 {
+			continue
+		}
+		path := algebra.NewPathFromElements(elements)
+		v, err := GetView(path)
+		if err == nil && v != nil && v.Materialized && v.Rewrite {
+			entry.views = append(entry.views, &MaterializedView{Path: path, View: v})
+		}
+	}
+
+	materializedCache.Lock()
+	materializedCache.scopes[key] = entry
+	materializedCache.Unlock()
+	return entry.views
+}
+
+/*
+An aggregate view produces one row per group of a single collection.
+Such a view can answer queries with the same FROM, WHERE and GROUP BY
+clauses, and can be maintained incrementally if its aggregates allow it.
+Every group key must be projected, as the rows are keyed on them.
+*/
+type AggregateView struct {
+	Term  *algebra.KeyspaceTerm
+	Where expression.Expression
+	Keys  expression.Expressions
+	Terms algebra.ResultTerms
+}
+
+// Returns the aggregate form of a view definition, or the reason it has none
+func NewAggregateView(body *algebra.Select) (*AggregateView, string) {
+	if body.Order() != nil || body.Limit() != nil || body.Offset() != nil {
+		return nil, "ORDER BY, LIMIT and OFFSET are not allowed"
+	}
+	node, ok := body.Subresult().(*algebra.Subselect)
+	if !ok {
+		return nil, "the definition must be a single query block"
+	}
+	term, ok := node.From().(*algebra.KeyspaceTerm)
+	if !ok || term.Path() == nil || !term.Path().IsCollection() || term.Keys() != nil || term.Indexes() != nil {
+		return nil, "the definition must read a single collection"
+	}
+	group := node.Group()
+	if group == nil || len(group.By()) == 0 {
+		return nil, "the definition must have a GROUP BY clause"
+	}
+	if node.Let() != nil || group.Letting() != nil || group.Having() != nil || group.GroupAs() != "" ||
+		node.Window() != nil {
+		return nil, "LET, LETTING, HAVING, GROUP AS and WINDOW are not allowed"
+	}
+	projection := node.Projection()
+	if projection.Distinct() || projection.Raw() || len(projection.Exclude()) > 0 {
+		return nil, "DISTINCT, RAW and EXCLUDE are not allowed"
+	}
+
+	rv := &AggregateView{Term: term, Where: node.Where(), Keys: group.By(), Terms: projection.Terms()}
+	for _, key := range rv.Keys {
+		if rv.KeyTerm(key) < 0 {
+			return nil, "every GROUP BY key must be projected"
+		}
+	}
+	for _, t := range rv.Terms {
+		if t.Star() {
+			return nil, "* is not allowed"
+		}
+		if _, ok := t.Expression().(algebra.Aggregate); !ok && !rv.isKey(t.Expression()) {
+			return nil, "projections must be GROUP BY keys or aggregates"
+		}
+	}
+	return rv, ""
+}
+
+// the position of the projection term for a group key
+func (this *AggregateView) KeyTerm(key expression.Expression) int {
+	for i, t := range this.Terms {
+		if !t.Star() && t.Expression().EquivalentTo(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (this *AggregateView) isKey(expr expression.Expression) bool {
+	for _, key := range this.Keys {
+		if expr.EquivalentTo(key) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+The document key of a row: the JSON encoding of the group keys. The
+same expression is evaluated on the projected fields by full refreshes
+and on the source documents by incremental ones.
+*/
+func (this *AggregateView) KeyExpression(keys expression.Expressions) expression.Expression {
+	return expression.NewJSONEncode(expression.NewArrayConstruct(keys...))
+}
+
+// Returns the reason the view cannot be maintained incrementally, if any
+func (this *AggregateView) NotIncremental() string {
+	exprs := append(expression.Expressions{}, this.Keys...)
+	if this.Where != nil {
+		exprs = append(exprs, this.Where)
+	}
+	hasCount := false
+	for _, t := range this.Terms {
+		agg, ok := t.Expression().(algebra.Aggregate)
+		if !ok {
+			continue
+		}
+		if agg.HasFlags(algebra.AGGREGATE_DISTINCT) || agg.Filter() != nil || agg.WindowTerm() != nil {
+			return "DISTINCT, FILTER and OVER are not allowed in aggregates"
+		}
+		switch agg.(type) {
+		case *algebra.Count:
+			// COUNT(*) has a nil operand
+			if agg.Operands()[0] == nil {
+				hasCount = true
+				continue
+			}
+		case *algebra.Sum:
+			if this.CountOf(agg.Operands()[0]) < 0 {
+				return "SUM(" + agg.Operands()[0].String() + ") needs a matching COUNT"
+			}
+		default:
+			return "only COUNT and SUM aggregates are allowed"
+		}
+		exprs = append(exprs, agg.Operands()...)
+	}
+	if !hasCount {
+		return "COUNT(*) must be projected"
+	}
+	for _, expr := range exprs {
+		if expr.HasVolatileExpr() || !referencesDocument(expr) {
+			return "expressions must depend on the document only"
+		}
+	}
+	return ""
+}
+
+// the position of COUNT(expr) in the projection
+func (this *AggregateView) CountOf(expr expression.Expression) int {
+	for i, t := range this.Terms {
+		if c, ok := t.Expression().(*algebra.Count); ok && !c.Distinct() && c.Filter() == nil &&
+			c.Operands()[0] != nil && c.Operands()[0].EquivalentTo(expr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// deltas carry document bodies only: no META(), and no subqueries
+func referencesDocument(expr expression.Expression) bool {
+	switch expr.(type) {
+	case *expression.Meta, *algebra.Subquery:
+		return false
+	}
+	for _, child := range expr.Children() {
+		if !referencesDocument(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// refreshes of the same view on this node are serialized
+var refreshing struct {
+	sync.Mutex
+	views map[string]bool
+}
+
+func BeginRefresh(name string) errors.Error {
+	refreshing.Lock()
+	defer refreshing.Unlock()
+	if refreshing.views == nil {
+		refreshing.views = make(map[string]bool)
+	}
+	if refreshing.views[name] {
+		return errors.NewViewError(errors.E_VIEW_REFRESH_RUNNING, name)
+	}
+	refreshing.views[name] = true
+	return nil
+}
+
+func EndRefresh(name string) {
+	refreshing.Lock()
+	delete(refreshing.views, name)
+	refreshing.Unlock()
+}
@@ -0,0 +1,138 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+package views
+
+import (
+	"testing"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/parser/n1ql"
+	"github.com/couchbase/query/value"
+)
+
+func aggregateView(t *testing.T, text string) (*AggregateView, string) {
+	t.Helper()
+	n1ql.SetNamespaces(map[string]interface{}{"default": true})
+	stmt, err := n1ql.ParseStatement2(text, "default", "")
+	if err != nil {
+		t.Fatalf("n1ql.ParseStatement2(%q): %v", text, err)
+	}
+	return NewAggregateView(stmt.(*algebra.Select))
+}
+
+func TestAggregateView(t *testing.T) {
+	agg, reason := aggregateView(t, "SELECT o.region, COUNT(*) AS n, COUNT(o.amount) AS c, SUM(o.amount) AS total "+
+		"FROM default:b.s.orders AS o WHERE o.status = \"open\" GROUP BY o.region")
+	if agg == nil {
+		t.Fatalf("Expected an aggregate view, found %v", reason)
+	}
+	if agg.KeyTerm(agg.Keys[0]) != 0 || agg.CountOf(agg.Terms[3].Expression().Children()[0]) != 2 {
+		t.Fatalf("Expected the key in term 0 and the count of o.amount in term 2")
+	}
+	if reason := agg.NotIncremental(); reason != "" {
+		t.Fatalf("Expected the view to be maintained incrementally, found %v", reason)
+	}
+
+	for _, c := range []struct{ text, reason string }{
+		{"SELECT o.region, COUNT(*) AS n FROM default:b.s.orders AS o GROUP BY o.region ORDER BY o.region",
+			"ORDER BY, LIMIT and OFFSET are not allowed"},
+		{"SELECT COUNT(*) AS n FROM default:b.s.orders AS o", "the definition must have a GROUP BY clause"},
+		{"SELECT COUNT(*) AS n FROM default:b.s.orders AS o GROUP BY o.region",
+			"every GROUP BY key must be projected"},
+		{"SELECT o.region, o.status FROM default:b.s.orders AS o GROUP BY o.region",
+			"projections must be GROUP BY keys or aggregates"},
+	} {
+		if agg, reason := aggregateView(t, c.text); agg != nil || reason != c.reason {
+			t.Errorf("Expected %q to be refused with %q, found %q", c.text, c.reason, reason)
+		}
+	}
+
+	for _, c := range []struct{ text, reason string }{
+		{"SELECT o.region, SUM(o.amount) AS total FROM default:b.s.orders AS o GROUP BY o.region",
+			"SUM((`o`.`amount`)) needs a matching COUNT"},
+		{"SELECT o.region, COUNT(*) AS n, MAX(o.amount) AS m FROM default:b.s.orders AS o GROUP BY o.region",
+			"only COUNT and SUM aggregates are allowed"},
+		{"SELECT o.region, COUNT(DISTINCT o.id) AS d, COUNT(*) AS n FROM default:b.s.orders AS o GROUP BY o.region",
+			"DISTINCT, FILTER and OVER are not allowed in aggregates"},
+		{"SELECT o.region, COUNT(o.id) AS n FROM default:b.s.orders AS o GROUP BY o.region",
+			"COUNT(*) must be projected"},
+		{"SELECT META(o).cas AS region, COUNT(*) AS n FROM default:b.s.orders AS o GROUP BY META(o).cas",
+			"expressions must depend on the document only"},
+	} {
+		agg, reason := aggregateView(t, c.text)
+		if agg == nil {
+			t.Fatalf("Expected an aggregate view for %q, found %v", c.text, reason)
+		}
+		if reason = agg.NotIncremental(); reason != c.reason {
+			t.Errorf("Expected %q not to be incremental with %q, found %q", c.text, c.reason, reason)
+		}
+	}
+}
+
+func TestTrackMutations(t *testing.T) {
+	view, keyspace := "default:b.s.totals", "default:b.s.orders"
+	delta := Delta{New: value.NewValue(map[string]interface{}{"amount": 1})}
+
+	if Tracking(keyspace) {
+		t.Fatalf("Expected no tracking before a full refresh")
+	}
+	if _, ok := TakeMutations(view); ok {
+		t.Fatalf("Expected a full refresh to be needed")
+	}
+
+	StartTracking(view, keyspace)
+	defer StopTracking(view)
+	if !Tracking(keyspace) {
+		t.Fatalf("Expected the mutations of %v to be tracked", keyspace)
+	}
+	RecordMutations(keyspace, []Delta{delta, delta})
+	RecordMutations("default:b.s.other", []Delta{delta})
+	if deltas, ok := TakeMutations(view); !ok || len(deltas) != 2 {
+		t.Fatalf("Expected two mutations, found %v, %v", len(deltas), ok)
+	}
+	if deltas, ok := TakeMutations(view); !ok || len(deltas) != 0 {
+		t.Fatalf("Expected the log to start again, found %v, %v", len(deltas), ok)
+	}
+
+	// a log that cannot be relied on makes the next refresh a full one
+	InvalidateMutations(keyspace)
+	RecordMutations(keyspace, []Delta{delta})
+	if _, ok := TakeMutations(view); ok {
+		t.Fatalf("Expected a full refresh to be needed once the log is invalidated")
+	}
+	StartTracking(view, keyspace)
+	RecordMutations(keyspace, make([]Delta, _MAX_DELTAS+1))
+	if _, ok := TakeMutations(view); ok {
+		t.Fatalf("Expected a full refresh to be needed once the log overflows")
+	}
+
+	StopTracking(view)
+	if Tracking(keyspace) {
+		t.Fatalf("Expected tracking to stop with the view")
+	}
+}
+
+func TestBeginRefresh(t *testing.T) {
+	if err := BeginRefresh("v1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := BeginRefresh("v1"); err == nil || err.Code() != errors.E_VIEW_REFRESH_RUNNING {
+		t.Fatalf("Expected a concurrent refresh to be refused, found %v", err)
+	}
+	if err := BeginRefresh("v2"); err != nil {
+		t.Fatalf("Unexpected error refreshing another view: %v", err)
+	}
+	EndRefresh("v1")
+	EndRefresh("v2")
+	if err := BeginRefresh("v1"); err != nil {
+		t.Fatalf("Unexpected error once the refresh ended: %v", err)
+	}
+	EndRefresh("v1")
+}
@@ -0,0 +1,335 @@
+//  Copyright 2026-Present Couchbase, Inc.
+//
+//  Use of this software is governed by the Business Source License included
+//  in the file licenses/BSL-Couchbase.txt.  As of the Change Date specified
+//  in that file, in accordance with the Business Source License, use of this
+//  software will be governed by the Apache License, Version 2.0, included in
+//  the file licenses/APL2.txt.
+
+/*
+Package views stores scope level view definitions in the bucket's system
+collection, alongside sequences and UDFs. A view is kept as the text of
+its SELECT statement together with the query context it was created
+under, and is expanded by the planner wherever it is referenced.
+
+A materialized view stores its rows in a collection of the same name,
+which the view's definition populates on refresh, so that queries read
+the collection rather than expanding the view.
+*/
+package views
+
+import (
+	"strings"
+
+	"github.com/couchbase/query/algebra"
+	"github.com/couchbase/query/datastore"
+	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/logging"
+	"github.com/couchbase/query/value"
+)
+
+const _VIEW = "view::"
+const _BATCH_SIZE = 512
+
+// A stored view definition
+type View struct {
+	Text         string
+	QueryContext string
+	Materialized bool
+	Rewrite      bool
+}
+
+func getStorageKey(path *algebra.Path, uid string) string {
+	return _VIEW + uid + "::" + path.Scope() + "." + path.Keyspace()
+}
+
+func getCacheKey(namespace string, bucket string, key string) string {
+	return namespace + ":" + bucket + "." + trimPrefixAndScopeUid(key)
+}
+
+func trimPrefixAndScopeUid(key string) string {
+	if len(key) > len(_VIEW)+10 && strings.HasPrefix(key, _VIEW) {
+		key = key[len(_VIEW)+10:]
+	}
+	return key
+}
+
+func validateScope(path *algebra.Path) (datastore.Scope, errors.Error) {
+	if path.Namespace() == datastore.SYSTEM_NAMESPACE {
+		return nil, errors.NewDatastoreInvalidPathError("system namespace not permitted", "view name")
+	}
+
+	store := datastore.GetDatastore()
+	if store == nil {
+		return nil, errors.NewNoDatastoreError()
+	}
+
+	ns, err := store.NamespaceById(path.Namespace())
+	if err != nil {
+		return nil, err
+	}
+	b, err := ns.BucketByName(path.Bucket())
+	if err != nil {
+		return nil, err
+	}
+	return b.ScopeByName(path.Scope())
+}
+
+func getSystemCollection(bucket string) (datastore.Keyspace, errors.Error) {
+	store := datastore.GetDatastore()
+	if store == nil {
+		return nil, errors.NewNoDatastoreError()
+	}
+	ks, err := store.GetSystemCollection(bucket)
+	if err == nil && ks == nil {
+		err = errors.NewViewError(errors.E_VIEW_NOT_ENABLED, bucket, err)
+	} else if err != nil && err.Code() == errors.E_CB_SCOPE_NOT_FOUND {
+		err = errors.NewViewError(errors.E_VIEW_NOT_ENABLED, bucket, nil)
+	}
+	return ks, err
+}
+
+func CreateView(path *algebra.Path, text string, queryContext string, replace bool) errors.Error {
+	if path.Scope() == "" {
+		return errors.NewViewError(errors.E_VIEW_INVALID_NAME, path.SimpleString())
+	}
+	name := path.SimpleString()
+
+	s, err := validateScope(path)
+	if err != nil {
+		return errors.NewViewError(errors.E_VIEW_CREATE, name, err)
+	}
+	b, err := getSystemCollection(path.Bucket())
+	if err != nil {
+		return err
+	}
+	if b.ScopeId() == path.Scope() {
+		return errors.NewViewError(errors.E_VIEW_INVALID_NAME, name)
+	}
+
+	// views and collections share a name space
+	if ks, _ := s.KeyspaceByName(path.Keyspace()); ks != nil {
+		return errors.NewViewError(errors.E_VIEW_ALREADY_EXISTS, name)
+	}
+
+	pairs := make([]value.Pair, 1)
+	pairs[0].Name = getStorageKey(path, s.Uid())
+	m := make(map[string]interface{}, 2)
+	m["text"] = text
+	m["query_context"] = queryContext
+	pairs[0].Value = value.NewAnnotatedValue(value.NewValue(m))
+
+	var errs errors.Errors
+	if replace {
+		_, _, errs = b.Upsert(pairs, datastore.GetDurableQueryContextFor(b), true)
+	} else {
+		_, _, errs = b.Insert(pairs, datastore.GetDurableQueryContextFor(b), true)
+	}
+	if len(errs) > 0 {
+		if !replace && errs[0].HasCause(errors.E_DUPLICATE_KEY) {
+			return errors.NewViewError(errors.E_VIEW_ALREADY_EXISTS, name)
+		}
+		return errors.NewViewError(errors.E_VIEW_CREATE, name, errs[0])
+	}
+	return nil
+}
+
+func DropView(context datastore.QueryContext, path *algebra.Path, failIfNotExists bool, materialized bool) errors.Error {
+	if path.Scope() == "" {
+		return errors.NewViewError(errors.E_VIEW_INVALID_NAME, path.SimpleString())
+	}
+	name := path.SimpleString()
+
+	v, err := GetView(path)
+	if err != nil {
+		return err
+	} else if v == nil {
+		if failIfNotExists {
+			return errors.NewViewError(errors.E_VIEW_NOT_FOUND, name)
+		}
+		return nil
+	} else if materialized && !v.Materialized {
+		return errors.NewViewError(errors.E_VIEW_NOT_MATERIALIZED, name)
+	}
+
+	s, err := validateScope(path)
+	if err != nil {
+		return errors.NewViewError(errors.E_VIEW_DROP, name, err)
+	}
+	b, err := getSystemCollection(path.Bucket())
+	if err != nil {
+		return err
+	}
+	if v.Materialized {
+		err = dropMaterialized(context, path, s)
+		if err != nil {
+			return errors.NewViewError(errors.E_VIEW_DROP, name, err)
+		}
+	}
+
+	pairs := make([]value.Pair, 1)
+	pairs[0].Name = getStorageKey(path, s.Uid())
+	_, _, errs := b.Delete(pairs, datastore.GetDurableQueryContextFor(b), true)
+	if len(errs) > 0 {
+		return errors.NewViewError(errors.E_VIEW_DROP, name, errs[0])
+	}
+	return nil
+}
+
+// Returns the definition of the view, or nil if the view does not exist
+func GetView(path *algebra.Path) (*View, errors.Error) {
+	if path.Scope() == "" || path.Namespace() == datastore.SYSTEM_NAMESPACE {
+		return nil, nil
+	}
+	s, err := validateScope(path)
+	if err != nil {
+		return nil, nil
+	}
+	b, err := getSystemCollection(path.Bucket())
+	if err != nil || b.ScopeId() == path.Scope() {
+		return nil, nil
+	}
+
+	res := make(map[string]value.AnnotatedValue, 1)
+	keys := []string{getStorageKey(path, s.Uid())}
+	errs := b.Fetch(keys, res, datastore.NULL_QUERY_CONTEXT, nil, nil, false)
+	if len(errs) > 0 {
+		if !errors.IsNotFoundError("", errs[0]) && !errs[0].HasCause(errors.E_CB_BULK_GET) {
+			return nil, errs[0]
+		}
+		return nil, nil
+	}
+	av, ok := res[keys[0]]
+	if !ok {
+		return nil, nil
+	}
+
+	rv := &View{}
+	if t, ok := av.Field("text"); ok && t.Type() == value.STRING {
+		rv.Text = t.ToString()
+	} else {
+		return nil, errors.NewViewError(errors.E_VIEW_INVALID_DEFINITION, path.SimpleString())
+	}
+	if qc, ok := av.Field("query_context"); ok && qc.Type() == value.STRING {
+		rv.QueryContext = qc.ToString()
+	}
+	if m, ok := av.Field("materialized"); ok && m.Truth() {
+		rv.Materialized = true
+		if r, ok := av.Field("rewrite"); ok {
+			rv.Rewrite = r.Truth()
+		}
+	}
+	return rv, nil
+}
+
+// Lists the full names of the views defined in a scope
+func ListViewKeys(namespace string, bucket string, scope string, limit int64) ([]string, errors.Error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	res := make([]string, 0, 32)
+	path := algebra.NewPathFromElements([]string{namespace, bucket, scope})
+	s, err := validateScope(path)
+	if err != nil {
+		return res, nil
+	}
+	prefix := _VIEW + s.Uid() + "::" + scope + "."
+
+	datastore.ScanSystemCollection(bucket, prefix,
+		func(systemCollection datastore.Keyspace) errors.Error {
+			if systemCollection.ScopeId() == scope {
+				// there are no views in the _system scope
+				return errors.NewViewError(errors.E_VIEW_NOT_FOUND, scope) // will just stop the scan
+			}
+			return nil
+		},
+		func(key string, systemCollection datastore.Keyspace) errors.Error {
+			if limit > 0 {
+				res = append(res, getCacheKey(namespace, bucket, key))
+				limit--
+				return nil
+			}
+			return errors.NewViewError(errors.E_VIEW_NOT_FOUND, key) // will just stop the scan
+		}, nil)
+
+	return res, nil
+}
+
+// Returns the system:views entry for the view
+func FetchView(name string) (value.AnnotatedValue, errors.Error) {
+	elements := algebra.ParsePath(name)
+	if len(elements) != 4 {
+		return nil, errors.NewViewError(errors.E_VIEW_INVALID_NAME, name)
+	}
+	path := algebra.NewPathFromElements(elements)
+	v, err := GetView(path)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	m["namespace"] = elements[0]
+	m["namespace_id"] = elements[0]
+	m["bucket"] = elements[1]
+	m["scope_id"] = elements[2]
+	m["name"] = elements[3]
+	m["path"] = path.ProtectedString()
+	m["definition"] = v.Text
+	if v.QueryContext != "" {
+		m["query_context"] = v.QueryContext
+	}
+	if v.Materialized {
+		m["materialized"] = true
+		m["rewrite"] = v.Rewrite
+	}
+	return value.NewAnnotatedValue(value.NewValue(m)), nil
+}
+
+// Removes all view definitions belonging to a scope that has been dropped
+func DropAllViews(namespace string, bucket string, scope string, uid string) errors.Error {
+	var lastError errors.Error
+	pairs := make([]value.Pair, 0, _BATCH_SIZE)
+	errorCount := 0
+
+	prefix := _VIEW
+	if scope != "" {
+		prefix += uid + "::" + scope + "."
+	}
+	var qcontext datastore.QueryContext
+	flush := func(systemCollection datastore.Keyspace) {
+		_, _, errs := systemCollection.Delete(pairs, qcontext, true)
+		if len(errs) > 0 {
+			errorCount += len(errs)
+			lastError = errors.NewViewError(errors.E_VIEW_DROP_ALL, bucket+"."+scope+".*", errs[0])
+		}
+		pairs = pairs[:0]
+	}
+	err := datastore.ScanSystemCollection(bucket, prefix,
+		func(systemCollection datastore.Keyspace) errors.Error {
+			qcontext = datastore.GetDurableQueryContextFor(systemCollection)
+			return nil
+		},
+		func(key string, systemCollection datastore.Keyspace) errors.Error {
+			pairs = append(pairs, value.Pair{Name: key})
+			if len(pairs) >= _BATCH_SIZE {
+				flush(systemCollection)
+			}
+			return nil
+		},
+		func(systemCollection datastore.Keyspace) errors.Error {
+			if len(pairs) > 0 {
+				flush(systemCollection)
+			}
+			return nil
+		})
+	if err != nil && err.Code() == errors.E_CB_KEYSPACE_NOT_FOUND {
+		logging.Debugf("%v:%v.%v %v", namespace, bucket, scope, err)
+		return nil
+	}
+	if err != nil && lastError == nil {
+		lastError = err
+	}
+	logging.Debugf("%v:%v.%v %v - %v", namespace, bucket, scope, errorCount, lastError)
+	return lastError
+}